@@ -137,43 +137,87 @@ type httpResult struct {
 	ownersFileExists bool
 }
 
+// expandAliases resolves the aliases among the logins to their members. Aliases may
+// refer to other aliases, those are expanded transitively. An alias that refers back
+// to itself is expanded only once.
+func expandAliases(aliases RepoAliases, logins sets.Set[string]) sets.Set[string] {
+	result := sets.New[string]()
+	var expand func(logins sets.Set[string], seen sets.Set[string])
+	expand = func(logins sets.Set[string], seen sets.Set[string]) {
+		for _, login := range sets.List(logins) {
+			normalized := github.NormLogin(login)
+			members, isAlias := aliases[normalized]
+			if !isAlias {
+				result.Insert(login)
+				continue
+			}
+			if seen.Has(normalized) {
+				continue
+			}
+			expand(members, seen.Union(sets.New[string](normalized)))
+		}
+	}
+	expand(logins, sets.New[string]())
+	return result
+}
+
+// resolveLogins expands the aliases among the logins, adds the extra logins from the policy
+// and drops the excluded ones and those who are not allowed to approve or review
+func (r httpResult) resolveLogins(logins, extra []string, policy ownersPolicy, cleaner ownersCleaner) []string {
+	resolved := expandAliases(r.repoAliases, repoowners.NormLogins(logins)).Union(repoowners.NormLogins(extra))
+	return cleaner(sets.List(resolved.Difference(repoowners.NormLogins(policy.ExcludedLogins))))
+}
+
+func (r httpResult) resolveConfig(cfg repoowners.Config, policy ownersPolicy, cleaner ownersCleaner) repoowners.Config {
+	resolved := repoowners.Config{
+		Approvers:         r.resolveLogins(cfg.Approvers, policy.ExtraApprovers, policy, cleaner),
+		Reviewers:         r.resolveLogins(cfg.Reviewers, policy.ExtraReviewers, policy, cleaner),
+		RequiredReviewers: r.resolveLogins(cfg.RequiredReviewers, nil, policy, cleaner),
+		Labels:            sets.List(sets.New[string](cfg.Labels...)),
+	}
+	if len(resolved.Approvers) == 0 && len(policy.FallbackApprovers) > 0 {
+		resolved.Approvers = r.resolveLogins(policy.FallbackApprovers, nil, policy, cleaner)
+	}
+	if len(resolved.Reviewers) == 0 {
+		resolved.Reviewers = resolved.Approvers
+	}
+	return resolved
+}
+
 // resolveOwnerAliases computes the resolved (simple or full config) format of the OWNERS file
-func (r httpResult) resolveOwnerAliases(cleaner ownersCleaner) interface{} {
+func (r httpResult) resolveOwnerAliases(cleaner ownersCleaner, policy ownersPolicy) interface{} {
 	if !r.simpleConfig.Empty() {
-		sc := SimpleConfig{
-			Config: repoowners.Config{
-				Approvers:         cleaner(sets.List(r.repoAliases.ExpandAliases(repoowners.NormLogins(r.simpleConfig.Approvers)))),
-				Reviewers:         cleaner(sets.List(r.repoAliases.ExpandAliases(repoowners.NormLogins(r.simpleConfig.Reviewers)))),
-				RequiredReviewers: cleaner(sets.List(r.repoAliases.ExpandAliases(repoowners.NormLogins(r.simpleConfig.RequiredReviewers)))),
-				Labels:            sets.List(sets.New[string](r.simpleConfig.Labels...)),
-			},
+		return SimpleConfig{
+			Config:  r.resolveConfig(r.simpleConfig.Config, policy, cleaner),
 			Options: r.simpleConfig.Options,
 		}
-		if len(sc.Reviewers) == 0 {
-			sc.Reviewers = sc.Approvers
-		}
-		return sc
 	} else {
 		fc := FullConfig{
 			Filters: map[string]repoowners.Config{},
 			Options: r.fullConfig.Options,
 		}
 		for k, v := range r.fullConfig.Filters {
-			cfg := repoowners.Config{
-				Approvers:         cleaner(sets.List(r.repoAliases.ExpandAliases(repoowners.NormLogins(v.Approvers)))),
-				Reviewers:         cleaner(sets.List(r.repoAliases.ExpandAliases(repoowners.NormLogins(v.Reviewers)))),
-				RequiredReviewers: cleaner(sets.List(r.repoAliases.ExpandAliases(repoowners.NormLogins(v.RequiredReviewers)))),
-				Labels:            sets.List(sets.New[string](v.Labels...)),
-			}
-			if len(cfg.Reviewers) == 0 {
-				cfg.Reviewers = cfg.Approvers
-			}
-			fc.Filters[k] = cfg
+			fc.Filters[k] = r.resolveConfig(v, policy, cleaner)
 		}
 		return fc
 	}
 }
 
+// hasApprovers determines if anybody is able to approve changes under the resolved OWNERS file
+func hasApprovers(config interface{}) bool {
+	switch cfg := config.(type) {
+	case SimpleConfig:
+		return len(cfg.Approvers) > 0
+	case FullConfig:
+		for _, filter := range cfg.Filters {
+			if len(filter.Approvers) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 type FileGetter interface {
 	GetFile(org, repo, filepath, commit string) ([]byte, error)
 }
@@ -233,8 +277,20 @@ func addHeader(path string, header string) error {
 	return os.WriteFile(path, append([]byte(header), content...), 0644)
 }
 
-func writeOwners(orgRepo orgRepo, httpResult httpResult, cleaner ownersCleaner, header string) error {
+func writeOwners(orgRepo orgRepo, httpResult httpResult, cleaner ownersCleaner, overrides ownersOverrides, header string) error {
 	for _, directory := range orgRepo.Directories {
+		logger := logrus.WithField("directory", directory)
+		policy := overrides.policyFor(directory)
+		if policy.Skip {
+			logger.Info("Syncing the OWNERS file is disabled for the directory, skipping")
+			continue
+		}
+		config := httpResult.resolveOwnerAliases(cleaner, policy)
+		if !hasApprovers(config) {
+			logger.WithField("orgRepo", orgRepo.repoString()).Warn("No approvers remain after resolving aliases and filtering, keeping the existing OWNERS file")
+			continue
+		}
+
 		path := filepath.Join(directory, "OWNERS")
 		err := os.Remove(path)
 		if err != nil && !os.IsNotExist(err) {
@@ -242,7 +298,6 @@ func writeOwners(orgRepo orgRepo, httpResult httpResult, cleaner ownersCleaner,
 		}
 
 		logrus.WithField("path", path).Debug("Writing to path ...")
-		config := httpResult.resolveOwnerAliases(cleaner)
 		switch cfg := config.(type) {
 		case SimpleConfig:
 			err = repoowners.SaveSimpleConfig(cfg, path)
@@ -279,7 +334,7 @@ func makeHeader(destOrg, srcOrg, srcRepo string) string {
 	return strings.Join(lines, "") + "\n"
 }
 
-func pullOwners(gc github.Client, configRootDir string, blocklist blocklist, configSubDirs, extraDirs []string, githubOrg string, githubRepo string, pc plugins.Configuration) error {
+func pullOwners(gc github.Client, configRootDir string, blocklist blocklist, configSubDirs, extraDirs []string, githubOrg string, githubRepo string, pc plugins.Configuration, overrides ownersOverrides) error {
 	orgRepos, err := loadRepos(configRootDir, blocklist, configSubDirs, extraDirs, githubOrg, githubRepo)
 	if err != nil {
 		return err
@@ -305,7 +360,7 @@ func pullOwners(gc github.Client, configRootDir string, blocklist blocklist, con
 			continue
 		}
 
-		if err := writeOwners(orgRepo, httpResult, cleaner, makeHeader(githubOrg, orgRepo.Organization, orgRepo.Repository)); err != nil {
+		if err := writeOwners(orgRepo, httpResult, cleaner, overrides, makeHeader(githubOrg, orgRepo.Organization, orgRepo.Repository)); err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -328,6 +383,7 @@ type options struct {
 	extraDirs          flagutil.Strings
 	blockedRepos       flagutil.Strings
 	blockedOrgs        flagutil.Strings
+	overridesPath      string
 	debugMode          bool
 	selfApprove        bool
 	prBaseBranch       string
@@ -353,6 +409,7 @@ func parseOptions() options {
 	fs.Var(&o.extraDirs, "extra-config-dir", "The directory path from the repo root where extra configuration is stored.")
 	fs.Var(&o.blockedRepos, "ignore-repo", "The repo for which syncing OWNERS file is disabled.")
 	fs.Var(&o.blockedOrgs, "ignore-org", "The orgs for which syncing OWNERS file is disabled.")
+	fs.StringVar(&o.overridesPath, "overrides-config", "", "Path to the file with the per-directory policies for syncing OWNERS files.")
 	fs.BoolVar(&o.debugMode, "debug-mode", false, "Enable the DEBUG level of logs if true.")
 	fs.BoolVar(&o.selfApprove, "self-approve", false, "Self-approve the PR by adding the `approved` and `lgtm` labels. Requires write permissions on the repo.")
 	fs.StringVar(&o.prBaseBranch, "pr-base-branch", defaultBaseBranch, "The base branch to use for the pull request.")
@@ -461,6 +518,14 @@ func main() {
 		pc = *(agent.Config())
 	}
 
+	var overrides ownersOverrides
+	if o.overridesPath != "" {
+		var err error
+		if overrides, err = loadOwnersOverrides(o.overridesPath); err != nil {
+			logrus.WithError(err).Fatal("failed to load overrides config file")
+		}
+	}
+
 	gc, err := o.GitHubOptions.GitHubClient(o.dryRun)
 	if err != nil {
 		logrus.WithError(err).Fatal("error getting GitHub client")
@@ -480,7 +545,7 @@ func main() {
 	var blocked blocklist
 	blocked.directories = sets.New[string](o.blockedRepos.Strings()...)
 	blocked.orgs = sets.New[string](o.blockedOrgs.Strings()...)
-	if err := pullOwners(gc, configRootDirectory, blocked, configSubDirectories, o.extraDirs.Strings(), o.githubOrg, o.githubRepo, pc); err != nil {
+	if err := pullOwners(gc, configRootDirectory, blocked, configSubDirectories, o.extraDirs.Strings(), o.githubOrg, o.githubRepo, pc, overrides); err != nil {
 		logrus.WithError(err).Fatal("Error occurred when walking through the target dir.")
 	}
 
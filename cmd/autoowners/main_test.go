@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -127,6 +129,28 @@ func TestResolveAliases(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "Simple Config with aliases nested in other aliases",
+			given: httpResult{
+				simpleConfig: SimpleConfig{
+					Config: repoowners.Config{
+						Approvers: []string{"david", "sig-parent"},
+					},
+				},
+				repoAliases: RepoAliases{
+					"sig-parent": sets.New[string]("sig-alias", "erin"),
+					"sig-alias":  sets.New[string]("bob", "carol", "sig-parent"),
+				},
+			},
+			expected: SimpleConfig{
+				Config: repoowners.Config{
+					Approvers:         []string{"bob", "carol", "david", "erin"},
+					Reviewers:         []string{"bob", "carol", "david", "erin"},
+					RequiredReviewers: []string{},
+					Labels:            []string{},
+				},
+			},
+		},
 		{
 			description: "Full Config case",
 			given: httpResult{
@@ -178,7 +202,7 @@ func TestResolveAliases(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.description, func(t *testing.T) {
-			result := tc.given.resolveOwnerAliases(noOpCleaner)
+			result := tc.given.resolveOwnerAliases(noOpCleaner, ownersPolicy{})
 			assertEqual(t, result, tc.expected)
 		})
 	}
@@ -472,7 +496,146 @@ func TestResolveOwnerAliasesCleans(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			assertEqual(t, tc.in.resolveOwnerAliases(cleaner), tc.expectedResult)
+			assertEqual(t, tc.in.resolveOwnerAliases(cleaner, ownersPolicy{}), tc.expectedResult)
+		})
+	}
+}
+
+func TestResolveOwnerAliasesPolicy(t *testing.T) {
+	cleaner := func(logins []string) []string {
+		var result []string
+		for _, login := range logins {
+			if login != "outsider" {
+				result = append(result, login)
+			}
+		}
+		return result
+	}
+	testCases := []struct {
+		name     string
+		in       httpResult
+		policy   ownersPolicy
+		expected interface{}
+	}{
+		{
+			name: "extra logins are added and excluded logins removed",
+			in: httpResult{simpleConfig: SimpleConfig{Config: repoowners.Config{
+				Approvers: []string{"alice", "bob"},
+				Reviewers: []string{"carol"},
+			}}},
+			policy: ownersPolicy{
+				ExtraApprovers: []string{"Dave", "outsider"},
+				ExtraReviewers: []string{"erin"},
+				ExcludedLogins: []string{"bob"},
+			},
+			expected: SimpleConfig{Config: repoowners.Config{
+				Approvers: []string{"alice", "dave"},
+				Reviewers: []string{"carol", "erin"},
+				Labels:    []string{},
+			}},
+		},
+		{
+			name: "fallback approvers are used when no approvers remain",
+			in: httpResult{simpleConfig: SimpleConfig{Config: repoowners.Config{
+				Approvers: []string{"outsider"},
+			}}},
+			policy: ownersPolicy{FallbackApprovers: []string{"alice"}},
+			expected: SimpleConfig{Config: repoowners.Config{
+				Approvers: []string{"alice"},
+				Reviewers: []string{"alice"},
+				Labels:    []string{},
+			}},
+		},
+		{
+			name: "fallback approvers are not used when approvers remain",
+			in: httpResult{fullConfig: FullConfig{Filters: map[string]repoowners.Config{
+				".*": {Approvers: []string{"bob"}},
+			}}},
+			policy: ownersPolicy{FallbackApprovers: []string{"alice"}},
+			expected: FullConfig{Filters: map[string]repoowners.Config{
+				".*": {
+					Approvers: []string{"bob"},
+					Reviewers: []string{"bob"},
+					Labels:    []string{},
+				},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assertEqual(t, tc.in.resolveOwnerAliases(cleaner, tc.policy), tc.expected)
+		})
+	}
+}
+
+func TestWriteOwners(t *testing.T) {
+	existing := []byte("approvers:\n- old\n")
+	testCases := []struct {
+		name      string
+		in        httpResult
+		overrides ownersOverrides
+		expected  map[string]string
+	}{
+		{
+			name: "OWNERS files are written",
+			in:   httpResult{simpleConfig: SimpleConfig{Config: repoowners.Config{Approvers: []string{"alice"}}}},
+			expected: map[string]string{
+				"config": "# header\napprovers:\n- alice\noptions: {}\nreviewers:\n- alice\n",
+				"jobs":   "# header\napprovers:\n- alice\noptions: {}\nreviewers:\n- alice\n",
+			},
+		},
+		{
+			name:      "skipped directory is left untouched",
+			in:        httpResult{simpleConfig: SimpleConfig{Config: repoowners.Config{Approvers: []string{"alice"}}}},
+			overrides: ownersOverrides{Directories: map[string]ownersPolicy{"jobs": {Skip: true}}},
+			expected: map[string]string{
+				"config": "# header\napprovers:\n- alice\noptions: {}\nreviewers:\n- alice\n",
+				"jobs":   string(existing),
+			},
+		},
+		{
+			name: "existing file is kept when no approvers remain",
+			in:   httpResult{simpleConfig: SimpleConfig{Config: repoowners.Config{Reviewers: []string{"alice"}}}},
+			expected: map[string]string{
+				"config": string(existing),
+				"jobs":   string(existing),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var directories []string
+			for _, d := range []string{"config", "jobs"} {
+				if err := os.Mkdir(filepath.Join(dir, d), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, d, "OWNERS"), existing, 0644); err != nil {
+					t.Fatal(err)
+				}
+				directories = append(directories, filepath.Join(dir, d))
+			}
+			overrides := ownersOverrides{Directories: map[string]ownersPolicy{}}
+			for d, policy := range tc.overrides.Directories {
+				overrides.Directories[filepath.Join(dir, d)] = policy
+			}
+
+			if err := writeOwners(orgRepo{Directories: directories}, tc.in, noOpCleaner, overrides, "# header\n"); err != nil {
+				t.Fatalf("writeOwners failed: %v", err)
+			}
+			actual := map[string]string{}
+			for _, d := range []string{"config", "jobs"} {
+				content, err := os.ReadFile(filepath.Join(dir, d, "OWNERS"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				actual[d] = string(content)
+			}
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("OWNERS files differ from expected: %s", diff)
+			}
 		})
 	}
 }
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// ownersOverrides configures how the OWNERS files are synced for individual directories
+type ownersOverrides struct {
+	// Directories maps the directories, as they are given to --ignore-repo, to their policy
+	Directories map[string]ownersPolicy `json:"directories,omitempty"`
+}
+
+// ownersPolicy overrides the OWNERS file synced from upstream for a directory
+type ownersPolicy struct {
+	// Skip leaves the OWNERS file in the directory untouched
+	Skip bool `json:"skip,omitempty"`
+	// ExtraApprovers are added to the approvers from upstream
+	ExtraApprovers []string `json:"extra_approvers,omitempty"`
+	// ExtraReviewers are added to the reviewers from upstream
+	ExtraReviewers []string `json:"extra_reviewers,omitempty"`
+	// ExcludedLogins are never added to the OWNERS file
+	ExcludedLogins []string `json:"excluded_logins,omitempty"`
+	// FallbackApprovers are used when none of the upstream approvers remain after filtering
+	FallbackApprovers []string `json:"fallback_approvers,omitempty"`
+}
+
+func (o ownersOverrides) policyFor(directory string) ownersPolicy {
+	return o.Directories[filepath.Clean(directory)]
+}
+
+func (o ownersOverrides) validate() error {
+	var errs []error
+	for directory, policy := range o.Directories {
+		if policy.Skip && (len(policy.ExtraApprovers) > 0 || len(policy.ExtraReviewers) > 0 || len(policy.ExcludedLogins) > 0 || len(policy.FallbackApprovers) > 0) {
+			errs = append(errs, fmt.Errorf("directory %s: skip cannot be combined with other settings", directory))
+		}
+		for field, logins := range map[string][]string{
+			"extra_approvers":    policy.ExtraApprovers,
+			"extra_reviewers":    policy.ExtraReviewers,
+			"excluded_logins":    policy.ExcludedLogins,
+			"fallback_approvers": policy.FallbackApprovers,
+		} {
+			for _, login := range logins {
+				if login == "" {
+					errs = append(errs, fmt.Errorf("directory %s: %s must not contain empty logins", directory, field))
+					break
+				}
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func loadOwnersOverrides(path string) (ownersOverrides, error) {
+	var overrides ownersOverrides
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return overrides, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := yaml.UnmarshalStrict(data, &overrides); err != nil {
+		return overrides, fmt.Errorf("failed to unmarshal %s: %w", path, err)
+	}
+	if err := overrides.validate(); err != nil {
+		return overrides, fmt.Errorf("invalid overrides in %s: %w", path, err)
+	}
+	directories := make(map[string]ownersPolicy, len(overrides.Directories))
+	for directory, policy := range overrides.Directories {
+		directories[filepath.Clean(directory)] = policy
+	}
+	overrides.Directories = directories
+	return overrides, nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadOwnersOverrides(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		expected    ownersOverrides
+		expectedErr bool
+	}{
+		{
+			name: "valid overrides",
+			content: `directories:
+  ci-operator/config/openshift/origin/:
+    skip: true
+  ci-operator/jobs/openshift/origin:
+    extra_approvers:
+    - alice
+    fallback_approvers:
+    - bob
+`,
+			expected: ownersOverrides{Directories: map[string]ownersPolicy{
+				"ci-operator/config/openshift/origin": {Skip: true},
+				"ci-operator/jobs/openshift/origin": {
+					ExtraApprovers:    []string{"alice"},
+					FallbackApprovers: []string{"bob"},
+				},
+			}},
+		},
+		{
+			name:        "unknown field",
+			content:     "directories:\n  ci-operator/config/openshift/origin:\n    approvers: [alice]\n",
+			expectedErr: true,
+		},
+		{
+			name:        "skip combined with other settings",
+			content:     "directories:\n  ci-operator/config/openshift/origin:\n    skip: true\n    extra_reviewers: [alice]\n",
+			expectedErr: true,
+		},
+		{
+			name:        "empty login",
+			content:     "directories:\n  ci-operator/config/openshift/origin:\n    excluded_logins: ['']\n",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "overrides.yaml")
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			actual, err := loadOwnersOverrides(path)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("expected error: %t, got: %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("overrides differ from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateOwnersOverrides(t *testing.T) {
+	testCases := []struct {
+		name      string
+		overrides ownersOverrides
+		expected  string
+	}{
+		{
+			name: "valid",
+			overrides: ownersOverrides{Directories: map[string]ownersPolicy{
+				"ci-operator/config/openshift/origin": {ExtraApprovers: []string{"alice"}},
+			}},
+		},
+		{
+			name: "skip combined with other settings",
+			overrides: ownersOverrides{Directories: map[string]ownersPolicy{
+				"ci-operator/config/openshift/origin": {Skip: true, ExtraReviewers: []string{"alice"}},
+			}},
+			expected: "directory ci-operator/config/openshift/origin: skip cannot be combined with other settings",
+		},
+		{
+			name: "empty login",
+			overrides: ownersOverrides{Directories: map[string]ownersPolicy{
+				"ci-operator/config/openshift/origin": {ExcludedLogins: []string{""}},
+			}},
+			expected: "directory ci-operator/config/openshift/origin: excluded_logins must not contain empty logins",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual string
+			if err := tc.overrides.validate(); err != nil {
+				actual = err.Error()
+			}
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/ci-tools/pkg/api/ocplifecycle"
+)
+
+const (
+	mergeBlockerLabel = "tide/merge-blocker"
+	ocpProductName    = "ocp"
+
+	defaultBodyTemplate = "The following branches are being fast-forwarded from the current development branch ({{ .Branch }}) as placeholders for future releases. No merging is allowed into these release branches until they are unfrozen for production release.\n\n" +
+		"{{ range .FrozenBranches }} - `{{ . }}`\n{{ end }}" +
+		"\nFor more information, see the [branching documentation](https://docs.ci.openshift.org/docs/architecture/branching/)."
+)
+
+// Config allows to customize the blocking issues per org or repo
+type Config struct {
+	// Default applies to all repos without a more specific configuration
+	Default IssueConfig `json:"default,omitempty"`
+	// Orgs holds configuration by org, overriding the default
+	Orgs map[string]IssueConfig `json:"orgs,omitempty"`
+	// Repos holds configuration by org/repo, overriding the org and default
+	Repos map[string]IssueConfig `json:"repos,omitempty"`
+}
+
+// IssueConfig describes the blocking issue for a repo. Unset fields are
+// inherited from the less specific configuration.
+type IssueConfig struct {
+	// BodyTemplate is a Go template for the issue body. It has access to the
+	// org, repo and development branch of the repo as well as the list of
+	// frozen branches.
+	BodyTemplate string `json:"body_template,omitempty"`
+	// Labels are added to the issue in addition to the merge blocker label
+	Labels []string `json:"labels,omitempty"`
+	// Assignees are assigned to the issue
+	Assignees []string `json:"assignees,omitempty"`
+	// LifecyclePhases restricts blocking future branches to the ones whose
+	// release is currently in one of the phases. Requires the lifecycle
+	// config; releases without lifecycle information are always blocked.
+	LifecyclePhases []ocplifecycle.LifecycleEvent `json:"lifecycle_phases,omitempty"`
+
+	body *template.Template
+}
+
+// templateData is passed to the body template
+type templateData struct {
+	Org            string
+	Repo           string
+	Branch         string
+	FrozenBranches []string
+}
+
+func loadConfig(path string) (*Config, error) {
+	config := &Config{}
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+		}
+		if err := yaml.UnmarshalStrict(raw, config); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config %s: %w", path, err)
+		}
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+func (c *Config) validate() error {
+	validate := func(name string, ic IssueConfig) error {
+		if ic.BodyTemplate != "" {
+			if _, err := template.New(name).Parse(ic.BodyTemplate); err != nil {
+				return fmt.Errorf("%s: failed to parse body template: %w", name, err)
+			}
+		}
+		for _, phase := range ic.LifecyclePhases {
+			if err := phase.Validate(); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		return nil
+	}
+	if err := validate("default", c.Default); err != nil {
+		return err
+	}
+	for org, ic := range c.Orgs {
+		if err := validate(org, ic); err != nil {
+			return err
+		}
+	}
+	for orgRepo, ic := range c.Repos {
+		if err := validate(orgRepo, ic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requireLifecycleConfig fails when lifecycle phases are configured but no
+// lifecycle config is given, as the phases would silently be ignored
+func (c *Config) requireLifecycleConfig(lifecycleConfigPath string) error {
+	if lifecycleConfigPath != "" {
+		return nil
+	}
+	var configured []string
+	if len(c.Default.LifecyclePhases) > 0 {
+		configured = append(configured, "default")
+	}
+	for _, org := range sets.List(sets.KeySet(c.Orgs)) {
+		if len(c.Orgs[org].LifecyclePhases) > 0 {
+			configured = append(configured, org)
+		}
+	}
+	for _, orgRepo := range sets.List(sets.KeySet(c.Repos)) {
+		if len(c.Repos[orgRepo].LifecyclePhases) > 0 {
+			configured = append(configured, orgRepo)
+		}
+	}
+	if len(configured) > 0 {
+		return fmt.Errorf("--lifecycle-config is required when lifecycle_phases are set, but they are set for: %s", strings.Join(configured, ", "))
+	}
+	return nil
+}
+
+// For resolves the configuration for a repo
+func (c *Config) For(org, repo string) (IssueConfig, error) {
+	resolved := IssueConfig{BodyTemplate: defaultBodyTemplate}
+	for _, ic := range []IssueConfig{c.Default, c.Orgs[org], c.Repos[fmt.Sprintf("%s/%s", org, repo)]} {
+		if ic.BodyTemplate != "" {
+			resolved.BodyTemplate = ic.BodyTemplate
+		}
+		if ic.Labels != nil {
+			resolved.Labels = ic.Labels
+		}
+		if ic.Assignees != nil {
+			resolved.Assignees = ic.Assignees
+		}
+		if ic.LifecyclePhases != nil {
+			resolved.LifecyclePhases = ic.LifecyclePhases
+		}
+	}
+	body, err := template.New("body").Parse(resolved.BodyTemplate)
+	if err != nil {
+		return resolved, fmt.Errorf("failed to parse body template: %w", err)
+	}
+	resolved.body = body
+	return resolved, nil
+}
+
+func (ic IssueConfig) renderBody(data templateData) (string, error) {
+	body := ic.body
+	if body == nil {
+		var err error
+		if body, err = template.New("body").Parse(defaultBodyTemplate); err != nil {
+			return "", err
+		}
+	}
+	var buf bytes.Buffer
+	if err := body.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render body template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// labels returns all labels the issue must have
+func (ic IssueConfig) labels() []string {
+	return sets.List(sets.New[string](ic.Labels...).Insert(mergeBlockerLabel))
+}
+
+// inScope determines whether a future release should be blocked, given the
+// phase it is in at the moment
+func (ic IssueConfig) inScope(lifecycleConfig ocplifecycle.Config, version string, now time.Time) bool {
+	if len(ic.LifecyclePhases) == 0 || lifecycleConfig == nil {
+		return true
+	}
+	timeline, ok := lifecycleConfig.GetTimelinesByVersion(ocpProductName)[version]
+	if !ok || len(timeline) == 0 {
+		return true
+	}
+	current, _ := timeline.DeterminePlaceInTime(now)
+	if current.LifecyclePhase.Event == "" {
+		// the release has not entered any phase yet
+		return false
+	}
+	for _, phase := range ic.LifecyclePhases {
+		if current.LifecyclePhase.Event == phase {
+			return true
+		}
+	}
+	return false
+}
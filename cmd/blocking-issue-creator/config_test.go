@@ -0,0 +1,178 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/test-infra/prow/github"
+	"k8s.io/test-infra/prow/github/fakegithub"
+
+	cioperatorapi "github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/api/ocplifecycle"
+	"github.com/openshift/ci-tools/pkg/config"
+	"github.com/openshift/ci-tools/pkg/testhelper"
+)
+
+func TestConfigFor(t *testing.T) {
+	c := &Config{
+		Default: IssueConfig{Labels: []string{"default"}},
+		Orgs: map[string]IssueConfig{
+			"org": {BodyTemplate: "org {{ .Branch }}", Assignees: []string{"org-admin"}},
+		},
+		Repos: map[string]IssueConfig{
+			"org/repo": {Labels: []string{"repo"}, LifecyclePhases: []ocplifecycle.LifecycleEvent{ocplifecycle.LifecycleEventOpen}},
+		},
+	}
+	if err := c.validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	testCases := []struct {
+		org, repo string
+		expected  IssueConfig
+	}{
+		{
+			org:      "other",
+			repo:     "repo",
+			expected: IssueConfig{BodyTemplate: defaultBodyTemplate, Labels: []string{"default"}},
+		},
+		{
+			org:      "org",
+			repo:     "other",
+			expected: IssueConfig{BodyTemplate: "org {{ .Branch }}", Labels: []string{"default"}, Assignees: []string{"org-admin"}},
+		},
+		{
+			org:      "org",
+			repo:     "repo",
+			expected: IssueConfig{BodyTemplate: "org {{ .Branch }}", Labels: []string{"repo"}, Assignees: []string{"org-admin"}, LifecyclePhases: []ocplifecycle.LifecycleEvent{ocplifecycle.LifecycleEventOpen}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.org+"/"+tc.repo, func(t *testing.T) {
+			actual, err := c.For(tc.org, tc.repo)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, actual, cmpopts.IgnoreUnexported(IssueConfig{})); diff != "" {
+				t.Errorf("unexpected config: %s", diff)
+			}
+		})
+	}
+
+	invalid := &Config{Repos: map[string]IssueConfig{"org/repo": {LifecyclePhases: []ocplifecycle.LifecycleEvent{"party"}}}}
+	if err := invalid.validate(); err == nil {
+		t.Error("expected an error for an unknown lifecycle phase")
+	}
+}
+
+func TestRequireLifecycleConfig(t *testing.T) {
+	phases := []ocplifecycle.LifecycleEvent{ocplifecycle.LifecycleEventOpen}
+	testCases := []struct {
+		name          string
+		config        Config
+		lifecyclePath string
+		expectedErr   error
+	}{
+		{
+			name:   "no lifecycle phases",
+			config: Config{Default: IssueConfig{Labels: []string{"label"}}, Repos: map[string]IssueConfig{"org/repo": {}}},
+		},
+		{
+			name:          "lifecycle phases with lifecycle config",
+			config:        Config{Default: IssueConfig{LifecyclePhases: phases}},
+			lifecyclePath: "lifecycle.yaml",
+		},
+		{
+			name: "lifecycle phases without lifecycle config",
+			config: Config{
+				Default: IssueConfig{LifecyclePhases: phases},
+				Orgs:    map[string]IssueConfig{"org": {LifecyclePhases: phases}, "other": {}},
+				Repos:   map[string]IssueConfig{"org/repo": {LifecyclePhases: phases}},
+			},
+			expectedErr: errors.New("--lifecycle-config is required when lifecycle_phases are set, but they are set for: default, org, org/repo"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.requireLifecycleConfig(tc.lifecyclePath)
+			if diff := cmp.Diff(tc.expectedErr, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("unexpected error: %s", diff)
+			}
+		})
+	}
+}
+
+func TestInScope(t *testing.T) {
+	at := func(month time.Month) *metav1.Time { return &metav1.Time{Time: time.Date(2023, month, 1, 0, 0, 0, 0, time.UTC)} }
+	lifecycleConfig := ocplifecycle.Config{"ocp": {
+		"4.15": {{Event: ocplifecycle.LifecycleEventOpen, When: at(time.January)}, {Event: ocplifecycle.LifecycleEventFeatureFreeze, When: at(time.March)}},
+		"4.16": {{Event: ocplifecycle.LifecycleEventOpen, When: at(time.June)}},
+	}}
+	now := time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)
+	ic := IssueConfig{LifecyclePhases: []ocplifecycle.LifecycleEvent{ocplifecycle.LifecycleEventFeatureFreeze}}
+
+	testCases := []struct {
+		name      string
+		config    IssueConfig
+		lifecycle ocplifecycle.Config
+		version   string
+		expected  bool
+	}{
+		{name: "no phases configured", config: IssueConfig{}, lifecycle: lifecycleConfig, version: "4.16", expected: true},
+		{name: "no lifecycle config", config: ic, version: "4.16", expected: true},
+		{name: "release in a configured phase", config: ic, lifecycle: lifecycleConfig, version: "4.15", expected: true},
+		{name: "release not in any phase yet", config: ic, lifecycle: lifecycleConfig, version: "4.16", expected: false},
+		{name: "release without lifecycle information", config: ic, lifecycle: lifecycleConfig, version: "4.17", expected: true},
+		{name: "release in another phase", config: IssueConfig{LifecyclePhases: []ocplifecycle.LifecycleEvent{ocplifecycle.LifecycleEventOpen}}, lifecycle: lifecycleConfig, version: "4.15", expected: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.config.inScope(tc.lifecycle, tc.version, now); actual != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestManageIssuesWithConfig(t *testing.T) {
+	repoInfo := &config.Info{Metadata: cioperatorapi.Metadata{Org: "org", Repo: "repo", Branch: "main"}}
+	c := &Config{Default: IssueConfig{BodyTemplate: "{{ .Org }}/{{ .Repo }}@{{ .Branch }} freezes {{ range .FrozenBranches }}{{ . }} {{ end }}", Labels: []string{"extra"}, Assignees: []string{"someone"}}}
+	ic, err := c.For("org", "repo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fgh := fakeGithubClient{FakeClient: fakegithub.NewFakeClient()}
+	fgh.FakeClient.Issues = map[int]*github.Issue{}
+	if err := manageIssues(fgh, "", repoInfo, sets.New[string]("release-4.16", "release-4.15"), ic, logrus.WithField("test", t.Name())); err != nil {
+		t.Fatal(err)
+	}
+	expected := []github.Issue{{
+		ID:        1,
+		Title:     "Future Release Branches Frozen For Merging | branch:release-4.15 branch:release-4.16",
+		Body:      "org/repo@main freezes release-4.15 release-4.16 ",
+		Labels:    []github.Label{{Name: "extra"}, {Name: "tide/merge-blocker"}},
+		Assignees: []github.User{{Name: "someone"}},
+	}}
+	created, _ := fgh.ListOpenIssues("org", "repo")
+	if diff := cmp.Diff(expected, created); diff != "" {
+		t.Fatalf("unexpected created issue: %s", diff)
+	}
+
+	fgh.FakeClient.Issues = map[int]*github.Issue{1: {ID: 1, Number: 1, Title: expected[0].Title, Body: expected[0].Body, Labels: []github.Label{{Name: "tide/merge-blocker"}}}}
+	if err := manageIssues(fgh, "", repoInfo, sets.New[string]("release-4.16", "release-4.15"), ic, logrus.WithField("test", t.Name())); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"org/repo#1:extra"}, fgh.FakeClient.IssueLabelsAdded); diff != "" {
+		t.Errorf("unexpected added labels: %s", diff)
+	}
+	if diff := cmp.Diff([]string{"org/repo#1:someone"}, fgh.FakeClient.AssigneesAdded); diff != "" {
+		t.Errorf("unexpected added assignees: %s", diff)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"k8s.io/test-infra/prow/github"
 
 	"github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/api/ocplifecycle"
 	"github.com/openshift/ci-tools/pkg/config"
 	"github.com/openshift/ci-tools/pkg/promotion"
 )
@@ -24,6 +25,8 @@ type githubClient interface {
 	CreateIssue(org, repo, title, body string, milestone int, labels, assignees []string) (int, error)
 	EditIssue(org, repo string, number int, issue *github.Issue) (*github.Issue, error)
 	CloseIssue(org, repo string, number int) error
+	AddLabel(org, repo string, number int, label string) error
+	AssignIssue(org, repo string, number int, logins []string) error
 }
 
 type options struct {
@@ -31,6 +34,9 @@ type options struct {
 	github prowflagutil.GitHubOptions
 
 	dryRun bool
+
+	configPath          string
+	lifecycleConfigPath string
 }
 
 func (o *options) Validate() error {
@@ -48,6 +54,8 @@ func gatherOptions() options {
 	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 
 	fs.BoolVar(&o.dryRun, "dry-run", true, "Dry run for testing. Uses API tokens but does not mutate.")
+	fs.StringVar(&o.configPath, "config", "", "Path to a config file customizing the issue body, labels, assignees and lifecycle phases per org or repo")
+	fs.StringVar(&o.lifecycleConfigPath, "lifecycle-config", "", "Path to the lifecycle config file, required to restrict blocking to lifecycle phases")
 
 	o.github.AddFlags(fs)
 	o.FutureOptions.Bind(fs)
@@ -64,6 +72,21 @@ func main() {
 		logrus.Fatalf("Invalid options: %v", err)
 	}
 
+	issueConfig, err := loadConfig(o.configPath)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to load config.")
+	}
+	if err := issueConfig.requireLifecycleConfig(o.lifecycleConfigPath); err != nil {
+		logrus.Fatalf("Invalid options: %v", err)
+	}
+	var lifecycleConfig ocplifecycle.Config
+	if o.lifecycleConfigPath != "" {
+		if lifecycleConfig, err = ocplifecycle.LoadConfig(o.lifecycleConfigPath); err != nil {
+			logrus.WithError(err).Fatal("Failed to load the lifecycle config.")
+		}
+	}
+	now := time.Now()
+
 	if err := secret.Add(o.github.TokenPath); err != nil {
 		logrus.WithError(err).Fatal("Error starting secrets agent.")
 	}
@@ -86,6 +109,13 @@ func main() {
 	if err := o.OperateOnCIOperatorConfigDir(o.ConfigDir, api.WithoutOKD, func(configuration *api.ReleaseBuildConfiguration, repoInfo *config.Info) error {
 		logger := config.LoggerForInfo(*repoInfo)
 
+		repoConfig, err := issueConfig.For(repoInfo.Org, repoInfo.Repo)
+		if err != nil {
+			logger.WithError(err).Error("Failed to resolve config.")
+			failed = true
+			return nil
+		}
+
 		branches := sets.New[string]()
 		for _, futureRelease := range o.FutureReleases.Strings() {
 			futureBranch, err := promotion.DetermineReleaseBranch(o.CurrentRelease, futureRelease, repoInfo.Branch)
@@ -98,11 +128,15 @@ func main() {
 				logger.Debugf("Skipping branch %s as it is the current development branch.", futureBranch)
 				continue
 			}
+			if !repoConfig.inScope(lifecycleConfig, futureRelease, now) {
+				logger.Debugf("Skipping branch %s as release %s is not in a configured lifecycle phase.", futureBranch, futureRelease)
+				continue
+			}
 
 			branches.Insert(futureBranch)
 		}
 
-		if err := manageIssues(client, botUser.Login, repoInfo, branches, logger); err != nil {
+		if err := manageIssues(client, botUser.Login, repoInfo, branches, repoConfig, logger); err != nil {
 			failed = true
 		}
 
@@ -114,17 +148,19 @@ func main() {
 	}
 }
 
-func manageIssues(client githubClient, githubLogin string, repoInfo *config.Info, branches sets.Set[string], logger *logrus.Entry) error {
+func manageIssues(client githubClient, githubLogin string, repoInfo *config.Info, branches sets.Set[string], issueConfig IssueConfig, logger *logrus.Entry) error {
 	var branchTokens []string
-	body := fmt.Sprintf("The following branches are being fast-forwarded from the current development branch (%s) as placeholders for future releases. No merging is allowed into these release branches until they are unfrozen for production release.\n\n", repoInfo.Branch)
 	for _, branch := range sets.List(branches) {
-		body += fmt.Sprintf(" - `%s`\n", branch)
 		branchTokens = append(branchTokens, fmt.Sprintf("branch:%s", branch))
 	}
-	body += "\nFor more information, see the [branching documentation](https://docs.ci.openshift.org/docs/architecture/branching/)."
+	body, err := issueConfig.renderBody(templateData{Org: repoInfo.Org, Repo: repoInfo.Repo, Branch: repoInfo.Branch, FrozenBranches: sets.List(branches)})
+	if err != nil {
+		logger.WithError(err).Error("Failed to render issue body.")
+		return err
+	}
 	title := fmt.Sprintf("Future Release Branches Frozen For Merging | %s", strings.Join(branchTokens, " "))
 
-	query := fmt.Sprintf("is:issue state:open label:\"tide/merge-blocker\" repo:%s/%s author:%s", repoInfo.Org, repoInfo.Repo, githubLogin)
+	query := fmt.Sprintf("is:issue state:open label:\"%s\" repo:%s/%s author:%s", mergeBlockerLabel, repoInfo.Org, repoInfo.Repo, githubLogin)
 	sort := "updated"
 	// We will make sure that the first issue in the list will be with the most recent update.
 	ascending := false
@@ -162,6 +198,9 @@ func manageIssues(client githubClient, githubLogin string, repoInfo *config.Info
 	if len(issues) != 0 {
 		logger = logger.WithField("merge-blocker", issues[0])
 		existing := issues[0]
+		if err := ensureLabelsAndAssignees(client, repoInfo, existing, issueConfig, logger); err != nil {
+			return err
+		}
 		needsUpdate := existing.Title != title || existing.Body != body
 		if !needsUpdate {
 			logger.Info("Current merge-blocker issue is up to date, no update necessary.")
@@ -179,7 +218,7 @@ func manageIssues(client githubClient, githubLogin string, repoInfo *config.Info
 		logger.WithField("number", toBeUpdated.Number).Info("Updated issue")
 	} else {
 		// we need to create a new issue
-		issueNumber, err := client.CreateIssue(repoInfo.Org, repoInfo.Repo, title, body, 0, []string{"tide/merge-blocker"}, []string{})
+		issueNumber, err := client.CreateIssue(repoInfo.Org, repoInfo.Repo, title, body, 0, issueConfig.labels(), append([]string{}, issueConfig.Assignees...))
 		if err != nil {
 			logger.WithError(err).Error("Failed to create merge blocker issue.")
 			return err
@@ -188,3 +227,41 @@ func manageIssues(client githubClient, githubLogin string, repoInfo *config.Info
 	}
 	return nil
 }
+
+// ensureLabelsAndAssignees adds the configured labels and assignees that are
+// missing on an existing issue. Additional ones set by humans are left alone.
+func ensureLabelsAndAssignees(client githubClient, repoInfo *config.Info, issue github.Issue, issueConfig IssueConfig, logger *logrus.Entry) error {
+	existingLabels := sets.New[string]()
+	for _, label := range issue.Labels {
+		existingLabels.Insert(label.Name)
+	}
+	for _, label := range issueConfig.labels() {
+		if existingLabels.Has(label) {
+			continue
+		}
+		if err := client.AddLabel(repoInfo.Org, repoInfo.Repo, issue.Number, label); err != nil {
+			logger.WithError(err).Error("Failed to add label to issue.")
+			return err
+		}
+		logger.WithField("label", label).Info("Added label to issue")
+	}
+
+	existingAssignees := sets.New[string]()
+	for _, assignee := range issue.Assignees {
+		existingAssignees.Insert(github.NormLogin(assignee.Login))
+	}
+	var missing []string
+	for _, assignee := range issueConfig.Assignees {
+		if !existingAssignees.Has(github.NormLogin(assignee)) {
+			missing = append(missing, assignee)
+		}
+	}
+	if len(missing) > 0 {
+		if err := client.AssignIssue(repoInfo.Org, repoInfo.Repo, issue.Number, missing); err != nil {
+			logger.WithError(err).Error("Failed to assign issue.")
+			return err
+		}
+		logger.WithField("assignees", missing).Info("Assigned issue")
+	}
+	return nil
+}
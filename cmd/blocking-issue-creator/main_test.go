@@ -224,7 +224,7 @@ func TestManageIssues(t *testing.T) {
 			}
 			fgh.FakeClient.Issues = tc.issues
 
-			if err := manageIssues(fgh, "", tc.repoInfo, tc.branches, logrus.WithField("id", tc.id)); err != nil {
+			if err := manageIssues(fgh, "", tc.repoInfo, tc.branches, IssueConfig{}, logrus.WithField("id", tc.id)); err != nil {
 				t.Fatal(err)
 			}
 
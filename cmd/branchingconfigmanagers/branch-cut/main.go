@@ -0,0 +1,221 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/ini.v1"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/test-infra/prow/flagutil"
+
+	"github.com/openshift/ci-tools/pkg/api/ocplifecycle"
+	"github.com/openshift/ci-tools/pkg/branchcuts/bumper"
+	"github.com/openshift/ci-tools/pkg/branchcuts/bumper/repo"
+	cioperatorcfg "github.com/openshift/ci-tools/pkg/config"
+	"github.com/openshift/ci-tools/pkg/github/prcreation"
+)
+
+const (
+	releaseControllerConfigPath           = "core-services/release-controller/_releases"
+	releaseJobsPath                       = "ci-operator/config/openshift/release"
+	rpmMirroringServicesPath              = "core-services/release-controller/_repos"
+	rpmMirroringServicesGlobPatternFormat = "ocp-%s*.repo"
+	imageMirroringPath                    = "core-services/image-mirroring/openshift"
+)
+
+// step is a single branch cut task, creating the configuration of the next release
+// from the configuration of the current one
+type step struct {
+	name string
+	run  func(o *options) error
+}
+
+var steps = []step{
+	{name: "release-controller-config", run: bumpReleaseControllerConfig},
+	{name: "release-gating-jobs", run: bumpReleaseGatingJobs},
+	{name: "rpm-mirroring", run: bumpRPMMirroring},
+	{name: "image-mirroring", run: bumpImageMirroring},
+}
+
+type options struct {
+	prcreation.PRCreationOptions
+	curOCPVersion    string
+	releaseRepoDir   string
+	logLevel         int
+	newIntervalValue int
+	skipSteps        flagutil.Strings
+	createPR         bool
+	prAssignee       string
+}
+
+func gatherOptions(fs *flag.FlagSet, args []string) (*options, error) {
+	o := &options{}
+	fs.StringVar(&o.curOCPVersion, "current-release", "", "Current OCP version")
+	fs.StringVar(&o.releaseRepoDir, "release-repo", "", "Path to 'openshift/release/ folder")
+	fs.IntVar(&o.newIntervalValue, "interval", 168, "New interval to set on the release gating jobs of the current release")
+	fs.IntVar(&o.logLevel, "log-level", int(logrus.DebugLevel), "Log level")
+	fs.Var(&o.skipSteps, "skip-step", fmt.Sprintf("Name of a step to skip, can be passed multiple times. One of: %s", strings.Join(stepNames(), ", ")))
+	fs.BoolVar(&o.createPR, "create-pr", false, "Whether to open a pull request with the changes of all steps")
+	fs.StringVar(&o.prAssignee, "pr-assignee", "", "The assignee of the pull request to create")
+	o.PRCreationOptions.AddFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("failed to parse flags: %w", err)
+	}
+	return o, nil
+}
+
+func (o *options) validate() error {
+	var errs []error
+	if _, err := ocplifecycle.ParseMajorMinor(o.curOCPVersion); err != nil {
+		errs = append(errs, fmt.Errorf("error parsing current-release %q", o.curOCPVersion))
+	}
+
+	if o.newIntervalValue < 0 {
+		errs = append(errs, errors.New("error parsing interval: value is not a positive integer"))
+	}
+
+	if o.releaseRepoDir != "" {
+		if !path.IsAbs(o.releaseRepoDir) {
+			errs = append(errs, errors.New("error parsing release repo path: path has to be absolute"))
+		}
+	} else {
+		errs = append(errs, errors.New("error parsing release repo path: path is mandatory"))
+	}
+
+	known := sets.New[string](stepNames()...)
+	for _, s := range o.skipSteps.Strings() {
+		if !known.Has(s) {
+			errs = append(errs, fmt.Errorf("unknown step %q passed to --skip-step", s))
+		}
+	}
+
+	if o.createPR {
+		if err := o.PRCreationOptions.Finalize(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func stepNames() []string {
+	var names []string
+	for _, s := range steps {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+func main() {
+	o, err := gatherOptions(flag.NewFlagSet(os.Args[0], flag.ExitOnError), os.Args[1:])
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to gather options")
+	}
+	if err := o.validate(); err != nil {
+		logrus.WithError(err).Fatal("invalid options")
+	}
+
+	logrus.SetLevel(logrus.Level(o.logLevel))
+	logrus.Debugf("using options %+v", o)
+
+	if err := runSteps(o, steps); err != nil {
+		logrus.WithError(err).Fatal("failed to cut the branch")
+	}
+	logrus.Info("all steps finished")
+
+	if !o.createPR {
+		return
+	}
+	if err := o.PRCreationOptions.UpsertPR(o.releaseRepoDir,
+		"openshift",
+		"release",
+		"master",
+		prTitle(o.curOCPVersion),
+		prcreation.PrBody(prBody(o)),
+		prcreation.PrAssignee(o.prAssignee),
+	); err != nil {
+		logrus.WithError(err).Fatal("failed to upsert PR")
+	}
+}
+
+// runSteps runs every step not skipped by the options, continuing past the failing
+// ones so that a single run reports all the problems
+func runSteps(o *options, steps []step) error {
+	skipped := sets.New[string](o.skipSteps.Strings()...)
+	var errs []error
+	for _, s := range steps {
+		logger := logrus.WithField("step", s.name)
+		if skipped.Has(s.name) {
+			logger.Info("skipping step")
+			continue
+		}
+		logger.Info("running step")
+		if err := s.run(o); err != nil {
+			errs = append(errs, fmt.Errorf("step %s failed: %w", s.name, err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func prTitle(curOCPVersion string) string {
+	mm, err := ocplifecycle.ParseMajorMinor(curOCPVersion)
+	if err != nil {
+		return fmt.Sprintf("Branch cut of %s", curOCPVersion)
+	}
+	return fmt.Sprintf("Branch cut: create the configuration of %s", mm.GetFutureVersion())
+}
+
+func prBody(o *options) string {
+	skipped := sets.New[string](o.skipSteps.Strings()...)
+	var b strings.Builder
+	b.WriteString("This pull request was created by the `branch-cut` tool and contains the changes of the following steps:\n\n")
+	for _, s := range steps {
+		if !skipped.Has(s.name) {
+			b.WriteString(fmt.Sprintf("- `%s`\n", s.name))
+		}
+	}
+	return b.String()
+}
+
+func bumpReleaseControllerConfig(o *options) error {
+	b, err := bumper.NewReleaseControllerConfigBumper(o.curOCPVersion, path.Join(o.releaseRepoDir, releaseControllerConfigPath))
+	if err != nil {
+		return fmt.Errorf("new bumper: %w", err)
+	}
+	return bumper.Bump[*bumper.ReleaseConfig](b, &bumper.BumpingOptions{})
+}
+
+func bumpReleaseGatingJobs(o *options) error {
+	b, err := bumper.NewGeneratedReleaseGatingJobsBumper(o.curOCPVersion, path.Join(o.releaseRepoDir, releaseJobsPath), o.newIntervalValue)
+	if err != nil {
+		return fmt.Errorf("new bumper: %w", err)
+	}
+	return bumper.Bump[*cioperatorcfg.DataWithInfo](b, &bumper.BumpingOptions{})
+}
+
+func bumpRPMMirroring(o *options) error {
+	b, err := repo.NewRepoBumper(&repo.RepoBumperOptions{
+		FilesDir:      path.Join(o.releaseRepoDir, rpmMirroringServicesPath),
+		GlobPattern:   fmt.Sprintf(rpmMirroringServicesGlobPatternFormat, o.curOCPVersion),
+		CurOCPRelease: o.curOCPVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("new repo bumper: %w", err)
+	}
+	return bumper.Bump[*ini.File](b, &bumper.BumpingOptions{})
+}
+
+func bumpImageMirroring(o *options) error {
+	b, err := bumper.NewImageMirroringBumper(o.curOCPVersion, path.Join(o.releaseRepoDir, imageMirroringPath))
+	if err != nil {
+		return fmt.Errorf("new bumper: %w", err)
+	}
+	return bumper.Bump[[]string](b, &bumper.BumpingOptions{})
+}
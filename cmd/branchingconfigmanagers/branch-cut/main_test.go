@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/test-infra/prow/flagutil"
+
+	"github.com/openshift/ci-tools/pkg/testhelper"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError error
+	}{
+		{
+			name: "valid options",
+			args: []string{"--current-release", "4.14", "--release-repo", "/release", "--skip-step", "rpm-mirroring"},
+		},
+		{
+			name:          "missing options",
+			args:          []string{},
+			expectedError: errors.New(`[error parsing current-release "", error parsing release repo path: path is mandatory]`),
+		},
+		{
+			name:          "relative release repo and unknown step",
+			args:          []string{"--current-release", "4.14", "--release-repo", "release", "--skip-step", "unknown"},
+			expectedError: errors.New(`[error parsing release repo path: path has to be absolute, unknown step "unknown" passed to --skip-step]`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			o, err := gatherOptions(flag.NewFlagSet("test", flag.ContinueOnError), tc.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedError, o.validate(), testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestRunSteps(t *testing.T) {
+	var ran []string
+	record := func(name string, err error) step {
+		return step{name: name, run: func(*options) error {
+			ran = append(ran, name)
+			return err
+		}}
+	}
+	testSteps := []step{
+		record("first", nil),
+		record("failing", errors.New("oops")),
+		record("skipped", nil),
+		record("last", nil),
+	}
+	err := runSteps(&options{skipSteps: flagutil.NewStrings("skipped")}, testSteps)
+	if diff := cmp.Diff(errors.New("step failing failed: oops"), err, testhelper.EquateErrorMessage); diff != "" {
+		t.Errorf("error differs from expected: %s", diff)
+	}
+	if diff := cmp.Diff([]string{"first", "failing", "last"}, ran); diff != "" {
+		t.Errorf("steps that ran differ from expected: %s", diff)
+	}
+}
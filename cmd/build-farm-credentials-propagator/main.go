@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/test-infra/prow/flagutil"
+	"k8s.io/test-infra/prow/kube"
+	"k8s.io/test-infra/prow/logrusutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+
+	"github.com/openshift/ci-tools/pkg/secrets"
+)
+
+type options struct {
+	kubernetesOptions flagutil.KubernetesOptions
+	secrets           secrets.CLIOptions
+
+	configPath    string
+	refreshBefore time.Duration
+	revoke        bool
+	dryRun        bool
+}
+
+func parseOptions(censor *secrets.DynamicCensor) (*options, error) {
+	o := &options{kubernetesOptions: flagutil.KubernetesOptions{NOInClusterConfigDefault: true}}
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	o.kubernetesOptions.AddFlags(fs)
+	o.secrets.Bind(fs, os.Getenv, censor)
+	fs.StringVar(&o.configPath, "config", "", "Path to the file declaring the service accounts whose credentials are propagated.")
+	fs.DurationVar(&o.refreshBefore, "refresh-before", 30*24*time.Hour, "Credentials that expire within this period are refreshed.")
+	fs.BoolVar(&o.revoke, "revoke-removed-clusters", true, "Whether to remove the credentials of clusters that are no longer part of the build farm from the secret store.")
+	fs.BoolVar(&o.dryRun, "dry-run", true, "Whether to only log the changes instead of generating credentials and updating the secret store.")
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		return nil, fmt.Errorf("failed to parse flags: %w", err)
+	}
+	return o, nil
+}
+
+func (o *options) validate() error {
+	if o.configPath == "" {
+		return errors.New("--config is required")
+	}
+	if o.refreshBefore <= 0 {
+		return errors.New("--refresh-before must be positive")
+	}
+	if err := o.secrets.Validate(); err != nil {
+		return err
+	}
+	return o.kubernetesOptions.Validate(o.dryRun)
+}
+
+func main() {
+	logrusutil.ComponentInit()
+	censor := secrets.NewDynamicCensor()
+	logrus.SetFormatter(logrusutil.NewFormatterWithCensor(logrus.StandardLogger().Formatter, &censor))
+
+	o, err := parseOptions(&censor)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to get options")
+	}
+	if err := o.validate(); err != nil {
+		logrus.WithError(err).Fatal("Invalid options")
+	}
+	if err := o.secrets.Complete(&censor); err != nil {
+		logrus.WithError(err).Fatal("Failed to complete the secret store options")
+	}
+
+	data, err := os.ReadFile(o.configPath)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to read config")
+	}
+	c, err := loadConfig(data, o.refreshBefore)
+	if err != nil {
+		logrus.WithError(err).Fatal("Invalid config")
+	}
+
+	kubeconfigs, err := o.kubernetesOptions.LoadClusterConfigs()
+	if err != nil {
+		// Clusters whose kubeconfig could not be loaded must not lose their credentials
+		logrus.WithError(err).Warn("Failed to load kubeconfigs, not revoking credentials of removed clusters")
+		o.revoke = false
+	}
+	clusters, err := clustersFor(kubeconfigs)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to construct clients")
+	}
+	if len(clusters) == 0 {
+		logrus.Fatal("No clusters available")
+	}
+
+	store, err := o.secrets.NewUnprefixedVaultClient()
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to construct the secret store client")
+	}
+
+	p := &propagator{
+		store:         store,
+		path:          o.secrets.VaultPrefix + "/" + c.Item,
+		clusters:      clusters,
+		revoke:        o.revoke,
+		refreshBefore: o.refreshBefore,
+		dryRun:        o.dryRun,
+		now:           time.Now,
+	}
+	ctx := signals.SetupSignalHandler()
+	if err := p.propagate(ctx, c.ServiceAccounts); err != nil {
+		logrus.WithError(err).Fatal("Failed to propagate build farm credentials")
+	}
+	logrus.Info("Propagated build farm credentials")
+}
+
+func clustersFor(kubeconfigs map[string]rest.Config) (map[string]cluster, error) {
+	clusters := map[string]cluster{}
+	for name, kubeconfig := range kubeconfigs {
+		if name == kube.DefaultClusterAlias || name == kube.InClusterContext {
+			continue
+		}
+		kubeconfig := kubeconfig
+		caData := kubeconfig.CAData
+		if len(caData) == 0 && kubeconfig.CAFile != "" {
+			var err error
+			if caData, err = os.ReadFile(kubeconfig.CAFile); err != nil {
+				return nil, fmt.Errorf("failed to read the CA of cluster %s: %w", name, err)
+			}
+		}
+		client, err := kubernetes.NewForConfig(&kubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct client for cluster %s: %w", name, err)
+		}
+		clusters[name] = cluster{client: client, server: kubeconfig.Host, caData: caData}
+	}
+	return clusters, nil
+}
+
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/ci-tools/pkg/secrets"
+	"github.com/openshift/ci-tools/pkg/vaultclient"
+)
+
+const (
+	defaultNamespace     = "ci"
+	defaultTokenLifetime = 90 * 24 * time.Hour
+
+	kubeconfigSuffix = "config"
+	tokenSuffix      = "token.txt"
+)
+
+// config declares the service accounts whose credentials are propagated into the secret store
+type config struct {
+	// Item is the item in the secret store that holds the credentials
+	Item string `json:"item"`
+	// ServiceAccounts are the service accounts whose credentials are propagated for every build farm cluster
+	ServiceAccounts []serviceAccount `json:"service_accounts"`
+}
+
+type serviceAccount struct {
+	Name string `json:"name"`
+	// Namespace of the service account, defaults to "ci"
+	Namespace string `json:"namespace,omitempty"`
+	// Lifetime of the generated tokens, defaults to 90 days
+	Lifetime *metav1.Duration `json:"lifetime,omitempty"`
+}
+
+func (sa serviceAccount) lifetime() time.Duration {
+	if sa.Lifetime == nil {
+		return defaultTokenLifetime
+	}
+	return sa.Lifetime.Duration
+}
+
+func loadConfig(data []byte, refreshBefore time.Duration) (*config, error) {
+	var c config
+	if err := yaml.UnmarshalStrict(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	var errs []error
+	if c.Item == "" {
+		errs = append(errs, errors.New("item must be set"))
+	}
+	seen := sets.New[string]()
+	for i := range c.ServiceAccounts {
+		sa := &c.ServiceAccounts[i]
+		if sa.Name == "" {
+			errs = append(errs, fmt.Errorf("service_accounts[%d]: name must be set", i))
+			continue
+		}
+		if strings.Contains(sa.Name, ".") {
+			errs = append(errs, fmt.Errorf("service_accounts[%d]: name %s must not contain dots", i, sa.Name))
+		}
+		if seen.Has(sa.Name) {
+			errs = append(errs, fmt.Errorf("service_accounts[%d]: service account %s is declared more than once", i, sa.Name))
+		}
+		seen.Insert(sa.Name)
+		if sa.Namespace == "" {
+			sa.Namespace = defaultNamespace
+		}
+		if sa.lifetime() <= refreshBefore {
+			errs = append(errs, fmt.Errorf("service_accounts[%d]: lifetime %s must be longer than the refresh period %s", i, sa.lifetime(), refreshBefore))
+		}
+	}
+	return &c, utilerrors.NewAggregate(errs)
+}
+
+// serviceAccountField is the field in the secret store that holds a credential for a service
+// account on a cluster. The format is shared with the secrets cluster-init sets up.
+func serviceAccountField(serviceAccount, cluster, suffix string) string {
+	return fmt.Sprintf("sa.%s.%s.%s", serviceAccount, cluster, suffix)
+}
+
+// clusterForField determines the cluster a field in the secret store holds a credential of
+// the service account for
+func clusterForField(field, serviceAccount string) (string, bool) {
+	prefix := fmt.Sprintf("sa.%s.", serviceAccount)
+	if !strings.HasPrefix(field, prefix) {
+		return "", false
+	}
+	remainder := strings.TrimPrefix(field, prefix)
+	for _, suffix := range []string{kubeconfigSuffix, tokenSuffix} {
+		if !strings.HasSuffix(remainder, "."+suffix) {
+			continue
+		}
+		if cluster := strings.TrimSuffix(remainder, "."+suffix); cluster != "" && !strings.Contains(cluster, ".") {
+			return cluster, true
+		}
+	}
+	return "", false
+}
+
+// tokenExpiry reads the expiration time from the claims of a service account token
+func tokenExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode the token claims: %w", err)
+	}
+	var claims struct {
+		Expiry int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("failed to unmarshal the token claims: %w", err)
+	}
+	if claims.Expiry == 0 {
+		return time.Time{}, errors.New("token does not expire")
+	}
+	return time.Unix(claims.Expiry, 0), nil
+}
+
+// cluster is a build farm cluster for which credentials are generated
+type cluster struct {
+	client kubernetes.Interface
+	server string
+	caData []byte
+}
+
+func kubeconfigFor(clusterName, namespace, token string, c cluster) ([]byte, error) {
+	return clientcmd.Write(clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{
+			clusterName: {Server: c.server, CertificateAuthorityData: c.caData},
+		},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			clusterName: {Token: token},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			clusterName: {Cluster: clusterName, AuthInfo: clusterName, Namespace: namespace},
+		},
+		CurrentContext: clusterName,
+	})
+}
+
+type propagator struct {
+	store    secrets.VaultClient
+	path     string
+	clusters map[string]cluster
+	// revoke determines if the credentials of clusters that are no longer part of the build farm are removed
+	revoke        bool
+	refreshBefore time.Duration
+	dryRun        bool
+	now           func() time.Time
+}
+
+// propagate generates the credentials for all service accounts on all clusters that are missing
+// or about to expire and removes the credentials of clusters that were removed from the build farm
+func (p *propagator) propagate(ctx context.Context, serviceAccounts []serviceAccount) error {
+	data := map[string]string{}
+	current, err := p.store.GetKV(p.path)
+	if err != nil && !vaultclient.IsNotFound(err) {
+		return fmt.Errorf("failed to get item %s: %w", p.path, err)
+	}
+	if current != nil {
+		for field, value := range current.Data {
+			data[field] = value
+		}
+	}
+
+	var changed bool
+	var errs []error
+	for _, sa := range serviceAccounts {
+		for _, clusterName := range sets.List(sets.KeySet(p.clusters)) {
+			logger := logrus.WithFields(logrus.Fields{"serviceaccount": sa.Name, "cluster": clusterName})
+			tokenField := serviceAccountField(sa.Name, clusterName, tokenSuffix)
+			if expiry, err := tokenExpiry(data[tokenField]); err == nil && expiry.Sub(p.now()) > p.refreshBefore {
+				logger.WithField("expiry", expiry).Debug("Credentials are still valid")
+				continue
+			}
+			if p.dryRun {
+				logger.Info("Running in dry-run mode, not generating new credentials")
+				continue
+			}
+			token, kubeconfig, err := p.generate(ctx, clusterName, sa)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to generate credentials for service account %s on cluster %s: %w", sa.Name, clusterName, err))
+				continue
+			}
+			logger.Info("Propagating new credentials")
+			data[tokenField] = token
+			data[serviceAccountField(sa.Name, clusterName, kubeconfigSuffix)] = string(kubeconfig)
+			changed = true
+		}
+
+		if !p.revoke {
+			continue
+		}
+		for _, field := range sets.List(sets.KeySet(data)) {
+			if clusterName, ok := clusterForField(field, sa.Name); ok {
+				if _, exists := p.clusters[clusterName]; !exists {
+					logrus.WithFields(logrus.Fields{"serviceaccount": sa.Name, "cluster": clusterName, "field": field}).Info("Revoking credentials of removed cluster")
+					delete(data, field)
+					changed = true
+				}
+			}
+		}
+	}
+
+	if !changed {
+		return utilerrors.NewAggregate(errs)
+	}
+	if p.dryRun {
+		logrus.WithField("item", p.path).Info("Running in dry-run mode, not updating the secret store")
+	} else if err := p.store.UpsertKV(p.path, data); err != nil {
+		errs = append(errs, fmt.Errorf("failed to update item %s: %w", p.path, err))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (p *propagator) generate(ctx context.Context, clusterName string, sa serviceAccount) (string, []byte, error) {
+	c := p.clusters[clusterName]
+	expirationSeconds := int64(sa.lifetime().Seconds())
+	request := &authenticationv1.TokenRequest{Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expirationSeconds}}
+	response, err := c.client.CoreV1().ServiceAccounts(sa.Namespace).CreateToken(ctx, sa.Name, request, metav1.CreateOptions{})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to request token: %w", err)
+	}
+	kubeconfig, err := kubeconfigFor(clusterName, sa.Namespace, response.Status.Token, c)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to construct kubeconfig: %w", err)
+	}
+	return response.Status.Token, kubeconfig, nil
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/vault/api"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	coretesting "k8s.io/client-go/testing"
+
+	"github.com/openshift/ci-tools/pkg/testhelper"
+	"github.com/openshift/ci-tools/pkg/vaultclient"
+)
+
+var now = time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func tokenExpiringAt(expiry time.Time) string {
+	claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, expiry.Unix())))
+	return "header." + claims + ".signature"
+}
+
+func TestLoadConfig(t *testing.T) {
+	testCases := []struct {
+		name          string
+		data          string
+		expected      *config
+		expectedError error
+	}{
+		{
+			name: "namespace is defaulted",
+			data: "item: build_farm\nservice_accounts:\n- name: config-updater\n- name: ci-chat-bot\n  namespace: ci-chat-bot\n  lifetime: 1440h\n",
+			expected: &config{Item: "build_farm", ServiceAccounts: []serviceAccount{
+				{Name: "config-updater", Namespace: "ci"},
+				{Name: "ci-chat-bot", Namespace: "ci-chat-bot", Lifetime: &metav1.Duration{Duration: 1440 * time.Hour}},
+			}},
+		},
+		{
+			name: "invalid config",
+			data: "service_accounts:\n- name: config-updater\n  lifetime: 24h\n- name: config.updater\n- name: config.updater\n- namespace: ci\n",
+			expected: &config{ServiceAccounts: []serviceAccount{
+				{Name: "config-updater", Namespace: "ci", Lifetime: &metav1.Duration{Duration: 24 * time.Hour}},
+				{Name: "config.updater", Namespace: "ci"},
+				{Name: "config.updater", Namespace: "ci"},
+				{Namespace: "ci"},
+			}},
+			expectedError: errors.New("[item must be set, service_accounts[0]: lifetime 24h0m0s must be longer than the refresh period 720h0m0s, service_accounts[1]: name config.updater must not contain dots, service_accounts[2]: name config.updater must not contain dots, service_accounts[2]: service account config.updater is declared more than once, service_accounts[3]: name must be set]"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := loadConfig([]byte(tc.data), 30*24*time.Hour)
+			if diff := cmp.Diff(tc.expectedError, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Fatalf("error differs from expected: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("config differs from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestClusterForField(t *testing.T) {
+	testCases := []struct {
+		field           string
+		expectedCluster string
+		expectedOK      bool
+	}{
+		{field: "sa.config-updater.build01.config", expectedCluster: "build01", expectedOK: true},
+		{field: "sa.config-updater.build01.token.txt", expectedCluster: "build01", expectedOK: true},
+		{field: "sa.config-updater-2.build01.config"},
+		{field: "sa.config-updater.config"},
+		{field: "sa.config-updater.build01.extra.config"},
+		{field: "sa.config-updater.build01.kubeconfig"},
+		{field: "token_image-puller_build01_reg_auth_value.txt"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.field, func(t *testing.T) {
+			cluster, ok := clusterForField(tc.field, "config-updater")
+			if cluster != tc.expectedCluster || ok != tc.expectedOK {
+				t.Errorf("expected (%q, %t), got (%q, %t)", tc.expectedCluster, tc.expectedOK, cluster, ok)
+			}
+		})
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	testCases := []struct {
+		name          string
+		token         string
+		expected      time.Time
+		expectedError error
+	}{
+		{
+			name:     "expiry is read from the claims",
+			token:    tokenExpiringAt(now),
+			expected: now,
+		},
+		{
+			name:          "not a JWT",
+			token:         "opaque",
+			expectedError: errors.New("token is not a JWT"),
+		},
+		{
+			name:          "token without expiry",
+			token:         "header." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"system:serviceaccount:ci:config-updater"}`)) + ".signature",
+			expectedError: errors.New("token does not expire"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := tokenExpiry(tc.token)
+			if diff := cmp.Diff(tc.expectedError, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Fatalf("error differs from expected: %s", diff)
+			}
+			if !actual.Equal(tc.expected) {
+				t.Errorf("expected expiry %s, got %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+type fakeStore struct {
+	items   map[string]map[string]string
+	upserts int
+}
+
+func (f *fakeStore) GetKV(path string) (*vaultclient.KVData, error) {
+	data, ok := f.items[path]
+	if !ok {
+		return nil, &api.ResponseError{StatusCode: http.StatusNotFound}
+	}
+	return &vaultclient.KVData{Data: data}, nil
+}
+
+func (f *fakeStore) ListKVRecursively(string) ([]string, error) {
+	return nil, nil
+}
+
+func (f *fakeStore) UpsertKV(path string, data map[string]string) error {
+	f.upserts++
+	f.items[path] = data
+	return nil
+}
+
+func fakeCluster(token string) cluster {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "serviceaccounts", func(action coretesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+		return true, &authenticationv1.TokenRequest{Status: authenticationv1.TokenRequestStatus{Token: token}}, nil
+	})
+	return cluster{client: client, server: "https://api.build01.ci.devcluster.openshift.com:6443"}
+}
+
+func TestPropagate(t *testing.T) {
+	valid := tokenExpiringAt(now.Add(60 * 24 * time.Hour))
+	expiring := tokenExpiringAt(now.Add(24 * time.Hour))
+	fresh := tokenExpiringAt(now.Add(90 * 24 * time.Hour))
+	kubeconfig, err := kubeconfigFor("build01", "ci", fresh, fakeCluster(fresh))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name            string
+		items           map[string]map[string]string
+		revoke          bool
+		dryRun          bool
+		expected        map[string]map[string]string
+		expectedUpserts int
+	}{
+		{
+			name:  "missing credentials are generated",
+			items: map[string]map[string]string{},
+			expected: map[string]map[string]string{"prefix/build_farm": {
+				"sa.config-updater.build01.token.txt": fresh,
+				"sa.config-updater.build01.config":    string(kubeconfig),
+			}},
+			expectedUpserts: 1,
+		},
+		{
+			name: "valid credentials are kept",
+			items: map[string]map[string]string{"prefix/build_farm": {
+				"sa.config-updater.build01.token.txt": valid,
+				"sa.config-updater.build01.config":    "old",
+			}},
+			expected: map[string]map[string]string{"prefix/build_farm": {
+				"sa.config-updater.build01.token.txt": valid,
+				"sa.config-updater.build01.config":    "old",
+			}},
+		},
+		{
+			name: "expiring credentials are refreshed and other fields kept",
+			items: map[string]map[string]string{"prefix/build_farm": {
+				"sa.config-updater.build01.token.txt": expiring,
+				"sa.config-updater.build01.config":    "old",
+				"unrelated":                           "value",
+			}},
+			expected: map[string]map[string]string{"prefix/build_farm": {
+				"sa.config-updater.build01.token.txt": fresh,
+				"sa.config-updater.build01.config":    string(kubeconfig),
+				"unrelated":                           "value",
+			}},
+			expectedUpserts: 1,
+		},
+		{
+			name: "credentials of removed clusters are revoked",
+			items: map[string]map[string]string{"prefix/build_farm": {
+				"sa.config-updater.build01.token.txt": valid,
+				"sa.config-updater.build01.config":    "old",
+				"sa.config-updater.build99.token.txt": valid,
+				"sa.config-updater.build99.config":    "old",
+				"sa.other.build99.config":             "old",
+			}},
+			revoke: true,
+			expected: map[string]map[string]string{"prefix/build_farm": {
+				"sa.config-updater.build01.token.txt": valid,
+				"sa.config-updater.build01.config":    "old",
+				"sa.other.build99.config":             "old",
+			}},
+			expectedUpserts: 1,
+		},
+		{
+			name: "credentials of removed clusters are kept without revoking",
+			items: map[string]map[string]string{"prefix/build_farm": {
+				"sa.config-updater.build01.token.txt": valid,
+				"sa.config-updater.build99.token.txt": valid,
+			}},
+			expected: map[string]map[string]string{"prefix/build_farm": {
+				"sa.config-updater.build01.token.txt": valid,
+				"sa.config-updater.build99.token.txt": valid,
+			}},
+		},
+		{
+			name: "dry-run changes nothing",
+			items: map[string]map[string]string{"prefix/build_farm": {
+				"sa.config-updater.build01.token.txt": expiring,
+				"sa.config-updater.build99.token.txt": valid,
+			}},
+			revoke: true,
+			dryRun: true,
+			expected: map[string]map[string]string{"prefix/build_farm": {
+				"sa.config-updater.build01.token.txt": expiring,
+				"sa.config-updater.build99.token.txt": valid,
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &fakeStore{items: tc.items}
+			p := &propagator{
+				store:         store,
+				path:          "prefix/build_farm",
+				clusters:      map[string]cluster{"build01": fakeCluster(fresh)},
+				revoke:        tc.revoke,
+				refreshBefore: 30 * 24 * time.Hour,
+				dryRun:        tc.dryRun,
+				now:           func() time.Time { return now },
+			}
+			if err := p.propagate(context.Background(), []serviceAccount{{Name: "config-updater", Namespace: "ci"}}); err != nil {
+				t.Fatalf("propagate failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, store.items); diff != "" {
+				t.Errorf("secret store differs from expected: %s", diff)
+			}
+			if store.upserts != tc.expectedUpserts {
+				t.Errorf("expected %d updates of the secret store, got %d", tc.expectedUpserts, store.upserts)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/test-infra/prow/github"
+	"sigs.k8s.io/yaml"
+)
+
+// requiredAppsConfig lists the GitHub Apps that must be installed on every
+// checked repo
+type requiredAppsConfig struct {
+	Apps []requiredApp `json:"apps"`
+}
+
+type requiredApp struct {
+	// Slug is the URL-friendly name of the app
+	Slug string `json:"slug"`
+	// Permissions maps the permissions the installation must be granted, named as
+	// in the GitHub API (e.g. `pull_requests`), to the minimal access level
+	Permissions map[string]string `json:"permissions,omitempty"`
+}
+
+var accessLevels = map[string]int{"read": 1, "write": 2, "admin": 3}
+
+func loadRequiredAppsConfig(path string) (*requiredAppsConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var config requiredAppsConfig
+	if err := yaml.UnmarshalStrict(raw, &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", path, err)
+	}
+	for _, app := range config.Apps {
+		if app.Slug == "" {
+			return nil, fmt.Errorf("%s: app slug must not be empty", path)
+		}
+		for permission, level := range app.Permissions {
+			if _, ok := accessLevels[level]; !ok {
+				return nil, fmt.Errorf("%s: app %s: permission %s has invalid level %q, must be one of read, write, admin", path, app.Slug, permission, level)
+			}
+		}
+	}
+	return &config, nil
+}
+
+// appReport is the result of checking a required app on a repo
+type appReport struct {
+	Slug      string `json:"slug"`
+	Installed bool   `json:"installed"`
+	// MissingPermissions maps the permissions the installation lacks to the
+	// required access level
+	MissingPermissions map[string]string `json:"missing_permissions,omitempty"`
+	// RepoNotSelected is set when the app is installed for selected repositories
+	// only and the repo is not among them
+	RepoNotSelected bool `json:"repo_not_selected,omitempty"`
+}
+
+func (r appReport) failing() bool {
+	return !r.Installed || len(r.MissingPermissions) > 0 || r.RepoNotSelected
+}
+
+// repoReport is the machine-readable result for a single repo
+type repoReport struct {
+	Repo    string      `json:"repo"`
+	Failing bool        `json:"failing"`
+	Apps    []appReport `json:"apps,omitempty"`
+}
+
+// fleetReport is the machine-readable result for all checked repos
+type fleetReport struct {
+	Repos []repoReport `json:"repos"`
+}
+
+func (r *fleetReport) failing() []string {
+	var failing []string
+	for _, repo := range r.Repos {
+		if repo.Failing {
+			failing = append(failing, repo.Repo)
+		}
+	}
+	return failing
+}
+
+func (r *fleetReport) write(path string) error {
+	raw, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		return fmt.Errorf("failed to write report to %s: %w", path, err)
+	}
+	return nil
+}
+
+// missingPermissions compares the permissions granted to an installation with
+// the required ones
+func missingPermissions(granted github.InstallationPermissions, required map[string]string) (map[string]string, error) {
+	raw, err := json.Marshal(granted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal permissions: %w", err)
+	}
+	grantedByName := map[string]string{}
+	if err := json.Unmarshal(raw, &grantedByName); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal permissions: %w", err)
+	}
+	missing := map[string]string{}
+	for permission, level := range required {
+		if accessLevels[grantedByName[permission]] < accessLevels[level] {
+			missing[permission] = level
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+	return missing, nil
+}
+
+// installationReposLister lists the repos, as org/repo, that an app installation
+// for selected repositories has access to
+type installationReposLister func(installationID int64) (sets.Set[string], error)
+
+const installationReposPerPage = 100
+
+// installationReposClient lists the repos of app installations through the
+// GitHub API. Apps cannot list the repos of other apps' installations, so this
+// requires the token of a user with access to the installations, e.g. an
+// organization owner.
+type installationReposClient struct {
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+func (c *installationReposClient) list(installationID int64) (sets.Set[string], error) {
+	repos := sets.New[string]()
+	for page := 1; ; page++ {
+		names, err := c.listPage(installationID, page)
+		if err != nil {
+			return nil, err
+		}
+		repos.Insert(names...)
+		if len(names) < installationReposPerPage {
+			return repos, nil
+		}
+	}
+}
+
+func (c *installationReposClient) listPage(installationID int64, page int) ([]string, error) {
+	url := fmt.Sprintf("%s/user/installations/%d/repositories?per_page=%d&page=%d", strings.TrimSuffix(c.endpoint, "/"), installationID, installationReposPerPage, page)
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	request.Header.Set("Accept", "application/vnd.github+json")
+	request.Header.Set("Authorization", "Bearer "+c.token)
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories of installation %d: %w", installationID, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(response.Body)
+		return nil, fmt.Errorf("failed to list repositories of installation %d: status %d: %s", installationID, response.StatusCode, string(body))
+	}
+	var result struct {
+		Repositories []struct {
+			FullName string `json:"full_name"`
+		} `json:"repositories"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode repositories of installation %d: %w", installationID, err)
+	}
+	var names []string
+	for _, repo := range result.Repositories {
+		names = append(names, repo.FullName)
+	}
+	return names, nil
+}
+
+// buildReport checks the required apps on every repo and combines the results
+// with the repos that already failed the other checks
+func buildReport(repos []string, failing []string, ignore sets.Set[string], apps []requiredApp, client automationClient, listRepos installationReposLister, logger *logrus.Entry) (*fleetReport, error) {
+	failingRepos := sets.New[string](failing...)
+	installationsByOrg := map[string][]github.AppInstallation{}
+	reposByInstallation := map[int64]sets.Set[string]{}
+	cachedListRepos := func(installationID int64) (sets.Set[string], error) {
+		if repos, cached := reposByInstallation[installationID]; cached {
+			return repos, nil
+		}
+		repos, err := listRepos(installationID)
+		if err != nil {
+			return nil, err
+		}
+		reposByInstallation[installationID] = repos
+		return repos, nil
+	}
+	report := &fleetReport{}
+	sorted := sets.List(sets.New[string](repos...))
+	for _, orgRepo := range sorted {
+		org, _, _ := strings.Cut(orgRepo, "/")
+		if ignore.Has(org) || ignore.Has(orgRepo) {
+			continue
+		}
+		repoLogger := logger.WithField("repo", orgRepo)
+		result := repoReport{Repo: orgRepo, Failing: failingRepos.Has(orgRepo)}
+		if len(apps) > 0 {
+			installations, cached := installationsByOrg[org]
+			if !cached {
+				var err error
+				if installations, err = client.ListAppInstallationsForOrg(org); err != nil {
+					return nil, fmt.Errorf("unable to list app installations for %s: %w", org, err)
+				}
+				installationsByOrg[org] = installations
+			}
+			for _, app := range apps {
+				appResult, err := checkApp(app, orgRepo, installations, cachedListRepos)
+				if err != nil {
+					return nil, fmt.Errorf("unable to check app %s on %s: %w", app.Slug, orgRepo, err)
+				}
+				if appResult.failing() {
+					result.Failing = true
+					repoLogger.WithField("app", app.Slug).WithField("installed", appResult.Installed).WithField("missing_permissions", appResult.MissingPermissions).WithField("repo_not_selected", appResult.RepoNotSelected).Error("required app is not installed with the required permissions")
+				}
+				result.Apps = append(result.Apps, appResult)
+			}
+		}
+		report.Repos = append(report.Repos, result)
+	}
+	sort.Slice(report.Repos, func(i, j int) bool { return report.Repos[i].Repo < report.Repos[j].Repo })
+	return report, nil
+}
+
+func checkApp(app requiredApp, orgRepo string, installations []github.AppInstallation, listRepos installationReposLister) (appReport, error) {
+	result := appReport{Slug: app.Slug}
+	for _, installation := range installations {
+		if installation.AppSlug != app.Slug {
+			continue
+		}
+		result.Installed = true
+		missing, err := missingPermissions(installation.Permissions, app.Permissions)
+		if err != nil {
+			return result, err
+		}
+		result.MissingPermissions = missing
+		if installation.RepositorySelection != "all" {
+			repos, err := listRepos(installation.ID)
+			if err != nil {
+				return result, err
+			}
+			result.RepoNotSelected = !repos.Has(orgRepo)
+		}
+		break
+	}
+	return result, nil
+}
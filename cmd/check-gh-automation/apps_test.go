@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/test-infra/prow/github"
+
+	"github.com/openshift/ci-tools/pkg/testhelper"
+)
+
+func TestBuildReport(t *testing.T) {
+	client := fakeAutomationClient{
+		installationsByOrg: map[string][]github.AppInstallation{
+			"org-1": {
+				{AppSlug: "openshift-ci", RepositorySelection: "all", Permissions: github.InstallationPermissions{PullRequests: "write", Contents: "read"}},
+				{ID: 2, AppSlug: "other-app", RepositorySelection: "selected", Permissions: github.InstallationPermissions{Checks: "write"}},
+			},
+			"org-2": {
+				{AppSlug: "openshift-ci", RepositorySelection: "all", Permissions: github.InstallationPermissions{PullRequests: "read"}},
+			},
+		},
+	}
+	listRepos := func(installationID int64) (sets.Set[string], error) {
+		if installationID != 2 {
+			return nil, fmt.Errorf("unexpected installation %d", installationID)
+		}
+		return sets.New[string]("org-1/repo-a"), nil
+	}
+	apps := []requiredApp{
+		{Slug: "openshift-ci", Permissions: map[string]string{"pull_requests": "write", "contents": "read"}},
+		{Slug: "other-app", Permissions: map[string]string{"checks": "read"}},
+	}
+
+	testCases := []struct {
+		name        string
+		repos       []string
+		failing     []string
+		ignore      sets.Set[string]
+		apps        []requiredApp
+		expected    *fleetReport
+		expectedErr error
+	}{
+		{
+			name:    "no required apps only reports the other checks",
+			repos:   []string{"org-2/repo-b", "org-1/repo-a"},
+			failing: []string{"org-2/repo-b"},
+			expected: &fleetReport{Repos: []repoReport{
+				{Repo: "org-1/repo-a"},
+				{Repo: "org-2/repo-b", Failing: true},
+			}},
+		},
+		{
+			name:  "apps installed with sufficient permissions",
+			repos: []string{"org-1/repo-a"},
+			apps:  apps,
+			expected: &fleetReport{Repos: []repoReport{{Repo: "org-1/repo-a", Apps: []appReport{
+				{Slug: "openshift-ci", Installed: true},
+				{Slug: "other-app", Installed: true},
+			}}}},
+		},
+		{
+			name:  "app installed for selected repositories that do not include the repo",
+			repos: []string{"org-1/repo-a", "org-1/repo-c"},
+			apps:  apps,
+			expected: &fleetReport{Repos: []repoReport{
+				{Repo: "org-1/repo-a", Apps: []appReport{
+					{Slug: "openshift-ci", Installed: true},
+					{Slug: "other-app", Installed: true},
+				}},
+				{Repo: "org-1/repo-c", Failing: true, Apps: []appReport{
+					{Slug: "openshift-ci", Installed: true},
+					{Slug: "other-app", Installed: true, RepoNotSelected: true},
+				}},
+			}},
+		},
+		{
+			name:   "missing app and insufficient permissions",
+			repos:  []string{"org-2/repo-b", "org-3/ignored"},
+			ignore: sets.New[string]("org-3"),
+			apps:   apps,
+			expected: &fleetReport{Repos: []repoReport{{Repo: "org-2/repo-b", Failing: true, Apps: []appReport{
+				{Slug: "openshift-ci", Installed: true, MissingPermissions: map[string]string{"pull_requests": "write", "contents": "read"}},
+				{Slug: "other-app"},
+			}}}},
+		},
+		{
+			name:        "listing installations fails",
+			repos:       []string{"fake/repo"},
+			apps:        apps,
+			expectedErr: errors.New("unable to list app installations for fake: intentional error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := buildReport(tc.repos, tc.failing, tc.ignore, tc.apps, client, listRepos, logrus.NewEntry(logrus.New()))
+			if diff := cmp.Diff(tc.expectedErr, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Fatalf("error doesn't match expected, diff: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expected, report); diff != "" {
+				t.Errorf("report doesn't match expected, diff: %s", diff)
+			}
+		})
+	}
+}
+
+func TestInstallationReposClient(t *testing.T) {
+	var repos []string
+	for i := 0; i < installationReposPerPage+1; i++ {
+		repos = append(repos, fmt.Sprintf("org/repo-%d", i))
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user/installations/1/repositories" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		type repo struct {
+			FullName string `json:"full_name"`
+		}
+		var result struct {
+			Repositories []repo `json:"repositories"`
+		}
+		for i := (page - 1) * installationReposPerPage; i < len(repos) && i < page*installationReposPerPage; i++ {
+			result.Repositories = append(result.Repositories, repo{FullName: repos[i]})
+		}
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			t.Errorf("failed to write response: %v", err)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := &installationReposClient{endpoint: server.URL + "/", token: "token", client: server.Client()}
+	actual, err := client.list(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(sets.New[string](repos...), actual); diff != "" {
+		t.Errorf("unexpected repos: %s", diff)
+	}
+	if _, err := client.list(2); err == nil {
+		t.Error("expected an error for an unknown installation")
+	}
+	client.token = "wrong"
+	if _, err := client.list(1); err == nil {
+		t.Error("expected an error for an unauthorized token")
+	}
+}
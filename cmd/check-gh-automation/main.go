@@ -1,16 +1,20 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	prowconfig "k8s.io/test-infra/prow/config"
 	"k8s.io/test-infra/prow/flagutil"
+	"k8s.io/test-infra/prow/github"
 	configflagutil "k8s.io/test-infra/prow/flagutil/config"
 	prowpluginconfig "k8s.io/test-infra/prow/flagutil/plugins"
 	"k8s.io/test-infra/prow/logrusutil"
@@ -39,6 +43,12 @@ type options struct {
 	releaseRepoPath string
 	flagutil.GitHubOptions
 	pluginConfig prowpluginconfig.PluginOptions
+
+	requiredAppsConfigPath     string
+	requiredApps               []requiredApp
+	installationReposTokenPath string
+	installationReposEndpoint  string
+	reportPath                 string
 }
 
 func gatherOptions() options {
@@ -51,6 +61,10 @@ func gatherOptions() options {
 	fs.Var(&o.ignore, "ignore", "Ignore a repo or entire org. Formatted org or org/repo. Can be passed multiple times.")
 	fs.Var(&o.repos, "repo", "Specifically check only an org/repo. Can be passed multiple times.")
 	fs.StringVar(&o.releaseRepoPath, "candidate-path", "", "Path to a openshift/release working copy with a revision to be tested")
+	fs.StringVar(&o.requiredAppsConfigPath, "required-apps-config", "", "Path to a config file listing the GitHub Apps, and their permissions, that must be installed on every checked repo")
+	fs.StringVar(&o.installationReposTokenPath, "installation-repos-token-path", "", "Path to a user token able to list the repositories of app installations for selected repositories, e.g. of an organization owner. Required with --required-apps-config.")
+	fs.StringVar(&o.installationReposEndpoint, "installation-repos-endpoint", "https://api.github.com", "GitHub API endpoint used to list the repositories of app installations")
+	fs.StringVar(&o.reportPath, "report-path", "", "Path to write a JSON report of the results for every checked repo to")
 	o.pluginConfig.AddFlags(fs)
 
 	o.GitHubOptions.AddFlags(fs)
@@ -71,6 +85,17 @@ func (o *options) validate() error {
 		return fmt.Errorf("app-check-mode of %s not recognized, must be: %s or %s", o.appCheckMode, standard, tide)
 	}
 
+	if o.requiredAppsConfigPath != "" {
+		config, err := loadRequiredAppsConfig(o.requiredAppsConfigPath)
+		if err != nil {
+			return fmt.Errorf("error loading required apps config: %w", err)
+		}
+		o.requiredApps = config.Apps
+		if o.installationReposTokenPath == "" {
+			return errors.New("--installation-repos-token-path is required with --required-apps-config")
+		}
+	}
+
 	return o.GitHubOptions.Validate(true)
 }
 
@@ -79,6 +104,7 @@ type automationClient interface {
 	IsCollaborator(org, repo, user string) (bool, error)
 	IsAppInstalled(org, repo string) (bool, error)
 	HasPermission(org, repo, user string, permissions ...string) (bool, error)
+	ListAppInstallationsForOrg(org string) ([]github.AppInstallation, error)
 }
 
 func main() {
@@ -121,6 +147,26 @@ func main() {
 		logger.Fatalf("error checking repos: %v", err)
 	}
 
+	var listInstallationRepos installationReposLister
+	if o.installationReposTokenPath != "" {
+		token, err := os.ReadFile(o.installationReposTokenPath)
+		if err != nil {
+			logger.Fatalf("error reading installation repos token: %v", err)
+		}
+		reposClient := &installationReposClient{endpoint: o.installationReposEndpoint, token: strings.TrimSpace(string(token)), client: &http.Client{Timeout: time.Minute}}
+		listInstallationRepos = reposClient.list
+	}
+	report, err := buildReport(repos, failing, o.ignore.StringSet(), o.requiredApps, client, listInstallationRepos, logger)
+	if err != nil {
+		logger.Fatalf("error checking required apps: %v", err)
+	}
+	if o.reportPath != "" {
+		if err := report.write(o.reportPath); err != nil {
+			logger.Fatalf("error writing report: %v", err)
+		}
+	}
+	failing = report.failing()
+
 	if len(failing) > 0 {
 		logger.Fatalf("Repo(s) missing github automation: %s", strings.Join(failing, ", "))
 	}
@@ -10,6 +10,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	prowconfig "k8s.io/test-infra/prow/config"
+	"k8s.io/test-infra/prow/github"
 	"k8s.io/test-infra/prow/plugins"
 
 	"github.com/openshift/ci-tools/pkg/testhelper"
@@ -20,6 +21,14 @@ type fakeAutomationClient struct {
 	membersByOrg          map[string][]string
 	reposWithAppInstalled sets.Set[string]
 	permissionsByRepo     map[string]map[string][]string
+	installationsByOrg    map[string][]github.AppInstallation
+}
+
+func (c fakeAutomationClient) ListAppInstallationsForOrg(org string) ([]github.AppInstallation, error) {
+	if org == "fake" {
+		return nil, errors.New("intentional error")
+	}
+	return c.installationsByOrg[org], nil
 }
 
 func newFakePluginConfigAgent() *plugins.ConfigAgent {
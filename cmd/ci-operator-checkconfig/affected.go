@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/config"
+	"github.com/openshift/ci-tools/pkg/registry"
+)
+
+// changeSet holds the ci-operator configurations and the registry components that
+// changed since the base revision, together with the registry components that
+// include the changed ones
+type changeSet struct {
+	configs    sets.Set[string]
+	references sets.Set[string]
+	chains     sets.Set[string]
+	workflows  sets.Set[string]
+	observers  sets.Set[string]
+}
+
+// affects determines whether a configuration needs to be validated: it
+// changed itself or one of its tests uses a changed registry component. A nil
+// changeSet affects all configurations.
+func (c *changeSet) affects(configuration *api.ReleaseBuildConfiguration) bool {
+	if c == nil {
+		return true
+	}
+	if c.configs.Has(configuration.Metadata.RelativePath()) {
+		return true
+	}
+	for _, test := range configuration.Tests {
+		ms := test.MultiStageTestConfiguration
+		if ms == nil {
+			continue
+		}
+		if ms.Workflow != nil && c.workflows.Has(*ms.Workflow) {
+			return true
+		}
+		if ms.Observers != nil && c.observers.HasAny(ms.Observers.Enable...) {
+			return true
+		}
+		for _, steps := range [][]api.TestStep{ms.Pre, ms.Test, ms.Post} {
+			for _, step := range steps {
+				if step.Reference != nil && c.references.Has(*step.Reference) || step.Chain != nil && c.chains.Has(*step.Chain) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (c *changeSet) insert(nodes []registry.Node) {
+	for _, node := range nodes {
+		switch node.Type() {
+		case registry.Reference:
+			c.references.Insert(node.Name())
+		case registry.Chain:
+			c.chains.Insert(node.Name())
+		case registry.Workflow:
+			c.workflows.Insert(node.Name())
+		case registry.Observer:
+			c.observers.Insert(node.Name())
+		}
+	}
+}
+
+// releaseRepoRoot returns the root of the repository holding the ci-operator
+// configuration, which must be laid out like openshift/release
+func releaseRepoRoot(configDir string) (string, error) {
+	out, err := exec.Command("git", "-C", configDir, "rev-parse", "--show-toplevel").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine the repository of %s: %w: %s", configDir, err, string(out))
+	}
+	root := strings.TrimSpace(string(out))
+	absConfigDir, err := filepath.Abs(configDir)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(absConfigDir); err == nil {
+		absConfigDir = resolved
+	}
+	if expected := filepath.Join(root, config.CiopConfigInRepoPath); absConfigDir != expected {
+		return "", fmt.Errorf("the configuration directory must be %s in its repository", config.CiopConfigInRepoPath)
+	}
+	return root, nil
+}
+
+// determineChanges collects the changes made since baseRev in the repository
+// at root. It returns a nil changeSet when every configuration must be
+// validated, which is the case when registry components or cluster profiles
+// were removed or changed in a way that cannot be traced to the configurations.
+func determineChanges(root, baseRev string, graph registry.NodeByName, profilesConfigPath string) (*changeSet, error) {
+	changedConfigs, err := config.GetChangedConfigs(root, baseRev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine changed configurations: %w", err)
+	}
+	registryFiles, err := config.GetChangedRegistryFiles(root, baseRev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine changed registry files: %w", err)
+	}
+	for _, file := range registryFiles {
+		if _, err := os.Stat(filepath.Join(root, config.RegistryPath, file)); os.IsNotExist(err) {
+			logrus.WithField("file", file).Info("A registry file was removed, validating all configurations")
+			return nil, nil
+		}
+	}
+	if profilesConfigPath != "" {
+		changed, err := fileChanged(root, baseRev, profilesConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			logrus.Info("The cluster profiles changed, validating all configurations")
+			return nil, nil
+		}
+	}
+	changedNodes, err := config.GetChangedRegistrySteps(root, baseRev, graph)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine changed registry components: %w", err)
+	}
+
+	c := &changeSet{
+		configs:    sets.New[string](changedConfigs...),
+		references: sets.New[string](),
+		chains:     sets.New[string](),
+		workflows:  sets.New[string](),
+		observers:  sets.New[string](),
+	}
+	c.insert(changedNodes)
+	for _, node := range changedNodes {
+		c.insert(node.Ancestors())
+	}
+	logrus.WithFields(logrus.Fields{
+		"configs":    c.configs.Len(),
+		"references": c.references.Len(),
+		"chains":     c.chains.Len(),
+		"workflows":  c.workflows.Len(),
+		"observers":  c.observers.Len(),
+	}).Info("Validating only configurations affected by the changes")
+	return c, nil
+}
+
+// fileChanged determines whether a file changed since baseRev, files outside
+// of the repository are never considered changed
+func fileChanged(root, baseRev, path string) (bool, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false, err
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return false, nil
+	}
+	out, err := exec.Command("git", "-C", root, "diff", "--name-only", baseRev, "HEAD", "--", rel).CombinedOutput()
+	if err != nil {
+		return false, fmt.Errorf("failed to determine whether %s changed: %w: %s", rel, err, string(out))
+	}
+	return strings.TrimSpace(string(out)) != "", nil
+}
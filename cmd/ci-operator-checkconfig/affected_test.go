@@ -0,0 +1,208 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/registry"
+)
+
+func TestChangeSetAffects(t *testing.T) {
+	ref, chain, workflow, observer := "changed-ref", "changed-chain", "changed-workflow", "changed-observer"
+	other := "other"
+	configWith := func(ms *api.MultiStageTestConfiguration) *api.ReleaseBuildConfiguration {
+		return &api.ReleaseBuildConfiguration{
+			Metadata: api.Metadata{Org: "org", Repo: "repo", Branch: "master"},
+			Tests: []api.TestStepConfiguration{
+				{As: "unit", ContainerTestConfiguration: &api.ContainerTestConfiguration{From: "src"}},
+				{As: "e2e", MultiStageTestConfiguration: ms},
+			},
+		}
+	}
+	changes := &changeSet{
+		configs:    sets.New[string]("org/changed/org-changed-master.yaml"),
+		references: sets.New[string](ref),
+		chains:     sets.New[string](chain),
+		workflows:  sets.New[string](workflow),
+		observers:  sets.New[string](observer),
+	}
+	testCases := []struct {
+		name          string
+		changes       *changeSet
+		configuration *api.ReleaseBuildConfiguration
+		expected      bool
+	}{
+		{
+			name:          "all configurations are affected without a change set",
+			configuration: configWith(nil),
+			expected:      true,
+		},
+		{
+			name:          "changed configuration",
+			changes:       changes,
+			configuration: &api.ReleaseBuildConfiguration{Metadata: api.Metadata{Org: "org", Repo: "changed", Branch: "master"}},
+			expected:      true,
+		},
+		{
+			name:          "unchanged configuration without multi-stage tests",
+			changes:       changes,
+			configuration: configWith(nil),
+		},
+		{
+			name:          "unchanged configuration using unchanged components",
+			changes:       changes,
+			configuration: configWith(&api.MultiStageTestConfiguration{Workflow: &other, Test: []api.TestStep{{Reference: &other}, {Chain: &other}}}),
+		},
+		{
+			name:          "workflow changed",
+			changes:       changes,
+			configuration: configWith(&api.MultiStageTestConfiguration{Workflow: &workflow}),
+			expected:      true,
+		},
+		{
+			name:          "reference in post steps changed",
+			changes:       changes,
+			configuration: configWith(&api.MultiStageTestConfiguration{Post: []api.TestStep{{Reference: &ref}}}),
+			expected:      true,
+		},
+		{
+			name:          "chain in pre steps changed",
+			changes:       changes,
+			configuration: configWith(&api.MultiStageTestConfiguration{Pre: []api.TestStep{{Chain: &chain}}}),
+			expected:      true,
+		},
+		{
+			name:          "enabled observer changed",
+			changes:       changes,
+			configuration: configWith(&api.MultiStageTestConfiguration{Observers: &api.Observers{Enable: []string{observer}}}),
+			expected:      true,
+		},
+		{
+			name:          "disabled observer changed",
+			changes:       changes,
+			configuration: configWith(&api.MultiStageTestConfiguration{Observers: &api.Observers{Disable: []string{observer}}}),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.changes.affects(tc.configuration); actual != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDetermineChanges(t *testing.T) {
+	files := map[string]string{
+		"ci-operator/config/org/repo/org-repo-master.yaml":          "unchanged",
+		"ci-operator/config/org/other/org-other-master.yaml":        "unchanged",
+		"ci-operator/step-registry/step/step-ref.yaml":              "unchanged",
+		"ci-operator/step-registry/step/step-commands.sh":           "unchanged",
+		"ci-operator/step-registry/chain/chain-chain.yaml":          "unchanged",
+		"ci-operator/step-registry/workflow/workflow-workflow.yaml": "unchanged",
+		"ci-operator/step-registry/unused/unused-ref.yaml":          "unchanged",
+		"ci-operator/step-registry/unused/unused-commands.sh":       "unchanged",
+		"cluster/profiles.yaml":                                     "unchanged",
+	}
+	step, chain, workflow, unused := "step", "chain", "workflow", "unused"
+	graph, err := registry.NewGraph(
+		registry.ReferenceByName{step: {}, unused: {}},
+		registry.ChainByName{chain: {As: chain, Steps: []api.TestStep{{Reference: &step}}}},
+		registry.WorkflowByName{workflow: {Test: []api.TestStep{{Chain: &chain}}}},
+		registry.ObserverByName{},
+	)
+	if err != nil {
+		t.Fatalf("failed to build graph: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		change   func(root string) error
+		expected *changeSet
+	}{
+		{
+			name: "configuration and reference changed",
+			change: func(root string) error {
+				for _, file := range []string{"ci-operator/config/org/repo/org-repo-master.yaml", "ci-operator/step-registry/step/step-commands.sh"} {
+					if err := os.WriteFile(filepath.Join(root, file), []byte("changed"), 0644); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			expected: &changeSet{
+				configs:    sets.New[string]("org/repo/org-repo-master.yaml"),
+				references: sets.New[string](step),
+				chains:     sets.New[string](chain),
+				workflows:  sets.New[string](workflow),
+				observers:  sets.New[string](),
+			},
+		},
+		{
+			name: "removed reference requires validating everything",
+			change: func(root string) error {
+				return os.Remove(filepath.Join(root, "ci-operator/step-registry/unused/unused-ref.yaml"))
+			},
+		},
+		{
+			name: "changed cluster profiles require validating everything",
+			change: func(root string) error {
+				return os.WriteFile(filepath.Join(root, "cluster/profiles.yaml"), []byte("changed"), 0644)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			for file, content := range files {
+				if err := os.MkdirAll(filepath.Dir(filepath.Join(root, file)), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(root, file), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			gitCommit(t, root, true)
+			if err := tc.change(root); err != nil {
+				t.Fatal(err)
+			}
+			gitCommit(t, root, false)
+
+			if _, err := releaseRepoRoot(filepath.Join(root, "ci-operator/config")); err != nil {
+				t.Fatalf("unexpected error determining the repository root: %v", err)
+			}
+			changes, err := determineChanges(root, "HEAD~", graph, filepath.Join(root, "cluster/profiles.yaml"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, changes, cmp.AllowUnexported(changeSet{})); diff != "" {
+				t.Errorf("changes differ from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func gitCommit(t *testing.T, dir string, initialize bool) {
+	t.Helper()
+	var commands [][]string
+	if initialize {
+		commands = append(commands, []string{"init", "--quiet"})
+	}
+	commands = append(commands,
+		[]string{"add", "--all"},
+		[]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "--allow-empty", "--message", "commit"},
+	)
+	for _, args := range commands {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, string(out))
+		}
+	}
+}
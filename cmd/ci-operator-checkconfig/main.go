@@ -31,24 +31,38 @@ type options struct {
 	config.Options
 
 	resolver        registry.Resolver
+	graph           registry.NodeByName
 	ciOPConfigAgent agents.ConfigAgent
 	clusterProfiles api.ClusterProfilesList
+	concurrency     int
+	// changes restricts the validation to affected configurations, all are validated when nil
+	changes *changeSet
 }
 
 func (o *options) parse() error {
 	var registryDir string
 	var profilesConfigPath string
+	var baseRev string
 
 	fs := flag.NewFlagSet("", flag.ExitOnError)
 
 	fs.StringVar(&registryDir, "registry", "", "Path to the step registry directory")
 	fs.StringVar(&profilesConfigPath, "cluster-profiles-config", "", "Path to the cluster profile config file")
+	fs.StringVar(&baseRev, "base-ref", "", "If set, only validate the configurations affected by the changes made since this revision in the repository holding --config-dir")
+	fs.IntVar(&o.concurrency, "concurrency", 0, "Number of configurations validated in parallel, defaults to the number of CPUs")
 	o.Options.Bind(fs)
 
 	if err := fs.Parse(os.Args[1:]); err != nil {
 		return fmt.Errorf("failed to parse flags: %w", err)
 	}
 
+	if baseRev != "" && registryDir == "" {
+		return errors.New("--base-ref requires --registry")
+	}
+	if o.concurrency < 0 {
+		return errors.New("--concurrency must not be negative")
+	}
+
 	if err := o.loadResolver(registryDir); err != nil {
 		return fmt.Errorf("failed to load registry: %w", err)
 	}
@@ -65,6 +79,16 @@ func (o *options) parse() error {
 	}
 	o.ciOPConfigAgent = ciOPConfigAgent
 
+	if baseRev != "" {
+		root, err := releaseRepoRoot(o.ConfigDir)
+		if err != nil {
+			return err
+		}
+		if o.changes, err = determineChanges(root, baseRev, o.graph, profilesConfigPath); err != nil {
+			return err
+		}
+	}
+
 	if err := o.Options.Validate(); err != nil {
 		return fmt.Errorf("failed to validate config options: %w", err)
 	}
@@ -92,6 +116,11 @@ func (o *options) validate() (ret []error) {
 	map_ := func() error {
 		validator := validation.NewValidator(o.clusterProfiles)
 		for c := range inputCh {
+			if !o.changes.affects(&c) {
+				// promoted tags are still collected, as they are validated across all configurations
+				sendPromotedTags(outputCh, c)
+				continue
+			}
 			if err := o.validateConfiguration(&validator, outputCh, c); err != nil {
 				errCh <- fmt.Errorf("failed to validate configuration %s: %w", c.Metadata.RelativePath(), err)
 			}
@@ -106,7 +135,7 @@ func (o *options) validate() (ret []error) {
 		return nil
 	}
 	done := func() { close(outputCh) }
-	if err := util.ProduceMapReduce(0, produce, map_, reduce, done, errCh); err != nil {
+	if err := util.ProduceMapReduce(o.concurrency, produce, map_, reduce, done, errCh); err != nil {
 		ret = append(ret, err)
 	}
 	return append(ret, validateTags(seen)...)
@@ -121,6 +150,9 @@ func (o *options) loadResolver(path string) error {
 		return err
 	}
 	o.resolver = registry.NewResolver(refs, chains, workflows, observers)
+	if o.graph, err = registry.NewGraph(refs, chains, workflows, observers); err != nil {
+		return fmt.Errorf("failed to build the registry graph: %w", err)
+	}
 	return nil
 }
 
@@ -143,15 +175,19 @@ func (o *options) validateConfiguration(
 	if err := validation.IsValidGraphConfiguration(graphConf.Steps); err != nil {
 		return err
 	}
-	for _, tag := range release.PromotedTags(&configuration) {
-		seenCh <- promotedTag{tag, &configuration.Metadata}
-	}
+	sendPromotedTags(seenCh, configuration)
 	if configuration.PromotionConfiguration != nil && configuration.PromotionConfiguration.RegistryOverride != "" {
 		return errors.New("setting promotion.registry_override is not allowed")
 	}
 	return nil
 }
 
+func sendPromotedTags(seenCh chan<- promotedTag, configuration api.ReleaseBuildConfiguration) {
+	for _, tag := range release.PromotedTags(&configuration) {
+		seenCh <- promotedTag{tag, &configuration.Metadata}
+	}
+}
+
 func validateTags(seen tagSet) []error {
 	var dupes []error
 	for tag, infos := range seen {
@@ -205,6 +205,8 @@ func main() {
 		l("resolve"),
 		l("configGeneration"),
 		l("registryGeneration"),
+		l("registryUsage"),
+		l("configAtRevision"),
 	))
 
 	uisimplifier := simplifypath.NewSimplifier(l("", // shadow element mimicing the root
@@ -218,14 +220,20 @@ func main() {
 	handler := metrics.TraceHandler(simplifier, configresolverMetrics.HTTPRequestDuration, configresolverMetrics.HTTPResponseSize)
 	uihandler := metrics.TraceHandler(uisimplifier, configresolverMetrics.HTTPRequestDuration, configresolverMetrics.HTTPResponseSize)
 	// add handler func for incorrect paths as well; can help with identifying errors/404s caused by incorrect paths
+	usageTracker := registryserver.NewUsageTracker(configAgent, registryAgent)
 	http.HandleFunc("/", handler(http.HandlerFunc(http.NotFound)).ServeHTTP)
-	http.HandleFunc("/config", handler(registryserver.ResolveConfig(configAgent, registryAgent, configresolverMetrics)).ServeHTTP)
+	http.HandleFunc("/config", handler(registryserver.ResolveConfig(configAgent, usageTracker.Resolver(registryAgent), configresolverMetrics)).ServeHTTP)
 	//TODO(sgoeddel): this is deprecated, mergeConfigsWithInjectedTest should be used instead
-	http.HandleFunc("/configWithInjectedTest", handler(registryserver.ResolveConfigWithInjectedTest(configAgent, registryAgent, configresolverMetrics)).ServeHTTP)
-	http.HandleFunc("/mergeConfigsWithInjectedTest", handler(registryserver.ResolveAndMergeConfigsAndInjectTest(configAgent, registryAgent, configresolverMetrics)).ServeHTTP)
+	http.HandleFunc("/configWithInjectedTest", handler(registryserver.ResolveConfigWithInjectedTest(configAgent, usageTracker.Resolver(registryAgent), configresolverMetrics)).ServeHTTP)
+	http.HandleFunc("/mergeConfigsWithInjectedTest", handler(registryserver.ResolveAndMergeConfigsAndInjectTest(configAgent, usageTracker.Resolver(registryAgent), configresolverMetrics)).ServeHTTP)
 	http.HandleFunc("/resolve", handler(registryserver.ResolveLiteralConfig(registryAgent, configresolverMetrics)).ServeHTTP)
 	http.HandleFunc("/configGeneration", handler(getConfigGeneration(configAgent)).ServeHTTP)
 	http.HandleFunc("/registryGeneration", handler(getRegistryGeneration(registryAgent)).ServeHTTP)
+	http.HandleFunc("/registryUsage", handler(usageTracker.ServeUsage()).ServeHTTP)
+	if o.releaseRepoGitSyncPath != "" {
+		revisionAgent := agents.NewRevisionAgent(o.releaseRepoGitSyncPath, o.flatRegistry)
+		http.HandleFunc("/configAtRevision", handler(registryserver.ResolveConfigAtRevision(revisionAgent, configresolverMetrics)).ServeHTTP)
+	}
 	http.HandleFunc("/readyz", func(_ http.ResponseWriter, _ *http.Request) {})
 	interrupts.ListenAndServe(&http.Server{Addr: ":" + strconv.Itoa(o.port)}, o.gracePeriod)
 	uiMux := http.NewServeMux()
@@ -103,6 +103,12 @@ variable called the JOB_SPEC that defines the GitHub project to execute and the
 branch, and any PRs to merge onto the branch. See the kubernetes/test-infra project for
 a description of JOB_SPEC.
 
+To try a configuration out before pushing it, --local runs it against the namespace of the
+current context of the kubeconfig of the user instead: JOB_SPEC is optional, the namespace
+is neither created nor annotated for cleanup, and the artifacts are written to
+--local-artifact-dir unless $ARTIFACTS is set. The namespace is reused by every local run,
+so the images already in its pipeline image stream are not built again.
+
 The inputs of the build (source code, tagged images, configuration) are combined to form
 a consistent name for the target namespace that will change if any of the inputs change.
 This allows multiple test jobs to share common artifacts and still perform retries.
@@ -184,7 +190,21 @@ var (
 const CustomProwMetadata = "custom-prow-metadata.json"
 
 func main() {
-	censor, closer, err := setupLogger()
+	censor := setupLogger()
+	// "i just don't want spam"
+	klog.LogToStderr(false)
+	flagSet := flag.NewFlagSet("", flag.ExitOnError)
+	opt := bindOptions(flagSet)
+	opt.censor = censor
+	if err := flagSet.Parse(os.Args[1:]); err != nil {
+		logrus.WithError(err).Fatal("failed to parse flags")
+	}
+	if _, set := api.Artifacts(); opt.local && !set {
+		if err := api.SetArtifacts(opt.localArtifactDir); err != nil {
+			logrus.WithError(err).Fatal("Could not set the local artifact directory.")
+		}
+	}
+	closer, err := setupArtifactLogger(censor)
 	if err != nil {
 		logrus.WithError(err).Fatal("Could not set up logging.")
 	}
@@ -195,15 +215,7 @@ func main() {
 			}
 		}()
 	}
-	// "i just don't want spam"
-	klog.LogToStderr(false)
 	logrus.Infof("%s version %s", version.Name, version.Version)
-	flagSet := flag.NewFlagSet("", flag.ExitOnError)
-	opt := bindOptions(flagSet)
-	opt.censor = censor
-	if err := flagSet.Parse(os.Args[1:]); err != nil {
-		logrus.WithError(err).Fatal("failed to parse flags")
-	}
 
 	ctrlruntimelog.SetLogger(logr.New(ctrlruntimelog.NullLogSink{}))
 	if opt.verbose {
@@ -268,8 +280,8 @@ func main() {
 	opt.Report()
 }
 
-// setupLogger sets up logrus to print all logs to a file and user-friendly logs to stdout
-func setupLogger() (*secrets.DynamicCensor, io.Closer, error) {
+// setupLogger sets up logrus to print user-friendly logs to stdout
+func setupLogger() *secrets.DynamicCensor {
 	logrus.SetLevel(logrus.TraceLevel)
 	censor := secrets.NewDynamicCensor()
 	logrus.SetFormatter(logrusutil.NewFormatterWithCensor(logrus.StandardLogger().Formatter, &censor))
@@ -290,23 +302,28 @@ func setupLogger() (*secrets.DynamicCensor, io.Closer, error) {
 			logrus.PanicLevel,
 		},
 	})
+	return &censor
+}
+
+// setupArtifactLogger sets up logrus to print all logs to a file in the artifact directory, when it is set
+func setupArtifactLogger(censor *secrets.DynamicCensor) (io.Closer, error) {
 	artifactDir, set := api.Artifacts()
 	if !set {
-		return &censor, nil, nil
+		return nil, nil
 	}
 	if err := os.MkdirAll(artifactDir, 0777); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	verboseFile, err := os.Create(filepath.Join(artifactDir, "ci-operator.log"))
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	logrus.AddHook(&formattingHook{
-		formatter: logrusutil.NewFormatterWithCensor(&logrus.JSONFormatter{}, &censor),
+		formatter: logrusutil.NewFormatterWithCensor(&logrus.JSONFormatter{}, censor),
 		writer:    verboseFile,
 		logLevels: logrus.AllLevels,
 	})
-	return &censor, verboseFile, nil
+	return verboseFile, nil
 }
 
 type formattingHook struct {
@@ -359,6 +376,9 @@ type options struct {
 	writeParams string
 	artifactDir string
 
+	local            bool
+	localArtifactDir string
+
 	gitRef                 string
 	namespace              string
 	baseNamespace          string
@@ -473,6 +493,8 @@ func bindOptions(flag *flag.FlagSet) *options {
 	flag.StringVar(&opt.gitRef, "git-ref", "", "Populate the job spec from this local Git reference. If JOB_SPEC is set, the refs field will be overwritten.")
 	flag.BoolVar(&opt.givePrAuthorAccessToNamespace, "give-pr-author-access-to-namespace", true, "Give view access to the temporarily created namespace to the PR author.")
 	flag.StringVar(&opt.impersonateUser, "as", "", "Username to impersonate")
+	flag.BoolVar(&opt.local, "local", false, "Run against the namespace of the current context of the kubeconfig, without requiring JOB_SPEC or creating the namespace.")
+	flag.StringVar(&opt.localArtifactDir, "local-artifact-dir", "artifacts", "The directory the artifacts are written to with --local, when $ARTIFACTS is not set.")
 
 	// flags needed for the configresolver
 	flag.StringVar(&opt.resolverAddress, "resolver-address", configResolverAddress, "Address of configresolver")
@@ -504,15 +526,20 @@ func bindOptions(flag *flag.FlagSet) *options {
 func (o *options) Complete() error {
 	jobSpec, err := api.ResolveSpecFromEnv()
 	if err != nil {
-		if len(o.gitRef) == 0 {
-			return fmt.Errorf("failed to determine job spec: no --git-ref passed and failed to resolve job spec from env: %w", err)
-		}
-		// Failed to read $JOB_SPEC but --git-ref was passed, so try that instead
-		spec, refErr := jobSpecFromGitRef(o.gitRef)
-		if refErr != nil {
-			return fmt.Errorf("failed to determine job spec: failed to resolve --git-ref: %w", refErr)
+		switch {
+		case len(o.gitRef) > 0:
+			// Failed to read $JOB_SPEC but --git-ref was passed, so try that instead
+			spec, refErr := jobSpecFromGitRef(o.gitRef)
+			if refErr != nil {
+				return fmt.Errorf("failed to determine job spec: failed to resolve --git-ref: %w", refErr)
+			}
+			jobSpec = spec
+		case o.local:
+			// Local runs are not started by Prow, so they can do without a $JOB_SPEC and just have no sources
+			jobSpec = localJobSpec()
+		default:
+			return fmt.Errorf("failed to determine job spec: no --git-ref or --local passed and failed to resolve job spec from env: %w", err)
 		}
-		jobSpec = spec
 	} else if len(o.gitRef) > 0 {
 		// Read from $JOB_SPEC but --git-ref was also passed, so merge them
 		spec, err := jobSpecFromGitRef(o.gitRef)
@@ -605,6 +632,9 @@ func (o *options) Complete() error {
 	if len(o.sshKeyPath) > 0 && len(o.oauthTokenPath) > 0 {
 		return errors.New("both --ssh-key-path and --oauth-token-path are specified")
 	}
+	if o.local && o.promote {
+		return errors.New("cannot promote the images of a --local run")
+	}
 
 	var cloneAuthSecretPath string
 	if len(o.oauthTokenPath) > 0 {
@@ -660,7 +690,7 @@ func (o *options) Complete() error {
 		o.templates = append(o.templates, template)
 	}
 
-	clusterConfig, err := util.LoadClusterConfig()
+	clusterConfig, err := o.loadClusterConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load cluster config: %w", err)
 	}
@@ -711,6 +741,22 @@ func (o *options) Complete() error {
 	return overrideTestStepDependencyParams(o)
 }
 
+// loadClusterConfig loads the config of the cluster ci-operator runs in, or the one $KUBECONFIG points to. With --local,
+// the current context of the kubeconfig is loaded instead and its namespace is used unless --namespace is set.
+func (o *options) loadClusterConfig() (*rest.Config, error) {
+	if !o.local {
+		return util.LoadClusterConfig()
+	}
+	clusterConfig, namespace, err := util.LoadCurrentContext()
+	if err != nil {
+		return nil, err
+	}
+	if len(o.namespace) == 0 {
+		o.namespace = namespace
+	}
+	return clusterConfig, nil
+}
+
 func parseKeyValParams(input []string, paramType string) (map[string]string, error) {
 	var validationErrors []error
 	params := make(map[string]string)
@@ -1111,41 +1157,13 @@ func (o *options) initializeNamespace() error {
 	client = ctrlruntimeclient.NewNamespacedClient(client, o.namespace)
 	ctx := context.Background()
 
-	logrus.Debugf("Creating namespace %s", o.namespace)
-	authTimeout := 15 * time.Second
-	initBeginning := time.Now()
-	for {
-		project, err := projectGetter.ProjectV1().ProjectRequests().Create(context.TODO(), &projectapi.ProjectRequest{
-			ObjectMeta: meta.ObjectMeta{
-				Name:   o.namespace,
-				Labels: map[string]string{api.DPTPRequesterLabel: "ci-operator"},
-			},
-			DisplayName: fmt.Sprintf("%s - %s", o.namespace, o.jobSpec.Job),
-			Description: jobDescription(o.jobSpec),
-		}, meta.CreateOptions{})
-		if err != nil && !kerrors.IsAlreadyExists(err) {
-			return fmt.Errorf("could not set up namespace for test: %w", err)
-		}
-		if err != nil {
-			project, err = projectGetter.ProjectV1().Projects().Get(context.TODO(), o.namespace, meta.GetOptions{})
-			if err != nil {
-				if kerrors.IsNotFound(err) {
-					continue
-				}
-				// wait a few seconds for auth caches to catch up
-				if kerrors.IsForbidden(err) && time.Since(initBeginning) < authTimeout {
-					time.Sleep(time.Second)
-					continue
-				}
-				return fmt.Errorf("failed to wait for authentication cache to warm up after %s: %w", authTimeout, err)
-			}
-		}
-		if project.Status.Phase == coreapi.NamespaceTerminating {
-			logrus.Info("Waiting for namespace to finish terminating before creating another")
-			time.Sleep(3 * time.Second)
-			continue
+	if o.local {
+		// The namespace of the current context belongs to the user, it is neither created nor annotated for cleanup
+		if _, err := projectGetter.ProjectV1().Projects().Get(ctx, o.namespace, meta.GetOptions{}); err != nil {
+			return fmt.Errorf("could not get namespace %s of the current context: %w", o.namespace, err)
 		}
-		break
+	} else if err := o.createProject(projectGetter); err != nil {
+		return err
 	}
 
 	ssarStart := time.Now()
@@ -1173,68 +1191,10 @@ func (o *options) initializeNamespace() error {
 		return errors.New("timed out waiting for RBAC")
 	}
 
-	// Annotate the namespace for cleanup by external tooling (ci-ns-ttl-controller)
-	// Unfortunately we cannot set the annotations right away when we create a project
-	// because that API does not support it (historical limitation).
-	//
-	// We can also only annotate the project *after* the SSAR check above, which
-	// means that if SSAR fails, the project will *not* be annotated for cleanup.
-	annotationUpdates := map[string]string{}
-	if o.idleCleanupDuration > 0 {
-		if o.idleCleanupDurationSet {
-			logrus.Debugf("Setting a soft TTL of %s for the namespace", o.idleCleanupDuration.String())
-		}
-		annotationUpdates[nsttl.AnnotationIdleCleanupDurationTTL] = o.idleCleanupDuration.String()
-	}
-
-	if o.cleanupDuration > 0 {
-		if o.cleanupDurationSet {
-			logrus.Debugf("Setting a hard TTL of %s for the namespace", o.cleanupDuration.String())
-		}
-		annotationUpdates[nsttl.AnnotationCleanupDurationTTL] = o.cleanupDuration.String()
-	}
-
-	// This label makes sure that the namespace is active, and the value will be updated
-	// if the namespace will be reused.
-	annotationUpdates[nsttl.AnnotationNamespaceLastActive] = time.Now().Format(time.RFC3339)
-
-	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		ns := &coreapi.Namespace{}
-		if err := client.Get(ctx, ctrlruntimeclient.ObjectKey{Name: o.namespace}, ns); err != nil {
+	if !o.local {
+		if err := o.annotateNamespace(ctx, client); err != nil {
 			return err
 		}
-
-		if ns.Labels == nil {
-			ns.Labels = map[string]string{}
-		}
-		ns.Labels[api.AutoScalePodsLabel] = "true"
-
-		if ns.Annotations == nil {
-			ns.Annotations = make(map[string]string)
-		}
-		for key, value := range annotationUpdates {
-			// allow specific annotations to be skipped if they are already set and the user didn't ask
-			switch key {
-			case nsttl.AnnotationCleanupDurationTTL:
-				if !o.cleanupDurationSet && len(ns.Annotations[key]) != 0 {
-					continue
-				}
-			case nsttl.AnnotationIdleCleanupDurationTTL:
-				if !o.idleCleanupDurationSet && len(ns.Annotations[key]) != 0 {
-					continue
-				}
-			}
-			ns.ObjectMeta.Annotations[key] = value
-		}
-
-		updateErr := client.Update(ctx, ns)
-		if kerrors.IsForbidden(updateErr) {
-			logrus.WithError(err).Warn("Could not edit namespace because you do not have permission to update the namespace.")
-			return nil
-		}
-		return updateErr
-	}); err != nil {
-		return fmt.Errorf("could not update namespace to add labels, TTLs and active annotations: %w", err)
 	}
 
 	pullStart := time.Now()
@@ -1282,30 +1242,32 @@ func (o *options) initializeNamespace() error {
 		}
 	}
 
-	go func() {
-		ticker := time.NewTicker(10 * time.Minute)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				ns := &coreapi.Namespace{}
-				if err := client.Get(ctx, ctrlruntimeclient.ObjectKey{Name: o.namespace}, ns); err != nil {
-					logrus.WithError(err).Warnf("Failed to get namespace %s for heartbeating", o.namespace)
-					continue
-				}
-				originalNS := ns.DeepCopy()
-				if ns.Annotations == nil {
-					ns.Annotations = map[string]string{}
-				}
-				ns.Annotations[nsttl.AnnotationNamespaceLastActive] = time.Now().Format(time.RFC3339)
-				if err := client.Patch(ctx, ns, ctrlruntimeclient.MergeFrom(originalNS)); err != nil {
-					logrus.WithError(err).Warnf("Failed to patch the %s namespace to update the %s annotation.", o.namespace, nsttl.AnnotationNamespaceLastActive)
+	if !o.local {
+		go func() {
+			ticker := time.NewTicker(10 * time.Minute)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					ns := &coreapi.Namespace{}
+					if err := client.Get(ctx, ctrlruntimeclient.ObjectKey{Name: o.namespace}, ns); err != nil {
+						logrus.WithError(err).Warnf("Failed to get namespace %s for heartbeating", o.namespace)
+						continue
+					}
+					originalNS := ns.DeepCopy()
+					if ns.Annotations == nil {
+						ns.Annotations = map[string]string{}
+					}
+					ns.Annotations[nsttl.AnnotationNamespaceLastActive] = time.Now().Format(time.RFC3339)
+					if err := client.Patch(ctx, ns, ctrlruntimeclient.MergeFrom(originalNS)); err != nil {
+						logrus.WithError(err).Warnf("Failed to patch the %s namespace to update the %s annotation.", o.namespace, nsttl.AnnotationNamespaceLastActive)
+					}
 				}
 			}
-		}
-	}()
+		}()
+	}
 
 	logrus.Debugf("Setting up pipeline ImageStream for the test")
 
@@ -1361,6 +1323,115 @@ func (o *options) initializeNamespace() error {
 	return nil
 }
 
+// createProject creates the namespace of the job and waits for it to be usable
+func (o *options) createProject(projectGetter projectclientset.Interface) error {
+	logrus.Debugf("Creating namespace %s", o.namespace)
+	authTimeout := 15 * time.Second
+	initBeginning := time.Now()
+	for {
+		project, err := projectGetter.ProjectV1().ProjectRequests().Create(context.TODO(), &projectapi.ProjectRequest{
+			ObjectMeta: meta.ObjectMeta{
+				Name:   o.namespace,
+				Labels: map[string]string{api.DPTPRequesterLabel: "ci-operator"},
+			},
+			DisplayName: fmt.Sprintf("%s - %s", o.namespace, o.jobSpec.Job),
+			Description: jobDescription(o.jobSpec),
+		}, meta.CreateOptions{})
+		if err != nil && !kerrors.IsAlreadyExists(err) {
+			return fmt.Errorf("could not set up namespace for test: %w", err)
+		}
+		if err != nil {
+			project, err = projectGetter.ProjectV1().Projects().Get(context.TODO(), o.namespace, meta.GetOptions{})
+			if err != nil {
+				if kerrors.IsNotFound(err) {
+					continue
+				}
+				// wait a few seconds for auth caches to catch up
+				if kerrors.IsForbidden(err) && time.Since(initBeginning) < authTimeout {
+					time.Sleep(time.Second)
+					continue
+				}
+				return fmt.Errorf("failed to wait for authentication cache to warm up after %s: %w", authTimeout, err)
+			}
+		}
+		if project.Status.Phase == coreapi.NamespaceTerminating {
+			logrus.Info("Waiting for namespace to finish terminating before creating another")
+			time.Sleep(3 * time.Second)
+			continue
+		}
+		break
+	}
+	return nil
+}
+
+// annotateNamespace labels the namespace of the job and annotates it for cleanup by external tooling
+func (o *options) annotateNamespace(ctx context.Context, client ctrlruntimeclient.Client) error {
+	// Annotate the namespace for cleanup by external tooling (ci-ns-ttl-controller)
+	// Unfortunately we cannot set the annotations right away when we create a project
+	// because that API does not support it (historical limitation).
+	//
+	// We can also only annotate the project *after* the SSAR check in initializeNamespace, which
+	// means that if SSAR fails, the project will *not* be annotated for cleanup.
+	annotationUpdates := map[string]string{}
+	if o.idleCleanupDuration > 0 {
+		if o.idleCleanupDurationSet {
+			logrus.Debugf("Setting a soft TTL of %s for the namespace", o.idleCleanupDuration.String())
+		}
+		annotationUpdates[nsttl.AnnotationIdleCleanupDurationTTL] = o.idleCleanupDuration.String()
+	}
+
+	if o.cleanupDuration > 0 {
+		if o.cleanupDurationSet {
+			logrus.Debugf("Setting a hard TTL of %s for the namespace", o.cleanupDuration.String())
+		}
+		annotationUpdates[nsttl.AnnotationCleanupDurationTTL] = o.cleanupDuration.String()
+	}
+
+	// This label makes sure that the namespace is active, and the value will be updated
+	// if the namespace will be reused.
+	annotationUpdates[nsttl.AnnotationNamespaceLastActive] = time.Now().Format(time.RFC3339)
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ns := &coreapi.Namespace{}
+		if err := client.Get(ctx, ctrlruntimeclient.ObjectKey{Name: o.namespace}, ns); err != nil {
+			return err
+		}
+
+		if ns.Labels == nil {
+			ns.Labels = map[string]string{}
+		}
+		ns.Labels[api.AutoScalePodsLabel] = "true"
+
+		if ns.Annotations == nil {
+			ns.Annotations = make(map[string]string)
+		}
+		for key, value := range annotationUpdates {
+			// allow specific annotations to be skipped if they are already set and the user didn't ask
+			switch key {
+			case nsttl.AnnotationCleanupDurationTTL:
+				if !o.cleanupDurationSet && len(ns.Annotations[key]) != 0 {
+					continue
+				}
+			case nsttl.AnnotationIdleCleanupDurationTTL:
+				if !o.idleCleanupDurationSet && len(ns.Annotations[key]) != 0 {
+					continue
+				}
+			}
+			ns.ObjectMeta.Annotations[key] = value
+		}
+
+		updateErr := client.Update(ctx, ns)
+		if kerrors.IsForbidden(updateErr) {
+			logrus.WithError(updateErr).Warn("Could not edit namespace because you do not have permission to update the namespace.")
+			return nil
+		}
+		return updateErr
+	}); err != nil {
+		return fmt.Errorf("could not update namespace to add labels, TTLs and active annotations: %w", err)
+	}
+	return nil
+}
+
 func generateAuthorAccessRoleBinding(namespace string, authors []string) *rbacapi.RoleBinding {
 	var subjects []rbacapi.Subject
 	authorSet := sets.New[string](authors...)
@@ -1779,6 +1850,16 @@ func jobDescription(job *api.JobSpec) string {
 	return fmt.Sprintf("%s on https://github.com/%s/%s ref=%s commit=%s", job.Job, job.Refs.Org, job.Refs.Repo, job.Refs.BaseRef, job.Refs.BaseSHA)
 }
 
+// localJobSpec is the job spec of the --local runs which neither have a $JOB_SPEC nor a --git-ref
+func localJobSpec() *api.JobSpec {
+	return &api.JobSpec{
+		JobSpec: downwardapi.JobSpec{
+			Type: prowapi.PeriodicJob,
+			Job:  "local",
+		},
+	}
+}
+
 func jobSpecFromGitRef(ref string) (*api.JobSpec, error) {
 	parts := strings.Split(ref, "@")
 	if len(parts) != 2 {
@@ -1612,3 +1612,65 @@ func TestHandleTargetAdditionalSuffix(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadClusterConfig(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: dev
+  cluster:
+    server: https://api.dev.example.com:6443
+users:
+- name: developer
+  user:
+    token: secret
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: developer
+    namespace: my-project
+current-context: dev
+`), 0644); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	t.Setenv("KUBECONFIG", kubeconfig)
+
+	testCases := []struct {
+		name              string
+		local             bool
+		namespace         string
+		expectedNamespace string
+	}{
+		{
+			name: "namespace is not defaulted without --local",
+		},
+		{
+			name:              "--local defaults to the namespace of the current context",
+			local:             true,
+			expectedNamespace: "my-project",
+		},
+		{
+			name:              "--namespace overrides the namespace of the current context",
+			local:             true,
+			namespace:         "ci-op-{id}",
+			expectedNamespace: "ci-op-{id}",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &options{local: tc.local, namespace: tc.namespace}
+			clusterConfig, err := o.loadClusterConfig()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if clusterConfig.Host != "https://api.dev.example.com:6443" {
+				t.Errorf("expected the host of the kubeconfig, got %s", clusterConfig.Host)
+			}
+			if diff := cmp.Diff(tc.expectedNamespace, o.namespace); diff != "" {
+				t.Errorf("unexpected namespace: %s", diff)
+			}
+		})
+	}
+}
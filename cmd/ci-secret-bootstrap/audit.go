@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+
+	coreapi "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	auditFormatJSON = "json"
+	auditFormatHTML = "html"
+)
+
+// auditReport lists, per cluster, the secrets whose content in the cluster drifted from the
+// content the config expects. Like plans, it only holds the names of keys, never values.
+type auditReport struct {
+	// Audited is the number of secrets that were compared
+	Audited  int                      `json:"audited"`
+	Clusters map[string][]secretAudit `json:"clusters"`
+}
+
+type secretAudit struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Missing is set when the secret does not exist in the cluster
+	Missing bool `json:"missing,omitempty"`
+	// Type is the type of the secret in the cluster, when it differs from the expected one
+	Type          coreapi.SecretType `json:"type,omitempty"`
+	MissingKeys   []string           `json:"missing_keys,omitempty"`
+	DriftedKeys   []string           `json:"drifted_keys,omitempty"`
+	UnmanagedKeys []string           `json:"unmanaged_keys,omitempty"`
+}
+
+// auditSecrets compares the secrets in the clusters with the expected ones and builds a report
+// of the ones that drifted
+func auditSecrets(getters map[string]Getter, secretsMap map[string][]*coreapi.Secret, osdGlobalPullSecretGroup sets.Set[string]) (auditReport, error) {
+	var errs []error
+	report := auditReport{Clusters: map[string][]secretAudit{}}
+	for cluster, secrets := range secretsMap {
+		for _, secret := range secrets {
+			plan, err := planSecret(getters[cluster], secret, osdGlobalPullSecretGroup.Has(cluster))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to audit secret %s:%s/%s: %w", cluster, secret.Namespace, secret.Name, err))
+				continue
+			}
+			report.Audited++
+			audit := secretAudit{
+				Namespace:     secret.Namespace,
+				Name:          secret.Name,
+				MissingKeys:   plan.added,
+				DriftedKeys:   plan.changed,
+				UnmanagedKeys: plan.removed,
+			}
+			switch plan.action {
+			case planCreate:
+				audit.Missing = true
+			case planReplace:
+				audit.Type = plan.existingType
+			case planUnchanged:
+				continue
+			}
+			if !audit.Missing && audit.Type == "" && len(audit.MissingKeys)+len(audit.DriftedKeys)+len(audit.UnmanagedKeys) == 0 {
+				// only the metadata differs
+				continue
+			}
+			report.Clusters[cluster] = append(report.Clusters[cluster], audit)
+		}
+	}
+	for _, audits := range report.Clusters {
+		sort.Slice(audits, func(i, j int) bool {
+			if audits[i].Namespace != audits[j].Namespace {
+				return audits[i].Namespace < audits[j].Namespace
+			}
+			return audits[i].Name < audits[j].Name
+		})
+	}
+	return report, utilerrors.NewAggregate(errs)
+}
+
+var auditTemplate = template.Must(template.New("audit").Parse(`<!DOCTYPE html>
+<html>
+<head><title>ci-secret-bootstrap audit</title></head>
+<body>
+<h1>Drifted secrets</h1>
+<p>{{ .Audited }} secrets were audited.</p>
+{{- range $cluster, $audits := .Clusters }}
+<h2>{{ $cluster }}</h2>
+<table>
+<tr><th>Secret</th><th>Missing keys</th><th>Drifted keys</th><th>Unmanaged keys</th></tr>
+{{- range $audits }}
+<tr><td>{{ .Namespace }}/{{ .Name }}{{ if .Missing }} (missing){{ end }}{{ if .Type }} (type {{ .Type }}){{ end }}</td><td>{{ range $i, $key := .MissingKeys }}{{ if $i }}, {{ end }}{{ $key }}{{ end }}</td><td>{{ range $i, $key := .DriftedKeys }}{{ if $i }}, {{ end }}{{ $key }}{{ end }}</td><td>{{ range $i, $key := .UnmanagedKeys }}{{ if $i }}, {{ end }}{{ $key }}{{ end }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+</body>
+</html>
+`))
+
+// writeAuditReport writes the report in the format, either json or html
+func writeAuditReport(report auditReport, format string, out io.Writer) error {
+	switch format {
+	case auditFormatJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case auditFormatHTML:
+		return auditTemplate.Execute(out, report)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
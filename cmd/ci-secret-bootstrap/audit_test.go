@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	coreapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAuditSecrets(t *testing.T) {
+	managed := map[string]string{"dptp.openshift.io/requester": "ci-secret-bootstrap"}
+	client := fake.NewSimpleClientset(
+		&coreapi.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "in-sync", Labels: managed},
+			Data:       map[string][]byte{"key": []byte("value")},
+			Type:       coreapi.SecretTypeOpaque,
+		},
+		&coreapi.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "unlabeled"},
+			Data:       map[string][]byte{"key": []byte("value")},
+			Type:       coreapi.SecretTypeOpaque,
+		},
+		&coreapi.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "drifted", Labels: managed},
+			Data:       map[string][]byte{"drifted": []byte("old"), "unmanaged": []byte("value"), "key": []byte("value")},
+			Type:       coreapi.SecretTypeOpaque,
+		},
+	)
+	secretsMap := map[string][]*coreapi.Secret{
+		"build01": {
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "in-sync"},
+				Data:       map[string][]byte{"key": []byte("value")},
+				Type:       coreapi.SecretTypeOpaque,
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "unlabeled"},
+				Data:       map[string][]byte{"key": []byte("value")},
+				Type:       coreapi.SecretTypeOpaque,
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "drifted"},
+				Data:       map[string][]byte{"drifted": []byte("new"), "missing": []byte("value"), "key": []byte("value")},
+				Type:       coreapi.SecretTypeDockerConfigJson,
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "absent"},
+				Data:       map[string][]byte{"key": []byte("value")},
+				Type:       coreapi.SecretTypeOpaque,
+			},
+		},
+	}
+
+	report, err := auditSecrets(map[string]Getter{"build01": client.CoreV1()}, secretsMap, sets.New[string]())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := auditReport{
+		Audited: 4,
+		Clusters: map[string][]secretAudit{
+			"build01": {
+				{Namespace: "ci", Name: "absent", Missing: true},
+				{
+					Namespace:     "ci",
+					Name:          "drifted",
+					Type:          coreapi.SecretTypeOpaque,
+					MissingKeys:   []string{"missing"},
+					DriftedKeys:   []string{"drifted"},
+					UnmanagedKeys: []string{"unmanaged"},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, report); diff != "" {
+		t.Fatalf("report differs from expected: %s", diff)
+	}
+
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: "json",
+			expected: `{
+  "audited": 4,
+  "clusters": {
+    "build01": [
+      {
+        "namespace": "ci",
+        "name": "absent",
+        "missing": true
+      },
+      {
+        "namespace": "ci",
+        "name": "drifted",
+        "type": "Opaque",
+        "missing_keys": [
+          "missing"
+        ],
+        "drifted_keys": [
+          "drifted"
+        ],
+        "unmanaged_keys": [
+          "unmanaged"
+        ]
+      }
+    ]
+  }
+}
+`,
+		},
+		{
+			format: "html",
+			expected: `<!DOCTYPE html>
+<html>
+<head><title>ci-secret-bootstrap audit</title></head>
+<body>
+<h1>Drifted secrets</h1>
+<p>4 secrets were audited.</p>
+<h2>build01</h2>
+<table>
+<tr><th>Secret</th><th>Missing keys</th><th>Drifted keys</th><th>Unmanaged keys</th></tr>
+<tr><td>ci/absent (missing)</td><td></td><td></td><td></td></tr>
+<tr><td>ci/drifted (type Opaque)</td><td>missing</td><td>drifted</td><td>unmanaged</td></tr>
+</table>
+</body>
+</html>
+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if err := writeAuditReport(report, tc.format, out); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, out.String()); diff != "" {
+				t.Errorf("report differs from expected: %s", diff)
+			}
+		})
+	}
+}
@@ -47,11 +47,16 @@ type options struct {
 	force              bool
 	validateItemsUsage bool
 	confirm            bool
+	plan               bool
+	audit              bool
+	auditFormat        string
+	prune              bool
 
 	kubernetesOptions   flagutil.KubernetesOptions
 	configPath          string
 	generatorConfigPath string
 	cluster             string
+	namespace           string
 	secretNamesRaw      flagutil.Strings
 	logLevel            string
 	impersonateUser     string
@@ -60,9 +65,11 @@ type options struct {
 	config          secretbootstrap.Config
 	generatorConfig secretgenerator.Config
 
-	allowUnused flagutil.Strings
+	allowUnused              flagutil.Strings
+	pruneProtectedNamespaces flagutil.Strings
 
-	validateOnly bool
+	validateOnly          bool
+	requireGeneratedItems bool
 }
 
 const (
@@ -80,10 +87,17 @@ func parseOptions(censor *secrets.DynamicCensor) (options, error) {
 	fs.BoolVar(&o.validateItemsUsage, "validate-bitwarden-items-usage", false, fmt.Sprintf("If set, the tool only validates if all fields that exist in Vault and were last modified before %d days ago are being used in the given config.", allowUnusedDays))
 	fs.BoolVar(&o.dryRun, "dry-run", true, "Whether to actually create the secrets with oc command")
 	fs.BoolVar(&o.confirm, "confirm", true, "Whether to mutate the actual secrets in the targeted clusters")
+	fs.BoolVar(&o.prune, "prune", false, "If set, secrets with the label of the tool in the clusters that are no longer in the config are deleted.")
+	fs.Var(&o.pruneProtectedNamespaces, "prune-protected-namespace", "A namespace in which --prune never deletes secrets. Can be passed multiple times.")
+	fs.BoolVar(&o.audit, "audit", false, "If set, the tool only reports the secrets in the clusters that drifted from the config, without their values.")
+	fs.StringVar(&o.auditFormat, "audit-format", auditFormatJSON, fmt.Sprintf("The format of the report of --audit, one of %s and %s.", auditFormatJSON, auditFormatHTML))
+	fs.BoolVar(&o.plan, "plan", false, "If set, the tool only prints which secrets would be created, updated or left alone in every cluster and namespace, without their values.")
 	o.kubernetesOptions.AddFlags(fs)
 	fs.StringVar(&o.configPath, "config", "", "Path to the config file to use for this tool.")
-	fs.StringVar(&o.generatorConfigPath, "generator-config", "", "Path to the secret-generator config file.")
+	fs.StringVar(&o.generatorConfigPath, "generator-config", "", "Path to the secret-generator config file, or to a directory of them.")
+	fs.BoolVar(&o.requireGeneratedItems, "require-generated-items", false, "If set, every field that secrets are populated from must be generated by the --generator-config, unless its item is listed in external_items.")
 	fs.StringVar(&o.cluster, "cluster", "", "If set, only provision secrets for this cluster")
+	fs.StringVar(&o.namespace, "namespace", "", "If set, only provision secrets in this namespace")
 	fs.Var(&o.secretNamesRaw, "secret-names", "If set, only provision secrets with the given name. user_secrets_target_clusters in the configuration is ignored. Can be passed multiple times.")
 	fs.BoolVar(&o.force, "force", false, "If true, update the secrets even if existing one differs from Bitwarden items instead of existing with error. Default false.")
 	fs.StringVar(&o.logLevel, "log-level", "info", fmt.Sprintf("Log level is one of %v.", logrus.AllLevels))
@@ -109,6 +123,24 @@ func (o *options) validateOptions() error {
 	if len(o.allowUnused.Strings()) > 0 && !o.validateItemsUsage {
 		errs = append(errs, errors.New("--bw-allow-unused must be specified with --validate-items-usage"))
 	}
+	if o.prune && len(o.secretNamesRaw.Strings()) > 0 {
+		errs = append(errs, errors.New("--prune and --secret-names are mutually exclusive"))
+	}
+	if len(o.pruneProtectedNamespaces.Strings()) > 0 && !o.prune {
+		errs = append(errs, errors.New("--prune-protected-namespace must be specified with --prune"))
+	}
+	if o.requireGeneratedItems && o.generatorConfigPath == "" {
+		errs = append(errs, errors.New("--require-generated-items must be specified with --generator-config"))
+	}
+	if o.plan && o.validateOnly {
+		errs = append(errs, errors.New("--plan and --validate-only are mutually exclusive"))
+	}
+	if o.audit && (o.plan || o.validateOnly) {
+		errs = append(errs, errors.New("--audit, --plan and --validate-only are mutually exclusive"))
+	}
+	if o.audit && o.auditFormat != auditFormatJSON && o.auditFormat != auditFormatHTML {
+		errs = append(errs, fmt.Errorf("--audit-format must be one of %s and %s", auditFormatJSON, auditFormatHTML))
+	}
 	errs = append(errs, o.kubernetesOptions.Validate(o.dryRun))
 	return utilerrors.NewAggregate(errs)
 }
@@ -160,6 +192,10 @@ func (o *options) completeOptions(censor *secrets.DynamicCensor, kubeConfigs map
 				logrus.WithFields(logrus.Fields{"target-cluster": o.cluster, "secret-cluster": secretContext.Cluster}).Debug("Skipping provisioning of secrets for a cluster that does not match the one configured via --cluster")
 				continue
 			}
+			if o.namespace != "" && o.namespace != secretContext.Namespace {
+				logrus.WithFields(logrus.Fields{"target-namespace": o.namespace, "secret-namespace": secretContext.Namespace}).Debug("Skipping provisioning of secrets for a namespace that does not match the one configured via --namespace")
+				continue
+			}
 			to = append(to, secretContext)
 
 			if !o.validateOnly {
@@ -177,13 +213,27 @@ func (o *options) completeOptions(censor *secrets.DynamicCensor, kubeConfigs map
 			}
 		}
 
-		if len(to) > 0 {
+		if o.cluster != "" || o.namespace != "" {
+			// partial syncs only target clusters
+			secretConfig.ToVault = nil
+		}
+		if len(to) > 0 || len(secretConfig.ToVault) > 0 {
 			secretConfig.To = to
 			filteredSecrets = append(filteredSecrets, secretConfig)
 		}
 	}
 	o.config.Secrets = filteredSecrets
 
+	if o.cluster != "" {
+		var userSecretsTargetClusters []string
+		for _, cluster := range o.config.UserSecretsTargetClusters {
+			if cluster == o.cluster {
+				userSecretsTargetClusters = append(userSecretsTargetClusters, cluster)
+			}
+		}
+		o.config.UserSecretsTargetClusters = userSecretsTargetClusters
+	}
+
 	return o.validateCompletedOptions()
 }
 
@@ -192,6 +242,7 @@ func pruneIrrelevantConfiguration(c *secretbootstrap.Config, secretNames sets.Se
 	for _, secretConfig := range c.Secrets {
 		for _, secretContext := range secretConfig.To {
 			if secretNames.Has(secretContext.Name) {
+				secretConfig.ToVault = nil
 				secretConfigs = append(secretConfigs, secretConfig)
 				break
 			}
@@ -205,14 +256,43 @@ func (o *options) validateCompletedOptions() error {
 	if err := o.config.Validate(); err != nil {
 		return fmt.Errorf("failed to validate the config: %w", err)
 	}
+	if o.requireGeneratedItems {
+		if err := o.config.ValidateGenerated(o.generatorConfig); err != nil {
+			return fmt.Errorf("failed to validate the config against the generator config: %w", err)
+		}
+	}
 	toMap := map[string]map[string]string{}
+	sourceItems := sets.New[string]()
+	for _, secretConfig := range o.config.Secrets {
+		for _, itemContext := range secretConfig.From {
+			sourceItems.Insert(itemContext.Item)
+			for _, data := range itemContext.DockerConfigJSONData {
+				sourceItems.Insert(data.Item)
+			}
+		}
+	}
+	vaultKeys := sets.New[string]()
 	for i, secretConfig := range o.config.Secrets {
 		if len(secretConfig.From) == 0 {
 			return fmt.Errorf("config[%d].from is empty", i)
 		}
-		if len(secretConfig.To) == 0 {
+		if len(secretConfig.To) == 0 && len(secretConfig.ToVault) == 0 {
 			return fmt.Errorf("config[%d].to is empty", i)
 		}
+		for j, vaultContext := range secretConfig.ToVault {
+			if vaultContext.Path == "" {
+				return fmt.Errorf("config[%d].to_vault[%d].path: empty value is not allowed", i, j)
+			}
+			if sourceItems.Has(vaultContext.Path) {
+				return fmt.Errorf("config[%d].to_vault[%d]: item %s is a source of secrets and cannot be synced to", i, j, vaultContext.Path)
+			}
+			for key := range secretConfig.From {
+				if vaultKeys.Has(vaultContext.Path + "/" + key) {
+					return fmt.Errorf("config[%d].to_vault[%d]: key %s of item %s is synced to more than once", i, j, key, vaultContext.Path)
+				}
+				vaultKeys.Insert(vaultContext.Path + "/" + key)
+			}
+		}
 		for key, itemContext := range secretConfig.From {
 			if key == "" {
 				return fmt.Errorf("config[%d].from: empty key is not allowed", i)
@@ -235,7 +315,13 @@ func (o *options) validateCompletedOptions() error {
 						return fmt.Errorf("config[%d].from[%s]: registry_url must be set", i, key)
 					}
 
-					if data.AuthField == "" {
+					if data.AuthField != "" && (data.UsernameField != "" || data.PasswordField != "") {
+						return fmt.Errorf("config[%d].from[%s]: auth_field is mutually exclusive with username_field and password_field", i, key)
+					}
+					if data.AuthField == "" && (data.UsernameField == "") != (data.PasswordField == "") {
+						return fmt.Errorf("config[%d].from[%s]: username_field and password_field must be set together", i, key)
+					}
+					if data.AuthField == "" && data.UsernameField == "" {
 						return fmt.Errorf("config[%d].from[%s]: auth_field is missing", i, key)
 					}
 				}
@@ -274,11 +360,23 @@ func constructDockerConfigJSON(client secrets.ReadOnlyClient, dockerConfigJSONDa
 	for _, data := range dockerConfigJSONData {
 		authData := secretbootstrap.DockerAuth{}
 
-		authBWAttachmentValue, err := client.GetFieldOnItem(data.Item, data.AuthField)
-		if err != nil {
-			return nil, fmt.Errorf("couldn't get auth field '%s' from item %s: %w", data.AuthField, data.Item, err)
+		if data.AuthField != "" {
+			authBWAttachmentValue, err := client.GetFieldOnItem(data.Item, data.AuthField)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't get auth field '%s' from item %s: %w", data.AuthField, data.Item, err)
+			}
+			authData.Auth = string(bytes.TrimSpace(authBWAttachmentValue))
+		} else {
+			username, err := client.GetFieldOnItem(data.Item, data.UsernameField)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't get username field '%s' from item %s: %w", data.UsernameField, data.Item, err)
+			}
+			password, err := client.GetFieldOnItem(data.Item, data.PasswordField)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't get password field '%s' from item %s: %w", data.PasswordField, data.Item, err)
+			}
+			authData.Auth = base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", bytes.TrimSpace(username), bytes.TrimSpace(password))))
 		}
-		authData.Auth = string(bytes.TrimSpace(authBWAttachmentValue))
 
 		if data.EmailField != "" {
 			emailValue, err := client.GetFieldOnItem(data.Item, data.EmailField)
@@ -303,6 +401,60 @@ func constructDockerConfigJSON(client secrets.ReadOnlyClient, dockerConfigJSONDa
 	return b, nil
 }
 
+// constructData fetches the values of the keys of a secret config from the secret store
+func constructData(idx int, cfg secretbootstrap.SecretConfig, client secrets.ReadOnlyClient) (map[string][]byte, []error) {
+	data := make(map[string][]byte)
+	var errs []error
+	var keys []string
+	for key := range cfg.From {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	keyWg := sync.WaitGroup{}
+	dataLock := &sync.Mutex{}
+	keyWg.Add(len(keys))
+	for _, key := range keys {
+
+		key := key
+		go func() {
+			defer keyWg.Done()
+			itemContext := cfg.From[key]
+			var value []byte
+			var err error
+			if itemContext.Field != "" {
+				value, err = client.GetFieldOnItem(itemContext.Item, itemContext.Field)
+			} else if len(itemContext.DockerConfigJSONData) > 0 {
+				value, err = constructDockerConfigJSON(client, itemContext.DockerConfigJSONData)
+			}
+			if err != nil {
+				dataLock.Lock()
+				errs = append(errs, fmt.Errorf("config.%d.\"%s\": %w", idx, key, err))
+				dataLock.Unlock()
+				return
+			}
+			if cfg.From[key].Base64Decode {
+				decoded, err := base64.StdEncoding.DecodeString(string(value))
+				if err != nil {
+					dataLock.Lock()
+					errs = append(errs, fmt.Errorf(`failed to base64-decode config.%d."%s": %w`, idx, key, err))
+					dataLock.Unlock()
+					return
+				}
+				value = decoded
+			}
+			dataLock.Lock()
+			data[key] = value
+			dataLock.Unlock()
+
+		}()
+	}
+	// We copy the data map to not have multiple secrets with the same inner data map. This implies
+	// that we need to wait for that map to be fully populated.
+	keyWg.Wait()
+	return data, errs
+}
+
 func constructSecrets(config secretbootstrap.Config, client secrets.ReadOnlyClient, prowDisabledClusters sets.Set[string]) (map[string][]*coreapi.Secret, error) {
 	secretsByClusterAndName := map[string]map[types.NamespacedName]coreapi.Secret{}
 	secretsMapLock := &sync.Mutex{}
@@ -322,50 +474,10 @@ func constructSecrets(config secretbootstrap.Config, client secrets.ReadOnlyClie
 		go func() {
 			defer secretConfigWG.Done()
 
-			data := make(map[string][]byte)
-			var keys []string
-			for key := range cfg.From {
-				keys = append(keys, key)
-			}
-			sort.Strings(keys)
-
-			keyWg := sync.WaitGroup{}
-			dataLock := &sync.Mutex{}
-			keyWg.Add(len(keys))
-			for _, key := range keys {
-
-				key := key
-				go func() {
-					defer keyWg.Done()
-					itemContext := cfg.From[key]
-					var value []byte
-					var err error
-					if itemContext.Field != "" {
-						value, err = client.GetFieldOnItem(itemContext.Item, itemContext.Field)
-					} else if len(itemContext.DockerConfigJSONData) > 0 {
-						value, err = constructDockerConfigJSON(client, itemContext.DockerConfigJSONData)
-					}
-					if err != nil {
-						errChan <- fmt.Errorf("config.%d.\"%s\": %w", idx, key, err)
-						return
-					}
-					if cfg.From[key].Base64Decode {
-						decoded, err := base64.StdEncoding.DecodeString(string(value))
-						if err != nil {
-							errChan <- fmt.Errorf(`failed to base64-decode config.%d."%s": %w`, idx, key, err)
-							return
-						}
-						value = decoded
-					}
-					dataLock.Lock()
-					data[key] = value
-					dataLock.Unlock()
-
-				}()
+			data, errs := constructData(idx, cfg, client)
+			for _, err := range errs {
+				errChan <- err
 			}
-			// We copy the data map to not have multiple secrets with the same inner data map. This implies
-			// that we need to wait for that map to be fully populated.
-			keyWg.Wait()
 
 			for _, secretContext := range cfg.To {
 				if secretContext.Type == "" {
@@ -427,6 +539,19 @@ func constructSecrets(config secretbootstrap.Config, client secrets.ReadOnlyClie
 	return result, utilerrors.NewAggregate(errs)
 }
 
+// secretsInNamespace returns the secrets of the clusters that are in the namespace
+func secretsInNamespace(secretsMap map[string][]*coreapi.Secret, namespace string) map[string][]*coreapi.Secret {
+	filtered := map[string][]*coreapi.Secret{}
+	for cluster, secrets := range secretsMap {
+		for _, secret := range secrets {
+			if secret.Namespace == namespace {
+				filtered[cluster] = append(filtered[cluster], secret)
+			}
+		}
+	}
+	return filtered
+}
+
 func fetchUserSecrets(secretsMap map[string]map[types.NamespacedName]coreapi.Secret, secretStoreClient secrets.ReadOnlyClient, targetClusters []string) (map[string]map[types.NamespacedName]coreapi.Secret, error) {
 	if len(targetClusters) == 0 {
 		logrus.Warn("No target clusters for user secrets configured, skipping...")
@@ -734,7 +859,7 @@ func constructConfigItemsByName(config secretbootstrap.Config) map[string]*compa
 						}
 					}
 
-					item.fields = insertIfNotEmpty(item.fields, context.AuthField, context.EmailField)
+					item.fields = insertIfNotEmpty(item.fields, context.Fields()...)
 
 					cfgComparableItemsByName[context.Item] = item
 				}
@@ -843,11 +968,16 @@ func (o *options) validateItems(client secrets.ReadOnlyClient) error {
 						errs = append(errs, fmt.Errorf("item %s doesn't exist", data.Item))
 						break
 					}
-					if _, err := client.GetFieldOnItem(data.Item, data.AuthField); err != nil {
-						if o.generatorConfig.IsFieldGenerated(stripDPTPPrefixFromItem(data.Item, &o.config), data.AuthField) {
-							logger.WithField("field", data.AuthField).Warn("Field doesn't exist but it will be generated")
-						} else {
-							errs = append(errs, fmt.Errorf("field %s in item %s doesn't exist", data.AuthField, data.Item))
+					for _, field := range []string{data.AuthField, data.UsernameField, data.PasswordField} {
+						if field == "" {
+							continue
+						}
+						if _, err := client.GetFieldOnItem(data.Item, field); err != nil {
+							if o.generatorConfig.IsFieldGenerated(stripDPTPPrefixFromItem(data.Item, &o.config), field) {
+								logger.WithField("field", field).Warn("Field doesn't exist but it will be generated")
+							} else {
+								errs = append(errs, fmt.Errorf("field %s in item %s doesn't exist", field, data.Item))
+							}
 						}
 					}
 				}
@@ -915,7 +1045,7 @@ func main() {
 	if err := o.completeOptions(&censor, kubeconfigs, disabledClusters); err != nil {
 		logrus.WithError(err).Error("Failed to complete options.")
 	}
-	client, err := o.secrets.NewReadOnlyClient(&censor)
+	client, err := o.secrets.NewClient(&censor)
 	if err != nil {
 		logrus.WithError(err).Fatal("Failed to create client.")
 	}
@@ -925,7 +1055,7 @@ func main() {
 	}
 }
 
-func reconcileSecrets(o options, client secrets.ReadOnlyClient, prowDisabledClusters sets.Set[string]) (errs []error) {
+func reconcileSecrets(o options, client secrets.Client, prowDisabledClusters sets.Set[string]) (errs []error) {
 	if o.validateOnly {
 		var config secretbootstrap.Config
 		if err := secretbootstrap.LoadConfigFromFile(o.configPath, &config); err != nil {
@@ -944,9 +1074,13 @@ func reconcileSecrets(o options, client secrets.ReadOnlyClient, prowDisabledClus
 	}
 
 	// errors returned by constructSecrets will be handled once the rest of the secrets have been uploaded
-	secretsMap, err := constructSecrets(o.config, client, prowDisabledClusters)
-	if err != nil {
-		errs = append(errs, err)
+	secretsMap, constructErr := constructSecrets(o.config, client, prowDisabledClusters)
+	if constructErr != nil {
+		errs = append(errs, constructErr)
+	}
+	if o.namespace != "" {
+		// user secrets are not part of the config, so they are only filtered once constructed
+		secretsMap = secretsInNamespace(secretsMap, o.namespace)
 	}
 
 	if o.validateItemsUsage {
@@ -957,7 +1091,19 @@ func reconcileSecrets(o options, client secrets.ReadOnlyClient, prowDisabledClus
 		}
 	}
 
-	if o.dryRun {
+	if o.plan {
+		if err := planSecrets(o.secretsGetters, secretsMap, o.force, sets.New[string](o.config.OSDGlobalPullSecretGroup()...), os.Stdout); err != nil {
+			errs = append(errs, fmt.Errorf("failed to plan secrets: %w", err))
+		}
+	} else if o.audit {
+		report, err := auditSecrets(o.secretsGetters, secretsMap, sets.New[string](o.config.OSDGlobalPullSecretGroup()...))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to audit secrets: %w", err))
+		}
+		if err := writeAuditReport(report, o.auditFormat, os.Stdout); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write the audit report: %w", err))
+		}
+	} else if o.dryRun {
 		logrus.Infof("Running in dry-run mode")
 		if err := writeSecrets(secretsMap); err != nil {
 			errs = append(errs, fmt.Errorf("failed to write secrets on dry run: %w", err))
@@ -969,5 +1115,24 @@ func reconcileSecrets(o options, client secrets.ReadOnlyClient, prowDisabledClus
 		logrus.Info("Updated secrets.")
 	}
 
+	if !o.plan && !o.audit {
+		vaultItems, err := constructVaultItems(o.config, client)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to construct the items in Vault: %w", err))
+		}
+		if err := updateVaultItems(client, vaultItems, o.dryRun || !o.confirm); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update the items in Vault: %w", err))
+		}
+	}
+
+	if o.prune && !o.plan && !o.audit {
+		// a secret that failed to be constructed would look like an orphan
+		if constructErr != nil {
+			errs = append(errs, errors.New("not pruning secrets because not all secrets could be constructed"))
+		} else if _, err := pruneSecrets(o.secretsGetters, secretsMap, o.namespace, o.pruneProtectedNamespaces.StringSet(), o.dryRun || !o.confirm); err != nil {
+			errs = append(errs, fmt.Errorf("failed to prune secrets: %w", err))
+		}
+	}
+
 	return errs
 }
@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
+	"k8s.io/test-infra/prow/flagutil"
 
 	"github.com/openshift/ci-tools/pkg/api/secretbootstrap"
 	"github.com/openshift/ci-tools/pkg/api/secretgenerator"
@@ -50,6 +51,64 @@ func TestValidateOptions(t *testing.T) {
 			},
 			expected: fmt.Errorf("--config is required"),
 		},
+		{
+			name: "plan with validate-only",
+			given: options{
+				logLevel:     "info",
+				configPath:   "/tmp/config.yaml",
+				plan:         true,
+				validateOnly: true,
+				secrets: secrets.CLIOptions{
+					VaultAddr:      "https://vault.test",
+					VaultPrefix:    "prefix",
+					VaultTokenFile: "/tmp/vault-token",
+				},
+			},
+			expected: fmt.Errorf("--plan and --validate-only are mutually exclusive"),
+		},
+		{
+			name: "prune with secret names",
+			given: options{
+				logLevel:       "info",
+				configPath:     "/tmp/config.yaml",
+				prune:          true,
+				secretNamesRaw: flagutil.NewStrings("secret"),
+				secrets: secrets.CLIOptions{
+					VaultAddr:      "https://vault.test",
+					VaultPrefix:    "prefix",
+					VaultTokenFile: "/tmp/vault-token",
+				},
+			},
+			expected: fmt.Errorf("--prune and --secret-names are mutually exclusive"),
+		},
+		{
+			name: "protected namespaces without prune",
+			given: options{
+				logLevel:                 "info",
+				configPath:               "/tmp/config.yaml",
+				pruneProtectedNamespaces: flagutil.NewStrings("ci"),
+				secrets: secrets.CLIOptions{
+					VaultAddr:      "https://vault.test",
+					VaultPrefix:    "prefix",
+					VaultTokenFile: "/tmp/vault-token",
+				},
+			},
+			expected: fmt.Errorf("--prune-protected-namespace must be specified with --prune"),
+		},
+		{
+			name: "require generated items without a generator config",
+			given: options{
+				logLevel:              "info",
+				configPath:            "/tmp/config.yaml",
+				requireGeneratedItems: true,
+				secrets: secrets.CLIOptions{
+					VaultAddr:      "https://vault.test",
+					VaultPrefix:    "prefix",
+					VaultTokenFile: "/tmp/vault-token",
+				},
+			},
+			expected: fmt.Errorf("--require-generated-items must be specified with --generator-config"),
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -366,6 +425,16 @@ func TestCompleteOptions(t *testing.T) {
 			expectedConfig:   defaultConfigWithoutDefaultCluster,
 			expectedClusters: []string{"build01"},
 		},
+		{
+			name: "only configured namespace is used",
+			given: options{
+				logLevel:   "info",
+				configPath: configPath,
+				namespace:  "namespace-2",
+			},
+			expectedConfig:   defaultConfigWithoutDefaultCluster,
+			expectedClusters: []string{"build01"},
+		},
 		{
 			name: "group is resolved",
 			given: options{
@@ -769,6 +838,70 @@ func TestValidateCompletedOptions(t *testing.T) {
 			},
 			expected: fmt.Errorf("config[0].from[key-name-1]: auth_field is missing"),
 		},
+		{
+			name: "sad dockerconfigJSON configuration: auth and username",
+			given: options{
+				logLevel: "info",
+				config: secretbootstrap.Config{
+					Secrets: []secretbootstrap.SecretConfig{
+						{
+							From: map[string]secretbootstrap.ItemContext{
+								"key-name-1": {
+									DockerConfigJSONData: []secretbootstrap.DockerConfigJSONData{
+										{
+											Item:          "item-1",
+											RegistryURL:   "test.com",
+											AuthField:     "auth",
+											UsernameField: "username",
+											PasswordField: "password",
+										},
+									},
+								},
+							},
+							To: []secretbootstrap.SecretContext{
+								{
+									Cluster:   "default",
+									Name:      "docker-config-json-secret",
+									Namespace: "namespace-1",
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: fmt.Errorf("config[0].from[key-name-1]: auth_field is mutually exclusive with username_field and password_field"),
+		},
+		{
+			name: "sad dockerconfigJSON configuration: username without password",
+			given: options{
+				logLevel: "info",
+				config: secretbootstrap.Config{
+					Secrets: []secretbootstrap.SecretConfig{
+						{
+							From: map[string]secretbootstrap.ItemContext{
+								"key-name-1": {
+									DockerConfigJSONData: []secretbootstrap.DockerConfigJSONData{
+										{
+											Item:          "item-1",
+											RegistryURL:   "test.com",
+											UsernameField: "username",
+										},
+									},
+								},
+							},
+							To: []secretbootstrap.SecretContext{
+								{
+									Cluster:   "default",
+									Name:      "docker-config-json-secret",
+									Namespace: "namespace-1",
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: fmt.Errorf("config[0].from[key-name-1]: username_field and password_field must be set together"),
+		},
 		{
 			name: "sad dockerconfigJSON configuration: cannot determine registry URL",
 			given: options{
@@ -800,6 +933,82 @@ func TestValidateCompletedOptions(t *testing.T) {
 			},
 			expected: fmt.Errorf("config[0].from[key-name-1]: registry_url must be set"),
 		},
+		{
+			name: "secret only synced to vault",
+			given: options{
+				logLevel: "info",
+				config: secretbootstrap.Config{
+					Secrets: []secretbootstrap.SecretConfig{{
+						From:    map[string]secretbootstrap.ItemContext{"key-name-1": {Item: "item-name-1", Field: "field-name-1"}},
+						ToVault: []secretbootstrap.VaultContext{{Path: "consumers/a"}},
+					}},
+				},
+			},
+		},
+		{
+			name: "vault target without a path",
+			given: options{
+				logLevel: "info",
+				config: secretbootstrap.Config{
+					Secrets: []secretbootstrap.SecretConfig{{
+						From:    map[string]secretbootstrap.ItemContext{"key-name-1": {Item: "item-name-1", Field: "field-name-1"}},
+						ToVault: []secretbootstrap.VaultContext{{}},
+					}},
+				},
+			},
+			expected: fmt.Errorf("config[0].to_vault[0].path: empty value is not allowed"),
+		},
+		{
+			name: "vault target is a source item",
+			given: options{
+				logLevel: "info",
+				config: secretbootstrap.Config{
+					Secrets: []secretbootstrap.SecretConfig{
+						{
+							From:    map[string]secretbootstrap.ItemContext{"key-name-1": {Item: "item-name-1", Field: "field-name-1"}},
+							ToVault: []secretbootstrap.VaultContext{{Path: "consumers/a"}},
+						},
+						{
+							From:    map[string]secretbootstrap.ItemContext{"key-name-1": {Item: "consumers/a", Field: "key-name-1"}},
+							ToVault: []secretbootstrap.VaultContext{{Path: "consumers/b"}},
+						},
+					},
+				},
+			},
+			expected: fmt.Errorf("config[0].to_vault[0]: item consumers/a is a source of secrets and cannot be synced to"),
+		},
+		{
+			name: "key of a vault target synced to more than once",
+			given: options{
+				logLevel: "info",
+				config: secretbootstrap.Config{
+					Secrets: []secretbootstrap.SecretConfig{
+						{
+							From:    map[string]secretbootstrap.ItemContext{"key-name-1": {Item: "item-name-1", Field: "field-name-1"}},
+							ToVault: []secretbootstrap.VaultContext{{Path: "consumers/a"}},
+						},
+						{
+							From:    map[string]secretbootstrap.ItemContext{"key-name-1": {Item: "item-name-1", Field: "field-name-2"}},
+							ToVault: []secretbootstrap.VaultContext{{Path: "consumers/a"}},
+						},
+					},
+				},
+			},
+			expected: fmt.Errorf("config[1].to_vault[0]: key key-name-1 of item consumers/a is synced to more than once"),
+		},
+		{
+			name: "fields that are not generated",
+			given: options{
+				logLevel:              "info",
+				requireGeneratedItems: true,
+				config:                defaultConfig,
+				generatorConfig: secretgenerator.Config{
+					{ItemName: "item-name-1", Fields: []secretgenerator.FieldGenerator{{Name: "field-name-1"}, {Name: "field-name-2"}, {Name: "field-name-3"}}},
+					{ItemName: "item-name-2", Fields: []secretgenerator.FieldGenerator{{Name: "field-name-1"}, {Name: "field-name-2"}}},
+				},
+			},
+			expected: fmt.Errorf("failed to validate the config against the generator config: [secrets.0.from.key-name-6: field field-name-1 of item item-name-3 is not generated and the item is not external, secrets.1.from..dockerconfigjson: field pull-credentials of item quay.io is not generated and the item is not external]"),
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1857,6 +2066,42 @@ func TestConstructDockerConfigJSON(t *testing.T) {
 			},
 			expectedJSON: []byte(`{"auths":{"cloud.redhat.com":{"auth":"c2VydmljZWFjY291bnQ6ZXlKaGJHY2lPaUpTVXpJMU5pSXNJbXRwWkNJNklrRndTekF0YjBaNGJXMUZURXRHTVMwMFVEa3djbEEwUTJWQlRUZERNMGRXUkZwdmJGOVllaTFEUW5NaWZRLmV5SnBjM01pT2lKcmRXSmxjbTVsZEdWekwzTmxjblpwWTJWaFkyTnZkVzUwSWl3aWEzVmlaWEp1WlhSbGN5NXBieTl6WlhKMmFXTmxZV05qYjNWdWRDOXVZVzFsYzNCaFkyVWlPaUpoYkhaaGNtOHRkR1Z6ZENJc0ltdDFZbVZ5Ym1WMFpYTXVhVzh2YzJWeWRtbGpaV0ZqWTI5MWJuUXZjMlZqY21WMExtNWhiV1VpT2lKa1pXWmhkV3gwTFhSdmEyVnVMV1EwT1d4aUlpd2lhM1ZpWlhKdVpYUmxjeTVwYnk5elpYSjJhV05sWVdOamIzVnVkQzl6WlhKMmFXTmxMV0ZqWTI5MWJuUXVibUZ0WlNJNkltUmxabUYxYkhRaUxDSnJkV0psY201bGRHVnpMbWx2TDNObGNuWnBZMlZoWTJOdmRXNTBMM05sY25acFkyVXRZV05qYjNWdWRDNTFhV1FpT2lJM05tVTRZMlpsTmkxbU1HWXhMVFF5WlRNdFlqUm1NQzFoTXpjM1pUbGhOemxrWWpRaUxDSnpkV0lpT2lKemVYTjBaVzA2YzJWeWRtbGpaV0ZqWTI5MWJuUTZZV3gyWVhKdkxYUmxjM1E2WkdWbVlYVnNkQ0o5LnMyajh6X2JfT3NMOHY5UGlLR1NUQmFuZDE0MHExMHc3VTlMdU9JWmZlUG1SeF9OMHdKRkZPcVN0MGNjdmtVaUVGV0x5QWNSU2k2cUt3T1FSVzE2MVUzSU52UEY4Q0pDZ2d2R3JHUnMzeHp6N3hjSmgzTWRpcXhzWGViTmNmQmlmWWxXUTU2U1RTZDlUeUh1RkN6c1poNXBlSHVzS3hOa2hJRTNyWHp5ZHNoMkhCaTZMYTlYZ1l4R1VjM0x3NWh4RnB5bXFyajFJNzExbWZLcUV2bUN0a0J4blJtMlhIZmFKalNVRkswWWdoY0lMbkhuWGhMOEx2MUl0bnU4SzlvWFRfWVZIQWY1R3hlaERjZ3FBMmw1NUZyYkJMTGVfNi1DV2V2N2RQZU5PbFlaWE5xbEtkUG5KbW9BREdsOEktTlhKN2x5ZXl2a2hfZ3JkanhXdVVqQ3lQUQ==","email":"foo@bar.com"},"quay.io":{"auth":"c2VydmljZWFjY291bnQ6ZXlKaGJHY2lPaUpTVXpJMU5pSXNJbXRwWkNJNklrRndTekF0YjBaNGJXMUZURXRHTVMwMFVEa3djbEEwUTJWQlRUZERNMGRXUkZwdmJGOVllaTFEUW5NaWZRLmV5SnBjM01pT2lKcmRXSmxjbTVsZEdWekwzTmxjblpwWTJWaFkyTnZkVzUwSWl3aWEzVmlaWEp1WlhSbGN5NXBieTl6WlhKMmFXTmxZV05qYjNWdWRDOXVZVzFsYzNCaFkyVWlPaUpoYkhaaGNtOHRkR1Z6ZENJc0ltdDFZbVZ5Ym1WMFpYTXVhVzh2YzJWeWRtbGpaV0ZqWTI5MWJuUXZjMlZqY21WMExtNWhiV1VpT2lKa1pXWmhkV3gwTFhSdmEyVnVMV1EwT1d4aUlpd2lhM1ZpWlhKdVpYUmxjeTVwYnk5elpYSjJhV05sWVdOamIzVnVkQzl6WlhKMmFXTmxMV0ZqWTI5MWJuUXVibUZ0WlNJNkltUmxabUYxYkhRaUxDSnJkV0psY201bGRHVnpMbWx2TDNObGNuWnBZMlZoWTJOdmRXNTBMM05sY25acFkyVXRZV05qYjNWdWRDNTFhV1FpT2lJM05tVTRZMlpsTmkxbU1HWXhMVFF5WlRNdFlqUm1NQzFoTXpjM1pUbGhOemxrWWpRaUxDSnpkV0lpT2lKemVYTjBaVzA2YzJWeWRtbGpaV0ZqWTI5MWJuUTZZV3gyWVhKdkxYUmxjM1E2WkdWbVlYVnNkQ0o5LnMyajh6X2JfT3NMOHY5UGlLR1NUQmFuZDE0MHExMHc3VTlMdU9JWmZlUG1SeF9OMHdKRkZPcVN0MGNjdmtVaUVGV0x5QWNSU2k2cUt3T1FSVzE2MVUzSU52UEY4Q0pDZ2d2R3JHUnMzeHp6N3hjSmgzTWRpcXhzWGViTmNmQmlmWWxXUTU2U1RTZDlUeUh1RkN6c1poNXBlSHVzS3hOa2hJRTNyWHp5ZHNoMkhCaTZMYTlYZ1l4R1VjM0x3NWh4RnB5bXFyajFJNzExbWZLcUV2bUN0a0J4blJtMlhIZmFKalNVRkswWWdoY0lMbkhuWGhMOEx2MUl0bnU4SzlvWFRfWVZIQWY1R3hlaERjZ3FBMmw1NUZyYkJMTGVfNi1DV2V2N2RQZU5PbFlaWE5xbEtkUG5KbW9BREdsOEktTlhKN2x5ZXl2a2hfZ3JkanhXdVVqQ3lQUQ==","email":"test@test.com"}}}`),
 		},
+		{
+			id: "credentials of several registries",
+			dockerConfigJSONData: []secretbootstrap.DockerConfigJSONData{
+				{
+					Item:          "item-name-1",
+					RegistryURL:   "quay.io",
+					UsernameField: "username",
+					PasswordField: "password",
+				},
+				{
+					Item:        "item-name-2",
+					RegistryURL: "registry.redhat.io",
+					AuthField:   "auth",
+				},
+			},
+			items: map[string]vaultclient.KVData{
+				"item-name-1": {Data: map[string]string{"username": "user\n", "password": "secret"}},
+				"item-name-2": {Data: map[string]string{"auth": "b3RoZXI6c2VjcmV0"}},
+			},
+			expectedJSON: []byte(`{"auths":{"quay.io":{"auth":"dXNlcjpzZWNyZXQ="},"registry.redhat.io":{"auth":"b3RoZXI6c2VjcmV0"}}}`),
+		},
+		{
+			id: "sad case, password field is missing",
+			dockerConfigJSONData: []secretbootstrap.DockerConfigJSONData{
+				{
+					Item:          "item-name-1",
+					RegistryURL:   "quay.io",
+					UsernameField: "username",
+					PasswordField: "password",
+				},
+			},
+			items: map[string]vaultclient.KVData{
+				"item-name-1": {Data: map[string]string{"username": "user"}},
+			},
+			expectedError: `couldn't get password field 'password' from item item-name-1: item at path "prefix/item-name-1" has no key "password"`,
+		},
 		{
 			id: "sad case, field is missing",
 			dockerConfigJSONData: []secretbootstrap.DockerConfigJSONData{
@@ -2210,7 +2455,8 @@ func (f *fakeVaultClient) ListKVRecursively(prefix string) ([]string, error) {
 	return result, nil
 }
 
-func (f *fakeVaultClient) UpsertKV(_ string, _ map[string]string) error {
+func (f *fakeVaultClient) UpsertKV(path string, data map[string]string) error {
+	f.items[path] = &vaultclient.KVData{Data: data}
 	return nil
 }
 
@@ -2904,15 +3150,15 @@ func TestIntegration(t *testing.T) {
 				}
 			}
 
-			readOnlyClient, err := o.secrets.NewReadOnlyClient(&censor)
+			client, err := o.secrets.NewClient(&censor)
 			if err != nil {
-				t.Fatal("Failed to create a read only client.")
+				t.Fatal("Failed to create a client.")
 			}
 
 			actualSecretsByCluster := make(map[string][]coreapi.Secret)
 
 			// Create Case
-			errs := reconcileSecrets(o, readOnlyClient, tc.disabledClusters)
+			errs := reconcileSecrets(o, client, tc.disabledClusters)
 			if tc.expectedError != nil {
 				if len(errs) == 0 {
 					t.Fatal("expected errors but got nothing")
@@ -2953,7 +3199,7 @@ func TestIntegration(t *testing.T) {
 				}
 			}
 
-			errs = reconcileSecrets(o, readOnlyClient, tc.disabledClusters)
+			errs = reconcileSecrets(o, client, tc.disabledClusters)
 			if tc.expectedError != nil {
 				if len(errs) == 0 {
 					t.Fatal("expected errors but got nothing")
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	coreapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/ci-tools/pkg/api"
+)
+
+const (
+	planCreate    = "create"
+	planUpdate    = "update"
+	planReplace   = "replace"
+	planUnchanged = "unchanged"
+)
+
+// secretPlan is what syncing would do to a secret. It only holds the names of the keys, so
+// that printing it never reveals a value.
+type secretPlan struct {
+	name   string
+	action string
+	// keys are the keys of a secret that would be created
+	keys    []string
+	added   []string
+	removed []string
+	changed []string
+	// existingType and expectedType are the types of a secret that would be replaced
+	existingType coreapi.SecretType
+	expectedType coreapi.SecretType
+	// needsForce is set when the change is only made with --force
+	needsForce bool
+}
+
+func (p secretPlan) String() string {
+	var details []string
+	switch p.action {
+	case planCreate:
+		details = append(details, "keys "+strings.Join(p.keys, ", "))
+	case planReplace:
+		details = append(details, fmt.Sprintf("type %s to %s", p.existingType, p.expectedType))
+	}
+	for _, keys := range []struct {
+		what  string
+		names []string
+	}{{what: "added", names: p.added}, {what: "removed", names: p.removed}, {what: "changed", names: p.changed}} {
+		if len(keys.names) > 0 {
+			details = append(details, fmt.Sprintf("%s keys %s", keys.what, strings.Join(keys.names, ", ")))
+		}
+	}
+	if p.action == planUpdate && len(details) == 0 {
+		details = append(details, "metadata only")
+	}
+	if len(details) == 0 {
+		return fmt.Sprintf("%s %s", p.action, p.name)
+	}
+	line := fmt.Sprintf("%s %s: %s", p.action, p.name, strings.Join(details, "; "))
+	if p.needsForce {
+		line += " (requires --force)"
+	}
+	return line
+}
+
+// planSecrets prints, for every cluster and namespace, which secrets syncing would create,
+// update or replace, and which it would leave alone. Values are never printed, updates
+// only list the keys that would be added, removed or changed.
+func planSecrets(getters map[string]Getter, secretsMap map[string][]*coreapi.Secret, force bool, osdGlobalPullSecretGroup sets.Set[string], out io.Writer) error {
+	var errs []error
+	var clusters []string
+	for cluster := range secretsMap {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	for _, cluster := range clusters {
+		secrets := append([]*coreapi.Secret(nil), secretsMap[cluster]...)
+		sort.Slice(secrets, func(i, j int) bool {
+			if secrets[i].Namespace != secrets[j].Namespace {
+				return secrets[i].Namespace < secrets[j].Namespace
+			}
+			return secrets[i].Name < secrets[j].Name
+		})
+		var namespace string
+		for _, secret := range secrets {
+			plan, err := planSecret(getters[cluster], secret, osdGlobalPullSecretGroup.Has(cluster))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to plan secret %s:%s/%s: %w", cluster, secret.Namespace, secret.Name, err))
+				continue
+			}
+			plan.needsForce = plan.needsForce && !force
+			if secret.Namespace != namespace {
+				namespace = secret.Namespace
+				if _, err := fmt.Fprintf(out, "cluster %s, namespace %s:\n", cluster, namespace); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintf(out, "  %s\n", plan); err != nil {
+				return err
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// planSecret compares a secret with the one in the cluster like updateSecrets does
+func planSecret(getter Getter, secret *coreapi.Secret, isOSDGlobalPullSecret bool) (secretPlan, error) {
+	plan := secretPlan{name: secret.Name}
+	existing, err := getter.Secrets(secret.Namespace).Get(context.TODO(), secret.Name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		plan.action = planCreate
+		plan.keys = sets.List(sets.KeySet(secret.Data))
+		return plan, nil
+	}
+	if err != nil {
+		return plan, err
+	}
+
+	if isOSDGlobalPullSecret && secret.Namespace == "openshift-config" && secret.Name == "pull-secret" {
+		mutated, err := mutateGlobalPullSecret(existing.DeepCopy(), secret)
+		if err != nil {
+			return plan, err
+		}
+		plan.action = planUnchanged
+		if mutated {
+			plan.action = planUpdate
+			plan.changed = []string{coreapi.DockerConfigJsonKey}
+		}
+		return plan, nil
+	}
+
+	expectedKeys, existingKeys := sets.KeySet(secret.Data), sets.KeySet(existing.Data)
+	plan.added = sets.List(expectedKeys.Difference(existingKeys))
+	plan.removed = sets.List(existingKeys.Difference(expectedKeys))
+	for _, key := range sets.List(expectedKeys.Intersection(existingKeys)) {
+		if !equality.Semantic.DeepEqual(secret.Data[key], existing.Data[key]) {
+			plan.changed = append(plan.changed, key)
+		}
+	}
+	differentData := len(plan.added) > 0 || len(plan.removed) > 0 || len(plan.changed) > 0
+	switch {
+	case secret.Type != existing.Type:
+		plan.action = planReplace
+		plan.existingType, plan.expectedType = existing.Type, secret.Type
+		plan.needsForce = true
+	case differentData || existing.Labels[api.DPTPRequesterLabel] != "ci-secret-bootstrap":
+		plan.action = planUpdate
+		plan.needsForce = differentData
+	default:
+		plan.action = planUnchanged
+	}
+	return plan, nil
+}
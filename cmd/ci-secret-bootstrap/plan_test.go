@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	coreapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPlanSecrets(t *testing.T) {
+	managed := map[string]string{"dptp.openshift.io/requester": "ci-secret-bootstrap"}
+	existing := []runtime.Object{
+		&coreapi.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "unchanged", Labels: managed},
+			Data:       map[string][]byte{"key": []byte("value")},
+			Type:       coreapi.SecretTypeOpaque,
+		},
+		&coreapi.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "updated", Labels: managed},
+			Data:       map[string][]byte{"changed": []byte("old"), "removed": []byte("value"), "same": []byte("value")},
+			Type:       coreapi.SecretTypeOpaque,
+		},
+		&coreapi.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "unlabeled"},
+			Data:       map[string][]byte{"key": []byte("value")},
+			Type:       coreapi.SecretTypeOpaque,
+		},
+		&coreapi.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test-credentials", Name: "retyped", Labels: managed},
+			Data:       map[string][]byte{".dockerconfigjson": []byte("{}")},
+			Type:       coreapi.SecretTypeOpaque,
+		},
+	}
+	secretsMap := map[string][]*coreapi.Secret{
+		"build01": {
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test-credentials", Name: "retyped"},
+				Data:       map[string][]byte{".dockerconfigjson": []byte("{}")},
+				Type:       coreapi.SecretTypeDockerConfigJson,
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "updated"},
+				Data:       map[string][]byte{"added": []byte("value"), "changed": []byte("new"), "same": []byte("value")},
+				Type:       coreapi.SecretTypeOpaque,
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "unchanged"},
+				Data:       map[string][]byte{"key": []byte("value")},
+				Type:       coreapi.SecretTypeOpaque,
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "unlabeled"},
+				Data:       map[string][]byte{"key": []byte("value")},
+				Type:       coreapi.SecretTypeOpaque,
+			},
+		},
+		"app.ci": {
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "new-namespace", Name: "created"},
+				Data:       map[string][]byte{"b": []byte("value"), "a": []byte("value")},
+				Type:       coreapi.SecretTypeOpaque,
+			},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		force    bool
+		expected string
+	}{
+		{
+			name: "changes of data require --force",
+			expected: `cluster app.ci, namespace new-namespace:
+  create created: keys a, b
+cluster build01, namespace ci:
+  unchanged unchanged
+  update unlabeled: metadata only
+  update updated: added keys added; removed keys removed; changed keys changed (requires --force)
+cluster build01, namespace test-credentials:
+  replace retyped: type Opaque to kubernetes.io/dockerconfigjson (requires --force)
+`,
+		},
+		{
+			name:  "forced",
+			force: true,
+			expected: `cluster app.ci, namespace new-namespace:
+  create created: keys a, b
+cluster build01, namespace ci:
+  unchanged unchanged
+  update unlabeled: metadata only
+  update updated: added keys added; removed keys removed; changed keys changed
+cluster build01, namespace test-credentials:
+  replace retyped: type Opaque to kubernetes.io/dockerconfigjson
+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			build01 := fake.NewSimpleClientset(existing...)
+			getters := map[string]Getter{
+				"build01": build01.CoreV1(),
+				"app.ci":  fake.NewSimpleClientset().CoreV1(),
+			}
+			out := &bytes.Buffer{}
+			if err := planSecrets(getters, secretsMap, tc.force, sets.New[string](), out); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, out.String()); diff != "" {
+				t.Errorf("plan differs from expected: %s", diff)
+			}
+			if actions := build01.Actions(); len(actions) != 4 {
+				t.Errorf("expected only the secrets to be read, got %d actions", len(actions))
+			}
+		})
+	}
+}
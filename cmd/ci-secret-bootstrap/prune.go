@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+
+	coreapi "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/ci-tools/pkg/api"
+)
+
+// pruneSecrets deletes the secrets that carry the label of the tool in the clusters but are no
+// longer part of the config, except for the ones in the protected namespaces. Only the given
+// namespace is pruned, unless it is empty. It returns the secrets that are pruned, per cluster.
+// When dryRun is set, nothing is deleted.
+func pruneSecrets(getters map[string]Getter, secretsMap map[string][]*coreapi.Secret, namespace string, protectedNamespaces sets.Set[string], dryRun bool) (map[string][]types.NamespacedName, error) {
+	var errs []error
+	pruned := map[string][]types.NamespacedName{}
+	var clusters []string
+	for cluster := range getters {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	for _, cluster := range clusters {
+		logger := logrus.WithField("cluster", cluster)
+		expected := sets.New[types.NamespacedName]()
+		for _, secret := range secretsMap[cluster] {
+			expected.Insert(types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name})
+		}
+		managed, err := getters[cluster].Secrets(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: api.DPTPRequesterLabel + "=ci-secret-bootstrap"})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list the secrets in cluster %s: %w", cluster, err))
+			continue
+		}
+		for _, secret := range managed.Items {
+			name := types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}
+			if expected.Has(name) {
+				continue
+			}
+			logger := logger.WithFields(logrus.Fields{"namespace": secret.Namespace, "name": secret.Name})
+			if protectedNamespaces.Has(secret.Namespace) {
+				logger.Info("Not pruning secret in a protected namespace")
+				continue
+			}
+			if dryRun {
+				logger.Info("Secret would be pruned")
+			} else {
+				if err := getters[cluster].Secrets(secret.Namespace).Delete(context.TODO(), secret.Name, metav1.DeleteOptions{}); err != nil && !kerrors.IsNotFound(err) {
+					errs = append(errs, fmt.Errorf("failed to prune secret %s:%s/%s: %w", cluster, secret.Namespace, secret.Name, err))
+					continue
+				}
+				logger.Info("Secret pruned")
+			}
+			pruned[cluster] = append(pruned[cluster], name)
+		}
+	}
+	return pruned, utilerrors.NewAggregate(errs)
+}
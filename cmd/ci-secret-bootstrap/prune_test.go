@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	coreapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPruneSecrets(t *testing.T) {
+	managed := map[string]string{"dptp.openshift.io/requester": "ci-secret-bootstrap"}
+	existing := []runtime.Object{
+		&coreapi.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "configured", Labels: managed}},
+		&coreapi.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "orphaned", Labels: managed}},
+		&coreapi.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "unmanaged"}},
+		&coreapi.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "protected", Name: "orphaned", Labels: managed}},
+	}
+	secretsMap := map[string][]*coreapi.Secret{
+		"build01": {{ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "configured"}}},
+	}
+
+	testCases := []struct {
+		name            string
+		namespace       string
+		dryRun          bool
+		expectedPruned  map[string][]types.NamespacedName
+		expectedSecrets []string
+	}{
+		{
+			name:            "orphaned secrets are deleted",
+			expectedPruned:  map[string][]types.NamespacedName{"build01": {{Namespace: "ci", Name: "orphaned"}}},
+			expectedSecrets: []string{"ci/configured", "ci/unmanaged", "protected/orphaned"},
+		},
+		{
+			name:            "nothing is deleted when running dry",
+			dryRun:          true,
+			expectedPruned:  map[string][]types.NamespacedName{"build01": {{Namespace: "ci", Name: "orphaned"}}},
+			expectedSecrets: []string{"ci/configured", "ci/orphaned", "ci/unmanaged", "protected/orphaned"},
+		},
+		{
+			name:            "only the namespace is pruned",
+			namespace:       "other",
+			expectedPruned:  map[string][]types.NamespacedName{},
+			expectedSecrets: []string{"ci/configured", "ci/orphaned", "ci/unmanaged", "protected/orphaned"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(existing...)
+			getters := map[string]Getter{"build01": client.CoreV1()}
+			pruned, err := pruneSecrets(getters, secretsMap, tc.namespace, sets.New[string]("protected"), tc.dryRun)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedPruned, pruned); diff != "" {
+				t.Errorf("pruned secrets differ from expected: %s", diff)
+			}
+			secrets, err := client.CoreV1().Secrets(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("failed to list secrets: %v", err)
+			}
+			var names []string
+			for _, secret := range secrets.Items {
+				names = append(names, secret.Namespace+"/"+secret.Name)
+			}
+			if diff := cmp.Diff(tc.expectedSecrets, names); diff != "" {
+				t.Errorf("secrets differ from expected: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/openshift/ci-tools/pkg/api/secretbootstrap"
+	"github.com/openshift/ci-tools/pkg/secrets"
+	"github.com/openshift/ci-tools/pkg/vaultclient"
+)
+
+// constructVaultItems fetches the data of the secret configs that are synced to Vault, by the
+// path of the items they are synced to
+func constructVaultItems(config secretbootstrap.Config, client secrets.ReadOnlyClient) (map[string]map[string][]byte, error) {
+	var errs []error
+	items := map[string]map[string][]byte{}
+	for idx, cfg := range config.Secrets {
+		if len(cfg.ToVault) == 0 {
+			continue
+		}
+		data, dataErrs := constructData(idx, cfg, client)
+		if len(dataErrs) > 0 {
+			errs = append(errs, dataErrs...)
+			continue
+		}
+		for _, vaultContext := range cfg.ToVault {
+			if items[vaultContext.Path] == nil {
+				items[vaultContext.Path] = map[string][]byte{}
+			}
+			for key, value := range data {
+				items[vaultContext.Path][key] = value
+			}
+		}
+	}
+	return items, utilerrors.NewAggregate(errs)
+}
+
+// updateVaultItems uploads the keys of the items in Vault that differ from the expected
+// ones. Keys of the items that are not part of the config are left alone. When dryRun is
+// set, nothing is uploaded.
+func updateVaultItems(client secrets.Client, items map[string]map[string][]byte, dryRun bool) error {
+	var errs []error
+	var paths []string
+	for path := range items {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		var keys []string
+		for key := range items[path] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			logger := logrus.WithFields(logrus.Fields{"item": path, "key": key})
+			value := items[path][key]
+			current, err := client.GetFieldOnItem(path, key)
+			if err != nil && !vaultclient.IsNotFound(err) && !secrets.IsKeyNotFound(err) {
+				errs = append(errs, fmt.Errorf("failed to get key %s of item %s: %w", key, path, err))
+				continue
+			}
+			if err == nil && bytes.Equal(current, value) {
+				logger.Debug("key of the item in Vault is up to date")
+				continue
+			}
+			if dryRun {
+				logger.Info("Key of the item in Vault would be updated")
+				continue
+			}
+			if err := client.SetFieldOnItem(path, key, value); err != nil {
+				errs = append(errs, fmt.Errorf("failed to update key %s of item %s: %w", key, path, err))
+				continue
+			}
+			logger.Debug("key of the item in Vault updated")
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/openshift/ci-tools/pkg/api/secretbootstrap"
+	"github.com/openshift/ci-tools/pkg/secrets"
+	"github.com/openshift/ci-tools/pkg/testhelper"
+	"github.com/openshift/ci-tools/pkg/vaultclient"
+)
+
+func TestConstructVaultItems(t *testing.T) {
+	client := vaultClientFromTestItems(map[string]vaultclient.KVData{
+		"item-name-1": {Data: map[string]string{"field-name-1": "value-1", "field-name-2": "dmFsdWUtMg=="}},
+	})
+	config := secretbootstrap.Config{
+		Secrets: []secretbootstrap.SecretConfig{
+			{
+				From: map[string]secretbootstrap.ItemContext{
+					"key-name-1": {Item: "item-name-1", Field: "field-name-1"},
+					"key-name-2": {Item: "item-name-1", Field: "field-name-2", Base64Decode: true},
+				},
+				ToVault: []secretbootstrap.VaultContext{{Path: "consumers/a"}, {Path: "consumers/b"}},
+			},
+			{
+				From: map[string]secretbootstrap.ItemContext{"key-name-3": {Item: "item-name-1", Field: "field-name-1"}},
+				To:   []secretbootstrap.SecretContext{{Cluster: "build01", Namespace: "ci", Name: "not-synced-to-vault"}},
+			},
+			{
+				From:    map[string]secretbootstrap.ItemContext{"key-name-4": {Item: "item-name-1", Field: "field-name-1"}},
+				ToVault: []secretbootstrap.VaultContext{{Path: "consumers/a"}},
+			},
+			{
+				From:    map[string]secretbootstrap.ItemContext{"key-name-5": {Item: "item-name-1", Field: "missing"}},
+				ToVault: []secretbootstrap.VaultContext{{Path: "consumers/c"}},
+			},
+		},
+	}
+	items, err := constructVaultItems(config, client)
+	if diff := cmp.Diff(errors.New(`config.3."key-name-5": item at path "prefix/item-name-1" has no key "missing"`), err, testhelper.EquateErrorMessage); diff != "" {
+		t.Errorf("error differs from expected: %s", diff)
+	}
+	expected := map[string]map[string][]byte{
+		"consumers/a": {"key-name-1": []byte("value-1"), "key-name-2": []byte("value-2"), "key-name-4": []byte("value-1")},
+		"consumers/b": {"key-name-1": []byte("value-1"), "key-name-2": []byte("value-2")},
+	}
+	if diff := cmp.Diff(expected, items); diff != "" {
+		t.Errorf("items differ from expected: %s", diff)
+	}
+}
+
+func TestUpdateVaultItems(t *testing.T) {
+	items := map[string]map[string][]byte{
+		"consumers/a": {"up-to-date": []byte("value"), "changed": []byte("new")},
+		"consumers/b": {"key": []byte("value")},
+	}
+	testCases := []struct {
+		name     string
+		dryRun   bool
+		expected map[string]*vaultclient.KVData
+	}{
+		{
+			name: "keys that differ are updated",
+			expected: map[string]*vaultclient.KVData{
+				"prefix/consumers/a": {Data: map[string]string{"up-to-date": "value", "changed": "new", "other": "value"}},
+				"prefix/consumers/b": {Data: map[string]string{"key": "value"}},
+			},
+		},
+		{
+			name:   "nothing is updated when running dry",
+			dryRun: true,
+			expected: map[string]*vaultclient.KVData{
+				"prefix/consumers/a": {Data: map[string]string{"up-to-date": "value", "changed": "old", "other": "value"}},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			upstream := &fakeVaultClient{items: map[string]*vaultclient.KVData{
+				"prefix/consumers/a": {Data: map[string]string{"up-to-date": "value", "changed": "old", "other": "value"}},
+			}}
+			censor := secrets.NewDynamicCensor()
+			if err := updateVaultItems(secrets.NewVaultClient(upstream, "prefix", &censor), items, tc.dryRun); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, upstream.items); diff != "" {
+				t.Errorf("items differ from expected: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/ci-tools/pkg/api/secretgenerator"
+)
+
+// checkpoint records the items that were generated successfully, so that an interrupted
+// or failed run can be resumed without generating them again
+type checkpoint struct {
+	FinishedItems []string `json:"finished_items"`
+}
+
+func loadCheckpoint(path string) (sets.Set[string], error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the checkpoint: %w", err)
+	}
+	var c checkpoint
+	if err := yaml.UnmarshalStrict(raw, &c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the checkpoint: %w", err)
+	}
+	return sets.New[string](c.FinishedItems...), nil
+}
+
+// writeCheckpoint writes the items that succeeded in the report, along with the items that
+// were already finished before the run
+func writeCheckpoint(path string, report *runReport, finished sets.Set[string]) error {
+	succeeded, _ := report.itemResults()
+	c := checkpoint{FinishedItems: sets.List(finished.Clone().Insert(succeeded...))}
+	raw, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the checkpoint: %w", err)
+	}
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		return fmt.Errorf("failed to write the checkpoint: %w", err)
+	}
+	return nil
+}
+
+// skipFinishedItems returns the items of the config that are not finished yet
+func skipFinishedItems(config secretgenerator.Config, finished sets.Set[string]) secretgenerator.Config {
+	var remaining secretgenerator.Config
+	for _, item := range config {
+		if !finished.Has(item.ItemName) {
+			remaining = append(remaining, item)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/ci-tools/pkg/api/secretgenerator"
+	"github.com/openshift/ci-tools/pkg/secretstore"
+)
+
+func TestCheckpoint(t *testing.T) {
+	report := newRunReport(time.Now())
+	report.add("succeeded", "field", "", time.Second, true, "", nil)
+	report.add("failed", "field", "", time.Second, false, "failed to generate field", context.Canceled)
+	path := filepath.Join(t.TempDir(), "checkpoint.yaml")
+	if err := writeCheckpoint(path, report, sets.New[string]("previous")); err != nil {
+		t.Fatalf("failed to write the checkpoint: %v", err)
+	}
+	finished, err := loadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("failed to load the checkpoint: %v", err)
+	}
+	if diff := cmp.Diff([]string{"previous", "succeeded"}, sets.List(finished)); diff != "" {
+		t.Errorf("finished items differ from expected: %s", diff)
+	}
+
+	config := secretgenerator.Config{{ItemName: "previous"}, {ItemName: "failed"}, {ItemName: "succeeded"}, {ItemName: "new"}}
+	if diff := cmp.Diff(secretgenerator.Config{{ItemName: "failed"}, {ItemName: "new"}}, skipFinishedItems(config, finished)); diff != "" {
+		t.Errorf("remaining items differ from expected: %s", diff)
+	}
+}
+
+func TestUpdateSecretsInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client := secretstore.NewFakeClient()
+	config := secretgenerator.Config{
+		{ItemName: "a", Fields: []secretgenerator.FieldGenerator{{Name: "field", Cmd: "printf a"}}},
+		{ItemName: "b", Fields: []secretgenerator.FieldGenerator{{Name: "field", Cmd: "printf b"}}},
+	}
+	report := newRunReport(time.Now())
+	_ = updateSecrets(ctx, config, client, nil, 1, report)
+	if len(client.Items) != 0 {
+		t.Errorf("expected nothing to be uploaded after the interruption, got %v", client.Items)
+	}
+	if succeeded, _ := report.itemResults(); len(succeeded) != 0 {
+		t.Errorf("expected no item to succeed, got %v", succeeded)
+	}
+}
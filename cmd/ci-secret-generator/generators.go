@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/ci-tools/pkg/api/secretgenerator"
+)
+
+const (
+	defaultRandomStringLength  = 32
+	defaultRandomStringCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	defaultRSAKeyBits          = 4096
+	minRSAKeyBits              = 2048
+	sshPublicKeySuffix         = ".pub"
+)
+
+// errInvalidOutput is returned when a generated value fails the validation of its field
+var errInvalidOutput = errors.New("invalid output")
+
+// generatedValue is the value generated for a field of an item
+type generatedValue struct {
+	field string
+	value []byte
+}
+
+// fieldReference matches a reference to the value of another field of the item in a command
+var fieldReference = regexp.MustCompile(`\$\(field:([^)]+)\)`)
+
+// fieldValues are the values of the fields of an item generated so far, which the
+// commands of the following fields of the item can reference
+type fieldValues map[string][]byte
+
+func (v fieldValues) add(values []generatedValue) {
+	for _, value := range values {
+		v[value.field] = value.value
+	}
+}
+
+// resolveReferences replaces the references to other fields in a command with their values,
+// quoted for a shell if the command is run by one
+func resolveReferences(command string, values fieldValues, shellQuote bool) (string, error) {
+	var errs []error
+	resolved := fieldReference.ReplaceAllStringFunc(command, func(reference string) string {
+		name := fieldReference.FindStringSubmatch(reference)[1]
+		value, ok := values[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("referenced field %s was not generated", name))
+			return reference
+		}
+		if shellQuote {
+			return "'" + strings.ReplaceAll(string(value), "'", `'\''`) + "'"
+		}
+		return string(value)
+	})
+	return resolved, utilerrors.NewAggregate(errs)
+}
+
+// validateReferences validates that the commands of the fields of an item only reference
+// fields generated before them
+func validateReferences(item secretgenerator.SecretItem) error {
+	generated := sets.New[string]()
+	for i, field := range item.Fields {
+		for _, part := range append([]string{field.Cmd}, field.Args...) {
+			for _, match := range fieldReference.FindAllStringSubmatch(part, -1) {
+				if !generated.Has(match[1]) {
+					return fmt.Errorf("fields[%d]: references field %s, which is not generated before it", i, match[1])
+				}
+			}
+		}
+		generated.Insert(field.Name)
+		if field.Type == secretgenerator.GeneratorSSHKeypair {
+			generated.Insert(field.Name + sshPublicKeySuffix)
+		}
+	}
+	return nil
+}
+
+// generateField generates the values of a field, either by running its command, by reading
+// its file or URL or by using its built-in generator. Only ssh-keypair generates more than one value.
+func generateField(ctx context.Context, field secretgenerator.FieldGenerator) ([]generatedValue, error) {
+	return generateFieldOfItem(ctx, field, nil)
+}
+
+// generateFieldOfItem generates the values of a field like generateField, resolving the
+// references of its command to the values of the fields generated before it
+func generateFieldOfItem(ctx context.Context, field secretgenerator.FieldGenerator, values fieldValues) ([]generatedValue, error) {
+	var value []byte
+	var err error
+	// derived are the values generated along with the value of the field
+	var derived []generatedValue
+	switch field.Type {
+	case "":
+		switch {
+		case field.File != "":
+			value, err = readFile(field.File)
+		case field.URL != "":
+			value, err = fetchURL(ctx, field.URL, field.SHA256, field.Timeout)
+		case len(field.Args) > 0:
+			args := make([]string, len(field.Args))
+			for i, arg := range field.Args {
+				if args[i], err = resolveReferences(arg, values, false); err != nil {
+					return nil, err
+				}
+			}
+			// the command with the references is reported, as the values are secret
+			value, err = execute(ctx, field.Command(), args, field.Timeout)
+		default:
+			var command string
+			if command, err = resolveReferences(field.Cmd, values, true); err != nil {
+				return nil, err
+			}
+			value, err = execute(ctx, field.Cmd, shellArgv(command), field.Timeout)
+		}
+	case secretgenerator.GeneratorRandomString:
+		value, err = randomString(field.Length, field.Charset)
+	case secretgenerator.GeneratorRSAKey:
+		value, err = rsaKey(field.Bits)
+	case secretgenerator.GeneratorSSHKeypair:
+		private, public, err := sshKeypair()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate ssh keypair: %w", err)
+		}
+		value = private
+		derived = append(derived, generatedValue{field: field.Name + sshPublicKeySuffix, value: public})
+	default:
+		return nil, fmt.Errorf("unknown generator type %q", field.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := validateOutput(field, value); err != nil {
+		return nil, err
+	}
+	return append([]generatedValue{{field: field.Name, value: value}}, derived...), nil
+}
+
+// validateOutput validates the value generated for a field. The value is never part of the error.
+func validateOutput(field secretgenerator.FieldGenerator, value []byte) error {
+	if field.ValidationRegex != "" {
+		re, err := regexp.Compile(field.ValidationRegex)
+		if err != nil {
+			return fmt.Errorf("validation_regex is invalid: %w", err)
+		}
+		if !re.Match(value) {
+			return fmt.Errorf("%w: does not match validation_regex %s", errInvalidOutput, field.ValidationRegex)
+		}
+	}
+	if len(value) < field.MinLength {
+		return fmt.Errorf("%w: shorter than min_length %d", errInvalidOutput, field.MinLength)
+	}
+	switch field.Format {
+	case secretgenerator.OutputFormatBase64:
+		if _, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(value))); err != nil {
+			return fmt.Errorf("%w: not base64", errInvalidOutput)
+		}
+	case secretgenerator.OutputFormatJSON:
+		if !json.Valid(value) {
+			return fmt.Errorf("%w: not JSON", errInvalidOutput)
+		}
+	case secretgenerator.OutputFormatPEM:
+		if block, _ := pem.Decode(value); block == nil {
+			return fmt.Errorf("%w: no PEM block", errInvalidOutput)
+		}
+	}
+	return nil
+}
+
+func validateGenerator(field secretgenerator.FieldGenerator) error {
+	if field.ValidationRegex != "" {
+		if _, err := regexp.Compile(field.ValidationRegex); err != nil {
+			return fmt.Errorf("validation_regex is invalid: %w", err)
+		}
+	}
+	if field.MinLength < 0 {
+		return fmt.Errorf("min_length must not be negative")
+	}
+	switch field.Format {
+	case "", secretgenerator.OutputFormatBase64, secretgenerator.OutputFormatJSON, secretgenerator.OutputFormatPEM:
+	default:
+		return fmt.Errorf("unknown format %q, must be one of %s, %s and %s", field.Format, secretgenerator.OutputFormatBase64, secretgenerator.OutputFormatJSON, secretgenerator.OutputFormatPEM)
+	}
+	var sources int
+	for _, source := range []string{field.Command(), field.File, field.URL} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return fmt.Errorf("cmd, file and url are mutually exclusive")
+	}
+	if field.SHA256 != "" {
+		if field.URL == "" {
+			return fmt.Errorf("sha256 can only be set with a url")
+		}
+		if !sha256Checksum.MatchString(field.SHA256) {
+			return fmt.Errorf("sha256 must be a hex-encoded SHA-256 checksum")
+		}
+	}
+	switch field.Type {
+	case "":
+		if field.Length != 0 || field.Charset != "" || field.Bits != 0 {
+			return fmt.Errorf("length, charset and bits can only be set with a type")
+		}
+		return nil
+	case secretgenerator.GeneratorRandomString:
+		if field.Length < 0 {
+			return fmt.Errorf("length must not be negative")
+		}
+		if field.Bits != 0 {
+			return fmt.Errorf("bits can only be set for %s", secretgenerator.GeneratorRSAKey)
+		}
+	case secretgenerator.GeneratorRSAKey:
+		if field.Bits != 0 && field.Bits < minRSAKeyBits {
+			return fmt.Errorf("bits must be at least %d", minRSAKeyBits)
+		}
+		if field.Length != 0 || field.Charset != "" {
+			return fmt.Errorf("length and charset can only be set for %s", secretgenerator.GeneratorRandomString)
+		}
+	case secretgenerator.GeneratorSSHKeypair:
+		if field.Length != 0 || field.Charset != "" || field.Bits != 0 {
+			return fmt.Errorf("length, charset and bits cannot be set for %s", secretgenerator.GeneratorSSHKeypair)
+		}
+	default:
+		return fmt.Errorf("unknown type %q, must be one of %s, %s and %s", field.Type, secretgenerator.GeneratorRSAKey, secretgenerator.GeneratorRandomString, secretgenerator.GeneratorSSHKeypair)
+	}
+	if field.Command() != "" {
+		return fmt.Errorf("cmd and type are mutually exclusive")
+	}
+	if field.File != "" || field.URL != "" {
+		return fmt.Errorf("file and url cannot be set with a type")
+	}
+	return nil
+}
+
+func randomString(length int, charset string) ([]byte, error) {
+	if length == 0 {
+		length = defaultRandomStringLength
+	}
+	if charset == "" {
+		charset = defaultRandomStringCharset
+	}
+	chars := []rune(charset)
+	size := big.NewInt(int64(len(chars)))
+	var ret []rune
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate random string: %w", err)
+		}
+		ret = append(ret, chars[n.Int64()])
+	}
+	return []byte(string(ret)), nil
+}
+
+func rsaKey(bits int) ([]byte, error) {
+	if bits == 0 {
+		bits = defaultRSAKeyBits
+	}
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate RSA key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), nil
+}
+
+func sshKeypair() (private, public []byte, err error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, err := ssh.MarshalPrivateKey(privateKey, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(block), ssh.MarshalAuthorizedKey(sshPublicKey), nil
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/openshift/ci-tools/pkg/api/secretgenerator"
+	"github.com/openshift/ci-tools/pkg/testhelper"
+)
+
+func TestValidateGenerator(t *testing.T) {
+	testCases := []struct {
+		name     string
+		field    secretgenerator.FieldGenerator
+		expected error
+	}{
+		{
+			name:  "command",
+			field: secretgenerator.FieldGenerator{Name: "field", Cmd: "echo -n secret"},
+		},
+		{
+			name:  "random string",
+			field: secretgenerator.FieldGenerator{Name: "field", Type: secretgenerator.GeneratorRandomString, Length: 16, Charset: "abc"},
+		},
+		{
+			name:  "rsa key",
+			field: secretgenerator.FieldGenerator{Name: "field", Type: secretgenerator.GeneratorRSAKey, Bits: 2048},
+		},
+		{
+			name:  "ssh keypair",
+			field: secretgenerator.FieldGenerator{Name: "field", Type: secretgenerator.GeneratorSSHKeypair},
+		},
+		{
+			name:     "options of a generator with a command",
+			field:    secretgenerator.FieldGenerator{Name: "field", Cmd: "echo -n secret", Length: 16},
+			expected: errors.New("length, charset and bits can only be set with a type"),
+		},
+		{
+			name:     "invalid validation regex",
+			field:    secretgenerator.FieldGenerator{Name: "field", Cmd: "echo -n secret", ValidationRegex: "("},
+			expected: errors.New("validation_regex is invalid: error parsing regexp: missing closing ): `(`"),
+		},
+		{
+			name:     "negative min length",
+			field:    secretgenerator.FieldGenerator{Name: "field", Cmd: "echo -n secret", MinLength: -1},
+			expected: errors.New("min_length must not be negative"),
+		},
+		{
+			name:     "unknown format",
+			field:    secretgenerator.FieldGenerator{Name: "field", Cmd: "echo -n secret", Format: "yaml"},
+			expected: errors.New(`unknown format "yaml", must be one of base64, json and pem`),
+		},
+		{
+			name:     "command and type",
+			field:    secretgenerator.FieldGenerator{Name: "field", Cmd: "echo -n secret", Type: secretgenerator.GeneratorRandomString},
+			expected: errors.New("cmd and type are mutually exclusive"),
+		},
+		{
+			name:  "url with a checksum",
+			field: secretgenerator.FieldGenerator{Name: "field", URL: "https://example.com/ca.crt", SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		},
+		{
+			name:     "command and file",
+			field:    secretgenerator.FieldGenerator{Name: "field", Cmd: "echo -n secret", File: "ca.crt"},
+			expected: errors.New("cmd, file and url are mutually exclusive"),
+		},
+		{
+			name:     "checksum without a url",
+			field:    secretgenerator.FieldGenerator{Name: "field", File: "ca.crt", SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+			expected: errors.New("sha256 can only be set with a url"),
+		},
+		{
+			name:     "invalid checksum",
+			field:    secretgenerator.FieldGenerator{Name: "field", URL: "https://example.com/ca.crt", SHA256: "abc"},
+			expected: errors.New("sha256 must be a hex-encoded SHA-256 checksum"),
+		},
+		{
+			name:     "file and type",
+			field:    secretgenerator.FieldGenerator{Name: "field", File: "ca.crt", Type: secretgenerator.GeneratorRSAKey},
+			expected: errors.New("file and url cannot be set with a type"),
+		},
+		{
+			name:     "unknown type",
+			field:    secretgenerator.FieldGenerator{Name: "field", Type: "password"},
+			expected: errors.New(`unknown type "password", must be one of rsa-key, random-string and ssh-keypair`),
+		},
+		{
+			name:     "small rsa key",
+			field:    secretgenerator.FieldGenerator{Name: "field", Type: secretgenerator.GeneratorRSAKey, Bits: 1024},
+			expected: errors.New("bits must be at least 2048"),
+		},
+		{
+			name:     "charset of an rsa key",
+			field:    secretgenerator.FieldGenerator{Name: "field", Type: secretgenerator.GeneratorRSAKey, Charset: "abc"},
+			expected: errors.New("length and charset can only be set for random-string"),
+		},
+		{
+			name:     "negative length",
+			field:    secretgenerator.FieldGenerator{Name: "field", Type: secretgenerator.GeneratorRandomString, Length: -1},
+			expected: errors.New("length must not be negative"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, validateGenerator(tc.field), testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateReferences(t *testing.T) {
+	testCases := []struct {
+		name     string
+		item     secretgenerator.SecretItem
+		expected error
+	}{
+		{
+			name: "references to fields generated before",
+			item: secretgenerator.SecretItem{ItemName: "item", Fields: []secretgenerator.FieldGenerator{
+				{Name: "id", Type: secretgenerator.GeneratorSSHKeypair},
+				{Name: "token", Cmd: "echo -n token"},
+				{Name: "config", Cmd: "make-config --token=$(field:token) --key=$(field:id.pub)"},
+				{Name: "args", Args: []string{"make-config", "$(field:config)"}},
+			}},
+		},
+		{
+			name: "reference to a field generated after",
+			item: secretgenerator.SecretItem{ItemName: "item", Fields: []secretgenerator.FieldGenerator{
+				{Name: "config", Cmd: "make-config --token=$(field:token)"},
+				{Name: "token", Cmd: "echo -n token"},
+			}},
+			expected: errors.New("fields[0]: references field token, which is not generated before it"),
+		},
+		{
+			name: "reference to itself",
+			item: secretgenerator.SecretItem{ItemName: "item", Fields: []secretgenerator.FieldGenerator{
+				{Name: "token", Args: []string{"echo", "$(field:token)"}},
+			}},
+			expected: errors.New("fields[0]: references field token, which is not generated before it"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, validateReferences(tc.item), testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateOutput(t *testing.T) {
+	testCases := []struct {
+		name     string
+		field    secretgenerator.FieldGenerator
+		value    string
+		expected error
+	}{
+		{
+			name:  "no validation",
+			value: "anything",
+		},
+		{
+			name:     "regex not matching",
+			field:    secretgenerator.FieldGenerator{ValidationRegex: "^[0-9]+$"},
+			value:    "error: something failed",
+			expected: errors.New("invalid output: does not match validation_regex ^[0-9]+$"),
+		},
+		{
+			name:  "long enough",
+			field: secretgenerator.FieldGenerator{MinLength: 4},
+			value: "long",
+		},
+		{
+			name:     "too short",
+			field:    secretgenerator.FieldGenerator{MinLength: 5},
+			value:    "long",
+			expected: errors.New("invalid output: shorter than min_length 5"),
+		},
+		{
+			name:  "base64 with a trailing newline",
+			field: secretgenerator.FieldGenerator{Format: secretgenerator.OutputFormatBase64},
+			value: "c2VjcmV0\n",
+		},
+		{
+			name:     "not base64",
+			field:    secretgenerator.FieldGenerator{Format: secretgenerator.OutputFormatBase64},
+			value:    "secret!",
+			expected: errors.New("invalid output: not base64"),
+		},
+		{
+			name:  "json",
+			field: secretgenerator.FieldGenerator{Format: secretgenerator.OutputFormatJSON},
+			value: `{"auths": {}}`,
+		},
+		{
+			name:     "not json",
+			field:    secretgenerator.FieldGenerator{Format: secretgenerator.OutputFormatJSON},
+			value:    "{",
+			expected: errors.New("invalid output: not JSON"),
+		},
+		{
+			name:  "pem",
+			field: secretgenerator.FieldGenerator{Format: secretgenerator.OutputFormatPEM},
+			value: "-----BEGIN CERTIFICATE-----\nc2VjcmV0\n-----END CERTIFICATE-----\n",
+		},
+		{
+			name:     "not pem",
+			field:    secretgenerator.FieldGenerator{Format: secretgenerator.OutputFormatPEM},
+			value:    "certificate",
+			expected: errors.New("invalid output: no PEM block"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateOutput(tc.field, []byte(tc.value))
+			if diff := cmp.Diff(tc.expected, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+			if err != nil && !errors.Is(err, errInvalidOutput) {
+				t.Errorf("expected the error to be errInvalidOutput, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGenerateField(t *testing.T) {
+	t.Run("random string", func(t *testing.T) {
+		values, err := generateField(context.Background(), secretgenerator.FieldGenerator{Name: "field", Type: secretgenerator.GeneratorRandomString, Length: 64, Charset: "ab"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(values) != 1 || values[0].field != "field" {
+			t.Fatalf("expected a single value for the field, got %v", values)
+		}
+		if len(values[0].value) != 64 || strings.Trim(string(values[0].value), "ab") != "" {
+			t.Errorf("expected 64 characters of the charset, got %q", values[0].value)
+		}
+	})
+	t.Run("random string with defaults", func(t *testing.T) {
+		values, err := generateField(context.Background(), secretgenerator.FieldGenerator{Name: "field", Type: secretgenerator.GeneratorRandomString})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(values[0].value) != defaultRandomStringLength || strings.Trim(string(values[0].value), defaultRandomStringCharset) != "" {
+			t.Errorf("expected %d letters and digits, got %q", defaultRandomStringLength, values[0].value)
+		}
+	})
+	t.Run("rsa key", func(t *testing.T) {
+		values, err := generateField(context.Background(), secretgenerator.FieldGenerator{Name: "field", Type: secretgenerator.GeneratorRSAKey, Bits: 2048})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		block, _ := pem.Decode(values[0].value)
+		if block == nil {
+			t.Fatalf("expected a PEM block, got %q", values[0].value)
+		}
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			t.Fatalf("failed to parse the key: %v", err)
+		}
+		if bits := key.N.BitLen(); bits != 2048 {
+			t.Errorf("expected a key of 2048 bits, got %d", bits)
+		}
+	})
+	t.Run("ssh keypair", func(t *testing.T) {
+		values, err := generateField(context.Background(), secretgenerator.FieldGenerator{Name: "id", Type: secretgenerator.GeneratorSSHKeypair})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(values) != 2 || values[0].field != "id" || values[1].field != "id.pub" {
+			t.Fatalf("expected values for id and id.pub, got %v", values)
+		}
+		signer, err := ssh.ParsePrivateKey(values[0].value)
+		if err != nil {
+			t.Fatalf("failed to parse the private key: %v", err)
+		}
+		public, _, _, _, err := ssh.ParseAuthorizedKey(values[1].value)
+		if err != nil {
+			t.Fatalf("failed to parse the public key: %v", err)
+		}
+		if diff := cmp.Diff(signer.PublicKey().Marshal(), public.Marshal()); diff != "" {
+			t.Errorf("the public key does not belong to the private key: %s", diff)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/ci-tools/pkg/api/secretbootstrap"
+	"github.com/openshift/ci-tools/pkg/api/secretgenerator"
+	"github.com/openshift/ci-tools/pkg/secretstore"
+)
+
+const (
+	importImported      = "imported"
+	importWouldImport   = "would be imported"
+	importAlreadyExists = "already exists"
+)
+
+// importSecrets reads the Secrets that the bootstrap config populates in the namespace of the
+// cluster and uploads their keys into the fields they are populated from, so that secrets
+// managed by hand can be onboarded into the generator. Only the fields of the generator
+// config are imported, and fields that exist in the secret store are never overwritten.
+func importSecrets(ctx context.Context, kubeClient ctrlruntimeclient.Client, cluster, namespace string, config secretgenerator.Config, bootstrapConfig secretbootstrap.Config, client secretstore.Client, dryRun bool, out io.Writer) error {
+	var errs []error
+	done := sets.New[string]()
+	for _, secretConfig := range bootstrapConfig.Secrets {
+		for _, to := range secretConfig.To {
+			if to.Cluster != cluster || to.Namespace != namespace {
+				continue
+			}
+			logger := logrus.WithFields(logrus.Fields{"namespace": to.Namespace, "name": to.Name})
+			secret := &corev1.Secret{}
+			if err := kubeClient.Get(ctx, ctrlruntimeclient.ObjectKey{Namespace: to.Namespace, Name: to.Name}, secret); err != nil {
+				if kerrors.IsNotFound(err) {
+					logger.Warn("Secret does not exist, not importing it")
+					continue
+				}
+				errs = append(errs, fmt.Errorf("failed to get secret %s/%s: %w", to.Namespace, to.Name, err))
+				continue
+			}
+			var keys []string
+			for key := range secretConfig.From {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				from := secretConfig.From[key]
+				// keys composed of several fields, like .dockerconfigjson, cannot be imported
+				if from.Item == "" || from.Field == "" {
+					continue
+				}
+				itemName := strings.TrimPrefix(from.Item, bootstrapConfig.VaultDPTPPrefix+"/")
+				if !config.IsFieldGenerated(itemName, from.Field) || done.Has(itemName+"/"+from.Field) {
+					continue
+				}
+				value, ok := secret.Data[key]
+				if !ok {
+					errs = append(errs, fmt.Errorf("secret %s/%s has no key %s", to.Namespace, to.Name, key))
+					continue
+				}
+				if from.Base64Decode {
+					value = []byte(base64.StdEncoding.EncodeToString(value))
+				}
+				state, err := importField(client, itemName, from.Field, value, dryRun)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("failed to import field %s of item %s: %w", from.Field, itemName, err))
+					continue
+				}
+				done.Insert(itemName + "/" + from.Field)
+				if _, err := fmt.Fprintf(out, "%s.%s from %s/%s[%s]: %s\n", itemName, from.Field, to.Namespace, to.Name, key, state); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// importField uploads the value of a field unless the field already exists
+func importField(client secretstore.Client, itemName, fieldName string, value []byte, dryRun bool) (string, error) {
+	if _, err := client.GetField(itemName, fieldName); err == nil {
+		return importAlreadyExists, nil
+	} else if !secretstore.IsNotFound(err) {
+		return "", err
+	}
+	if dryRun {
+		return importWouldImport, nil
+	}
+	if err := client.SetField(itemName, fieldName, value); err != nil {
+		return "", err
+	}
+	return importImported, nil
+}
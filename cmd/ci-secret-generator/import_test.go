@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/ci-tools/pkg/api/secretbootstrap"
+	"github.com/openshift/ci-tools/pkg/api/secretgenerator"
+	"github.com/openshift/ci-tools/pkg/secretstore"
+	"github.com/openshift/ci-tools/pkg/testhelper"
+)
+
+func TestImportSecrets(t *testing.T) {
+	kubeClient := fakectrlruntimeclient.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "legacy"},
+			Data: map[string][]byte{
+				"token":    []byte("token-value"),
+				"existing": []byte("new-value"),
+				"binary":   []byte{0xff},
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "incomplete"},
+			Data:       map[string][]byte{},
+		},
+	).Build()
+	config := secretgenerator.Config{
+		{
+			ItemName: "legacy",
+			Fields: []secretgenerator.FieldGenerator{
+				{Name: "token", Cmd: "printf token"},
+				{Name: "existing", Cmd: "printf existing"},
+				{Name: "binary", Cmd: "printf binary"},
+				{Name: "missing", Cmd: "printf missing"},
+			},
+		},
+	}
+	bootstrapConfig := secretbootstrap.Config{
+		VaultDPTPPrefix: "dptp",
+		Secrets: []secretbootstrap.SecretConfig{
+			{
+				From: map[string]secretbootstrap.ItemContext{
+					"token":    {Item: "dptp/legacy", Field: "token"},
+					"existing": {Item: "dptp/legacy", Field: "existing"},
+					"binary":   {Item: "dptp/legacy", Field: "binary", Base64Decode: true},
+					"other":    {Item: "dptp/not-generated", Field: "field"},
+					".dockerconfigjson": {DockerConfigJSONData: []secretbootstrap.DockerConfigJSONData{
+						{Item: "dptp/legacy", RegistryURL: "quay.io", AuthField: "token"},
+					}},
+				},
+				To: []secretbootstrap.SecretContext{
+					{Cluster: "build01", Namespace: "ci", Name: "legacy"},
+					{Cluster: "build02", Namespace: "ci", Name: "other-cluster"},
+					{Cluster: "build01", Namespace: "test-credentials", Name: "other-namespace"},
+					{Cluster: "build01", Namespace: "ci", Name: "absent"},
+				},
+			},
+			{
+				From: map[string]secretbootstrap.ItemContext{"key": {Item: "dptp/legacy", Field: "missing"}},
+				To:   []secretbootstrap.SecretContext{{Cluster: "build01", Namespace: "ci", Name: "incomplete"}},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name          string
+		dryRun        bool
+		expectedItems map[string]map[string]string
+		expectedOut   string
+	}{
+		{
+			name:          "fields are imported",
+			expectedItems: map[string]map[string]string{"legacy": {"token": "token-value", "existing": "old-value", "binary": "/w=="}},
+			expectedOut: `legacy.binary from ci/legacy[binary]: imported
+legacy.existing from ci/legacy[existing]: already exists
+legacy.token from ci/legacy[token]: imported
+`,
+		},
+		{
+			name:          "nothing is imported when running dry",
+			dryRun:        true,
+			expectedItems: map[string]map[string]string{"legacy": {"existing": "old-value"}},
+			expectedOut: `legacy.binary from ci/legacy[binary]: would be imported
+legacy.existing from ci/legacy[existing]: already exists
+legacy.token from ci/legacy[token]: would be imported
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := secretstore.NewFakeClient()
+			client.Items = map[string]map[string]string{"legacy": {"existing": "old-value"}}
+			out := &bytes.Buffer{}
+			err := importSecrets(context.Background(), kubeClient, "build01", "ci", config, bootstrapConfig, client, tc.dryRun, out)
+			if diff := cmp.Diff(errors.New("secret ci/incomplete has no key key"), err, testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedItems, client.Items); diff != "" {
+				t.Errorf("items differ from expected: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedOut, out.String()); diff != "" {
+				t.Errorf("output differs from expected: %s", diff)
+			}
+		})
+	}
+}
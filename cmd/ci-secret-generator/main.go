@@ -2,24 +2,40 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"os/exec"
 	"reflect"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
 
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/clientcmd"
+	prowv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+	"k8s.io/test-infra/prow/flagutil"
+	"k8s.io/test-infra/prow/interrupts"
 	"k8s.io/test-infra/prow/logrusutil"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/openshift/ci-tools/pkg/api/secretbootstrap"
 	"github.com/openshift/ci-tools/pkg/api/secretgenerator"
 	"github.com/openshift/ci-tools/pkg/prowconfigutils"
 	"github.com/openshift/ci-tools/pkg/secrets"
+	"github.com/openshift/ci-tools/pkg/secretstore"
+	"github.com/openshift/ci-tools/pkg/util"
 )
 
 const (
@@ -27,26 +43,115 @@ const (
 	execCmdValidateStdoutErrAction = "validate stdout of"
 	execCmdValidateStderrErrAction = "validate stderr of"
 	execCmdErrFmt                  = "failed to %s command %q: %w\n%s:\n%s\n%s:\n%s"
+
+	// backendVault stores the items in the KV v2 engine of Vault: every item is a secret
+	// at <vault-prefix>/<item_name>, and its fields and notes are keys of that secret.
+	backendVault = "vault"
+	// backendGSM stores the items in Google Secret Manager: every item is a secret named
+	// <item_name> in --gcp-project, whose payload is a JSON object of its fields and notes.
+	backendGSM = "gsm"
+
+	// outputFormatText writes every change to the dry-run output file as plain text
+	outputFormatText = "text"
+	// outputFormatSecrets writes the items to the dry-run output file as Kubernetes Secrets
+	outputFormatSecrets = "k8s-secrets"
+
+	diffNew       = "new"
+	diffChanged   = "changed"
+	diffUnchanged = "unchanged"
 )
 
 var (
 	errExecCmdNotEmptyStderr = errors.New("stderr is not empty")
 	errExecCmdNoStdout       = errors.New("no output returned")
 	errExecCmdNullStdout     = errors.New("'null' output returned")
+	errExecCmdTimedOut       = errors.New("timed out")
+	errExecCmdInterrupted    = errors.New("interrupted")
+
+	// backends maps the name of a secret store to how it is set up
+	backends = map[string]backend{
+		backendVault: {
+			validate: func(o *options) error { return o.secrets.Validate() },
+			newClient: func(o *options, censor *secrets.DynamicCensor) (secretstore.Client, error) {
+				client, err := o.secrets.NewClient(censor)
+				if err != nil {
+					return nil, err
+				}
+				return secretstore.NewKVClient(client), nil
+			},
+		},
+		backendGSM: {
+			validate: func(o *options) error {
+				if o.gcpProject == "" {
+					return fmt.Errorf("--gcp-project is required with --backend=%s", backendGSM)
+				}
+				return nil
+			},
+			newClient: func(o *options, censor *secrets.DynamicCensor) (secretstore.Client, error) {
+				opts := []option.ClientOption{option.WithScopes(secretstore.GSMScope)}
+				if o.gcpCredentialsFile != "" {
+					opts = append(opts, option.WithCredentialsFile(o.gcpCredentialsFile))
+				}
+				client, _, err := htransport.NewClient(context.Background(), opts...)
+				if err != nil {
+					return nil, fmt.Errorf("failed to create the client for Google Secret Manager: %w", err)
+				}
+				return secretstore.NewGSMClient(client, secretstore.GSMEndpoint, o.gcpProject, censor), nil
+			},
+		},
+	}
 )
 
+// backend is a secret store the secrets can be created in
+type backend struct {
+	// validate validates the options of the backend
+	validate func(o *options) error
+	// newClient creates a client for the backend
+	newClient func(o *options, censor *secrets.DynamicCensor) (secretstore.Client, error)
+}
+
+func backendNames() []string {
+	return sets.List(sets.KeySet(backends))
+}
+
 type options struct {
 	secrets secrets.CLIOptions
+	backend string
+
+	gcpProject         string
+	gcpCredentialsFile string
 
 	logLevel            string
 	configPath          string
 	bootstrapConfigPath string
 	outputFile          string
+	outputFormat        string
+	reportFile          string
+	pushGateway         string
+	checkpointFile      string
+	resumeFrom          string
 	dryRun              bool
+	diff                bool
+	checkExpiry         bool
+	validateCommands    bool
+	ownersReport        bool
+	ownersAliasesPath   string
+	importKubeconfig    string
+	importCluster       string
+	importNamespace     string
+	expiryMargin        time.Duration
 	validate            bool
 	validateOnly        bool
 	maxConcurrency      int
+	maxRetries          int
+	retryBackoff        time.Duration
+	requestRate         float64
+	commandTimeout      time.Duration
+	items               flagutil.Strings
+	itemRegex           string
 	disabledClusters    sets.Set[string]
+	finishedItems       sets.Set[string]
+	teams               sets.Set[string]
 
 	config          secretgenerator.Config
 	bootstrapConfig secretbootstrap.Config
@@ -56,13 +161,36 @@ func parseOptions(censor *secrets.DynamicCensor) options {
 	var o options
 	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 	fs.BoolVar(&o.dryRun, "dry-run", true, "Whether to actually create the secrets in vault.")
-	fs.StringVar(&o.configPath, "config", "", "Path to the config file to use for this tool.")
+	fs.BoolVar(&o.diff, "diff", false, "Whether to only print which fields would be new, changed or unchanged in the secret store, without writing anything. Their values are never printed.")
+	fs.BoolVar(&o.validateCommands, "validate-commands", false, "Whether to only run the commands of all fields and print whether their output is valid, without contacting the secret store. Their output is never printed.")
+	fs.BoolVar(&o.checkExpiry, "check-expiry", false, "Whether to only print the items whose secrets expire within --expiry-margin, and exit non-zero if there are any, without generating anything.")
+	fs.BoolVar(&o.ownersReport, "owners-report", false, "Whether to only print the items without an owner, without generating anything.")
+	fs.StringVar(&o.ownersAliasesPath, "owners-aliases", "", "If set, path to an OWNERS_ALIASES file whose aliases are the teams that may own items.")
+	fs.StringVar(&o.importKubeconfig, "import-kubeconfig", "", "If set, only import the Secrets of --import-namespace in the cluster of this kubeconfig into the fields they are populated from according to --bootstrap-config, without generating anything. Existing fields are never overwritten.")
+	fs.StringVar(&o.importCluster, "import-cluster", "", "The name of the cluster of --import-kubeconfig in the bootstrap config.")
+	fs.StringVar(&o.importNamespace, "import-namespace", "ci", "The namespace whose Secrets are imported with --import-kubeconfig.")
+	fs.DurationVar(&o.expiryMargin, "expiry-margin", 14*24*time.Hour, "How long before their expiry secrets are reported as due for rotation by --check-expiry.")
+	fs.StringVar(&o.configPath, "config", "", "Path to the config file to use for this tool, or to a directory whose YAML files are merged into the config.")
 	fs.StringVar(&o.bootstrapConfigPath, "bootstrap-config", "", "Path to the config file used for bootstrapping cluster secrets after using this tool.")
 	fs.BoolVar(&o.validate, "validate", true, "Validate that the items created from this tool are used in bootstrapping")
 	fs.BoolVar(&o.validateOnly, "validate-only", false, "If the tool should exit after the validation")
 	fs.StringVar(&o.outputFile, "output-file", "", "output file for dry-run mode")
+	fs.StringVar(&o.reportFile, "report-file", "", "If set, write a JSON report of every generated field and note to this file. Values are never part of the report.")
+	fs.StringVar(&o.pushGateway, "push-gateway", "", "If set, push the metrics of the run to the Prometheus push gateway at this address, e.g., http://pushgateway:9091.")
+	fs.StringVar(&o.checkpointFile, "checkpoint-file", "", "If set, write the items that were generated successfully to this file at the end of the run, also when it is interrupted, to resume the run with --resume-from.")
+	fs.StringVar(&o.resumeFrom, "resume-from", "", "If set, do not generate the items that are finished according to this checkpoint file written with --checkpoint-file.")
+	fs.StringVar(&o.outputFormat, "output-format", outputFormatText, fmt.Sprintf("The format of the output file for dry-run mode, one of %s and %s.", outputFormatText, outputFormatSecrets))
 	fs.StringVar(&o.logLevel, "log-level", "info", fmt.Sprintf("Log level is one of %v.", logrus.AllLevels))
-	fs.IntVar(&o.maxConcurrency, "concurrency", 1, "Maximum number of concurrent in-flight goroutines to BitWarden.")
+	fs.StringVar(&o.backend, "backend", backendVault, fmt.Sprintf("The secret store to create the secrets in, one of %v.", backendNames()))
+	fs.StringVar(&o.gcpProject, "gcp-project", "", fmt.Sprintf("The GCP project whose Google Secret Manager stores the secrets with --backend=%s.", backendGSM))
+	fs.StringVar(&o.gcpCredentialsFile, "gcp-credentials-file", "", fmt.Sprintf("The file with the credentials of the GCP service account used with --backend=%s. The application default credentials are used if unset.", backendGSM))
+	fs.DurationVar(&o.commandTimeout, "command-timeout", 10*time.Minute, "The maximal duration of the commands generating the secrets, unless a timeout is configured for their item or field. Zero means no timeout.")
+	fs.Var(&o.items, "item", "Only generate the item with this name, after the expansion of the params. Can be passed multiple times.")
+	fs.StringVar(&o.itemRegex, "item-regex", "", "Only generate the items whose name, after the expansion of the params, matches this regular expression.")
+	fs.IntVar(&o.maxRetries, "max-retries", 3, "Maximum number of retries of uploads to the secret store failing with a transient error, like a conflict or a server error.")
+	fs.DurationVar(&o.retryBackoff, "retry-backoff", time.Second, "Duration to wait before the first retry of an upload, doubled for every further retry.")
+	fs.Float64Var(&o.requestRate, "request-rate", 0, "Maximum number of requests per second to the secret store, shared by all workers. Zero means no limit.")
+	fs.IntVar(&o.maxConcurrency, "concurrency", 1, "Maximum number of items generated and uploaded to the secret store in parallel.")
 	o.secrets.Bind(fs, os.Getenv, censor)
 	if err := fs.Parse(os.Args[1:]); err != nil {
 		logrus.WithError(err).Errorf("cannot parse args: %q", os.Args[1:])
@@ -76,11 +204,61 @@ func (o *options) validateOptions() error {
 		return fmt.Errorf("invalid log level specified: %w", err)
 	}
 	logrus.SetLevel(level)
-	if !o.dryRun {
-		if err := o.secrets.Validate(); err != nil {
+	backend, ok := backends[o.backend]
+	if !ok {
+		return fmt.Errorf("--backend must be one of %v", backendNames())
+	}
+	if o.outputFormat != outputFormatText && o.outputFormat != outputFormatSecrets {
+		return fmt.Errorf("--output-format must be one of %s and %s", outputFormatText, outputFormatSecrets)
+	}
+	if o.outputFormat == outputFormatSecrets && !o.dryRun {
+		return fmt.Errorf("--output-format=%s requires --dry-run", outputFormatSecrets)
+	}
+	var modes int
+	for _, mode := range []bool{o.diff, o.checkExpiry, o.validateCommands, o.ownersReport, o.importKubeconfig != ""} {
+		if mode {
+			modes++
+		}
+	}
+	if modes > 1 {
+		return errors.New("--diff, --check-expiry, --validate-commands, --owners-report and --import-kubeconfig are mutually exclusive")
+	}
+	if o.importKubeconfig != "" {
+		if o.importCluster == "" || o.importNamespace == "" {
+			return errors.New("--import-cluster and --import-namespace are required with --import-kubeconfig")
+		}
+		if o.bootstrapConfigPath == "" {
+			return errors.New("--bootstrap-config is required with --import-kubeconfig")
+		}
+	}
+	if !o.dryRun || o.diff || o.checkExpiry || o.importKubeconfig != "" {
+		if err := backend.validate(o); err != nil {
 			return err
 		}
 	}
+	if o.maxConcurrency < 1 {
+		return errors.New("--concurrency must be positive")
+	}
+	if o.maxRetries < 0 {
+		return errors.New("--max-retries must not be negative")
+	}
+	if o.retryBackoff < 0 {
+		return errors.New("--retry-backoff must not be negative")
+	}
+	if o.requestRate < 0 {
+		return errors.New("--request-rate must not be negative")
+	}
+	if o.commandTimeout < 0 {
+		return errors.New("--command-timeout must not be negative")
+	}
+	if o.expiryMargin < 0 {
+		return errors.New("--expiry-margin must not be negative")
+	}
+	if o.itemRegex != "" {
+		if _, err := regexp.Compile(o.itemRegex); err != nil {
+			return fmt.Errorf("--item-regex is invalid: %w", err)
+		}
+	}
 	if o.configPath == "" {
 		return errors.New("--config is empty")
 	}
@@ -90,6 +268,18 @@ func (o *options) validateOptions() error {
 	return nil
 }
 
+// newClient creates a client for the backend, limited to --request-rate
+func (o *options) newClient(censor *secrets.DynamicCensor) (secretstore.Client, error) {
+	client, err := backends[o.backend].newClient(o, censor)
+	if err != nil {
+		return nil, err
+	}
+	if o.requestRate > 0 {
+		client = secretstore.NewRateLimitedClient(client, rate.NewLimiter(rate.Limit(o.requestRate), 1))
+	}
+	return client, nil
+}
+
 func (o *options) completeOptions(censor *secrets.DynamicCensor) error {
 	if err := o.secrets.Complete(censor); err != nil {
 		return err
@@ -107,13 +297,69 @@ func (o *options) completeOptions(censor *secrets.DynamicCensor) error {
 		}
 	}
 
+	if o.ownersAliasesPath != "" {
+		if o.teams, err = loadTeamAliases(o.ownersAliasesPath); err != nil {
+			return err
+		}
+	}
+
 	prowDisabledClustersList, err := prowconfigutils.ProwDisabledClusters(nil)
 	if err != nil {
 		logrus.WithError(err).Warn("Failed to get Prow disable clusters")
 	}
 	o.disabledClusters = sets.New[string](prowDisabledClustersList...)
 
-	return o.validateConfig()
+	if err := o.validateConfig(); err != nil {
+		return err
+	}
+	defaultTimeouts(o.config, o.commandTimeout)
+	addOwnershipToNotes(o.config)
+	return nil
+}
+
+// defaultTimeouts sets the timeout of every field to the timeout of its item or, if
+// neither is configured, to the default one
+func defaultTimeouts(config secretgenerator.Config, defaultTimeout time.Duration) {
+	for i := range config {
+		for j := range config[i].Fields {
+			field := &config[i].Fields[j]
+			if field.Timeout != nil {
+				continue
+			}
+			if config[i].Timeout != nil {
+				field.Timeout = config[i].Timeout
+			} else if defaultTimeout > 0 {
+				field.Timeout = &prowv1.Duration{Duration: defaultTimeout}
+			}
+		}
+	}
+}
+
+// filterItems returns the items whose name is one of the names or matches the regex. Every
+// name has to match an item, to catch typos that would otherwise silently generate nothing.
+func filterItems(config secretgenerator.Config, names sets.Set[string], regex string) (secretgenerator.Config, error) {
+	var re *regexp.Regexp
+	if regex != "" {
+		var err error
+		if re, err = regexp.Compile(regex); err != nil {
+			return nil, fmt.Errorf("failed to compile regex %q: %w", regex, err)
+		}
+	}
+	var filtered secretgenerator.Config
+	found := sets.New[string]()
+	for _, item := range config {
+		if names.Has(item.ItemName) || (re != nil && re.MatchString(item.ItemName)) {
+			filtered = append(filtered, item)
+			found.Insert(item.ItemName)
+		}
+	}
+	if missing := names.Difference(found); missing.Len() > 0 {
+		return nil, fmt.Errorf("items not found in the config: %s", strings.Join(sets.List(missing), ", "))
+	}
+	if len(filtered) == 0 {
+		return nil, errors.New("no item matches the filters")
+	}
+	return filtered, nil
 }
 
 func cmdEmptyErr(itemIndex, entryIndex int, entry string) error {
@@ -126,10 +372,39 @@ func (o *options) validateConfig() error {
 			return fmt.Errorf("config[%d].itemName: empty key is not allowed", i)
 		}
 
+		if item.Timeout != nil && item.Timeout.Duration <= 0 {
+			return fmt.Errorf("config[%d].timeout: must be positive", i)
+		}
+		if item.Expiry != nil && item.RotationPeriod != nil {
+			return fmt.Errorf("config[%d]: expiry and rotation_period are mutually exclusive", i)
+		}
+		if item.RotationPeriod != nil && item.RotationPeriod.Duration <= 0 {
+			return fmt.Errorf("config[%d].rotation_period: must be positive", i)
+		}
+		if err := validateReferences(item); err != nil {
+			return fmt.Errorf("config[%d].%w", i, err)
+		}
+		if err := validateOwnership(item, o.teams); err != nil {
+			return fmt.Errorf("config[%d].%w", i, err)
+		}
+		switch item.NotesMode {
+		case "", secretgenerator.NotesModeReplace, secretgenerator.NotesModeAppend, secretgenerator.NotesModeSetIfEmpty:
+		default:
+			return fmt.Errorf("config[%d].notes_mode: unknown mode %q, must be one of %s, %s and %s", i, item.NotesMode, secretgenerator.NotesModeReplace, secretgenerator.NotesModeAppend, secretgenerator.NotesModeSetIfEmpty)
+		}
 		for fieldIndex, field := range item.Fields {
-			if field.Name != "" && field.Cmd == "" {
+			if hasExpiry(item) && (field.Name == secretgenerator.GeneratedAtField || field.Name == secretgenerator.ExpiresAtField) {
+				return fmt.Errorf("config[%d].fields[%d]: %s and %s are reserved for the expiry of the item", i, fieldIndex, secretgenerator.GeneratedAtField, secretgenerator.ExpiresAtField)
+			}
+			if field.Name != "" && field.Cmd == "" && len(field.Args) == 0 && field.File == "" && field.URL == "" && field.Type == "" {
 				return cmdEmptyErr(i, fieldIndex, "fields")
 			}
+			if err := validateGenerator(field); err != nil {
+				return fmt.Errorf("config[%d].fields[%d]: %w", i, fieldIndex, err)
+			}
+			if field.Timeout != nil && field.Timeout.Duration <= 0 {
+				return fmt.Errorf("config[%d].fields[%d].timeout: must be positive", i, fieldIndex)
+			}
 		}
 		var hasCluster bool
 		for paramName, params := range item.Params {
@@ -147,15 +422,47 @@ func (o *options) validateConfig() error {
 	return nil
 }
 
-func executeCommand(command string) ([]byte, error) {
-	cmd := exec.Command("bash", "-o", "errexit", "-o", "nounset", "-o", "pipefail", "-c", command)
+// executeCommand runs the command and returns its output. A timeout of nil means that the
+// command may run indefinitely. The command is killed when the context is cancelled.
+func executeCommand(ctx context.Context, command string, timeout *prowv1.Duration) ([]byte, error) {
+	return execute(ctx, command, shellArgv(command), timeout)
+}
+
+// shellArgv returns the arguments to run the command with bash
+func shellArgv(command string) []string {
+	return []string{"bash", "-o", "errexit", "-o", "nounset", "-o", "pipefail", "-c", command}
+}
+
+// executeArgs runs the program with the arguments without a shell and returns its output,
+// like executeCommand
+func executeArgs(ctx context.Context, args []string, timeout *prowv1.Duration) ([]byte, error) {
+	return execute(ctx, strings.Join(args, " "), args, timeout)
+}
+
+// execute runs the program of argv, reporting the command in errors
+func execute(ctx context.Context, command string, argv []string, timeout *prowv1.Duration) ([]byte, error) {
+	parent := ctx
+	if timeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout.Duration)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
 	var outBuf, errBuf bytes.Buffer
 	cmd.Stdout = &outBuf
 	cmd.Stderr = &errBuf
+	// do not wait for processes started by the command that still hold its output open once it was killed
+	cmd.WaitDelay = time.Second
 
 	if err := cmd.Run(); err != nil {
 		stderr := errBuf.Bytes()
 		stdout := outBuf.Bytes()
+		if parent.Err() != nil {
+			return nil, fmtExecCmdErr(execCmdRunErrAction, command, errExecCmdInterrupted, stdout, stderr, true)
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmtExecCmdErr(execCmdRunErrAction, command, fmt.Errorf("%w after %s", errExecCmdTimedOut, timeout.Duration), stdout, stderr, true)
+		}
 		// The command completed with non zero exit code, standard streams *should* be available.
 		_, partialStreams := err.(*exec.ExitError)
 		return nil, fmtExecCmdErr(execCmdRunErrAction, command, err, stdout, stderr, !partialStreams)
@@ -182,60 +489,480 @@ func executeCommand(command string) ([]byte, error) {
 	return stdout, nil
 }
 
+// generateFieldErrMsg returns the message for a failed command, distinguishing commands that timed out
+// or were interrupted and values that failed their validation
+func generateFieldErrMsg(err error) string {
+	if errors.Is(err, errExecCmdTimedOut) {
+		return "timed out generating field"
+	}
+	if errors.Is(err, errExecCmdInterrupted) {
+		return "interrupted generating field"
+	}
+	if errors.Is(err, errInvalidOutput) {
+		return "invalid output of field"
+	}
+	return "failed to generate field"
+}
+
 func fmtExecCmdErr(action, cmd string, wrappedErr error, stdout, stderr []byte, partialStreams bool) error {
+	return &execCmdError{action: action, cmd: cmd, err: wrappedErr, stdout: stdout, stderr: stderr, partialStreams: partialStreams}
+}
+
+// execCmdError is the error of a failed command. Its message includes the output of the command,
+// which may contain secrets.
+type execCmdError struct {
+	action         string
+	cmd            string
+	err            error
+	stdout         []byte
+	stderr         []byte
+	partialStreams bool
+}
+
+func (e *execCmdError) Error() string {
 	stdoutPreamble := "output"
 	stderrPreamble := "error output"
-	if partialStreams {
+	if e.partialStreams {
 		stdoutPreamble = "output (may be incomplete)"
 		stderrPreamble = "error output (may be incomplete)"
 	}
-	return fmt.Errorf(execCmdErrFmt, action, cmd, wrappedErr, stdoutPreamble,
-		stdout, stderrPreamble, stderr)
+	return fmt.Errorf(execCmdErrFmt, e.action, e.cmd, e.err, stdoutPreamble,
+		e.stdout, stderrPreamble, e.stderr).Error()
+}
+
+func (e *execCmdError) Unwrap() error {
+	return e.err
+}
+
+// redacted returns the message of the error without the output of the command
+func (e *execCmdError) redacted() string {
+	return fmt.Sprintf("failed to %s command %q: %v", e.action, e.cmd, e.err)
+}
+
+// isUnchanged returns whether the value in the secret store equals the generated one. When the
+// current value cannot be read, it is considered changed, so that it is uploaded.
+func isUnchanged(generated, current []byte, err error, logger *logrus.Entry) bool {
+	state, err := diffState(generated, current, err)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get the current value, uploading it")
+		return false
+	}
+	return state == diffUnchanged
+}
+
+// updateSecrets uploads the generated secrets to the secret store. Values which are already
+// in the store are skipped to avoid creating new revisions of items for every run. Up to
+// maxConcurrency items are processed in parallel; the entries of the same item are always
+// processed by the same worker, one after another, as they modify the same secret.
+func updateSecrets(ctx context.Context, config secretgenerator.Config, client secretstore.Client, disabledClusters sets.Set[string], maxConcurrency int, report *runReport) error {
+	var itemNames []string
+	byName := map[string][]secretgenerator.SecretItem{}
+	for _, item := range config {
+		if _, ok := byName[item.ItemName]; !ok {
+			itemNames = append(itemNames, item.ItemName)
+		}
+		byName[item.ItemName] = append(byName[item.ItemName], item)
+	}
+
+	var lock sync.Mutex
+	var uploaded, skipped int
+	ch := make(chan []secretgenerator.SecretItem)
+	errCh := make(chan error)
+	produce := func() error {
+		defer close(ch)
+		for _, name := range itemNames {
+			select {
+			case <-ctx.Done():
+				logrus.WithField("item", name).Warn("Interrupted, not generating the remaining items")
+				return nil
+			case ch <- byName[name]:
+			}
+		}
+		return nil
+	}
+	map_ := func() error {
+		for items := range ch {
+			for _, item := range items {
+				itemUploaded, itemSkipped, errs := updateItem(ctx, item, client, disabledClusters, report)
+				lock.Lock()
+				uploaded += itemUploaded
+				skipped += itemSkipped
+				lock.Unlock()
+				for _, err := range errs {
+					errCh <- err
+				}
+			}
+		}
+		return nil
+	}
+	err := util.ProduceMap(maxConcurrency, produce, map_, errCh)
+	logrus.WithFields(logrus.Fields{"uploaded": uploaded, "skipped": skipped}).Info("Finished uploading secrets")
+	return err
+}
+
+// updateItem uploads the generated fields and the notes of an item and returns
+// how many of them were uploaded and skipped
+func updateItem(ctx context.Context, item secretgenerator.SecretItem, client secretstore.Client, disabledClusters sets.Set[string], report *runReport) (uploaded, skipped int, errs []error) {
+	logger := logrus.WithField("item", item.ItemName)
+	var fieldsChanged bool
+	values := fieldValues{}
+	defer func() {
+		if hasExpiry(item) {
+			expiryUploaded, expirySkipped, err := updateExpiry(item, client, fieldsChanged, time.Now())
+			uploaded, skipped = uploaded+expiryUploaded, skipped+expirySkipped
+			if err != nil {
+				msg := "failed to update expiry"
+				logrus.WithField("item", item.ItemName).WithError(err).Error(msg)
+				errs = append(errs, errors.New(msg))
+			}
+		}
+	}()
+	for _, field := range item.Fields {
+		logger = logger.WithFields(logrus.Fields{
+			"field":   field.Name,
+			"command": field.Command(),
+			"cluster": field.Cluster,
+		})
+		if disabledClusters.Has(field.Cluster) {
+			logger.Info("ignored field for disabled cluster")
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			msg := "interrupted generating field"
+			logger.WithError(err).Error(msg)
+			report.add(item.ItemName, field.Name, field.Cluster, 0, false, msg, err)
+			return uploaded, skipped, append(errs, errors.New(msg))
+		}
+		start := time.Now()
+		fieldUploaded, fieldSkipped, msg, err := updateField(ctx, item.ItemName, field, client, values, logger)
+		uploaded, skipped = uploaded+fieldUploaded, skipped+fieldSkipped
+		if fieldUploaded > 0 {
+			fieldsChanged = true
+		}
+		if err != nil {
+			logger.WithError(err).Error(msg)
+			errs = append(errs, errors.New(msg))
+		}
+		report.add(item.ItemName, field.Name, field.Cluster, time.Since(start), fieldUploaded > 0, msg, err)
+	}
+
+	// Adding the notes not empty check here since we dont want to overwrite any notes that might already be present
+	// If notes have to be deleted, it would have to be a manual operation where the user goes to the bw web UI and removes
+	// the notes
+	if item.Notes != "" {
+		logger = logger.WithFields(logrus.Fields{
+			"notes": item.Notes,
+		})
+		start := time.Now()
+		current, err := client.GetNotes(item.ItemName)
+		if err != nil && !secretstore.IsNotFound(err) && item.NotesMode != "" && item.NotesMode != secretgenerator.NotesModeReplace {
+			// the notes can only be combined with the current ones if they are known
+			msg := "failed to get notes"
+			logger.WithError(err).Error(msg)
+			report.add(item.ItemName, secretstore.NotesField, "", time.Since(start), false, msg, err)
+			return uploaded, skipped, append(errs, errors.New(msg))
+		}
+		notes := notesToUpload(item, current)
+		if isUnchanged([]byte(notes), []byte(current), err, logger) {
+			logger.Info("skipped unchanged notes")
+			report.add(item.ItemName, secretstore.NotesField, "", time.Since(start), false, "", nil)
+			return uploaded, skipped + 1, errs
+		}
+		logger.Info("adding notes")
+		if err := client.UpdateNotes(item.ItemName, notes); err != nil {
+			msg := "failed to update notes"
+			logger.WithError(err).Error(msg)
+			report.add(item.ItemName, secretstore.NotesField, "", time.Since(start), false, msg, err)
+			return uploaded, skipped, append(errs, errors.New(msg))
+		}
+		report.add(item.ItemName, secretstore.NotesField, "", time.Since(start), true, "", nil)
+		uploaded++
+	}
+	return uploaded, skipped, errs
+}
+
+// notesToUpload returns the notes of the item combined with its current notes according to its notes mode
+func notesToUpload(item secretgenerator.SecretItem, current string) string {
+	switch item.NotesMode {
+	case secretgenerator.NotesModeAppend:
+		if current == "" {
+			return item.Notes
+		}
+		if strings.Contains(current, item.Notes) {
+			return current
+		}
+		return strings.TrimSuffix(current, "\n") + "\n" + item.Notes
+	case secretgenerator.NotesModeSetIfEmpty:
+		if current != "" {
+			return current
+		}
+	}
+	return item.Notes
 }
 
-func updateSecrets(config secretgenerator.Config, client secrets.Client, disabledClusters sets.Set[string]) error {
+// updateField generates a field and uploads its values unless they are unchanged. It returns how
+// many values were uploaded and skipped and, on failure, the message to report along with the error.
+func updateField(ctx context.Context, itemName string, field secretgenerator.FieldGenerator, client secretstore.Client, values fieldValues, logger *logrus.Entry) (uploaded, skipped int, msg string, err error) {
+	if field.Type != "" {
+		// built-in generators only generate fields that do not exist yet, as
+		// they would generate a different value every time
+		if current, err := client.GetField(itemName, field.Name); err == nil {
+			logger.Info("skipped existing field of a built-in generator")
+			values[field.Name] = current
+			return 0, 1, "", nil
+		} else if !secretstore.IsNotFound(err) {
+			return 0, 0, "failed to get field", err
+		}
+	}
+	logger.Info("processing field")
+	generated, err := generateFieldOfItem(ctx, field, values)
+	if err != nil {
+		return 0, 0, generateFieldErrMsg(err), err
+	}
+	values.add(generated)
+	for _, value := range generated {
+		logger := logger.WithField("field", value.field)
+		if current, err := client.GetField(itemName, value.field); isUnchanged(value.value, current, err, logger) {
+			logger.Info("skipped unchanged field")
+			skipped++
+			continue
+		}
+		if err := client.SetField(itemName, value.field, value.value); err != nil {
+			return uploaded, skipped, "failed to upload field", err
+		}
+		uploaded++
+	}
+	return uploaded, skipped, "", nil
+}
+
+func hasExpiry(item secretgenerator.SecretItem) bool {
+	return item.Expiry != nil || item.RotationPeriod != nil
+}
+
+// updateExpiry uploads when the secrets of the item expire. For a rotation period, this is
+// relative to when they last changed, which is the time of this run if any of them changed
+// or if that time was not tracked yet.
+func updateExpiry(item secretgenerator.SecretItem, client secretstore.Client, changed bool, now time.Time) (uploaded, skipped int, err error) {
+	logger := logrus.WithField("item", item.ItemName)
+	set := func(field string, value time.Time) error {
+		formatted := []byte(value.UTC().Format(time.RFC3339))
+		logger := logger.WithField("field", field)
+		if current, err := client.GetField(item.ItemName, field); isUnchanged(formatted, current, err, logger) {
+			skipped++
+			return nil
+		}
+		if err := client.SetField(item.ItemName, field, formatted); err != nil {
+			return fmt.Errorf("failed to upload field %s: %w", field, err)
+		}
+		uploaded++
+		return nil
+	}
+
+	if item.Expiry != nil {
+		return uploaded, skipped, set(secretgenerator.ExpiresAtField, item.Expiry.Time)
+	}
+	generatedAt := now
+	if !changed {
+		current, err := client.GetField(item.ItemName, secretgenerator.GeneratedAtField)
+		if err != nil && !secretstore.IsNotFound(err) {
+			return uploaded, skipped, fmt.Errorf("failed to get field %s: %w", secretgenerator.GeneratedAtField, err)
+		}
+		if err == nil {
+			if generatedAt, err = time.Parse(time.RFC3339, string(current)); err != nil {
+				return uploaded, skipped, fmt.Errorf("failed to parse field %s: %w", secretgenerator.GeneratedAtField, err)
+			}
+		}
+	}
+	if err := set(secretgenerator.GeneratedAtField, generatedAt); err != nil {
+		return uploaded, skipped, err
+	}
+	return uploaded, skipped, set(secretgenerator.ExpiresAtField, generatedAt.Add(item.RotationPeriod.Duration))
+}
+
+// checkExpiry prints the items whose secrets expire before now plus the margin, or whose
+// expiry is unknown, and returns an error if there are any
+func checkExpiry(config secretgenerator.Config, client secretstore.Reader, now time.Time, margin time.Duration, out io.Writer) error {
+	var errs []error
+	var due int
+	seen := sets.New[string]()
+	for _, item := range config {
+		if !hasExpiry(item) || seen.Has(item.ItemName) {
+			continue
+		}
+		seen.Insert(item.ItemName)
+		var state string
+		value, err := client.GetField(item.ItemName, secretgenerator.ExpiresAtField)
+		if secretstore.IsNotFound(err) {
+			state = "expiry unknown"
+		} else if err != nil {
+			msg := "failed to get expiry"
+			logrus.WithField("item", item.ItemName).WithError(err).Error(msg)
+			errs = append(errs, errors.New(msg))
+			continue
+		} else if expiresAt, err := time.Parse(time.RFC3339, string(value)); err != nil {
+			state = fmt.Sprintf("invalid expiry %q", value)
+		} else if !expiresAt.After(now) {
+			state = fmt.Sprintf("expired at %s", expiresAt.Format(time.RFC3339))
+		} else if !expiresAt.After(now.Add(margin)) {
+			state = fmt.Sprintf("expires at %s", expiresAt.Format(time.RFC3339))
+		} else {
+			continue
+		}
+		due++
+		if _, err := fmt.Fprintf(out, "%s: %s\n", item.ItemName, state); err != nil {
+			return err
+		}
+	}
+	if due > 0 {
+		errs = append(errs, fmt.Errorf("%d items are due for rotation", due))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// validateCommandsOfItems generates all fields and prints for each item whether they are
+// valid, without reading from or writing to a secret store
+func validateCommandsOfItems(ctx context.Context, config secretgenerator.Config, disabledClusters sets.Set[string], out io.Writer) error {
+	var itemNames []string
+	results := map[string][]string{}
+	var failed int
+	for _, item := range config {
+		if _, ok := results[item.ItemName]; !ok {
+			itemNames = append(itemNames, item.ItemName)
+			results[item.ItemName] = nil
+		}
+		values := fieldValues{}
+		for _, field := range item.Fields {
+			logger := logrus.WithFields(logrus.Fields{
+				"item":    item.ItemName,
+				"field":   field.Name,
+				"command": field.Command(),
+				"cluster": field.Cluster,
+			})
+			if disabledClusters.Has(field.Cluster) {
+				logger.Info("ignored field for disabled cluster")
+				continue
+			}
+			result := "ok"
+			if generated, err := generateFieldOfItem(ctx, field, values); err != nil {
+				result = generateFieldErrMsg(err)
+				logger.WithError(err).Error(result)
+				failed++
+			} else {
+				values.add(generated)
+			}
+			results[item.ItemName] = append(results[item.ItemName], fmt.Sprintf("%s: %s", field.Name, result))
+		}
+	}
+	for _, name := range itemNames {
+		if len(results[name]) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(out, "%s:\n  %s\n", name, strings.Join(results[name], "\n  ")); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d fields failed", failed)
+	}
+	return nil
+}
+
+// diffState compares a generated value with the current value in the secret store
+func diffState(generated, current []byte, err error) (string, error) {
+	if secretstore.IsNotFound(err) {
+		return diffNew, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if bytes.Equal(generated, current) {
+		return diffUnchanged, nil
+	}
+	return diffChanged, nil
+}
+
+// diffSecrets generates the secrets and prints for each item whether its fields and notes
+// are new, changed or unchanged in the secret store, without writing anything to it
+func diffSecrets(ctx context.Context, config secretgenerator.Config, client secretstore.Reader, disabledClusters sets.Set[string], out io.Writer) error {
 	var errs []error
+	var itemNames []string
+	diffs := map[string][]string{}
 	for _, item := range config {
 		logger := logrus.WithField("item", item.ItemName)
+		if _, ok := diffs[item.ItemName]; !ok {
+			itemNames = append(itemNames, item.ItemName)
+			diffs[item.ItemName] = nil
+		}
+		values := fieldValues{}
 		for _, field := range item.Fields {
-			logger = logger.WithFields(logrus.Fields{
+			logger := logger.WithFields(logrus.Fields{
 				"field":   field.Name,
-				"command": field.Cmd,
+				"command": field.Command(),
 				"cluster": field.Cluster,
 			})
 			if disabledClusters.Has(field.Cluster) {
 				logger.Info("ignored field for disabled cluster")
 				continue
 			}
-			logger.Info("processing field")
-			out, err := executeCommand(field.Cmd)
-			if err != nil {
-				msg := "failed to generate field"
-				logger.WithError(err).Error(msg)
-				errs = append(errs, errors.New(msg))
+			if field.Type != "" {
+				// built-in generators only generate fields that do not exist yet
+				current, err := client.GetField(item.ItemName, field.Name)
+				state := diffUnchanged
+				if secretstore.IsNotFound(err) {
+					state = diffNew
+					// the value is only generated to be referenced by the following fields
+					if generated, err := generateField(ctx, field); err == nil {
+						values.add(generated)
+					}
+				} else if err != nil {
+					msg := "failed to get field"
+					logger.WithError(err).Error(msg)
+					errs = append(errs, errors.New(msg))
+					continue
+				} else {
+					values[field.Name] = current
+				}
+				diffs[item.ItemName] = append(diffs[item.ItemName], fmt.Sprintf("%s: %s", field.Name, state))
 				continue
 			}
-			if err := client.SetFieldOnItem(item.ItemName, field.Name, out); err != nil {
-				msg := "failed to upload field"
+			generated, err := generateFieldOfItem(ctx, field, values)
+			if err != nil {
+				msg := generateFieldErrMsg(err)
 				logger.WithError(err).Error(msg)
 				errs = append(errs, errors.New(msg))
 				continue
 			}
+			values.add(generated)
+			for _, value := range generated {
+				current, err := client.GetField(item.ItemName, value.field)
+				state, err := diffState(value.value, current, err)
+				if err != nil {
+					msg := "failed to get field"
+					logger.WithError(err).Error(msg)
+					errs = append(errs, errors.New(msg))
+					continue
+				}
+				diffs[item.ItemName] = append(diffs[item.ItemName], fmt.Sprintf("%s: %s", value.field, state))
+			}
 		}
-
-		// Adding the notes not empty check here since we dont want to overwrite any notes that might already be present
-		// If notes have to be deleted, it would have to be a manual operation where the user goes to the bw web UI and removes
-		// the notes
 		if item.Notes != "" {
-			logger = logger.WithFields(logrus.Fields{
-				"notes": item.Notes,
-			})
-			logger.Info("adding notes")
-			if err := client.UpdateNotesOnItem(item.ItemName, item.Notes); err != nil {
-				msg := "failed to update notes"
+			current, err := client.GetNotes(item.ItemName)
+			state, err := diffState([]byte(notesToUpload(item, current)), []byte(current), err)
+			if err != nil {
+				msg := "failed to get notes"
 				logger.WithError(err).Error(msg)
 				errs = append(errs, errors.New(msg))
+				continue
 			}
+			diffs[item.ItemName] = append(diffs[item.ItemName], fmt.Sprintf("notes: %s", state))
+		}
+	}
+	for _, name := range itemNames {
+		if len(diffs[name]) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(out, "%s:\n  %s\n", name, strings.Join(diffs[name], "\n  ")); err != nil {
+			return err
 		}
 	}
 	return utilerrors.NewAggregate(errs)
@@ -262,23 +989,102 @@ func main() {
 			logrus.Fatal("Failed to validate secret entries.")
 		}
 	}
+	if o.items.StringSet().Len() > 0 || o.itemRegex != "" {
+		filtered, err := filterItems(o.config, o.items.StringSet(), o.itemRegex)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to filter items.")
+		}
+		o.config = filtered
+	}
+	if o.resumeFrom != "" {
+		finished, err := loadCheckpoint(o.resumeFrom)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to load the checkpoint.")
+		}
+		o.finishedItems = finished
+		o.config = skipFinishedItems(o.config, finished)
+		logrus.WithField("finished", finished.Len()).Info("Resuming from the checkpoint, skipping finished items")
+	}
 	if o.validateOnly {
 		logrus.Info("Validation succeeded and --validate-only is set, exiting")
 		return
 	}
+	ctx := interrupts.Context()
+
+	if o.diff {
+		client, err := o.newClient(&censor)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to create secrets client.")
+		}
+		if err := diffSecrets(ctx, o.config, client, o.disabledClusters, os.Stdout); err != nil {
+			logrus.WithError(err).Fatal("Failed to diff secrets.")
+		}
+		return
+	}
+
+	if o.validateCommands {
+		if err := validateCommandsOfItems(ctx, o.config, o.disabledClusters, os.Stdout); err != nil {
+			logrus.WithError(err).Fatal("Failed to validate the commands.")
+		}
+		return
+	}
+
+	if o.ownersReport {
+		if err := ownersReport(o.config, os.Stdout); err != nil {
+			logrus.WithError(err).Fatal("Failed to print the owners report.")
+		}
+		return
+	}
 
-	if errs := generateSecrets(o, &censor); len(errs) > 0 {
+	if o.importKubeconfig != "" {
+		if err := importFromCluster(ctx, o, &censor); err != nil {
+			logrus.WithError(err).Fatal("Failed to import secrets.")
+		}
+		return
+	}
+
+	if o.checkExpiry {
+		client, err := o.newClient(&censor)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to create secrets client.")
+		}
+		if err := checkExpiry(o.config, client, time.Now(), o.expiryMargin, os.Stdout); err != nil {
+			logrus.WithError(err).Fatal("Failed to check the expiry of secrets.")
+		}
+		return
+	}
+
+	if errs := generateSecrets(ctx, o, &censor); len(errs) > 0 {
 		logrus.WithError(utilerrors.NewAggregate(errs)).Fatal("Failed to update secrets.")
 	}
 	logrus.Info("Updated secrets.")
 }
 
-func generateSecrets(o options, censor *secrets.DynamicCensor) (errs []error) {
-	var client secrets.Client
+// importFromCluster imports the Secrets of the cluster of --import-kubeconfig into the secret store
+func importFromCluster(ctx context.Context, o options, censor *secrets.DynamicCensor) error {
+	restConfig, err := clientcmd.BuildConfigFromFlags("", o.importKubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to load the kubeconfig: %w", err)
+	}
+	kubeClient, err := ctrlruntimeclient.New(restConfig, ctrlruntimeclient.Options{})
+	if err != nil {
+		return fmt.Errorf("failed to create the client for the cluster: %w", err)
+	}
+	client, err := o.newClient(censor)
+	if err != nil {
+		return fmt.Errorf("failed to create secrets client: %w", err)
+	}
+	client = secretstore.NewRetryingClient(client, o.maxRetries, o.retryBackoff)
+	return importSecrets(ctx, kubeClient, o.importCluster, o.importNamespace, o.config, o.bootstrapConfig, client, o.dryRun, os.Stdout)
+}
+
+func generateSecrets(ctx context.Context, o options, censor *secrets.DynamicCensor) (errs []error) {
+	var client secretstore.Client
+	var manifests *secretstore.ManifestClient
+	var f *os.File
 
 	if o.dryRun {
 		var err error
-		var f *os.File
 		if o.outputFile == "" {
 			f, err = os.CreateTemp("", "ci-secret-generator")
 			if err != nil {
@@ -291,18 +1097,55 @@ func generateSecrets(o options, censor *secrets.DynamicCensor) (errs []error) {
 				return append(errs, fmt.Errorf("failed to open output file %q: %w", o.outputFile, err))
 			}
 		}
-		client = secrets.NewDryRunClient(f)
+		defer f.Close()
+		if o.outputFormat == outputFormatSecrets {
+			manifests = secretstore.NewManifestClient()
+			client = manifests
+		} else {
+			client = secretstore.NewKVClient(secrets.NewDryRunClient(f))
+		}
 	} else {
 		var err error
-		client, err = o.secrets.NewClient(censor)
+		client, err = o.newClient(censor)
 		if err != nil {
 			return append(errs, fmt.Errorf("failed to create secrets client: %w", err))
 		}
+		client = secretstore.NewRetryingClient(client, o.maxRetries, o.retryBackoff)
 	}
 
-	if err := updateSecrets(o.config, client, o.disabledClusters); err != nil {
+	var report *runReport
+	if o.reportFile != "" || o.pushGateway != "" || o.checkpointFile != "" {
+		report = newRunReport(time.Now())
+	}
+	if err := updateSecrets(ctx, o.config, client, o.disabledClusters, o.maxConcurrency, report); err != nil {
 		errs = append(errs, fmt.Errorf("failed to update secrets: %w", err))
 	}
+	end := time.Now()
+	if o.reportFile != "" {
+		if err := report.write(o.reportFile, end); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write the report: %w", err))
+		}
+	}
+	if o.pushGateway != "" {
+		if err := pushMetrics(http.DefaultClient, o.pushGateway, report, end); err != nil {
+			errs = append(errs, fmt.Errorf("failed to push metrics: %w", err))
+		}
+	}
+	if o.checkpointFile != "" {
+		if err := writeCheckpoint(o.checkpointFile, report, o.finishedItems); err != nil {
+			errs = append(errs, err)
+		} else {
+			logrus.Infof("Wrote the checkpoint to %s", o.checkpointFile)
+		}
+	}
+	if ctx.Err() != nil {
+		errs = append(errs, errors.New("interrupted"))
+	}
+	if manifests != nil {
+		if err := manifests.WriteManifests(f); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write the secrets to %s: %w", f.Name(), err))
+		}
+	}
 
 	return errs
 }
@@ -331,12 +1174,14 @@ func validateContexts(contexts []secretbootstrap.ItemContext, config secretboots
 					found = true
 				}
 				for _, dc := range haystack.DockerConfigJSONData {
-					ctx := secretbootstrap.ItemContext{
-						Item:  strings.TrimPrefix(dc.Item, config.VaultDPTPPrefix+"/"),
-						Field: dc.AuthField,
-					}
-					if reflect.DeepEqual(needle, ctx) {
-						found = true
+					for _, field := range dc.Fields() {
+						ctx := secretbootstrap.ItemContext{
+							Item:  strings.TrimPrefix(dc.Item, config.VaultDPTPPrefix+"/"),
+							Field: field,
+						}
+						if reflect.DeepEqual(needle, ctx) {
+							found = true
+						}
 					}
 				}
 			}
@@ -1,18 +1,26 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	prowv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
 
 	"github.com/openshift/ci-tools/pkg/api/secretbootstrap"
 	"github.com/openshift/ci-tools/pkg/api/secretgenerator"
 	"github.com/openshift/ci-tools/pkg/secrets"
+	"github.com/openshift/ci-tools/pkg/secretstore"
 	"github.com/openshift/ci-tools/pkg/testhelper"
 	"github.com/openshift/ci-tools/pkg/vaultclient"
 )
@@ -69,7 +77,7 @@ func TestVault(t *testing.T) {
 	}
 	censor := secrets.NewDynamicCensor()
 	prefix := "prefix/"
-	client := secrets.NewVaultClient(vault, "secret/"+prefix, &censor)
+	client := secretstore.NewKVClient(secrets.NewVaultClient(vault, "secret/"+prefix, &censor))
 	for _, tc := range []struct {
 		name             string
 		config           secretgenerator.Config
@@ -193,7 +201,7 @@ func TestVault(t *testing.T) {
 					}
 				}
 			}()
-			if err := updateSecrets(tc.config, client, tc.disabledClusters); err != nil {
+			if err := updateSecrets(context.Background(), tc.config, client, tc.disabledClusters, 1, nil); err != nil {
 				t.Errorf("failed to update secrets: %v", err)
 			}
 			list, err := vault.ListKV("secret")
@@ -222,6 +230,420 @@ func TestVault(t *testing.T) {
 	}
 }
 
+// countingClient counts the uploads to the secret store
+type countingClient struct {
+	*secretstore.FakeClient
+	lock    sync.Mutex
+	uploads int
+}
+
+func (c *countingClient) count() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.uploads++
+}
+
+func (c *countingClient) SetField(itemName, fieldName string, value []byte) error {
+	c.count()
+	return c.FakeClient.SetField(itemName, fieldName, value)
+}
+
+func (c *countingClient) UpdateNotes(itemName, notes string) error {
+	c.count()
+	return c.FakeClient.UpdateNotes(itemName, notes)
+}
+
+func TestUpdateSecrets(t *testing.T) {
+	testCases := []struct {
+		name             string
+		config           secretgenerator.Config
+		disabledClusters sets.Set[string]
+		existingItems    map[string]map[string]string
+		existingNotes    map[string]string
+		expectedItems    map[string]map[string]string
+		expectedNotes    map[string]string
+		maxConcurrency   int
+		expectedUploads  int
+		expectedErr      error
+	}{
+		{
+			name: "fields and notes are uploaded",
+			config: secretgenerator.Config{
+				{
+					ItemName: "item",
+					Fields:   []secretgenerator.FieldGenerator{{Name: "a", Cmd: "printf a"}, {Name: "b", Cmd: "printf b"}},
+					Notes:    "notes",
+				},
+			},
+			expectedItems:   map[string]map[string]string{"item": {"a": "a", "b": "b"}},
+			expectedNotes:   map[string]string{"item": "notes"},
+			expectedUploads: 3,
+		},
+		{
+			name: "unchanged fields and notes are not uploaded again",
+			config: secretgenerator.Config{
+				{
+					ItemName: "item",
+					Fields:   []secretgenerator.FieldGenerator{{Name: "a", Cmd: "printf a"}, {Name: "b", Cmd: "printf b"}},
+					Notes:    "notes",
+				},
+			},
+			existingItems:   map[string]map[string]string{"item": {"a": "a", "b": "old"}},
+			existingNotes:   map[string]string{"item": "notes"},
+			expectedItems:   map[string]map[string]string{"item": {"a": "a", "b": "b"}},
+			expectedNotes:   map[string]string{"item": "notes"},
+			expectedUploads: 1,
+		},
+		{
+			name: "notes are appended to the current notes",
+			config: secretgenerator.Config{
+				{ItemName: "a", Notes: "owner: team-a", NotesMode: secretgenerator.NotesModeAppend},
+				{ItemName: "b", Notes: "owner: team-b", NotesMode: secretgenerator.NotesModeAppend},
+				{ItemName: "c", Notes: "owner: team-c", NotesMode: secretgenerator.NotesModeAppend},
+			},
+			existingNotes:   map[string]string{"a": "rotated by hand\n", "b": "rotated by hand\nowner: team-b"},
+			expectedNotes:   map[string]string{"a": "rotated by hand\nowner: team-a", "b": "rotated by hand\nowner: team-b", "c": "owner: team-c"},
+			expectedUploads: 2,
+		},
+		{
+			name: "notes are only set on items without notes",
+			config: secretgenerator.Config{
+				{ItemName: "a", Notes: "notes", NotesMode: secretgenerator.NotesModeSetIfEmpty},
+				{ItemName: "b", Notes: "notes", NotesMode: secretgenerator.NotesModeSetIfEmpty},
+			},
+			existingNotes:   map[string]string{"a": "written by a human"},
+			expectedNotes:   map[string]string{"a": "written by a human", "b": "notes"},
+			expectedUploads: 1,
+		},
+		{
+			name: "fields reference the values of the fields generated before them",
+			config: secretgenerator.Config{
+				{
+					ItemName: "item",
+					Fields: []secretgenerator.FieldGenerator{
+						{Name: "password", Type: secretgenerator.GeneratorRandomString},
+						{Name: "token", Cmd: `printf "it's"`},
+						{Name: "shell", Cmd: "printf %s:%s $(field:password) $(field:token)"},
+						{Name: "args", Args: []string{"printf", "%s", "--token=$(field:token)"}},
+					},
+				},
+			},
+			existingItems:   map[string]map[string]string{"item": {"password": "secret"}},
+			expectedItems:   map[string]map[string]string{"item": {"password": "secret", "token": "it's", "shell": "secret:it's", "args": "--token=it's"}},
+			expectedUploads: 3,
+		},
+		{
+			name: "fields referencing a failed field fail",
+			config: secretgenerator.Config{
+				{
+					ItemName: "item",
+					Fields: []secretgenerator.FieldGenerator{
+						{Name: "token", Cmd: "exit 1"},
+						{Name: "kubeconfig", Cmd: "printf $(field:token)"},
+					},
+				},
+			},
+			expectedErr: errors.New("failed to generate field"),
+		},
+		{
+			name: "fields of disabled clusters are skipped",
+			config: secretgenerator.Config{
+				{
+					ItemName: "item",
+					Fields:   []secretgenerator.FieldGenerator{{Name: "a", Cmd: "printf a", Cluster: "build01"}, {Name: "b", Cmd: "printf b", Cluster: "build02"}},
+				},
+			},
+			disabledClusters: sets.New[string]("build01"),
+			expectedItems:    map[string]map[string]string{"item": {"b": "b"}},
+			expectedUploads:  1,
+		},
+		{
+			name: "failing commands do not stop the other fields",
+			config: secretgenerator.Config{
+				{
+					ItemName: "item",
+					Fields:   []secretgenerator.FieldGenerator{{Name: "a", Cmd: "exit 1"}, {Name: "b", Cmd: "printf b"}},
+				},
+			},
+			expectedItems:   map[string]map[string]string{"item": {"b": "b"}},
+			expectedUploads: 1,
+			expectedErr:     errors.New("failed to generate field"),
+		},
+		{
+			name: "built-in generators only generate fields that do not exist",
+			config: secretgenerator.Config{
+				{
+					ItemName: "item",
+					Fields: []secretgenerator.FieldGenerator{
+						{Name: "existing", Type: secretgenerator.GeneratorRandomString},
+						{Name: "new", Type: secretgenerator.GeneratorRandomString, Length: 3, Charset: "a"},
+					},
+				},
+			},
+			existingItems:   map[string]map[string]string{"item": {"existing": "value"}},
+			expectedItems:   map[string]map[string]string{"item": {"existing": "value", "new": "aaa"}},
+			expectedUploads: 1,
+		},
+		{
+			name: "items are processed in parallel",
+			config: secretgenerator.Config{
+				{ItemName: "a", Fields: []secretgenerator.FieldGenerator{{Name: "field", Cmd: "printf a1", Cluster: "build01"}}},
+				{ItemName: "b", Fields: []secretgenerator.FieldGenerator{{Name: "field", Cmd: "printf b"}}},
+				{ItemName: "a", Fields: []secretgenerator.FieldGenerator{{Name: "other", Cmd: "printf a2", Cluster: "build02"}}},
+				{ItemName: "c", Fields: []secretgenerator.FieldGenerator{{Name: "field", Cmd: "exit 1"}}, Notes: "notes"},
+			},
+			maxConcurrency:  3,
+			expectedItems:   map[string]map[string]string{"a": {"field": "a1", "other": "a2"}, "b": {"field": "b"}},
+			expectedNotes:   map[string]string{"c": "notes"},
+			expectedUploads: 4,
+			expectedErr:     errors.New("failed to generate field"),
+		},
+		{
+			name: "commands timing out are reported separately",
+			config: secretgenerator.Config{
+				{
+					ItemName: "item",
+					Fields: []secretgenerator.FieldGenerator{
+						{Name: "a", Cmd: "sleep 10", Timeout: &prowv1.Duration{Duration: 100 * time.Millisecond}},
+						{Name: "b", Cmd: "exit 1"},
+					},
+				},
+			},
+			expectedErr: errors.New("[timed out generating field, failed to generate field]"),
+		},
+		{
+			name: "the expiry of an item is uploaded",
+			config: secretgenerator.Config{
+				{
+					ItemName: "item",
+					Fields:   []secretgenerator.FieldGenerator{{Name: "a", Cmd: "printf a"}},
+					Expiry:   &metav1.Time{Time: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+			expectedItems:   map[string]map[string]string{"item": {"a": "a", "expires_at": "2026-12-31T00:00:00Z"}},
+			expectedUploads: 2,
+		},
+		{
+			name: "the expiry of unchanged items with a rotation period is relative to when they were generated",
+			config: secretgenerator.Config{
+				{
+					ItemName:       "item",
+					Fields:         []secretgenerator.FieldGenerator{{Name: "a", Cmd: "printf a"}},
+					RotationPeriod: &prowv1.Duration{Duration: 24 * time.Hour},
+				},
+			},
+			existingItems:   map[string]map[string]string{"item": {"a": "a", "generated_at": "2026-01-01T00:00:00Z"}},
+			expectedItems:   map[string]map[string]string{"item": {"a": "a", "generated_at": "2026-01-01T00:00:00Z", "expires_at": "2026-01-02T00:00:00Z"}},
+			expectedUploads: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &countingClient{FakeClient: secretstore.NewFakeClient()}
+			for name, item := range tc.existingItems {
+				client.Items[name] = item
+			}
+			for name, notes := range tc.existingNotes {
+				client.Notes[name] = notes
+			}
+			if tc.maxConcurrency == 0 {
+				tc.maxConcurrency = 1
+			}
+			err := updateSecrets(context.Background(), tc.config, client, tc.disabledClusters, tc.maxConcurrency, nil)
+			if diff := cmp.Diff(tc.expectedErr, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedItems, client.Items, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("items differ from expected: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedNotes, client.Notes, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("notes differ from expected: %s", diff)
+			}
+			if client.uploads != tc.expectedUploads {
+				t.Errorf("expected %d uploads, got %d", tc.expectedUploads, client.uploads)
+			}
+		})
+	}
+}
+
+func TestDiffSecrets(t *testing.T) {
+	client := secretstore.NewFakeClient()
+	client.Items = map[string]map[string]string{"item": {"unchanged": "value", "changed": "old"}}
+	client.Notes = map[string]string{"item": "notes"}
+	config := secretgenerator.Config{
+		{
+			ItemName: "item",
+			Fields: []secretgenerator.FieldGenerator{
+				{Name: "unchanged", Cmd: "printf value"},
+				{Name: "changed", Cmd: "printf new", Cluster: "build01"},
+			},
+			Notes: "notes",
+		},
+		{
+			ItemName: "item",
+			Fields:   []secretgenerator.FieldGenerator{{Name: "changed", Cmd: "printf new", Cluster: "build02"}},
+		},
+		{
+			ItemName: "new-item",
+			Fields:   []secretgenerator.FieldGenerator{{Name: "field", Cmd: "printf value"}},
+			Notes:    "notes",
+		},
+		{
+			ItemName: "disabled",
+			Fields:   []secretgenerator.FieldGenerator{{Name: "field", Cmd: "printf value", Cluster: "build03"}},
+		},
+	}
+	out := &bytes.Buffer{}
+	if err := diffSecrets(context.Background(), config, client, sets.New[string]("build03"), out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `item:
+  unchanged: unchanged
+  changed: changed
+  notes: unchanged
+  changed: changed
+new-item:
+  field: new
+  notes: new
+`
+	if diff := cmp.Diff(expected, out.String()); diff != "" {
+		t.Errorf("diff differs from expected: %s", diff)
+	}
+	if diff := cmp.Diff(map[string]map[string]string{"item": {"unchanged": "value", "changed": "old"}}, client.Items); diff != "" {
+		t.Errorf("expected the secret store to be unchanged: %s", diff)
+	}
+}
+
+func TestValidateCommandsOfItems(t *testing.T) {
+	config := secretgenerator.Config{
+		{
+			ItemName: "item",
+			Fields: []secretgenerator.FieldGenerator{
+				{Name: "valid", Cmd: "printf value", ValidationRegex: "^v"},
+				{Name: "invalid", Cmd: "printf value", ValidationRegex: "^[0-9]+$"},
+				{Name: "failing", Cmd: "exit 1"},
+				{Name: "empty", Cmd: "printf ''"},
+			},
+		},
+		{
+			ItemName: "other",
+			Fields:   []secretgenerator.FieldGenerator{{Name: "key", Type: secretgenerator.GeneratorRandomString}},
+		},
+		{
+			ItemName: "disabled",
+			Fields:   []secretgenerator.FieldGenerator{{Name: "field", Cmd: "exit 1", Cluster: "build01"}},
+		},
+	}
+	out := &bytes.Buffer{}
+	err := validateCommandsOfItems(context.Background(), config, sets.New[string]("build01"), out)
+	if diff := cmp.Diff(errors.New("3 fields failed"), err, testhelper.EquateErrorMessage); diff != "" {
+		t.Errorf("error differs from expected: %s", diff)
+	}
+	expected := `item:
+  valid: ok
+  invalid: invalid output of field
+  failing: failed to generate field
+  empty: failed to generate field
+other:
+  key: ok
+`
+	if diff := cmp.Diff(expected, out.String()); diff != "" {
+		t.Errorf("output differs from expected: %s", diff)
+	}
+}
+
+func TestUpdateExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name          string
+		item          secretgenerator.SecretItem
+		changed       bool
+		existingItems map[string]map[string]string
+		expectedItems map[string]map[string]string
+		expectedErr   error
+	}{
+		{
+			name:          "fixed expiry",
+			item:          secretgenerator.SecretItem{ItemName: "item", Expiry: &metav1.Time{Time: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)}},
+			expectedItems: map[string]map[string]string{"item": {"expires_at": "2026-12-31T00:00:00Z"}},
+		},
+		{
+			name:          "rotation period of changed secrets starts now",
+			item:          secretgenerator.SecretItem{ItemName: "item", RotationPeriod: &prowv1.Duration{Duration: time.Hour}},
+			changed:       true,
+			existingItems: map[string]map[string]string{"item": {"generated_at": "2026-01-01T00:00:00Z"}},
+			expectedItems: map[string]map[string]string{"item": {"generated_at": "2026-03-01T12:00:00Z", "expires_at": "2026-03-01T13:00:00Z"}},
+		},
+		{
+			name:          "rotation period of untracked secrets starts now",
+			item:          secretgenerator.SecretItem{ItemName: "item", RotationPeriod: &prowv1.Duration{Duration: time.Hour}},
+			expectedItems: map[string]map[string]string{"item": {"generated_at": "2026-03-01T12:00:00Z", "expires_at": "2026-03-01T13:00:00Z"}},
+		},
+		{
+			name:          "rotation period of unchanged secrets starts when they were generated",
+			item:          secretgenerator.SecretItem{ItemName: "item", RotationPeriod: &prowv1.Duration{Duration: time.Hour}},
+			existingItems: map[string]map[string]string{"item": {"generated_at": "2026-01-01T00:00:00Z"}},
+			expectedItems: map[string]map[string]string{"item": {"generated_at": "2026-01-01T00:00:00Z", "expires_at": "2026-01-01T01:00:00Z"}},
+		},
+		{
+			name:          "invalid generation time",
+			item:          secretgenerator.SecretItem{ItemName: "item", RotationPeriod: &prowv1.Duration{Duration: time.Hour}},
+			existingItems: map[string]map[string]string{"item": {"generated_at": "yesterday"}},
+			expectedItems: map[string]map[string]string{"item": {"generated_at": "yesterday"}},
+			expectedErr:   errors.New(`failed to parse field generated_at: parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := secretstore.NewFakeClient()
+			for name, item := range tc.existingItems {
+				client.Items[name] = item
+			}
+			_, _, err := updateExpiry(tc.item, client, tc.changed, now)
+			if diff := cmp.Diff(tc.expectedErr, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedItems, client.Items); diff != "" {
+				t.Errorf("items differ from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rotated := &prowv1.Duration{Duration: 24 * time.Hour}
+	client := secretstore.NewFakeClient()
+	client.Items = map[string]map[string]string{
+		"expired":  {"expires_at": "2026-02-01T00:00:00Z"},
+		"expiring": {"expires_at": "2026-03-05T00:00:00Z"},
+		"valid":    {"expires_at": "2026-06-01T00:00:00Z"},
+		"invalid":  {"expires_at": "soon"},
+	}
+	config := secretgenerator.Config{
+		{ItemName: "expired", RotationPeriod: rotated},
+		{ItemName: "expired", RotationPeriod: rotated},
+		{ItemName: "expiring", Expiry: &metav1.Time{Time: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)}},
+		{ItemName: "valid", RotationPeriod: rotated},
+		{ItemName: "invalid", RotationPeriod: rotated},
+		{ItemName: "untracked", RotationPeriod: rotated},
+		{ItemName: "without-expiry"},
+	}
+	out := &bytes.Buffer{}
+	err := checkExpiry(config, client, now, 7*24*time.Hour, out)
+	if diff := cmp.Diff(errors.New("4 items are due for rotation"), err, testhelper.EquateErrorMessage); diff != "" {
+		t.Errorf("error differs from expected: %s", diff)
+	}
+	expected := `expired: expired at 2026-02-01T00:00:00Z
+expiring: expires at 2026-03-05T00:00:00Z
+invalid: invalid expiry "soon"
+untracked: expiry unknown
+`
+	if diff := cmp.Diff(expected, out.String()); diff != "" {
+		t.Errorf("output differs from expected: %s", diff)
+	}
+}
+
 func TestValidateContexts(t *testing.T) {
 	t.Parallel()
 
@@ -329,6 +751,7 @@ func TestExecuteCommand(t *testing.T) {
 	testCases := []struct {
 		name          string
 		cmd           string
+		timeout       *prowv1.Duration
 		expected      []byte
 		expectedError error
 	}{
@@ -366,6 +789,24 @@ output:
 
 error output:
 some error
+`),
+		},
+		{
+			name:     "command finishing within the timeout",
+			cmd:      "echo basic case",
+			timeout:  &prowv1.Duration{Duration: time.Minute},
+			expected: []byte("basic case\n"),
+		},
+		{
+			name:    "error if the command times out",
+			cmd:     "echo partial; sleep 10",
+			timeout: &prowv1.Duration{Duration: 100 * time.Millisecond},
+			expectedError: errors.New(
+				`failed to run command "echo partial; sleep 10": timed out after 100ms
+output (may be incomplete):
+partial
+
+error output (may be incomplete):
 `),
 		},
 		{
@@ -383,7 +824,7 @@ error output:
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			actual, actualError := executeCommand(tc.cmd)
+			actual, actualError := executeCommand(context.Background(), tc.cmd, tc.timeout)
 			if diff := cmp.Diff(tc.expected, actual); diff != "" {
 				t.Errorf("%s: mismatch (-expected +actual), diff: %s", tc.name, diff)
 			}
@@ -394,6 +835,35 @@ error output:
 	}
 }
 
+func TestExecuteArgs(t *testing.T) {
+	actual, err := executeArgs(context.Background(), []string{"printf", "%s", "a; echo $(b)"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("a; echo $(b)", string(actual)); diff != "" {
+		t.Errorf("expected the arguments to not be interpreted by a shell: %s", diff)
+	}
+	_, err = executeArgs(context.Background(), []string{"false"}, nil)
+	if diff := cmp.Diff(errors.New("failed to run command \"false\": exit status 1\noutput:\n\nerror output:\n"), err, testhelper.EquateErrorMessage); diff != "" {
+		t.Errorf("error differs from expected: %s", diff)
+	}
+}
+
+func TestExecuteCommandInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+	_, err := executeCommand(ctx, "sleep 10", &prowv1.Duration{Duration: time.Minute})
+	if !errors.Is(err, errExecCmdInterrupted) {
+		t.Errorf("expected the command to be interrupted, got %v", err)
+	}
+	if msg := generateFieldErrMsg(err); msg != "interrupted generating field" {
+		t.Errorf("expected the error to be reported as interrupted, got %q", msg)
+	}
+}
+
 func TestValidateConfig(t *testing.T) {
 	testcases := []struct {
 		name           string
@@ -404,6 +874,18 @@ func TestValidateConfig(t *testing.T) {
 			name:     "no cluster param",
 			expected: fmt.Errorf(`failed to find params['cluster'] in the 0 item with name "Item1"`),
 		},
+		{
+			name:     "expiry and rotation period",
+			expected: errors.New("config[0]: expiry and rotation_period are mutually exclusive"),
+		},
+		{
+			name:     "reserved field",
+			expected: errors.New("config[0].fields[0]: generated_at and expires_at are reserved for the expiry of the item"),
+		},
+		{
+			name:     "unknown notes mode",
+			expected: errors.New(`config[0].notes_mode: unknown mode "prepend", must be one of replace, append and set-if-empty`),
+		},
 		{
 			name: "valid",
 			expectedConfig: secretgenerator.Config{
@@ -435,3 +917,161 @@ func TestValidateConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultTimeouts(t *testing.T) {
+	config := secretgenerator.Config{
+		{
+			ItemName: "item",
+			Timeout:  &prowv1.Duration{Duration: time.Minute},
+			Fields:   []secretgenerator.FieldGenerator{{Name: "a"}, {Name: "b", Timeout: &prowv1.Duration{Duration: time.Second}}},
+		},
+		{
+			ItemName: "other",
+			Fields:   []secretgenerator.FieldGenerator{{Name: "c"}},
+		},
+	}
+	defaultTimeouts(config, time.Hour)
+	expected := secretgenerator.Config{
+		{
+			ItemName: "item",
+			Timeout:  &prowv1.Duration{Duration: time.Minute},
+			Fields: []secretgenerator.FieldGenerator{
+				{Name: "a", Timeout: &prowv1.Duration{Duration: time.Minute}},
+				{Name: "b", Timeout: &prowv1.Duration{Duration: time.Second}},
+			},
+		},
+		{
+			ItemName: "other",
+			Fields:   []secretgenerator.FieldGenerator{{Name: "c", Timeout: &prowv1.Duration{Duration: time.Hour}}},
+		},
+	}
+	if diff := cmp.Diff(expected, config); diff != "" {
+		t.Errorf("config differs from expected: %s", diff)
+	}
+}
+
+func TestFilterItems(t *testing.T) {
+	config := secretgenerator.Config{
+		{ItemName: "build01-token"},
+		{ItemName: "build02-token"},
+		{ItemName: "registry"},
+		{ItemName: "build01-token", Notes: "second entry"},
+	}
+	testCases := []struct {
+		name        string
+		names       sets.Set[string]
+		regex       string
+		expected    secretgenerator.Config
+		expectedErr error
+	}{
+		{
+			name:     "by name",
+			names:    sets.New[string]("build01-token"),
+			expected: secretgenerator.Config{{ItemName: "build01-token"}, {ItemName: "build01-token", Notes: "second entry"}},
+		},
+		{
+			name:     "by regex",
+			regex:    "^build0[12]-",
+			expected: secretgenerator.Config{{ItemName: "build01-token"}, {ItemName: "build02-token"}, {ItemName: "build01-token", Notes: "second entry"}},
+		},
+		{
+			name:     "by name and regex",
+			names:    sets.New[string]("registry"),
+			regex:    "^build02-",
+			expected: secretgenerator.Config{{ItemName: "build02-token"}, {ItemName: "registry"}},
+		},
+		{
+			name:        "unknown name",
+			names:       sets.New[string]("registry", "build03-token"),
+			expectedErr: errors.New("items not found in the config: build03-token"),
+		},
+		{
+			name:        "nothing matches the regex",
+			regex:       "^build03-",
+			expectedErr: errors.New("no item matches the filters"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.names == nil {
+				tc.names = sets.New[string]()
+			}
+			actual, err := filterItems(config, tc.names, tc.regex)
+			if diff := cmp.Diff(tc.expectedErr, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("items differ from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateOptions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		o        options
+		expected error
+	}{
+		{
+			name: "vault backend",
+			o:    options{logLevel: "info", outputFormat: "text", backend: "vault", dryRun: true, configPath: "config.yaml", maxConcurrency: 1},
+		},
+		{
+			name:     "invalid item regex",
+			o:        options{logLevel: "info", outputFormat: "text", backend: "vault", dryRun: true, configPath: "config.yaml", maxConcurrency: 1, itemRegex: "("},
+			expected: errors.New("--item-regex is invalid: error parsing regexp: missing closing ): `(`"),
+		},
+		{
+			name:     "no concurrency",
+			o:        options{logLevel: "info", outputFormat: "text", backend: "vault", dryRun: true, configPath: "config.yaml"},
+			expected: errors.New("--concurrency must be positive"),
+		},
+		{
+			name:     "unknown backend",
+			o:        options{logLevel: "info", outputFormat: "text", backend: "bitwarden", dryRun: true, configPath: "config.yaml"},
+			expected: errors.New("--backend must be one of [gsm vault]"),
+		},
+		{
+			name:     "vault backend without vault options",
+			o:        options{logLevel: "info", outputFormat: "text", backend: "vault", configPath: "config.yaml"},
+			expected: errors.New("--vault-addr, one of --vault-token, the VAULT_TOKEN env var or --vault-role and --vault-prefix must be specified together"),
+		},
+		{
+			name:     "diff and check-expiry",
+			o:        options{logLevel: "info", outputFormat: "text", backend: "vault", dryRun: true, diff: true, checkExpiry: true, configPath: "config.yaml", maxConcurrency: 1},
+			expected: errors.New("--diff, --check-expiry, --validate-commands, --owners-report and --import-kubeconfig are mutually exclusive"),
+		},
+		{
+			name:     "negative retries",
+			o:        options{logLevel: "info", outputFormat: "text", backend: "vault", dryRun: true, configPath: "config.yaml", maxConcurrency: 1, maxRetries: -1},
+			expected: errors.New("--max-retries must not be negative"),
+		},
+		{
+			name:     "unknown output format",
+			o:        options{logLevel: "info", outputFormat: "json", backend: "vault", dryRun: true, configPath: "config.yaml", maxConcurrency: 1},
+			expected: errors.New("--output-format must be one of text and k8s-secrets"),
+		},
+		{
+			name:     "secrets output without dry-run",
+			o:        options{logLevel: "info", outputFormat: "k8s-secrets", backend: "vault", configPath: "config.yaml", maxConcurrency: 1},
+			expected: errors.New("--output-format=k8s-secrets requires --dry-run"),
+		},
+		{
+			name:     "gsm backend without project",
+			o:        options{logLevel: "info", outputFormat: "text", backend: "gsm", configPath: "config.yaml", maxConcurrency: 1},
+			expected: errors.New("--gcp-project is required with --backend=gsm"),
+		},
+		{
+			name: "gsm backend",
+			o:    options{logLevel: "info", outputFormat: "text", backend: "gsm", gcpProject: "project", configPath: "config.yaml", maxConcurrency: 1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, tc.o.validateOptions(), testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+		})
+	}
+}
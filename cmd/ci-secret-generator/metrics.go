@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+)
+
+// metricsJob is the job the metrics are pushed for
+const metricsJob = "ci-secret-generator"
+
+// itemResults returns the names of the items whose fields and notes all succeeded and of those with a failure
+func (r *runReport) itemResults() (succeeded, failed []string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	results := map[string]bool{}
+	for _, field := range r.Fields {
+		ok, seen := results[field.Item]
+		results[field.Item] = field.Succeeded && (ok || !seen)
+	}
+	for item, ok := range results {
+		if ok {
+			succeeded = append(succeeded, item)
+		} else {
+			failed = append(failed, item)
+		}
+	}
+	sort.Strings(succeeded)
+	sort.Strings(failed)
+	return succeeded, failed
+}
+
+// pushMetrics pushes the metrics of the run to a Prometheus push gateway. The time of the
+// last success of every item is pushed in a group of its own, so that it is kept when the
+// item fails in later runs and stale secrets can be alerted on.
+func pushMetrics(client *http.Client, gateway string, report *runReport, end time.Time) error {
+	succeeded, failed := report.itemResults()
+
+	run := prometheus.NewRegistry()
+	for _, metric := range []struct {
+		name, help string
+		value      float64
+	}{
+		{name: "ci_secret_generator_items_processed", help: "The number of items processed by the last run.", value: float64(len(succeeded) + len(failed))},
+		{name: "ci_secret_generator_items_failed", help: "The number of items with a failure in the last run.", value: float64(len(failed))},
+		{name: "ci_secret_generator_run_duration_seconds", help: "The duration of the last run.", value: end.Sub(report.Start).Seconds()},
+	} {
+		gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: metric.name, Help: metric.help})
+		gauge.Set(metric.value)
+		run.MustRegister(gauge)
+	}
+	gateway = strings.TrimSuffix(gateway, "/")
+	if err := push(client, fmt.Sprintf("%s/metrics/job/%s", gateway, metricsJob), run); err != nil {
+		return err
+	}
+
+	for _, item := range succeeded {
+		registry := prometheus.NewRegistry()
+		gauge := prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "ci_secret_generator_item_last_success_timestamp_seconds",
+			Help: "The time all fields of the item were last generated successfully.",
+		})
+		gauge.Set(float64(end.Unix()))
+		registry.MustRegister(gauge)
+		// item names may contain characters that are not allowed in paths, so they are encoded
+		url := fmt.Sprintf("%s/metrics/job/%s/item@base64/%s", gateway, metricsJob, base64.RawURLEncoding.EncodeToString([]byte(item)))
+		if err := push(client, url, registry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// push replaces the metrics of the group at the URL with the metrics of the registry
+func push(client *http.Client, url string, registry *prometheus.Registry) error {
+	families, err := registry.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+	buf := &bytes.Buffer{}
+	encoder := expfmt.NewEncoder(buf, expfmt.FmtText)
+	for _, family := range families {
+		if err := encoder.Encode(family); err != nil {
+			return fmt.Errorf("failed to encode metrics: %w", err)
+		}
+	}
+	req, err := http.NewRequest(http.MethodPut, url, buf)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", string(expfmt.FmtText))
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push metrics to %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code %d while pushing metrics to %s: %s", resp.StatusCode, url, body)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPushMetrics(t *testing.T) {
+	pushed := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected a PUT, got %s", r.Method)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read body: %v", err)
+		}
+		var lines []string
+		for _, line := range strings.Split(string(body), "\n") {
+			if line != "" && !strings.HasPrefix(line, "#") {
+				lines = append(lines, line)
+			}
+		}
+		pushed[r.URL.Path] = strings.Join(lines, "\n")
+	}))
+	defer server.Close()
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	report := newRunReport(start)
+	report.add("a/b", "field", "", time.Second, true, "", nil)
+	report.add("a/b", "notes", "", time.Second, false, "", nil)
+	report.add("failing", "ok", "", time.Second, true, "", nil)
+	report.add("failing", "field", "", time.Second, false, "failed to generate field", io.EOF)
+	if err := pushMetrics(server.Client(), server.URL+"/", report, start.Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"/metrics/job/ci-secret-generator": `ci_secret_generator_items_failed 1
+ci_secret_generator_items_processed 2
+ci_secret_generator_run_duration_seconds 60`,
+		"/metrics/job/ci-secret-generator/item@base64/YS9i": "ci_secret_generator_item_last_success_timestamp_seconds 1.77232326e+09",
+	}
+	if diff := cmp.Diff(expected, pushed); diff != "" {
+		t.Errorf("pushed metrics differ from expected: %s", diff)
+	}
+}
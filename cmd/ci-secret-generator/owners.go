@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/test-infra/prow/github"
+	"k8s.io/test-infra/prow/repoowners"
+
+	"github.com/openshift/ci-tools/pkg/api/secretgenerator"
+)
+
+// ownershipHeader starts the block of the notes of an item with its owner and the teams to notify
+const ownershipHeader = "[ownership]"
+
+// loadTeamAliases returns the aliases of an OWNERS_ALIASES file, which are the known teams
+func loadTeamAliases(path string) (sets.Set[string], error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the aliases: %w", err)
+	}
+	aliases, err := repoowners.ParseAliasesConfig(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the aliases: %w", err)
+	}
+	return sets.KeySet(aliases), nil
+}
+
+// validateOwnership validates that the owner and the teams to notify of an item are known teams
+func validateOwnership(item secretgenerator.SecretItem, teams sets.Set[string]) error {
+	if len(item.Notify) > 0 && item.Owner == "" {
+		return fmt.Errorf("notify requires an owner")
+	}
+	if teams == nil {
+		return nil
+	}
+	if item.Owner != "" && !teams.Has(github.NormLogin(item.Owner)) {
+		return fmt.Errorf("owner: unknown team %q", item.Owner)
+	}
+	for i, team := range item.Notify {
+		if !teams.Has(github.NormLogin(team)) {
+			return fmt.Errorf("notify[%d]: unknown team %q", i, team)
+		}
+	}
+	return nil
+}
+
+// addOwnershipToNotes appends a block with the owner and the teams to notify to the notes of
+// every item with an owner
+func addOwnershipToNotes(config secretgenerator.Config) {
+	for i, item := range config {
+		if item.Owner == "" {
+			continue
+		}
+		block := fmt.Sprintf("%s\nowner: %s", ownershipHeader, item.Owner)
+		if len(item.Notify) > 0 {
+			block += fmt.Sprintf("\nnotify: %s", strings.Join(item.Notify, ", "))
+		}
+		if item.Notes == "" {
+			config[i].Notes = block
+		} else {
+			config[i].Notes = fmt.Sprintf("%s\n\n%s", item.Notes, block)
+		}
+	}
+}
+
+// ownersReport prints the items without an owner
+func ownersReport(config secretgenerator.Config, out io.Writer) error {
+	var itemNames []string
+	owned := sets.New[string]()
+	for _, item := range config {
+		if item.Owner != "" {
+			owned.Insert(item.ItemName)
+		}
+	}
+	seen := sets.New[string]()
+	for _, item := range config {
+		if owned.Has(item.ItemName) || seen.Has(item.ItemName) {
+			continue
+		}
+		seen.Insert(item.ItemName)
+		itemNames = append(itemNames, item.ItemName)
+	}
+	for _, name := range itemNames {
+		if _, err := fmt.Fprintf(out, "%s: no owner\n", name); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(out, "%d of %d items have no owner\n", len(itemNames), owned.Len()+len(itemNames))
+	return err
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/ci-tools/pkg/api/secretgenerator"
+	"github.com/openshift/ci-tools/pkg/testhelper"
+)
+
+func TestLoadTeamAliases(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "OWNERS_ALIASES")
+	if err := os.WriteFile(path, []byte("aliases:\n  test-platform:\n  - alice\n  Team-B:\n  - bob\n"), 0644); err != nil {
+		t.Fatalf("failed to write aliases: %v", err)
+	}
+	teams, err := loadTeamAliases(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"team-b", "test-platform"}, sets.List(teams)); diff != "" {
+		t.Errorf("teams differ from expected: %s", diff)
+	}
+}
+
+func TestValidateOwnership(t *testing.T) {
+	teams := sets.New[string]("test-platform", "team-b")
+	testCases := []struct {
+		name     string
+		item     secretgenerator.SecretItem
+		teams    sets.Set[string]
+		expected error
+	}{
+		{
+			name:  "known teams",
+			item:  secretgenerator.SecretItem{ItemName: "item", Owner: "test-platform", Notify: []string{"Team-B"}},
+			teams: teams,
+		},
+		{
+			name: "no owner",
+			item: secretgenerator.SecretItem{ItemName: "item"},
+		},
+		{
+			name: "teams are not validated without aliases",
+			item: secretgenerator.SecretItem{ItemName: "item", Owner: "anyone"},
+		},
+		{
+			name:     "unknown owner",
+			item:     secretgenerator.SecretItem{ItemName: "item", Owner: "team-c"},
+			teams:    teams,
+			expected: errors.New(`owner: unknown team "team-c"`),
+		},
+		{
+			name:     "unknown team to notify",
+			item:     secretgenerator.SecretItem{ItemName: "item", Owner: "test-platform", Notify: []string{"team-b", "team-c"}},
+			teams:    teams,
+			expected: errors.New(`notify[1]: unknown team "team-c"`),
+		},
+		{
+			name:     "teams to notify without an owner",
+			item:     secretgenerator.SecretItem{ItemName: "item", Notify: []string{"team-b"}},
+			expected: errors.New("notify requires an owner"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, validateOwnership(tc.item, tc.teams), testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestAddOwnershipToNotes(t *testing.T) {
+	config := secretgenerator.Config{
+		{ItemName: "a", Owner: "test-platform"},
+		{ItemName: "b", Owner: "test-platform", Notify: []string{"team-b", "team-c"}, Notes: "rotated by hand"},
+		{ItemName: "c", Notes: "no owner"},
+	}
+	addOwnershipToNotes(config)
+	expected := secretgenerator.Config{
+		{ItemName: "a", Owner: "test-platform", Notes: "[ownership]\nowner: test-platform"},
+		{ItemName: "b", Owner: "test-platform", Notify: []string{"team-b", "team-c"}, Notes: "rotated by hand\n\n[ownership]\nowner: test-platform\nnotify: team-b, team-c"},
+		{ItemName: "c", Notes: "no owner"},
+	}
+	if diff := cmp.Diff(expected, config); diff != "" {
+		t.Errorf("config differs from expected: %s", diff)
+	}
+}
+
+func TestOwnersReport(t *testing.T) {
+	config := secretgenerator.Config{
+		{ItemName: "owned", Owner: "test-platform"},
+		{ItemName: "unowned"},
+		{ItemName: "owned"},
+		{ItemName: "other"},
+		{ItemName: "unowned"},
+	}
+	out := &bytes.Buffer{}
+	if err := ownersReport(config, out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `unowned: no owner
+other: no owner
+2 of 3 items have no owner
+`
+	if diff := cmp.Diff(expected, out.String()); diff != "" {
+		t.Errorf("report differs from expected: %s", diff)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// runReport is the machine-readable report of a run. It never contains generated values.
+// A nil runReport ignores everything added to it.
+type runReport struct {
+	lock sync.Mutex
+
+	Start           time.Time     `json:"start"`
+	DurationSeconds float64       `json:"duration_seconds"`
+	Fields          []fieldReport `json:"fields"`
+}
+
+// fieldReport is the result of generating a field or the notes of an item
+type fieldReport struct {
+	Item            string  `json:"item"`
+	Field           string  `json:"field"`
+	Cluster         string  `json:"cluster,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Succeeded       bool    `json:"succeeded"`
+	Changed         bool    `json:"changed"`
+	Error           string  `json:"error,omitempty"`
+}
+
+func newRunReport(start time.Time) *runReport {
+	return &runReport{Start: start}
+}
+
+// add adds the result of a field. The error is redacted to not include the output of commands.
+func (r *runReport) add(item, field, cluster string, duration time.Duration, changed bool, msg string, err error) {
+	if r == nil {
+		return
+	}
+	result := fieldReport{
+		Item:            item,
+		Field:           field,
+		Cluster:         cluster,
+		DurationSeconds: duration.Seconds(),
+		Succeeded:       err == nil,
+		Changed:         changed,
+	}
+	if err != nil {
+		result.Error = fmt.Sprintf("%s: %s", msg, redactError(err))
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.Fields = append(r.Fields, result)
+}
+
+// write writes the report to a file, with the fields sorted as items are processed in parallel
+func (r *runReport) write(path string, end time.Time) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.DurationSeconds = end.Sub(r.Start).Seconds()
+	sort.SliceStable(r.Fields, func(i, j int) bool {
+		if r.Fields[i].Item != r.Fields[j].Item {
+			return r.Fields[i].Item < r.Fields[j].Item
+		}
+		return r.Fields[i].Field < r.Fields[j].Field
+	})
+	raw, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the report: %w", err)
+	}
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		return fmt.Errorf("failed to write the report to %s: %w", path, err)
+	}
+	return nil
+}
+
+// redactError returns the message of the error without the output of failed commands
+func redactError(err error) string {
+	var cmdErr *execCmdError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.redacted()
+	}
+	return err.Error()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/ci-tools/pkg/api/secretgenerator"
+	"github.com/openshift/ci-tools/pkg/secretstore"
+)
+
+func TestRunReport(t *testing.T) {
+	client := secretstore.NewFakeClient()
+	client.Items = map[string]map[string]string{"item": {"unchanged": "value"}}
+	config := secretgenerator.Config{
+		{
+			ItemName: "item",
+			Fields: []secretgenerator.FieldGenerator{
+				{Name: "unchanged", Cmd: "printf value"},
+				{Name: "changed", Cmd: "printf value", Cluster: "build01"},
+				{Name: "failing", Cmd: "printf secret; exit 1"},
+			},
+			Notes: "notes",
+		},
+		{
+			ItemName: "disabled",
+			Fields:   []secretgenerator.FieldGenerator{{Name: "field", Cmd: "printf value", Cluster: "build02"}},
+		},
+	}
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	report := newRunReport(start)
+	if err := updateSecrets(context.Background(), config, client, sets.New[string]("build02"), 1, report); err == nil {
+		t.Fatal("expected the failing command to fail")
+	}
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := report.write(path, start.Add(time.Minute)); err != nil {
+		t.Fatalf("failed to write the report: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read the report: %v", err)
+	}
+	var actual runReport
+	if err := json.Unmarshal(raw, &actual); err != nil {
+		t.Fatalf("failed to unmarshal the report: %v", err)
+	}
+	for i := range actual.Fields {
+		actual.Fields[i].DurationSeconds = 0
+	}
+	expected := []fieldReport{
+		{Item: "item", Field: "changed", Cluster: "build01", Succeeded: true, Changed: true},
+		{Item: "item", Field: "failing", Error: `failed to generate field: failed to run command "printf secret; exit 1": exit status 1`},
+		{Item: "item", Field: "notes", Succeeded: true, Changed: true},
+		{Item: "item", Field: "unchanged", Succeeded: true},
+	}
+	if diff := cmp.Diff(expected, actual.Fields); diff != "" {
+		t.Errorf("report differs from expected: %s", diff)
+	}
+	if actual.DurationSeconds != 60 || !actual.Start.Equal(start) {
+		t.Errorf("expected a run of 60s starting at %s, got %fs starting at %s", start, actual.DurationSeconds, actual.Start)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	prowv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+)
+
+// sha256Checksum matches a hex-encoded SHA-256 checksum
+var sha256Checksum = regexp.MustCompile(`^[a-fA-F0-9]{64}$`)
+
+// readFile returns the content of a file attached to a field
+func readFile(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(content) == 0 {
+		return nil, fmt.Errorf("file %s is empty", path)
+	}
+	return content, nil
+}
+
+// fetchURL downloads the content of a URL attached to a field and, if a checksum is given,
+// verifies that its SHA-256 checksum matches. A timeout of nil means that the download may
+// take indefinitely. The download is aborted when the context is cancelled.
+func fetchURL(ctx context.Context, url, checksum string, timeout *prowv1.Duration) ([]byte, error) {
+	parent := ctx
+	if timeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout.Duration)
+		defer cancel()
+	}
+	content, err := download(ctx, url)
+	if err != nil {
+		if parent.Err() != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", url, errExecCmdInterrupted)
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("failed to download %s: %w after %s", url, errExecCmdTimedOut, timeout.Duration)
+		}
+		return nil, err
+	}
+	if checksum != "" {
+		sum := sha256.Sum256(content)
+		if actual := hex.EncodeToString(sum[:]); actual != strings.ToLower(checksum) {
+			return nil, fmt.Errorf("checksum of %s is %s, expected %s", url, actual, checksum)
+		}
+	}
+	return content, nil
+}
+
+func download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d while downloading %s", resp.StatusCode, url)
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	if len(content) == 0 {
+		return nil, fmt.Errorf("%s is empty", url)
+	}
+	return content, nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	prowv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+
+	"github.com/openshift/ci-tools/pkg/api/secretgenerator"
+	"github.com/openshift/ci-tools/pkg/testhelper"
+)
+
+func TestGenerateFieldFromSources(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ca.crt"), []byte("certificate"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "empty"), nil, 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ca.crt":
+			_, _ = w.Write([]byte("certificate"))
+		case "/slow":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(10 * time.Second):
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name        string
+		field       secretgenerator.FieldGenerator
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "file",
+			field:    secretgenerator.FieldGenerator{Name: "field", File: filepath.Join(dir, "ca.crt")},
+			expected: "certificate",
+		},
+		{
+			name:        "empty file",
+			field:       secretgenerator.FieldGenerator{Name: "field", File: filepath.Join(dir, "empty")},
+			expectedErr: fmt.Errorf("file %s is empty", filepath.Join(dir, "empty")),
+		},
+		{
+			name:     "url",
+			field:    secretgenerator.FieldGenerator{Name: "field", URL: server.URL + "/ca.crt"},
+			expected: "certificate",
+		},
+		{
+			name:     "url with a matching checksum",
+			field:    secretgenerator.FieldGenerator{Name: "field", URL: server.URL + "/ca.crt", SHA256: "03D66DD08835C1CA3F128CCEACD1F31AC94163096B20F445AE84285BC0832D72"},
+			expected: "certificate",
+		},
+		{
+			name:        "url with a checksum mismatch",
+			field:       secretgenerator.FieldGenerator{Name: "field", URL: server.URL + "/ca.crt", SHA256: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+			expectedErr: fmt.Errorf("checksum of %s/ca.crt is 03d66dd08835c1ca3f128cceacd1f31ac94163096b20f445ae84285bc0832d72, expected e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", server.URL),
+		},
+		{
+			name:        "url not found",
+			field:       secretgenerator.FieldGenerator{Name: "field", URL: server.URL + "/missing"},
+			expectedErr: fmt.Errorf("unexpected status code 404 while downloading %s/missing", server.URL),
+		},
+		{
+			name:        "url timing out",
+			field:       secretgenerator.FieldGenerator{Name: "field", URL: server.URL + "/slow", Timeout: &prowv1.Duration{Duration: 100 * time.Millisecond}},
+			expectedErr: fmt.Errorf("failed to download %s/slow: timed out after 100ms", server.URL),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := generateField(context.Background(), tc.field)
+			if diff := cmp.Diff(tc.expectedErr, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Fatalf("error differs from expected: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if len(values) != 1 || values[0].field != "field" {
+				t.Fatalf("expected a single value for the field, got %v", values)
+			}
+			if diff := cmp.Diff(tc.expected, string(values[0].value)); diff != "" {
+				t.Errorf("value differs from expected: %s", diff)
+			}
+		})
+	}
+	t.Run("timeouts are reported like for commands", func(t *testing.T) {
+		_, err := generateField(context.Background(), secretgenerator.FieldGenerator{Name: "field", URL: server.URL + "/slow", Timeout: &prowv1.Duration{Duration: 100 * time.Millisecond}})
+		if !errors.Is(err, errExecCmdTimedOut) {
+			t.Errorf("expected a timeout, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// healthyReadyNodeRatio is the minimal ratio of ready nodes for a cluster to be considered healthy
+	healthyReadyNodeRatio = 0.9
+	// podPhaseField is the field selector for the phase of a pod
+	podPhaseField = "status.phase"
+	// capacityErrorCacheDuration is how long the capacity is cached when some clusters failed
+	capacityErrorCacheDuration = 30 * time.Second
+)
+
+// ClusterCapacity describes the capacity and health of a cluster
+type ClusterCapacity struct {
+	Cluster string       `json:"cluster"`
+	Healthy bool         `json:"healthy"`
+	Nodes   NodeCapacity `json:"nodes"`
+	// Allocatable is the sum of the allocatable resources of the ready and schedulable nodes
+	Allocatable corev1.ResourceList `json:"allocatable,omitempty"`
+	PendingPods int                 `json:"pendingPods"`
+	Quotas      []QuotaUsage        `json:"quotas,omitempty"`
+	Error       string              `json:"error,omitempty"`
+}
+
+// NodeCapacity summarizes the nodes of a cluster
+type NodeCapacity struct {
+	Total         int `json:"total"`
+	Ready         int `json:"ready"`
+	Unschedulable int `json:"unschedulable"`
+}
+
+// QuotaUsage describes the usage of a resource quota
+type QuotaUsage struct {
+	Namespace string              `json:"namespace"`
+	Name      string              `json:"name"`
+	Hard      corev1.ResourceList `json:"hard,omitempty"`
+	Used      corev1.ResourceList `json:"used,omitempty"`
+}
+
+type CapacityPage struct {
+	Data []ClusterCapacity `json:"data"`
+}
+
+func isNodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func getClusterCapacity(ctx context.Context, cluster string, client ctrlruntimeclient.Client, quotaNamespaces []string) (ClusterCapacity, error) {
+	capacity := ClusterCapacity{Cluster: cluster, Allocatable: corev1.ResourceList{}}
+
+	nodes := &corev1.NodeList{}
+	if err := client.List(ctx, nodes); err != nil {
+		return capacity, fmt.Errorf("failed to list nodes for cluster %s: %w", cluster, err)
+	}
+	for _, node := range nodes.Items {
+		capacity.Nodes.Total++
+		if node.Spec.Unschedulable {
+			capacity.Nodes.Unschedulable++
+		}
+		if !isNodeReady(node) {
+			continue
+		}
+		capacity.Nodes.Ready++
+		if node.Spec.Unschedulable {
+			continue
+		}
+		for name, quantity := range node.Status.Allocatable {
+			total := capacity.Allocatable[name]
+			total.Add(quantity)
+			capacity.Allocatable[name] = total
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if err := client.List(ctx, pods, ctrlruntimeclient.MatchingFields{podPhaseField: string(corev1.PodPending)}); err != nil {
+		return capacity, fmt.Errorf("failed to list pending pods for cluster %s: %w", cluster, err)
+	}
+	capacity.PendingPods = len(pods.Items)
+
+	for _, namespace := range quotaNamespaces {
+		quotas := &corev1.ResourceQuotaList{}
+		if err := client.List(ctx, quotas, ctrlruntimeclient.InNamespace(namespace)); err != nil {
+			return capacity, fmt.Errorf("failed to list resource quotas in namespace %s for cluster %s: %w", namespace, cluster, err)
+		}
+		for _, quota := range quotas.Items {
+			capacity.Quotas = append(capacity.Quotas, QuotaUsage{
+				Namespace: quota.Namespace,
+				Name:      quota.Name,
+				Hard:      quota.Status.Hard,
+				Used:      quota.Status.Used,
+			})
+		}
+	}
+
+	capacity.Healthy = capacity.Nodes.Total > 0 && float64(capacity.Nodes.Ready)/float64(capacity.Nodes.Total) >= healthyReadyNodeRatio
+	return capacity, nil
+}
+
+func getCapacity(ctx context.Context, clients map[string]ctrlruntimeclient.Client, quotaNamespaces []string) []ClusterCapacity {
+	logrus.Debug("Calling getCapacity ...")
+	var data []ClusterCapacity
+
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	for cluster, client := range clients {
+		cluster, client := cluster, client
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			capacity, err := getClusterCapacity(ctx, cluster, client, quotaNamespaces)
+			if err != nil {
+				logrus.WithError(err).Warn("Failed to get cluster capacity")
+				capacity = ClusterCapacity{Cluster: cluster, Error: "an error occurred while retrieving cluster capacity"}
+			}
+			lock.Lock()
+			data = append(data, capacity)
+			lock.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(data, func(i, j int) bool {
+		return data[i].Cluster < data[j].Cluster
+	})
+	return data
+}
+
+func (c *memoryCache) GetCapacityPage(ctx context.Context, clients map[string]ctrlruntimeclient.Client, quotaNamespaces []string) *CapacityPage {
+	c.capacityDataMutex.Lock()
+	defer c.capacityDataMutex.Unlock()
+	cacheDuration := c.CapacityCacheDuration
+	if c.capacityDataHasErrors && c.CapacityErrorCacheDuration < cacheDuration {
+		cacheDuration = c.CapacityErrorCacheDuration
+	}
+	if c.capacityData == nil || !time.Now().Before(c.capacityDataLastUpdatedAt.Add(cacheDuration)) {
+		c.capacityData = getCapacity(ctx, clients, quotaNamespaces)
+		c.capacityDataLastUpdatedAt = time.Now()
+		c.capacityDataHasErrors = false
+		for _, capacity := range c.capacityData {
+			if capacity.Error != "" {
+				c.capacityDataHasErrors = true
+				break
+			}
+		}
+	}
+	return &CapacityPage{Data: c.capacityData}
+}
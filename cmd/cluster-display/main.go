@@ -46,6 +46,8 @@ func gatherOptions() (options, error) {
 	fs.IntVar(&o.port, "port", 8090, "Port to run the server on")
 	o.kubernetesOptions.AddFlags(fs)
 	fs.DurationVar(&o.gracePeriod, "gracePeriod", time.Second*10, "Grace period for server shutdown")
+	fs.Var(&o.quotaNamespaces, "quota-namespace", "Namespace whose resource quotas are reported by the capacity API. Can be passed multiple times.")
+	fs.DurationVar(&o.capacityCacheDuration, "capacity-cache-duration", time.Minute, "Duration for which the capacity of the clusters is cached")
 	if err := fs.Parse(os.Args[1:]); err != nil {
 		return o, fmt.Errorf("failed to parse flags: %w", err)
 	}
@@ -61,10 +63,12 @@ func validateOptions(o options) error {
 }
 
 type options struct {
-	logLevel          string
-	port              int
-	gracePeriod       time.Duration
-	kubernetesOptions flagutil.KubernetesOptions
+	logLevel              string
+	port                  int
+	gracePeriod           time.Duration
+	kubernetesOptions     flagutil.KubernetesOptions
+	quotaNamespaces       flagutil.Strings
+	capacityCacheDuration time.Duration
 }
 
 func addSchemes() error {
@@ -199,7 +203,16 @@ type memoryCache struct {
 	ClusterPoolData              []map[string]string
 	ClusterPoolDataLastUpdatedAt time.Time
 
-	CacheDuration time.Duration
+	capacityDataMutex         sync.Mutex
+	capacityData              []ClusterCapacity
+	capacityDataLastUpdatedAt time.Time
+	capacityDataHasErrors     bool
+
+	CacheDuration         time.Duration
+	CapacityCacheDuration time.Duration
+	// CapacityErrorCacheDuration is how long the capacity is cached when it could not be
+	// retrieved for some clusters, so that a temporary error does not hide them for long
+	CapacityErrorCacheDuration time.Duration
 }
 
 func (c *memoryCache) GetClusterPoolPage(ctx context.Context, client ctrlruntimeclient.Client) (*Page, error) {
@@ -282,9 +295,9 @@ func resolveProduct(ctx context.Context, client ctrlruntimeclient.Client, versio
 	return "OSD", nil
 }
 
-func getRouter(ctx context.Context, hiveClient ctrlruntimeclient.Client, clients map[string]ctrlruntimeclient.Client, prowDisabledClusters []string) *http.ServeMux {
+func getRouter(ctx context.Context, hiveClient ctrlruntimeclient.Client, clients map[string]ctrlruntimeclient.Client, prowDisabledClusters []string, quotaNamespaces []string, capacityCacheDuration time.Duration) *http.ServeMux {
 	handler := http.NewServeMux()
-	cache := memoryCache{CacheDuration: time.Hour}
+	cache := memoryCache{CacheDuration: time.Hour, CapacityCacheDuration: capacityCacheDuration, CapacityErrorCacheDuration: capacityErrorCacheDuration}
 
 	handler.HandleFunc("/api/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -298,29 +311,54 @@ func getRouter(ctx context.Context, hiveClient ctrlruntimeclient.Client, clients
 	for cluster, client := range clients {
 		allClients[cluster] = client
 	}
+	disabledClusters := sets.New[string](prowDisabledClusters...)
+	enabledClients := map[string]ctrlruntimeclient.Client{}
+	for cluster, client := range allClients {
+		if !disabledClusters.Has(cluster) {
+			enabledClients[cluster] = client
+		}
+	}
 	writeRespond := func(crd string, w http.ResponseWriter, r *http.Request) {
-		var page *Page
+		var page interface{}
 		var err error
 		switch crd {
 		case "clusterpools":
 			page, err = cache.GetClusterPoolPage(ctx, hiveClient)
 		case "clusters":
 			skipHive := r.URL.Query().Get("skipHive") == "true"
-			disabledClusters := sets.New[string](prowDisabledClusters...)
-			page = cache.GetClusterPage(ctx, allClients, skipHive, &clusterInfoGetter{})
+			clusterPage := cache.GetClusterPage(ctx, allClients, skipHive, &clusterInfoGetter{})
 			var enabled []map[string]string
-			for _, d := range page.Data {
+			for _, d := range clusterPage.Data {
 				c, ok := d["cluster"]
 				if ok && !disabledClusters.Has(c) {
 					enabled = append(enabled, d)
 				}
 			}
-			page.Data = enabled
+			clusterPage.Data = enabled
 			noDups := disabledClusters.UnsortedList()
 			sort.Strings(noDups)
 			for _, cluster := range noDups {
-				page.Data = append(page.Data, map[string]string{"cluster": cluster, "error": "disabled cluster in Prow"})
+				clusterPage.Data = append(clusterPage.Data, map[string]string{"cluster": cluster, "error": "disabled cluster in Prow"})
 			}
+			page = clusterPage
+		case "capacity":
+			capacityPage := &CapacityPage{Data: []ClusterCapacity{}}
+			cluster := r.URL.Query().Get("cluster")
+			for _, c := range cache.GetCapacityPage(ctx, enabledClients, quotaNamespaces).Data {
+				if cluster == "" || c.Cluster == cluster {
+					capacityPage.Data = append(capacityPage.Data, c)
+				}
+			}
+			for _, c := range sets.List(disabledClusters) {
+				if cluster == "" || c == cluster {
+					capacityPage.Data = append(capacityPage.Data, ClusterCapacity{Cluster: c, Error: "disabled cluster in Prow"})
+				}
+			}
+			if cluster != "" && len(capacityPage.Data) == 0 {
+				http.Error(w, fmt.Sprintf("Unknown cluster: %s", cluster), http.StatusNotFound)
+				return
+			}
+			page = capacityPage
 		default:
 			http.Error(w, fmt.Sprintf("Unknown crd: %s", crd), http.StatusBadRequest)
 			return
@@ -360,6 +398,11 @@ func getRouter(ctx context.Context, hiveClient ctrlruntimeclient.Client, clients
 		logrus.WithField("path", "/api/v1/clusters").Info("serving")
 		writeRespond("clusters", w, r)
 	})
+
+	handler.HandleFunc("/api/v1/capacity", func(w http.ResponseWriter, r *http.Request) {
+		logrus.WithField("path", "/api/v1/capacity").Info("serving")
+		writeRespond("capacity", w, r)
+	})
 	return handler
 }
 
@@ -435,7 +478,7 @@ func main() {
 
 	server := &http.Server{
 		Addr:    ":" + strconv.Itoa(o.port),
-		Handler: getRouter(interrupts.Context(), hiveClient, clients, prowDisabledClusters),
+		Handler: getRouter(interrupts.Context(), hiveClient, clients, prowDisabledClusters, o.quotaNamespaces.Strings(), o.capacityCacheDuration),
 	}
 	interrupts.ListenAndServe(server, o.gracePeriod)
 	interrupts.WaitForGracefulShutdown()
@@ -6,10 +6,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -136,6 +140,40 @@ func TestGetRouter(t *testing.T) {
 `,
 			expectedContentType: "application/json",
 		},
+		{
+			name:       "capacity of all clusters",
+			url:        "/api/v1/capacity",
+			hiveClient: fakeCapacityClient(),
+			clients: map[string]ctrlruntimeclient.Client{
+				"a": fakeCapacityClient(aNode("node-1", true, false)),
+				"b": fakeCapacityClient(),
+			},
+			disabledClusters: []string{"b"},
+			expectedCode:     200,
+			expectedBody: `{"data":[{"cluster":"a","healthy":true,"nodes":{"total":1,"ready":1,"unschedulable":0},"allocatable":{"cpu":"4","memory":"16Gi"},"pendingPods":0},{"cluster":"hive","healthy":false,"nodes":{"total":0,"ready":0,"unschedulable":0},"pendingPods":0},{"cluster":"b","healthy":false,"nodes":{"total":0,"ready":0,"unschedulable":0},"pendingPods":0,"error":"disabled cluster in Prow"}]}
+`,
+			expectedContentType: "application/json",
+		},
+		{
+			name:       "capacity of a single cluster",
+			url:        "/api/v1/capacity?cluster=a",
+			hiveClient: fakeCapacityClient(),
+			clients: map[string]ctrlruntimeclient.Client{
+				"a": fakeCapacityClient(aNode("node-1", true, false)),
+			},
+			expectedCode: 200,
+			expectedBody: `{"data":[{"cluster":"a","healthy":true,"nodes":{"total":1,"ready":1,"unschedulable":0},"allocatable":{"cpu":"4","memory":"16Gi"},"pendingPods":0}]}
+`,
+			expectedContentType: "application/json",
+		},
+		{
+			name:                "capacity of an unknown cluster",
+			url:                 "/api/v1/capacity?cluster=unknown",
+			hiveClient:          fakeCapacityClient(),
+			expectedCode:        404,
+			expectedBody:        "Unknown cluster: unknown\n",
+			expectedContentType: "text/plain; charset=utf-8",
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -145,7 +183,7 @@ func TestGetRouter(t *testing.T) {
 			}
 
 			rr := httptest.NewRecorder()
-			router := getRouter(context.TODO(), tc.hiveClient, tc.clients, tc.disabledClusters)
+			router := getRouter(context.TODO(), tc.hiveClient, tc.clients, tc.disabledClusters, []string{"ci"}, time.Minute)
 			router.ServeHTTP(rr, req)
 
 			if diff := cmp.Diff(tc.expectedCode, rr.Code); diff != "" {
@@ -161,6 +199,160 @@ func TestGetRouter(t *testing.T) {
 	}
 }
 
+func aNode(name string, ready, unschedulable bool) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+		Status: corev1.NodeStatus{
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+			Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("16Gi")},
+		},
+	}
+}
+
+func aPod(name string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ci-op-1234", Name: name},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+func fakeCapacityClient(objs ...runtime.Object) ctrlruntimeclient.Client {
+	return fakectrlruntimeclient.NewClientBuilder().
+		WithRuntimeObjects(objs...).
+		WithIndex(&corev1.Pod{}, podPhaseField, func(o ctrlruntimeclient.Object) []string {
+			return []string{string(o.(*corev1.Pod).Status.Phase)}
+		}).
+		Build()
+}
+
+func TestGetClusterCapacity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		client   ctrlruntimeclient.Client
+		expected ClusterCapacity
+	}{
+		{
+			name:   "empty cluster is not healthy",
+			client: fakeCapacityClient(),
+			expected: ClusterCapacity{
+				Cluster:     "build01",
+				Allocatable: corev1.ResourceList{},
+			},
+		},
+		{
+			name: "nodes, pods and quotas",
+			client: fakeCapacityClient(
+				aNode("ready", true, false),
+				aNode("cordoned", true, true),
+				aPod("pending-1", corev1.PodPending),
+				aPod("pending-2", corev1.PodPending),
+				aPod("running", corev1.PodRunning),
+				&corev1.ResourceQuota{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "quota"},
+					Status: corev1.ResourceQuotaStatus{
+						Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("100")},
+						Used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("42")},
+					},
+				},
+				&corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "quota"}},
+			),
+			expected: ClusterCapacity{
+				Cluster:     "build01",
+				Healthy:     true,
+				Nodes:       NodeCapacity{Total: 2, Ready: 2, Unschedulable: 1},
+				Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("16Gi")},
+				PendingPods: 2,
+				Quotas: []QuotaUsage{{
+					Namespace: "ci",
+					Name:      "quota",
+					Hard:      corev1.ResourceList{corev1.ResourcePods: resource.MustParse("100")},
+					Used:      corev1.ResourceList{corev1.ResourcePods: resource.MustParse("42")},
+				}},
+			},
+		},
+		{
+			name:   "too many nodes not ready",
+			client: fakeCapacityClient(aNode("ready", true, false), aNode("not-ready", false, false)),
+			expected: ClusterCapacity{
+				Cluster:     "build01",
+				Nodes:       NodeCapacity{Total: 2, Ready: 1},
+				Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("16Gi")},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := getClusterCapacity(context.TODO(), "build01", tc.client, []string{"ci"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("result differs from expected output, diff:\n%s", diff)
+			}
+		})
+	}
+}
+
+// flakyClient fails to list until it is told to recover
+type flakyClient struct {
+	ctrlruntimeclient.Client
+	failing bool
+}
+
+func (c *flakyClient) List(ctx context.Context, list ctrlruntimeclient.ObjectList, opts ...ctrlruntimeclient.ListOption) error {
+	if c.failing {
+		return fmt.Errorf("injected failure")
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+func TestGetCapacityPageCaching(t *testing.T) {
+	testCases := []struct {
+		name               string
+		errorCacheDuration time.Duration
+		expectedHealthy    bool
+	}{
+		{
+			name:               "errors are not cached longer than the error cache duration",
+			errorCacheDuration: 0,
+			expectedHealthy:    true,
+		},
+		{
+			name:               "errors are cached for the error cache duration",
+			errorCacheDuration: time.Hour,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &flakyClient{Client: fakeCapacityClient(aNode("ready", true, false)), failing: true}
+			clients := map[string]ctrlruntimeclient.Client{"build01": client}
+			cache := memoryCache{CapacityCacheDuration: time.Hour, CapacityErrorCacheDuration: tc.errorCacheDuration}
+
+			page := cache.GetCapacityPage(context.TODO(), clients, nil)
+			if len(page.Data) != 1 || page.Data[0].Error == "" {
+				t.Fatalf("expected an error for the failing cluster, got %v", page.Data)
+			}
+
+			client.failing = false
+			page = cache.GetCapacityPage(context.TODO(), clients, nil)
+			if page.Data[0].Healthy != tc.expectedHealthy {
+				t.Errorf("expected healthy to be %t, got %v", tc.expectedHealthy, page.Data[0])
+			}
+
+			// successful results are cached for the full duration
+			client.failing = true
+			if healthy := cache.GetCapacityPage(context.TODO(), clients, nil).Data[0].Healthy; healthy != tc.expectedHealthy {
+				t.Errorf("expected the cached result, got healthy=%t", healthy)
+			}
+		})
+	}
+}
+
 type fakeClusterGetter struct{}
 
 func (g *fakeClusterGetter) GetClusterDetails(ctx context.Context, cluster string, client ctrlruntimeclient.Client) (map[string]string, error) {
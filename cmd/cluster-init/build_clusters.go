@@ -12,22 +12,40 @@ import (
 type BuildClusters struct {
 	Managed []string `json:"managed,omitempty"`
 	Hosted  []string `json:"hosted,omitempty"`
+	// RegistryHosts are the hosts of the image registries of the clusters whose registry is
+	// not exposed on the default host, so that updates keep generating the same configuration
+	RegistryHosts map[string]string `json:"registryHosts,omitempty"`
 }
 
 func updateBuildClusters(o options) error {
-	if o.unmanaged {
-		logrus.Infof("skipping build clusters config update for unmanaged cluster: %s", o.clusterName)
-		return nil
-	}
-	logrus.Infof("updating build clusters config to add: %s", o.clusterName)
 	buildClusters, err := loadBuildClusters(o)
 	if err != nil {
 		return err
 	}
 
-	buildClusters.Managed = append(buildClusters.Managed, o.clusterName)
-	if o.hosted {
-		buildClusters.Hosted = append(buildClusters.Hosted, o.clusterName)
+	var changed bool
+	if !o.update {
+		if o.unmanaged {
+			logrus.Infof("skipping adding unmanaged cluster to the build clusters config: %s", o.clusterName)
+		} else {
+			logrus.Infof("updating build clusters config to add: %s", o.clusterName)
+			buildClusters.Managed = append(buildClusters.Managed, o.clusterName)
+			if o.hosted {
+				buildClusters.Hosted = append(buildClusters.Hosted, o.clusterName)
+			}
+			changed = true
+		}
+	}
+	if o.registryHost != "" && buildClusters.RegistryHosts[o.clusterName] != o.registryHost {
+		logrus.Infof("recording the registry host of %s in the build clusters config: %s", o.clusterName, o.registryHost)
+		if buildClusters.RegistryHosts == nil {
+			buildClusters.RegistryHosts = map[string]string{}
+		}
+		buildClusters.RegistryHosts[o.clusterName] = o.registryHost
+		changed = true
+	}
+	if !changed {
+		return nil
 	}
 
 	rawYaml, err := yaml.Marshal(buildClusters)
@@ -50,6 +68,16 @@ func loadBuildClusters(o options) (*BuildClusters, error) {
 	return &buildClusters, nil
 }
 
+// optionsForCluster returns the options to generate the configuration of the cluster with. The
+// registry host recorded for the cluster is used unless one was given, e.g. by a cluster descriptor.
+func optionsForCluster(o options, buildClusters *BuildClusters, cluster string) options {
+	o.clusterName = cluster
+	if o.registryHost == "" && buildClusters != nil {
+		o.registryHost = buildClusters.RegistryHosts[cluster]
+	}
+	return o
+}
+
 func buildClustersFile(o options) string {
 	return filepath.Join(o.releaseRepo, "clusters", "build-clusters", "_cluster-init.yaml")
 }
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestRegistryHostIsKeptOnUpdate(t *testing.T) {
+	releaseRepo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(releaseRepo, "clusters", "build-clusters"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	o := options{releaseRepo: releaseRepo}
+	if err := os.WriteFile(buildClustersFile(o), []byte("managed:\n- build01\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the cluster is created from a descriptor setting a custom registry host
+	created := o
+	created.clusterName = "build10"
+	created.registryHost = "registry.build10.example.com"
+	if err := updateBuildClusters(created); err != nil {
+		t.Fatalf("failed to update the build clusters: %v", err)
+	}
+	buildClusters, err := loadBuildClusters(o)
+	if err != nil {
+		t.Fatalf("failed to load the build clusters: %v", err)
+	}
+	expected := &BuildClusters{
+		Managed:       []string{"build01", "build10"},
+		RegistryHosts: map[string]string{"build10": "registry.build10.example.com"},
+	}
+	if diff := cmp.Diff(expected, buildClusters); diff != "" {
+		t.Errorf("build clusters differ from expected: %s", diff)
+	}
+	written, err := os.ReadFile(buildClustersFile(o))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a later update of all clusters runs without the descriptor
+	updated := o
+	updated.update = true
+	for cluster, expectedHost := range map[string]string{
+		"build01": registryUrlFor("build01"),
+		"build10": "registry.build10.example.com",
+	} {
+		clusterOptions := optionsForCluster(updated, buildClusters, cluster)
+		for _, secret := range []string{"push", "pull"} {
+			generate := generateRegistryPushCredentialsSecret
+			if secret == "pull" {
+				generate = generateRegistryPullCredentialsSecret
+			}
+			var hosts []string
+			for _, data := range generate(clusterOptions).From[dotDockerConfigJson].DockerConfigJSONData {
+				hosts = append(hosts, data.RegistryURL)
+			}
+			if !sets.New[string](hosts...).Has(expectedHost) {
+				t.Errorf("%s: the %s secret does not use the registry host %s: %v", cluster, secret, expectedHost, hosts)
+			}
+		}
+		if err := updateBuildClusters(clusterOptions); err != nil {
+			t.Fatalf("failed to update the build clusters: %v", err)
+		}
+	}
+	rewritten, err := os.ReadFile(buildClustersFile(o))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(written), string(rewritten)); diff != "" {
+		t.Errorf("the update changed the build clusters config: %s", diff)
+	}
+}
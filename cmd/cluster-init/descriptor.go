@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/ci-tools/pkg/api"
+)
+
+// clusterDescriptor describes a build cluster, so that all the configuration for it
+// can be generated from a single file instead of a set of flags
+type clusterDescriptor struct {
+	// Name is the name of the cluster, e.g., build10
+	Name string `json:"name"`
+	// Hosted is true if the cluster is a HyperShift hosted cluster
+	Hosted bool `json:"hosted,omitempty"`
+	// Unmanaged is true if the cluster is not managed by DPTP
+	Unmanaged bool `json:"unmanaged,omitempty"`
+	// Cloud is the cloud provider of the cluster. If set, the cluster is added to
+	// the build farm of the cloud provider in the prow-job-dispatcher configuration
+	Cloud api.Cloud `json:"cloud,omitempty"`
+	// RegistryHost is the host of the public route of the image registry of the cluster.
+	// Defaults to registry.<name>.ci.openshift.org
+	RegistryHost string `json:"registryHost,omitempty"`
+}
+
+func loadClusterDescriptor(path string) (*clusterDescriptor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the cluster descriptor: %w", err)
+	}
+	var d clusterDescriptor
+	if err := yaml.UnmarshalStrict(data, &d); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the cluster descriptor %s: %w", path, err)
+	}
+	if d.Name == "" {
+		return nil, fmt.Errorf("the cluster descriptor %s does not set the name of the cluster", path)
+	}
+	return &d, nil
+}
+
+// applyClusterDescriptor sets the options describing the cluster from the descriptor
+func applyClusterDescriptor(o *options, d *clusterDescriptor) error {
+	if o.clusterName != "" && o.clusterName != d.Name {
+		return errors.New("--cluster-name must not be set to a different name than the one of the cluster descriptor")
+	}
+	o.clusterName = d.Name
+	o.hosted = o.hosted || d.Hosted
+	o.unmanaged = o.unmanaged || d.Unmanaged
+	o.cloud = d.Cloud
+	o.registryHost = d.RegistryHost
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/testhelper"
+)
+
+func TestLoadClusterDescriptor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cluster.yaml")
+	testCases := []struct {
+		name          string
+		data          string
+		expected      *clusterDescriptor
+		expectedError error
+	}{
+		{
+			name: "valid",
+			data: `name: build10
+hosted: true
+cloud: aws
+registryHost: registry.example.com
+`,
+			expected: &clusterDescriptor{Name: "build10", Hosted: true, Cloud: api.CloudAWS, RegistryHost: "registry.example.com"},
+		},
+		{
+			name:          "missing name",
+			data:          "cloud: aws\n",
+			expectedError: fmt.Errorf("the cluster descriptor %s does not set the name of the cluster", path),
+		},
+		{
+			name:          "unknown field",
+			data:          "name: build10\nregion: us-east-1\n",
+			expectedError: fmt.Errorf(`failed to unmarshal the cluster descriptor %s: error unmarshaling JSON: while decoding JSON: json: unknown field "region"`, path),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(tc.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			d, err := loadClusterDescriptor(path)
+			if diff := cmp.Diff(tc.expectedError, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expected, d); diff != "" {
+				t.Errorf("descriptor differs from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyClusterDescriptor(t *testing.T) {
+	d := &clusterDescriptor{Name: "build10", Unmanaged: true, Cloud: api.CloudGCP, RegistryHost: "registry.example.com"}
+
+	o := options{releaseRepo: "/release"}
+	if err := applyClusterDescriptor(&o, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o.clusterName != "build10" || o.hosted || !o.unmanaged || o.cloud != api.CloudGCP || o.registryHost != "registry.example.com" || o.releaseRepo != "/release" {
+		t.Errorf("options were not set from the descriptor: %s", o)
+	}
+
+	o = options{clusterName: "build11"}
+	expectedError := errors.New("--cluster-name must not be set to a different name than the one of the cluster descriptor")
+	if diff := cmp.Diff(expectedError, applyClusterDescriptor(&o, d), testhelper.EquateErrorMessage); diff != "" {
+		t.Errorf("error differs from expected: %s", diff)
+	}
+}
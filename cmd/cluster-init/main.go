@@ -41,6 +41,13 @@ type options struct {
 
 	hosted    bool
 	unmanaged bool
+
+	clusterDescriptor string
+	cloud             api.Cloud
+	registryHost      string
+
+	verify     bool
+	kubeconfig string
 }
 
 func (o options) String() string {
@@ -59,13 +66,31 @@ func parseOptions() (options, error) {
 	fs.BoolVar(&o.useTokenFileInKubeconfig, "use-token-file-in-kubeconfig", true, "Set true if the token files are used in kubeconfigs. Set to true by default")
 	fs.BoolVar(&o.hosted, "hosted", false, "Set true if the cluster is hosted (i.e., HyperShift hosted cluster). Set to false by default")
 	fs.BoolVar(&o.unmanaged, "unmanaged", false, "Set true if the cluster is unmanaged (i.e., not managed by DPTP). Set to false by default")
+	fs.StringVar(&o.clusterDescriptor, "cluster-descriptor", "", "Path to a file describing the cluster. Replaces --cluster-name, --hosted and --unmanaged")
+	fs.BoolVar(&o.verify, "verify", false, "Verify the cluster is set up as expected by the generated configuration instead of generating it. Requires --kubeconfig")
+	fs.StringVar(&o.kubeconfig, "kubeconfig", "", "Path to the kubeconfig of the cluster to verify")
 
 	o.GitAuthorOptions.AddFlags(fs)
 	o.PRCreationOptions.AddFlags(fs)
-	return o, fs.Parse(os.Args[1:])
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		return o, err
+	}
+	if o.clusterDescriptor != "" {
+		d, err := loadClusterDescriptor(o.clusterDescriptor)
+		if err != nil {
+			return o, err
+		}
+		if err := applyClusterDescriptor(&o, d); err != nil {
+			return o, err
+		}
+	}
+	return o, nil
 }
 
 func validateOptions(o options) []error {
+	if o.verify {
+		return validateVerifyOptions(o)
+	}
 	var errs []error
 	if !o.update && o.clusterName == "" {
 		errs = append(errs, errors.New("--cluster-name must be provided"))
@@ -162,6 +187,14 @@ func main() {
 		logrus.Fatalf("validation errors: %v", errorMessage)
 	}
 
+	if o.verify {
+		if err := verify(o); err != nil {
+			logrus.WithError(err).Fatal("the cluster is not set up as expected")
+		}
+		logrus.Info("the cluster is set up as expected")
+		return
+	}
+
 	// Each step in the process is allowed to fail independently so that the diffs for the others can still be generated
 	errorCount := 0
 	var clusters []string
@@ -184,6 +217,7 @@ func main() {
 	}
 	for _, cluster := range clusters {
 		o.clusterName = cluster
+		clusterOptions := optionsForCluster(o, buildClusters, cluster)
 		steps := []func(options) error{
 			updateJobs,
 			func(o options) error { return updateClusterBuildFarmDir(o, hostedClusters) },
@@ -192,12 +226,10 @@ func main() {
 			updateSanitizeProwJobs,
 			updateSyncRoverGroups,
 			updateProwPluginConfig,
-		}
-		if !o.update {
-			steps = append(steps, updateBuildClusters)
+			updateBuildClusters,
 		}
 		for _, step := range steps {
-			if err := step(o); err != nil {
+			if err := step(clusterOptions); err != nil {
 				logrus.WithError(err).Error("failed to execute step")
 				errorCount++
 			}
@@ -123,7 +123,7 @@ func generateCiOperatorSecret(o options) secretbootstrap.SecretConfig {
 func generateRegistryPushCredentialsSecret(o options) secretbootstrap.SecretConfig {
 	return secretbootstrap.SecretConfig{
 		From: map[string]secretbootstrap.ItemContext{
-			dotDockerConfigJson: generatePushPullSecretFrom(o.clusterName, []secretbootstrap.DockerConfigJSONData{
+			dotDockerConfigJson: generatePushPullSecretFrom(o.clusterName, registryURL(o), []secretbootstrap.DockerConfigJSONData{
 				{
 					AuthField:   registryCommandTokenField(string(api.ClusterAPPCI), push),
 					Item:        buildUFarm,
@@ -146,7 +146,7 @@ func generateRegistryPushCredentialsSecret(o options) secretbootstrap.SecretConf
 func generateRegistryPullCredentialsSecret(o options) secretbootstrap.SecretConfig {
 	return secretbootstrap.SecretConfig{
 		From: map[string]secretbootstrap.ItemContext{
-			dotDockerConfigJson: generatePushPullSecretFrom(o.clusterName, []secretbootstrap.DockerConfigJSONData{
+			dotDockerConfigJson: generatePushPullSecretFrom(o.clusterName, registryURL(o), []secretbootstrap.DockerConfigJSONData{
 				{
 					AuthField:   registryCommandTokenField(string(api.ClusterAPPCI), pull),
 					Item:        buildUFarm,
@@ -167,7 +167,7 @@ func generateRegistryPullCredentialsSecret(o options) secretbootstrap.SecretConf
 	}
 }
 
-func generatePushPullSecretFrom(clusterName string, items []secretbootstrap.DockerConfigJSONData) secretbootstrap.ItemContext {
+func generatePushPullSecretFrom(clusterName, registryURL string, items []secretbootstrap.DockerConfigJSONData) secretbootstrap.ItemContext {
 	itemContext := secretbootstrap.ItemContext{
 		DockerConfigJSONData: []secretbootstrap.DockerConfigJSONData{
 			{
@@ -183,7 +183,7 @@ func generatePushPullSecretFrom(clusterName string, items []secretbootstrap.Dock
 			{
 				AuthField:   registryCommandTokenField(clusterName, pull),
 				Item:        buildUFarm,
-				RegistryURL: registryUrlFor(clusterName),
+				RegistryURL: registryURL,
 			},
 		},
 	}
@@ -341,6 +341,15 @@ func updateSecretItemContext(c *secretbootstrap.Config, name, cluster, key strin
 	return nil
 }
 
+// registryURL returns the host of the public route of the image registry of the cluster,
+// preferring the one set in the cluster descriptor
+func registryURL(o options) string {
+	if o.registryHost != "" {
+		return o.registryHost
+	}
+	return registryUrlFor(o.clusterName)
+}
+
 func registryUrlFor(cluster string) string {
 	switch cluster {
 	case string(api.ClusterVSphere02):
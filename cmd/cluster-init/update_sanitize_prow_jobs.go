@@ -26,6 +26,9 @@ func updateSanitizeProwJobs(o options) error {
 		return err
 	}
 	updateSanitizeProwJobsConfig(&c, o.clusterName)
+	if o.cloud != "" {
+		updateSanitizeProwJobsBuildFarm(&c, o.cloud, o.clusterName)
+	}
 	rawYaml, err := yaml.Marshal(c)
 	if err != nil {
 		return err
@@ -42,3 +45,17 @@ func updateSanitizeProwJobsConfig(c *dispatcher.Config, clusterName string) {
 		Insert(metadata.SimpleJobName(jobconfig.PeriodicPrefix, clusterName+"-apply")))
 	c.Groups[api.ClusterAPPCI] = appGroup
 }
+
+// updateSanitizeProwJobsBuildFarm adds the cluster to the build farm of its cloud provider,
+// so that the dispatcher starts scheduling jobs to it
+func updateSanitizeProwJobsBuildFarm(c *dispatcher.Config, cloud api.Cloud, clusterName string) {
+	if c.BuildFarm == nil {
+		c.BuildFarm = map[api.Cloud]map[api.Cluster]*dispatcher.BuildFarmConfig{}
+	}
+	if c.BuildFarm[cloud] == nil {
+		c.BuildFarm[cloud] = map[api.Cluster]*dispatcher.BuildFarmConfig{}
+	}
+	if _, ok := c.BuildFarm[cloud][api.Cluster(clusterName)]; !ok {
+		c.BuildFarm[cloud][api.Cluster(clusterName)] = &dispatcher.BuildFarmConfig{}
+	}
+}
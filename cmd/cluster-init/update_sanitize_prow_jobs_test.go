@@ -49,3 +49,57 @@ func TestUpdateConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateSanitizeProwJobsBuildFarm(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    dispatcher.Config
+		expected dispatcher.Config
+	}{
+		{
+			name:  "no build farm yet",
+			input: dispatcher.Config{},
+			expected: dispatcher.Config{
+				BuildFarm: map[api.Cloud]map[api.Cluster]*dispatcher.BuildFarmConfig{
+					api.CloudAWS: {"newcluster": {}},
+				},
+			},
+		},
+		{
+			name: "cloud has other clusters",
+			input: dispatcher.Config{
+				BuildFarm: map[api.Cloud]map[api.Cluster]*dispatcher.BuildFarmConfig{
+					api.CloudAWS: {api.ClusterBuild01: {FilenamesRaw: []string{"a.yaml"}}},
+					api.CloudGCP: {api.ClusterBuild02: {}},
+				},
+			},
+			expected: dispatcher.Config{
+				BuildFarm: map[api.Cloud]map[api.Cluster]*dispatcher.BuildFarmConfig{
+					api.CloudAWS: {api.ClusterBuild01: {FilenamesRaw: []string{"a.yaml"}}, "newcluster": {}},
+					api.CloudGCP: {api.ClusterBuild02: {}},
+				},
+			},
+		},
+		{
+			name: "cluster is already in the build farm",
+			input: dispatcher.Config{
+				BuildFarm: map[api.Cloud]map[api.Cluster]*dispatcher.BuildFarmConfig{
+					api.CloudAWS: {"newcluster": {FilenamesRaw: []string{"a.yaml"}}},
+				},
+			},
+			expected: dispatcher.Config{
+				BuildFarm: map[api.Cloud]map[api.Cluster]*dispatcher.BuildFarmConfig{
+					api.CloudAWS: {"newcluster": {FilenamesRaw: []string{"a.yaml"}}},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			updateSanitizeProwJobsBuildFarm(&tc.input, api.CloudAWS, "newcluster")
+			if diff := cmp.Diff(tc.expected, tc.input); diff != "" {
+				t.Fatalf("expected build farm was different than results: %s", diff)
+			}
+		})
+	}
+}
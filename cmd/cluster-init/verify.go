@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/ci-tools/pkg/api"
+)
+
+const imageRegistryNamespace = "openshift-image-registry"
+
+func validateVerifyOptions(o options) []error {
+	var errs []error
+	if o.clusterName == "" {
+		errs = append(errs, errors.New("--cluster-name or --cluster-descriptor must be provided to verify a cluster"))
+	}
+	if o.kubeconfig == "" {
+		errs = append(errs, errors.New("--kubeconfig must be provided to verify a cluster"))
+	}
+	if o.update {
+		errs = append(errs, errors.New("--verify and --update are mutually exclusive"))
+	}
+	return errs
+}
+
+func verify(o options) error {
+	restConfig, err := clientcmd.BuildConfigFromFlags("", o.kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to load the kubeconfig: %w", err)
+	}
+	if err := routev1.Install(scheme.Scheme); err != nil {
+		return fmt.Errorf("failed to add routev1 to scheme: %w", err)
+	}
+	client, err := ctrlruntimeclient.New(restConfig, ctrlruntimeclient.Options{})
+	if err != nil {
+		return fmt.Errorf("failed to create the client: %w", err)
+	}
+	return verifyCluster(context.Background(), client, o)
+}
+
+// verifyCluster checks that the cluster is set up as expected by the configuration
+// generated for it: the namespaces the secrets are bootstrapped to exist, the
+// registry credentials were propagated and the image registry is exposed on the
+// host used in the registry credentials
+func verifyCluster(ctx context.Context, client ctrlruntimeclient.Client, o options) error {
+	var errs []error
+	for _, namespace := range []string{ci, testCredentials} {
+		if err := client.Get(ctx, ctrlruntimeclient.ObjectKey{Name: namespace}, &corev1.Namespace{}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to get namespace %s: %w", namespace, err))
+			continue
+		}
+		for _, name := range []string{api.RegistryPullCredentialsSecret, api.RegistryPushCredentialsCICentralSecret} {
+			if err := client.Get(ctx, ctrlruntimeclient.ObjectKey{Namespace: namespace, Name: name}, &corev1.Secret{}); err != nil {
+				if kerrors.IsNotFound(err) {
+					err = errors.New("the secret was not bootstrapped")
+				}
+				errs = append(errs, fmt.Errorf("failed to get secret %s/%s: %w", namespace, name, err))
+			}
+		}
+	}
+
+	host := registryURL(o)
+	routes := &routev1.RouteList{}
+	if err := client.List(ctx, routes, ctrlruntimeclient.InNamespace(imageRegistryNamespace)); err != nil {
+		errs = append(errs, fmt.Errorf("failed to list routes in namespace %s: %w", imageRegistryNamespace, err))
+	} else {
+		var found bool
+		for _, route := range routes.Items {
+			if route.Spec.Host == host {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("no route in namespace %s exposes the image registry on %s", imageRegistryNamespace, host))
+		}
+	}
+
+	if len(errs) == 0 {
+		logrus.WithField("cluster", o.clusterName).Info("Verified the cluster")
+	}
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/testhelper"
+)
+
+func TestVerifyCluster(t *testing.T) {
+	namespace := func(name string) ctrlruntimeclient.Object {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+	secret := func(namespace, name string) ctrlruntimeclient.Object {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+	route := func(host string) ctrlruntimeclient.Object {
+		return &routev1.Route{ObjectMeta: metav1.ObjectMeta{Namespace: imageRegistryNamespace, Name: "default-route"}, Spec: routev1.RouteSpec{Host: host}}
+	}
+	complete := []ctrlruntimeclient.Object{
+		namespace(ci),
+		namespace(testCredentials),
+		secret(ci, api.RegistryPullCredentialsSecret),
+		secret(ci, api.RegistryPushCredentialsCICentralSecret),
+		secret(testCredentials, api.RegistryPullCredentialsSecret),
+		secret(testCredentials, api.RegistryPushCredentialsCICentralSecret),
+	}
+
+	testCases := []struct {
+		name          string
+		options       options
+		objects       []ctrlruntimeclient.Object
+		expectedError error
+	}{
+		{
+			name:    "cluster is set up",
+			options: options{clusterName: "newcluster"},
+			objects: append([]ctrlruntimeclient.Object{route("registry.newcluster.ci.openshift.org")}, complete...),
+		},
+		{
+			name:    "cluster with a registry host from the descriptor is set up",
+			options: options{clusterName: "newcluster", registryHost: "registry.example.com"},
+			objects: append([]ctrlruntimeclient.Object{route("registry.example.com")}, complete...),
+		},
+		{
+			name:    "secrets were not bootstrapped and the registry is not exposed",
+			options: options{clusterName: "newcluster"},
+			objects: []ctrlruntimeclient.Object{
+				namespace(ci),
+				secret(ci, api.RegistryPullCredentialsSecret),
+				route("registry.other.ci.openshift.org"),
+			},
+			expectedError: errors.New(`[failed to get secret ci/registry-push-credentials-ci-central: the secret was not bootstrapped, failed to get namespace test-credentials: namespaces "test-credentials" not found, no route in namespace openshift-image-registry exposes the image registry on registry.newcluster.ci.openshift.org]`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := corev1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			if err := routev1.Install(scheme); err != nil {
+				t.Fatal(err)
+			}
+			client := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme).WithObjects(tc.objects...).Build()
+			err := verifyCluster(context.Background(), client, tc.options)
+			if diff := cmp.Diff(tc.expectedError, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateVerifyOptions(t *testing.T) {
+	testCases := []struct {
+		name           string
+		options        options
+		expectedErrors []error
+	}{
+		{
+			name:    "valid",
+			options: options{verify: true, clusterName: "newcluster", kubeconfig: "/kubeconfig"},
+		},
+		{
+			name:    "missing cluster and kubeconfig",
+			options: options{verify: true, update: true},
+			expectedErrors: []error{
+				errors.New("--cluster-name or --cluster-descriptor must be provided to verify a cluster"),
+				errors.New("--kubeconfig must be provided to verify a cluster"),
+				errors.New("--verify and --update are mutually exclusive"),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expectedErrors, validateOptions(tc.options), testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("errors differ from expected: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+// config declares the jobs that are triggered when pull requests merge in source repositories
+type config struct {
+	Triggers []trigger `json:"triggers"`
+}
+
+// trigger declares the jobs that run after a pull request merged in a source repository
+type trigger struct {
+	// Source is the org/repo in which merged pull requests trigger the jobs
+	Source string `json:"source"`
+	// Branches restricts the trigger to pull requests merged into these branches, all
+	// branches trigger the jobs when empty
+	Branches []string `json:"branches,omitempty"`
+	// RunIfChanged restricts the trigger to pull requests changing files that match
+	// this regular expression, for example the files that affect the payload
+	RunIfChanged string `json:"run_if_changed,omitempty"`
+	// Jobs are the names of the periodics or postsubmits of dependent repositories to
+	// trigger, postsubmits run on the current head of their branch
+	Jobs []string `json:"jobs"`
+
+	runIfChanged *regexp.Regexp
+}
+
+func (t *trigger) matches(org, repo, branch string) bool {
+	return t.Source == org+"/"+repo && (len(t.Branches) == 0 || sets.New[string](t.Branches...).Has(branch))
+}
+
+// triggersFor returns the triggers for pull requests merged into the branch of the repository
+func (c *config) triggersFor(org, repo, branch string) []trigger {
+	var triggers []trigger
+	for _, t := range c.Triggers {
+		if t.matches(org, repo, branch) {
+			triggers = append(triggers, t)
+		}
+	}
+	return triggers
+}
+
+func (c *config) validate() error {
+	var errs []error
+	for i := range c.Triggers {
+		t := &c.Triggers[i]
+		if parts := strings.Split(t.Source, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			errs = append(errs, fmt.Errorf("triggers[%d]: source %q must be of the form org/repo", i, t.Source))
+		}
+		if len(t.Jobs) == 0 {
+			errs = append(errs, fmt.Errorf("triggers[%d]: jobs must be set", i))
+		}
+		if t.RunIfChanged != "" {
+			var err error
+			if t.runIfChanged, err = regexp.Compile(t.RunIfChanged); err != nil {
+				errs = append(errs, fmt.Errorf("triggers[%d]: invalid run_if_changed: %w", i, err))
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func loadConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var c config
+	if err := yaml.UnmarshalStrict(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", path, err)
+	}
+	if len(c.Triggers) == 0 {
+		return nil, errors.New("no triggers are configured")
+	}
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration in %s: %w", path, err)
+	}
+	return &c, nil
+}
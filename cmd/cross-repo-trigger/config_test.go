@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/openshift/ci-tools/pkg/testhelper"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	testCases := []struct {
+		name          string
+		data          string
+		expectedError error
+	}{
+		{
+			name: "valid",
+			data: `triggers:
+- source: openshift/api
+  branches: [master]
+  run_if_changed: ^config/
+  jobs: [periodic-ci-openshift-kube-apiserver-operator-master-e2e]
+`,
+		},
+		{
+			name:          "no triggers",
+			data:          "triggers: []\n",
+			expectedError: errors.New("no triggers are configured"),
+		},
+		{
+			name: "invalid triggers",
+			data: `triggers:
+- source: openshift
+  run_if_changed: "("
+`,
+			expectedError: fmt.Errorf("invalid configuration in %s: [triggers[0]: source \"openshift\" must be of the form org/repo, triggers[0]: jobs must be set, triggers[0]: invalid run_if_changed: error parsing regexp: missing closing ): `(`]", path),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(tc.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := loadConfig(path)
+			if diff := cmp.Diff(tc.expectedError, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestTriggersFor(t *testing.T) {
+	c := &config{Triggers: []trigger{
+		{Source: "openshift/api", Jobs: []string{"all-branches"}},
+		{Source: "openshift/api", Branches: []string{"release-4.14"}, Jobs: []string{"release-branch"}},
+		{Source: "openshift/other", Jobs: []string{"other-repo"}},
+	}}
+	var jobs []string
+	for _, t := range c.triggersFor("openshift", "api", "master") {
+		jobs = append(jobs, t.Jobs...)
+	}
+	if diff := cmp.Diff([]string{"all-branches"}, jobs); diff != "" {
+		t.Errorf("jobs differ from expected: %s", diff)
+	}
+	jobs = nil
+	for _, t := range c.triggersFor("openshift", "api", "release-4.14") {
+		jobs = append(jobs, t.Jobs...)
+	}
+	if diff := cmp.Diff([]string{"all-branches", "release-branch"}, jobs); diff != "" {
+		t.Errorf("jobs differ from expected: %s", diff)
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bombsimon/logrusr/v3"
+	"github.com/sirupsen/logrus"
+
+	prowv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+	prowconfig "k8s.io/test-infra/prow/config"
+	"k8s.io/test-infra/prow/config/secret"
+	prowflagutil "k8s.io/test-infra/prow/flagutil"
+	configflagutil "k8s.io/test-infra/prow/flagutil/config"
+	"k8s.io/test-infra/prow/githubeventserver"
+	"k8s.io/test-infra/prow/interrupts"
+	"k8s.io/test-infra/prow/logrusutil"
+	"k8s.io/test-infra/prow/pjutil"
+	ctrlruntimelog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+type options struct {
+	githubEventServerOptions githubeventserver.Options
+	github                   prowflagutil.GitHubOptions
+	kubernetesOptions        prowflagutil.KubernetesOptions
+	config                   configflagutil.ConfigOptions
+	webhookSecretFile        string
+	triggerConfigPath        string
+	statusContext            string
+	dryRun                   bool
+}
+
+func gatherOptions() (*options, error) {
+	o := &options{}
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	fs.StringVar(&o.webhookSecretFile, "hmac-secret-file", "/etc/webhook/hmac", "Path to the file containing the GitHub HMAC secret.")
+	fs.StringVar(&o.triggerConfigPath, "trigger-config", "", "Path to the file declaring the jobs triggered by merged pull requests.")
+	fs.StringVar(&o.statusContext, "status-context", "ci/cross-repo-trigger", "The context of the status reported on merged commits.")
+	fs.BoolVar(&o.dryRun, "dry-run", false, "Whether to only log the jobs that would be triggered.")
+	o.githubEventServerOptions.Bind(fs)
+	o.github.AddFlags(fs)
+	o.kubernetesOptions.AddFlags(fs)
+	o.config.AddFlags(fs)
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		return nil, fmt.Errorf("failed to parse flags: %w", err)
+	}
+	return o, nil
+}
+
+func (o *options) validate() error {
+	if o.triggerConfigPath == "" {
+		return errors.New("--trigger-config is required")
+	}
+	if o.statusContext == "" {
+		return errors.New("--status-context must not be empty")
+	}
+	for _, opt := range []interface{ Validate(bool) error }{&o.github, &o.kubernetesOptions, &o.config} {
+		if err := opt.Validate(o.dryRun); err != nil {
+			return err
+		}
+	}
+	return o.githubEventServerOptions.DefaultAndValidate()
+}
+
+func main() {
+	logrusutil.ComponentInit()
+	logger := logrus.WithField("plugin", pluginName)
+	ctrlruntimelog.SetLogger(logrusr.New(logger))
+
+	o, err := gatherOptions()
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to get options")
+	}
+	if err := o.validate(); err != nil {
+		logger.WithError(err).Fatal("Invalid options")
+	}
+
+	triggerConfig, err := loadConfig(o.triggerConfigPath)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load the trigger configuration")
+	}
+
+	tokens := []string{o.webhookSecretFile}
+	if o.github.TokenPath != "" {
+		tokens = append(tokens, o.github.TokenPath)
+	}
+	if o.github.AppPrivateKeyPath != "" {
+		tokens = append(tokens, o.github.AppPrivateKeyPath)
+	}
+	if err := secret.Add(tokens...); err != nil {
+		logger.WithError(err).Fatal("Error starting secrets agent")
+	}
+	githubClient, err := o.github.GitHubClient(o.dryRun)
+	if err != nil {
+		logger.WithError(err).Fatal("Error getting GitHub client")
+	}
+
+	configAgent, err := o.config.ConfigAgent()
+	if err != nil {
+		logger.WithError(err).Fatal("Error starting config agent")
+	}
+	prowConfig := configAgent.Config
+
+	restConfig, err := o.kubernetesOptions.InfrastructureClusterConfig(o.dryRun)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to get kubeconfig")
+	}
+	mgr, err := manager.New(restConfig, manager.Options{
+		Namespace:          prowConfig().ProwJobNamespace,
+		MetricsBindAddress: "0",
+		DryRunClient:       o.dryRun,
+	})
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to create manager")
+	}
+	if err := prowv1.AddToScheme(mgr.GetScheme()); err != nil {
+		logger.WithError(err).Fatal("Failed to add prowv1 to scheme")
+	}
+	if err := addReconciler(mgr, githubClient, o.statusContext, logger); err != nil {
+		logger.WithError(err).Fatal("Failed to add the reconciler")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupts.OnInterrupt(cancel)
+	serv := &server{
+		ghc:           githubClient,
+		kubeClient:    mgr.GetClient(),
+		ctx:           ctx,
+		namespace:     prowConfig().ProwJobNamespace,
+		jobConfig:     func() *prowconfig.JobConfig { return &prowConfig().JobConfig },
+		config:        triggerConfig,
+		statusContext: o.statusContext,
+	}
+
+	eventServer := githubeventserver.New(o.githubEventServerOptions, secret.GetTokenGenerator(o.webhookSecretFile), logger)
+	eventServer.RegisterHandlePullRequestEvent(serv.handlePullRequest)
+	eventServer.RegisterHelpProvider(helpProvider, logger)
+	interrupts.OnInterrupt(func() {
+		eventServer.GracefulShutdown()
+	})
+
+	health := pjutil.NewHealth()
+	health.ServeReady()
+
+	interrupts.Run(func(ctx context.Context) {
+		if err := mgr.Start(ctx); err != nil {
+			logger.WithError(err).Fatal("Controller manager exited with error")
+		}
+	})
+	interrupts.ListenAndServe(eventServer, 30*time.Second)
+	interrupts.WaitForGracefulShutdown()
+}
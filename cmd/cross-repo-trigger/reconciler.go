@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/cache"
+	prowv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+	"k8s.io/test-infra/prow/github"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// reportedCacheSize bounds the number of commits whose last status is remembered
+	reportedCacheSize = 10000
+	// reportedCacheTTL is how long the last status of a commit is remembered. Once it is
+	// forgotten, an unchanged status may be reported once more.
+	reportedCacheTTL = 24 * time.Hour
+)
+
+type statusCreator interface {
+	CreateStatus(org, repo, SHA string, s github.Status) error
+}
+
+// reconciler aggregates the results of the jobs triggered for a merged commit into
+// a status on the commit
+type reconciler struct {
+	client        ctrlruntimeclient.Client
+	ghc           statusCreator
+	statusContext string
+	logger        *logrus.Entry
+
+	// reported holds the last status reported for recent commits, to avoid reporting it repeatedly
+	reported *cache.LRUExpireCache
+}
+
+func addReconciler(mgr manager.Manager, ghc statusCreator, statusContext string, logger *logrus.Entry) error {
+	r := &reconciler{
+		client:        mgr.GetClient(),
+		ghc:           ghc,
+		statusContext: statusContext,
+		logger:        logger,
+		reported:      cache.NewLRUExpireCache(reportedCacheSize),
+	}
+	triggered := predicate.NewPredicateFuncs(func(o ctrlruntimeclient.Object) bool {
+		_, ok := o.GetLabels()[sourceSHALabel]
+		return ok
+	})
+	if err := builder.
+		ControllerManagedBy(mgr).
+		Named(pluginName).
+		For(&prowv1.ProwJob{}, builder.WithPredicates(triggered)).
+		Complete(r); err != nil {
+		return fmt.Errorf("failed to construct controller: %w", err)
+	}
+	return nil
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	logger := r.logger.WithField("prowjob", req.String())
+	err := r.reconcile(ctx, req, logger)
+	if err != nil {
+		logger.WithError(err).Error("Reconciliation failed")
+	}
+	return reconcile.Result{}, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, req reconcile.Request, logger *logrus.Entry) error {
+	pj := &prowv1.ProwJob{}
+	if err := r.client.Get(ctx, req.NamespacedName, pj); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get the ProwJob: %w", err)
+	}
+	org, repo, sha := pj.Labels[sourceOrgLabel], pj.Labels[sourceRepoLabel], pj.Labels[sourceSHALabel]
+	if org == "" || repo == "" || sha == "" {
+		return nil
+	}
+
+	var pjs prowv1.ProwJobList
+	selector := ctrlruntimeclient.MatchingLabels{sourceOrgLabel: org, sourceRepoLabel: repo, sourceSHALabel: sha}
+	if err := r.client.List(ctx, &pjs, selector, ctrlruntimeclient.InNamespace(req.Namespace)); err != nil {
+		return fmt.Errorf("failed to list the ProwJobs triggered for %s/%s@%s: %w", org, repo, sha, err)
+	}
+	status := aggregateStatus(pjs.Items)
+	status.Context = r.statusContext
+
+	key := fmt.Sprintf("%s/%s@%s", org, repo, sha)
+	if reported, ok := r.reported.Get(key); ok && reported.(github.Status) == status {
+		return nil
+	}
+	if err := r.ghc.CreateStatus(org, repo, sha, status); err != nil {
+		return fmt.Errorf("failed to report the status on %s: %w", key, err)
+	}
+	logger.WithFields(logrus.Fields{"commit": key, "state": status.State}).Info("Reported the status of the triggered jobs")
+	r.reported.Add(key, status, reportedCacheTTL)
+	return nil
+}
+
+// aggregateStatus determines the status of a commit from the jobs triggered for it: it is
+// pending until all jobs finished and successful only when all jobs succeeded
+func aggregateStatus(pjs []prowv1.ProwJob) github.Status {
+	var finished, failed int
+	var failedURL string
+	for _, pj := range pjs {
+		if !pj.Complete() {
+			continue
+		}
+		finished++
+		if pj.Status.State != prowv1.SuccessState {
+			failed++
+			failedURL = pj.Status.URL
+		}
+	}
+	switch {
+	case finished < len(pjs):
+		return github.Status{State: github.StatusPending, Description: fmt.Sprintf("%d/%d triggered jobs finished", finished, len(pjs))}
+	case failed > 0:
+		status := github.Status{State: github.StatusFailure, Description: fmt.Sprintf("%d/%d triggered jobs failed", failed, len(pjs))}
+		if failed == 1 {
+			status.TargetURL = failedURL
+		}
+		return status
+	default:
+		return github.Status{State: github.StatusSuccess, Description: fmt.Sprintf("All %d triggered jobs succeeded", len(pjs))}
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/cache"
+	prowv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+	"k8s.io/test-infra/prow/github"
+	"k8s.io/test-infra/prow/github/fakegithub"
+	fakeclock "k8s.io/utils/clock/testing"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func triggeredJob(name, sha string, state prowv1.ProwJobState) *prowv1.ProwJob {
+	pj := &prowv1.ProwJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ci",
+			Labels:    map[string]string{sourceOrgLabel: "openshift", sourceRepoLabel: "api", sourceSHALabel: sha},
+		},
+		Status: prowv1.ProwJobStatus{State: state, URL: "https://prow/" + name},
+	}
+	if state != prowv1.PendingState && state != prowv1.TriggeredState {
+		completion := metav1.Now()
+		pj.Status.CompletionTime = &completion
+	}
+	return pj
+}
+
+func TestAggregateStatus(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pjs      []*prowv1.ProwJob
+		expected github.Status
+	}{
+		{
+			name:     "running jobs",
+			pjs:      []*prowv1.ProwJob{triggeredJob("a", "sha", prowv1.SuccessState), triggeredJob("b", "sha", prowv1.PendingState)},
+			expected: github.Status{State: github.StatusPending, Description: "1/2 triggered jobs finished"},
+		},
+		{
+			name:     "all jobs succeeded",
+			pjs:      []*prowv1.ProwJob{triggeredJob("a", "sha", prowv1.SuccessState), triggeredJob("b", "sha", prowv1.SuccessState)},
+			expected: github.Status{State: github.StatusSuccess, Description: "All 2 triggered jobs succeeded"},
+		},
+		{
+			name:     "one job failed",
+			pjs:      []*prowv1.ProwJob{triggeredJob("a", "sha", prowv1.SuccessState), triggeredJob("b", "sha", prowv1.FailureState)},
+			expected: github.Status{State: github.StatusFailure, Description: "1/2 triggered jobs failed", TargetURL: "https://prow/b"},
+		},
+		{
+			name:     "jobs failed and were aborted",
+			pjs:      []*prowv1.ProwJob{triggeredJob("a", "sha", prowv1.AbortedState), triggeredJob("b", "sha", prowv1.ErrorState)},
+			expected: github.Status{State: github.StatusFailure, Description: "2/2 triggered jobs failed"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var pjs []prowv1.ProwJob
+			for _, pj := range tc.pjs {
+				pjs = append(pjs, *pj)
+			}
+			if diff := cmp.Diff(tc.expected, aggregateStatus(pjs)); diff != "" {
+				t.Errorf("status differs from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	objects := []ctrlruntimeclient.Object{
+		triggeredJob("a", "merged", prowv1.SuccessState),
+		triggeredJob("b", "merged", prowv1.PendingState),
+		triggeredJob("c", "other", prowv1.FailureState),
+	}
+	client := fakectrlruntimeclient.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(objects...).Build()
+	ghc := fakegithub.NewFakeClient()
+	r := &reconciler{
+		client:        client,
+		ghc:           ghc,
+		statusContext: "ci/cross-repo-trigger",
+		logger:        logrus.NewEntry(logrus.StandardLogger()),
+		reported:      cache.NewLRUExpireCache(reportedCacheSize),
+	}
+	reconcileJob := func(name string) {
+		t.Helper()
+		if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ci", Name: name}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	reconcileJob("a")
+	pending := github.Status{State: github.StatusPending, Description: "1/2 triggered jobs finished", Context: "ci/cross-repo-trigger"}
+	if diff := cmp.Diff([]github.Status{pending}, ghc.CreatedStatuses["merged"]); diff != "" {
+		t.Errorf("statuses differ from expected: %s", diff)
+	}
+
+	// an unchanged status is not reported again
+	ghc.CreatedStatuses = nil
+	reconcileJob("b")
+	if len(ghc.CreatedStatuses) != 0 {
+		t.Errorf("expected no status to be reported again, got %v", ghc.CreatedStatuses)
+	}
+
+	b := &prowv1.ProwJob{}
+	if err := client.Get(context.Background(), types.NamespacedName{Namespace: "ci", Name: "b"}, b); err != nil {
+		t.Fatal(err)
+	}
+	b.Status.State = prowv1.SuccessState
+	completion := metav1.Now()
+	b.Status.CompletionTime = &completion
+	if err := client.Update(context.Background(), b); err != nil {
+		t.Fatal(err)
+	}
+	reconcileJob("b")
+	success := github.Status{State: github.StatusSuccess, Description: "All 2 triggered jobs succeeded", Context: "ci/cross-repo-trigger"}
+	if diff := cmp.Diff([]github.Status{success}, ghc.CreatedStatuses["merged"]); diff != "" {
+		t.Errorf("statuses differ from expected: %s", diff)
+	}
+
+	reconcileJob("missing")
+}
+
+func TestReconcileForgetsReportedStatuses(t *testing.T) {
+	client := fakectrlruntimeclient.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(triggeredJob("a", "merged", prowv1.SuccessState)).Build()
+	ghc := fakegithub.NewFakeClient()
+	clock := fakeclock.NewFakeClock(time.Now())
+	r := &reconciler{
+		client:        client,
+		ghc:           ghc,
+		statusContext: "ci/cross-repo-trigger",
+		logger:        logrus.NewEntry(logrus.StandardLogger()),
+		reported:      cache.NewLRUExpireCacheWithClock(reportedCacheSize, clock),
+	}
+	reconcileJob := func() {
+		t.Helper()
+		if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ci", Name: "a"}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	reconcileJob()
+	ghc.CreatedStatuses = nil
+	reconcileJob()
+	if n := len(ghc.CreatedStatuses["merged"]); n != 0 {
+		t.Errorf("expected an unchanged status not to be reported again, got %d", n)
+	}
+	if n := len(r.reported.Keys()); n != 1 {
+		t.Errorf("expected one remembered commit, got %d", n)
+	}
+
+	clock.Step(reportedCacheTTL + time.Second)
+	if n := len(r.reported.Keys()); n != 0 {
+		t.Errorf("expected the commit to be forgotten after the TTL, got %d", n)
+	}
+	reconcileJob()
+	if n := len(ghc.CreatedStatuses["merged"]); n != 1 {
+		t.Errorf("expected the status to be reported again once forgotten, got %d", n)
+	}
+}
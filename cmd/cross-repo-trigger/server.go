@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	prowv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+	prowconfig "k8s.io/test-infra/prow/config"
+	"k8s.io/test-infra/prow/github"
+	"k8s.io/test-infra/prow/pjutil"
+	"k8s.io/test-infra/prow/pluginhelp"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	pluginName = "cross-repo-trigger"
+
+	// The labels identify the merged commit a job was triggered for
+	sourceOrgLabel  = "ci.openshift.io/cross-repo-trigger-org"
+	sourceRepoLabel = "ci.openshift.io/cross-repo-trigger-repo"
+	sourceSHALabel  = "ci.openshift.io/cross-repo-trigger-sha"
+	// sourcePullAnnotation holds the number of the merged pull request
+	sourcePullAnnotation = "ci.openshift.io/cross-repo-trigger-pull"
+)
+
+type githubClient interface {
+	GetPullRequestChanges(org, repo string, number int) ([]github.PullRequestChange, error)
+	GetRef(org, repo, ref string) (string, error)
+	CreateStatus(org, repo, SHA string, s github.Status) error
+}
+
+type server struct {
+	ghc        githubClient
+	kubeClient ctrlruntimeclient.Client
+	ctx        context.Context
+	namespace  string
+	jobConfig  func() *prowconfig.JobConfig
+	config     *config
+	// statusContext is the context of the status reported on the merged commits
+	statusContext string
+}
+
+func helpProvider(_ []prowconfig.OrgRepo) (*pluginhelp.PluginHelp, error) {
+	return &pluginhelp.PluginHelp{
+		Description: `The cross-repo-trigger plugin triggers jobs of dependent repositories when a pull request merges in a configured source repository and reports their results in a status on the merged commit.`,
+	}, nil
+}
+
+func (s *server) handlePullRequest(l *logrus.Entry, event github.PullRequestEvent) {
+	pr := event.PullRequest
+	if event.Action != github.PullRequestActionClosed || !pr.Merged || pr.MergeSHA == nil {
+		return
+	}
+	org, repo, branch := event.Repo.Owner.Login, event.Repo.Name, pr.Base.Ref
+	triggers := s.config.triggersFor(org, repo, branch)
+	if len(triggers) == 0 {
+		return
+	}
+	logger := l.WithFields(logrus.Fields{
+		github.OrgLogField:  org,
+		github.RepoLogField: repo,
+		github.PrLogField:   pr.Number,
+		"sha":               *pr.MergeSHA,
+	})
+
+	jobs, err := s.jobsFor(triggers, org, repo, pr.Number)
+	if err != nil {
+		logger.WithError(err).Error("Failed to determine the jobs to trigger")
+	}
+	if len(jobs) == 0 {
+		if err != nil {
+			s.reportStatus(logger, org, repo, *pr.MergeSHA, github.Status{State: github.StatusError, Description: "Failed to determine the jobs to trigger"})
+		}
+		return
+	}
+
+	s.reportStatus(logger, org, repo, *pr.MergeSHA, github.Status{State: github.StatusPending, Description: fmt.Sprintf("Triggering %d job(s) in dependent repositories", len(jobs))})
+	labels := map[string]string{sourceOrgLabel: org, sourceRepoLabel: repo, sourceSHALabel: *pr.MergeSHA}
+	annotations := map[string]string{sourcePullAnnotation: strconv.Itoa(pr.Number)}
+	var created int
+	for _, job := range jobs {
+		pj, err := s.prowJobFor(job, labels, annotations)
+		if err != nil {
+			logger.WithError(err).WithField("job", job.name).Error("Failed to construct the ProwJob")
+			continue
+		}
+		pj.Namespace = s.namespace
+		if err := s.kubeClient.Create(s.ctx, pj); err != nil {
+			logger.WithError(err).WithFields(pjutil.ProwJobFields(pj)).Error("Failed to create the ProwJob")
+			continue
+		}
+		logger.WithFields(pjutil.ProwJobFields(pj)).Info("Triggered ProwJob")
+		created++
+	}
+	if created == 0 {
+		s.reportStatus(logger, org, repo, *pr.MergeSHA, github.Status{State: github.StatusError, Description: "Failed to trigger the jobs in dependent repositories"})
+	}
+}
+
+func (s *server) reportStatus(logger *logrus.Entry, org, repo, sha string, status github.Status) {
+	status.Context = s.statusContext
+	if err := s.ghc.CreateStatus(org, repo, sha, status); err != nil {
+		logger.WithError(err).Error("Failed to report the status")
+	}
+}
+
+// job is a job to trigger, either a periodic or a postsubmit
+type job struct {
+	name       string
+	periodic   *prowconfig.Periodic
+	postsubmit *prowconfig.Postsubmit
+	// orgRepo is the repository of a postsubmit
+	orgRepo string
+}
+
+// jobsFor resolves the jobs of the triggers that apply to the changes of the pull request
+func (s *server) jobsFor(triggers []trigger, org, repo string, number int) ([]job, error) {
+	var changes []string
+	var changesLoaded bool
+	names := sets.New[string]()
+	for _, t := range triggers {
+		if t.runIfChanged != nil {
+			if !changesLoaded {
+				prChanges, err := s.ghc.GetPullRequestChanges(org, repo, number)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get the changes of the pull request: %w", err)
+				}
+				for _, change := range prChanges {
+					changes = append(changes, change.Filename)
+				}
+				changesLoaded = true
+			}
+			if !anyMatches(t.runIfChanged, changes) {
+				continue
+			}
+		}
+		names.Insert(t.Jobs...)
+	}
+	if names.Len() == 0 {
+		return nil, nil
+	}
+
+	jobConfig := s.jobConfig()
+	byName := map[string]job{}
+	for i := range jobConfig.Periodics {
+		periodic := &jobConfig.Periodics[i]
+		byName[periodic.Name] = job{name: periodic.Name, periodic: periodic}
+	}
+	for orgRepo, postsubmits := range jobConfig.PostsubmitsStatic {
+		for i := range postsubmits {
+			postsubmit := &postsubmits[i]
+			byName[postsubmit.Name] = job{name: postsubmit.Name, postsubmit: postsubmit, orgRepo: orgRepo}
+		}
+	}
+	var jobs []job
+	var errs []error
+	for _, name := range sets.List(names) {
+		j, ok := byName[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("job %s is neither a periodic nor a postsubmit", name))
+			continue
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, utilerrors.NewAggregate(errs)
+}
+
+func anyMatches(re *regexp.Regexp, files []string) bool {
+	for _, file := range files {
+		if re.MatchString(file) {
+			return true
+		}
+	}
+	return false
+}
+
+// exactBranch matches the branch configuration of postsubmits that run on a single branch
+var exactBranch = regexp.MustCompile(`^\^?([\w./-]+?)\$?$`)
+
+func (s *server) prowJobFor(j job, labels, annotations map[string]string) (*prowv1.ProwJob, error) {
+	if j.periodic != nil {
+		pj := pjutil.NewProwJob(pjutil.PeriodicSpec(*j.periodic), merge(j.periodic.Labels, labels), merge(j.periodic.Annotations, annotations))
+		return &pj, nil
+	}
+	if len(j.postsubmit.Branches) != 1 || !exactBranch.MatchString(j.postsubmit.Branches[0]) {
+		return nil, fmt.Errorf("postsubmit %s must run on exactly one branch", j.name)
+	}
+	branch := exactBranch.FindStringSubmatch(j.postsubmit.Branches[0])[1]
+	orgRepo := prowconfig.NewOrgRepo(j.orgRepo)
+	sha, err := s.ghc.GetRef(orgRepo.Org, orgRepo.Repo, "heads/"+branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the head of %s@%s: %w", j.orgRepo, branch, err)
+	}
+	refs := prowv1.Refs{Org: orgRepo.Org, Repo: orgRepo.Repo, BaseRef: branch, BaseSHA: sha}
+	pj := pjutil.NewProwJob(pjutil.PostsubmitSpec(*j.postsubmit, refs), merge(j.postsubmit.Labels, labels), merge(j.postsubmit.Annotations, annotations))
+	return &pj, nil
+}
+
+func merge(base, extra map[string]string) map[string]string {
+	merged := map[string]string{}
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return merged
+}
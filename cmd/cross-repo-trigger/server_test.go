@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	prowv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+	prowconfig "k8s.io/test-infra/prow/config"
+	"k8s.io/test-infra/prow/github"
+	"k8s.io/test-infra/prow/github/fakegithub"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := prowv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add prowv1 to scheme: %v", err)
+	}
+	return scheme
+}
+
+func mergedEvent(number int, sha string) github.PullRequestEvent {
+	return github.PullRequestEvent{
+		Action: github.PullRequestActionClosed,
+		Repo:   github.Repo{Owner: github.User{Login: "openshift"}, Name: "api"},
+		PullRequest: github.PullRequest{
+			Number:   number,
+			Merged:   true,
+			MergeSHA: &sha,
+			Base:     github.PullRequestBranch{Ref: "master"},
+		},
+	}
+}
+
+func TestHandlePullRequest(t *testing.T) {
+	jobConfig := &prowconfig.JobConfig{
+		Periodics: []prowconfig.Periodic{
+			{JobBase: prowconfig.JobBase{Name: "periodic-e2e", Labels: map[string]string{"existing": "label"}}},
+			{JobBase: prowconfig.JobBase{Name: "periodic-unrelated"}},
+		},
+		PostsubmitsStatic: map[string][]prowconfig.Postsubmit{
+			"openshift/operator": {
+				{JobBase: prowconfig.JobBase{Name: "branch-ci-openshift-operator-master-e2e"}, Brancher: prowconfig.Brancher{Branches: []string{"^master$"}}},
+				{JobBase: prowconfig.JobBase{Name: "branch-ci-openshift-operator-all-branches"}},
+			},
+		},
+	}
+	triggerConfig := &config{Triggers: []trigger{
+		{Source: "openshift/api", Jobs: []string{"periodic-e2e", "branch-ci-openshift-operator-master-e2e"}},
+		{Source: "openshift/api", RunIfChanged: "^payload/", runIfChanged: regexp.MustCompile("^payload/"), Jobs: []string{"periodic-unrelated"}},
+		{Source: "openshift/api", Branches: []string{"release-4.14"}, Jobs: []string{"branch-ci-openshift-operator-all-branches"}},
+	}}
+
+	testCases := []struct {
+		name             string
+		event            github.PullRequestEvent
+		changes          []github.PullRequestChange
+		expectedJobs     []string
+		expectedStatuses []github.Status
+	}{
+		{
+			name:         "merged pull request triggers the jobs",
+			event:        mergedEvent(1, "merged"),
+			changes:      []github.PullRequestChange{{Filename: "README.md"}},
+			expectedJobs: []string{"branch-ci-openshift-operator-master-e2e", "periodic-e2e"},
+			expectedStatuses: []github.Status{
+				{State: github.StatusPending, Description: "Triggering 2 job(s) in dependent repositories", Context: "ci/cross-repo-trigger"},
+			},
+		},
+		{
+			name:         "changed files trigger more jobs",
+			event:        mergedEvent(1, "merged"),
+			changes:      []github.PullRequestChange{{Filename: "payload/types.go"}},
+			expectedJobs: []string{"branch-ci-openshift-operator-master-e2e", "periodic-e2e", "periodic-unrelated"},
+			expectedStatuses: []github.Status{
+				{State: github.StatusPending, Description: "Triggering 3 job(s) in dependent repositories", Context: "ci/cross-repo-trigger"},
+			},
+		},
+		{
+			name: "closed pull request does not trigger jobs",
+			event: func() github.PullRequestEvent {
+				e := mergedEvent(1, "merged")
+				e.PullRequest.Merged = false
+				return e
+			}(),
+		},
+		{
+			name: "pull request in a repository without triggers does not trigger jobs",
+			event: func() github.PullRequestEvent {
+				e := mergedEvent(1, "merged")
+				e.Repo.Name = "other"
+				return e
+			}(),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ghc := fakegithub.NewFakeClient()
+			ghc.PullRequestChanges = map[int][]github.PullRequestChange{1: tc.changes}
+			client := fakectrlruntimeclient.NewClientBuilder().WithScheme(testScheme(t)).Build()
+			s := &server{
+				ghc:           ghc,
+				kubeClient:    client,
+				ctx:           context.Background(),
+				namespace:     "ci",
+				jobConfig:     func() *prowconfig.JobConfig { return jobConfig },
+				config:        triggerConfig,
+				statusContext: "ci/cross-repo-trigger",
+			}
+			s.handlePullRequest(logrus.NewEntry(logrus.StandardLogger()), tc.event)
+
+			var pjs prowv1.ProwJobList
+			if err := client.List(context.Background(), &pjs, ctrlruntimeclient.InNamespace("ci")); err != nil {
+				t.Fatal(err)
+			}
+			var jobs []string
+			for _, pj := range pjs.Items {
+				jobs = append(jobs, pj.Spec.Job)
+				if diff := cmp.Diff("merged", pj.Labels[sourceSHALabel]); diff != "" {
+					t.Errorf("%s: source label differs from expected: %s", pj.Spec.Job, diff)
+				}
+				if diff := cmp.Diff("1", pj.Annotations[sourcePullAnnotation]); diff != "" {
+					t.Errorf("%s: source annotation differs from expected: %s", pj.Spec.Job, diff)
+				}
+				if pj.Spec.Job == "periodic-e2e" && pj.Labels["existing"] != "label" {
+					t.Errorf("%s: labels of the job were not kept: %v", pj.Spec.Job, pj.Labels)
+				}
+				if pj.Spec.Type == prowv1.PostsubmitJob {
+					expectedRefs := &prowv1.Refs{Org: "openshift", Repo: "operator", BaseRef: "master", BaseSHA: fakegithub.TestRef}
+					if diff := cmp.Diff(expectedRefs, pj.Spec.Refs); diff != "" {
+						t.Errorf("%s: refs differ from expected: %s", pj.Spec.Job, diff)
+					}
+				}
+			}
+			sort.Strings(jobs)
+			if diff := cmp.Diff(tc.expectedJobs, jobs); diff != "" {
+				t.Errorf("triggered jobs differ from expected: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedStatuses, ghc.CreatedStatuses["merged"]); diff != "" {
+				t.Errorf("statuses differ from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestJobsForMissingJob(t *testing.T) {
+	s := &server{
+		ghc:       fakegithub.NewFakeClient(),
+		jobConfig: func() *prowconfig.JobConfig { return &prowconfig.JobConfig{} },
+	}
+	jobs, err := s.jobsFor([]trigger{{Source: "openshift/api", Jobs: []string{"missing"}}}, "openshift", "api", 1)
+	if err == nil {
+		t.Error("expected an error for a missing job, got none")
+	}
+	if len(jobs) != 0 {
+		t.Errorf("expected no jobs, got %v", jobs)
+	}
+}
+
+func TestProwJobForPostsubmitOnMultipleBranches(t *testing.T) {
+	s := &server{ghc: fakegithub.NewFakeClient()}
+	postsubmit := &prowconfig.Postsubmit{JobBase: prowconfig.JobBase{Name: "job"}, Brancher: prowconfig.Brancher{Branches: []string{"master", "main"}}}
+	if _, err := s.prowJobFor(job{name: "job", postsubmit: postsubmit, orgRepo: "org/repo"}, nil, nil); err == nil {
+		t.Error("expected an error for a postsubmit running on multiple branches, got none")
+	}
+}
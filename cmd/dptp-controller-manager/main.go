@@ -34,6 +34,7 @@ import (
 	"github.com/openshift/ci-tools/pkg/api"
 	"github.com/openshift/ci-tools/pkg/config"
 	"github.com/openshift/ci-tools/pkg/controller/promotionreconciler"
+	"github.com/openshift/ci-tools/pkg/controller/resourcereaper"
 	serviceaccountsecretrefresher "github.com/openshift/ci-tools/pkg/controller/serviceaccount_secret_refresher"
 	testimagesdistributor "github.com/openshift/ci-tools/pkg/controller/test-images-distributor"
 	"github.com/openshift/ci-tools/pkg/controller/testimagestreamimportcleaner"
@@ -51,6 +52,7 @@ var allControllers = sets.New[string](
 	testimagesdistributor.ControllerName,
 	serviceaccountsecretrefresher.ControllerName,
 	testimagestreamimportcleaner.ControllerName,
+	resourcereaper.ControllerName,
 )
 
 type options struct {
@@ -69,6 +71,7 @@ type options struct {
 	serviceAccountSecretRefresherOptions serviceAccountSecretRefresherOptions
 	imagePusherOptions                   imagePusherOptions
 	promotionReconcilerOptions           promotionReconcilerOptions
+	resourceReaperOptions                resourceReaperOptions
 	*flagutil.GitHubOptions
 	releaseRepoGitSyncPath string
 }
@@ -102,6 +105,13 @@ type imagePusherOptions struct {
 	imageStreams    sets.Set[string]
 }
 
+type resourceReaperOptions struct {
+	namespaceTTL            time.Duration
+	clusterClaimGracePeriod time.Duration
+	leaseTTL                time.Duration
+	leaseNamespaces         flagutil.Strings
+}
+
 type serviceAccountSecretRefresherOptions struct {
 	enabledNamespaces     flagutil.Strings
 	removeOldSecrets      bool
@@ -134,6 +144,10 @@ func newOpts() (*options, error) {
 	fs.Var(&opts.imagePusherOptions.imageStreamsRaw, "imagePusherOptions.image-stream", "An imagestream that will be synced. It must be in namespace/name format (e.G `ci/clonerefs`). Can be passed multiple times.")
 	fs.Var(&opts.promotionReconcilerOptions.ignoreImageStreamsRaw, "promotionReconcilerOptions.ignore-image-stream", "The image stream to ignore. It is an regular expression (e.G ^openshift-priv/.+). Can be passed multiple times.")
 	fs.StringVar(&opts.promotionReconcilerOptions.sinceRaw, "promotionReconcilerOptions.since", "360h", "The image stream tags to reconcile if it is younger than a relative duration like 5s, 2m, or 3h. Defaults to 360h, i.e., 15 days")
+	fs.DurationVar(&opts.resourceReaperOptions.namespaceTTL, "resourceReaperOptions.namespace-ttl", 72*time.Hour, "The maximum age of a ci-operator namespace, after which it is deleted regardless of its TTL annotations")
+	fs.DurationVar(&opts.resourceReaperOptions.clusterClaimGracePeriod, "resourceReaperOptions.cluster-claim-grace-period", time.Hour, "The time a cluster claim created by a job is kept after its lifetime expired")
+	fs.DurationVar(&opts.resourceReaperOptions.leaseTTL, "resourceReaperOptions.lease-ttl", 24*time.Hour, "The time a lease is kept after it expired")
+	fs.Var(&opts.resourceReaperOptions.leaseNamespaces, "resourceReaperOptions.lease-namespace", "A namespace in which expired leases are deleted. Can be passed multiple times.")
 	fs.BoolVar(&opts.dryRun, "dry-run", true, "Whether to run the controller-manager with dry-run")
 	fs.StringVar(&opts.releaseRepoGitSyncPath, "release-repo-git-sync-path", "", "Path to release repository dir")
 	if err := fs.Parse(os.Args[1:]); err != nil {
@@ -222,6 +236,18 @@ func newOpts() (*options, error) {
 		}
 	}
 
+	if opts.enabledControllersSet.Has(resourcereaper.ControllerName) {
+		for flagName, value := range map[string]time.Duration{
+			"resourceReaperOptions.namespace-ttl":              opts.resourceReaperOptions.namespaceTTL,
+			"resourceReaperOptions.cluster-claim-grace-period": opts.resourceReaperOptions.clusterClaimGracePeriod,
+			"resourceReaperOptions.lease-ttl":                  opts.resourceReaperOptions.leaseTTL,
+		} {
+			if value < 0 {
+				errs = append(errs, fmt.Errorf("--%s must not be negative", flagName))
+			}
+		}
+	}
+
 	if err := opts.GitHubOptions.Validate(opts.dryRun); err != nil {
 		errs = append(errs, err)
 	}
@@ -510,6 +536,19 @@ func main() {
 		}
 	}
 
+	if opts.enabledControllersSet.Has(resourcereaper.ControllerName) {
+		reaperOptions := resourcereaper.Options{
+			NamespaceTTL:            opts.resourceReaperOptions.namespaceTTL,
+			ClusterClaimGracePeriod: opts.resourceReaperOptions.clusterClaimGracePeriod,
+			LeaseTTL:                opts.resourceReaperOptions.leaseTTL,
+			LeaseNamespaces:         opts.resourceReaperOptions.leaseNamespaces.StringSet(),
+			DryRun:                  opts.dryRun,
+		}
+		if err := resourcereaper.AddToManager(mgr, allManagers, string(api.HiveCluster), reaperOptions); err != nil {
+			logrus.WithError(err).Fatal("Failed to construct the resourcereaper controller")
+		}
+	}
+
 	if err := mgr.Start(ctx); err != nil {
 		logrus.WithError(err).Fatal("Manager ended with error")
 	}
@@ -255,8 +255,12 @@ func main() {
 	for _, kerberosId := range mapping {
 		kerberosIds.Insert(kerberosId)
 	}
+	protectedUsers := sets.New[string](defaultProtectedUsers...)
+	if config != nil {
+		protectedUsers.Insert(config.ProtectedUsers...)
+	}
 	if opts.deleteInvalidUsers {
-		if err := deleteInvalidUsers(ctx, clients, kerberosIds, sets.New[string](ciAdmins...), opts.dryRun); err != nil {
+		if err := deleteInvalidUsers(ctx, clients, kerberosIds, sets.New[string](ciAdmins...), protectedUsers, opts.dryRun); err != nil {
 			logrus.WithError(err).Fatal("Failed to delete users")
 		}
 	}
@@ -266,7 +270,7 @@ func main() {
 		logrus.WithError(err).Fatal("Failed to make groups")
 	}
 
-	if err := ensureGroups(ctx, clients, groups, opts.maxConcurrency, opts.dryRun, sets.New[string](prowDisabledClusters...)); err != nil {
+	if err := ensureGroups(ctx, clients, groups, opts.maxConcurrency, opts.dryRun, sets.New[string](prowDisabledClusters...), protectedUsers); err != nil {
 		logrus.WithError(err).Fatal("could not ensure groups")
 	}
 }
@@ -308,8 +312,11 @@ type GroupClusters struct {
 
 var githubRobotIds = sets.New[string]("RH-Cachito", "openshift-bot", "openshift-ci-robot", "openshift-merge-robot", "openshift-cherrypick-robot")
 
+// defaultProtectedUsers are protected in addition to the protected users from the configuration file
+var defaultProtectedUsers = []string{"backplane-cluster-admin"}
+
 func deleteInvalidUsers(ctx context.Context, clients map[string]ctrlruntimeclient.Client,
-	kerberosIDs sets.Set[string], ciAdmins sets.Set[string], dryRun bool) error {
+	kerberosIDs sets.Set[string], ciAdmins sets.Set[string], protectedUsers sets.Set[string], dryRun bool) error {
 
 	var errs []error
 	for cluster, client := range clients {
@@ -319,8 +326,8 @@ func deleteInvalidUsers(ctx context.Context, clients map[string]ctrlruntimeclien
 			continue
 		}
 		for user, identites := range usersToDelete {
-			if user == "backplane-cluster-admin" {
-				logrus.WithField("cluster", cluster).Info("Skip deleting user backplane-cluster-admin")
+			if protectedUsers.Has(user) {
+				logrus.WithField("cluster", cluster).WithField("user", user).Info("Skip deleting protected user")
 				continue
 			}
 			if ciAdmins.Has(user) {
@@ -453,9 +460,16 @@ func makeGroups(openshiftPrivAdmins sets.Set[string], peribolosConfig string, ma
 	return groups, kerrors.NewAggregate(errs)
 }
 
-func ensureGroups(ctx context.Context, clients map[string]ctrlruntimeclient.Client, groupsToCreate map[string]GroupClusters, maxConcurrency int, dryRun bool, disabledClusters sets.Set[string]) error {
+func ensureGroups(ctx context.Context, clients map[string]ctrlruntimeclient.Client, groupsToCreate map[string]GroupClusters, maxConcurrency int, dryRun bool, disabledClusters sets.Set[string], protectedUsers sets.Set[string]) error {
 	var errs []error
 
+	renamedFrom := map[string]string{}
+	for name, groupClusters := range groupsToCreate {
+		if oldName, ok := groupClusters.Group.Labels["rover-group-name"]; ok {
+			renamedFrom[oldName] = name
+		}
+	}
+
 	for cluster, client := range clients {
 		listOption := ctrlruntimeclient.MatchingLabels{
 			api.DPTPRequesterLabel: toolName,
@@ -477,7 +491,11 @@ func ensureGroups(ctx context.Context, clients map[string]ctrlruntimeclient.Clie
 						errs = append(errs, fmt.Errorf("attempt to delete group %s on cluster %s", group.Name, cluster))
 						continue
 					}
-					logrus.WithField("cluster", cluster).WithField("group.Name", group.Name).Info("Deleting group ...")
+					logger := logrus.WithField("cluster", cluster).WithField("group.Name", group.Name)
+					if newName, ok := renamedFrom[group.Name]; ok {
+						logger = logger.WithField("renamedTo", newName)
+					}
+					logger.Info("Deleting group ...")
 					if dryRun {
 						continue
 					}
@@ -499,11 +517,11 @@ func ensureGroups(ctx context.Context, clients map[string]ctrlruntimeclient.Clie
 			"cluster":    cluster,
 			"group.Name": group.Name,
 		})
-		logger.Info("Upserting group ...")
 		if dryRun {
-			return nil
+			return logGroupDiff(ctx, client, group, protectedUsers, logger)
 		}
-		if err := upsertGroupWithRetry(ctx, client, cluster, group, logger); err != nil {
+		logger.Info("Upserting group ...")
+		if err := upsertGroupWithRetry(ctx, client, cluster, group, protectedUsers, logger); err != nil {
 			return fmt.Errorf("failed to upsert group %s on cluster %s after retrying: %w", group.Name, cluster, err)
 		}
 
@@ -570,9 +588,45 @@ func validate(group *userv1.Group) error {
 	return nil
 }
 
-func upsertGroupWithRetry(ctx context.Context, client ctrlruntimeclient.Client, cluster string, group *userv1.Group, logger *logrus.Entry) error {
+// logGroupDiff logs the changes to the members of the group that would be made on the cluster
+func logGroupDiff(ctx context.Context, client ctrlruntimeclient.Client, group *userv1.Group, protectedUsers sets.Set[string], logger *logrus.Entry) error {
+	existing := &userv1.Group{}
+	if err := client.Get(ctx, ctrlruntimeclient.ObjectKey{Name: group.Name}, existing); err != nil {
+		if errors.IsNotFound(err) {
+			logger.WithField("added", []string(group.Users)).Info("Group would be created")
+			return nil
+		}
+		return fmt.Errorf("failed to get group %s: %w", group.Name, err)
+	}
+	added, removed := membersDiff(existing.Users, withProtectedMembers(group.Users, existing.Users, protectedUsers))
+	if len(added) == 0 && len(removed) == 0 {
+		logger.Debug("Group with expected members already present in the cluster")
+		return nil
+	}
+	logger.WithField("added", added).WithField("removed", removed).Info("Group members would be changed")
+	return nil
+}
+
+// membersDiff returns the members that are in desired but not in existing, and those
+// that are in existing but not in desired
+func membersDiff(existing, desired []string) (added []string, removed []string) {
+	existingSet, desiredSet := sets.New[string](existing...), sets.New[string](desired...)
+	return sets.List(desiredSet.Difference(existingSet)), sets.List(existingSet.Difference(desiredSet))
+}
+
+// withProtectedMembers adds the protected users that are members of the existing group
+// to the desired members, so that they are never removed from the group
+func withProtectedMembers(desired, existing []string, protectedUsers sets.Set[string]) userv1.OptionalNames {
+	kept := sets.New[string](existing...).Intersection(protectedUsers).Difference(sets.New[string](desired...))
+	if kept.Len() == 0 {
+		return desired
+	}
+	return append(append(userv1.OptionalNames{}, desired...), sets.List(kept)...)
+}
+
+func upsertGroupWithRetry(ctx context.Context, client ctrlruntimeclient.Client, cluster string, group *userv1.Group, protectedUsers sets.Set[string], logger *logrus.Entry) error {
 	if err := wait.ExponentialBackoff(wait.Backoff{Steps: 4, Factor: 2, Duration: time.Second}, func() (bool, error) {
-		modified, err := upsertGroup(ctx, client, group)
+		modified, err := upsertGroup(ctx, client, group, protectedUsers)
 		if err != nil {
 			logger.WithError(err).WithField("cluster", cluster).WithField("group", group.Name).Warn("Failed to upsert group")
 			return false, nil
@@ -589,7 +643,7 @@ func upsertGroupWithRetry(ctx context.Context, client ctrlruntimeclient.Client,
 	return nil
 }
 
-func upsertGroup(ctx context.Context, client ctrlruntimeclient.Client, group *userv1.Group) (modified bool, err error) {
+func upsertGroup(ctx context.Context, client ctrlruntimeclient.Client, group *userv1.Group, protectedUsers sets.Set[string]) (modified bool, err error) {
 	err = client.Create(ctx, group.DeepCopy())
 	if err == nil {
 		return true, nil
@@ -601,6 +655,8 @@ func upsertGroup(ctx context.Context, client ctrlruntimeclient.Client, group *us
 	if err := client.Get(ctx, ctrlruntimeclient.ObjectKey{Name: group.Name}, existing); err != nil {
 		return false, fmt.Errorf("[2] get failed: %w", err)
 	}
+	group = group.DeepCopy()
+	group.Users = withProtectedMembers(group.Users, existing.Users, protectedUsers)
 	if equality.Semantic.DeepEqual(group.Users, existing.Users) {
 		return false, nil
 	}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/stretchr/testify/assert"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -150,6 +151,7 @@ func TestEnsureGroups(t *testing.T) {
 		groups           map[string]GroupClusters
 		dryRun           bool
 		disabledClusters sets.Set[string]
+		protectedUsers   sets.Set[string]
 		expected         error
 		verifyFunc       func(ctx context.Context, clients map[string]ctrlruntimeclient.Client) error
 	}{
@@ -310,6 +312,41 @@ func TestEnsureGroups(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "protected users are not removed from groups",
+			clients: map[string]ctrlruntimeclient.Client{
+				"b01": fakeclient.NewClientBuilder().WithRuntimeObjects(&userv1.Group{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "some-group",
+						Labels: map[string]string{api.DPTPRequesterLabel: toolName},
+					},
+					Users: userv1.OptionalNames{"break-glass", "offboarded", "u01"},
+				}).Build(),
+			},
+			groups: map[string]GroupClusters{
+				"some-group": {
+					Clusters: sets.New[string]("b01"),
+					Group: &userv1.Group{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   "some-group",
+							Labels: map[string]string{api.DPTPRequesterLabel: toolName},
+						},
+						Users: userv1.OptionalNames{"u01", "u02"},
+					},
+				},
+			},
+			protectedUsers: sets.New[string]("break-glass", "not-a-member"),
+			verifyFunc: func(ctx context.Context, clients map[string]ctrlruntimeclient.Client) error {
+				actual := &userv1.Group{}
+				if err := clients["b01"].Get(ctx, ctrlruntimeclient.ObjectKey{Name: "some-group"}, actual); err != nil {
+					return err
+				}
+				if diff := cmp.Diff(userv1.OptionalNames{"u01", "u02", "break-glass"}, actual.Users); diff != "" {
+					return fmt.Errorf("members do not match expected, diff: %s", diff)
+				}
+				return nil
+			},
+		},
 		{
 			name: "invalid group: duplicate members",
 			clients: map[string]ctrlruntimeclient.Client{
@@ -336,7 +373,7 @@ func TestEnsureGroups(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx := context.TODO()
-			actual := ensureGroups(ctx, tc.clients, tc.groups, 60, tc.dryRun, tc.disabledClusters)
+			actual := ensureGroups(ctx, tc.clients, tc.groups, 60, tc.dryRun, tc.disabledClusters, tc.protectedUsers)
 			if diff := cmp.Diff(tc.expected, actual, testhelper.EquateErrorMessage); diff != "" {
 				t.Errorf("%s: actual does not match expected, diff: %s", tc.name, diff)
 			}
@@ -473,11 +510,12 @@ func TestDeleteInvalidUsers(t *testing.T) {
 		ProviderUserName: "d",
 	}
 	testCases := []struct {
-		name        string
-		clients     map[string]ctrlruntimeclient.Client
-		kerberosIDs sets.Set[string]
-		ciAdmins    sets.Set[string]
-		verifyFunc  func(ctx context.Context, clients map[string]ctrlruntimeclient.Client) error
+		name           string
+		clients        map[string]ctrlruntimeclient.Client
+		kerberosIDs    sets.Set[string]
+		ciAdmins       sets.Set[string]
+		protectedUsers sets.Set[string]
+		verifyFunc     func(ctx context.Context, clients map[string]ctrlruntimeclient.Client) error
 	}{
 		{
 			name: "basic case",
@@ -597,11 +635,33 @@ func TestDeleteInvalidUsers(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "protected users are not deleted",
+			clients: map[string]ctrlruntimeclient.Client{
+				"b01": fakeclient.NewClientBuilder().WithRuntimeObjects(
+					u01.DeepCopy(), u02.DeepCopy(), u03.DeepCopy(),
+					i01.DeepCopy(), i02.DeepCopy(), i03.DeepCopy(), i04.DeepCopy()).Build(),
+			},
+			kerberosIDs:    sets.New[string]("b"),
+			ciAdmins:       sets.New[string](),
+			protectedUsers: sets.New[string]("c"),
+			verifyFunc: func(ctx context.Context, clients map[string]ctrlruntimeclient.Client) error {
+				client := clients["b01"]
+				assert.False(t, isUser(ctx, client, "a"))
+				assert.False(t, isIdentity(ctx, client, "a"))
+				assert.True(t, isUser(ctx, client, "b"))
+				assert.True(t, isIdentity(ctx, client, "b"))
+				assert.True(t, isUser(ctx, client, "c"))
+				assert.True(t, isIdentity(ctx, client, "c"))
+				assert.True(t, isIdentity(ctx, client, "d"))
+				return nil
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx := context.TODO()
-			err := deleteInvalidUsers(ctx, tc.clients, tc.kerberosIDs, tc.ciAdmins, false)
+			err := deleteInvalidUsers(ctx, tc.clients, tc.kerberosIDs, tc.ciAdmins, tc.protectedUsers, false)
 			if err != nil {
 				t.Errorf("%s: unexpected error occurred: %v", tc.name, err)
 			}
@@ -623,3 +683,45 @@ func isIdentity(ctx context.Context, client ctrlruntimeclient.Client, user strin
 	err := client.Get(ctx, ctrlruntimeclient.ObjectKey{Name: user}, &userv1.Identity{})
 	return err == nil
 }
+
+func TestMembersDiff(t *testing.T) {
+	testCases := []struct {
+		name            string
+		existing        []string
+		desired         []string
+		protectedUsers  sets.Set[string]
+		expectedAdded   []string
+		expectedRemoved []string
+	}{
+		{
+			name:     "no changes",
+			existing: []string{"a", "b"},
+			desired:  []string{"b", "a"},
+		},
+		{
+			name:            "members are added and removed",
+			existing:        []string{"a", "b"},
+			desired:         []string{"b", "c"},
+			expectedAdded:   []string{"c"},
+			expectedRemoved: []string{"a"},
+		},
+		{
+			name:           "protected members are not removed",
+			existing:       []string{"a", "b"},
+			desired:        []string{"b", "c"},
+			protectedUsers: sets.New[string]("a"),
+			expectedAdded:  []string{"c"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			added, removed := membersDiff(tc.existing, withProtectedMembers(tc.desired, tc.existing, tc.protectedUsers))
+			if diff := cmp.Diff(tc.expectedAdded, added, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("added members differ from expected: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedRemoved, removed, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("removed members differ from expected: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"github.com/sirupsen/logrus"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/boskos/common"
+)
+
+// metricClient queries the states of the resources of a type, *client.Client from Boskos
+// implements it
+type metricClient interface {
+	Metric(rtype string) (common.Metric, error)
+}
+
+// usage is the number of resources of a type in each state at a point in time
+type usage struct {
+	Free, Leased, Dirty, Other int
+}
+
+func (u usage) total() int {
+	return u.Free + u.Leased + u.Dirty + u.Other
+}
+
+// utilization is the fraction of resources that cannot be leased
+func (u usage) utilization() float64 {
+	if u.total() == 0 {
+		return 0
+	}
+	return float64(u.total()-u.Free) / float64(u.total())
+}
+
+func usageFor(metric common.Metric) usage {
+	var u usage
+	for state, count := range metric.Current {
+		switch state {
+		case common.Free:
+			u.Free += count
+		case common.Leased:
+			u.Leased += count
+		case common.Dirty:
+			u.Dirty += count
+		default:
+			u.Other += count
+		}
+	}
+	return u
+}
+
+// sample is the usage of all resource types at a point in time
+type sample struct {
+	time  time.Time
+	usage map[string]usage
+}
+
+// resourceTypes returns the types of resources declared in the Boskos configuration
+func resourceTypes(config *common.BoskosConfig) []string {
+	types := sets.New[string]()
+	for _, resource := range config.Resources {
+		types.Insert(resource.Type)
+	}
+	return sets.List(types)
+}
+
+// collect queries the usage of the resource types the given number of times, waiting for the
+// interval between the queries. It returns the samples taken before the context is cancelled.
+func collect(ctx context.Context, client metricClient, types []string, samples int, interval time.Duration, now func() time.Time) ([]sample, error) {
+	var collected []sample
+	for i := 0; i < samples; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return collected, nil
+			case <-time.After(interval):
+			}
+		}
+		s := sample{time: now(), usage: map[string]usage{}}
+		var errs []error
+		for _, rtype := range types {
+			metric, err := client.Metric(rtype)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to query resources of type %s: %w", rtype, err))
+				continue
+			}
+			s.usage[rtype] = usageFor(metric)
+		}
+		if err := utilerrors.NewAggregate(errs); err != nil {
+			return collected, err
+		}
+		logrus.WithField("sample", i+1).Debug("Collected resource usage")
+		collected = append(collected, s)
+	}
+	return collected, nil
+}
+
+// summary aggregates the samples of one resource type
+type summary struct {
+	rtype   string
+	latest  usage
+	minFree int
+	peak    float64
+	mean    float64
+}
+
+func (s summary) exhausted() bool {
+	return s.latest.total() > 0 && s.latest.Free == 0
+}
+
+func summarize(samples []sample) []summary {
+	byType := map[string]*summary{}
+	for _, s := range samples {
+		for rtype, u := range s.usage {
+			sum, ok := byType[rtype]
+			if !ok {
+				sum = &summary{rtype: rtype, minFree: math.MaxInt}
+				byType[rtype] = sum
+			}
+			sum.latest = u
+			sum.minFree = min(sum.minFree, u.Free)
+			sum.peak = math.Max(sum.peak, u.utilization())
+			sum.mean += u.utilization() / float64(len(samples))
+		}
+	}
+	var summaries []summary
+	for _, sum := range byType {
+		summaries = append(summaries, *sum)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].rtype < summaries[j].rtype
+	})
+	return summaries
+}
+
+func percent(f float64) string {
+	return fmt.Sprintf("%.0f%%", f*100)
+}
+
+// writeMarkdown renders a report of the usage of all resource types, highlighting the ones whose
+// peak utilization reached the threshold
+func writeMarkdown(w io.Writer, samples []sample, threshold float64) error {
+	if len(samples) == 0 {
+		_, err := fmt.Fprintln(w, "No resource usage was collected.")
+		return err
+	}
+	b := &strings.Builder{}
+	first, last := samples[0].time, samples[len(samples)-1].time
+	fmt.Fprintf(b, "# Lease inventory\n\n%d sample(s) from %s to %s.\n\n", len(samples), first.UTC().Format(time.RFC3339), last.UTC().Format(time.RFC3339))
+	fmt.Fprintln(b, "| Type | Total | Free | Leased | Dirty | Other | Min. free | Mean utilization | Peak utilization | |")
+	fmt.Fprintln(b, "|---|---|---|---|---|---|---|---|---|---|")
+	var atRisk []string
+	for _, sum := range summarize(samples) {
+		var status string
+		switch {
+		case sum.exhausted():
+			status = ":x: exhausted"
+		case sum.peak >= threshold:
+			status = ":warning:"
+		}
+		if status != "" {
+			atRisk = append(atRisk, sum.rtype)
+		}
+		fmt.Fprintf(b, "| `%s` | %d | %d | %d | %d | %d | %d | %s | %s | %s |\n", sum.rtype, sum.latest.total(), sum.latest.Free, sum.latest.Leased, sum.latest.Dirty, sum.latest.Other, sum.minFree, percent(sum.mean), percent(sum.peak), status)
+	}
+	if len(atRisk) > 0 {
+		fmt.Fprintf(b, "\nThe peak utilization of %d resource type(s) reached %s: %s\n", len(atRisk), percent(threshold), strings.Join(atRisk, ", "))
+	}
+	if len(samples) > 1 {
+		fmt.Fprintln(b, "\n## Utilization over time")
+		for _, sum := range summarize(samples) {
+			fmt.Fprintf(b, "\n### `%s`\n\n| Time | Free | Leased | Dirty | Other | Utilization |\n|---|---|---|---|---|---|\n", sum.rtype)
+			for _, s := range samples {
+				u, ok := s.usage[sum.rtype]
+				if !ok {
+					continue
+				}
+				fmt.Fprintf(b, "| %s | %d | %d | %d | %d | %s |\n", s.time.UTC().Format(time.RFC3339), u.Free, u.Leased, u.Dirty, u.Other, percent(u.utilization()))
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writePrometheus renders the latest usage of all resource types in the Prometheus text format,
+// for example for the textfile collector of the node exporter
+func writePrometheus(w io.Writer, samples []sample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	resources := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "boskos_inventory_resources",
+		Help: "Number of resources of a type in a state.",
+	}, []string{"type", "state"})
+	utilization := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "boskos_inventory_utilization_ratio",
+		Help: "Fraction of the resources of a type that are not free.",
+	}, []string{"type"})
+	peak := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "boskos_inventory_peak_utilization_ratio",
+		Help: "Highest fraction of the resources of a type that were not free over the collected samples.",
+	}, []string{"type"})
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(resources, utilization, peak)
+	for _, sum := range summarize(samples) {
+		for state, count := range map[string]int{common.Free: sum.latest.Free, common.Leased: sum.latest.Leased, common.Dirty: sum.latest.Dirty, common.Other: sum.latest.Other} {
+			resources.WithLabelValues(sum.rtype, state).Set(float64(count))
+		}
+		utilization.WithLabelValues(sum.rtype).Set(sum.latest.utilization())
+		peak.WithLabelValues(sum.rtype).Set(sum.peak)
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+	for _, family := range families {
+		if _, err := expfmt.MetricFamilyToText(w, family); err != nil {
+			return fmt.Errorf("failed to write metrics: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/boskos/common"
+
+	"github.com/openshift/ci-tools/pkg/testhelper"
+)
+
+type fakeMetricClient struct {
+	// metrics are returned for each query in turn
+	metrics map[string][]map[string]int
+	queries map[string]int
+}
+
+func (f *fakeMetricClient) Metric(rtype string) (common.Metric, error) {
+	if f.queries == nil {
+		f.queries = map[string]int{}
+	}
+	metrics, ok := f.metrics[rtype]
+	if !ok {
+		return common.Metric{}, errors.New("injected error")
+	}
+	current := metrics[f.queries[rtype]%len(metrics)]
+	f.queries[rtype]++
+	return common.Metric{Type: rtype, Current: current}, nil
+}
+
+func TestResourceTypes(t *testing.T) {
+	config := &common.BoskosConfig{Resources: []common.ResourceEntry{
+		{Type: "aws-quota-slice", State: common.Free},
+		{Type: "gcp-quota-slice", State: common.Free},
+		{Type: "aws-quota-slice", State: common.Dirty},
+	}}
+	if diff := cmp.Diff([]string{"aws-quota-slice", "gcp-quota-slice"}, resourceTypes(config)); diff != "" {
+		t.Errorf("resource types differ from expected: %s", diff)
+	}
+}
+
+func TestUsageFor(t *testing.T) {
+	u := usageFor(common.Metric{Current: map[string]int{common.Free: 2, common.Leased: 5, common.Dirty: 1, common.Busy: 1, common.Cleaning: 1}})
+	if diff := cmp.Diff(usage{Free: 2, Leased: 5, Dirty: 1, Other: 2}, u); diff != "" {
+		t.Errorf("usage differs from expected: %s", diff)
+	}
+	if u.utilization() != 0.8 {
+		t.Errorf("expected utilization 0.8, got %v", u.utilization())
+	}
+	if (usage{}).utilization() != 0 {
+		t.Errorf("expected no utilization without resources, got %v", (usage{}).utilization())
+	}
+}
+
+func testSamples(t *testing.T) []sample {
+	client := &fakeMetricClient{metrics: map[string][]map[string]int{
+		"aws-quota-slice": {
+			{common.Free: 6, common.Leased: 4},
+			{common.Free: 1, common.Leased: 8, common.Dirty: 1},
+			{common.Free: 0, common.Leased: 9, common.Cleaning: 1},
+		},
+		"gcp-quota-slice": {
+			{common.Free: 9, common.Leased: 1},
+			{common.Free: 8, common.Leased: 2},
+			{common.Free: 10},
+		},
+	}}
+	start := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	var calls int
+	now := func() time.Time {
+		calls++
+		return start.Add(time.Duration(calls-1) * time.Minute)
+	}
+	samples, err := collect(context.Background(), client, []string{"aws-quota-slice", "gcp-quota-slice"}, 3, time.Millisecond, now)
+	if err != nil {
+		t.Fatalf("failed to collect samples: %v", err)
+	}
+	return samples
+}
+
+func TestCollect(t *testing.T) {
+	samples := testSamples(t)
+	if len(samples) != 3 {
+		t.Fatalf("expected 3 samples, got %d", len(samples))
+	}
+	if diff := cmp.Diff(usage{Leased: 9, Other: 1}, samples[2].usage["aws-quota-slice"]); diff != "" {
+		t.Errorf("usage differs from expected: %s", diff)
+	}
+
+	client := &fakeMetricClient{metrics: map[string][]map[string]int{"aws-quota-slice": {{common.Free: 1}}}}
+	samples, err := collect(context.Background(), client, []string{"aws-quota-slice", "missing"}, 2, time.Millisecond, time.Now)
+	if err == nil {
+		t.Error("expected an error for a failed query, got none")
+	}
+	if len(samples) != 0 {
+		t.Errorf("expected no samples after a failed query, got %d", len(samples))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	samples, err = collect(ctx, client, []string{"aws-quota-slice"}, 5, time.Hour, time.Now)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(samples) != 1 {
+		t.Errorf("expected the samples taken before cancellation, got %d", len(samples))
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	out := &bytes.Buffer{}
+	if err := writeMarkdown(out, testSamples(t), 0.8); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+	testhelper.CompareWithFixture(t, out.String(), testhelper.WithExtension(".md"))
+}
+
+func TestWritePrometheus(t *testing.T) {
+	out := &bytes.Buffer{}
+	if err := writePrometheus(out, testSamples(t)); err != nil {
+		t.Fatalf("failed to write metrics: %v", err)
+	}
+	testhelper.CompareWithFixture(t, out.String(), testhelper.WithExtension(".prom"))
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/test-infra/prow/flagutil"
+	"k8s.io/test-infra/prow/logrusutil"
+	boskos "sigs.k8s.io/boskos/client"
+	"sigs.k8s.io/boskos/common"
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+
+	"github.com/openshift/ci-tools/pkg/api"
+)
+
+const (
+	outputMarkdown   = "markdown"
+	outputPrometheus = "prometheus"
+)
+
+type options struct {
+	leaseServer                string
+	leaseServerCredentialsFile string
+	boskosConfigPath           string
+	resourceTypes              flagutil.Strings
+
+	samples   int
+	interval  time.Duration
+	threshold float64
+
+	output     string
+	outputFile string
+}
+
+func parseOptions() (*options, error) {
+	o := &options{}
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	fs.StringVar(&o.leaseServer, "lease-server", api.URLForService(api.ServiceBoskos), "Address of the server that manages leases.")
+	fs.StringVar(&o.leaseServerCredentialsFile, "lease-server-credentials-file", "", "The path to credentials file used to access the lease server. The content is of the form <username>:<password>.")
+	fs.StringVar(&o.boskosConfigPath, "boskos-config", "", "Path to the Boskos configuration, all resource types declared in it are reported.")
+	fs.Var(&o.resourceTypes, "resource-type", "A resource type to report. Can be passed multiple times, in addition to the types from --boskos-config.")
+	fs.IntVar(&o.samples, "samples", 1, "Number of times the usage of the resources is queried.")
+	fs.DurationVar(&o.interval, "interval", time.Minute, "Time to wait between the samples.")
+	fs.Float64Var(&o.threshold, "utilization-threshold", 0.9, "Resource types whose peak utilization reaches this fraction are highlighted in the report.")
+	fs.StringVar(&o.output, "output", outputMarkdown, fmt.Sprintf("The format of the output, %q or %q.", outputMarkdown, outputPrometheus))
+	fs.StringVar(&o.outputFile, "output-file", "", "The file to write the output to, defaults to stdout.")
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		return nil, fmt.Errorf("failed to parse flags: %w", err)
+	}
+	return o, nil
+}
+
+func (o *options) validate() error {
+	if o.leaseServer == "" {
+		return errors.New("--lease-server is required")
+	}
+	if o.boskosConfigPath == "" && len(o.resourceTypes.Strings()) == 0 {
+		return errors.New("--boskos-config or --resource-type is required")
+	}
+	if o.samples < 1 {
+		return errors.New("--samples must be positive")
+	}
+	if o.samples > 1 && o.interval <= 0 {
+		return errors.New("--interval must be positive")
+	}
+	if o.threshold <= 0 || o.threshold > 1 {
+		return errors.New("--utilization-threshold must be in (0, 1]")
+	}
+	if o.output != outputMarkdown && o.output != outputPrometheus {
+		return fmt.Errorf("--output must be %q or %q", outputMarkdown, outputPrometheus)
+	}
+	return nil
+}
+
+func (o *options) types() ([]string, error) {
+	config := &common.BoskosConfig{}
+	if o.boskosConfigPath != "" {
+		var err error
+		if config, err = common.ParseConfig(o.boskosConfigPath); err != nil {
+			return nil, fmt.Errorf("failed to load the Boskos configuration: %w", err)
+		}
+	}
+	for _, rtype := range o.resourceTypes.Strings() {
+		config.Resources = append(config.Resources, common.ResourceEntry{Type: rtype})
+	}
+	return resourceTypes(config), nil
+}
+
+func (o *options) client() (*boskos.Client, error) {
+	var username string
+	var passwordGetter func() []byte
+	if o.leaseServerCredentialsFile != "" {
+		raw, err := os.ReadFile(o.leaseServerCredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the lease server credentials: %w", err)
+		}
+		splits := strings.Split(strings.TrimSpace(string(raw)), ":")
+		if len(splits) != 2 {
+			return nil, errors.New("got invalid content of lease server credentials file which must be of the form '<username>:<password>'")
+		}
+		username = splits[0]
+		passwordGetter = func() []byte {
+			return []byte(splits[1])
+		}
+	}
+	return boskos.NewClientWithPasswordGetter("lease-inventory", o.leaseServer, username, passwordGetter)
+}
+
+func main() {
+	logrusutil.ComponentInit()
+	o, err := parseOptions()
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to get options")
+	}
+	if err := o.validate(); err != nil {
+		logrus.WithError(err).Fatal("Invalid options")
+	}
+	types, err := o.types()
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to determine the resource types")
+	}
+	client, err := o.client()
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to construct the lease server client")
+	}
+
+	samples, err := collect(signals.SetupSignalHandler(), client, types, o.samples, o.interval, time.Now)
+	if err != nil {
+		if len(samples) == 0 {
+			logrus.WithError(err).Fatal("Failed to collect the usage of the resources")
+		}
+		logrus.WithError(err).Warn("Failed to collect the usage of the resources, reporting the samples collected so far")
+	}
+
+	var out io.Writer = os.Stdout
+	if o.outputFile != "" {
+		f, err := os.Create(o.outputFile)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to create the output file")
+		}
+		defer f.Close()
+		out = f
+	}
+	switch o.output {
+	case outputMarkdown:
+		err = writeMarkdown(out, samples, o.threshold)
+	case outputPrometheus:
+		err = writePrometheus(out, samples)
+	}
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to write the output")
+	}
+}
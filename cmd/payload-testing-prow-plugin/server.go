@@ -13,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
 	prowapi "k8s.io/test-infra/prow/apis/prowjobs/v1"
 	prowconfig "k8s.io/test-infra/prow/config"
 	"k8s.io/test-infra/prow/github"
@@ -28,6 +29,8 @@ import (
 
 const (
 	prPayloadTestsUIURL = "https://pr-payload-tests.ci.openshift.org/runs"
+	// maxAggregatedCount limits the number of runs of an aggregated job
+	maxAggregatedCount = 20
 )
 
 type githubClient interface {
@@ -36,10 +39,10 @@ type githubClient interface {
 }
 
 var (
-	ocpPayloadTestsPattern              = regexp.MustCompile(`(?mi)^/payload\s+(?P<ocp>4\.\d+)\s+(?P<release>\w+)\s+(?P<jobs>\w+)\s*$`)
+	ocpPayloadTestsPattern              = regexp.MustCompile(`(?mi)^/payload\s+(?P<ocp>4\.\d+)\s+(?P<release>\w+(?:,\w+)*)\s+(?P<jobs>\w+)\s*$`)
 	ocpPayloadJobTestsPattern           = regexp.MustCompile(`(?mi)^/payload-job\s+((?:[-\w.]+\s*?)+)\s*$`)
 	ocpPayloadAggregatedJobTestsPattern = regexp.MustCompile(`(?mi)^/payload-aggregate\s+(?P<job>[-\w.]+)\s+(?P<aggregate>\d+)\s*$`)
-	ocpPayloadAbortPattern              = regexp.MustCompile(`(?mi)^/payload-abort$`)
+	ocpPayloadAbortPattern              = regexp.MustCompile(`(?mi)^/payload-abort(?:[ \t]+(?P<jobs>[-\w.]+(?:[ \t]+[-\w.]+)*))?[ \t\r]*$`)
 )
 
 func helpProvider(_ []prowconfig.OrgRepo) (*pluginhelp.PluginHelp, error) {
@@ -53,13 +56,25 @@ func helpProvider(_ []prowconfig.OrgRepo) (*pluginhelp.PluginHelp, error) {
 		Usage:       "/payload",
 		Description: "The payload-testing plugin triggers a run of specified release qualification jobs against PR code",
 		WhoCanUse:   "Members of the trusted organization for the repo.",
-		Examples:    []string{"/payload 4.10 nightly informing", "/payload 4.8 ci all"},
+		Examples:    []string{"/payload 4.10 nightly informing", "/payload 4.8 ci all", "/payload 4.14 ci,nightly blocking"},
 	})
 	pluginHelp.AddCommand(pluginhelp.Command{
-		Usage:       "/payload-abort",
-		Description: "The payload-testing plugin aborts all active payload jobs for the PR",
+		Usage:       "/payload-job <job> [<job> ...]",
+		Description: "The payload-testing plugin triggers a run of the given release qualification jobs against PR code",
 		WhoCanUse:   "Members of the trusted organization for the repo.",
-		Examples:    []string{"/payload-abort"},
+		Examples:    []string{"/payload-job periodic-ci-openshift-release-master-nightly-4.10-e2e-aws-serial"},
+	})
+	pluginHelp.AddCommand(pluginhelp.Command{
+		Usage:       "/payload-aggregate <job> <count>",
+		Description: fmt.Sprintf("The payload-testing plugin triggers the given release qualification job against PR code the given number of times, at most %d, and aggregates the results", maxAggregatedCount),
+		WhoCanUse:   "Members of the trusted organization for the repo.",
+		Examples:    []string{"/payload-aggregate periodic-ci-openshift-release-master-nightly-4.10-e2e-aws-serial 10"},
+	})
+	pluginHelp.AddCommand(pluginhelp.Command{
+		Usage:       "/payload-abort [<job> ...]",
+		Description: "The payload-testing plugin aborts all active payload jobs for the PR, or only the given ones",
+		WhoCanUse:   "Members of the trusted organization for the repo.",
+		Examples:    []string{"/payload-abort", "/payload-abort periodic-ci-openshift-release-master-nightly-4.10-e2e-aws-serial"},
 	})
 	return pluginHelp, nil
 }
@@ -115,18 +130,29 @@ func specsFromComment(comment string) []jobSetSpecification {
 	releaseIdx := ocpPayloadTestsPattern.SubexpIndex("release")
 	jobsIdx := ocpPayloadTestsPattern.SubexpIndex("jobs")
 
+	seen := map[jobSetSpecification]bool{}
 	for i := range matches {
-		specs = append(specs, jobSetSpecification{
-			ocp:         matches[i][ocpIdx],
-			releaseType: api.ReleaseStream(matches[i][releaseIdx]),
-			jobs:        config.JobType(matches[i][jobsIdx]),
-		})
+		for _, release := range strings.Split(matches[i][releaseIdx], ",") {
+			spec := jobSetSpecification{
+				ocp:         matches[i][ocpIdx],
+				releaseType: api.ReleaseStream(release),
+				jobs:        config.JobType(matches[i][jobsIdx]),
+			}
+			if seen[spec] {
+				continue
+			}
+			seen[spec] = true
+			specs = append(specs, spec)
+		}
 	}
 	return specs
 }
 
-func jobsFromComment(comment string) []config.Job {
+// jobsFromComment returns the jobs requested by the /payload-(job|aggregate) commands and
+// describes the requests that were rejected
+func jobsFromComment(comment string) ([]config.Job, []string) {
 	var ret []config.Job
+	var rejected []string
 	for _, match := range ocpPayloadJobTestsPattern.FindAllStringSubmatch(comment, -1) {
 		if len(match) < 2 {
 			// This should never happen
@@ -148,13 +174,31 @@ func jobsFromComment(comment string) []config.Job {
 			logrus.WithField("match", match).WithField("comment", comment).WithError(err).Error("failed to parse the aggregated job")
 			continue
 		}
+		if aggregatedCount < 1 || aggregatedCount > maxAggregatedCount {
+			rejected = append(rejected, fmt.Sprintf("%s: the aggregated count must be between 1 and %d, got %d", match[jobIndex], maxAggregatedCount, aggregatedCount))
+			continue
+		}
 		ret = append(ret, config.Job{
 			Name:            match[jobIndex],
 			AggregatedCount: aggregatedCount,
 		})
 
 	}
-	return ret
+	return ret, rejected
+}
+
+// abortFromComment determines whether the comment requests to abort payload jobs and
+// returns the names of the jobs to abort; no names means all jobs are aborted
+func abortFromComment(comment string) (bool, []string) {
+	match := ocpPayloadAbortPattern.FindStringSubmatch(comment)
+	if match == nil {
+		return false, nil
+	}
+	jobs := match[ocpPayloadAbortPattern.SubexpIndex("jobs")]
+	if jobs == "" {
+		return true, nil
+	}
+	return true, strings.Fields(jobs)
 }
 
 const (
@@ -191,7 +235,7 @@ func (s *server) handle(l *logrus.Entry, ic github.IssueCommentEvent) string {
 	logger.WithField("ic.Comment.Body", ic.Comment.Body).Trace("received a comment")
 
 	specs := specsFromComment(ic.Comment.Body)
-	jobsFromComment := jobsFromComment(ic.Comment.Body)
+	jobsFromComment, rejected := jobsFromComment(ic.Comment.Body)
 	if len(specs) == 0 {
 		logger.Trace("found no specs from comment")
 	}
@@ -203,8 +247,8 @@ func (s *server) handle(l *logrus.Entry, ic github.IssueCommentEvent) string {
 		specs = append(specs, jobSetSpecification{})
 	}
 
-	abortRequested := ocpPayloadAbortPattern.MatchString(strings.TrimSpace(ic.Comment.Body))
-	if len(specs) == 0 && !abortRequested {
+	abortRequested, jobsToAbort := abortFromComment(ic.Comment.Body)
+	if len(specs) == 0 && len(rejected) == 0 && !abortRequested {
 		return ""
 	}
 
@@ -221,7 +265,7 @@ func (s *server) handle(l *logrus.Entry, ic github.IssueCommentEvent) string {
 	}
 
 	if abortRequested {
-		return s.abortAll(logger, ic)
+		return s.abort(logger, ic, jobsToAbort)
 	}
 
 	startGetPullRequest := time.Now()
@@ -243,6 +287,9 @@ func (s *server) handle(l *logrus.Entry, ic github.IssueCommentEvent) string {
 	}
 
 	var messages []string
+	if len(rejected) > 0 {
+		messages = append(messages, rejectedMessage(rejected))
+	}
 	builder := &prpqrBuilder{
 		namespace: s.namespace,
 		org:       org,
@@ -260,7 +307,7 @@ func (s *server) handle(l *logrus.Entry, ic github.IssueCommentEvent) string {
 			"jobs":        spec.jobs,
 		})
 		builder.spec = spec
-		var jobNames []string
+		var jobNames, unresolved []string
 		var releaseJobSpecs []prpqv1.ReleaseJobSpec
 
 		var jobs []config.Job
@@ -284,7 +331,7 @@ func (s *server) handle(l *logrus.Entry, ic github.IssueCommentEvent) string {
 
 		for _, job := range jobs {
 			if job.Test != "" {
-				jobNames = append(jobNames, job.Name)
+				jobNames = append(jobNames, jobDescription(job))
 				releaseJobSpecs = append(releaseJobSpecs, prpqv1.ReleaseJobSpec{
 					CIOperatorConfig: prpqv1.CIOperatorMetadata{
 						Org:     job.Metadata.Org,
@@ -300,9 +347,10 @@ func (s *server) handle(l *logrus.Entry, ic github.IssueCommentEvent) string {
 				if err != nil {
 					// This is expected for non-generated jobs
 					specLogger.WithError(err).WithField("job.Name", job.Name).Info("could not resolve tests for job")
+					unresolved = append(unresolved, job.Name)
 					continue
 				}
-				jobNames = append(jobNames, job.Name)
+				jobNames = append(jobNames, jobDescription(job))
 				releaseJobSpecs = append(releaseJobSpecs, prpqv1.ReleaseJobSpec{
 					CIOperatorConfig: prpqv1.CIOperatorMetadata{
 						Org:     jobTuple.Metadata.Org,
@@ -326,30 +374,33 @@ func (s *server) handle(l *logrus.Entry, ic github.IssueCommentEvent) string {
 				specLogger.WithError(err).Error("could not create PullRequestPayloadQualificationRun")
 				return formatError(fmt.Errorf("could not create PullRequestPayloadQualificationRun: %w", err))
 			}
-			messages = append(messages, message(spec, jobNames))
+			messages = append(messages, message(spec, jobNames, unresolved))
 			messages = append(messages, fmt.Sprintf("See details on %s/%s/%s\n", prPayloadTestsUIURL, builder.namespace, run.Name))
 
 			specLogger.WithField("duration", time.Since(startCreateRuns)).WithField("run.Name", run.Name).
 				WithField("run.Namespace", run.Namespace).Debug("creating PullRequestPayloadQualificationRuns completed")
 		} else {
 			specLogger.Warn("found no resolved tests")
-			messages = append(messages, message(spec, jobNames))
+			messages = append(messages, message(spec, jobNames, unresolved))
 		}
 	}
 	logger.WithField("duration", time.Since(start)).Debug("handle completed")
 	return strings.Join(messages, "\n")
 }
 
-func (s *server) abortAll(logger *logrus.Entry, ic github.IssueCommentEvent) string {
+func (s *server) abort(logger *logrus.Entry, ic github.IssueCommentEvent, releaseJobNames []string) string {
 	org := ic.Repo.Owner.Login
 	repo := ic.Repo.Name
 	prNumber := ic.Issue.Number
 
-	jobs, err := s.getPayloadJobsForPR(org, repo, prNumber, logger)
+	jobs, err := s.getPayloadJobsForPR(org, repo, prNumber, releaseJobNames, logger)
 	if err != nil {
 		return formatError(err)
 	}
 	if len(jobs) == 0 {
+		if len(releaseJobNames) > 0 {
+			return fmt.Sprintf("no active payload jobs named %s found to abort for pull request %s/%s#%d", strings.Join(releaseJobNames, ", "), org, repo, prNumber)
+		}
 		return fmt.Sprintf("no active payload jobs found to abort for pull request %s/%s#%d", org, repo, prNumber)
 	}
 
@@ -384,10 +435,15 @@ func (s *server) abortAll(logger *logrus.Entry, ic github.IssueCommentEvent) str
 		return fmt.Sprintf("Failed to abort %d payload jobs out of %d. Failed jobs: %s", len(erroredJobs), len(jobs), strings.Join(erroredJobs, ", "))
 	}
 
+	if len(releaseJobNames) > 0 {
+		return fmt.Sprintf("aborted active payload jobs named %s for pull request %s/%s#%d", strings.Join(releaseJobNames, ", "), org, repo, prNumber)
+	}
 	return fmt.Sprintf("aborted active payload jobs for pull request %s/%s#%d", org, repo, prNumber)
 }
 
-func (s *server) getPayloadJobsForPR(org, repo string, prNumber int, logger *logrus.Entry) ([]string, error) {
+// getPayloadJobsForPR returns the active prowjobs for the pull request, limited to the given
+// release jobs if there are any
+func (s *server) getPayloadJobsForPR(org, repo string, prNumber int, releaseJobNames []string, logger *logrus.Entry) ([]string, error) {
 	var l prpqv1.PullRequestPayloadQualificationRunList
 	labelSelector, err := labelSelectorForPayloadPRPQRs(org, repo, prNumber)
 	if err != nil {
@@ -399,9 +455,13 @@ func (s *server) getPayloadJobsForPR(org, repo string, prNumber int, logger *log
 		return nil, fmt.Errorf("failed to gather payload job runs for pull request %s/%s#%d in order to abort", org, repo, prNumber)
 	}
 
+	wanted := sets.New[string](releaseJobNames...)
 	var jobs []string
 	for _, item := range l.Items {
 		for _, job := range item.Status.Jobs {
+			if wanted.Len() > 0 && !wanted.Has(job.ReleaseJobName) {
+				continue
+			}
 			state := job.Status.State
 			if state == prowapi.TriggeredState || state == prowapi.PendingState {
 				jobs = append(jobs, job.ProwJob)
@@ -481,7 +541,7 @@ func (b *prpqrBuilder) build(releaseJobSpecs []prpqv1.ReleaseJobSpec) *prpqv1.Pu
 	return run
 }
 
-func message(spec jobSetSpecification, tests []string) string {
+func message(spec jobSetSpecification, tests []string, unresolved []string) string {
 	var b strings.Builder
 	if spec.ocp == "" {
 		b.WriteString(fmt.Sprintf("trigger %d job(s) for the /payload-(job|aggregate) command\n", len(tests)))
@@ -491,6 +551,28 @@ func message(spec jobSetSpecification, tests []string) string {
 	for _, test := range tests {
 		b.WriteString(fmt.Sprintf("- %s\n", test))
 	}
+	if len(unresolved) > 0 {
+		b.WriteString(fmt.Sprintf("\n%d job(s) could not be resolved to a test and are not triggered\n", len(unresolved)))
+		for _, job := range unresolved {
+			b.WriteString(fmt.Sprintf("- %s\n", job))
+		}
+	}
+	return b.String()
+}
+
+func jobDescription(job config.Job) string {
+	if job.AggregatedCount > 0 {
+		return fmt.Sprintf("%s (aggregated %d times)", job.Name, job.AggregatedCount)
+	}
+	return job.Name
+}
+
+func rejectedMessage(rejected []string) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("ignore %d invalid request(s)\n", len(rejected)))
+	for _, r := range rejected {
+		b.WriteString(fmt.Sprintf("- %s\n", r))
+	}
 	return b.String()
 }
 
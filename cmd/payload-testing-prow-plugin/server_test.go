@@ -52,6 +52,16 @@ func TestSpecsFromComment(t *testing.T) {
 			comment:  "/payload 4.10 nightly informing\n/payload 4.8 ci all",
 			expected: []jobSetSpecification{{ocp: "4.10", releaseType: "nightly", jobs: "informing"}, {ocp: "4.8", releaseType: "ci", jobs: "all"}},
 		},
+		{
+			name:     "multiple release streams",
+			comment:  "/payload 4.14 ci,nightly blocking",
+			expected: []jobSetSpecification{{ocp: "4.14", releaseType: "ci", jobs: "blocking"}, {ocp: "4.14", releaseType: "nightly", jobs: "blocking"}},
+		},
+		{
+			name:     "duplicated release streams",
+			comment:  "/payload 4.14 ci,ci blocking\n/payload 4.14 ci blocking",
+			expected: []jobSetSpecification{{ocp: "4.14", releaseType: "ci", jobs: "blocking"}},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -67,9 +77,10 @@ func TestSpecsFromComment(t *testing.T) {
 
 func TestJobNamesFromComment(t *testing.T) {
 	testCases := []struct {
-		name     string
-		comment  string
-		expected []config.Job
+		name             string
+		comment          string
+		expected         []config.Job
+		expectedRejected []string
 	}{
 		{
 			name:    "no job name",
@@ -95,22 +106,78 @@ func TestJobNamesFromComment(t *testing.T) {
 			comment:  "/payload-aggregate periodic-ci-openshift-release-some-job   10  ",
 			expected: []config.Job{{Name: "periodic-ci-openshift-release-some-job", AggregatedCount: 10}},
 		},
+		{
+			name:             "/payload-aggregate with too many runs",
+			comment:          "/payload-aggregate periodic-ci-openshift-release-some-job 100\n/payload-aggregate periodic-ci-openshift-release-another-job 0",
+			expectedRejected: []string{"periodic-ci-openshift-release-some-job: the aggregated count must be between 1 and 20, got 100", "periodic-ci-openshift-release-another-job: the aggregated count must be between 1 and 20, got 0"},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			actual := jobsFromComment(tc.comment)
+			actual, actualRejected := jobsFromComment(tc.comment)
 			if diff := cmp.Diff(tc.expected, actual); diff != "" {
 				t.Errorf("%s differs from expected:\n%s", tc.name, diff)
 			}
+			if diff := cmp.Diff(tc.expectedRejected, actualRejected); diff != "" {
+				t.Errorf("%s rejected requests differ from expected:\n%s", tc.name, diff)
+			}
+		})
+	}
+}
+
+func TestAbortFromComment(t *testing.T) {
+	testCases := []struct {
+		name          string
+		comment       string
+		expectedAbort bool
+		expectedJobs  []string
+	}{
+		{
+			name:    "no abort",
+			comment: "/payload 4.10 nightly informing",
+		},
+		{
+			name:          "abort all",
+			comment:       "/payload-abort",
+			expectedAbort: true,
+		},
+		{
+			name:          "abort some jobs",
+			comment:       "/payload-abort periodic-ci-openshift-release-some-job  periodic-ci-openshift-release-another-job ",
+			expectedAbort: true,
+			expectedJobs:  []string{"periodic-ci-openshift-release-some-job", "periodic-ci-openshift-release-another-job"},
+		},
+		{
+			name:          "the next line is not part of the jobs to abort",
+			comment:       "/payload-abort\nthanks all",
+			expectedAbort: true,
+		},
+		{
+			name:          "jobs are only read from the command line",
+			comment:       "/payload-abort periodic-ci-openshift-release-some-job\r\nthanks all",
+			expectedAbort: true,
+			expectedJobs:  []string{"periodic-ci-openshift-release-some-job"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			abort, jobs := abortFromComment(tc.comment)
+			if diff := cmp.Diff(tc.expectedAbort, abort); diff != "" {
+				t.Errorf("%s abort differs from expected:\n%s", tc.name, diff)
+			}
+			if diff := cmp.Diff(tc.expectedJobs, jobs); diff != "" {
+				t.Errorf("%s jobs differ from expected:\n%s", tc.name, diff)
+			}
 		})
 	}
 }
 
 func TestMessage(t *testing.T) {
 	testCases := []struct {
-		name     string
-		spec     jobSetSpecification
-		expected string
+		name       string
+		spec       jobSetSpecification
+		unresolved []string
+		expected   string
 	}{
 		{
 			name: "basic case",
@@ -118,12 +185,24 @@ func TestMessage(t *testing.T) {
 			expected: `trigger 2 job(s) of type informing for the nightly release of OCP 4.10
 - dummy-ocp-4.10-nightly-informing-job1
 - dummy-ocp-4.10-nightly-informing-job2
+`,
+		},
+		{
+			name:       "unresolved jobs",
+			spec:       jobSetSpecification{ocp: "4.10", releaseType: "nightly", jobs: "informing"},
+			unresolved: []string{"release-openshift-ocp-installer-e2e-azure-serial-4.10"},
+			expected: `trigger 2 job(s) of type informing for the nightly release of OCP 4.10
+- dummy-ocp-4.10-nightly-informing-job1
+- dummy-ocp-4.10-nightly-informing-job2
+
+1 job(s) could not be resolved to a test and are not triggered
+- release-openshift-ocp-installer-e2e-azure-serial-4.10
 `,
 		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			actual := message(tc.spec, fakeResolve(tc.spec.ocp, tc.spec.releaseType, tc.spec.jobs))
+			actual := message(tc.spec, fakeResolve(tc.spec.ocp, tc.spec.releaseType, tc.spec.jobs), tc.unresolved)
 			if diff := cmp.Diff(tc.expected, actual); diff != "" {
 				t.Errorf("%s differs from expected:\n%s", tc.name, diff)
 			}
@@ -133,12 +212,13 @@ func TestMessage(t *testing.T) {
 
 func TestGetPayloadJobsForPR(t *testing.T) {
 	testCases := []struct {
-		name     string
-		org      string
-		repo     string
-		prNumber int
-		s        *server
-		expected []string
+		name            string
+		org             string
+		repo            string
+		prNumber        int
+		releaseJobNames []string
+		s               *server
+		expected        []string
 	}{
 		{
 			name:     "jobs exist in the proper states",
@@ -284,10 +364,47 @@ func TestGetPayloadJobsForPR(t *testing.T) {
 				namespace: "ci",
 			},
 		},
+		{
+			name:            "only the requested jobs",
+			org:             "org",
+			repo:            "repo",
+			prNumber:        123,
+			releaseJobNames: []string{"periodic-ci-openshift-release-some-job"},
+			s: &server{
+				kubeClient: fakeclient.NewClientBuilder().WithRuntimeObjects(
+					&prpqv1.PullRequestPayloadQualificationRun{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "ci",
+							Labels: map[string]string{
+								kube.OrgLabel:  "org",
+								kube.RepoLabel: "repo",
+								kube.PullLabel: "123",
+							},
+						},
+						Status: prpqv1.PullRequestPayloadTestStatus{
+							Jobs: []prpqv1.PullRequestPayloadJobStatus{
+								{
+									ReleaseJobName: "periodic-ci-openshift-release-some-job",
+									Status:         prowapi.ProwJobStatus{State: prowapi.PendingState},
+									ProwJob:        "some-job",
+								},
+								{
+									ReleaseJobName: "periodic-ci-openshift-release-another-job",
+									Status:         prowapi.ProwJobStatus{State: prowapi.PendingState},
+									ProwJob:        "another-job",
+								},
+							},
+						},
+					},
+				).Build(),
+				namespace: "ci",
+			},
+			expected: []string{"some-job"},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			jobs, err := tc.s.getPayloadJobsForPR(tc.org, tc.repo, tc.prNumber, logrus.NewEntry(nil))
+			jobs, err := tc.s.getPayloadJobsForPR(tc.org, tc.repo, tc.prNumber, tc.releaseJobNames, logrus.NewEntry(nil))
 			if err != nil {
 				t.Fatalf("couldn't get jobs")
 			}
@@ -549,6 +666,9 @@ See details on https://pr-payload-tests.ci.openshift.org/runs/ci/guid-0
 			expected: `trigger 1 job(s) for the /payload-(job|aggregate) command
 - periodic-ci-openshift-release-master-nightly-4.10-e2e-aws-serial
 
+1 job(s) could not be resolved to a test and are not triggered
+- periodic-ci-openshift-release-another-job
+
 See details on https://pr-payload-tests.ci.openshift.org/runs/ci/guid-0
 `,
 		},
@@ -576,10 +696,49 @@ See details on https://pr-payload-tests.ci.openshift.org/runs/ci/guid-0
 				},
 			},
 			expected: `trigger 2 job(s) for the /payload-(job|aggregate) command
+- periodic-ci-openshift-release-master-nightly-4.10-e2e-aws-serial (aggregated 10 times)
+- periodic-ci-openshift-release-master-nightly-4.10-e2e-metal-ipi (aggregated 10 times)
+
+See details on https://pr-payload-tests.ci.openshift.org/runs/ci/guid-0
+`,
+		},
+		{
+			name: "multiple release streams and an invalid aggregation",
+			s: &server{
+				ghc:        ghc,
+				ctx:        context.TODO(),
+				kubeClient: fakeclient.NewClientBuilder().Build(),
+				namespace:  "ci",
+				jobResolver: newFakeJobResolver(map[string][]config.Job{"4.10": {
+					{Name: "periodic-ci-openshift-release-master-nightly-4.10-e2e-aws-serial"},
+				}}),
+				testResolver:       newFakeTestResolver(),
+				trustedChecker:     &fakeTrustedChecker{},
+				ciOpConfigResolver: &fakeCIOpConfigResolver{},
+			},
+			ic: github.IssueCommentEvent{
+				GUID: "guid",
+				Repo: github.Repo{Owner: github.User{Login: "openshift"}},
+				Issue: github.Issue{
+					Number:      123,
+					PullRequest: &struct{}{},
+				},
+				Comment: github.IssueComment{
+					Body: "/payload 4.10 ci,nightly blocking\n/payload-aggregate periodic-ci-openshift-release-master-nightly-4.10-e2e-metal-ipi 50",
+				},
+			},
+			expected: `ignore 1 invalid request(s)
+- periodic-ci-openshift-release-master-nightly-4.10-e2e-metal-ipi: the aggregated count must be between 1 and 20, got 50
+
+trigger 1 job(s) of type blocking for the ci release of OCP 4.10
 - periodic-ci-openshift-release-master-nightly-4.10-e2e-aws-serial
-- periodic-ci-openshift-release-master-nightly-4.10-e2e-metal-ipi
 
 See details on https://pr-payload-tests.ci.openshift.org/runs/ci/guid-0
+
+trigger 1 job(s) of type blocking for the nightly release of OCP 4.10
+- periodic-ci-openshift-release-master-nightly-4.10-e2e-aws-serial
+
+See details on https://pr-payload-tests.ci.openshift.org/runs/ci/guid-1
 `,
 		},
 		{
@@ -615,9 +774,15 @@ See details on https://pr-payload-tests.ci.openshift.org/runs/ci/guid-0
 - periodic-ci-openshift-release-master-nightly-4.10-e2e-aws-serial
 - periodic-ci-openshift-release-master-nightly-4.10-e2e-metal-ipi
 
+1 job(s) could not be resolved to a test and are not triggered
+- release-openshift-ocp-installer-e2e-azure-serial-4.10
+
 See details on https://pr-payload-tests.ci.openshift.org/runs/ci/guid-0
 
 trigger 0 job(s) of type all for the ci release of OCP 4.8
+
+1 job(s) could not be resolved to a test and are not triggered
+- some-non-prow-gen-job
 `,
 		},
 		{
@@ -50,6 +50,10 @@ type options struct {
 	explainsRaw flagutil.Strings
 	explains    map[api.ImageStreamTagReference]string
 
+	reportPath    string
+	allowListPath string
+	allowList     allowList
+
 	logLevel string
 }
 
@@ -65,6 +69,8 @@ func parseOptions() *options {
 	fs.StringVar(&opts.openshiftMappingDir, "openshift-mapping-dir", "", "Path to the openshift mapping directory")
 	fs.StringVar(&opts.openshiftMappingConfigPath, "openshift-mapping-config", "", "Path to the openshift mapping config file")
 	fs.Var(&opts.explainsRaw, "explain", "An imagestreamtag to explain its existence. It must be in namespace/name:tag format (e.G `ci/clonerefs:latest`). Can be passed multiple times.")
+	fs.StringVar(&opts.reportPath, "report-path", "", "Path to write a JSON report of every tag to delete to before anything is pruned, e.g., in $ARTIFACTS")
+	fs.StringVar(&opts.allowListPath, "allow-list", "", "Path to a config file listing the image streams that must never be touched")
 	if err := fs.Parse(os.Args[1:]); err != nil {
 		logrus.WithError(err).Fatal("could not parse args")
 	}
@@ -102,6 +108,14 @@ func (o *options) validate() error {
 		o.ignoredImageStreamTags = append(o.ignoredImageStreamTags, re)
 	}
 
+	if o.allowListPath != "" {
+		a, err := loadAllowList(o.allowListPath)
+		if err != nil {
+			return fmt.Errorf("could not load allow list: %w", err)
+		}
+		o.allowList = a
+	}
+
 	if o.openshiftMappingConfigPath != "" && len(o.explainsRaw.Strings()) > 0 {
 		return fmt.Errorf("--openshift-mapping-config and --explain cannot be set together")
 	}
@@ -301,7 +315,7 @@ func isMirroredFromOCP(tag api.ImageStreamTagReference, refs []releaseconfig.Ima
 	return false
 }
 
-func deleteTagsOnBuildFarm(ctx context.Context, appCIClient ctrlruntimeclient.Client, buildClusterClients map[string]ctrlruntimeclient.Client, imageStreamsWithPromotedTags map[ctrlruntimeclient.ObjectKey]interface{}, dryRun bool) error {
+func deleteTagsOnBuildFarm(ctx context.Context, appCIClient ctrlruntimeclient.Client, buildClusterClients map[string]ctrlruntimeclient.Client, imageStreamsWithPromotedTags map[ctrlruntimeclient.ObjectKey]interface{}, dryRun bool, report *pruneReport) error {
 	var errs []error
 	for streamKey := range imageStreamsWithPromotedTags {
 		for cluster, client := range buildClusterClients {
@@ -321,6 +335,7 @@ func deleteTagsOnBuildFarm(ctx context.Context, appCIClient ctrlruntimeclient.Cl
 					errs = append(errs, fmt.Errorf("could not get image stream %s in namespace %s on cluster %s: %w", streamKey.Name, streamKey.Namespace, appCIContextName, err))
 				} else {
 					logrus.WithField("cluster", cluster).WithField("streamKey", streamKey).Info("deleting image stream on build farm")
+					report.addImageStream(cluster, streamKey)
 					if dryRun {
 						continue
 					}
@@ -356,6 +371,7 @@ func deleteTagsOnBuildFarm(ctx context.Context, appCIClient ctrlruntimeclient.Cl
 				}
 				tagKey := fmt.Sprintf("%s/%s", isTagOnBuildFarm.Namespace, isTagOnBuildFarm.Name)
 				logrus.WithField("cluster", cluster).WithField("tagKey", tagKey).Info("deleting image stream tag on build farm")
+				report.addTag(cluster, streamKey, tagReport{Tag: tag})
 				if dryRun {
 					continue
 				}
@@ -547,6 +563,21 @@ func main() {
 	if err != nil {
 		logrus.WithError(err).Fatal("could not get tags to delete")
 	}
+	opts.allowList.filter(toDelete, imageStreamsWithPromotedTags)
+
+	if opts.reportPath != "" {
+		report := &pruneReport{DryRun: opts.dryRun}
+		for tag := range toDelete {
+			report.addTag(appCIContextName, ctrlruntimeclient.ObjectKey{Namespace: tag.Namespace, Name: tag.Name}, describeTag(ctx, appCIClient, tag))
+		}
+		if err := deleteTagsOnBuildFarm(ctx, appCIClient, clients, imageStreamsWithPromotedTags, true, report); err != nil {
+			logrus.WithError(err).Fatal("could not determine tags to delete on build farm")
+		}
+		if err := report.write(opts.reportPath); err != nil {
+			logrus.WithError(err).Fatal("could not write the report")
+		}
+		logrus.WithField("path", opts.reportPath).Info("Wrote the report")
+	}
 
 	var errs []error
 	for tag := range toDelete {
@@ -568,7 +599,7 @@ func main() {
 		logrus.WithError(utilerrors.NewAggregate(errs)).Fatal("could not delete tags")
 	}
 
-	if err := deleteTagsOnBuildFarm(ctx, appCIClient, clients, imageStreamsWithPromotedTags, opts.dryRun); err != nil {
+	if err := deleteTagsOnBuildFarm(ctx, appCIClient, clients, imageStreamsWithPromotedTags, opts.dryRun, nil); err != nil {
 		logrus.WithError(err).Fatal("could not delete tags on build farm")
 	}
 }
@@ -466,7 +466,7 @@ func TestDeleteTagsOnBuildFarm(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx := context.TODO()
-			actual := deleteTagsOnBuildFarm(ctx, tc.appCIClient, tc.buildClusterClients, tc.imageStreamsWithPromotedTags, tc.dryRun)
+			actual := deleteTagsOnBuildFarm(ctx, tc.appCIClient, tc.buildClusterClients, tc.imageStreamsWithPromotedTags, tc.dryRun, nil)
 			if diff := cmp.Diff(tc.expected, actual, testhelper.EquateErrorMessage); diff != "" {
 				t.Errorf("%s: actual does not match expected, diff: %s", tc.name, diff)
 			}
@@ -478,3 +478,84 @@ func TestDeleteTagsOnBuildFarm(t *testing.T) {
 		})
 	}
 }
+
+func TestAllowListFilter(t *testing.T) {
+	allowed := allowList{regexp.MustCompile(`^ci/protected-.*`), regexp.MustCompile(`^ocp/4\.1$`)}
+	tags := map[api.ImageStreamTagReference]interface{}{
+		{Namespace: "ci", Name: "protected-tools", Tag: "latest"}: nil,
+		{Namespace: "ci", Name: "tools", Tag: "latest"}:           nil,
+		{Namespace: "ocp", Name: "4.1", Tag: "cli"}:               nil,
+		{Namespace: "ocp", Name: "4.10", Tag: "cli"}:              nil,
+	}
+	imageStreams := map[ctrlruntimeclient.ObjectKey]interface{}{
+		{Namespace: "ci", Name: "protected-tools"}: nil,
+		{Namespace: "ocp", Name: "4.10"}:           nil,
+	}
+	allowed.filter(tags, imageStreams)
+	expectedTags := map[api.ImageStreamTagReference]interface{}{
+		{Namespace: "ci", Name: "tools", Tag: "latest"}: nil,
+		{Namespace: "ocp", Name: "4.10", Tag: "cli"}:    nil,
+	}
+	if diff := cmp.Diff(expectedTags, tags); diff != "" {
+		t.Errorf("unexpected tags: %s", diff)
+	}
+	if diff := cmp.Diff(map[ctrlruntimeclient.ObjectKey]interface{}{{Namespace: "ocp", Name: "4.10"}: nil}, imageStreams); diff != "" {
+		t.Errorf("unexpected image streams: %s", diff)
+	}
+}
+
+func TestPruneReport(t *testing.T) {
+	report := &pruneReport{DryRun: true}
+	report.addTag(appCIContextName, ctrlruntimeclient.ObjectKey{Namespace: "ci", Name: "tools"}, tagReport{Tag: "b"})
+	report.addTag("build01", ctrlruntimeclient.ObjectKey{Namespace: "ci", Name: "tools"}, tagReport{Tag: "c"})
+	report.addTag(appCIContextName, ctrlruntimeclient.ObjectKey{Namespace: "ci", Name: "tools"}, tagReport{Tag: "a", LastReferencedBy: "branch-ci-org-repo-master-images"})
+	report.addImageStream("build01", ctrlruntimeclient.ObjectKey{Namespace: "ci", Name: "gone"})
+	var nilReport *pruneReport
+	nilReport.addTag(appCIContextName, ctrlruntimeclient.ObjectKey{Namespace: "ci", Name: "tools"}, tagReport{Tag: "ignored"})
+	report.sort()
+
+	expected := &pruneReport{
+		DryRun: true,
+		ImageStreams: []imageStreamReport{
+			{Cluster: appCIContextName, Namespace: "ci", Name: "tools", Tags: []tagReport{{Tag: "a", LastReferencedBy: "branch-ci-org-repo-master-images"}, {Tag: "b"}}},
+			{Cluster: "build01", Namespace: "ci", Name: "gone", Deleted: true},
+			{Cluster: "build01", Namespace: "ci", Name: "tools", Tags: []tagReport{{Tag: "c"}}},
+		},
+		ByJob: map[string][]string{
+			"branch-ci-org-repo-master-images": {"ci/tools:a"},
+			explanationUnknown:                 {"ci/tools:b"},
+		},
+	}
+	if diff := cmp.Diff(expected, report); diff != "" {
+		t.Errorf("unexpected report: %s", diff)
+	}
+}
+
+func TestPromotingJob(t *testing.T) {
+	testCases := []struct {
+		name     string
+		labels   map[string]string
+		expected string
+	}{
+		{
+			name:     "built from a branch",
+			labels:   map[string]string{"vcs-url": "https://github.com/openshift/ci-tools", "io.openshift.build.commit.ref": "master"},
+			expected: "branch-ci-openshift-ci-tools-master-images",
+		},
+		{
+			name:   "no branch",
+			labels: map[string]string{"vcs-url": "https://github.com/openshift/ci-tools"},
+		},
+		{
+			name:   "unknown source",
+			labels: map[string]string{"vcs-url": "https://example.com/some/deep/path", "io.openshift.build.commit.ref": "master"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, promotingJob(tc.labels)); diff != "" {
+				t.Errorf("unexpected job: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	imagev1 "github.com/openshift/api/image/v1"
+
+	"github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/api/helper"
+	"github.com/openshift/ci-tools/pkg/jobconfig"
+)
+
+// AllowListConfig lists the image streams the governor must never touch
+type AllowListConfig struct {
+	// ImageStreams are regular expressions matched against image streams in
+	// namespace/name format
+	ImageStreams []string `json:"image_streams,omitempty"`
+}
+
+type allowList []*regexp.Regexp
+
+func loadAllowList(path string) (allowList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %s", path)
+	}
+	c := &AllowListConfig{}
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the allow list %s: %w", path, err)
+	}
+	var ret allowList
+	for _, raw := range c.ImageStreams {
+		re, err := regexp.Compile(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile regex from %q: %w", raw, err)
+		}
+		ret = append(ret, re)
+	}
+	return ret, nil
+}
+
+func (a allowList) protects(key ctrlruntimeclient.ObjectKey) bool {
+	for _, re := range a {
+		if re.MatchString(key.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// filter removes all tags and image streams protected by the allow list
+func (a allowList) filter(tags map[api.ImageStreamTagReference]interface{}, imageStreams map[ctrlruntimeclient.ObjectKey]interface{}) {
+	for tag := range tags {
+		if key := (ctrlruntimeclient.ObjectKey{Namespace: tag.Namespace, Name: tag.Name}); a.protects(key) {
+			logrus.WithField("tag", tag.ISTagName()).Info("Tag is protected by the allow list")
+			delete(tags, tag)
+		}
+	}
+	for key := range imageStreams {
+		if a.protects(key) {
+			logrus.WithField("imageStream", key.String()).Info("Image stream is protected by the allow list")
+			delete(imageStreams, key)
+		}
+	}
+}
+
+// pruneReport describes everything the governor deletes, or would delete in
+// dry-run mode
+type pruneReport struct {
+	DryRun       bool                `json:"dry_run"`
+	ImageStreams []imageStreamReport `json:"image_streams"`
+	// ByJob lists the tags on app.ci in namespace/name:tag format grouped by
+	// the job that last promoted them
+	ByJob map[string][]string `json:"by_job,omitempty"`
+}
+
+type imageStreamReport struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Deleted is set when the whole image stream is deleted
+	Deleted bool        `json:"deleted,omitempty"`
+	Tags    []tagReport `json:"tags,omitempty"`
+}
+
+type tagReport struct {
+	Tag         string       `json:"tag"`
+	LastUpdated *metav1.Time `json:"last_updated,omitempty"`
+	// Commit is the source commit the image was built from
+	Commit string `json:"commit,omitempty"`
+	// LastReferencedBy is the job that last promoted the tag, derived from
+	// the source labels on the image
+	LastReferencedBy string `json:"last_referenced_by,omitempty"`
+}
+
+func (r *pruneReport) stream(cluster string, key ctrlruntimeclient.ObjectKey) *imageStreamReport {
+	for i := range r.ImageStreams {
+		if s := &r.ImageStreams[i]; s.Cluster == cluster && s.Namespace == key.Namespace && s.Name == key.Name {
+			return s
+		}
+	}
+	r.ImageStreams = append(r.ImageStreams, imageStreamReport{Cluster: cluster, Namespace: key.Namespace, Name: key.Name})
+	return &r.ImageStreams[len(r.ImageStreams)-1]
+}
+
+func (r *pruneReport) addTag(cluster string, key ctrlruntimeclient.ObjectKey, tag tagReport) {
+	if r == nil {
+		return
+	}
+	s := r.stream(cluster, key)
+	s.Tags = append(s.Tags, tag)
+}
+
+func (r *pruneReport) addImageStream(cluster string, key ctrlruntimeclient.ObjectKey) {
+	if r == nil {
+		return
+	}
+	r.stream(cluster, key).Deleted = true
+}
+
+func (r *pruneReport) sort() {
+	sort.Slice(r.ImageStreams, func(i, j int) bool {
+		a, b := r.ImageStreams[i], r.ImageStreams[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	r.ByJob = nil
+	for _, s := range r.ImageStreams {
+		sort.Slice(s.Tags, func(i, j int) bool { return s.Tags[i].Tag < s.Tags[j].Tag })
+		if s.Cluster != appCIContextName {
+			continue
+		}
+		for _, tag := range s.Tags {
+			job := tag.LastReferencedBy
+			if job == "" {
+				job = explanationUnknown
+			}
+			if r.ByJob == nil {
+				r.ByJob = map[string][]string{}
+			}
+			r.ByJob[job] = append(r.ByJob[job], fmt.Sprintf("%s/%s:%s", s.Namespace, s.Name, tag.Tag))
+		}
+	}
+}
+
+func (r *pruneReport) write(path string) error {
+	r.sort()
+	raw, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the report: %w", err)
+	}
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		return fmt.Errorf("failed to write the report to %s: %w", path, err)
+	}
+	return nil
+}
+
+// describeTag collects what is known about the origin of the image behind the
+// tag. Failures are logged and produce a report with only the tag name, as the
+// report must not block pruning.
+func describeTag(ctx context.Context, client ctrlruntimeclient.Client, tag api.ImageStreamTagReference) tagReport {
+	ret := tagReport{Tag: tag.Tag}
+	logger := logrus.WithField("tag", tag.ISTagName())
+	isTag := &imagev1.ImageStreamTag{}
+	if err := client.Get(ctx, ctrlruntimeclient.ObjectKey{Namespace: tag.Namespace, Name: fmt.Sprintf("%s:%s", tag.Name, tag.Tag)}, isTag); err != nil {
+		logger.WithError(err).Warn("Could not get image stream tag for the report")
+		return ret
+	}
+	if !isTag.Image.CreationTimestamp.IsZero() {
+		ret.LastUpdated = &isTag.Image.CreationTimestamp
+	}
+	labels, err := helper.LabelsOnISTagImage(ctx, client, isTag, api.ReleaseArchitectureAMD64)
+	if err != nil {
+		logger.WithError(err).Debug("Could not get labels of the image for the report")
+		return ret
+	}
+	ret.Commit = labels["io.openshift.build.commit.id"]
+	ret.LastReferencedBy = promotingJob(labels)
+	return ret
+}
+
+// promotingJob reconstructs the name of the postsubmit that built the image from
+// the source labels ci-operator sets on images built from a branch
+func promotingJob(labels map[string]string) string {
+	branch := labels["io.openshift.build.commit.ref"]
+	orgRepo := strings.Split(strings.TrimPrefix(labels["vcs-url"], "https://github.com/"), "/")
+	if branch == "" || len(orgRepo) != 2 || orgRepo[0] == "" || orgRepo[1] == "" {
+		return ""
+	}
+	metadata := api.Metadata{Org: orgRepo[0], Repo: orgRepo[1], Branch: branch}
+	return metadata.JobName(jobconfig.PostsubmitPrefix, "images")
+}
@@ -59,6 +59,10 @@ type options struct {
 	disableClusters flagutil.Strings
 	defaultCluster  string
 
+	rebalance          bool
+	rebalanceTolerance float64
+	clusterDisplayURL  string
+
 	bumper.GitAuthorOptions
 	dispatcher.PrometheusOptions
 	prcreation.PRCreationOptions
@@ -82,6 +86,10 @@ func gatherOptions() options {
 	fs.Var(&o.disableClusters, "disable-cluster", "Disable this cluster. Does nothing if the cluster is disabled. Can be passed multiple times and must be disjoint with all --enable-cluster values.")
 	fs.StringVar(&o.defaultCluster, "default-cluster", "", "If passed, changes the default cluster to the specified value.")
 
+	fs.BoolVar(&o.rebalance, "rebalance", false, "Keep the current assignments of the Prow job config files and only move as many of them as needed to balance the load relative to the capacity of the clusters.")
+	fs.Float64Var(&o.rebalanceTolerance, "rebalance-tolerance", 0.1, "The ratio by which the load of a cluster relative to its capacity may exceed the average before jobs are moved off it. Only used with --rebalance.")
+	fs.StringVar(&o.clusterDisplayURL, "cluster-display-url", "https://cluster-display.ci.openshift.org", "The URL of cluster-display to get the capacity of the clusters from. Only used with --rebalance.")
+
 	o.GitAuthorOptions.AddFlags(fs)
 	o.PrometheusOptions.AddFlags(fs)
 	o.PRCreationOptions.AddFlags(fs)
@@ -118,6 +126,15 @@ func (o *options) validate() error {
 		return fmt.Errorf("--default-cluster value cannot be also be in --disable-cluster")
 	}
 
+	if o.rebalance {
+		if o.rebalanceTolerance < 0 {
+			return fmt.Errorf("--rebalance-tolerance must not be negative")
+		}
+		if o.clusterDisplayURL == "" {
+			return fmt.Errorf("--cluster-display-url is mandatory with --rebalance")
+		}
+	}
+
 	if o.createPR {
 		if o.githubLogin == "" {
 			return fmt.Errorf("--github-login cannot be empty string")
@@ -505,9 +522,21 @@ func main() {
 	}
 	addEnabledClusters(config, enabled, getClusterProvider)
 
-	logrus.Info("Dispatching ...")
-	if err := dispatchJobs(context.TODO(), o.prowJobConfigDir, o.maxConcurrency, config, jobVolumes); err != nil {
-		logrus.WithError(err).Fatal("Failed to dispatch")
+	if o.rebalance {
+		capacities, err := getClusterCapacities(o.clusterDisplayURL)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to get the capacity of the clusters")
+		}
+		logrus.WithField("capacities", capacities).Debug("loaded cluster capacities")
+		logrus.Info("Rebalancing ...")
+		if err := rebalanceJobs(o.prowJobConfigDir, config, jobVolumes, capacities, o.rebalanceTolerance); err != nil {
+			logrus.WithError(err).Fatal("Failed to rebalance")
+		}
+	} else {
+		logrus.Info("Dispatching ...")
+		if err := dispatchJobs(context.TODO(), o.prowJobConfigDir, o.maxConcurrency, config, jobVolumes); err != nil {
+			logrus.WithError(err).Fatal("Failed to dispatch")
+		}
 	}
 	if err := dispatcher.SaveConfig(config, o.configPath); err != nil {
 		logrus.WithError(err).Fatalf("Failed to save config file to %s", o.configPath)
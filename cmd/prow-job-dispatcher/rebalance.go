@@ -0,0 +1,310 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	prowconfig "k8s.io/test-infra/prow/config"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/dispatcher"
+	"github.com/openshift/ci-tools/pkg/util/gzip"
+)
+
+// clusterCapacity is the capacity of a cluster in the build farm as reported by cluster-display
+type clusterCapacity struct {
+	Cluster     string              `json:"cluster"`
+	Healthy     bool                `json:"healthy"`
+	Allocatable corev1.ResourceList `json:"allocatable,omitempty"`
+}
+
+// getClusterCapacities returns the allocatable CPU cores of the healthy clusters reported by cluster-display.
+// Unhealthy clusters are returned with zero capacity, so that no jobs are moved to them. The jobs already
+// assigned to them are kept, to avoid moving jobs back and forth when a cluster is briefly unhealthy.
+func getClusterCapacities(clusterDisplayURL string) (map[string]float64, error) {
+	resp, err := http.Get(strings.TrimSuffix(clusterDisplayURL, "/") + "/api/v1/capacity")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the capacity of the clusters: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get the capacity of the clusters: unexpected status code %d", resp.StatusCode)
+	}
+	var page struct {
+		Data []clusterCapacity `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode the capacity of the clusters: %w", err)
+	}
+	capacities := map[string]float64{}
+	for _, c := range page.Data {
+		if !c.Healthy {
+			capacities[c.Cluster] = 0
+			continue
+		}
+		cpu := c.Allocatable[corev1.ResourceCPU]
+		capacities[c.Cluster] = float64(cpu.MilliValue()) / 1000
+	}
+	return capacities, nil
+}
+
+// fileLoad is the volume of the jobs in a Prow job config file that can be run on any cluster of the build farm
+type fileLoad struct {
+	filename string
+	// cloud is the cloud provider the jobs must run on, empty if they can run on any
+	cloud  string
+	volume float64
+}
+
+// loadFileVolumes determines the relocatable volume of each Prow job config file and the volume of jobs
+// that are bound to a specific cluster of the build farm
+func loadFileVolumes(prowJobConfigDir string, config *dispatcher.Config, jobVolumes map[string]float64) ([]fileLoad, map[string]float64, error) {
+	var files []fileLoad
+	fixed := map[string]float64{}
+	err := filepath.WalkDir(prowJobConfigDir, func(path string, info fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to walk file/directory '%s': %w", path, err)
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".yaml") || config.MatchingPathRegEx(path) {
+			return nil
+		}
+		data, err := gzip.ReadFileMaybeGZIP(path)
+		if err != nil {
+			return fmt.Errorf("failed to read file %q: %w", path, err)
+		}
+		jc := &prowconfig.JobConfig{}
+		if err := yaml.Unmarshal(data, jc); err != nil {
+			return fmt.Errorf("failed to unmarshal file %q: %w", path, err)
+		}
+
+		load := fileLoad{filename: info.Name()}
+		if clouds := getCloudProvidersForE2ETests(jc); clouds.Len() == 1 {
+			cloud, _ := clouds.PopAny()
+			if _, ok := config.BuildFarm[api.Cloud(cloud)]; ok {
+				load.cloud = cloud
+			}
+		}
+		for _, job := range jobBases(jc) {
+			cluster, canBeRelocated, err := config.DetermineClusterForJob(job, path)
+			if err != nil {
+				return fmt.Errorf("failed to determine cluster for the job %s in path %q: %w", job.Name, path, err)
+			}
+			if canBeRelocated {
+				load.volume += jobVolumes[job.Name]
+			} else if config.IsInBuildFarm(cluster) != "" {
+				fixed[string(cluster)] += jobVolumes[job.Name]
+			}
+		}
+		files = append(files, load)
+		return nil
+	})
+	return files, fixed, err
+}
+
+func jobBases(jc *prowconfig.JobConfig) []prowconfig.JobBase {
+	var bases []prowconfig.JobBase
+	for _, jobs := range jc.PresubmitsStatic {
+		for _, job := range jobs {
+			bases = append(bases, job.JobBase)
+		}
+	}
+	for _, jobs := range jc.PostsubmitsStatic {
+		for _, job := range jobs {
+			bases = append(bases, job.JobBase)
+		}
+	}
+	for _, job := range jc.Periodics {
+		bases = append(bases, job.JobBase)
+	}
+	return bases
+}
+
+// buildFarmLoad tracks the load of the clusters in the build farm while rebalancing
+type buildFarmLoad struct {
+	clusterCloud map[string]string
+	capacity     map[string]float64
+	load         map[string]float64
+}
+
+// relative returns the load of the cluster relative to its capacity
+func (b *buildFarmLoad) relative(cluster string, additional float64) float64 {
+	return (b.load[cluster] + additional) / b.capacity[cluster]
+}
+
+// target returns the cluster with the lowest relative load after adding the file, among the
+// clusters that can run the file other than the excluded one
+func (b *buildFarmLoad) target(file fileLoad, excluded string) string {
+	var best string
+	for _, cluster := range sets.List(sets.KeySet(b.clusterCloud)) {
+		if cluster == excluded || b.capacity[cluster] == 0 {
+			continue
+		}
+		if file.cloud != "" && b.clusterCloud[cluster] != file.cloud {
+			continue
+		}
+		if best == "" || b.relative(cluster, file.volume) < b.relative(best, file.volume) {
+			best = cluster
+		}
+	}
+	return best
+}
+
+// rebalance assigns the Prow job config files to the clusters of the build farm, keeping the
+// previous assignments where possible. Files that were not assigned or whose cluster left the
+// build farm are assigned to the cluster with the lowest load relative to its capacity. Then,
+// while the relative load of a cluster exceeds the average by more than the tolerance, files are
+// moved off it one at a time, so that the resulting change is as small as possible.
+func rebalance(files []fileLoad, fixed map[string]float64, previous map[string]string, clusterCloud map[string]string, capacities map[string]float64, tolerance float64) map[string]string {
+	b := &buildFarmLoad{clusterCloud: clusterCloud, capacity: map[string]float64{}, load: map[string]float64{}}
+	// clusters unknown to cluster-display are assumed to be of an average size
+	averageCapacity, known := float64(0), 0
+	for cluster := range clusterCloud {
+		if c, ok := capacities[cluster]; ok && c > 0 {
+			averageCapacity += c
+			known++
+		}
+	}
+	if known > 0 {
+		averageCapacity /= float64(known)
+	} else {
+		averageCapacity = 1
+	}
+	for cluster := range clusterCloud {
+		c, ok := capacities[cluster]
+		if !ok {
+			c = averageCapacity
+		}
+		b.capacity[cluster] = c
+		b.load[cluster] = fixed[cluster]
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].volume != files[j].volume {
+			return files[i].volume > files[j].volume
+		}
+		return files[i].filename < files[j].filename
+	})
+
+	assignments := map[string]string{}
+	var unassigned []fileLoad
+	for _, file := range files {
+		cluster, ok := previous[file.filename]
+		if _, inBuildFarm := clusterCloud[cluster]; ok && inBuildFarm && (file.cloud == "" || clusterCloud[cluster] == file.cloud) {
+			assignments[file.filename] = cluster
+			b.load[cluster] += file.volume
+			continue
+		}
+		unassigned = append(unassigned, file)
+	}
+	for _, file := range unassigned {
+		cluster := b.target(file, "")
+		if cluster == "" {
+			logrus.WithField("file", file.filename).Warn("Found no cluster in the build farm to run the jobs")
+			continue
+		}
+		assignments[file.filename] = cluster
+		b.load[cluster] += file.volume
+	}
+
+	// the jobs kept on unhealthy clusters are not considered, they cannot be moved and no jobs are
+	// moved to these clusters
+	average := func() float64 {
+		var load, capacity float64
+		for cluster := range clusterCloud {
+			if b.capacity[cluster] == 0 {
+				continue
+			}
+			load += b.load[cluster]
+			capacity += b.capacity[cluster]
+		}
+		if capacity == 0 {
+			return 0
+		}
+		return load / capacity
+	}()
+
+	for moves := 0; moves < len(files); moves++ {
+		var source string
+		for _, cluster := range sets.List(sets.KeySet(clusterCloud)) {
+			if b.capacity[cluster] == 0 {
+				continue
+			}
+			if source == "" || b.relative(cluster, 0) > b.relative(source, 0) {
+				source = cluster
+			}
+		}
+		if source == "" || b.relative(source, 0) <= average*(1+tolerance) {
+			break
+		}
+		moved := false
+		for _, file := range files {
+			if assignments[file.filename] != source || file.volume == 0 {
+				continue
+			}
+			target := b.target(file, source)
+			// only move the file if the load of the target stays below the load of the source
+			if target == "" || b.relative(target, file.volume) >= b.relative(source, 0) {
+				continue
+			}
+			logrus.WithFields(logrus.Fields{"file": file.filename, "from": source, "to": target, "volume": file.volume}).Info("Moving the jobs to another cluster")
+			assignments[file.filename] = target
+			b.load[source] -= file.volume
+			b.load[target] += file.volume
+			moved = true
+			break
+		}
+		if !moved {
+			break
+		}
+	}
+	return assignments
+}
+
+// rebalanceJobs updates the assignments of the Prow job config files to the clusters in the build farm
+// with the minimal change needed to balance the load relative to the capacity of the clusters
+func rebalanceJobs(prowJobConfigDir string, config *dispatcher.Config, jobVolumes map[string]float64, capacities map[string]float64, tolerance float64) error {
+	files, fixed, err := loadFileVolumes(prowJobConfigDir, config, jobVolumes)
+	if err != nil {
+		return fmt.Errorf("failed to load the volumes of the Prow jobs: %w", err)
+	}
+	previous := map[string]string{}
+	clusterCloud := map[string]string{}
+	for cloudProvider, clusters := range config.BuildFarm {
+		for cluster, buildFarmConfig := range clusters {
+			clusterCloud[string(cluster)] = string(cloudProvider)
+			if buildFarmConfig == nil {
+				continue
+			}
+			for _, filename := range buildFarmConfig.FilenamesRaw {
+				previous[filename] = string(cluster)
+			}
+		}
+	}
+	if len(clusterCloud) == 0 {
+		return nil
+	}
+
+	assignments := rebalance(files, fixed, previous, clusterCloud, capacities, tolerance)
+	results := map[string][]string{}
+	for filename, cluster := range assignments {
+		results[cluster] = append(results[cluster], filename)
+	}
+	for cloudProvider, clusters := range config.BuildFarm {
+		for cluster := range clusters {
+			filenames := results[string(cluster)]
+			sort.Strings(filenames)
+			config.BuildFarm[cloudProvider][cluster] = &dispatcher.BuildFarmConfig{FilenamesRaw: filenames}
+		}
+	}
+	return nil
+}
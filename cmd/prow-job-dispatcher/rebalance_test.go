@@ -0,0 +1,144 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRebalance(t *testing.T) {
+	testCases := []struct {
+		name         string
+		files        []fileLoad
+		fixed        map[string]float64
+		previous     map[string]string
+		clusterCloud map[string]string
+		capacities   map[string]float64
+		expected     map[string]string
+	}{
+		{
+			name: "balanced assignments are kept",
+			files: []fileLoad{
+				{filename: "a.yaml", volume: 10},
+				{filename: "b.yaml", volume: 10},
+			},
+			previous:     map[string]string{"a.yaml": "build01", "b.yaml": "build02"},
+			clusterCloud: map[string]string{"build01": "aws", "build02": "gcp"},
+			expected:     map[string]string{"a.yaml": "build01", "b.yaml": "build02"},
+		},
+		{
+			name: "new files are assigned to the least loaded cluster",
+			files: []fileLoad{
+				{filename: "a.yaml", volume: 10},
+				{filename: "new.yaml", volume: 5},
+			},
+			previous:     map[string]string{"a.yaml": "build01"},
+			clusterCloud: map[string]string{"build01": "aws", "build02": "gcp"},
+			expected:     map[string]string{"a.yaml": "build01", "new.yaml": "build02"},
+		},
+		{
+			name: "only the files needed to balance an overloaded cluster are moved",
+			files: []fileLoad{
+				{filename: "a.yaml", volume: 10},
+				{filename: "b.yaml", volume: 5},
+				{filename: "c.yaml", volume: 5},
+				{filename: "d.yaml", volume: 2},
+			},
+			previous:     map[string]string{"a.yaml": "build01", "b.yaml": "build01", "c.yaml": "build01", "d.yaml": "build02"},
+			clusterCloud: map[string]string{"build01": "aws", "build02": "gcp"},
+			capacities:   map[string]float64{"build01": 10, "build02": 10},
+			expected:     map[string]string{"a.yaml": "build02", "b.yaml": "build01", "c.yaml": "build01", "d.yaml": "build02"},
+		},
+		{
+			name: "the capacity of the clusters is taken into account",
+			files: []fileLoad{
+				{filename: "a.yaml", volume: 10},
+				{filename: "b.yaml", volume: 10},
+				{filename: "c.yaml", volume: 10},
+				{filename: "d.yaml", volume: 10},
+			},
+			clusterCloud: map[string]string{"build01": "aws", "build02": "gcp"},
+			capacities:   map[string]float64{"build01": 30, "build02": 10},
+			expected:     map[string]string{"a.yaml": "build01", "b.yaml": "build01", "c.yaml": "build01", "d.yaml": "build02"},
+		},
+		{
+			name: "files are only moved to clusters on the cloud provider of their e2e tests",
+			files: []fileLoad{
+				{filename: "aws-1.yaml", volume: 10, cloud: "aws"},
+				{filename: "aws-2.yaml", volume: 10, cloud: "aws"},
+				{filename: "gone.yaml", volume: 10, cloud: "aws"},
+			},
+			previous:     map[string]string{"aws-1.yaml": "build01", "aws-2.yaml": "build01", "gone.yaml": "build05"},
+			clusterCloud: map[string]string{"build01": "aws", "build02": "gcp"},
+			expected:     map[string]string{"aws-1.yaml": "build01", "aws-2.yaml": "build01", "gone.yaml": "build01"},
+		},
+		{
+			name: "jobs bound to a cluster count towards its load",
+			files: []fileLoad{
+				{filename: "a.yaml", volume: 10},
+				{filename: "b.yaml", volume: 10},
+			},
+			fixed:        map[string]float64{"build01": 30},
+			previous:     map[string]string{"a.yaml": "build01", "b.yaml": "build02"},
+			clusterCloud: map[string]string{"build01": "aws", "build02": "gcp"},
+			expected:     map[string]string{"a.yaml": "build02", "b.yaml": "build02"},
+		},
+		{
+			name: "no jobs are moved to an unhealthy cluster, but its jobs are kept",
+			files: []fileLoad{
+				{filename: "a.yaml", volume: 10},
+				{filename: "b.yaml", volume: 10},
+				{filename: "new.yaml", volume: 10},
+			},
+			previous:     map[string]string{"a.yaml": "build01", "b.yaml": "build02"},
+			clusterCloud: map[string]string{"build01": "aws", "build02": "gcp"},
+			capacities:   map[string]float64{"build01": 0, "build02": 10},
+			expected:     map[string]string{"a.yaml": "build01", "b.yaml": "build02", "new.yaml": "build02"},
+		},
+		{
+			name: "the load of an unhealthy cluster does not count towards the average",
+			files: []fileLoad{
+				{filename: "a.yaml", volume: 8},
+				{filename: "b.yaml", volume: 4},
+				{filename: "c.yaml", volume: 4},
+				{filename: "unhealthy.yaml", volume: 100},
+			},
+			previous:     map[string]string{"a.yaml": "build02", "b.yaml": "build02", "c.yaml": "build03", "unhealthy.yaml": "build01"},
+			clusterCloud: map[string]string{"build01": "aws", "build02": "gcp", "build03": "gcp"},
+			capacities:   map[string]float64{"build01": 0, "build02": 10, "build03": 10},
+			expected:     map[string]string{"a.yaml": "build02", "b.yaml": "build03", "c.yaml": "build03", "unhealthy.yaml": "build01"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := rebalance(tc.files, tc.fixed, tc.previous, tc.clusterCloud, tc.capacities, 0.1)
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("assignments differ from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestGetClusterCapacities(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/capacity" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[
+{"cluster":"build01","healthy":true,"allocatable":{"cpu":"1500m","memory":"10Gi"}},
+{"cluster":"build02","healthy":false,"allocatable":{"cpu":"64"}}
+]}`))
+	}))
+	defer server.Close()
+
+	actual, err := getClusterCapacities(server.URL + "/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(map[string]float64{"build01": 1.5, "build02": 0}, actual); diff != "" {
+		t.Errorf("capacities differ from expected: %s", diff)
+	}
+}
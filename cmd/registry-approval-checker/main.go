@@ -0,0 +1,166 @@
+// registry-approval-checker verifies that every step registry component touched
+// by a pull request was approved by one of the component's owners.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/test-infra/prow/flagutil"
+	"k8s.io/test-infra/prow/logrusutil"
+	"k8s.io/test-infra/prow/pod-utils/downwardapi"
+	"k8s.io/test-infra/prow/repoowners"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/ci-tools/pkg/config"
+	"github.com/openshift/ci-tools/pkg/registry/ownership"
+)
+
+const ownersAliasesFile = "OWNERS_ALIASES"
+
+type options struct {
+	releaseRepoPath string
+	flagutil.GitHubOptions
+}
+
+func gatherOptions() options {
+	o := options{}
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	fs.StringVar(&o.releaseRepoPath, "candidate-path", "", "Path to a openshift/release working copy with a revision to be tested")
+	o.GitHubOptions.AddFlags(fs)
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		logrus.WithError(err).Fatal("could not parse input")
+	}
+	return o
+}
+
+func (o *options) validate() error {
+	if o.releaseRepoPath == "" {
+		return errors.New("--candidate-path is required")
+	}
+	return o.GitHubOptions.Validate(true)
+}
+
+// fileAtRevision reads a file, relative to the repository root, as it was at
+// the given revision. A file that did not exist at the revision yields no
+// content and no error; any other failure to read it is an error.
+func fileAtRevision(repoPath, revision, path string) ([]byte, error) {
+	listing, err := git(repoPath, "ls-tree", "--name-only", revision, "--", path)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(listing)) == 0 {
+		return nil, nil
+	}
+	return git(repoPath, "show", fmt.Sprintf("%s:%s", revision, path))
+}
+
+func git(repoPath string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", repoPath}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w, output:\n%s", cmd.Args, err, stderr.String())
+	}
+	return out, nil
+}
+
+// ownersAtRevision reads OWNERS files only as they were at the base revision,
+// so that a pull request cannot approve itself by adding or editing them.
+// Directories without an OWNERS file at the base revision, including the ones
+// added by the pull request, inherit the approvers of their closest parent
+// directory that had one.
+func ownersAtRevision(repoPath, revision string) ownership.OwnersGetter {
+	return func(dir string) (*repoowners.SimpleConfig, error) {
+		path := filepath.Join(config.RegistryPath, dir, "OWNERS")
+		raw, err := fileAtRevision(repoPath, revision, path)
+		if err != nil || raw == nil {
+			return nil, err
+		}
+		var owners repoowners.SimpleConfig
+		if err := yaml.Unmarshal(raw, &owners); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", path, err)
+		}
+		return &owners, nil
+	}
+}
+
+// aliasesAtRevision reads the OWNERS_ALIASES file at the repository root as it
+// was at the base revision
+func aliasesAtRevision(repoPath, revision string) (repoowners.RepoAliases, error) {
+	raw, err := fileAtRevision(repoPath, revision, ownersAliasesFile)
+	if err != nil || raw == nil {
+		return nil, err
+	}
+	aliases, err := repoowners.ParseAliasesConfig(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", ownersAliasesFile, err)
+	}
+	return aliases, nil
+}
+
+func main() {
+	logrusutil.ComponentInit()
+	logger := logrus.WithField("component", "registry-approval-checker")
+
+	o := gatherOptions()
+	if err := o.validate(); err != nil {
+		logger.Fatalf("validation error: %v", err)
+	}
+
+	jobSpec, err := downwardapi.ResolveSpecFromEnv()
+	if err != nil {
+		logger.WithError(err).Fatal("error resolving JobSpec")
+	}
+	if jobSpec.Refs == nil || len(jobSpec.Refs.Pulls) != 1 {
+		logger.Fatal("this tool must run in a presubmit job for a single pull request")
+	}
+	refs, pull := jobSpec.Refs, jobSpec.Refs.Pulls[0]
+
+	changed, err := config.GetChangedRegistryFiles(o.releaseRepoPath, refs.BaseSHA)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to determine changed registry files")
+	}
+	if len(changed) == 0 {
+		logger.Info("No registry components were changed.")
+		return
+	}
+	aliases, err := aliasesAtRevision(o.releaseRepoPath, refs.BaseSHA)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to load OWNERS aliases")
+	}
+	components, err := ownership.TouchedComponents(changed, ownersAtRevision(o.releaseRepoPath, refs.BaseSHA), aliases)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to determine owners of changed registry components")
+	}
+
+	client, err := o.GitHubOptions.GitHubClient(true)
+	if err != nil {
+		logger.WithError(err).Fatal("error creating client")
+	}
+	comments, err := client.ListIssueComments(refs.Org, refs.Repo, pull.Number)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to list comments")
+	}
+	reviews, err := client.ListReviews(refs.Org, refs.Repo, pull.Number)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to list reviews")
+	}
+	approvals := ownership.Approvals(pull.Author, comments, reviews)
+	logger.WithField("approvers", strings.Join(approvals.UnsortedList(), ",")).Info("Determined approvals.")
+
+	if unapproved := ownership.Unapproved(components, approvals); len(unapproved) > 0 {
+		fmt.Printf("The following registry components were changed without approval from their owners:\n%s", ownership.Format(unapproved))
+		os.Exit(1)
+	}
+	logger.Infof("All %d touched registry components are approved by their owners.", len(components))
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/ci-tools/pkg/config"
+	"github.com/openshift/ci-tools/pkg/registry/ownership"
+)
+
+func TestOwnersAtRevision(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%v failed: %v: %s", cmd.Args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(path, content string) {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "--quiet")
+	write(ownersAliasesFile, "aliases:\n  ipi-approvers:\n  - IPI-Lead\n")
+	write(filepath.Join(config.RegistryPath, "OWNERS"), "approvers:\n- root\n")
+	write(filepath.Join(config.RegistryPath, "ipi", "OWNERS"), "approvers:\n- ipi-approvers\n")
+	write(filepath.Join(config.RegistryPath, "ipi", "ipi-ref.yaml"), "ref: {}\n")
+	write(filepath.Join(config.RegistryPath, "gone", "OWNERS"), "approvers:\n- gone-owner\n")
+	write(filepath.Join(config.RegistryPath, "gone", "gone-ref.yaml"), "ref: {}\n")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "base")
+	base := git("rev-parse", "HEAD")
+
+	// the pull request tries to approve itself by editing and adding OWNERS files
+	write(ownersAliasesFile, "aliases:\n  ipi-approvers:\n  - attacker\n")
+	write(filepath.Join(config.RegistryPath, "ipi", "OWNERS"), "approvers:\n- attacker\n")
+	write(filepath.Join(config.RegistryPath, "new", "nested", "OWNERS"), "approvers:\n- attacker\n")
+	write(filepath.Join(config.RegistryPath, "new", "nested", "new-ref.yaml"), "ref: {}\n")
+	git("rm", "--quiet", "-r", filepath.Join(config.RegistryPath, "gone"))
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "pull request")
+
+	changed, err := config.GetChangedRegistryFiles(dir, base)
+	if err != nil {
+		t.Fatalf("failed to get changed files: %v", err)
+	}
+	aliases, err := aliasesAtRevision(dir, base)
+	if err != nil {
+		t.Fatalf("failed to get aliases: %v", err)
+	}
+	components, err := ownership.TouchedComponents(changed, ownersAtRevision(dir, base), aliases)
+	if err != nil {
+		t.Fatalf("failed to get touched components: %v", err)
+	}
+	expected := []ownership.Component{
+		{Path: "gone", Files: []string{"gone/OWNERS", "gone/gone-ref.yaml"}, Approvers: []string{"gone-owner", "root"}},
+		{Path: "ipi", Files: []string{"ipi/OWNERS"}, Approvers: []string{"ipi-lead", "root"}},
+		{Path: "new/nested", Files: []string{"new/nested/OWNERS", "new/nested/new-ref.yaml"}, Approvers: []string{"root"}},
+	}
+	if diff := cmp.Diff(expected, components); diff != "" {
+		t.Errorf("unexpected components: %s", diff)
+	}
+	if unapproved := ownership.Unapproved(components, sets.New[string]("attacker")); len(unapproved) != len(components) {
+		t.Errorf("expected the pull request not to be able to approve itself, unapproved: %v", unapproved)
+	}
+
+	if _, err := ownersAtRevision(dir, "0000000000000000000000000000000000000000")("ipi"); err == nil {
+		t.Error("expected an error for an unknown revision")
+	}
+}
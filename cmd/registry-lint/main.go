@@ -0,0 +1,153 @@
+// registry-lint enforces structural rules on the step registry, such as unused
+// components, missing documentation or OWNERS and unused parameters.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/test-infra/prow/flagutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/ci-tools/pkg/config"
+	"github.com/openshift/ci-tools/pkg/load"
+	"github.com/openshift/ci-tools/pkg/registry/lint"
+)
+
+type options struct {
+	registry       string
+	configDir      string
+	baseline       string
+	updateBaseline bool
+	maxChainDepth  int
+	severities     flagutil.Strings
+	flatRegistry   bool
+}
+
+func gatherOptions() (options, error) {
+	o := options{}
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	fs.StringVar(&o.registry, "registry", "", "Path to the step registry directory.")
+	fs.StringVar(&o.configDir, "config-dir", "", "Path to the ci-operator configuration directory, used to find components used by tests.")
+	fs.StringVar(&o.baseline, "baseline", "", "Path to a file listing grandfathered violations.")
+	fs.BoolVar(&o.updateBaseline, "update-baseline", false, "Write all current violations into the baseline file instead of failing.")
+	fs.IntVar(&o.maxChainDepth, "max-chain-depth", 3, "Maximum allowed nesting of chains. Set to 0 to disable the check.")
+	fs.Var(&o.severities, "severity", "Override the severity of a rule, in the form rule=error|warning. Can be passed multiple times.")
+	fs.BoolVar(&o.flatRegistry, "flat-registry", false, "Disable directory structure based registry validation")
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		return options{}, fmt.Errorf("could not parse input: %w", err)
+	}
+	return o, nil
+}
+
+func (o *options) validate() error {
+	if o.registry == "" {
+		return errors.New("--registry is required")
+	}
+	if o.configDir == "" {
+		return errors.New("--config-dir is required")
+	}
+	if o.updateBaseline && o.baseline == "" {
+		return errors.New("--update-baseline requires --baseline")
+	}
+	if o.maxChainDepth < 0 {
+		return errors.New("--max-chain-depth must not be negative")
+	}
+	_, err := parseSeverities(o.severities.Strings())
+	return err
+}
+
+func parseSeverities(raw []string) (map[lint.Rule]lint.Severity, error) {
+	ret := map[lint.Rule]lint.Severity{}
+	for _, item := range raw {
+		rule, severity, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("--severity %q is not in the form rule=severity", item)
+		}
+		if _, known := lint.DefaultSeverities[lint.Rule(rule)]; !known {
+			return nil, fmt.Errorf("--severity: unknown rule %q", rule)
+		}
+		switch s := lint.Severity(severity); s {
+		case lint.SeverityError, lint.SeverityWarning:
+			ret[lint.Rule(rule)] = s
+		default:
+			return nil, fmt.Errorf("--severity: unknown severity %q, must be one of %s or %s", severity, lint.SeverityError, lint.SeverityWarning)
+		}
+	}
+	return ret, nil
+}
+
+func main() {
+	o, err := gatherOptions()
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to gather options")
+	}
+	if err := o.validate(); err != nil {
+		logrus.WithError(err).Fatal("invalid options")
+	}
+	severities, _ := parseSeverities(o.severities.Strings())
+
+	flags := load.RegistryMetadata | load.RegistryDocumentation
+	if o.flatRegistry {
+		flags |= load.RegistryFlat
+	}
+	references, chains, workflows, documentation, metadata, _, err := load.Registry(o.registry, flags)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to load registry")
+	}
+	configs, err := config.LoadByOrgRepo(o.configDir)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to load ci-operator configuration")
+	}
+
+	violations := lint.Lint(lint.Registry{
+		References:    references,
+		Chains:        chains,
+		Workflows:     workflows,
+		Documentation: documentation,
+		Metadata:      metadata,
+	}, lint.Options{
+		MaxChainDepth: o.maxChainDepth,
+		Severities:    severities,
+		Configs:       configs,
+	})
+
+	if o.updateBaseline {
+		raw, err := yaml.Marshal(lint.NewBaseline(violations))
+		if err != nil {
+			logrus.WithError(err).Fatal("failed to marshal baseline")
+		}
+		if err := os.WriteFile(o.baseline, raw, 0644); err != nil {
+			logrus.WithError(err).Fatal("failed to write baseline")
+		}
+		logrus.Infof("Wrote %d violations to the baseline", len(violations))
+		return
+	}
+
+	var baseline lint.Baseline
+	if o.baseline != "" {
+		if baseline, err = lint.LoadBaseline(o.baseline); err != nil {
+			logrus.WithError(err).Fatal("failed to load baseline")
+		}
+	}
+	remaining, grandfathered, stale := baseline.Filter(violations)
+	for _, v := range stale {
+		logrus.Infof("Baseline entry for %s %s (%s) no longer matches anything and can be removed", v.Type, v.Name, v.Rule)
+	}
+	var failed int
+	for _, v := range remaining {
+		fmt.Println(v.String())
+		if v.Severity == lint.SeverityError {
+			failed++
+		}
+	}
+	logrus.Infof("Found %d violations (%d errors), %d grandfathered by the baseline", len(remaining), failed, len(grandfathered))
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/openshift/ci-tools/pkg/api"
 	"github.com/openshift/ci-tools/pkg/dispatcher"
 	"github.com/openshift/ci-tools/pkg/jobconfig"
+	"github.com/openshift/ci-tools/pkg/jobpolicy"
 	"github.com/openshift/ci-tools/pkg/util"
 	"github.com/openshift/ci-tools/pkg/util/gzip"
 )
@@ -28,6 +29,7 @@ const (
 type options struct {
 	prowJobConfigDir string
 	configPath       string
+	policyPath       string
 
 	help bool
 }
@@ -37,17 +39,20 @@ func bindOptions(flag *flag.FlagSet) *options {
 
 	flag.StringVar(&opt.prowJobConfigDir, "prow-jobs-dir", "", "Path to a root of directory structure with Prow job config files (ci-operator/jobs in openshift/release)")
 	flag.StringVar(&opt.configPath, "config-path", "", "Path to the config file (core-services/sanitize-prow-jobs/_config.yaml in openshift/release)")
+	flag.StringVar(&opt.policyPath, "policy-path", "", "Path to the file with the policies all Prow jobs have to comply with (optional)")
 	flag.BoolVar(&opt.help, "h", false, "Show help for ci-operator-prowgen")
 
 	return opt
 }
 
-func determinizeJobs(prowJobConfigDir string, config *dispatcher.Config) error {
+// determinizeJobs determinizes the jobs in the subdirectory of the Prow job config directory.
+// The policies are checked with the paths of the files relative to the Prow job config directory.
+func determinizeJobs(prowJobConfigDir, subDir string, config *dispatcher.Config, policies *jobpolicy.Config) error {
 	ch := make(chan string)
 	errCh := make(chan error)
 	produce := func() error {
 		defer close(ch)
-		return filepath.WalkDir(prowJobConfigDir, func(path string, info fs.DirEntry, err error) error {
+		return filepath.WalkDir(filepath.Join(prowJobConfigDir, subDir), func(path string, info fs.DirEntry, err error) error {
 			if err != nil {
 				errCh <- fmt.Errorf("failed to walk file/directory %q: %w", path, err)
 				return nil
@@ -77,6 +82,17 @@ func determinizeJobs(prowJobConfigDir string, config *dispatcher.Config) error {
 				errCh <- fmt.Errorf("failed to default job config %q: %w", path, err)
 			}
 
+			if policies != nil {
+				relPath, err := filepath.Rel(prowJobConfigDir, path)
+				if err != nil {
+					errCh <- fmt.Errorf("failed to determine the path of %q relative to %q: %w", path, prowJobConfigDir, err)
+					continue
+				}
+				if err := policies.CheckJobConfig(jobConfig, filepath.ToSlash(relPath)); err != nil {
+					errCh <- fmt.Errorf("jobs in %q violate the policies: %w", path, err)
+				}
+			}
+
 			serialized, err := yaml.Marshal(jobConfig)
 			if err != nil {
 				errCh <- fmt.Errorf("failed to marshal file %q: %w", path, err)
@@ -177,13 +193,18 @@ func main() {
 	if err := config.Validate(); err != nil {
 		logrus.WithError(err).Fatal("Failed to validate the config")
 	}
+	var policies *jobpolicy.Config
+	if opt.policyPath != "" {
+		if policies, err = jobpolicy.LoadConfig(opt.policyPath); err != nil {
+			logrus.WithError(err).Fatal("Failed to load the policies")
+		}
+	}
 	args := flagSet.Args()
 	if len(args) == 0 {
 		args = append(args, "")
 	}
 	for _, subDir := range args {
-		subDir = filepath.Join(opt.prowJobConfigDir, subDir)
-		if err := determinizeJobs(subDir, config); err != nil {
+		if err := determinizeJobs(opt.prowJobConfigDir, subDir, config, policies); err != nil {
 			logrus.WithError(err).Fatal("Failed to determinize")
 		}
 	}
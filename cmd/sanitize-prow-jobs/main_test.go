@@ -1,11 +1,15 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"k8s.io/test-infra/prow/config"
 
 	"github.com/openshift/ci-tools/pkg/dispatcher"
+	"github.com/openshift/ci-tools/pkg/jobpolicy"
 )
 
 func TestDefaultJobConfig(t *testing.T) {
@@ -67,3 +71,52 @@ func TestIsCIOperatorLatest(t *testing.T) {
 		})
 	}
 }
+
+func TestDeterminizeJobsChecksPoliciesWithRelativePaths(t *testing.T) {
+	// the job config directory is absolute, the exemptions must still match
+	prowJobConfigDir := t.TempDir()
+	job := []byte(`periodics:
+- name: periodic-without-team
+  interval: 24h
+  spec:
+    containers:
+    - image: ci-operator:latest
+`)
+	exempted := filepath.Join(prowJobConfigDir, "openshift", "release", "openshift-release-infra-periodics.yaml")
+	violating := filepath.Join(prowJobConfigDir, "org", "repo", "org-repo-periodics.yaml")
+	for _, path := range []string{exempted, violating} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, job, 0644); err != nil {
+			t.Fatalf("failed to write job config: %v", err)
+		}
+	}
+	policyPath := filepath.Join(t.TempDir(), "policies.yaml")
+	if err := os.WriteFile(policyPath, []byte(`rules:
+- name: team-label
+  requiredLabels:
+  - ci.openshift.io/team
+  exemptions:
+  - paths:
+    - ^openshift/release/
+    reason: owned by the test platform team
+`), 0644); err != nil {
+		t.Fatalf("failed to write policies: %v", err)
+	}
+	policies, err := jobpolicy.LoadConfig(policyPath)
+	if err != nil {
+		t.Fatalf("failed to load policies: %v", err)
+	}
+
+	err = determinizeJobs(prowJobConfigDir, "", &dispatcher.Config{Default: "api.ci"}, policies)
+	if err == nil {
+		t.Fatal("expected the job without a team label to violate the policies")
+	}
+	if !strings.Contains(err.Error(), violating) {
+		t.Errorf("expected %s to violate the policies, got: %v", violating, err)
+	}
+	if strings.Contains(err.Error(), exempted) {
+		t.Errorf("expected %s to be exempted, got: %v", exempted, err)
+	}
+}
@@ -35,13 +35,18 @@ type options struct {
 	testGridConfigDir string
 	prowJobConfigDir  string
 
-	validationOnlyRun bool
-	jobsAllowListFile string
+	validationOnlyRun  bool
+	jobsAllowListFile  string
+	teamDashboardsFile string
 
 	gcsBucket string
 }
 
-const defaultAggregateProwJobName = "release-openshift-release-analysis-aggregator"
+const (
+	defaultAggregateProwJobName = "release-openshift-release-analysis-aggregator"
+	// defaultDashboardGroup is the dashboard group the generated dashboards are listed in
+	defaultDashboardGroup = "redhat"
+)
 
 func (o *options) Validate() error {
 	if o.prowJobConfigDir == "" && !o.validationOnlyRun {
@@ -68,6 +73,7 @@ func gatherOptions() options {
 	fs.StringVar(&o.releaseConfigDir, "release-config", "", "Path to Release Controller configuration directory.")
 	fs.StringVar(&o.testGridConfigDir, "testgrid-config", "", "Path to TestGrid configuration directory.")
 	fs.StringVar(&o.jobsAllowListFile, "allow-list", "", "Path to file containing jobs to be overridden to informing jobs")
+	fs.StringVar(&o.teamDashboardsFile, "team-dashboards", "", "Path to file declaring dashboards for periodics that are not release-gating or informing")
 	fs.BoolVar(&o.validationOnlyRun, "validate", false, "Validate entries in file specified by allow-list (if allow_list is not specified validation would succeed)")
 	fs.StringVar(&o.gcsBucket, "google-storage-bucket", "test-platform-results", "The optional GCS Bucket holding test artifacts")
 	if err := fs.Parse(os.Args[1:]); err != nil {
@@ -81,6 +87,10 @@ type dashboard struct {
 	*config.Dashboard
 	testGroups []*config.TestGroup
 	existing   sets.Set[string]
+	// group is the dashboard group the dashboard is listed in
+	group string
+	// team is set for dashboards declared by teams
+	team bool
 }
 
 func genericDashboardFor(role string) *dashboard {
@@ -91,6 +101,7 @@ func genericDashboardFor(role string) *dashboard {
 		},
 		testGroups: []*config.TestGroup{},
 		existing:   sets.New[string](),
+		group:      defaultDashboardGroup,
 	}
 }
 
@@ -102,6 +113,7 @@ func dashboardFor(stream, version, role string) *dashboard {
 		},
 		testGroups: []*config.TestGroup{},
 		existing:   sets.New[string](),
+		group:      defaultDashboardGroup,
 	}
 }
 
@@ -294,6 +306,18 @@ func addDashboardTab(p prowConfig.Periodic,
 		current = dashboardFor(stream, version, dashboardType)
 	}
 
+	if existing, ok := dashboards[current.Name]; ok {
+		current = existing
+	} else {
+		dashboards[current.Name] = current
+	}
+
+	current.add(bucket, jobName, p.Annotations["description"], daysOfResultsFor(p))
+
+}
+
+// daysOfResultsFor determines how many days of results to show for the periodic
+func daysOfResultsFor(p prowConfig.Periodic) int32 {
 	daysOfResults := int32(0)
 	// for infrequently run jobs (at 12h or 24h intervals) we'd prefer to have more history than just the default
 	// 7-10 days (specified by the default testgrid config), so try to set number of days of results so that we
@@ -311,19 +335,87 @@ func addDashboardTab(p prowConfig.Periodic,
 		}
 	}
 
-	if existing, ok := dashboards[current.Name]; ok {
-		current = existing
-	} else {
-		dashboards[current.Name] = current
+	return daysOfResults
+}
+
+// addTeamDashboardTabs adds the periodic to all team dashboards that declare it
+func addTeamDashboardTabs(p prowConfig.Periodic, dashboards map[string]*dashboard, teamDashboards []teamDashboard, bucket string) {
+	for _, team := range teamDashboards {
+		if !team.matches(p.Name) {
+			continue
+		}
+		current, ok := dashboards[team.Name]
+		if !ok {
+			current = team.dashboard()
+			dashboards[team.Name] = current
+		}
+		current.add(bucket, p.Name, p.Annotations["description"], daysOfResultsFor(p))
+	}
+}
+
+// updateDashboardGroups lists the generated dashboards in their dashboard groups, creating the
+// groups that do not exist yet. It returns the dashboards that were generated in the past but no
+// longer are, those are removed from the groups. Team dashboards generated in the past are given
+// by previousTeamDashboards, as their names follow no convention.
+func updateDashboardGroups(groups *config.Configuration, dashboards map[string]*dashboard, previousTeamDashboards sets.Set[string]) sets.Set[string] {
+	dashboardNames := map[string]sets.Set[string]{defaultDashboardGroup: sets.New[string]()}
+	teamDashboards := sets.New[string]()
+	for _, dash := range dashboards {
+		if len(dash.testGroups) == 0 {
+			continue
+		}
+		if _, ok := dashboardNames[dash.group]; !ok {
+			dashboardNames[dash.group] = sets.New[string]()
+		}
+		dashboardNames[dash.group].Insert(dash.Name)
+		if dash.team {
+			teamDashboards.Insert(dash.Name)
+		}
 	}
 
-	current.add(bucket, jobName, p.Annotations["description"], daysOfResults)
+	toRemove := previousTeamDashboards.Difference(teamDashboards)
+	var dashGroups []*config.DashboardGroup
+	for _, dashGroup := range groups.DashboardGroups {
+		existing := sets.New[string](dashGroup.DashboardNames...)
+		if dashGroup.Name == defaultDashboardGroup {
+			for _, name := range sets.List(existing.Difference(dashboardNames[defaultDashboardGroup])) {
+				if isGeneratedReleaseDashboard(name) {
+					// this is a good-enough heuristic to identify a board that was generated by this tool in the past,
+					// but is no longer generated and should be pruned.
+					toRemove.Insert(name)
+				}
+			}
+		}
+		names := existing.Union(dashboardNames[dashGroup.Name]).Difference(toRemove)
+		delete(dashboardNames, dashGroup.Name)
+		if names.Len() == 0 && existing.HasAny(sets.List(toRemove)...) {
+			// the group only listed dashboards that were pruned
+			continue
+		}
+		dashGroup.DashboardNames = sets.List(names) // sorted implicitly
+		dashGroups = append(dashGroups, dashGroup)
+	}
+	for _, name := range sets.List(sets.KeySet(dashboardNames)) {
+		if name == defaultDashboardGroup || dashboardNames[name].Len() == 0 {
+			// the default group is managed by hand
+			continue
+		}
+		dashGroups = append(dashGroups, &config.DashboardGroup{Name: name, DashboardNames: sets.List(dashboardNames[name])})
+	}
+	groups.DashboardGroups = dashGroups
+	return toRemove
+}
 
+func isGeneratedReleaseDashboard(name string) bool {
+	return strings.HasPrefix(name, "redhat-openshift-") && strings.Contains(name, "-release-")
 }
 
 // This tool is intended to make the process of maintaining TestGrid dashboards for
 // release-gating and release-informing tests simple.
 //
+// Teams can additionally declare their own dashboards for arbitrary periodics, those
+// are listed in the dashboard group the team chooses.
+//
 // We read all jobs that are annotated for the grid. The release controller's configuration
 // is used to default those roles but they can be overridden per job. We partition by overall
 // type (blocking, informing, broken), version or generic (generic have no version), and by
@@ -392,6 +484,18 @@ func main() {
 		logrus.WithError(err).Fatal("Could not process input configurations.")
 	}
 
+	var teamDashboards []teamDashboard
+	if o.teamDashboardsFile != "" {
+		data, err := gzip.ReadFileMaybeGZIP(o.teamDashboardsFile)
+		if err != nil {
+			logrus.WithError(err).Fatalf("could not read team dashboards at %s", o.teamDashboardsFile)
+		}
+		teamDashboards, err = getTeamDashboards(data)
+		if err != nil {
+			logrus.WithError(err).Fatal("invalid team dashboards")
+		}
+	}
+
 	// read the list of jobs from the allow list along with its release-type
 	var allowList map[string]string
 	if o.jobsAllowListFile != "" {
@@ -431,17 +535,10 @@ func main() {
 				addDashboardTab(p, dashboards, configuredJobs, allowList, &aggregateJob, o.gcsBucket)
 			}
 		}
+		addTeamDashboardTabs(p, dashboards, teamDashboards, o.gcsBucket)
 	}
 
-	// first, update the overall list of dashboards that exist for the redhat group
-	dashboardNames := sets.New[string]()
-	for _, dash := range dashboards {
-		if len(dash.testGroups) == 0 {
-			continue
-		}
-		dashboardNames.Insert(dash.Name)
-	}
-
+	// first, update the overall list of dashboards that exist for the dashboard groups
 	groupFile := path.Join(o.testGridConfigDir, "groups.yaml")
 	data, err := gzip.ReadFileMaybeGZIP(groupFile)
 	if err != nil {
@@ -453,20 +550,11 @@ func main() {
 		logrus.WithError(err).Fatal("Could not unmarshal TestGrid group config")
 	}
 
-	toRemove := sets.New[string]()
-	for _, dashGroup := range groups.DashboardGroups {
-		if dashGroup.Name == "redhat" {
-			for _, name := range sets.List(sets.New[string](dashGroup.DashboardNames...).Difference(dashboardNames)) {
-				if strings.HasPrefix(name, "redhat-openshift-") && strings.Contains(name, "-release-") {
-					// this is a good-enough heuristic to identify a board that was generated by this tool in the past,
-					// but is no longer generated and should be pruned.
-					toRemove.Insert(name)
-				}
-			}
-			dashboardNames.Insert(dashGroup.DashboardNames...).Delete(sets.List(toRemove)...)
-			dashGroup.DashboardNames = sets.List(dashboardNames) // sorted implicitly
-		}
+	previousTeamDashboards, err := generatedTeamDashboards(o.testGridConfigDir)
+	if err != nil {
+		logrus.WithError(err).Fatal("Could not determine previously generated team dashboards")
 	}
+	toRemove := updateDashboardGroups(&groups, dashboards, previousTeamDashboards)
 
 	data, err = yaml.Marshal(&groups)
 	if err != nil {
@@ -501,6 +589,9 @@ func main() {
 		if err != nil {
 			logrus.WithError(err).Fatalf("Could not marshal TestGrid config for %s", dash.Name)
 		}
+		if dash.team {
+			data = append([]byte(teamDashboardHeader), data...)
+		}
 
 		if err := os.WriteFile(path.Join(o.testGridConfigDir, fmt.Sprintf("%s.yaml", dash.Name)), data, 0664); err != nil {
 			logrus.WithError(err).Fatalf("Could not write TestGrid config for %s", dash.Name)
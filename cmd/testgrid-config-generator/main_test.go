@@ -2,10 +2,16 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
+	"github.com/GoogleCloudPlatform/testgrid/pb/config"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 func TestGetAllowList(t *testing.T) {
@@ -60,6 +66,58 @@ key2: informing
 
 }
 
+func TestUpdateDashboardGroups(t *testing.T) {
+	withTab := func(d *dashboard) *dashboard {
+		d.add("bucket", "job", "", 0)
+		return d
+	}
+	team := teamDashboard{Name: "network-edge", DashboardGroup: "redhat-network"}
+	groups := config.Configuration{DashboardGroups: []*config.DashboardGroup{
+		{Name: "redhat", DashboardNames: []string{"manual", "redhat-openshift-ocp-release-4.1-informing", "redhat-openshift-ocp-release-4.2-informing"}},
+		{Name: "other", DashboardNames: []string{"other", "old-team"}},
+		{Name: "redhat-storage", DashboardNames: []string{"storage"}},
+	}}
+	dashboards := map[string]*dashboard{
+		"redhat-openshift-ocp-release-4.2-informing": withTab(dashboardFor("ocp", "4.2", "informing")),
+		"redhat-openshift-ocp-release-4.3-informing": withTab(dashboardFor("ocp", "4.3", "informing")),
+		"redhat-openshift-ocp-release-4.4-informing": dashboardFor("ocp", "4.4", "informing"),
+		"network-edge": withTab(team.dashboard()),
+	}
+
+	toRemove := updateDashboardGroups(&groups, dashboards, sets.New[string]("network-edge", "old-team", "storage"))
+	if diff := cmp.Diff(sets.New[string]("redhat-openshift-ocp-release-4.1-informing", "old-team", "storage"), toRemove); diff != "" {
+		t.Errorf("dashboards to remove differ from expected: %s", diff)
+	}
+	expected := []*config.DashboardGroup{
+		{Name: "redhat", DashboardNames: []string{"manual", "redhat-openshift-ocp-release-4.2-informing", "redhat-openshift-ocp-release-4.3-informing"}},
+		{Name: "other", DashboardNames: []string{"other"}},
+		{Name: "redhat-network", DashboardNames: []string{"network-edge"}},
+	}
+	if diff := cmp.Diff(expected, groups.DashboardGroups, cmpopts.IgnoreUnexported(config.DashboardGroup{})); diff != "" {
+		t.Errorf("dashboard groups differ from expected: %s", diff)
+	}
+}
+
+func TestGeneratedTeamDashboards(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"network-edge.yaml": teamDashboardHeader + "dashboards: []\n",
+		"manual.yaml":       "dashboards: []\n",
+		"notes.txt":         teamDashboardHeader,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	names, err := generatedTeamDashboards(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(sets.New[string]("network-edge"), names); diff != "" {
+		t.Errorf("team dashboards differ from expected: %s", diff)
+	}
+}
+
 func equalError(t *testing.T, expected, actual error) {
 	if expected != nil && actual == nil || expected == nil && actual != nil {
 		t.Errorf("expecting error \"%v\", got \"%v\"", expected, actual)
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/testgrid/pb/config"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+// teamDashboardHeader marks the configuration files of team dashboards, so that the ones no
+// longer declared can be pruned
+const teamDashboardHeader = "# Generated by testgrid-config-generator from the team dashboards, do not edit.\n"
+
+// teamDashboards declares dashboards for periodics that are not release-gating or informing
+type teamDashboards struct {
+	Dashboards []teamDashboard `json:"dashboards"`
+}
+
+// teamDashboard is a dashboard owned by a team
+type teamDashboard struct {
+	// Name is the name of the dashboard
+	Name string `json:"name"`
+	// DashboardGroup is the dashboard group the dashboard is listed in, defaults to "redhat"
+	DashboardGroup string `json:"dashboard_group,omitempty"`
+	// Jobs are the names of the periodics shown on the dashboard
+	Jobs []string `json:"jobs,omitempty"`
+	// JobPatterns are regular expressions matching the names of the periodics shown on the dashboard
+	JobPatterns []string `json:"job_patterns,omitempty"`
+
+	jobs     sets.Set[string]
+	patterns []*regexp.Regexp
+}
+
+func (t teamDashboard) matches(jobName string) bool {
+	if t.jobs.Has(jobName) {
+		return true
+	}
+	for _, pattern := range t.patterns {
+		if pattern.MatchString(jobName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teamDashboard) dashboard() *dashboard {
+	return &dashboard{
+		Dashboard: &config.Dashboard{
+			Name:         t.Name,
+			DashboardTab: []*config.DashboardTab{},
+		},
+		testGroups: []*config.TestGroup{},
+		existing:   sets.New[string](),
+		group:      t.DashboardGroup,
+		team:       true,
+	}
+}
+
+// generatedTeamDashboards returns the names of the team dashboards in the TestGrid configuration
+// directory, determined by the header the configuration files were written with
+func generatedTeamDashboards(dir string) (sets.Set[string], error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not list %s: %w", dir, err)
+	}
+	names := sets.New[string]()
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".yaml" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %w", entry.Name(), err)
+		}
+		if bytes.HasPrefix(data, []byte(teamDashboardHeader)) {
+			names.Insert(strings.TrimSuffix(entry.Name(), ".yaml"))
+		}
+	}
+	return names, nil
+}
+
+// isGeneratedDashboard determines if the name belongs to a dashboard generated for the releases
+func isGeneratedDashboard(name string) bool {
+	switch name {
+	case genericDashboardFor("informing").Name, genericDashboardFor("osd").Name, genericDashboardFor("olm").Name:
+		return true
+	}
+	return isGeneratedReleaseDashboard(name)
+}
+
+func getTeamDashboards(data []byte) ([]teamDashboard, error) {
+	var teams teamDashboards
+	if err := yaml.UnmarshalStrict(data, &teams); err != nil {
+		return nil, fmt.Errorf("could not unmarshal team dashboards: %w", err)
+	}
+	var errs []error
+	seen := sets.New[string]()
+	for i := range teams.Dashboards {
+		team := &teams.Dashboards[i]
+		if team.Name == "" {
+			errs = append(errs, fmt.Errorf("dashboards[%d]: name must be set", i))
+			continue
+		}
+		if seen.Has(team.Name) {
+			errs = append(errs, fmt.Errorf("%s: dashboard is declared more than once", team.Name))
+		}
+		seen.Insert(team.Name)
+		if isGeneratedDashboard(team.Name) {
+			errs = append(errs, fmt.Errorf("%s: name is reserved for the dashboards generated for the releases", team.Name))
+		}
+		if len(team.Jobs) == 0 && len(team.JobPatterns) == 0 {
+			errs = append(errs, fmt.Errorf("%s: at least one of jobs or job_patterns must be set", team.Name))
+		}
+		if team.DashboardGroup == "" {
+			team.DashboardGroup = defaultDashboardGroup
+		}
+		team.jobs = sets.New[string](team.Jobs...)
+		for _, pattern := range team.JobPatterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid job pattern %q: %w", team.Name, pattern, err))
+				continue
+			}
+			team.patterns = append(team.patterns, re)
+		}
+	}
+	return teams.Dashboards, utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetTeamDashboards(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         string
+		expectedError error
+		matching      map[string]map[string]bool
+	}{
+		{
+			name: "valid dashboards",
+			input: `
+dashboards:
+- name: redhat-openshift-storage
+  jobs:
+  - periodic-ci-openshift-csi-operator-master-e2e
+  job_patterns:
+  - ^periodic-ci-openshift-aws-ebs-csi-driver-
+- name: network-edge
+  dashboard_group: redhat-network
+  job_patterns:
+  - -ingress-
+`,
+			matching: map[string]map[string]bool{
+				"redhat-openshift-storage": {
+					"periodic-ci-openshift-csi-operator-master-e2e":       true,
+					"periodic-ci-openshift-aws-ebs-csi-driver-master-e2e": true,
+					"periodic-ci-openshift-csi-operator-master-e2e-aws":   false,
+				},
+				"network-edge": {
+					"periodic-ci-openshift-cluster-ingress-operator-master-e2e": true,
+					"periodic-ci-openshift-csi-operator-master-e2e":             false,
+				},
+			},
+		},
+		{
+			name: "invalid dashboards",
+			input: `
+dashboards:
+- jobs: [job]
+- name: redhat-openshift-ocp-release-4.14-informing
+  jobs: [job]
+- name: redhat-openshift-informing
+  jobs: [job]
+- name: empty
+- name: broken
+  job_patterns: ["("]
+- name: broken
+  jobs: [job]
+`,
+			expectedError: errors.New("[dashboards[0]: name must be set, redhat-openshift-ocp-release-4.14-informing: name is reserved for the dashboards generated for the releases, redhat-openshift-informing: name is reserved for the dashboards generated for the releases, empty: at least one of jobs or job_patterns must be set, broken: invalid job pattern \"(\": error parsing regexp: missing closing ): `(`, broken: dashboard is declared more than once]"),
+		},
+		{
+			name: "unknown field",
+			input: `
+dashboards:
+- name: team
+  job: [job]
+`,
+			expectedError: errors.New("could not unmarshal team dashboards: error unmarshaling JSON: while decoding JSON: json: unknown field \"job\""),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dashboards, err := getTeamDashboards([]byte(tc.input))
+			equalError(t, tc.expectedError, err)
+			if err != nil {
+				return
+			}
+			for _, team := range dashboards {
+				for job, expected := range tc.matching[team.Name] {
+					if actual := team.matches(job); actual != expected {
+						t.Errorf("%s: expected job %s to match: %t, matched: %t", team.Name, job, expected, actual)
+					}
+				}
+			}
+			if dashboards[1].DashboardGroup != "redhat-network" || dashboards[0].DashboardGroup != defaultDashboardGroup {
+				t.Errorf("unexpected dashboard groups: %s, %s", dashboards[0].DashboardGroup, dashboards[1].DashboardGroup)
+			}
+		})
+	}
+}
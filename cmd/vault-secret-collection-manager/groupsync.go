@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+// syncedMembersMetadataKey is the group metadata key under which we record the
+// members that were added by the group sync. Only those are ever removed by it,
+// members added through self-service are left alone.
+const syncedMembersMetadataKey = "group-synced-members"
+
+// groupSyncConfig maps secret collections to the Rover groups whose members
+// must be members of the collection
+type groupSyncConfig struct {
+	Collections map[string]collectionGroupSync `json:"collections"`
+}
+
+type collectionGroupSync struct {
+	Groups []string `json:"groups"`
+}
+
+func loadGroupSyncConfig(path string) (*groupSyncConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var config groupSyncConfig
+	if err := yaml.UnmarshalStrict(raw, &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// loadRoverGroups reads the groups file written by sync-rover-groups, which maps
+// group names to the Kerberos IDs of their members
+func loadRoverGroups(path string) (map[string][]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	groups := map[string][]string{}
+	if err := yaml.Unmarshal(raw, &groups); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", path, err)
+	}
+	return groups, nil
+}
+
+// collectionDrift describes how the members of a secret collection differ from
+// the members of the groups it is synced with
+type collectionDrift struct {
+	Collection string   `json:"collection"`
+	Missing    []string `json:"missing,omitempty"`
+	Stale      []string `json:"stale,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+type groupSyncReport struct {
+	LastSync time.Time         `json:"last_sync"`
+	DryRun   bool              `json:"dry_run"`
+	Drift    []collectionDrift `json:"drift,omitempty"`
+}
+
+var groupSyncDriftMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "secret_collection_manager_group_sync_drift",
+	Help: "Number of members by which a secret collection differs from its synced groups",
+}, []string{"collection", "kind"})
+
+func init() {
+	prometheus.MustRegister(groupSyncDriftMetric)
+}
+
+// collectionMembership reads and replaces the members of secret collections
+type collectionMembership interface {
+	// collectionMembers returns the names of the members of a collection and the metadata of its group
+	collectionMembers(collection string) (sets.Set[string], map[string]string, error)
+	// setCollectionMembers replaces the members of a collection and the metadata of its group
+	setCollectionMembers(collection string, members sets.Set[string], metadata map[string]string) error
+}
+
+type groupSyncer struct {
+	collections collectionMembership
+	// membersLock serializes the sync with self-service changes to the members
+	membersLock sync.Locker
+	configPath  string
+	groupsPath  string
+	dryRun      bool
+
+	lock   sync.RWMutex
+	report groupSyncReport
+}
+
+// membershipChange determines which users must be added to and removed from a
+// collection: every member of a synced group must be a member of the collection
+// and users we added earlier that left the groups are removed again. It also
+// returns the members to record as synced, which are only the ones we added:
+// a self-service member who joins a group later must not be removed when they
+// leave it again.
+func membershipChange(current, previouslySynced, desired sets.Set[string]) (missing, stale, synced sets.Set[string]) {
+	missing = desired.Difference(current)
+	stale = previouslySynced.Difference(desired).Intersection(current)
+	synced = previouslySynced.Intersection(desired).Union(missing)
+	return missing, stale, synced
+}
+
+// sync reconciles the members of all configured collections. Errors for single
+// collections are recorded in the report and do not stop the others from being
+// reconciled.
+func (s *groupSyncer) sync() error {
+	config, err := loadGroupSyncConfig(s.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load group sync config: %w", err)
+	}
+	groups, err := loadRoverGroups(s.groupsPath)
+	if err != nil {
+		return fmt.Errorf("failed to load groups: %w", err)
+	}
+
+	report := groupSyncReport{LastSync: time.Now(), DryRun: s.dryRun}
+	var errs []error
+	for _, name := range sets.List(sets.KeySet(config.Collections)) {
+		drift, err := s.syncCollection(name, config.Collections[name].Groups, groups)
+		if err != nil {
+			drift.Error = err.Error()
+			errs = append(errs, fmt.Errorf("failed to sync collection %s: %w", name, err))
+		}
+		groupSyncDriftMetric.WithLabelValues(name, "missing").Set(float64(len(drift.Missing)))
+		groupSyncDriftMetric.WithLabelValues(name, "stale").Set(float64(len(drift.Stale)))
+		if len(drift.Missing) > 0 || len(drift.Stale) > 0 || drift.Error != "" {
+			report.Drift = append(report.Drift, drift)
+		}
+	}
+
+	s.lock.Lock()
+	s.report = report
+	s.lock.Unlock()
+	return utilerrors.NewAggregate(errs)
+}
+
+func (s *groupSyncer) syncCollection(name string, groupNames []string, groups map[string][]string) (collectionDrift, error) {
+	drift := collectionDrift{Collection: name}
+	logger := logrus.WithField("collection", name)
+
+	desired := sets.New[string]()
+	for _, groupName := range groupNames {
+		members, ok := groups[groupName]
+		if !ok {
+			return drift, fmt.Errorf("group %s not found", groupName)
+		}
+		desired.Insert(members...)
+	}
+
+	s.membersLock.Lock()
+	defer s.membersLock.Unlock()
+	current, metadata, err := s.collections.collectionMembers(name)
+	if err != nil {
+		return drift, err
+	}
+	previouslySynced := sets.New[string]()
+	if raw := metadata[syncedMembersMetadataKey]; raw != "" {
+		previouslySynced.Insert(strings.Split(raw, ",")...)
+	}
+
+	missing, stale, synced := membershipChange(current, previouslySynced, desired)
+	drift.Missing, drift.Stale = sets.List(missing), sets.List(stale)
+	updated := current.Union(missing).Difference(stale)
+	if updated.Len() == 0 {
+		return drift, fmt.Errorf("refusing to remove all members")
+	}
+	if s.dryRun || (missing.Len() == 0 && stale.Len() == 0 && previouslySynced.Equal(synced)) {
+		return drift, nil
+	}
+
+	updatedMetadata := map[string]string{}
+	for k, v := range metadata {
+		updatedMetadata[k] = v
+	}
+	updatedMetadata[syncedMembersMetadataKey] = strings.Join(sets.List(synced), ",")
+	if err := s.collections.setCollectionMembers(name, updated, updatedMetadata); err != nil {
+		return drift, err
+	}
+	if len(drift.Missing) > 0 || len(drift.Stale) > 0 {
+		logger.WithField("added", drift.Missing).WithField("removed", drift.Stale).Info("Synced members from groups")
+	}
+	return drift, nil
+}
+
+func (s *groupSyncer) reportHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	s.lock.RLock()
+	report := s.report
+	s.lock.RUnlock()
+	serialized, err := json.Marshal(report)
+	if err != nil {
+		logrus.WithError(err).Error("failed to serialize group sync report")
+		http.Error(w, "failed to serialize group sync report", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	if _, err := w.Write(serialized); err != nil {
+		logrus.WithError(err).Error("failed to write response")
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/ci-tools/pkg/testhelper"
+)
+
+func TestMembershipChange(t *testing.T) {
+	testCases := []struct {
+		name             string
+		current          []string
+		previouslySynced []string
+		desired          []string
+		expectedMissing  []string
+		expectedStale    []string
+		expectedSynced   []string
+	}{
+		{
+			name:            "new group members are added",
+			current:         []string{"self-service"},
+			desired:         []string{"a", "b"},
+			expectedMissing: []string{"a", "b"},
+			expectedSynced:  []string{"a", "b"},
+		},
+		{
+			name:             "synced members that left the group are removed, self-service members are kept",
+			current:          []string{"a", "b", "self-service"},
+			previouslySynced: []string{"a", "b"},
+			desired:          []string{"a"},
+			expectedStale:    []string{"b"},
+			expectedSynced:   []string{"a"},
+		},
+		{
+			name:             "synced member that was already removed by hand is not stale",
+			current:          []string{"a"},
+			previouslySynced: []string{"a", "b"},
+			desired:          []string{"a"},
+			expectedSynced:   []string{"a"},
+		},
+		{
+			name:             "no drift",
+			current:          []string{"a", "self-service"},
+			previouslySynced: []string{"a"},
+			desired:          []string{"a"},
+			expectedSynced:   []string{"a"},
+		},
+		{
+			name:             "self-service member who joins the group is not recorded as synced",
+			current:          []string{"a", "self-service"},
+			previouslySynced: []string{"a"},
+			desired:          []string{"a", "self-service"},
+			expectedSynced:   []string{"a"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			missing, stale, synced := membershipChange(sets.New[string](tc.current...), sets.New[string](tc.previouslySynced...), sets.New[string](tc.desired...))
+			if diff := cmp.Diff(sets.New[string](tc.expectedMissing...), missing); diff != "" {
+				t.Errorf("unexpected missing members: %s", diff)
+			}
+			if diff := cmp.Diff(sets.New[string](tc.expectedStale...), stale); diff != "" {
+				t.Errorf("unexpected stale members: %s", diff)
+			}
+			if diff := cmp.Diff(sets.New[string](tc.expectedSynced...), synced); diff != "" {
+				t.Errorf("unexpected synced members: %s", diff)
+			}
+		})
+	}
+}
+
+type fakeCollectionMembership struct {
+	members  map[string]sets.Set[string]
+	metadata map[string]map[string]string
+	updates  int
+}
+
+func (f *fakeCollectionMembership) collectionMembers(collection string) (sets.Set[string], map[string]string, error) {
+	members, ok := f.members[collection]
+	if !ok {
+		return nil, nil, fmt.Errorf("collection %s not found", collection)
+	}
+	return members.Clone(), f.metadata[collection], nil
+}
+
+func (f *fakeCollectionMembership) setCollectionMembers(collection string, members sets.Set[string], metadata map[string]string) error {
+	f.members[collection] = members.Clone()
+	f.metadata[collection] = metadata
+	f.updates++
+	return nil
+}
+
+func TestSyncCollection(t *testing.T) {
+	groups := map[string][]string{"team": {"a", "b"}, "other": {"c"}, "empty": {}}
+	testCases := []struct {
+		name             string
+		groups           []string
+		members          []string
+		metadata         map[string]string
+		dryRun           bool
+		expectedDrift    collectionDrift
+		expectedErr      error
+		expectedMembers  []string
+		expectedMetadata map[string]string
+		expectedUpdates  int
+	}{
+		{
+			name:             "group members are added and recorded",
+			groups:           []string{"team", "other"},
+			members:          []string{"self-service"},
+			metadata:         map[string]string{"created-by-secret-collection-manager": "true"},
+			expectedDrift:    collectionDrift{Collection: "collection", Missing: []string{"a", "b", "c"}},
+			expectedMembers:  []string{"a", "b", "c", "self-service"},
+			expectedMetadata: map[string]string{"created-by-secret-collection-manager": "true", syncedMembersMetadataKey: "a,b,c"},
+			expectedUpdates:  1,
+		},
+		{
+			name:             "synced members that left the group are removed",
+			groups:           []string{"team"},
+			members:          []string{"a", "b", "c", "self-service"},
+			metadata:         map[string]string{syncedMembersMetadataKey: "a,b,c"},
+			expectedDrift:    collectionDrift{Collection: "collection", Stale: []string{"c"}},
+			expectedMembers:  []string{"a", "b", "self-service"},
+			expectedMetadata: map[string]string{syncedMembersMetadataKey: "a,b"},
+			expectedUpdates:  1,
+		},
+		{
+			name:             "no drift does not update the group",
+			groups:           []string{"team"},
+			members:          []string{"a", "b"},
+			metadata:         map[string]string{syncedMembersMetadataKey: "a,b"},
+			expectedDrift:    collectionDrift{Collection: "collection"},
+			expectedMembers:  []string{"a", "b"},
+			expectedMetadata: map[string]string{syncedMembersMetadataKey: "a,b"},
+		},
+		{
+			name:             "dry-run only reports the drift",
+			groups:           []string{"other"},
+			members:          []string{"a", "b"},
+			metadata:         map[string]string{syncedMembersMetadataKey: "a,b"},
+			dryRun:           true,
+			expectedDrift:    collectionDrift{Collection: "collection", Missing: []string{"c"}, Stale: []string{"a", "b"}},
+			expectedMembers:  []string{"a", "b"},
+			expectedMetadata: map[string]string{syncedMembersMetadataKey: "a,b"},
+		},
+		{
+			name:             "removing all members is refused",
+			groups:           []string{"empty"},
+			members:          []string{"a", "b"},
+			metadata:         map[string]string{syncedMembersMetadataKey: "a,b"},
+			expectedDrift:    collectionDrift{Collection: "collection", Stale: []string{"a", "b"}},
+			expectedErr:      errors.New("refusing to remove all members"),
+			expectedMembers:  []string{"a", "b"},
+			expectedMetadata: map[string]string{syncedMembersMetadataKey: "a,b"},
+		},
+		{
+			name:            "unknown group",
+			groups:          []string{"unknown"},
+			members:         []string{"a"},
+			expectedDrift:   collectionDrift{Collection: "collection"},
+			expectedErr:     errors.New("group unknown not found"),
+			expectedMembers: []string{"a"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			collections := &fakeCollectionMembership{
+				members:  map[string]sets.Set[string]{"collection": sets.New[string](tc.members...)},
+				metadata: map[string]map[string]string{"collection": tc.metadata},
+			}
+			syncer := &groupSyncer{collections: collections, membersLock: &sync.Mutex{}, dryRun: tc.dryRun}
+			drift, err := syncer.syncCollection("collection", tc.groups, groups)
+			if diff := cmp.Diff(tc.expectedErr, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("unexpected error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedDrift, drift, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("unexpected drift: %s", diff)
+			}
+			if diff := cmp.Diff(sets.New[string](tc.expectedMembers...), collections.members["collection"]); diff != "" {
+				t.Errorf("unexpected members: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedMetadata, collections.metadata["collection"]); diff != "" {
+				t.Errorf("unexpected metadata: %s", diff)
+			}
+			if collections.updates != tc.expectedUpdates {
+				t.Errorf("expected %d updates, got %d", tc.expectedUpdates, collections.updates)
+			}
+		})
+	}
+}
+
+func TestSyncCollectionKeepsSelfServiceMemberWhoJoinedTheGroup(t *testing.T) {
+	collections := &fakeCollectionMembership{
+		members:  map[string]sets.Set[string]{"collection": sets.New[string]("a", "self-service")},
+		metadata: map[string]map[string]string{"collection": {syncedMembersMetadataKey: "a"}},
+	}
+	syncer := &groupSyncer{collections: collections, membersLock: &sync.Mutex{}}
+
+	// the self-service member joins the group, then leaves it again
+	for _, members := range [][]string{{"a", "self-service"}, {"a"}} {
+		if _, err := syncer.syncCollection("collection", []string{"team"}, map[string][]string{"team": members}); err != nil {
+			t.Fatalf("failed to sync: %v", err)
+		}
+	}
+	if diff := cmp.Diff(sets.New[string]("a", "self-service"), collections.members["collection"]); diff != "" {
+		t.Errorf("unexpected members: %s", diff)
+	}
+	if diff := cmp.Diff(map[string]string{syncedMembersMetadataKey: "a"}, collections.metadata["collection"]); diff != "" {
+		t.Errorf("unexpected metadata: %s", diff)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/test-infra/prow/config"
 	"k8s.io/test-infra/prow/flagutil"
 	"k8s.io/test-infra/prow/interrupts"
@@ -39,6 +40,11 @@ type option struct {
 
 	authBackendType string
 	flagutil.InstrumentationOptions
+
+	groupSyncConfigPath string
+	roverGroupsPath     string
+	groupSyncDryRun     bool
+	groupSyncInterval   time.Duration
 }
 
 func parseOptions() (*option, error) {
@@ -49,6 +55,10 @@ func parseOptions() (*option, error) {
 	flag.StringVar(&o.vaultToken, "vault-token", "", "The privileged token to use when communicating with vault, must be able to CRUD policies")
 	flag.StringVar(&o.vaultRole, "vault-role", "", "The vault role to use, must be able to CRUD policies. Will be used for kubernetes service account auth.")
 	flag.StringVar(&o.authBackendType, "auth-backend-type", "oidc", "The backend type used for user authentication.")
+	flag.StringVar(&o.groupSyncConfigPath, "group-sync-config", "", "Path to a config file mapping secret collections to the Rover groups whose members are synced into them")
+	flag.StringVar(&o.roverGroupsPath, "rover-groups-file", "", "Path to the groups file written by sync-rover-groups, required with --group-sync-config")
+	flag.BoolVar(&o.groupSyncDryRun, "group-sync-dry-run", false, "Only report the drift between secret collections and their groups instead of reconciling it")
+	flag.DurationVar(&o.groupSyncInterval, "group-sync-interval", 10*time.Minute, "How often to sync the members of secret collections from their groups")
 	o.InstrumentationOptions.AddFlags(flag.CommandLine)
 	flag.Parse()
 
@@ -56,6 +66,9 @@ func parseOptions() (*option, error) {
 	if o.vaultToken == "" && o.vaultRole == "" {
 		errs = append(errs, errors.New("--vault-token or --vault-role is required"))
 	}
+	if (o.groupSyncConfigPath == "") != (o.roverGroupsPath == "") {
+		errs = append(errs, errors.New("--group-sync-config and --rover-groups-file must be set together"))
+	}
 	if err := o.InstrumentationOptions.Validate(false); err != nil {
 		errs = append(errs, err)
 	}
@@ -114,6 +127,14 @@ func main() {
 			logrus.WithField("reconciled_policies", reconciledPolicies).Info("Successfully reconciled policies")
 		}
 	}, time.Hour)
+	if o.groupSyncConfigPath != "" {
+		manager.groupSyncer = &groupSyncer{collections: manager, membersLock: &manager.membersLock, configPath: o.groupSyncConfigPath, groupsPath: o.roverGroupsPath, dryRun: o.groupSyncDryRun}
+		interrupts.TickLiteral(func() {
+			if err := manager.groupSyncer.sync(); err != nil {
+				logrus.WithError(err).Error("Failed to sync secret collection members from groups")
+			}
+		}, o.groupSyncInterval)
+	}
 	interrupts.ListenAndServe(server, 5*time.Second)
 	interrupts.WaitForGracefulShutdown()
 }
@@ -150,6 +171,10 @@ type secretCollectionManager struct {
 	kvDataPrefix          string
 	groupCache            idNameCache
 	userCache             idNameCache
+	// groupSyncer is nil unless members are synced from groups
+	groupSyncer *groupSyncer
+	// membersLock serializes changes to the members of collections
+	membersLock sync.Mutex
 
 	authAccessorBackendType   string
 	authAccessorBackendID     string
@@ -204,9 +229,18 @@ func (m *secretCollectionManager) mux() *instrumentationWrapper {
 	router.PUT("/secretcollection/:name/members", loggingWrapper(userWrapper(m.updateSecretCollectionMembersHandler)))
 	router.DELETE("/secretcollection/:name", loggingWrapper(userWrapper(m.deleteCollectionHandler)))
 	router.GET("/users", loggingWrapper(userWrapper(m.usersHandler)))
+	router.GET("/groupsync", loggingWrapper(userWrapper(m.groupSyncReportHandler)))
 	return router
 }
 
+func (m *secretCollectionManager) groupSyncReportHandler(_ *logrus.Entry, _ string, w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	if m.groupSyncer == nil {
+		http.Error(w, "group sync is not enabled", http.StatusNotFound)
+		return
+	}
+	m.groupSyncer.reportHandler(w, r, params)
+}
+
 func healthHandler(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 	w.WriteHeader(http.StatusOK)
 }
@@ -289,6 +323,8 @@ func (m *secretCollectionManager) updateSecretCollectionMembersHandler(l *logrus
 		return
 	}
 
+	m.membersLock.Lock()
+	defer m.membersLock.Unlock()
 	isMember, err := m.isUserMemberInSecretCollection(l, user, name)
 	if err != nil {
 		l.WithError(err).Error("failed to check if user is member for secret collection")
@@ -339,6 +375,43 @@ func (m *secretCollectionManager) updateSecretCollectionMembers(_ *logrus.Entry,
 	return m.privilegedVaultClient.UpdateGroupMembers(prefixedName(collectionName), updatedMemberIDs)
 }
 
+func (m *secretCollectionManager) collectionMembers(collectionName string) (sets.Set[string], map[string]string, error) {
+	group, err := m.privilegedVaultClient.GetGroupByName(prefixedName(collectionName))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get group %s: %w", prefixedName(collectionName), err)
+	}
+	members := sets.New[string]()
+	for _, id := range group.MemberEntityIDs {
+		memberName, err := m.userAliasByIDCached(id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get name for entity %s: %w", id, err)
+		}
+		members.Insert(memberName)
+	}
+	return members, group.Metadata, nil
+}
+
+// setCollectionMembers replaces the members of a collection, creating the users that do not exist yet
+func (m *secretCollectionManager) setCollectionMembers(collectionName string, members sets.Set[string], metadata map[string]string) error {
+	var ids []string
+	for _, member := range sets.List(members) {
+		user, err := m.userByAliasCached(member)
+		if err != nil {
+			if !vaultclient.IsNotFound(err) {
+				return fmt.Errorf("failed to get user %s: %w", member, err)
+			}
+			if user, err = m.createUser(member); err != nil {
+				return fmt.Errorf("failed to create user %s: %w", member, err)
+			}
+		}
+		ids = append(ids, user.ID)
+	}
+	if err := m.privilegedVaultClient.UpdateGroupMembersAndMetadata(prefixedName(collectionName), ids, metadata); err != nil {
+		return fmt.Errorf("failed to update group %s: %w", prefixedName(collectionName), err)
+	}
+	return nil
+}
+
 var alphaNumericRegex = regexp.MustCompile("^[a-z0-9-]+$")
 
 func (m *secretCollectionManager) createSecretCollectionHandler(l *logrus.Entry, user string, w http.ResponseWriter, r *http.Request, params httprouter.Params) {
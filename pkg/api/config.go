@@ -24,15 +24,19 @@ func (config *ReleaseBuildConfiguration) Default() {
 		if c == nil {
 			return
 		}
+		if c.Timeout == nil {
+			c.Timeout = &prowv1.Duration{Duration: time.Hour}
+		}
+		if len(c.Selector) != 0 {
+			// the pools are not described by a product and an architecture
+			return
+		}
 		if c.Product == "" {
 			c.Product = ReleaseProductOCP
 		}
 		if c.Architecture == "" {
 			c.Architecture = ReleaseArchitectureAMD64
 		}
-		if c.Timeout == nil {
-			c.Timeout = &prowv1.Duration{Duration: time.Hour}
-		}
 	}
 	defTest := func(t *TestStepConfiguration) {
 		defClusterClaim(t.ClusterClaim)
@@ -128,6 +132,16 @@ func (config *ReleaseBuildConfiguration) WithPresubmitFrom(source *ReleaseBuildC
 		result.BaseImages[name] = isTagRef
 	}
 
+	for name, image := range source.ExternalImages {
+		if destImage, ok := result.ExternalImages[name]; ok && image != destImage {
+			return nil, fmt.Errorf("conflicting external_images: %s", name)
+		}
+		if result.ExternalImages == nil {
+			result.ExternalImages = map[string]ExternalImage{}
+		}
+		result.ExternalImages[name] = image
+	}
+
 	var hasLatestRelease bool
 	if result.Releases != nil {
 		_, hasLatestRelease = result.Releases[LatestReleaseName]
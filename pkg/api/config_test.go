@@ -110,6 +110,28 @@ func TestWithPresubmitFrom(t *testing.T) {
 			},
 			defaultTests: true,
 		},
+		{
+			name:   "external_images is an union of both configs",
+			base:   &ReleaseBuildConfiguration{InputConfiguration: InputConfiguration{ExternalImages: map[string]ExternalImage{"base-tool": {PullSpec: "quay.io/org/base-tool"}}}},
+			source: &ReleaseBuildConfiguration{InputConfiguration: InputConfiguration{ExternalImages: map[string]ExternalImage{"source-tool": {PullSpec: "quay.io/org/source-tool"}}}},
+			expected: &ReleaseBuildConfiguration{
+				InputConfiguration: InputConfiguration{
+					ExternalImages: map[string]ExternalImage{
+						"base-tool":   {PullSpec: "quay.io/org/base-tool"},
+						"source-tool": {PullSpec: "quay.io/org/source-tool"},
+					}},
+			},
+			defaultTests: true,
+		},
+		{
+			name: "external_images conflict when both configs have a different image with the same name",
+			base: &ReleaseBuildConfiguration{InputConfiguration: InputConfiguration{ExternalImages: map[string]ExternalImage{"tool": {PullSpec: "quay.io/org/base-tool"}}}},
+			source: &ReleaseBuildConfiguration{
+				InputConfiguration: InputConfiguration{ExternalImages: map[string]ExternalImage{"tool": {PullSpec: "quay.io/org/source-tool"}}},
+				Tests:              []TestStepConfiguration{sourceTest},
+			},
+			expectedError: errors.New("conflicting external_images: tool"),
+		},
 		{
 			name: "base_images do not conflict when both configs have a same base image",
 			base: &ReleaseBuildConfiguration{InputConfiguration: InputConfiguration{BaseImages: baseBaseImages}},
@@ -16,6 +16,11 @@ func Artifacts() (string, bool) {
 	return os.LookupEnv(prowArtifactsEnv)
 }
 
+// SetArtifacts points the artifact directory at dir, for the runs which are not in Prow
+func SetArtifacts(dir string) error {
+	return os.Setenv(prowArtifactsEnv, dir)
+}
+
 // SaveArtifact saves the data under the path relative to the artifact directory.
 // If no artifact directory is set, we no-op.
 // A note on censoring: SaveArtifact will ensure that the raw data being written
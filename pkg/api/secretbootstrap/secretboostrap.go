@@ -11,8 +11,10 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/yaml"
 
+	"github.com/openshift/ci-tools/pkg/api/secretgenerator"
 	"github.com/openshift/ci-tools/pkg/util/gzip"
 	"github.com/openshift/ci-tools/pkg/validation"
 )
@@ -29,8 +31,25 @@ type ItemContext struct {
 type DockerConfigJSONData struct {
 	Item        string `json:"item"`
 	RegistryURL string `json:"registry_url"`
-	AuthField   string `json:"auth_field"`
-	EmailField  string `json:"email_field,omitempty"`
+	// AuthField holds the base64-encoded "username:password" credentials.
+	// Mutually exclusive with 'UsernameField' and 'PasswordField'
+	AuthField string `json:"auth_field,omitempty"`
+	// UsernameField and PasswordField hold the plain credentials, from which
+	// the auth of the registry is assembled
+	UsernameField string `json:"username_field,omitempty"`
+	PasswordField string `json:"password_field,omitempty"`
+	EmailField    string `json:"email_field,omitempty"`
+}
+
+// Fields returns the fields of the item that the entry is assembled from
+func (d DockerConfigJSONData) Fields() []string {
+	var fields []string
+	for _, field := range []string{d.AuthField, d.UsernameField, d.PasswordField, d.EmailField} {
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
 }
 
 type DockerConfigJSON struct {
@@ -56,9 +75,18 @@ func (sc SecretContext) String() string {
 	return sc.Namespace + "/" + sc.Name + " in cluster " + sc.Cluster
 }
 
+// VaultContext is an item in Vault that a secret is synced to
+type VaultContext struct {
+	// Path of the item, relative to the prefix the tool operates under
+	Path string `json:"path"`
+}
+
 type SecretConfig struct {
 	From map[string]ItemContext `json:"from"`
 	To   []SecretContext        `json:"to"`
+	// ToVault are the items in Vault the secret is additionally synced to,
+	// for consumers outside of the clusters
+	ToVault []VaultContext `json:"to_vault,omitempty"`
 }
 
 // LoadConfigFromFile renders a Config object loaded from the given file
@@ -85,6 +113,9 @@ type Config struct {
 	ClusterGroups             map[string][]string `json:"cluster_groups,omitempty"`
 	Secrets                   []SecretConfig      `json:"secret_configs"`
 	UserSecretsTargetClusters []string            `json:"user_secrets_target_clusters,omitempty"`
+	// ExternalItems are the items that are not produced by ci-secret-generator,
+	// for example because they are managed by hand
+	ExternalItems []string `json:"external_items,omitempty"`
 }
 
 type configWithoutUnmarshaler Config
@@ -104,6 +135,7 @@ func (c *Config) MarshalJSON() ([]byte, error) {
 		VaultDPTPPrefix:           c.VaultDPTPPrefix,
 		ClusterGroups:             c.ClusterGroups,
 		UserSecretsTargetClusters: c.UserSecretsTargetClusters,
+		ExternalItems:             c.ExternalItems,
 	}
 	pre := c.VaultDPTPPrefix + "/"
 	var secrets []SecretConfig
@@ -185,6 +217,41 @@ func (c *Config) Validate() error {
 	return utilerrors.NewAggregate(errs)
 }
 
+// ValidateGenerated validates that every field the secrets are populated from is produced by
+// the generator config, unless its item is external
+func (c *Config) ValidateGenerated(generator secretgenerator.Config) error {
+	var errs []error
+	external := sets.New[string](c.ExternalItems...)
+	for _, item := range sets.List(external) {
+		if generator.IsItemGenerated(item) {
+			errs = append(errs, fmt.Errorf("external item %s is generated", item))
+		}
+	}
+	for idx, secret := range c.Secrets {
+		for _, key := range sets.List(sets.KeySet(secret.From)) {
+			from := secret.From[key]
+			type reference struct{ item, field string }
+			var references []reference
+			if from.Item != "" {
+				references = append(references, reference{item: from.Item, field: from.Field})
+			}
+			for _, data := range from.DockerConfigJSONData {
+				for _, field := range data.Fields() {
+					references = append(references, reference{item: data.Item, field: field})
+				}
+			}
+			for _, ref := range references {
+				item := strings.TrimPrefix(ref.item, c.VaultDPTPPrefix+"/")
+				if external.Has(item) || generator.IsFieldGenerated(item, ref.field) {
+					continue
+				}
+				errs = append(errs, fmt.Errorf("secrets.%d.from.%s: field %s of item %s is not generated and the item is not external", idx, key, ref.field, item))
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
 func (c *Config) resolve() error {
 	var errs []error
 
@@ -12,6 +12,7 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/yaml"
 
+	"github.com/openshift/ci-tools/pkg/api/secretgenerator"
 	"github.com/openshift/ci-tools/pkg/testhelper"
 )
 
@@ -433,3 +434,69 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateGenerated(t *testing.T) {
+	generator := secretgenerator.Config{
+		{ItemName: "generated", Fields: []secretgenerator.FieldGenerator{{Name: "token"}, {Name: "auth"}}},
+	}
+	testCases := []struct {
+		name     string
+		config   Config
+		expected error
+	}{
+		{
+			name: "generated fields",
+			config: Config{
+				VaultDPTPPrefix: "dptp",
+				Secrets: []SecretConfig{{From: map[string]ItemContext{
+					"token": {Item: "dptp/generated", Field: "token"},
+					".dockerconfigjson": {DockerConfigJSONData: []DockerConfigJSONData{
+						{Item: "dptp/generated", RegistryURL: "quay.io", AuthField: "auth"},
+					}},
+				}}},
+			},
+		},
+		{
+			name: "fields of external items",
+			config: Config{
+				ExternalItems: []string{"by-hand"},
+				Secrets: []SecretConfig{{From: map[string]ItemContext{
+					"token": {Item: "by-hand", Field: "token"},
+				}}},
+			},
+		},
+		{
+			name: "fields that are not generated",
+			config: Config{
+				Secrets: []SecretConfig{
+					{From: map[string]ItemContext{
+						"token": {Item: "generated", Field: "tokn"},
+						"other": {Item: "by-hand", Field: "token"},
+					}},
+					{From: map[string]ItemContext{
+						".dockerconfigjson": {DockerConfigJSONData: []DockerConfigJSONData{
+							{Item: "generated", RegistryURL: "quay.io", UsernameField: "user", PasswordField: "token"},
+						}},
+					}},
+				},
+			},
+			expected: utilerrors.NewAggregate([]error{
+				fmt.Errorf("secrets.0.from.other: field token of item by-hand is not generated and the item is not external"),
+				fmt.Errorf("secrets.0.from.token: field tokn of item generated is not generated and the item is not external"),
+				fmt.Errorf("secrets.1.from..dockerconfigjson: field user of item generated is not generated and the item is not external"),
+			}),
+		},
+		{
+			name:     "generated items cannot be external",
+			config:   Config{ExternalItems: []string{"generated"}},
+			expected: utilerrors.NewAggregate([]error{fmt.Errorf("external item generated is generated")}),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, tc.config.ValidateGenerated(generator), testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+		})
+	}
+}
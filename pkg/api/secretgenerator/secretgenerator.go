@@ -1,41 +1,136 @@
 package secretgenerator
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/getlantern/deepcopy"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	prowv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
 	"sigs.k8s.io/yaml"
 
 	"github.com/openshift/ci-tools/pkg/util/gzip"
 )
 
+// LoadConfigFromPath loads the config from a file or, if the path is a directory, from all
+// YAML files directly in it. Includes of items are resolved relative to their file.
 func LoadConfigFromPath(path string) (Config, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return loadConfigFromFile(path)
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
 	var config Config
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || (filepath.Ext(entry.Name()) != ".yaml" && filepath.Ext(entry.Name()) != ".yml") {
+			continue
+		}
+		filePath := filepath.Join(path, entry.Name())
+		items, err := loadConfigFromFile(filePath)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to load %s: %w", filePath, err))
+			continue
+		}
+		config = append(config, items...)
+	}
+	return config, utilerrors.NewAggregate(errs)
+}
+
+// ConfigAPIVersion is the version of the config files
+const ConfigAPIVersion = "v1"
+
+// configFile is a config file declaring its version. Files with just the list of
+// items are still accepted as the current version.
+type configFile struct {
+	APIVersion string       `json:"apiVersion"`
+	Items      []SecretItem `json:"items"`
+}
+
+// unmarshalConfigFile unmarshals the items of a config file, rejecting unknown keys
+func unmarshalConfigFile(data []byte) ([]SecretItem, error) {
+	raw, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) == 0 || trimmed[0] != '{' {
+		var items []SecretItem
+		if err := yaml.UnmarshalStrict(data, &items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+	var file configFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, err
+	}
+	if file.APIVersion != ConfigAPIVersion {
+		return nil, fmt.Errorf("unsupported apiVersion %q, must be %s", file.APIVersion, ConfigAPIVersion)
+	}
+	return file.Items, nil
+}
+
+func loadConfigFromFile(path string) (Config, error) {
 	cfgBytes, err := gzip.ReadFileMaybeGZIP(path)
 	if err != nil {
 		return nil, err
 	}
-	if err := yaml.Unmarshal(cfgBytes, &config); err != nil {
+	items, err := unmarshalConfigFile(cfgBytes)
+	if err != nil {
 		return nil, err
 	}
-	return config, nil
+	for i := range items {
+		if err := items[i].resolveIncludes(filepath.Dir(path)); err != nil {
+			return nil, err
+		}
+		if err := items[i].resolveParamsFrom(filepath.Dir(path)); err != nil {
+			return nil, err
+		}
+	}
+	return expandItems(items)
 }
 
 type Config []SecretItem
 
 func (c *Config) UnmarshalJSON(data []byte) error {
-	var errs []error
-	var newConfig Config
-
 	var config []SecretItem
-	if err := json.Unmarshal(data, &config); err != nil {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
 		return err
 	}
+	for _, si := range config {
+		if len(si.Include) > 0 {
+			return fmt.Errorf("item %s: include is only supported when loading the config from a path", si.ItemName)
+		}
+		if len(si.ParamsFrom) > 0 {
+			return fmt.Errorf("item %s: params_from is only supported when loading the config from a path", si.ItemName)
+		}
+	}
+
+	newConfig, err := expandItems(config)
+	*c = newConfig
+	return err
+}
 
+// expandItems generates the items of the config from the params of each item
+func expandItems(config []SecretItem) (Config, error) {
+	var errs []error
+	var newConfig Config
 	for _, si := range config {
 		items, err := si.generateItemsFromParams()
 		if err != nil {
@@ -43,9 +138,7 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 		}
 		newConfig = append(newConfig, items...)
 	}
-	*c = newConfig
-
-	return utilerrors.NewAggregate(errs)
+	return newConfig, utilerrors.NewAggregate(errs)
 }
 
 func (c Config) itemsByName() map[string][]SecretItem {
@@ -79,17 +172,283 @@ func (c Config) IsFieldGenerated(name, component string) bool {
 	return false
 }
 
+// GeneratorType is a generator built into the tool, used instead of a command
+type GeneratorType string
+
+const (
+	// GeneratorRSAKey generates a PEM-encoded RSA private key
+	GeneratorRSAKey GeneratorType = "rsa-key"
+	// GeneratorRandomString generates a random string
+	GeneratorRandomString GeneratorType = "random-string"
+	// GeneratorSSHKeypair generates an ed25519 SSH private key in the field and its
+	// public key in the authorized_keys format in the field suffixed with .pub
+	GeneratorSSHKeypair GeneratorType = "ssh-keypair"
+)
+
+const (
+	// GeneratedAtField is the field of an item with a rotation period storing when its
+	// secrets were last changed, in RFC 3339
+	GeneratedAtField = "generated_at"
+	// ExpiresAtField is the field of an item with an expiry or a rotation period storing
+	// when its secrets expire, in RFC 3339
+	ExpiresAtField = "expires_at"
+)
+
+// OutputFormat is a format the generated value of a field has to be valid in
+type OutputFormat string
+
+const (
+	// OutputFormatBase64 requires the value to be base64-encoded
+	OutputFormatBase64 OutputFormat = "base64"
+	// OutputFormatJSON requires the value to be JSON
+	OutputFormatJSON OutputFormat = "json"
+	// OutputFormatPEM requires the value to contain a PEM block
+	OutputFormatPEM OutputFormat = "pem"
+)
+
+// NotesMode is how the notes of an item are combined with the notes it already has
+type NotesMode string
+
+const (
+	// NotesModeReplace replaces the current notes, the default
+	NotesModeReplace NotesMode = "replace"
+	// NotesModeAppend appends the notes to the current ones, unless they already contain them
+	NotesModeAppend NotesMode = "append"
+	// NotesModeSetIfEmpty only sets the notes if the item has none yet
+	NotesModeSetIfEmpty NotesMode = "set-if-empty"
+)
+
 type FieldGenerator struct {
-	Name    string `json:"name,omitempty"`
-	Cmd     string `json:"cmd,omitempty"`
-	Cluster string `json:"-"`
+	Name string `json:"name,omitempty"`
+	// Cmd is a command run by bash. Params substituted into it must not contain characters
+	// with a special meaning to the shell.
+	Cmd string `json:"cmd,omitempty"`
+	// Args are the program and arguments of a command run without a shell, set by a list
+	// in cmd instead of a string
+	Args []string `json:"-"`
+	// File is the path to a file whose content is the value, instead of Cmd
+	File string `json:"file,omitempty"`
+	// URL is a URL whose content is the value, instead of Cmd
+	URL string `json:"url,omitempty"`
+	// SHA256 is the hex-encoded SHA-256 checksum the content of URL has to match
+	SHA256 string `json:"sha256,omitempty"`
+	// Type is the built-in generator to use instead of Cmd
+	Type GeneratorType `json:"type,omitempty"`
+	// Length is the length of a random-string, 32 by default
+	Length int `json:"length,omitempty"`
+	// Charset are the characters of a random-string, letters and digits by default
+	Charset string `json:"charset,omitempty"`
+	// Bits is the size of an rsa-key, 4096 by default
+	Bits int `json:"bits,omitempty"`
+	// Timeout is the maximal duration of the command, overriding the timeout of the item
+	Timeout *prowv1.Duration `json:"timeout,omitempty"`
+	// ValidationRegex is a regular expression the generated value has to match
+	ValidationRegex string `json:"validation_regex,omitempty"`
+	// MinLength is the minimal length of the generated value
+	MinLength int `json:"min_length,omitempty"`
+	// Format is the format the generated value has to be valid in
+	Format  OutputFormat `json:"format,omitempty"`
+	Cluster string       `json:"-"`
 }
 
+// ParamSource is where the values of a param are read from, one value per line. Exactly one
+// of its fields has to be set.
+type ParamSource struct {
+	// FromCmd is a command whose output are the values
+	FromCmd string `json:"from_cmd,omitempty"`
+	// FromFile is the path to a file with the values, relative to the file of the item
+	FromFile string `json:"from_file,omitempty"`
+}
+
+// fieldGenerator is a FieldGenerator without its methods, to marshal and unmarshal it
+type fieldGenerator FieldGenerator
+
+// fieldGeneratorJSON is a FieldGenerator whose cmd is either a string or a list
+type fieldGeneratorJSON struct {
+	fieldGenerator
+	Cmd json.RawMessage `json:"cmd,omitempty"`
+}
+
+func (f *FieldGenerator) UnmarshalJSON(data []byte) error {
+	var raw fieldGeneratorJSON
+	// unknown keys are rejected, as the strictness of the decoder is not passed on
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+	*f = FieldGenerator(raw.fieldGenerator)
+	if len(raw.Cmd) == 0 || string(raw.Cmd) == "null" {
+		return nil
+	}
+	if raw.Cmd[0] == '[' {
+		if err := json.Unmarshal(raw.Cmd, &f.Args); err != nil {
+			return fmt.Errorf("cmd: %w", err)
+		}
+		if len(f.Args) == 0 {
+			return fmt.Errorf("cmd: must not be an empty list")
+		}
+		return nil
+	}
+	if err := json.Unmarshal(raw.Cmd, &f.Cmd); err != nil {
+		return fmt.Errorf("cmd: must be a string or a list of strings: %w", err)
+	}
+	return nil
+}
+
+func (f FieldGenerator) MarshalJSON() ([]byte, error) {
+	raw := fieldGeneratorJSON{fieldGenerator: fieldGenerator(f)}
+	var cmd interface{}
+	if len(f.Args) > 0 {
+		cmd = f.Args
+	} else if f.Cmd != "" {
+		cmd = f.Cmd
+	}
+	if cmd != nil {
+		var err error
+		if raw.Cmd, err = json.Marshal(cmd); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(raw)
+}
+
+// Command returns the command of the field for logs and errors
+func (f FieldGenerator) Command() string {
+	if len(f.Args) > 0 {
+		return strings.Join(f.Args, " ")
+	}
+	return f.Cmd
+}
+
+// shellSafeParam matches the values of params that can be substituted into commands run by a shell
+var shellSafeParam = regexp.MustCompile(`^[a-zA-Z0-9_.,:/@%+=-]*$`)
+
 type SecretItem struct {
 	ItemName string              `json:"item_name"`
 	Fields   []FieldGenerator    `json:"fields,omitempty"`
 	Notes    string              `json:"notes,omitempty"`
 	Params   map[string][]string `json:"params,omitempty"`
+	// Owner is the team owning the secrets of the item, an alias of the OWNERS_ALIASES file
+	// passed to the tool. It is written into the notes along with Notify.
+	Owner string `json:"owner,omitempty"`
+	// Notify are the teams to notify about changes to the secrets of the item
+	Notify []string `json:"notify,omitempty"`
+	// NotesMode is how the notes are combined with the current notes of the item, so
+	// that automation can add to notes written by humans without overwriting them
+	NotesMode NotesMode `json:"notes_mode,omitempty"`
+	// Timeout is the maximal duration of the commands of the fields
+	Timeout *prowv1.Duration `json:"timeout,omitempty"`
+	// Expiry is when the secrets of the item expire, e.g., for tokens issued by a third party
+	// with a fixed lifetime. It is stored in the expires_at field of the item.
+	Expiry *metav1.Time `json:"expiry,omitempty"`
+	// Exclude are combinations of params that are not generated, e.g., a cluster that does not
+	// run on a cloud. An item is excluded if its params have all values of any of the combinations.
+	Exclude []map[string]string `json:"exclude,omitempty"`
+	// ParamsFrom are params whose values are read when the config is loaded, e.g., the
+	// clusters that currently exist. They are merged into Params, and a param may only be defined once.
+	ParamsFrom map[string]ParamSource `json:"params_from,omitempty"`
+	// Include are paths to YAML files, relative to the file of the item, with params shared
+	// by several items. They are merged into Params, and a param may only be defined once.
+	Include []string `json:"include,omitempty"`
+	// RotationPeriod is how long the secrets of the item may be used before they have to be
+	// rotated. The time they were last changed is stored in the generated_at field of the
+	// item, and the time they expire in its expires_at field.
+	RotationPeriod *prowv1.Duration `json:"rotation_period,omitempty"`
+}
+
+// resolveIncludes merges the params of the included files into the params of the item
+func (si *SecretItem) resolveIncludes(dir string) error {
+	for _, include := range si.Include {
+		path := include
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, include)
+		}
+		data, err := gzip.ReadFileMaybeGZIP(path)
+		if err != nil {
+			return fmt.Errorf("item %s: failed to read include %s: %w", si.ItemName, include, err)
+		}
+		var params map[string][]string
+		if err := yaml.UnmarshalStrict(data, &params); err != nil {
+			return fmt.Errorf("item %s: failed to unmarshal include %s: %w", si.ItemName, include, err)
+		}
+		if si.Params == nil && len(params) > 0 {
+			si.Params = map[string][]string{}
+		}
+		for name, values := range params {
+			if _, ok := si.Params[name]; ok {
+				return fmt.Errorf("item %s: param %s of include %s is already defined", si.ItemName, name, include)
+			}
+			si.Params[name] = values
+		}
+	}
+	si.Include = nil
+	return nil
+}
+
+// resolveParamsFrom reads the values of the params of the item from their sources and merges
+// them into the params of the item
+func (si *SecretItem) resolveParamsFrom(dir string) error {
+	for name, source := range si.ParamsFrom {
+		if _, ok := si.Params[name]; ok {
+			return fmt.Errorf("item %s: param %s of params_from is already defined", si.ItemName, name)
+		}
+		var raw []byte
+		var err error
+		switch {
+		case source.FromCmd != "" && source.FromFile != "":
+			return fmt.Errorf("item %s: params_from.%s: from_cmd and from_file are mutually exclusive", si.ItemName, name)
+		case source.FromCmd != "":
+			cmd := exec.Command("bash", "-o", "errexit", "-o", "nounset", "-o", "pipefail", "-c", source.FromCmd)
+			var errBuf bytes.Buffer
+			cmd.Stderr = &errBuf
+			if raw, err = cmd.Output(); err != nil {
+				return fmt.Errorf("item %s: params_from.%s: failed to run %q: %w: %s", si.ItemName, name, source.FromCmd, err, errBuf.String())
+			}
+		case source.FromFile != "":
+			path := source.FromFile
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			if raw, err = gzip.ReadFileMaybeGZIP(path); err != nil {
+				return fmt.Errorf("item %s: params_from.%s: failed to read %s: %w", si.ItemName, name, source.FromFile, err)
+			}
+		default:
+			return fmt.Errorf("item %s: params_from.%s: one of from_cmd or from_file is required", si.ItemName, name)
+		}
+		var values []string
+		for _, line := range strings.Split(string(raw), "\n") {
+			if value := strings.TrimSpace(line); value != "" {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("item %s: params_from.%s: no values", si.ItemName, name)
+		}
+		if si.Params == nil {
+			si.Params = map[string][]string{}
+		}
+		si.Params[name] = values
+	}
+	si.ParamsFrom = nil
+	return nil
+}
+
+// isExcluded returns whether the params have all values of any of the exclusions
+func isExcluded(params map[string]string, exclusions []map[string]string) bool {
+	for _, exclusion := range exclusions {
+		matches := true
+		for name, value := range exclusion {
+			if params[name] != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
 }
 
 func (si SecretItem) generateItemsFromParams() ([]SecretItem, error) {
@@ -100,11 +459,43 @@ func (si SecretItem) generateItemsFromParams() ([]SecretItem, error) {
 		return strings.ReplaceAll(template, fmt.Sprintf("$(%s)", paramName), param)
 	}
 
+	for i, exclusion := range si.Exclude {
+		if len(exclusion) == 0 {
+			errs = append(errs, fmt.Errorf("item %s: exclude[%d]: must not be empty", si.ItemName, i))
+		}
+		for paramName := range exclusion {
+			if _, ok := si.Params[paramName]; !ok {
+				errs = append(errs, fmt.Errorf("item %s: exclude[%d]: unknown param %s", si.ItemName, i, paramName))
+			}
+		}
+	}
+
+	for paramName, params := range si.Params {
+		for _, field := range si.Fields {
+			if !strings.Contains(field.Cmd, fmt.Sprintf("$(%s)", paramName)) {
+				continue
+			}
+			for _, param := range params {
+				if !shellSafeParam.MatchString(param) {
+					errs = append(errs, fmt.Errorf("item %s: field %s: value %q of param %s contains characters with a special meaning to the shell, use a list in cmd to run the command without a shell", si.ItemName, field.Name, param, paramName))
+				}
+			}
+		}
+	}
+
 	itemsProcessingHolder := []SecretItem{si}
+	// paramsOfItems are the values of the params of the items being processed
+	paramsOfItems := []map[string]string{{}}
 	for paramName, params := range si.Params {
 		itemsProcessed := []SecretItem{}
-		for _, qItem := range itemsProcessingHolder {
+		paramsProcessed := []map[string]string{}
+		for j, qItem := range itemsProcessingHolder {
 			for _, param := range params {
+				itemParams := map[string]string{paramName: param}
+				for name, value := range paramsOfItems[j] {
+					itemParams[name] = value
+				}
+				paramsProcessed = append(paramsProcessed, itemParams)
 				argItem := SecretItem{}
 				err := deepcopy.Copy(&argItem, &qItem)
 				if err != nil {
@@ -114,6 +505,11 @@ func (si SecretItem) generateItemsFromParams() ([]SecretItem, error) {
 				for i, field := range argItem.Fields {
 					argItem.Fields[i].Name = replaceParameter(paramName, param, field.Name)
 					argItem.Fields[i].Cmd = replaceParameter(paramName, param, field.Cmd)
+					for j, arg := range field.Args {
+						argItem.Fields[i].Args[j] = replaceParameter(paramName, param, arg)
+					}
+					argItem.Fields[i].File = replaceParameter(paramName, param, field.File)
+					argItem.Fields[i].URL = replaceParameter(paramName, param, field.URL)
 					if paramName == "cluster" {
 						argItem.Fields[i].Cluster = param
 					}
@@ -123,9 +519,16 @@ func (si SecretItem) generateItemsFromParams() ([]SecretItem, error) {
 			}
 		}
 		itemsProcessingHolder = itemsProcessed
+		paramsOfItems = paramsProcessed
 	}
 	if len(errs) == 0 {
-		processedBwItems = append(processedBwItems, itemsProcessingHolder...)
+		for i, item := range itemsProcessingHolder {
+			if isExcluded(paramsOfItems[i], si.Exclude) {
+				continue
+			}
+			item.Exclude = nil
+			processedBwItems = append(processedBwItems, item)
+		}
 	}
 
 	return processedBwItems, utilerrors.NewAggregate(errs)
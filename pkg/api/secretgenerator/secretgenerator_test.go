@@ -1,11 +1,15 @@
 package secretgenerator
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"sort"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/openshift/ci-tools/pkg/testhelper"
 )
 
@@ -13,6 +17,7 @@ func TestLoadConfigFromPath(t *testing.T) {
 	testcases := []struct {
 		name          string
 		config        string
+		path          string
 		expected      Config
 		expectedError error
 	}{
@@ -28,11 +33,66 @@ func TestLoadConfigFromPath(t *testing.T) {
 		{
 			name: "two parameters with multiple values",
 		},
+		{
+			name: "directory",
+			path: filepath.Join("testdata", "TestLoadConfigFromPath", "directory"),
+		},
+		{
+			name: "include",
+		},
+		{
+			name: "exclude",
+		},
+		{
+			name:          "exclude with an unknown param",
+			expectedError: fmt.Errorf("item item-$(cluster): exclude[0]: unknown param cloud"),
+		},
+		{
+			name: "params from",
+		},
+		{
+			name:          "params from a failing command",
+			expectedError: fmt.Errorf("item item-$(cluster): params_from.cluster: failed to run \"echo oops >&2; exit 1\": exit status 1: oops\n"),
+		},
+		{
+			name:          "params from redefining a param",
+			expectedError: fmt.Errorf("item item-$(cluster): param cluster of params_from is already defined"),
+		},
+		{
+			name: "argv cmd",
+		},
+		{
+			name:          "shell cmd with unsafe param",
+			expectedError: fmt.Errorf(`item item-$(cluster): field token: value "image-pusher; rm -rf /" of param sa contains characters with a special meaning to the shell, use a list in cmd to run the command without a shell`),
+		},
+		{
+			name: "versioned",
+		},
+		{
+			name:          "unsupported api version",
+			expectedError: fmt.Errorf(`unsupported apiVersion "v2", must be v1`),
+		},
+		{
+			name:          "unknown key",
+			expectedError: fmt.Errorf(`error unmarshaling JSON: while decoding JSON: json: unknown field "attachements"`),
+		},
+		{
+			name:          "unknown key of a field",
+			expectedError: fmt.Errorf(`error unmarshaling JSON: while decoding JSON: json: unknown field "commmand"`),
+		},
+		{
+			name:          "include redefining a param",
+			expectedError: fmt.Errorf("item Item$(cluster): param cluster of include include/clusters.yaml is already defined"),
+		},
 	}
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			actualConfig, err := LoadConfigFromPath(filepath.Join("testdata", fmt.Sprintf("%s.yaml", t.Name())))
+			path := tc.path
+			if path == "" {
+				path = filepath.Join("testdata", fmt.Sprintf("%s.yaml", t.Name()))
+			}
+			actualConfig, err := LoadConfigFromPath(path)
 			if (tc.expectedError == nil) != (err == nil) {
 				t.Fatalf("%s: expecting error \"%v\", got \"%v\"", t.Name(), tc.expectedError, err)
 			} else if tc.expectedError != nil && err != nil && tc.expectedError.Error() != err.Error() {
@@ -51,3 +111,55 @@ func TestLoadConfigFromPath(t *testing.T) {
 		})
 	}
 }
+
+func TestFieldGeneratorJSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		raw      string
+		expected FieldGenerator
+		err      error
+	}{
+		{
+			name:     "shell cmd",
+			raw:      `{"name":"field","cmd":"echo -n $(cluster)"}`,
+			expected: FieldGenerator{Name: "field", Cmd: "echo -n $(cluster)"},
+		},
+		{
+			name:     "argv cmd",
+			raw:      `{"name":"field","cmd":["echo","-n","$(cluster)"]}`,
+			expected: FieldGenerator{Name: "field", Args: []string{"echo", "-n", "$(cluster)"}},
+		},
+		{
+			name:     "no cmd",
+			raw:      `{"name":"field","type":"random-string"}`,
+			expected: FieldGenerator{Name: "field", Type: GeneratorRandomString},
+		},
+		{
+			name: "empty argv cmd",
+			raw:  `{"name":"field","cmd":[]}`,
+			err:  errors.New("cmd: must not be an empty list"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var field FieldGenerator
+			err := json.Unmarshal([]byte(tc.raw), &field)
+			if diff := cmp.Diff(tc.err, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Fatalf("error differs from expected: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.expected, field); diff != "" {
+				t.Errorf("field differs from expected: %s", diff)
+			}
+			raw, err := json.Marshal(field)
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+			if diff := cmp.Diff(tc.raw, string(raw)); diff != "" {
+				t.Errorf("marshalled field differs from expected: %s", diff)
+			}
+		})
+	}
+}
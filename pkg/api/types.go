@@ -168,7 +168,7 @@ func (config ReleaseBuildConfiguration) BuildsImage(name string) bool {
 }
 
 // IsBaseImage checks if `name` will be a tag in the pipeline image stream
-// by virtue of being imported as a base image
+// by virtue of being imported as a base image or an external image
 func (config ReleaseBuildConfiguration) IsBaseImage(name string) bool {
 	for i := range config.BaseImages {
 		if i == name {
@@ -180,6 +180,9 @@ func (config ReleaseBuildConfiguration) IsBaseImage(name string) bool {
 			return true
 		}
 	}
+	if _, ok := config.ExternalImages[name]; ok {
+		return true
+	}
 	return false
 }
 
@@ -261,6 +264,11 @@ type InputConfiguration struct {
 	// have RPM repositories injected into them for downstream
 	// image builds that require built project RPMs.
 	BaseRPMImages map[string]ImageStreamTagReference `json:"base_rpm_images,omitempty"`
+	// ExternalImages are images outside of image streams, given by their
+	// pull specs. They are resolved to digests when the job starts, so that
+	// all steps use the same image, and are tagged into the pipeline under
+	// their key, which steps can use as a dependency.
+	ExternalImages map[string]ExternalImage `json:"external_images,omitempty"`
 
 	// BuildRootImage supports two ways to get the image that
 	// the pipeline will caches on. The one way is to take the reference
@@ -287,6 +295,12 @@ type InputConfiguration struct {
 	Releases map[string]UnresolvedRelease `json:"releases,omitempty"`
 }
 
+// ExternalImage is an image outside of image streams.
+type ExternalImage struct {
+	// PullSpec is the pull spec of the image, which may be a tag.
+	PullSpec string `json:"pull_spec"`
+}
+
 // UnresolvedRelease describes a semantic release payload
 // identifier we need to resolve to a pull spec.
 type UnresolvedRelease struct {
@@ -862,11 +876,56 @@ type ClusterClaim struct {
 	Owner string `json:"owner"`
 	// Labels is the labels to select the cluster pools
 	Labels map[string]string `json:"labels,omitempty"`
+	// Selector is the labels to select the cluster pools by alone, for the
+	// pools which are not described by a product, version, architecture,
+	// cloud and owner, e.g. the ones providing hosted clusters. It cannot be
+	// set together with any of those or with labels.
+	Selector map[string]string `json:"selector,omitempty"`
 	// Timeout is how long ci-operator will wait for the cluster to be ready.
 	// Defaults to 1h.
 	Timeout *prowv1.Duration `json:"timeout,omitempty"`
 }
 
+const (
+	// ClusterClaimPoolEnv is the variable holding the name of the cluster
+	// pool a cluster was claimed from
+	ClusterClaimPoolEnv = "CLUSTER_CLAIM_POOL"
+	// ClusterClaimPoolNamespaceEnv is the variable holding the namespace of
+	// the cluster pool a cluster was claimed from
+	ClusterClaimPoolNamespaceEnv = "CLUSTER_CLAIM_POOL_NAMESPACE"
+	// ClusterClaimPoolLabelsEnv is the variable holding the labels of the
+	// cluster pool a cluster was claimed from, as comma-separated key=value
+	// pairs
+	ClusterClaimPoolLabelsEnv = "CLUSTER_CLAIM_POOL_LABELS"
+)
+
+// ClusterClaimEnv are the variables the parameters of a cluster claim are
+// exposed to the steps of the test in
+var ClusterClaimEnv = []string{ClusterClaimPoolEnv, ClusterClaimPoolNamespaceEnv, ClusterClaimPoolLabelsEnv}
+
+// PoolSelector returns the labels of the cluster pools the cluster can be
+// claimed from
+func (c *ClusterClaim) PoolSelector() map[string]string {
+	if len(c.Selector) != 0 {
+		selector := make(map[string]string, len(c.Selector))
+		for k, v := range c.Selector {
+			selector[k] = v
+		}
+		return selector
+	}
+	selector := map[string]string{
+		"product":      string(c.Product),
+		"version":      c.Version,
+		"architecture": string(c.Architecture),
+		"cloud":        string(c.Cloud),
+		"owner":        c.Owner,
+	}
+	for k, v := range c.Labels {
+		selector[k] = v
+	}
+	return selector
+}
+
 type ClaimRelease struct {
 	ReleaseName  string
 	OverrideName string
@@ -982,6 +1041,59 @@ type Observer struct {
 	GracePeriod *prowv1.Duration `json:"grace_period,omitempty"`
 	// Environment has the values of parameters for the observer.
 	Environment []StepParameter `json:"env,omitempty"`
+	// Readiness, when set, holds what the observer observes until the observer
+	// is ready to do so.
+	Readiness *ObserverReadiness `json:"readiness,omitempty"`
+	// Scope is what the observer observes: the whole test by default or, with
+	// `step`, only the steps which list it in their `observers`.
+	Scope ObserverScope `json:"scope,omitempty"`
+}
+
+// ObserverScope is what an observer observes
+type ObserverScope string
+
+const (
+	// ObserverScopeTest observers run while the pre and test steps run
+	ObserverScopeTest ObserverScope = "test"
+	// ObserverScopeStep observers run while each of the steps which list
+	// them in their `observers` runs
+	ObserverScopeStep ObserverScope = "step"
+)
+
+// ObserverReadiness determines when an observer is ready to observe
+type ObserverReadiness struct {
+	// Commands is run in the observer container until it succeeds, the
+	// observer is ready from then on.
+	Commands string `json:"commands"`
+	// Timeout is how long we wait for the observer to become ready before
+	// going on without it. Defaults to 10 minutes.
+	Timeout *prowv1.Duration `json:"timeout,omitempty"`
+}
+
+// ObserverTerminationFileEnv is the variable holding the path of the file an
+// ObserverTermination is written to before the observer is signalled to
+// terminate.
+const ObserverTerminationFileEnv = "OBSERVER_TERMINATION_FILE"
+
+// ObserverOutcome is how what an observer observed ended
+type ObserverOutcome string
+
+const (
+	ObserverOutcomeSucceeded ObserverOutcome = "succeeded"
+	ObserverOutcomeFailed    ObserverOutcome = "failed"
+	ObserverOutcomeCancelled ObserverOutcome = "cancelled"
+)
+
+// ObserverTermination tells an observer how what it observed ended.
+type ObserverTermination struct {
+	// Phase is the phase of the test the observer observed last, `pre` or
+	// `test` for the observers of the test.
+	Phase string `json:"phase"`
+	// Step is the step the observer observed, only set for the observers of
+	// steps.
+	Step string `json:"step,omitempty"`
+	// Outcome is how the phase, or the step, ended.
+	Outcome ObserverOutcome `json:"outcome"`
 }
 
 // Observers is a configuration for which observer pods should and should not
@@ -1040,11 +1152,49 @@ type LiteralTestStep struct {
 	// Cli is the (optional) name of the release from which the `oc` binary
 	// will be injected into this step.
 	Cli string `json:"cli,omitempty"`
-	// Observers are the observers that should be running
+	// Observers are the observers that should be running. Those with the
+	// `step` scope only run while this step runs.
 	Observers []string `json:"observers,omitempty"`
 	// RunAsScript defines if this step should be executed as a script mounted
 	// in the test container instead of being executed directly via bash
 	RunAsScript *bool `json:"run_as_script,omitempty"`
+	// Retries defines if and how many times this step is run again when
+	// it fails, without running the whole test again.
+	Retries *StepRetries `json:"retries,omitempty"`
+}
+
+// StepRetries defines how a step is retried when it fails. Every attempt
+// after the first one runs in its own pod and its artifacts are saved in the
+// attempt-<number> directory of the artifacts of the step.
+type StepRetries struct {
+	// Count is how many times the step is run again after its first attempt.
+	Count int `json:"count"`
+	// On lists the outcomes of an attempt the step is retried on. Defaults
+	// to both failure and timeout.
+	On []StepRetryOutcome `json:"on,omitempty"`
+}
+
+// StepRetryOutcome is an outcome of an attempt of a step it can be retried on.
+type StepRetryOutcome string
+
+const (
+	// StepRetryOnFailure retries the step when its command fails.
+	StepRetryOnFailure StepRetryOutcome = "failure"
+	// StepRetryOnTimeout retries the step when it does not finish before its timeout.
+	StepRetryOnTimeout StepRetryOutcome = "timeout"
+)
+
+// RetriesOn determines whether the step is retried on the outcome.
+func (r *StepRetries) RetriesOn(outcome StepRetryOutcome) bool {
+	if len(r.On) == 0 {
+		return true
+	}
+	for _, on := range r.On {
+		if on == outcome {
+			return true
+		}
+	}
+	return false
 }
 
 // StepParameter is a variable set by the test, with an optional default.
@@ -1157,6 +1307,9 @@ type MultiStageTestConfiguration struct {
 	// DependencyOverrides allows a step to override a dependency with a fully-qualified pullspec. This will probably only ever
 	// be used with rehearsals. Otherwise, the overrides should be passed in as parameters to ci-operator.
 	DependencyOverrides DependencyOverrides `json:"dependency_overrides,omitempty"`
+	// Cache is a volume shared between the steps which, unlike the shared directory, is not limited in size.
+	// It is mounted at $CACHE_DIR, the observers do not mount it.
+	Cache *StepCache `json:"cache,omitempty"`
 }
 type DependencyOverrides map[string]string
 
@@ -1194,11 +1347,25 @@ type MultiStageTestConfigurationLiteral struct {
 	// DependencyOverrides allows a step to override a dependency with a fully-qualified pullspec. This will probably only ever
 	// be used with rehearsals. Otherwise, the overrides should be passed in as parameters to ci-operator.
 	DependencyOverrides DependencyOverrides `json:"dependency_overrides,omitempty"`
+	// Cache is a volume shared between the steps which, unlike the shared directory, is not limited in size.
+	// It is mounted at $CACHE_DIR, the observers do not mount it.
+	Cache *StepCache `json:"cache,omitempty"`
 
 	// Override job timeout
 	Timeout *prowv1.Duration `json:"timeout,omitempty"`
 }
 
+// StepCache is a volume backed by a persistent volume claim, shared between
+// the steps of a multi-stage test. It holds what is too large for the shared
+// directory, like extracted installers or test binaries.
+type StepCache struct {
+	// Size is the size of the volume, e.g. `10Gi`.
+	Size string `json:"size"`
+	// StorageClass is the storage class of the volume. Defaults to the
+	// default storage class of the cluster.
+	StorageClass string `json:"storage_class,omitempty"`
+}
+
 // TestEnvironment has the values of parameters for multi-stage tests.
 type TestEnvironment map[string]string
 
@@ -2253,6 +2420,21 @@ type ProjectDirectoryImageBuildStepConfiguration struct {
 
 	// Ref is an optional string linking to the extra_ref in "org.repo" format that this belongs to
 	Ref string `json:"ref,omitempty"`
+
+	// Architectures are the architectures the image is built for. The image is built
+	// on the nodes of each architecture and pushed as a manifest list. When unset, the
+	// image is built for all the architectures of the nodes of the build cluster.
+	Architectures []ReleaseArchitecture `json:"architectures,omitempty"`
+}
+
+// BuildsMultiArch determines if the image is built for an architecture other than amd64.
+func (config ProjectDirectoryImageBuildStepConfiguration) BuildsMultiArch() bool {
+	for _, arch := range config.Architectures {
+		if arch != ReleaseArchitectureAMD64 {
+			return true
+		}
+	}
+	return false
 }
 
 func (config ProjectDirectoryImageBuildStepConfiguration) TargetName() string {
@@ -2417,6 +2599,14 @@ func (a ReleaseArchitecture) GetMappedCluster() Cluster {
 	return c
 }
 
+// ImageArchitectures are the architectures images can be built for.
+var ImageArchitectures = sets.New[ReleaseArchitecture](
+	ReleaseArchitectureAMD64,
+	ReleaseArchitectureARM64,
+	ReleaseArchitecturePPC64le,
+	ReleaseArchitectureS390x,
+)
+
 func GetAvailableArchitectures() []string {
 	architectures := make([]string, 0, len(archToCluster))
 	for arch := range archToCluster {
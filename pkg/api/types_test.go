@@ -345,3 +345,44 @@ func TestInputImageTagStepConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterClaimPoolSelector(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		claim    ClusterClaim
+		expected map[string]string
+	}{
+		{
+			name: "product, version, architecture, cloud and owner",
+			claim: ClusterClaim{
+				Product:      ReleaseProductOCP,
+				Version:      "4.14",
+				Architecture: ReleaseArchitectureAMD64,
+				Cloud:        CloudAWS,
+				Owner:        "dpp",
+				Labels:       map[string]string{"region": "us-east-1"},
+			},
+			expected: map[string]string{
+				"product":      "ocp",
+				"version":      "4.14",
+				"architecture": "amd64",
+				"cloud":        "aws",
+				"owner":        "dpp",
+				"region":       "us-east-1",
+			},
+		},
+		{
+			name: "selector",
+			claim: ClusterClaim{
+				Selector: map[string]string{"hypershift": "hosted", "architecture": "arm64"},
+			},
+			expected: map[string]string{"hypershift": "hosted", "architecture": "arm64"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, tc.claim.PoolSelector()); diff != "" {
+				t.Errorf("unexpected selector: %s", diff)
+			}
+		})
+	}
+}
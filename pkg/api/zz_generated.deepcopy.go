@@ -141,6 +141,13 @@ func (in *ClusterClaim) DeepCopyInto(out *ClusterClaim) {
 			(*out)[key] = val
 		}
 	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Timeout != nil {
 		in, out := &in.Timeout, &out.Timeout
 		*out = new(v1.Duration)
@@ -297,6 +304,21 @@ func (in DependencyOverrides) DeepCopy() DependencyOverrides {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalImage) DeepCopyInto(out *ExternalImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalImage.
+func (in *ExternalImage) DeepCopy() *ExternalImage {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GraphConfiguration) DeepCopyInto(out *GraphConfiguration) {
 	*out = *in
@@ -426,6 +448,13 @@ func (in *InputConfiguration) DeepCopyInto(out *InputConfiguration) {
 			(*out)[key] = val
 		}
 	}
+	if in.ExternalImages != nil {
+		in, out := &in.ExternalImages, &out.ExternalImages
+		*out = make(map[string]ExternalImage, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.BuildRootImage != nil {
 		in, out := &in.BuildRootImage, &out.BuildRootImage
 		*out = new(BuildRootImageConfiguration)
@@ -604,6 +633,11 @@ func (in *LiteralTestStep) DeepCopyInto(out *LiteralTestStep) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(StepRetries)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LiteralTestStep.
@@ -737,6 +771,11 @@ func (in *MultiStageTestConfiguration) DeepCopyInto(out *MultiStageTestConfigura
 			(*out)[key] = val
 		}
 	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(StepCache)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiStageTestConfiguration.
@@ -821,6 +860,11 @@ func (in *MultiStageTestConfigurationLiteral) DeepCopyInto(out *MultiStageTestCo
 			(*out)[key] = val
 		}
 	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(StepCache)
+		**out = **in
+	}
 	if in.Timeout != nil {
 		in, out := &in.Timeout, &out.Timeout
 		*out = new(v1.Duration)
@@ -864,6 +908,11 @@ func (in *Observer) DeepCopyInto(out *Observer) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ObserverReadiness)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Observer.
@@ -876,6 +925,41 @@ func (in *Observer) DeepCopy() *Observer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObserverReadiness) DeepCopyInto(out *ObserverReadiness) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObserverReadiness.
+func (in *ObserverReadiness) DeepCopy() *ObserverReadiness {
+	if in == nil {
+		return nil
+	}
+	out := new(ObserverReadiness)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObserverTermination) DeepCopyInto(out *ObserverTermination) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObserverTermination.
+func (in *ObserverTermination) DeepCopy() *ObserverTermination {
+	if in == nil {
+		return nil
+	}
+	out := new(ObserverTermination)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Observers) DeepCopyInto(out *Observers) {
 	*out = *in
@@ -1201,6 +1285,11 @@ func (in *ProjectDirectoryImageBuildInputs) DeepCopy() *ProjectDirectoryImageBui
 func (in *ProjectDirectoryImageBuildStepConfiguration) DeepCopyInto(out *ProjectDirectoryImageBuildStepConfiguration) {
 	*out = *in
 	in.ProjectDirectoryImageBuildInputs.DeepCopyInto(&out.ProjectDirectoryImageBuildInputs)
+	if in.Architectures != nil {
+		in, out := &in.Architectures, &out.Architectures
+		*out = make([]ReleaseArchitecture, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectDirectoryImageBuildStepConfiguration.
@@ -1758,6 +1847,21 @@ func (in *SourceStepConfiguration) DeepCopy() *SourceStepConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepCache) DeepCopyInto(out *StepCache) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepCache.
+func (in *StepCache) DeepCopy() *StepCache {
+	if in == nil {
+		return nil
+	}
+	out := new(StepCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StepConfiguration) DeepCopyInto(out *StepConfiguration) {
 	*out = *in
@@ -1928,6 +2032,26 @@ func (in *StepParameter) DeepCopy() *StepParameter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepRetries) DeepCopyInto(out *StepRetries) {
+	*out = *in
+	if in.On != nil {
+		in, out := &in.On, &out.On
+		*out = make([]StepRetryOutcome, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepRetries.
+func (in *StepRetries) DeepCopy() *StepRetries {
+	if in == nil {
+		return nil
+	}
+	out := new(StepRetries)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in TestDependencies) DeepCopyInto(out *TestDependencies) {
 	{
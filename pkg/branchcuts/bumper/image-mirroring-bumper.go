@@ -0,0 +1,92 @@
+package bumper
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/openshift/ci-tools/pkg/api/ocplifecycle"
+)
+
+const (
+	// imageMirroringMappingRegexPatternFormat matches mapping files such as
+	// mapping_origin_4_14 or mapping_origin_4_14_ppc64le
+	imageMirroringMappingRegexPatternFormat = `^mapping_.+_%d_%d(_[a-z0-9]+)?$`
+)
+
+// ImageMirroringBumper creates the image mirroring mapping files of the next release
+// from the mapping files of the current release
+type ImageMirroringBumper struct {
+	mm             *ocplifecycle.MajorMinor
+	getFilesRegexp *regexp.Regexp
+	mappingsDir    string
+}
+
+var _ Bumper[[]string] = &ImageMirroringBumper{}
+
+func NewImageMirroringBumper(ocpVer, mappingsDir string) (*ImageMirroringBumper, error) {
+	mm, err := ocplifecycle.ParseMajorMinor(ocpVer)
+	if err != nil {
+		return nil, fmt.Errorf("parse release: %w", err)
+	}
+	return &ImageMirroringBumper{
+		mm:             mm,
+		getFilesRegexp: regexp.MustCompile(fmt.Sprintf(imageMirroringMappingRegexPatternFormat, mm.Major, mm.Minor)),
+		mappingsDir:    mappingsDir,
+	}, nil
+}
+
+func (b *ImageMirroringBumper) GetFiles() ([]string, error) {
+	files := make([]string, 0)
+	err := fs.WalkDir(os.DirFS(b.mappingsDir), ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && b.getFilesRegexp.MatchString(d.Name()) {
+			files = append(files, path.Join(b.mappingsDir, p))
+		}
+		return nil
+	})
+	return files, err
+}
+
+func (b *ImageMirroringBumper) Unmarshall(file string) ([]string, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", file, err)
+	}
+	return strings.Split(strings.TrimSuffix(string(raw), "\n"), "\n"), nil
+}
+
+func (b *ImageMirroringBumper) BumpFilename(filename string, _ []string) (string, error) {
+	currentRelease := fmt.Sprintf("_%d_%d", b.mm.Major, b.mm.Minor)
+	futureRelease := fmt.Sprintf("_%d_%d", b.mm.Major, b.mm.Minor+1)
+	return strings.Replace(filename, currentRelease, futureRelease, 1), nil
+}
+
+// BumpContent replaces every {major}.{minor} reference in the mappings, so that
+// registry.ci.openshift.org/origin/4.14:cli quay.io/openshift/origin-cli:4.14
+// becomes
+// registry.ci.openshift.org/origin/4.15:cli quay.io/openshift/origin-cli:4.15
+func (b *ImageMirroringBumper) BumpContent(lines []string) ([]string, error) {
+	bumped := make([]string, 0, len(lines))
+	for _, line := range lines {
+		bumpedLine, err := ReplaceWithNextVersion(line, b.mm.Major)
+		if err != nil {
+			return nil, err
+		}
+		bumped = append(bumped, bumpedLine)
+	}
+	return bumped, nil
+}
+
+func (b *ImageMirroringBumper) Marshall(lines []string, bumpedFilename, dir string) error {
+	absolutePath := path.Join(dir, bumpedFilename)
+	if err := os.WriteFile(absolutePath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", absolutePath, err)
+	}
+	return nil
+}
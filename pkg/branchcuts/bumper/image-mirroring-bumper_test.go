@@ -0,0 +1,57 @@
+package bumper_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/openshift/ci-tools/pkg/branchcuts/bumper"
+)
+
+func TestImageMirroringBumper(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"mapping_origin_4_14":         "registry.ci.openshift.org/origin/4.14:cli quay.io/openshift/origin-cli:4.14 quay.io/openshift/origin-cli:4.14.0\n",
+		"mapping_origin_4_14_ppc64le": "registry.ci.openshift.org/origin/4.14-ppc64le:cli quay.io/openshift/origin-cli:4.14-ppc64le\n",
+		"mapping_origin_4_13":         "registry.ci.openshift.org/origin/4.13:cli quay.io/openshift/origin-cli:4.13\n",
+		"mapping_origin_4_140":        "unrelated\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, err := bumper.NewImageMirroringBumper("4.14", dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := bumper.Bump[[]string](b, &bumper.BumpingOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"mapping_origin_4_15":         "registry.ci.openshift.org/origin/4.15:cli quay.io/openshift/origin-cli:4.15 quay.io/openshift/origin-cli:4.15.0\n",
+		"mapping_origin_4_15_ppc64le": "registry.ci.openshift.org/origin/4.15-ppc64le:cli quay.io/openshift/origin-cli:4.15-ppc64le\n",
+	}
+	for name, content := range files {
+		expected[name] = content
+	}
+	actual := map[string]string{}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		raw, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual[entry.Name()] = string(raw)
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("mapping files differ from expected: %s", diff)
+	}
+}
@@ -242,6 +242,24 @@ func GetChangedRegistrySteps(path, baseRev string, graph registry.NodeByName) ([
 	return changes, nil
 }
 
+// GetChangedRegistryFiles returns all files in the registry that were added,
+// modified or deleted since baseRev, relative to the registry root.
+func GetChangedRegistryFiles(path, baseRev string) ([]string, error) {
+	changes, err := diffTree(path, RegistryPath, baseRev, "")
+	if err != nil {
+		return nil, err
+	}
+	var ret []string
+	for _, c := range changes {
+		rel, err := filepath.Rel(RegistryPath, c)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, rel)
+	}
+	return ret, nil
+}
+
 func GetChangedClusterProfiles(path, baseRev string) ([]string, error) {
 	return getRevChanges(path, ClusterProfilesPath, baseRev, false)
 }
@@ -250,18 +268,47 @@ func GetAddedConfigs(path, baseRev string) ([]string, error) {
 	return getRevChanges(path, CiopConfigInRepoPath, baseRev, true)
 }
 
+// GetChangedConfigs returns the ci-operator configuration files that were added or
+// modified since baseRev, relative to the configuration directory.
+func GetChangedConfigs(path, baseRev string) ([]string, error) {
+	changes, err := getRevChanges(path, CiopConfigInRepoPath, baseRev, false)
+	if err != nil {
+		return nil, err
+	}
+	var ret []string
+	for _, c := range changes {
+		rel, err := filepath.Rel(CiopConfigInRepoPath, c)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, rel)
+	}
+	return ret, nil
+}
+
 // getRevChanges returns the name and a hash of the contents of files under
 // `path` that were added/modified since revision `base` in the repository at
 // `root`.  Paths are relative to `root`.
 // If 'ignoreModified' is true it will only check for relevant added, moved, or copied files
 func getRevChanges(root, path, base string, ignoreModified bool) ([]string, error) {
-	// Sample output (with abbreviated hashes) from git-diff-tree(1):
-	// :100644 100644 bcd1234 0123456 M file0
 	filter := "--diff-filter=d"
 	if ignoreModified {
 		filter = "--diff-filter=ACR"
 	}
-	cmd := []string{"diff-tree", "-r", filter, base + ":" + path, "HEAD:" + path}
+	return diffTree(root, path, base, filter)
+}
+
+// diffTree returns the files under `path` that changed since revision `base`
+// and pass the git-diff-tree(1) filter, or all changed files if the filter is
+// empty. Paths are relative to `root`.
+func diffTree(root, path, base, filter string) ([]string, error) {
+	// Sample output (with abbreviated hashes) from git-diff-tree(1):
+	// :100644 100644 bcd1234 0123456 M file0
+	cmd := []string{"diff-tree", "-r"}
+	if filter != "" {
+		cmd = append(cmd, filter)
+	}
+	cmd = append(cmd, base+":"+path, "HEAD:"+path)
 	diff, err := git(root, cmd...)
 	if err != nil || diff == "" {
 		return nil, err
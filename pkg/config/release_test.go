@@ -191,6 +191,28 @@ git add \
 	})
 }
 
+func TestGetChangedRegistryFiles(t *testing.T) {
+	files := []string{
+		"nochanges/file", "changeme/file", "removeme/file", "moveme/file",
+	}
+	cmd := `
+> changeme/file
+git rm --quiet removeme/file
+mkdir new/
+> new/file
+git add new/file
+git mv moveme/file moveme/moved
+`
+	expected := []string{
+		filepath.Join("changeme", "file"),
+		filepath.Join("moveme", "file"),
+		filepath.Join("moveme", "moved"),
+		filepath.Join("new", "file"),
+		filepath.Join("removeme", "file"),
+	}
+	compareChanges(t, RegistryPath, files, cmd, GetChangedRegistryFiles, expected)
+}
+
 func TestGetAddedConfigs(t *testing.T) {
 	files := []string{
 		"nochanges/file", "changeme/file", "removeme/file", "moveme/file",
@@ -214,6 +236,26 @@ git mv renameme/file renamed/file
 	compareChanges(t, CiopConfigInRepoPath, files, cmd, GetAddedConfigs, expected)
 }
 
+func TestGetChangedConfigs(t *testing.T) {
+	files := []string{
+		"nochanges/file", "changeme/file", "removeme/file", "moveme/file",
+	}
+	cmd := `
+> changeme/file
+git rm --quiet removeme/file
+mkdir new/
+> new/file
+git add new/file
+git mv moveme/file moveme/moved
+`
+	expected := []string{
+		filepath.Join("changeme", "file"),
+		filepath.Join("moveme", "moved"),
+		filepath.Join("new", "file"),
+	}
+	compareChanges(t, CiopConfigInRepoPath, files, cmd, GetChangedConfigs, expected)
+}
+
 func TestConfigMapName(t *testing.T) {
 	path := "path/to/a-file.yaml"
 	dnfError := fmt.Errorf("path not covered by any config-updater pattern: path/to/a-file.yaml")
@@ -0,0 +1,278 @@
+package resourcereaper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/test-infra/prow/kube"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+
+	"github.com/openshift/ci-tools/pkg/api/nsttl"
+)
+
+const (
+	ControllerName = "resourcereaper"
+
+	// ciOperatorNamespacePrefix is the prefix of the namespaces created by ci-operator
+	ciOperatorNamespacePrefix = "ci-op-"
+	// defaultClusterClaimLifetime is the lifetime ci-operator requests for its cluster claims
+	defaultClusterClaimLifetime = 4 * time.Hour
+	// idleRecheckInterval is how often a namespace past its soft TTL is checked for running pods
+	idleRecheckInterval = 10 * time.Minute
+
+	reasonSoftTTL = "soft-ttl"
+)
+
+// Options configures the reaper
+type Options struct {
+	// NamespaceTTL is the maximum age of a ci-operator namespace. Namespaces are reaped when
+	// they exceed it, regardless of their TTL annotations.
+	NamespaceTTL time.Duration
+	// ClusterClaimGracePeriod is the time a cluster claim is kept after its lifetime expired
+	ClusterClaimGracePeriod time.Duration
+	// LeaseTTL is the time a lease is kept after it expired
+	LeaseTTL time.Duration
+	// LeaseNamespaces are the namespaces in which expired coordination.k8s.io Lease objects are
+	// reaped. These are the leases left behind by leader election, not Boskos resource leases,
+	// which Boskos reclaims itself.
+	LeaseNamespaces sets.Set[string]
+	// DryRun only logs the resources that would be reaped, they are neither deleted nor counted
+	// as reclaimed
+	DryRun bool
+}
+
+var reclaimedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "dptp_resource_reaper_reclaimed_total",
+	Help: "The number of leaked resources the resource reaper deleted",
+}, []string{"cluster", "kind", "reason"})
+
+// AddToManager adds the reaper for namespaces and leases to every build farm cluster
+// and the reaper for cluster claims to the hive cluster, if it is known
+func AddToManager(mgr manager.Manager, allManagers map[string]manager.Manager, hiveClusterName string, opts Options) error {
+	if err := metrics.Registry.Register(reclaimedCounter); err != nil {
+		return fmt.Errorf("failed to register reclaimedCounter metric: %w", err)
+	}
+	for clusterName, clusterManager := range allManagers {
+		// Pods are only read when a namespace reaches its soft TTL, so they are read directly
+		// instead of caching all pods of the cluster
+		base := reaper{cluster: clusterName, client: clusterManager.GetClient(), podReader: clusterManager.GetAPIReader(), now: time.Now, opts: opts}
+
+		nsController, err := controller.New(ControllerName+"_namespaces_"+clusterName, mgr, controller.Options{
+			Reconciler:              &namespaceReconciler{reaper: base},
+			MaxConcurrentReconciles: 10,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to construct namespace controller for cluster %s: %w", clusterName, err)
+		}
+		isCIOperatorNamespace := predicate.NewPredicateFuncs(func(o ctrlruntimeclient.Object) bool {
+			return strings.HasPrefix(o.GetName(), ciOperatorNamespacePrefix)
+		})
+		if err := nsController.Watch(source.Kind(clusterManager.GetCache(), &corev1.Namespace{}), &handler.EnqueueRequestForObject{}, isCIOperatorNamespace); err != nil {
+			return fmt.Errorf("failed to watch namespaces in cluster %s: %w", clusterName, err)
+		}
+
+		if opts.LeaseNamespaces.Len() > 0 {
+			leaseController, err := controller.New(ControllerName+"_leases_"+clusterName, mgr, controller.Options{
+				Reconciler:              &leaseReconciler{reaper: base},
+				MaxConcurrentReconciles: 10,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to construct lease controller for cluster %s: %w", clusterName, err)
+			}
+			inLeaseNamespaces := predicate.NewPredicateFuncs(func(o ctrlruntimeclient.Object) bool {
+				return opts.LeaseNamespaces.Has(o.GetNamespace())
+			})
+			if err := leaseController.Watch(source.Kind(clusterManager.GetCache(), &coordinationv1.Lease{}), &handler.EnqueueRequestForObject{}, inLeaseNamespaces); err != nil {
+				return fmt.Errorf("failed to watch leases in cluster %s: %w", clusterName, err)
+			}
+		}
+
+		if clusterName != hiveClusterName {
+			continue
+		}
+		if err := hivev1.AddToScheme(clusterManager.GetScheme()); err != nil {
+			return fmt.Errorf("failed to add hivev1 to scheme: %w", err)
+		}
+		claimController, err := controller.New(ControllerName+"_clusterclaims_"+clusterName, mgr, controller.Options{
+			Reconciler:              &clusterClaimReconciler{reaper: base},
+			MaxConcurrentReconciles: 10,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to construct cluster claim controller for cluster %s: %w", clusterName, err)
+		}
+		if err := claimController.Watch(source.Kind(clusterManager.GetCache(), &hivev1.ClusterClaim{}), &handler.EnqueueRequestForObject{}); err != nil {
+			return fmt.Errorf("failed to watch cluster claims in cluster %s: %w", clusterName, err)
+		}
+	}
+
+	return nil
+}
+
+type reaper struct {
+	cluster   string
+	client    ctrlruntimeclient.Client
+	podReader ctrlruntimeclient.Reader
+	now       func() time.Time
+	opts      Options
+}
+
+// reap deletes the object once the deadline has passed and requeues it otherwise
+func (r *reaper) reap(ctx context.Context, obj ctrlruntimeclient.Object, kind string, deadline time.Time, reason string) (reconcile.Result, error) {
+	if remaining := deadline.Sub(r.now()); remaining > 0 {
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+	logger := logrus.WithFields(logrus.Fields{"cluster": r.cluster, "kind": kind, "namespace": obj.GetNamespace(), "name": obj.GetName(), "reason": reason})
+	if r.opts.DryRun {
+		logger.Info("Would reap leaked resource, but running in dry-run")
+		return reconcile.Result{}, nil
+	}
+	logger.Info("Reaping leaked resource")
+	if err := r.client.Delete(ctx, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("failed to delete %s %s/%s: %w", kind, obj.GetNamespace(), obj.GetName(), err)
+	}
+	reclaimedCounter.WithLabelValues(r.cluster, kind, reason).Inc()
+	return reconcile.Result{}, nil
+}
+
+type namespaceReconciler struct {
+	reaper
+}
+
+func (r *namespaceReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	var ns corev1.Namespace
+	if err := r.client.Get(ctx, req.NamespacedName, &ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("failed to get namespace %s: %w", req.Name, err)
+	}
+	if ns.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	deadline, reason := namespaceDeadline(&ns, r.opts.NamespaceTTL, true)
+	if reason == reasonSoftTTL && !deadline.After(r.now()) {
+		running, err := r.hasRunningPods(ctx, ns.Name)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if running {
+			// The soft TTL only applies once all pods completed, so the namespace is
+			// only reaped by the other deadlines until then
+			deadline, reason = namespaceDeadline(&ns, r.opts.NamespaceTTL, false)
+			if remaining := deadline.Sub(r.now()); remaining > idleRecheckInterval {
+				return reconcile.Result{RequeueAfter: idleRecheckInterval}, nil
+			}
+		}
+	}
+	return r.reap(ctx, &ns, "namespace", deadline, reason)
+}
+
+func (r *namespaceReconciler) hasRunningPods(ctx context.Context, namespace string) (bool, error) {
+	var pods corev1.PodList
+	if err := r.podReader.List(ctx, &pods, ctrlruntimeclient.InNamespace(namespace)); err != nil {
+		return false, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// namespaceDeadline determines when a namespace expires: the earliest of its hard TTL, its
+// soft TTL after it was last active, if it is idle, and the maximum age for ci-operator namespaces
+func namespaceDeadline(ns *corev1.Namespace, maxAge time.Duration, idle bool) (time.Time, string) {
+	deadline, reason := ns.CreationTimestamp.Add(maxAge), "max-age"
+	if ttl, err := time.ParseDuration(ns.Annotations[nsttl.AnnotationCleanupDurationTTL]); err == nil {
+		if hard := ns.CreationTimestamp.Add(ttl); hard.Before(deadline) {
+			deadline, reason = hard, "hard-ttl"
+		}
+	}
+	if !idle {
+		return deadline, reason
+	}
+	if ttl, err := time.ParseDuration(ns.Annotations[nsttl.AnnotationIdleCleanupDurationTTL]); err == nil {
+		lastActive := ns.CreationTimestamp.Time
+		if active, err := time.Parse(time.RFC3339, ns.Annotations[nsttl.AnnotationNamespaceLastActive]); err == nil && active.After(lastActive) {
+			lastActive = active
+		}
+		if soft := lastActive.Add(ttl); soft.Before(deadline) {
+			deadline, reason = soft, reasonSoftTTL
+		}
+	}
+	return deadline, reason
+}
+
+type clusterClaimReconciler struct {
+	reaper
+}
+
+func (r *clusterClaimReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	var claim hivev1.ClusterClaim
+	if err := r.client.Get(ctx, req.NamespacedName, &claim); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("failed to get cluster claim %s: %w", req, err)
+	}
+	// Only the claims created by CI jobs are ours to reap
+	if _, isCIClaim := claim.Labels[kube.ProwJobAnnotation]; !isCIClaim || claim.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	lifetime := defaultClusterClaimLifetime
+	if claim.Spec.Lifetime != nil {
+		lifetime = claim.Spec.Lifetime.Duration
+	}
+	return r.reap(ctx, &claim, "clusterclaim", claim.CreationTimestamp.Add(lifetime+r.opts.ClusterClaimGracePeriod), "lifetime")
+}
+
+type leaseReconciler struct {
+	reaper
+}
+
+func (r *leaseReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	var lease coordinationv1.Lease
+	if err := r.client.Get(ctx, req.NamespacedName, &lease); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("failed to get lease %s: %w", req, err)
+	}
+	if lease.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	lastRenewed := lease.CreationTimestamp.Time
+	if lease.Spec.RenewTime != nil && lease.Spec.RenewTime.After(lastRenewed) {
+		lastRenewed = lease.Spec.RenewTime.Time
+	}
+	var duration time.Duration
+	if lease.Spec.LeaseDurationSeconds != nil {
+		duration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+	return r.reap(ctx, &lease, "lease", lastRenewed.Add(duration+r.opts.LeaseTTL), "expired")
+}
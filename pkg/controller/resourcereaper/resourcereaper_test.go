@@ -0,0 +1,315 @@
+package resourcereaper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	dto "github.com/prometheus/client_model/go"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/test-infra/prow/kube"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+
+	"github.com/openshift/ci-tools/pkg/api/nsttl"
+)
+
+var now = time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newReaper(objs ...runtime.Object) reaper {
+	s := runtime.NewScheme()
+	if err := scheme.AddToScheme(s); err != nil {
+		panic(err)
+	}
+	if err := hivev1.AddToScheme(s); err != nil {
+		panic(err)
+	}
+	client := fakectrlruntimeclient.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...).Build()
+	return reaper{
+		cluster:   "build01",
+		client:    client,
+		podReader: client,
+		now:       func() time.Time { return now },
+		opts: Options{
+			NamespaceTTL:            72 * time.Hour,
+			ClusterClaimGracePeriod: time.Hour,
+			LeaseTTL:                24 * time.Hour,
+		},
+	}
+}
+
+func assertExists(t *testing.T, client ctrlruntimeclient.Client, key types.NamespacedName, obj ctrlruntimeclient.Object, expected bool) {
+	t.Helper()
+	err := client.Get(context.Background(), key, obj)
+	if err != nil && !apierrors.IsNotFound(err) {
+		t.Fatalf("failed to get %s: %v", key, err)
+	}
+	if exists := err == nil; exists != expected {
+		t.Errorf("expected %s to exist: %t, did exist: %t", key, expected, exists)
+	}
+}
+
+func TestNamespaceReconcile(t *testing.T) {
+	namespace := func(age time.Duration, annotations map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:              "ci-op-1234",
+			CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			Annotations:       annotations,
+		}}
+	}
+	pod := func(phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: string(phase), Namespace: "ci-op-1234"},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	idle := func(age time.Duration, hardTTL string) *corev1.Namespace {
+		annotations := map[string]string{
+			nsttl.AnnotationIdleCleanupDurationTTL: "1h",
+			nsttl.AnnotationNamespaceLastActive:    now.Add(-2 * time.Hour).Format(time.RFC3339),
+		}
+		if hardTTL != "" {
+			annotations[nsttl.AnnotationCleanupDurationTTL] = hardTTL
+		}
+		return namespace(age, annotations)
+	}
+	testCases := []struct {
+		name           string
+		ns             *corev1.Namespace
+		pods           []*corev1.Pod
+		expectedResult reconcile.Result
+		expectExists   bool
+	}{
+		{
+			name:         "not found is swallowed",
+			expectExists: false,
+		},
+		{
+			name:           "young namespace without annotations is requeued until max age",
+			ns:             namespace(2*time.Hour, nil),
+			expectedResult: reconcile.Result{RequeueAfter: 70 * time.Hour},
+			expectExists:   true,
+		},
+		{
+			name: "namespace beyond max age is reaped",
+			ns:   namespace(73*time.Hour, nil),
+		},
+		{
+			name: "namespace beyond hard TTL is reaped",
+			ns:   namespace(13*time.Hour, map[string]string{nsttl.AnnotationCleanupDurationTTL: "12h"}),
+		},
+		{
+			name: "idle namespace beyond soft TTL is reaped",
+			ns: namespace(5*time.Hour, map[string]string{
+				nsttl.AnnotationIdleCleanupDurationTTL: "1h",
+				nsttl.AnnotationNamespaceLastActive:    now.Add(-2 * time.Hour).Format(time.RFC3339),
+			}),
+		},
+		{
+			name: "active namespace is requeued until soft TTL",
+			ns: namespace(5*time.Hour, map[string]string{
+				nsttl.AnnotationCleanupDurationTTL:     "12h",
+				nsttl.AnnotationIdleCleanupDurationTTL: "1h",
+				nsttl.AnnotationNamespaceLastActive:    now.Add(-10 * time.Minute).Format(time.RFC3339),
+			}),
+			expectedResult: reconcile.Result{RequeueAfter: 50 * time.Minute},
+			expectExists:   true,
+		},
+		{
+			name: "namespace beyond soft TTL with completed pods is reaped",
+			ns:   idle(5*time.Hour, ""),
+			pods: []*corev1.Pod{pod(corev1.PodSucceeded), pod(corev1.PodFailed)},
+		},
+		{
+			name:           "namespace beyond soft TTL with running pods is rechecked later",
+			ns:             idle(5*time.Hour, ""),
+			pods:           []*corev1.Pod{pod(corev1.PodSucceeded), pod(corev1.PodRunning)},
+			expectedResult: reconcile.Result{RequeueAfter: idleRecheckInterval},
+			expectExists:   true,
+		},
+		{
+			name:           "namespace beyond soft TTL with pending pods is requeued until its hard TTL",
+			ns:             idle(5*time.Hour, "5h5m"),
+			pods:           []*corev1.Pod{pod(corev1.PodPending)},
+			expectedResult: reconcile.Result{RequeueAfter: 5 * time.Minute},
+			expectExists:   true,
+		},
+		{
+			name: "namespace beyond soft and hard TTL with running pods is reaped",
+			ns:   idle(6*time.Hour, "5h"),
+			pods: []*corev1.Pod{pod(corev1.PodRunning)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var objs []runtime.Object
+			if tc.ns != nil {
+				objs = append(objs, tc.ns)
+			}
+			for _, pod := range tc.pods {
+				objs = append(objs, pod)
+			}
+			r := &namespaceReconciler{reaper: newReaper(objs...)}
+			key := types.NamespacedName{Name: "ci-op-1234"}
+			result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+			if err != nil {
+				t.Fatalf("reconcile failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedResult, result); diff != "" {
+				t.Errorf("reconcile result differs from expected: %s", diff)
+			}
+			assertExists(t, r.client, key, &corev1.Namespace{}, tc.expectExists)
+		})
+	}
+}
+
+func TestClusterClaimReconcile(t *testing.T) {
+	claim := func(age time.Duration, labels map[string]string, lifetime *metav1.Duration) *hivev1.ClusterClaim {
+		return &hivev1.ClusterClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "claim",
+				Namespace:         "pool",
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+				Labels:            labels,
+			},
+			Spec: hivev1.ClusterClaimSpec{Lifetime: lifetime},
+		}
+	}
+	ciLabels := map[string]string{kube.ProwJobAnnotation: "periodic-ci-job"}
+	testCases := []struct {
+		name           string
+		claim          *hivev1.ClusterClaim
+		expectedResult reconcile.Result
+		expectExists   bool
+	}{
+		{
+			name:         "claim not created by a job is ignored",
+			claim:        claim(24*time.Hour, nil, nil),
+			expectExists: true,
+		},
+		{
+			name:           "claim within lifetime and grace period is requeued",
+			claim:          claim(4*time.Hour, ciLabels, nil),
+			expectedResult: reconcile.Result{RequeueAfter: time.Hour},
+			expectExists:   true,
+		},
+		{
+			name:  "claim beyond default lifetime is reaped",
+			claim: claim(6*time.Hour, ciLabels, nil),
+		},
+		{
+			name:  "claim beyond its lifetime is reaped",
+			claim: claim(3*time.Hour, ciLabels, &metav1.Duration{Duration: time.Hour}),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &clusterClaimReconciler{reaper: newReaper(tc.claim)}
+			key := types.NamespacedName{Namespace: "pool", Name: "claim"}
+			result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+			if err != nil {
+				t.Fatalf("reconcile failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedResult, result); diff != "" {
+				t.Errorf("reconcile result differs from expected: %s", diff)
+			}
+			assertExists(t, r.client, key, &hivev1.ClusterClaim{}, tc.expectExists)
+		})
+	}
+}
+
+func TestLeaseReconcile(t *testing.T) {
+	lease := func(age time.Duration, renewed *time.Duration) *coordinationv1.Lease {
+		duration := int32(60)
+		l := &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "lease",
+				Namespace:         "ci",
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+			Spec: coordinationv1.LeaseSpec{LeaseDurationSeconds: &duration},
+		}
+		if renewed != nil {
+			renewTime := metav1.NewMicroTime(now.Add(-*renewed))
+			l.Spec.RenewTime = &renewTime
+		}
+		return l
+	}
+	hour := time.Hour
+	twoDays := 48 * time.Hour
+	testCases := []struct {
+		name           string
+		lease          *coordinationv1.Lease
+		expectedResult reconcile.Result
+		expectExists   bool
+	}{
+		{
+			name:           "renewed lease is requeued",
+			lease:          lease(72*time.Hour, &hour),
+			expectedResult: reconcile.Result{RequeueAfter: 23*time.Hour + time.Minute},
+			expectExists:   true,
+		},
+		{
+			name:  "lease not renewed for longer than its TTL is reaped",
+			lease: lease(72*time.Hour, &twoDays),
+		},
+		{
+			name:  "old lease that was never renewed is reaped",
+			lease: lease(72*time.Hour, nil),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &leaseReconciler{reaper: newReaper(tc.lease)}
+			key := types.NamespacedName{Namespace: "ci", Name: "lease"}
+			result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+			if err != nil {
+				t.Fatalf("reconcile failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedResult, result); diff != "" {
+				t.Errorf("reconcile result differs from expected: %s", diff)
+			}
+			assertExists(t, r.client, key, &coordinationv1.Lease{}, tc.expectExists)
+		})
+	}
+}
+
+func TestReapDryRun(t *testing.T) {
+	reclaimed := func() float64 {
+		metric := &dto.Metric{}
+		if err := reclaimedCounter.WithLabelValues("build01", "namespace", "max-age").Write(metric); err != nil {
+			t.Fatalf("failed to read the metric: %v", err)
+		}
+		return metric.GetCounter().GetValue()
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ci-op-1234", CreationTimestamp: metav1.NewTime(now.Add(-96 * time.Hour))}}
+	for _, dryRun := range []bool{true, false} {
+		reaper := newReaper(ns.DeepCopy())
+		reaper.opts.DryRun = dryRun
+		r := &namespaceReconciler{reaper: reaper}
+		before := reclaimed()
+		key := types.NamespacedName{Name: ns.Name}
+		if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("reconcile failed: %v", err)
+		}
+		assertExists(t, r.client, key, &corev1.Namespace{}, dryRun)
+		expected := 1.0
+		if dryRun {
+			expected = 0
+		}
+		if counted := reclaimed() - before; counted != expected {
+			t.Errorf("dry-run %t: expected %v reclaimed resources to be counted, got %v", dryRun, expected, counted)
+		}
+	}
+}
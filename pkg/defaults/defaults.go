@@ -308,6 +308,12 @@ func fromConfig(
 		overridableSteps = append(overridableSteps, step)
 	}
 
+	for _, name := range sets.List(sets.KeySet(config.ExternalImages)) {
+		step := steps.ExternalImageStep(name, config.ExternalImages[name], client, jobSpec, censor)
+		buildSteps = append(buildSteps, step)
+		addProvidesForStep(step, params)
+	}
+
 	for _, template := range templates {
 		step := steps.TemplateExecutionStep(template, params, podClient, templateClient, jobSpec, config.Resources)
 		var hasClusterType, hasUseLease bool
@@ -403,7 +409,7 @@ func stepForTest(
 ) ([]api.Step, error) {
 	if test := c.MultiStageTestConfigurationLiteral; test != nil {
 		leases := api.LeasesForTest(test)
-		if len(leases) != 0 {
+		if len(leases) != 0 || c.ClusterClaim != nil {
 			params = api.NewDeferredParameters(params)
 		}
 		var ret []api.Step
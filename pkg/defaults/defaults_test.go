@@ -1145,7 +1145,7 @@ func TestFromConfig(t *testing.T) {
 			"base_rpm_image-org.repo1-without-rpms", "base_rpm_image-org.repo2-without-rpms",
 			"rpms-org.repo1", "rpms-org.repo2",
 			"src", "bin", "to",
-			"ci-bundle0", "ci-index",
+			"ci-bundle0", "ci-index", "tool",
 		},
 	}, {
 		name: "release",
@@ -1248,6 +1248,23 @@ func TestFromConfig(t *testing.T) {
 		expectedParams: map[string]string{
 			"LOCAL_IMAGE_BASE_IMAGE": "public_docker_image_repository:base_image",
 		},
+	}, {
+		name: "external image",
+		config: api.ReleaseBuildConfiguration{
+			InputConfiguration: api.InputConfiguration{
+				ExternalImages: map[string]api.ExternalImage{
+					"tool": {PullSpec: "quay.io/org/tool:latest"},
+				},
+			},
+		},
+		expectedSteps: []string{
+			"[external:tool]",
+			"[output-images]",
+			"[images]",
+		},
+		expectedParams: map[string]string{
+			"LOCAL_IMAGE_TOOL": "public_docker_image_repository:tool",
+		},
 	}, {
 		name:          "source build",
 		refs:          &prowapi.Refs{Org: "org", Repo: "repo"},
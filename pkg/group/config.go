@@ -19,6 +19,9 @@ type Config struct {
 	ClusterGroups map[string][]string `json:"cluster_groups,omitempty"`
 	// Groups holds the mapping from group name to its target
 	Groups map[string]Target `json:"groups,omitempty"`
+	// ProtectedUsers holds the users that are never deleted from the clusters, nor removed
+	// from the groups they are members of, e.g., break-glass accounts.
+	ProtectedUsers []string `json:"protected_users,omitempty"`
 }
 
 // Target represents the distribution of a group
@@ -62,5 +65,10 @@ func (c *Config) validate() error {
 			return fmt.Errorf("cannot use the group name %s in the configuration file", OpenshiftPrivAdminsGroup)
 		}
 	}
+	for _, user := range c.ProtectedUsers {
+		if user == "" {
+			return fmt.Errorf("protected user names cannot be empty")
+		}
+	}
 	return nil
 }
@@ -33,6 +33,19 @@ func TestLoadConfig(t *testing.T) {
 			file:        filepath.Join("testdata", "TestLoadConfig", "openshift_priv_admins.yaml"),
 			expectedErr: fmt.Errorf("failed to validate config file: cannot use the group name openshift-priv-admins in the configuration file"),
 		},
+		{
+			name: "protected users",
+			file: filepath.Join("testdata", "TestLoadConfig", "protected_users.yaml"),
+			expected: &Config{
+				Groups:         map[string]Target{"some-group": {}},
+				ProtectedUsers: []string{"break-glass"},
+			},
+		},
+		{
+			name:        "protected user names cannot be empty",
+			file:        filepath.Join("testdata", "TestLoadConfig", "empty_protected_user.yaml"),
+			expectedErr: fmt.Errorf("failed to validate config file: protected user names cannot be empty"),
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -0,0 +1,264 @@
+// Package jobpolicy implements fleet-wide policies that all Prow jobs have to comply with.
+// The policies are defined as rules in a YAML file, so that they can be changed without
+// changing the code of the tools enforcing them.
+package jobpolicy
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	prowconfig "k8s.io/test-infra/prow/config"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/ci-tools/pkg/util/gzip"
+)
+
+// Config is the set of rules the Prow jobs have to comply with
+type Config struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is a policy for Prow jobs. A job violates the rule if it fails any of the configured checks.
+type Rule struct {
+	// Name identifies the rule in violations
+	Name string `json:"name"`
+	// RequiredLabels are the labels every job must have with a non-empty value
+	RequiredLabels []string `json:"requiredLabels,omitempty"`
+	// AllowedClusters are the only clusters jobs may run on
+	AllowedClusters []string `json:"allowedClusters,omitempty"`
+	// ResourceBounds are the bounds for the resources of the containers of jobs
+	ResourceBounds *ResourceBounds `json:"resourceBounds,omitempty"`
+	// ForbiddenVolumes are the types of volumes jobs must not use, e.g., hostPath
+	ForbiddenVolumes []string `json:"forbiddenVolumes,omitempty"`
+	// Exemptions are the jobs the rule does not apply to
+	Exemptions []Exemption `json:"exemptions,omitempty"`
+}
+
+// ResourceBounds are the bounds for the resources of each container of a job
+type ResourceBounds struct {
+	// MinRequests are the minimal requests each container has to set
+	MinRequests corev1.ResourceList `json:"minRequests,omitempty"`
+	// MaxRequests are the maximal requests each container may set
+	MaxRequests corev1.ResourceList `json:"maxRequests,omitempty"`
+	// MaxLimits are the maximal limits each container may set
+	MaxLimits corev1.ResourceList `json:"maxLimits,omitempty"`
+}
+
+// Exemption exempts the jobs matching any of its regexes from a rule
+type Exemption struct {
+	// Jobs is a list of regexes of the names of the exempted jobs
+	Jobs []string `json:"jobs,omitempty"`
+	// Paths is a list of regexes of the file paths of the exempted jobs, relative to the
+	// directory holding all Prow job config files, e.g., openshift/release/openshift-release-infra-periodics.yaml
+	Paths []string `json:"paths,omitempty"`
+	// Reason documents why the jobs are exempted
+	Reason string `json:"reason"`
+
+	jobREs  []*regexp.Regexp
+	pathREs []*regexp.Regexp
+}
+
+// volumeTypes are the types of volumes, e.g., hostPath or emptyDir
+var volumeTypes = func() sets.Set[string] {
+	types := sets.New[string]()
+	t := reflect.TypeOf(corev1.VolumeSource{})
+	for i := 0; i < t.NumField(); i++ {
+		types.Insert(strings.Split(t.Field(i).Tag.Get("json"), ",")[0])
+	}
+	return types
+}()
+
+// LoadConfig loads the rules from a file and validates them
+func LoadConfig(path string) (*Config, error) {
+	data, err := gzip.ReadFileMaybeGZIP(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the policy file %q: %w", path, err)
+	}
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the policy file %q: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy file %q: %w", path, err)
+	}
+	return config, nil
+}
+
+// Validate validates the rules and compiles the regexes of their exemptions
+func (c *Config) Validate() error {
+	var errs []error
+	names := sets.New[string]()
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if rule.Name == "" {
+			errs = append(errs, fmt.Errorf("rules[%d]: name must be set", i))
+		} else if names.Has(rule.Name) {
+			errs = append(errs, fmt.Errorf("rules[%d]: duplicate rule %q", i, rule.Name))
+		}
+		names.Insert(rule.Name)
+		if len(rule.RequiredLabels) == 0 && len(rule.AllowedClusters) == 0 && rule.ResourceBounds == nil && len(rule.ForbiddenVolumes) == 0 {
+			errs = append(errs, fmt.Errorf("rule %q: at least one of requiredLabels, allowedClusters, resourceBounds and forbiddenVolumes must be set", rule.Name))
+		}
+		for _, volume := range rule.ForbiddenVolumes {
+			if !volumeTypes.Has(volume) {
+				errs = append(errs, fmt.Errorf("rule %q: unknown volume type %q", rule.Name, volume))
+			}
+		}
+		for j := range rule.Exemptions {
+			exemption := &rule.Exemptions[j]
+			if len(exemption.Jobs) == 0 && len(exemption.Paths) == 0 {
+				errs = append(errs, fmt.Errorf("rule %q: exemptions[%d]: at least one of jobs and paths must be set", rule.Name, j))
+			}
+			if exemption.Reason == "" {
+				errs = append(errs, fmt.Errorf("rule %q: exemptions[%d]: reason must be set", rule.Name, j))
+			}
+			exemption.jobREs, exemption.pathREs = nil, nil
+			for _, job := range exemption.Jobs {
+				re, err := regexp.Compile(job)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("rule %q: exemptions[%d]: failed to compile regex %q: %w", rule.Name, j, job, err))
+					continue
+				}
+				exemption.jobREs = append(exemption.jobREs, re)
+			}
+			for _, path := range exemption.Paths {
+				re, err := regexp.Compile(path)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("rule %q: exemptions[%d]: failed to compile regex %q: %w", rule.Name, j, path, err))
+					continue
+				}
+				exemption.pathREs = append(exemption.pathREs, re)
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// CheckJobConfig checks all jobs of a Prow job config file against the rules
+func (c *Config) CheckJobConfig(jc *prowconfig.JobConfig, path string) error {
+	var errs []error
+	for _, presubmits := range jc.PresubmitsStatic {
+		for _, job := range presubmits {
+			errs = append(errs, c.Check(job.JobBase, path))
+		}
+	}
+	for _, postsubmits := range jc.PostsubmitsStatic {
+		for _, job := range postsubmits {
+			errs = append(errs, c.Check(job.JobBase, path))
+		}
+	}
+	for _, job := range jc.Periodics {
+		errs = append(errs, c.Check(job.JobBase, path))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// Check checks a job against all rules it is not exempted from
+func (c *Config) Check(job prowconfig.JobBase, path string) error {
+	var errs []error
+	for _, rule := range c.Rules {
+		if rule.exempts(job.Name, path) {
+			continue
+		}
+		for _, violation := range rule.violations(job) {
+			errs = append(errs, fmt.Errorf("job %s violates rule %q: %s", job.Name, rule.Name, violation))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (r *Rule) exempts(job, path string) bool {
+	for _, exemption := range r.Exemptions {
+		for _, re := range exemption.jobREs {
+			if re.MatchString(job) {
+				return true
+			}
+		}
+		for _, re := range exemption.pathREs {
+			if re.MatchString(path) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *Rule) violations(job prowconfig.JobBase) []string {
+	var violations []string
+	for _, label := range r.RequiredLabels {
+		if job.Labels[label] == "" {
+			violations = append(violations, fmt.Sprintf("missing label %s", label))
+		}
+	}
+	if len(r.AllowedClusters) > 0 && !sets.New[string](r.AllowedClusters...).Has(job.Cluster) {
+		violations = append(violations, fmt.Sprintf("cluster %s is not one of %s", job.Cluster, strings.Join(r.AllowedClusters, ", ")))
+	}
+	if job.Spec == nil {
+		return violations
+	}
+	if r.ResourceBounds != nil {
+		containers := append(append([]corev1.Container{}, job.Spec.InitContainers...), job.Spec.Containers...)
+		for _, container := range containers {
+			violations = append(violations, r.ResourceBounds.violations(container)...)
+		}
+	}
+	forbidden := sets.New[string](r.ForbiddenVolumes...)
+	for _, volume := range job.Spec.Volumes {
+		if volumeType := typeOfVolume(volume); forbidden.Has(volumeType) {
+			violations = append(violations, fmt.Sprintf("volume %s is of forbidden type %s", volume.Name, volumeType))
+		}
+	}
+	return violations
+}
+
+func (b *ResourceBounds) violations(container corev1.Container) []string {
+	var violations []string
+	for _, name := range sortedResourceNames(b.MinRequests) {
+		minimum := b.MinRequests[name]
+		request, ok := container.Resources.Requests[name]
+		if !ok {
+			violations = append(violations, fmt.Sprintf("container %s does not request %s", container.Name, name))
+		} else if request.Cmp(minimum) < 0 {
+			violations = append(violations, fmt.Sprintf("container %s requests %s of %s, less than the minimum of %s", container.Name, request.String(), name, minimum.String()))
+		}
+	}
+	for _, name := range sortedResourceNames(b.MaxRequests) {
+		maximum := b.MaxRequests[name]
+		if request, ok := container.Resources.Requests[name]; ok && request.Cmp(maximum) > 0 {
+			violations = append(violations, fmt.Sprintf("container %s requests %s of %s, more than the maximum of %s", container.Name, request.String(), name, maximum.String()))
+		}
+	}
+	for _, name := range sortedResourceNames(b.MaxLimits) {
+		maximum := b.MaxLimits[name]
+		if limit, ok := container.Resources.Limits[name]; ok && limit.Cmp(maximum) > 0 {
+			violations = append(violations, fmt.Sprintf("container %s limits %s to %s, more than the maximum of %s", container.Name, name, limit.String(), maximum.String()))
+		}
+	}
+	return violations
+}
+
+func sortedResourceNames(resources corev1.ResourceList) []corev1.ResourceName {
+	var names []corev1.ResourceName
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// typeOfVolume returns the type of the volume as named in its JSON representation, e.g., hostPath
+func typeOfVolume(volume corev1.Volume) string {
+	v := reflect.ValueOf(volume.VolumeSource)
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Field(i).IsNil() {
+			return strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		}
+	}
+	return ""
+}
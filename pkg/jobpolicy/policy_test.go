@@ -0,0 +1,181 @@
+package jobpolicy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	prowconfig "k8s.io/test-infra/prow/config"
+
+	"github.com/openshift/ci-tools/pkg/testhelper"
+)
+
+func TestLoadConfig(t *testing.T) {
+	config, err := LoadConfig("testdata/policies.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := &Config{Rules: []Rule{
+		{
+			Name:           "team-label",
+			RequiredLabels: []string{"ci.openshift.io/team"},
+			Exemptions:     []Exemption{{Paths: []string{"^openshift/release/"}, Reason: "the infrastructure jobs are owned by the test platform team"}},
+		},
+		{
+			Name:            "build-farm",
+			AllowedClusters: []string{"build01", "build02"},
+		},
+		{
+			Name: "resources",
+			ResourceBounds: &ResourceBounds{
+				MinRequests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+				MaxRequests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("16Gi")},
+				MaxLimits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("32Gi")},
+			},
+		},
+		{
+			Name:             "no-host-path",
+			ForbiddenVolumes: []string{"hostPath"},
+			Exemptions:       []Exemption{{Jobs: []string{`^periodic-ci-image-import-to-build\d+$`}, Reason: "needs access to the container storage of the node"}},
+		},
+	}}
+	if diff := cmp.Diff(expected, config, cmpopts.IgnoreUnexported(Exemption{}), cmp.Comparer(func(a, b resource.Quantity) bool { return a.Cmp(b) == 0 })); diff != "" {
+		t.Errorf("config differs from expected: %s", diff)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   Config
+		expected error
+	}{
+		{
+			name: "valid config",
+			config: Config{Rules: []Rule{
+				{Name: "a", RequiredLabels: []string{"label"}, Exemptions: []Exemption{{Jobs: []string{"^job$"}, Reason: "reason"}}},
+				{Name: "b", ForbiddenVolumes: []string{"hostPath", "emptyDir"}},
+			}},
+		},
+		{
+			name: "invalid rules",
+			config: Config{Rules: []Rule{
+				{RequiredLabels: []string{"label"}},
+				{Name: "a"},
+				{Name: "a", ForbiddenVolumes: []string{"hostpath"}},
+			}},
+			expected: errors.New(`[rules[0]: name must be set, rule "a": at least one of requiredLabels, allowedClusters, resourceBounds and forbiddenVolumes must be set, rules[2]: duplicate rule "a", rule "a": unknown volume type "hostpath"]`),
+		},
+		{
+			name: "invalid exemptions",
+			config: Config{Rules: []Rule{
+				{Name: "a", RequiredLabels: []string{"label"}, Exemptions: []Exemption{{Reason: "reason"}, {Jobs: []string{"("}}}},
+			}},
+			expected: errors.New(`[rule "a": exemptions[0]: at least one of jobs and paths must be set, rule "a": exemptions[1]: reason must be set, rule "a": exemptions[1]: failed to compile regex "(": error parsing regexp: missing closing ): ` + "`(`" + `]`),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, tc.config.Validate(), testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	config, err := LoadConfig("testdata/policies.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	compliant := func() prowconfig.JobBase {
+		return prowconfig.JobBase{
+			Name:    "job",
+			Cluster: "build01",
+			Labels:  map[string]string{"ci.openshift.io/team": "team"},
+			Spec: &corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name: "test",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+						Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+					},
+				}},
+				Volumes: []corev1.Volume{{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+			},
+		}
+	}
+	testCases := []struct {
+		name     string
+		job      func() prowconfig.JobBase
+		path     string
+		expected error
+	}{
+		{
+			name: "compliant job",
+			job:  compliant,
+			path: "org/repo/org-repo-master-presubmits.yaml",
+		},
+		{
+			name: "job violating all rules",
+			job: func() prowconfig.JobBase {
+				job := compliant()
+				job.Labels = nil
+				job.Cluster = "app.ci"
+				job.Spec.InitContainers = []corev1.Container{{Name: "init"}}
+				job.Spec.Containers[0].Resources.Requests[corev1.ResourceMemory] = resource.MustParse("20Gi")
+				job.Spec.Containers[0].Resources.Limits[corev1.ResourceMemory] = resource.MustParse("40Gi")
+				job.Spec.Volumes = append(job.Spec.Volumes, corev1.Volume{Name: "storage", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/lib/containers"}}})
+				return job
+			},
+			path: "org/repo/org-repo-master-presubmits.yaml",
+			expected: errors.New(`[job job violates rule "team-label": missing label ci.openshift.io/team, ` +
+				`job job violates rule "build-farm": cluster app.ci is not one of build01, build02, ` +
+				`job job violates rule "resources": container init does not request cpu, ` +
+				`job job violates rule "resources": container test requests 20Gi of memory, more than the maximum of 16Gi, ` +
+				`job job violates rule "resources": container test limits memory to 40Gi, more than the maximum of 32Gi, ` +
+				`job job violates rule "no-host-path": volume storage is of forbidden type hostPath]`),
+		},
+		{
+			name: "request below the minimum",
+			job: func() prowconfig.JobBase {
+				job := compliant()
+				job.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU] = resource.MustParse("1m")
+				return job
+			},
+			path:     "org/repo/org-repo-master-presubmits.yaml",
+			expected: errors.New(`job job violates rule "resources": container test requests 1m of cpu, less than the minimum of 10m`),
+		},
+		{
+			name: "exempted jobs",
+			job: func() prowconfig.JobBase {
+				job := compliant()
+				job.Name = "periodic-ci-image-import-to-build01"
+				job.Labels = nil
+				job.Spec.Volumes = append(job.Spec.Volumes, corev1.Volume{Name: "storage", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/lib/containers"}}})
+				return job
+			},
+			path: "openshift/release/openshift-release-infra-periodics.yaml",
+		},
+		{
+			name: "jobs without a spec are only checked for labels and clusters",
+			job: func() prowconfig.JobBase {
+				job := compliant()
+				job.Spec = nil
+				return job
+			},
+			path: "org/repo/org-repo-master-presubmits.yaml",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, config.Check(tc.job(), tc.path), testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+		})
+	}
+}
@@ -4,20 +4,47 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	prowconfig "k8s.io/test-infra/prow/config"
+	"k8s.io/test-infra/prow/metrics"
+
 	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatoranalyzer"
 	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunbigqueryloader"
 	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunhistoricaldataanalyzer"
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunquery"
 	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobruntestcaseanalyzer"
 	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobtableprimer"
 	"github.com/openshift/ci-tools/pkg/jobrunaggregator/releasebigqueryloader"
 	"github.com/openshift/ci-tools/pkg/jobrunaggregator/tablescreator"
 )
 
+// logLevelForVerbosity maps the --v verbosity to a log level: 0 logs informational messages,
+// 1 adds debug messages like the prow jobs checked by matchers, and 2 or more traces everything.
+func logLevelForVerbosity(verbosity int) log.Level {
+	switch {
+	case verbosity <= 0:
+		return log.InfoLevel
+	case verbosity == 1:
+		return log.DebugLevel
+	default:
+		return log.TraceLevel
+	}
+}
+
 func NewJobAggregatorCommand() *cobra.Command {
+	var verbosity int
+	var metricsPort int
 	cmd := &cobra.Command{
 		Use:  "job-run-aggregator",
 		Long: `Commands associated with CI job run aggregation`,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			log.SetLevel(logLevelForVerbosity(verbosity))
+			if metricsPort > 0 {
+				metrics.ExposeMetrics("job-run-aggregator", prowconfig.PushGateway{}, metricsPort)
+			}
+		},
 	}
+	cmd.PersistentFlags().IntVar(&verbosity, "v", 0, "Log verbosity: 0 for informational messages, 1 for debug messages, 2 or more for tracing.")
+	cmd.PersistentFlags().IntVar(&metricsPort, "metrics-port", 0, "Port on which /metrics is served while the command runs. Metrics are not served when unset.")
 
 	// Add some millisecond precision to log timestamps, useful for debugging performance.
 	formatter := new(log.TextFormatter)
@@ -25,12 +52,13 @@ func NewJobAggregatorCommand() *cobra.Command {
 	formatter.FullTimestamp = true
 	formatter.DisableColors = false
 	log.SetFormatter(formatter)
-	log.SetLevel(log.DebugLevel)
 
 	cmd.AddCommand(jobrunbigqueryloader.NewBigQueryTestRunUploadFlagsCommand())
 	cmd.AddCommand(jobrunbigqueryloader.NewBigQueryDisruptionUploadFlagsCommand())
 	cmd.AddCommand(jobrunbigqueryloader.NewBigQueryAlertUploadFlagsCommand())
+	cmd.AddCommand(jobrunbigqueryloader.NewBigQueryStepResourceUploadFlagsCommand())
 	cmd.AddCommand(jobrunaggregatoranalyzer.NewJobRunsAnalyzerCommand())
+	cmd.AddCommand(jobrunaggregatoranalyzer.NewAggregationBackfillCommand())
 	cmd.AddCommand(jobtableprimer.NewPrimeJobTableCommand())
 	cmd.AddCommand(jobtableprimer.NewGenerateJobNamesCommand())
 
@@ -38,9 +66,12 @@ func NewJobAggregatorCommand() *cobra.Command {
 	cmd.AddCommand(releasebigqueryloader.NewBigQueryReleaseUploadFlagsCommand())
 
 	cmd.AddCommand(tablescreator.NewBigQueryCreateTablesFlagsCommand())
+	cmd.AddCommand(tablescreator.NewBigQueryMigrateSchemaFlagsCommand())
 
 	cmd.AddCommand(jobruntestcaseanalyzer.NewJobRunsTestCaseAnalyzerCommand())
 
 	cmd.AddCommand(jobrunhistoricaldataanalyzer.NewJobRunHistoricalDataAnalyzerCommand())
+
+	cmd.AddCommand(jobrunquery.NewQueryCommand())
 	return cmd
 }
@@ -1,8 +1,10 @@
 package jobrunaggregatoranalyzer
 
 import (
+	"bytes"
 	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -48,8 +50,29 @@ type JobRunAggregatorAnalyzerOptions struct {
 
 	staticJobRunIdentifiers []jobrunaggregatorlib.JobRunIdentifier
 	gcsBucket               string
+
+	aggregationID string
+	// notifier is told about the verdict when set
+	notifier aggregationNotifier
+	// aggregationRunInserter records the outcome of the aggregation when set
+	aggregationRunInserter jobrunaggregatorlib.BigQueryInserter
+	// disruptionBackends decides which disruption backends are analyzed, the default ones when unset
+	disruptionBackends *jobrunaggregatorlib.DisruptionBackends
+	// sippyExporter is sent the results of the aggregation when set
+	sippyExporter *sippyExporter
+	// testOwnership assigns the tests to their owners in the report when set
+	testOwnership *TestOwnership
+
+	// watch reads the job runs as they finish and aggregates once watchJobRuns of them finished, instead
+	// of waiting for all the job runs to finish
+	watch             bool
+	watchJobRuns      int
+	watchPollInterval time.Duration
 }
 
+// errTestsFailedAggregation is returned when the aggregation completed but some tests failed it
+var errTestsFailedAggregation = errors.New("Some tests failed aggregation.  See above for details.")
+
 func (o *JobRunAggregatorAnalyzerOptions) loadStaticJobRuns(ctx context.Context) ([]jobrunaggregatorapi.JobRunInfo, error) {
 	var jobRuns []jobrunaggregatorapi.JobRunInfo
 	for _, job := range o.staticJobRunIdentifiers {
@@ -101,6 +124,24 @@ func (o *JobRunAggregatorAnalyzerOptions) GetRelatedJobRuns(ctx context.Context)
 }
 
 func (o *JobRunAggregatorAnalyzerOptions) Run(ctx context.Context) error {
+	aggregationRun := &jobrunaggregatorapi.AggregationRunRow{
+		JobName:    o.jobName,
+		PayloadTag: o.payloadTag,
+		Status:     jobrunaggregatorapi.AggregationRunStatusError,
+	}
+	err := o.aggregate(ctx, aggregationRun)
+	jobrunaggregatorlib.RecordAggregationVerdict(o.jobName, aggregationRun.Status)
+	if o.aggregationRunInserter != nil {
+		aggregationRun.AggregationTime = o.clock.Now()
+		if err := o.aggregationRunInserter.Put(ctx, aggregationRun); err != nil {
+			logrus.WithError(err).Error("Failed to record the aggregation.")
+		}
+	}
+	return err
+}
+
+// aggregate aggregates the job runs and fills in the outcome of the aggregation
+func (o *JobRunAggregatorAnalyzerOptions) aggregate(ctx context.Context, aggregationRun *jobrunaggregatorapi.AggregationRunRow) error {
 	// if it hasn't been more than two hours since the jobRuns started, the list isn't complete.
 	readyAt := o.jobRunStartEstimate.Add(2 * time.Hour)
 
@@ -128,24 +169,55 @@ func (o *JobRunAggregatorAnalyzerOptions) Run(ctx context.Context) error {
 		return fmt.Errorf("error creating destination directory %q: %w", currentAggregationDir, err)
 	}
 
-	err := jobrunaggregatorlib.WaitUntilTime(ctx, readyAt)
-	if err != nil {
-		return err
+	jobRuns := newJobRunsState(o.jobName)
+	if len(o.explicitGCSPrefix) > 0 {
+		jobRuns.junit.jobGCSBucketRoot = o.explicitGCSPrefix
 	}
-
-	var jobRunWaiter jobrunaggregatorlib.JobRunWaiter
-	if o.jobStateQuerySource == jobrunaggregatorlib.JobStateQuerySourceBigQuery || o.prowJobClient == nil {
-		jobRunWaiter = &jobrunaggregatorlib.BigQueryJobRunWaiter{JobRunGetter: o, TimeToStopWaiting: timeToStopWaiting}
+	var finishedJobsToAggregate []jobrunaggregatorapi.JobRunInfo
+	var finishedJobRunNames, unfinishedJobNames []string
+	if o.watch {
+		watcher := &jobrunaggregatorlib.JobRunWatcher{
+			JobRunGetter:      o,
+			RequiredJobRuns:   o.watchJobRuns,
+			PollInterval:      o.watchPollInterval,
+			TimeToStopWaiting: timeToStopWaiting,
+			OnJobRunFinished:  jobRuns.addJobRun,
+		}
+		var unfinishedJobRuns []jobrunaggregatorapi.JobRunInfo
+		var err error
+		finishedJobsToAggregate, unfinishedJobRuns, err = watcher.Watch(ctx)
+		if err != nil {
+			return err
+		}
+		if err := jobrunaggregatorlib.WriteJobRunSummary(ctx, o.workingDir, finishedJobsToAggregate, unfinishedJobRuns, "aggregated"); err != nil {
+			return err
+		}
+		for _, jobRun := range finishedJobsToAggregate {
+			finishedJobRunNames = append(finishedJobRunNames, jobRun.GetJobName()+jobRun.GetJobRunID())
+		}
+		for _, jobRun := range unfinishedJobRuns {
+			unfinishedJobNames = append(unfinishedJobNames, jobRun.GetJobRunID())
+		}
 	} else {
-		jobRunWaiter = &jobrunaggregatorlib.ClusterJobRunWaiter{
-			ProwJobClient:      o.prowJobClient,
-			TimeToStopWaiting:  timeToStopWaiting,
-			ProwJobMatcherFunc: o.prowJobMatcherFunc,
+		err := jobrunaggregatorlib.WaitUntilTime(ctx, readyAt)
+		if err != nil {
+			return err
+		}
+
+		var jobRunWaiter jobrunaggregatorlib.JobRunWaiter
+		if o.jobStateQuerySource == jobrunaggregatorlib.JobStateQuerySourceBigQuery || o.prowJobClient == nil {
+			jobRunWaiter = &jobrunaggregatorlib.BigQueryJobRunWaiter{JobRunGetter: o, TimeToStopWaiting: timeToStopWaiting}
+		} else {
+			jobRunWaiter = &jobrunaggregatorlib.ClusterJobRunWaiter{
+				ProwJobClient:      o.prowJobClient,
+				TimeToStopWaiting:  timeToStopWaiting,
+				ProwJobMatcherFunc: o.prowJobMatcherFunc,
+			}
+		}
+		finishedJobsToAggregate, _, finishedJobRunNames, unfinishedJobNames, err = jobrunaggregatorlib.WaitAndGetAllFinishedJobRuns(ctx, o, jobRunWaiter, o.workingDir, "aggregated")
+		if err != nil {
+			return err
 		}
-	}
-	finishedJobsToAggregate, _, finishedJobRunNames, unfinishedJobNames, err := jobrunaggregatorlib.WaitAndGetAllFinishedJobRuns(ctx, o, jobRunWaiter, o.workingDir, "aggregated")
-	if err != nil {
-		return err
 	}
 
 	if len(unfinishedJobNames) > 0 {
@@ -153,9 +225,19 @@ func (o *JobRunAggregatorAnalyzerOptions) Run(ctx context.Context) error {
 	}
 	// if more than three jobruns timed out, just fail the entire aggregation
 	if len(unfinishedJobNames) > 3 {
+		if o.notifier != nil {
+			target := o.payloadTag
+			if len(o.aggregationID) > 0 {
+				target = o.aggregationID
+			}
+			if err := o.notifier.NotifyNotEnoughJobRuns(ctx, o.jobName, target, unfinishedJobNames); err != nil {
+				alog.WithError(err).Error("Failed to send notification.")
+			}
+		}
 		return fmt.Errorf("%s for %s: found %d unfinished related jobRuns: %v\n", o.jobName, o.payloadTag, len(unfinishedJobNames), strings.Join(unfinishedJobNames, ", "))
 	}
 	alog.Infof("aggregating %d related jobRuns: %v", len(finishedJobsToAggregate), strings.Join(finishedJobRunNames, ", "))
+	aggregationRun.JobRunCount = len(finishedJobsToAggregate)
 
 	aggregationConfiguration := &AggregationConfiguration{}
 	for _, jobRunName := range unfinishedJobNames {
@@ -175,64 +257,17 @@ func (o *JobRunAggregatorAnalyzerOptions) Run(ctx context.Context) error {
 		)
 	}
 
-	currentAggregationJunit := &aggregatedJobRunJunit{
-		jobGCSBucketRoot: filepath.Join("logs", o.jobName),
-	}
-	if len(o.explicitGCSPrefix) > 0 {
-		currentAggregationJunit.jobGCSBucketRoot = o.explicitGCSPrefix
-	}
-	masterNodesUpdated := ""
-	for i := range finishedJobsToAggregate {
-		jobRun := finishedJobsToAggregate[i]
-
-		// Initialize our junits and file names.
-		// We aren't required to do this but if we
-		// do we can catch any errors and bail.
-		err := jobRun.GetJobRunFromGCS(ctx)
-		if err != nil {
-			return err
-		}
-
-		// We found a case where the first job failed to upgrade but the others didn't
-		// original logic stopped on the first flag we found which indicated master nodes did not update
-		// and led to lower disruption values being used, causing failures.
-		// we now look at each job unless we have a 'Y' value already
-		if strings.ToUpper(masterNodesUpdated) != "Y" {
-			// get the flag to see if masternodes have been updated
-			clusterData, err := jobRun.GetOpenShiftTestsFilesWithPrefix(ctx, "cluster-data")
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Could not fetch cluster data for %s - %v\n", jobRun.GetJobRunID(), err)
-			}
-			updatedFlag := jobrunaggregatorlib.GetMasterNodesUpdatedStatusFromClusterData(clusterData)
-
-			// if we have any value set it here
-			// if we set a 'Y' here we won't come back in this loop based on the check above
-			if len(updatedFlag) > 0 {
-				masterNodesUpdated = updatedFlag
+	// when watching, the job runs were read as they finished
+	if !o.watch {
+		for i := range finishedJobsToAggregate {
+			if err := jobRuns.addJobRun(ctx, finishedJobsToAggregate[i]); err != nil {
+				return err
 			}
-
-		}
-		currJunit, err := newJobRunJunit(ctx, jobRun)
-		if err != nil {
-			return err
-		}
-		prowJob, err := currJunit.jobRun.GetProwJob(ctx)
-		if err != nil {
-			return err
 		}
-		aggregationConfiguration.FinishedJobs = append(
-			aggregationConfiguration.FinishedJobs,
-			JobRunInfo{
-				JobName:      jobRun.GetJobName(),
-				JobRunID:     jobRun.GetJobRunID(),
-				HumanURL:     jobRun.GetHumanURL(),
-				GCSBucketURL: jobRun.GetGCSArtifactURL(),
-				Status:       string(prowJob.Status.State),
-			},
-		)
-
-		currentAggregationJunit.addJobRun(jobrunaggregatorlib.GetPayloadTagFromProwJob(prowJob), currJunit)
 	}
+	aggregationConfiguration.FinishedJobs = append(aggregationConfiguration.FinishedJobs, jobRuns.finishedJobs...)
+	currentAggregationJunit := jobRuns.junit
+	masterNodesUpdated := jobRuns.masterNodesUpdated
 
 	// write out the jobruns aggregated by this jobrun.
 	aggregationConfigYAML, err := yaml.Marshal(aggregationConfiguration)
@@ -260,8 +295,15 @@ func (o *JobRunAggregatorAnalyzerOptions) Run(ctx context.Context) error {
 	}
 	currentAggregationJunitSuites.Suites = append(currentAggregationJunitSuites.Suites, disruptionSuite)
 
-	// TODO this is the spot where we would add an alertSuite that aggregates the alerts firing in our clusters to prevent
-	//  allowing more and more failing alerts through just because one fails.
+	logrus.Infof("%q for %q:  aggregating alerts", o.jobName, o.payloadTag)
+
+	// the alerts firing in our clusters are aggregated to prevent allowing more and more failing alerts through just
+	// because one fails.
+	alertSuite, err := o.CalculateAlertTestSuite(ctx, currentAggregationJunit.jobGCSBucketRoot, finishedJobsToAggregate)
+	if err != nil {
+		return err
+	}
+	currentAggregationJunitSuites.Suites = append(currentAggregationJunitSuites.Suites, alertSuite)
 
 	currentAggrationJunitXML, err := xml.Marshal(currentAggregationJunitSuites)
 	if err != nil {
@@ -282,26 +324,126 @@ func (o *JobRunAggregatorAnalyzerOptions) Run(ctx context.Context) error {
 		return err
 	}
 
-	if hasFailedTestCase(fakeSuite) {
+	report := newAggregationReport(o.jobName, o.payloadTag, aggregationConfiguration.FinishedJobs, currentAggregationJunitSuites, disruptionSuite)
+	report.AggregationID = o.aggregationID
+	if o.testOwnership != nil {
+		report.assignOwners(o.testOwnership)
+	}
+	reportHTML := &bytes.Buffer{}
+	if err := writeAggregationReport(report, reportHTML); err != nil {
+		return fmt.Errorf("failed to render the aggregation report: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(currentAggregationDir, "aggregation-report.html"), reportHTML.Bytes(), 0644); err != nil {
+		return err
+	}
+	sippyJSON, err := marshalSippyAggregation(report)
+	if err != nil {
+		return fmt.Errorf("failed to serialize the aggregation for Sippy: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(currentAggregationDir, "aggregation-sippy.json"), sippyJSON, 0644); err != nil {
+		return err
+	}
+	if o.sippyExporter != nil {
+		if err := o.sippyExporter.Export(ctx, sippyJSON); err != nil {
+			alog.WithError(err).Error("Failed to export the aggregation to Sippy.")
+		}
+	}
+	if o.notifier != nil {
+		if err := o.notifier.NotifyVerdict(ctx, report); err != nil {
+			alog.WithError(err).Error("Failed to send notification.")
+		}
+	}
+
+	aggregationRun.FailedTestCount = countFailedTestCases(fakeSuite)
+	if aggregationRun.FailedTestCount > 0 {
+		aggregationRun.Status = jobrunaggregatorapi.AggregationRunStatusFailed
 		// we already indicated failure messages above
-		return fmt.Errorf("Some tests failed aggregation.  See above for details.")
+		return errTestsFailedAggregation
 	}
+	aggregationRun.Status = jobrunaggregatorapi.AggregationRunStatusPassed
 
 	return nil
 }
 
-func hasFailedTestCase(suite *junit.TestSuite) bool {
+func countFailedTestCases(suite *junit.TestSuite) int {
+	failed := 0
 	for _, testCase := range suite.TestCases {
 		if testCase.FailureOutput != nil {
-			return true
+			failed++
 		}
 	}
 
 	for _, child := range suite.Children {
-		if hasFailedTestCase(child) {
-			return true
-		}
+		failed += countFailedTestCases(child)
 	}
 
-	return false
+	return failed
+}
+
+// jobRunsState is what the aggregation reads from the finished job runs. The job runs are added to it one
+// by one, so that they can be read as soon as they finish when watching them.
+type jobRunsState struct {
+	junit              *aggregatedJobRunJunit
+	finishedJobs       []JobRunInfo
+	masterNodesUpdated string
+}
+
+func newJobRunsState(jobName string) *jobRunsState {
+	return &jobRunsState{
+		junit: &aggregatedJobRunJunit{
+			jobGCSBucketRoot: filepath.Join("logs", jobName),
+		},
+	}
+}
+
+// addJobRun reads the junit and cluster data of a finished job run
+func (s *jobRunsState) addJobRun(ctx context.Context, jobRun jobrunaggregatorapi.JobRunInfo) error {
+	// Initialize our junits and file names.
+	// We aren't required to do this but if we
+	// do we can catch any errors and bail.
+	err := jobRun.GetJobRunFromGCS(ctx)
+	if err != nil {
+		return err
+	}
+
+	// We found a case where the first job failed to upgrade but the others didn't
+	// original logic stopped on the first flag we found which indicated master nodes did not update
+	// and led to lower disruption values being used, causing failures.
+	// we now look at each job unless we have a 'Y' value already
+	if strings.ToUpper(s.masterNodesUpdated) != "Y" {
+		// get the flag to see if masternodes have been updated
+		clusterData, err := jobRun.GetOpenShiftTestsFilesWithPrefix(ctx, "cluster-data")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not fetch cluster data for %s - %v\n", jobRun.GetJobRunID(), err)
+		}
+		updatedFlag := jobrunaggregatorlib.GetMasterNodesUpdatedStatusFromClusterData(clusterData)
+
+		// if we have any value set it here
+		// if we set a 'Y' here we won't come back in this loop based on the check above
+		if len(updatedFlag) > 0 {
+			s.masterNodesUpdated = updatedFlag
+		}
+
+	}
+	currJunit, err := newJobRunJunit(ctx, jobRun)
+	if err != nil {
+		return err
+	}
+	prowJob, err := currJunit.jobRun.GetProwJob(ctx)
+	if err != nil {
+		return err
+	}
+	s.finishedJobs = append(
+		s.finishedJobs,
+		JobRunInfo{
+			JobName:      jobRun.GetJobName(),
+			JobRunID:     jobRun.GetJobRunID(),
+			HumanURL:     jobRun.GetHumanURL(),
+			GCSBucketURL: jobRun.GetGCSArtifactURL(),
+			Status:       string(prowJob.Status.State),
+		},
+	)
+
+	s.junit.addJobRun(jobrunaggregatorlib.GetPayloadTagFromProwJob(prowJob), currJunit)
+	return nil
 }
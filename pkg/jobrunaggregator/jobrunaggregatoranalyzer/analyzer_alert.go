@@ -0,0 +1,125 @@
+package jobrunaggregatoranalyzer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorlib"
+	"github.com/openshift/ci-tools/pkg/junit"
+)
+
+// CalculateAlertTestSuite compares how long every alert fired in the job runs to how long it fired historically in
+// the job runs of the same release and platform, so that new always-firing alerts fail the aggregation.
+func (o *JobRunAggregatorAnalyzerOptions) CalculateAlertTestSuite(ctx context.Context, jobGCSBucketRoot string, finishedJobsToAggregate []jobrunaggregatorapi.JobRunInfo) (*junit.TestSuite, error) {
+	alertJunitSuite := &junit.TestSuite{
+		Name:      "Alerts",
+		TestCases: []*junit.TestCase{},
+	}
+	collectedDataTestCase := &junit.TestCase{
+		Name: "should collect alert data",
+	}
+	alertJunitSuite.TestCases = append(alertJunitSuite.TestCases, collectedDataTestCase)
+
+	jobRunIDToAlertSeconds, err := getAlertSecondsByJobRunID(ctx, finishedJobsToAggregate)
+	if len(jobRunIDToAlertSeconds) < 3 {
+		if err != nil {
+			return nil, err
+		}
+		// unlike disruption, not every job reports the alerts, so too little data skips the check instead of failing it
+		collectedDataTestCase.SkipMessage = &junit.SkipMessage{
+			Message: fmt.Sprintf("not enough data to aggregate: %d job runs reported alerts", len(jobRunIDToAlertSeconds)),
+		}
+		alertJunitSuite.NumSkipped++
+		return alertJunitSuite, nil
+	}
+	if err != nil {
+		// ignore the errors if we have at least three results
+		fmt.Fprintf(os.Stderr, "Could not fetch alert data for all runs %v\n", err)
+	}
+
+	for _, alert := range getAllAlerts(jobRunIDToAlertSeconds) {
+		jobRunIDToAlertSecondsForAlert := getAlertSecondsForAlert(jobRunIDToAlertSeconds, alert)
+		failedJobRunIDs, successfulJobRunIDs, status, message, err := o.passFailCalculator.CheckAlertDuration(ctx, jobRunIDToAlertSecondsForAlert, alert)
+		if err != nil {
+			return nil, err
+		}
+
+		testCaseName := fmt.Sprintf("%s should not fire longer than historically", alertTestName(alert))
+		junitTestCase, err := disruptionToJUnitTestCase(testCaseName, "aggregated-alerts", jobGCSBucketRoot, failedJobRunIDs, successfulJobRunIDs, status, message)
+		if err != nil {
+			return nil, err
+		}
+		alertJunitSuite.TestCases = append(alertJunitSuite.TestCases, junitTestCase)
+
+		switch status {
+		case testCaseFailed:
+			alertJunitSuite.NumFailed++
+		case testCaseSkipped:
+			alertJunitSuite.NumSkipped++
+		}
+	}
+
+	return alertJunitSuite, nil
+}
+
+func alertTestName(alert jobrunaggregatorlib.AlertKey) string {
+	if len(alert.Namespace) == 0 {
+		return fmt.Sprintf("alert/%s level/%s", alert.Name, alert.Level)
+	}
+	return fmt.Sprintf("alert/%s namespace/%s level/%s", alert.Name, alert.Namespace, alert.Level)
+}
+
+func getAlertSecondsByJobRunID(ctx context.Context, finishedJobsToAggregate []jobrunaggregatorapi.JobRunInfo) (map[string]map[jobrunaggregatorlib.AlertKey]int, error) {
+	jobRunIDToAlertSeconds := map[string]map[jobrunaggregatorlib.AlertKey]int{}
+
+	errs := []error{}
+	for i := range finishedJobsToAggregate {
+		jobRun := finishedJobsToAggregate[i]
+		rawAlertData, err := jobRun.GetOpenShiftTestsFilesWithPrefix(ctx, "alert")
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(rawAlertData) == 0 {
+			fmt.Fprintf(os.Stderr, "Could not fetch alert data for %s\n", jobRun.GetJobRunID())
+			continue
+		}
+
+		jobRunIDToAlertSeconds[jobRun.GetJobRunID()] = jobrunaggregatorlib.GetAlertSecondsFromDirectData(rawAlertData)
+	}
+
+	return jobRunIDToAlertSeconds, utilerrors.NewAggregate(errs)
+}
+
+// getAllAlerts returns the alerts which fired in any of the job runs, sorted
+func getAllAlerts(jobRunIDToAlertSeconds map[string]map[jobrunaggregatorlib.AlertKey]int) []jobrunaggregatorlib.AlertKey {
+	seen := map[jobrunaggregatorlib.AlertKey]bool{}
+	var alerts []jobrunaggregatorlib.AlertKey
+	for _, alertSeconds := range jobRunIDToAlertSeconds {
+		for alert := range alertSeconds {
+			if !seen[alert] {
+				seen[alert] = true
+				alerts = append(alerts, alert)
+			}
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return alertTestName(alerts[i]) < alertTestName(alerts[j])
+	})
+	return alerts
+}
+
+// getAlertSecondsForAlert returns a map of jobrunid to how long the alert fired, zero in the job runs which
+// reported alerts but not this one
+func getAlertSecondsForAlert(jobRunIDToAlertSeconds map[string]map[jobrunaggregatorlib.AlertKey]int, alert jobrunaggregatorlib.AlertKey) map[string]int {
+	jobRunIDToAlertSecondsForAlert := map[string]int{}
+	for jobRunID, alertSeconds := range jobRunIDToAlertSeconds {
+		jobRunIDToAlertSecondsForAlert[jobRunID] = alertSeconds[alert]
+	}
+	return jobRunIDToAlertSecondsForAlert
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path"
-	"strings"
 
 	"gopkg.in/yaml.v2"
 
@@ -17,31 +16,6 @@ import (
 	"github.com/openshift/ci-tools/pkg/junit"
 )
 
-// isExcludedDisruptionBackend returns true of any of the given backends are in the
-// disruption backend name.  Essentially, we want to skip testing of these disruption
-// backends for now as they run and gather more data.
-func isExcludedDisruptionBackend(name string) bool {
-	excludedNames := []string{
-		"ci-cluster-network-liveness",
-		"kube-api-http1-external-lb",
-		"kube-api-http2-external-lb",
-		"openshift-api-http2-external-lb",
-		"host-to-service",
-		"host-to-host",
-		"host-to-pod",
-		"pod-to-host",
-		"pod-to-pod",
-		"pod-to-service",
-	}
-
-	for _, excludedName := range excludedNames {
-		if strings.Contains(name, excludedName) {
-			return true
-		}
-	}
-	return false
-}
-
 func (o *JobRunAggregatorAnalyzerOptions) CalculateDisruptionTestSuite(ctx context.Context, jobGCSBucketRoot string, finishedJobsToAggregate []jobrunaggregatorapi.JobRunInfo, masterNodesUpdated string) (*junit.TestSuite, error) {
 	disruptionJunitSuite := &junit.TestSuite{
 		Name:      "BackendDisruption",
@@ -87,12 +61,16 @@ func (o *JobRunAggregatorAnalyzerOptions) CalculateDisruptionTestSuite(ctx conte
 		"%s disruption P85 should not be worse": checkPercentileDisruption(o.passFailCalculator, 85, 7), // for 5 attempts, this gives us a latch on getting worse.
 	}
 
+	disruptionBackends := o.disruptionBackends
+	if disruptionBackends == nil {
+		disruptionBackends = jobrunaggregatorlib.DefaultDisruptionBackends()
+	}
 	for _, testCaseNamePattern := range sets.StringKeySet(testCaseNamePatternToDisruptionCheckFn).List() {
 		disruptionCheckFn := testCaseNamePatternToDisruptionCheckFn[testCaseNamePattern]
 
-		allBackends := getAllDisruptionBackendNames(jobRunIDToBackendNameToAvailabilityResult)
+		allBackends := getAllDisruptionBackendNames(disruptionBackends, jobRunIDToBackendNameToAvailabilityResult)
 		for _, backendName := range sets.List(allBackends) {
-			if isExcludedDisruptionBackend(backendName) {
+			if disruptionBackends.IsExcludedFromAnalysis(backendName) {
 				continue
 			}
 			jobRunIDToAvailabilityResultForBackend := getDisruptionForBackend(jobRunIDToBackendNameToAvailabilityResult, backendName)
@@ -220,9 +198,10 @@ func getDisruptionForBackend(jobRunIDToBackendNameToAvailabilityResult map[strin
 	return jobRunIDToAvailabilityResultForBackend
 }
 
-func getAllDisruptionBackendNames(jobRunIDToBackendNameToAvailabilityResult map[string]map[string]jobrunaggregatorlib.AvailabilityResult) sets.Set[string] {
+// getAllDisruptionBackendNames returns the required backends and the ones reported by the job runs
+func getAllDisruptionBackendNames(disruptionBackends *jobrunaggregatorlib.DisruptionBackends, jobRunIDToBackendNameToAvailabilityResult map[string]map[string]jobrunaggregatorlib.AvailabilityResult) sets.Set[string] {
 	ret := sets.Set[string]{}
-	ret.Insert(jobrunaggregatorlib.RequiredDisruptionTests().List()...)
+	ret.Insert(disruptionBackends.Required...)
 	for _, curr := range jobRunIDToBackendNameToAvailabilityResult {
 		ret.Insert(sets.StringKeySet(curr).List()...)
 	}
@@ -0,0 +1,204 @@
+package jobrunaggregatoranalyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorlib"
+)
+
+const backfillDateLayout = "2006-01-02"
+
+type AggregationBackfillFlags struct {
+	// analyzer holds the flags shared with analyze-job-runs, the payload tag and the start time are set per payload
+	analyzer *JobRunsAnalyzerFlags
+
+	From   string
+	To     string
+	DryRun bool
+}
+
+func NewAggregationBackfillFlags() *AggregationBackfillFlags {
+	return &AggregationBackfillFlags{
+		analyzer: NewJobRunsAnalyzerFlags(),
+		To:       time.Now().UTC().Format(backfillDateLayout),
+	}
+}
+
+func (f *AggregationBackfillFlags) BindFlags(fs *pflag.FlagSet) {
+	f.analyzer.DataCoordinates.BindFlags(fs)
+	f.analyzer.Authentication.BindFlags(fs)
+
+	fs.StringVar(&f.analyzer.JobName, "job", f.analyzer.JobName, "The name of the job to backfill the aggregations of, like periodic-ci-openshift-release-master-ci-4.9-e2e-gcp-upgrade")
+	fs.StringVar(&f.analyzer.WorkingDir, "working-dir", f.analyzer.WorkingDir, "The directory to store caches, output, and the like.")
+	fs.StringVar(&f.analyzer.GCSBucket, "google-storage-bucket", "test-platform-results", "The optional GCS Bucket holding test artifacts")
+	fs.StringVar(&f.analyzer.PolicyFile, "policy-file", f.analyzer.PolicyFile, "The optional path to a file setting the minimum number of job runs and the pass rate required of the tests, overall and per test.")
+	fs.StringVar(&f.analyzer.OwnershipFile, "ownership-file", f.analyzer.OwnershipFile, "The optional path to a file assigning the tests to the components or SIGs owning them, to sum up the results by owner.")
+	fs.StringVar(&f.analyzer.DisruptionBackendsFile, "disruption-backends-file", f.analyzer.DisruptionBackendsFile, "The optional path to a file listing the disruption backends which are required, excluded from the analysis or ignored.")
+	fs.StringVar(&f.From, "from", f.From, fmt.Sprintf("The first day whose payloads are backfilled, like %s", backfillDateLayout))
+	fs.StringVar(&f.To, "to", f.To, fmt.Sprintf("The last day whose payloads are backfilled, like %s. Defaults to today.", backfillDateLayout))
+	fs.BoolVar(&f.DryRun, "dry-run", f.DryRun, "Only list the payloads whose job runs would be aggregated.")
+}
+
+func NewAggregationBackfillCommand() *cobra.Command {
+	f := NewAggregationBackfillFlags()
+
+	cmd := &cobra.Command{
+		Use:          "backfill",
+		Long:         `Aggregate the job runs of the payloads of a job whose aggregation never ran or errored, and record the outcome in BigQuery.`,
+		SilenceUsage: true,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			if err := f.Validate(); err != nil {
+				logrus.WithError(err).Fatal("Flags are invalid")
+			}
+			o, err := f.ToOptions(ctx)
+			if err != nil {
+				logrus.WithError(err).Fatal("Failed to build runtime options")
+			}
+
+			if err := o.Run(ctx); err != nil {
+				logrus.WithError(err).Fatal("Command failed")
+			}
+
+			return nil
+		},
+
+		Args: jobrunaggregatorlib.NoArgs,
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Validate checks to see if the user-input is likely to produce functional runtime options
+func (f *AggregationBackfillFlags) Validate() error {
+	if len(f.analyzer.WorkingDir) == 0 {
+		return fmt.Errorf("missing --working-dir: like job-aggregator-working-dir")
+	}
+	if len(f.analyzer.JobName) == 0 {
+		return fmt.Errorf("missing --job: like periodic-ci-openshift-release-master-ci-4.9-e2e-gcp-upgrade")
+	}
+	from, err := time.Parse(backfillDateLayout, f.From)
+	if err != nil {
+		return fmt.Errorf("invalid --from: %w", err)
+	}
+	to, err := time.Parse(backfillDateLayout, f.To)
+	if err != nil {
+		return fmt.Errorf("invalid --to: %w", err)
+	}
+	if to.Before(from) {
+		return fmt.Errorf("--to must not be before --from")
+	}
+	if err := f.analyzer.DataCoordinates.Validate(); err != nil {
+		return err
+	}
+	return f.analyzer.Authentication.Validate()
+}
+
+// ToOptions goes from the user input to the runtime values need to run the command.
+func (f *AggregationBackfillFlags) ToOptions(ctx context.Context) (*aggregationBackfillOptions, error) {
+	from, err := time.Parse(backfillDateLayout, f.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := time.Parse(backfillDateLayout, f.To)
+	if err != nil {
+		return nil, err
+	}
+
+	bigQueryClient, err := f.analyzer.Authentication.NewBigQueryClient(ctx, f.analyzer.DataCoordinates.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	ciDataClient := jobrunaggregatorlib.NewRetryingCIDataClient(
+		jobrunaggregatorlib.NewCIDataClient(*f.analyzer.DataCoordinates, bigQueryClient),
+	)
+
+	return &aggregationBackfillOptions{
+		jobName:      f.analyzer.JobName,
+		from:         from,
+		to:           to.Add(24 * time.Hour),
+		ciDataClient: ciDataClient,
+		aggregate: func(ctx context.Context, payload jobrunaggregatorapi.PayloadJobRunsRow) error {
+			analyzerFlags := *f.analyzer
+			analyzerFlags.PayloadTag = payload.ReleaseTag
+			analyzerFlags.EstimatedJobStartTimeString = payload.StartTime.Format(kubeTimeSerializationLayout)
+			analyzerFlags.RecordAggregation = true
+			if err := analyzerFlags.Validate(); err != nil {
+				return err
+			}
+			o, err := analyzerFlags.ToOptions(ctx)
+			if err != nil {
+				return err
+			}
+			return o.Run(ctx)
+		},
+		dryRun: f.DryRun,
+	}, nil
+}
+
+// aggregationBackfillOptions aggregates the job runs of the payloads of a job again, when their
+// aggregation was never recorded or the last recorded one errored
+type aggregationBackfillOptions struct {
+	jobName string
+	// from and to bound the start of the first job run of the payloads, to is exclusive
+	from time.Time
+	to   time.Time
+
+	ciDataClient jobrunaggregatorlib.AggregationBackfillClient
+	// aggregate aggregates the job runs of the payload and records the outcome
+	aggregate func(ctx context.Context, payload jobrunaggregatorapi.PayloadJobRunsRow) error
+	dryRun    bool
+}
+
+func (o *aggregationBackfillOptions) Run(ctx context.Context) error {
+	payloads, err := o.ciDataClient.ListPayloadsForJob(ctx, o.jobName, o.from, o.to)
+	if err != nil {
+		return fmt.Errorf("failed to list the payloads of %s: %w", o.jobName, err)
+	}
+	aggregationRuns, err := o.ciDataClient.ListAggregationRunsForJob(ctx, o.jobName, o.from)
+	if err != nil {
+		return fmt.Errorf("failed to list the aggregations of %s: %w", o.jobName, err)
+	}
+	lastAggregationRuns := map[string]jobrunaggregatorapi.AggregationRunRow{}
+	for _, aggregationRun := range aggregationRuns {
+		if last, ok := lastAggregationRuns[aggregationRun.PayloadTag]; !ok || aggregationRun.AggregationTime.After(last.AggregationTime) {
+			lastAggregationRuns[aggregationRun.PayloadTag] = aggregationRun
+		}
+	}
+
+	var errs []error
+	for _, payload := range payloads {
+		log := logrus.WithFields(logrus.Fields{"job": o.jobName, "payload": payload.ReleaseTag})
+		if last, ok := lastAggregationRuns[payload.ReleaseTag]; ok {
+			if last.Status != jobrunaggregatorapi.AggregationRunStatusError {
+				continue
+			}
+			log = log.WithField("erroredAt", last.AggregationTime.Format(time.RFC3339))
+		}
+		if o.dryRun {
+			log.Info("Would aggregate the job runs")
+			continue
+		}
+		log.Info("Aggregating the job runs")
+		// tests failing aggregation is an outcome like any other
+		if err := o.aggregate(ctx, payload); err != nil && !errors.Is(err, errTestsFailedAggregation) {
+			log.WithError(err).Error("Failed to aggregate the job runs")
+			errs = append(errs, fmt.Errorf("failed to aggregate the job runs of %s: %w", payload.ReleaseTag, err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
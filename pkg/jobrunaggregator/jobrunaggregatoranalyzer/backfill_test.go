@@ -0,0 +1,90 @@
+package jobrunaggregatoranalyzer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
+)
+
+type fakeBackfillClient struct {
+	payloads        []jobrunaggregatorapi.PayloadJobRunsRow
+	aggregationRuns []jobrunaggregatorapi.AggregationRunRow
+}
+
+func (c *fakeBackfillClient) ListPayloadsForJob(_ context.Context, _ string, _, _ time.Time) ([]jobrunaggregatorapi.PayloadJobRunsRow, error) {
+	return c.payloads, nil
+}
+
+func (c *fakeBackfillClient) ListAggregationRunsForJob(_ context.Context, _ string, _ time.Time) ([]jobrunaggregatorapi.AggregationRunRow, error) {
+	return c.aggregationRuns, nil
+}
+
+func TestAggregationBackfill(t *testing.T) {
+	start := time.Date(2023, 6, 18, 12, 0, 0, 0, time.UTC)
+	payloads := []jobrunaggregatorapi.PayloadJobRunsRow{
+		{ReleaseTag: "never-aggregated", StartTime: start},
+		{ReleaseTag: "passed", StartTime: start.Add(time.Hour)},
+		{ReleaseTag: "errored", StartTime: start.Add(2 * time.Hour)},
+		{ReleaseTag: "errored-then-failed", StartTime: start.Add(3 * time.Hour)},
+	}
+	aggregationRuns := []jobrunaggregatorapi.AggregationRunRow{
+		{PayloadTag: "passed", AggregationTime: start.Add(6 * time.Hour), Status: jobrunaggregatorapi.AggregationRunStatusPassed},
+		{PayloadTag: "errored", AggregationTime: start.Add(7 * time.Hour), Status: jobrunaggregatorapi.AggregationRunStatusError},
+		{PayloadTag: "errored-then-failed", AggregationTime: start.Add(9 * time.Hour), Status: jobrunaggregatorapi.AggregationRunStatusFailed},
+		{PayloadTag: "errored-then-failed", AggregationTime: start.Add(8 * time.Hour), Status: jobrunaggregatorapi.AggregationRunStatusError},
+	}
+
+	tests := []struct {
+		name               string
+		dryRun             bool
+		aggregationErrors  map[string]error
+		expectedAggregated []string
+		expectedError      bool
+	}{
+		{
+			name:               "payloads never aggregated or whose last aggregation errored are aggregated",
+			expectedAggregated: []string{"never-aggregated", "errored"},
+		},
+		{
+			name:   "nothing is aggregated in a dry run",
+			dryRun: true,
+		},
+		{
+			name:               "tests failing aggregation is not an error",
+			aggregationErrors:  map[string]error{"never-aggregated": errTestsFailedAggregation},
+			expectedAggregated: []string{"never-aggregated", "errored"},
+		},
+		{
+			name:               "errors do not stop the backfill",
+			aggregationErrors:  map[string]error{"never-aggregated": errors.New("not enough job runs")},
+			expectedAggregated: []string{"never-aggregated", "errored"},
+			expectedError:      true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var aggregated []string
+			o := &aggregationBackfillOptions{
+				jobName:      "job",
+				from:         start,
+				to:           start.Add(24 * time.Hour),
+				ciDataClient: &fakeBackfillClient{payloads: payloads, aggregationRuns: aggregationRuns},
+				aggregate: func(_ context.Context, payload jobrunaggregatorapi.PayloadJobRunsRow) error {
+					aggregated = append(aggregated, payload.ReleaseTag)
+					return tc.aggregationErrors[payload.ReleaseTag]
+				},
+				dryRun: tc.dryRun,
+			}
+			err := o.Run(context.Background())
+			if tc.expectedError != (err != nil) {
+				t.Fatalf("expected error %t, got: %v", tc.expectedError, err)
+			}
+			assert.Equal(t, tc.expectedAggregated, aggregated)
+		})
+	}
+}
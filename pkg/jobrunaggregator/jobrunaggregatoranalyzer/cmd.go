@@ -3,6 +3,9 @@ package jobrunaggregatoranalyzer
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -13,6 +16,7 @@ import (
 	prowjobclientset "k8s.io/test-infra/prow/client/clientset/versioned"
 	"k8s.io/utils/clock"
 
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
 	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorlib"
 )
 
@@ -28,10 +32,35 @@ type JobRunsAnalyzerFlags struct {
 	Timeout                     time.Duration
 	EstimatedJobStartTimeString string
 	JobStateQuerySource         string
+	JobSearchWindowStartOffset  time.Duration
+	JobSearchWindowEndOffset    time.Duration
+	MaxJobRunAge                time.Duration
 
 	StaticJobRunIdentifierPath string
 	StaticJobRunIdentifierJSON string
 	GCSBucket                  string
+
+	SlackWebhookURLPath string
+	ReportURL           string
+	PolicyFile          string
+	RecordAggregation   bool
+
+	DisruptionBackendsFile string
+	SippyURL               string
+	OwnershipFile          string
+
+	// Jobs are aggregated in one invocation instead of JobName, and their verdicts grouped by the
+	// variants of the jobs in the GroupBy dimensions
+	Jobs    []string
+	GroupBy []string
+
+	Watch             bool
+	WatchJobRuns      int
+	WatchPollInterval time.Duration
+
+	// Matcher is the name of the registered matcher selecting the job runs, configured with MatcherParams
+	Matcher       string
+	MatcherParams map[string]string
 }
 
 func NewJobRunsAnalyzerFlags() *JobRunsAnalyzerFlags {
@@ -42,6 +71,10 @@ func NewJobRunsAnalyzerFlags() *JobRunsAnalyzerFlags {
 		WorkingDir:                  "job-aggregator-working-dir",
 		EstimatedJobStartTimeString: time.Now().Format(kubeTimeSerializationLayout),
 		Timeout:                     5*time.Hour + 30*time.Minute,
+		JobSearchWindowStartOffset:  jobrunaggregatorlib.JobSearchWindowStartOffset,
+		JobSearchWindowEndOffset:    jobrunaggregatorlib.JobSearchWindowEndOffset,
+		GroupBy:                     append([]string{}, defaultVariantDimensions...),
+		WatchPollInterval:           jobrunaggregatorlib.DefaultWatchPollInterval,
 	}
 }
 
@@ -52,12 +85,23 @@ func (f *JobRunsAnalyzerFlags) BindFlags(fs *pflag.FlagSet) {
 	f.Authentication.BindFlags(fs)
 
 	fs.StringVar(&f.JobName, "job", f.JobName, "The name of the job to inspect, like periodic-ci-openshift-release-master-ci-4.9-e2e-gcp-upgrade")
+	fs.StringSliceVar(&f.Jobs, "jobs", f.Jobs, "mutually exclusive to --job. The names of the jobs to aggregate in one invocation, whose verdicts are grouped by the variants of the jobs.")
+	fs.StringSliceVar(&f.GroupBy, "group-by", f.GroupBy, fmt.Sprintf("The variants the verdicts of the --jobs are grouped by, among %s.", strings.Join(sets.List(sets.KeySet(variantDimensions)), ", ")))
 	fs.StringVar(&f.WorkingDir, "working-dir", f.WorkingDir, "The directory to store caches, output, and the like.")
 	fs.StringVar(&f.PayloadTag, "payload-tag", f.PayloadTag, "The payload tag to aggregate, like 4.9.0-0.ci-2021-07-19-185802")
-	fs.StringVar(&f.AggregationID, "aggregation-id", f.AggregationID, "mutually exclusive to --payload-tag.  Matches the .label[release.openshift.io/aggregation-id] on the prowjob, which is a UID")
+	fs.StringVar(&f.AggregationID, "aggregation-id", f.AggregationID, fmt.Sprintf("mutually exclusive to --payload-tag.  Matches the .label[%s] on the prowjob, which is a UID", jobrunaggregatorlib.ProwJobAggregationIDLabel))
+	fs.StringVar(&f.Matcher, "matcher", f.Matcher, fmt.Sprintf("The matcher selecting the job runs to aggregate, among %s. Defaults to %s with --payload-tag and %s with --aggregation-id.",
+		strings.Join(jobrunaggregatorlib.RegisteredProwJobMatchers(), ", "), jobrunaggregatorlib.ProwJobMatcherPayloadTag, jobrunaggregatorlib.ProwJobMatcherPerPR))
+	fs.StringToStringVar(&f.MatcherParams, "matcher-param", f.MatcherParams, "The parameters of the --matcher, like label-selector=ci.openshift.io/rehearse=1. --payload-tag and --aggregation-id set the parameters of the same name.")
 	fs.StringVar(&f.ExplicitGCSPrefix, "explicit-gcs-prefix", f.ExplicitGCSPrefix, "only used by per PR payload promotion jobs.  This overrides the well-known mapping and becomes the required prefix for the GCS query")
 	fs.DurationVar(&f.Timeout, "timeout", f.Timeout, "Time to wait for aggregation to complete.")
 	fs.StringVar(&f.EstimatedJobStartTimeString, "job-start-time", f.EstimatedJobStartTimeString, fmt.Sprintf("Start time in RFC822Z: %s", kubeTimeSerializationLayout))
+	fs.DurationVar(&f.JobSearchWindowStartOffset, "job-search-window-start-offset", f.JobSearchWindowStartOffset, "Only job runs started at most this long before --job-start-time are located.")
+	fs.DurationVar(&f.JobSearchWindowEndOffset, "job-search-window-end-offset", f.JobSearchWindowEndOffset, "Only job runs started at most this long after --job-start-time are located.")
+	fs.DurationVar(&f.MaxJobRunAge, "max-job-run-age", f.MaxJobRunAge, "If set, job runs started longer ago than this are not located, regardless of --job-start-time.")
+	fs.BoolVar(&f.Watch, "watch", f.Watch, "Read the job runs as they finish and aggregate once --watch-job-runs of them finished or --timeout is near, instead of waiting for all of them to finish.")
+	fs.IntVar(&f.WatchJobRuns, "watch-job-runs", f.WatchJobRuns, "The number of finished job runs --watch aggregates, the minimum number of job runs of the --policy-file when unset.")
+	fs.DurationVar(&f.WatchPollInterval, "watch-poll-interval", f.WatchPollInterval, "How often --watch locates the job runs and checks whether they finished.")
 	fs.StringVar(&f.JobStateQuerySource, "query-source", jobrunaggregatorlib.JobStateQuerySourceBigQuery, "The source from which job states are found. It is either bigquery or cluster")
 
 	// optional for local use or potentially gangway results
@@ -65,6 +109,14 @@ func (f *JobRunsAnalyzerFlags) BindFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&f.StaticJobRunIdentifierJSON, "static-run-info-json", f.StaticJobRunIdentifierJSON, "The optional JSON formatted string of JobRunIdentifier array used for aggregated analysis")
 
 	fs.StringVar(&f.GCSBucket, "google-storage-bucket", "test-platform-results", "The optional GCS Bucket holding test artifacts")
+
+	fs.StringVar(&f.SlackWebhookURLPath, "slack-webhook-url-path", f.SlackWebhookURLPath, "Path to the file containing the Slack webhook URL to post the verdict of the aggregation to. No notification is sent when unset.")
+	fs.StringVar(&f.ReportURL, "report-url", f.ReportURL, "The URL at which the aggregation report is published, linked from the notifications.")
+	fs.StringVar(&f.PolicyFile, "policy-file", f.PolicyFile, "The optional path to a file setting the minimum number of job runs and the pass rate required of the tests, overall and per test.")
+	fs.StringVar(&f.DisruptionBackendsFile, "disruption-backends-file", f.DisruptionBackendsFile, "The optional path to a file listing the disruption backends which are required, excluded from the analysis or ignored.")
+	fs.StringVar(&f.OwnershipFile, "ownership-file", f.OwnershipFile, "The optional path to a file assigning the tests to the components or SIGs owning them, to sum up the results by owner.")
+	fs.StringVar(&f.SippyURL, "sippy-url", f.SippyURL, "The URL of the Sippy endpoint to POST the results of the aggregation to. They are always written to aggregation-sippy.json.")
+	fs.BoolVar(&f.RecordAggregation, "record-aggregation", f.RecordAggregation, "Record the outcome of the aggregation in BigQuery, so that the backfill command can run the aggregations that did not complete again.")
 }
 
 func NewJobRunsAnalyzerCommand() *cobra.Command {
@@ -81,6 +133,16 @@ func NewJobRunsAnalyzerCommand() *cobra.Command {
 			if err := f.Validate(); err != nil {
 				logrus.WithError(err).Fatal("Flags are invalid")
 			}
+			if len(f.Jobs) > 0 {
+				o, err := f.ToVariantOptions(ctx)
+				if err != nil {
+					logrus.WithError(err).Fatal("Failed to build runtime options")
+				}
+				if err := o.Run(ctx); err != nil {
+					logrus.WithError(err).Fatal("Command failed")
+				}
+				return nil
+			}
 			o, err := f.ToOptions(ctx)
 			if err != nil {
 				logrus.WithError(err).Fatal("Failed to build runtime options")
@@ -106,9 +168,22 @@ func (f *JobRunsAnalyzerFlags) Validate() error {
 	if len(f.WorkingDir) == 0 {
 		return fmt.Errorf("missing --working-dir: like job-aggregator-working-dir")
 	}
-	if len(f.JobName) == 0 {
+	if len(f.JobName) == 0 && len(f.Jobs) == 0 {
 		return fmt.Errorf("missing --job: like periodic-ci-openshift-release-master-ci-4.9-e2e-gcp-upgrade")
 	}
+	if len(f.Jobs) > 0 {
+		if len(f.JobName) > 0 {
+			return fmt.Errorf("cannot specify both --job and --jobs")
+		}
+		if len(f.ExplicitGCSPrefix) > 0 || len(f.StaticJobRunIdentifierPath) > 0 || len(f.StaticJobRunIdentifierJSON) > 0 {
+			return fmt.Errorf("--explicit-gcs-prefix and --static-run-info-* locate the job runs of a single job and cannot be used with --jobs")
+		}
+		for _, dimension := range f.GroupBy {
+			if _, ok := variantDimensions[dimension]; !ok {
+				return fmt.Errorf("unknown --group-by variant %s, valid values are: %+q", dimension, sets.List(sets.KeySet(variantDimensions)))
+			}
+		}
+	}
 	if _, err := time.Parse(kubeTimeSerializationLayout, f.EstimatedJobStartTimeString); err != nil {
 		return err
 	}
@@ -127,6 +202,23 @@ func (f *JobRunsAnalyzerFlags) Validate() error {
 	if len(f.AggregationID) > 0 && len(f.ExplicitGCSPrefix) == 0 {
 		return fmt.Errorf("if --aggregation-id is specified, you must specify --explicit-gcs-prefix")
 	}
+	if f.JobSearchWindowStartOffset < 0 || f.JobSearchWindowEndOffset < 0 {
+		return fmt.Errorf("--job-search-window-start-offset and --job-search-window-end-offset must not be negative")
+	}
+	if f.MaxJobRunAge < 0 {
+		return fmt.Errorf("--max-job-run-age must not be negative")
+	}
+	if len(f.Jobs) == 0 {
+		if _, err := jobrunaggregatorlib.NewRegisteredProwJobMatcherFunc(f.prowJobMatcher(), f.JobName, f.prowJobMatcherParams()); err != nil {
+			return fmt.Errorf("invalid --matcher: %w", err)
+		}
+	}
+	if f.WatchJobRuns < 0 {
+		return fmt.Errorf("--watch-job-runs must not be negative")
+	}
+	if f.Watch && f.WatchPollInterval <= 0 {
+		return fmt.Errorf("--watch-poll-interval must be positive")
+	}
 	if len(f.JobStateQuerySource) > 0 {
 		if _, ok := jobrunaggregatorlib.KnownQuerySources[f.JobStateQuerySource]; !ok {
 			return fmt.Errorf("unknown query-source %s, valid values are: %+q", f.JobStateQuerySource, sets.List(jobrunaggregatorlib.KnownQuerySources))
@@ -136,6 +228,39 @@ func (f *JobRunsAnalyzerFlags) Validate() error {
 	return nil
 }
 
+// prowJobMatcher is the name of the matcher selecting the job runs
+func (f *JobRunsAnalyzerFlags) prowJobMatcher() string {
+	switch {
+	case len(f.Matcher) > 0:
+		return f.Matcher
+	case len(f.AggregationID) > 0:
+		return jobrunaggregatorlib.ProwJobMatcherPerPR
+	default:
+		return jobrunaggregatorlib.ProwJobMatcherPayloadTag
+	}
+}
+
+// prowJobMatcherParams are the --matcher-param, with --payload-tag and --aggregation-id for the matchers taking them
+func (f *JobRunsAnalyzerFlags) prowJobMatcherParams() map[string]string {
+	params := map[string]string{}
+	for name, value := range f.MatcherParams {
+		params[name] = value
+	}
+	registration, _ := jobrunaggregatorlib.LookupProwJobMatcher(f.prowJobMatcher())
+	for _, param := range registration.Params {
+		if _, ok := params[param.Name]; ok {
+			continue
+		}
+		switch {
+		case param.Name == "payload-tag" && len(f.PayloadTag) > 0:
+			params[param.Name] = f.PayloadTag
+		case param.Name == "aggregation-id" && len(f.AggregationID) > 0:
+			params[param.Name] = f.AggregationID
+		}
+	}
+	return params
+}
+
 // ToOptions goes from the user input to the runtime values need to run the command.
 // Expect to see unit tests on the options, but not on the flags which are simply value mappings.
 func (f *JobRunsAnalyzerFlags) ToOptions(ctx context.Context) (*JobRunAggregatorAnalyzerOptions, error) {
@@ -165,32 +290,28 @@ func (f *JobRunsAnalyzerFlags) ToOptions(ctx context.Context) (*JobRunAggregator
 		}
 	}
 
-	var jobRunLocator jobrunaggregatorlib.JobRunLocator
-	var prowJobMatcherFunc jobrunaggregatorlib.ProwJobMatcherFunc
-	if len(f.PayloadTag) > 0 {
-		jobRunLocator = jobrunaggregatorlib.NewPayloadAnalysisJobLocatorForReleaseController(
-			f.JobName,
-			f.PayloadTag,
-			estimatedStartTime,
-			ciDataClient,
-			ciGCSClient,
-			f.GCSBucket,
-		)
-		prowJobMatcherFunc = jobrunaggregatorlib.NewProwJobMatcherFuncForReleaseController(f.JobName, f.PayloadTag)
-	}
-	if len(f.AggregationID) > 0 {
-		jobRunLocator = jobrunaggregatorlib.NewPayloadAnalysisJobLocatorForPR(
-			f.JobName,
-			f.AggregationID,
-			jobrunaggregatorlib.ProwJobAggregationIDLabel,
-			estimatedStartTime,
-			ciDataClient,
-			ciGCSClient,
-			f.GCSBucket,
-			f.ExplicitGCSPrefix,
-		)
-		prowJobMatcherFunc = jobrunaggregatorlib.NewProwJobMatcherFuncForPR(f.JobName, f.AggregationID, jobrunaggregatorlib.ProwJobAggregationIDLabel)
+	locatorOpts := []jobrunaggregatorlib.JobRunLocatorOption{
+		jobrunaggregatorlib.WithJobSearchWindow(f.JobSearchWindowStartOffset, f.JobSearchWindowEndOffset),
+		jobrunaggregatorlib.WithMaxJobRunAge(f.MaxJobRunAge),
 	}
+	prowJobMatcherFunc, err := jobrunaggregatorlib.NewRegisteredProwJobMatcherFunc(f.prowJobMatcher(), f.JobName, f.prowJobMatcherParams())
+	if err != nil {
+		return nil, err
+	}
+	gcsPrefix := "logs/" + f.JobName
+	if len(f.ExplicitGCSPrefix) > 0 {
+		gcsPrefix = f.ExplicitGCSPrefix
+	}
+	jobRunLocator := jobrunaggregatorlib.NewPayloadAnalysisJobLocator(
+		f.JobName,
+		prowJobMatcherFunc,
+		estimatedStartTime,
+		ciDataClient,
+		ciGCSClient,
+		f.GCSBucket,
+		gcsPrefix,
+		locatorOpts...,
+	)
 
 	var prowJobClient *prowjobclientset.Clientset
 	if f.JobStateQuerySource != jobrunaggregatorlib.JobStateQuerySourceBigQuery {
@@ -200,10 +321,59 @@ func (f *JobRunsAnalyzerFlags) ToOptions(ctx context.Context) (*JobRunAggregator
 		}
 	}
 
+	policy := defaultAggregationPolicy()
+	if len(f.PolicyFile) > 0 {
+		policy, err = LoadAggregationPolicy(f.PolicyFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	watchJobRuns := f.WatchJobRuns
+	if watchJobRuns == 0 {
+		watchJobRuns = policy.MinimumJobRuns
+	}
+
+	disruptionBackends := jobrunaggregatorlib.DefaultDisruptionBackends()
+	if len(f.DisruptionBackendsFile) > 0 {
+		disruptionBackends, err = jobrunaggregatorlib.LoadDisruptionBackends(f.DisruptionBackendsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var notifier aggregationNotifier
+	if len(f.SlackWebhookURLPath) > 0 {
+		webhookURL, err := os.ReadFile(f.SlackWebhookURLPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the Slack webhook URL: %w", err)
+		}
+		notifier = newSlackNotifier(strings.TrimSpace(string(webhookURL)), f.ReportURL)
+	}
+
+	var testOwnership *TestOwnership
+	if len(f.OwnershipFile) > 0 {
+		testOwnership, err = LoadTestOwnership(f.OwnershipFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var exporter *sippyExporter
+	if len(f.SippyURL) > 0 {
+		exporter = newSippyExporter(f.SippyURL)
+	}
+
+	var aggregationRunInserter jobrunaggregatorlib.BigQueryInserter
+	if f.RecordAggregation {
+		aggregationRunInserter = jobrunaggregatorlib.NewInstrumentedInserter(jobrunaggregatorapi.AggregationRunsTableName,
+			bigQueryClient.Dataset(f.DataCoordinates.DataSetID).Table(jobrunaggregatorapi.AggregationRunsTableName).Inserter())
+	}
+
 	return &JobRunAggregatorAnalyzerOptions{
 		explicitGCSPrefix:       f.ExplicitGCSPrefix,
 		jobRunLocator:           jobRunLocator,
-		passFailCalculator:      newWeeklyAverageFromTenDaysAgo(f.JobName, estimatedStartTime, 6, ciDataClient),
+		passFailCalculator:      newWeeklyAverageFromTenDaysAgo(f.JobName, estimatedStartTime, policy, ciDataClient),
 		jobName:                 f.JobName,
 		payloadTag:              f.PayloadTag,
 		workingDir:              f.WorkingDir,
@@ -215,5 +385,47 @@ func (f *JobRunsAnalyzerFlags) ToOptions(ctx context.Context) (*JobRunAggregator
 		prowJobMatcherFunc:      prowJobMatcherFunc,
 		staticJobRunIdentifiers: staticJobRunIdentifiers,
 		gcsBucket:               f.GCSBucket,
+		aggregationID:           f.AggregationID,
+		notifier:                notifier,
+		aggregationRunInserter:  aggregationRunInserter,
+		disruptionBackends:      disruptionBackends,
+		sippyExporter:           exporter,
+		testOwnership:           testOwnership,
+		watch:                   f.Watch,
+		watchJobRuns:            watchJobRuns,
+		watchPollInterval:       f.WatchPollInterval,
+	}, nil
+}
+
+// ToVariantOptions goes from the user input to the runtime values needed to aggregate the --jobs.
+func (f *JobRunsAnalyzerFlags) ToVariantOptions(ctx context.Context) (*jobVariantAggregatorOptions, error) {
+	bigQueryClient, err := f.Authentication.NewBigQueryClient(ctx, f.DataCoordinates.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	ciDataClient := jobrunaggregatorlib.NewRetryingCIDataClient(
+		jobrunaggregatorlib.NewCIDataClient(*f.DataCoordinates, bigQueryClient),
+	)
+
+	return &jobVariantAggregatorOptions{
+		jobNames:   f.Jobs,
+		dimensions: f.GroupBy,
+		workingDir: f.WorkingDir,
+		jobLister:  ciDataClient,
+		aggregate: func(ctx context.Context, jobName string) error {
+			jobFlags := *f
+			jobFlags.JobName = jobName
+			jobFlags.Jobs = nil
+			// the aggregations of the jobs would write the same files in the working directory otherwise
+			jobFlags.WorkingDir = filepath.Join(f.WorkingDir, jobName)
+			if err := jobFlags.Validate(); err != nil {
+				return err
+			}
+			o, err := jobFlags.ToOptions(ctx)
+			if err != nil {
+				return err
+			}
+			return o.Run(ctx)
+		},
 	}, nil
 }
@@ -0,0 +1,54 @@
+package jobrunaggregatoranalyzer
+
+import "math"
+
+// significance is the p-value below which the passes of an aggregation are considered significantly
+// fewer than the historical passes
+const significance = 0.05
+
+// requiredPassesComparedToHistory is the number of passes out of the attempts a test needs, so that
+// its passes are not significantly fewer than its historical passes according to a one-sided Fisher
+// exact test. Like the tables generated by required-pass-rate.py, it is one less than the lowest number
+// of passes that is not significant.
+func requiredPassesComparedToHistory(historicalPasses, historicalFailures, attempts int) int {
+	for passes := 0; passes <= attempts; passes++ {
+		if fisherExactGreater(historicalPasses, passes, historicalFailures, attempts-passes) <= significance {
+			continue
+		}
+		if passes == 0 {
+			return 0
+		}
+		return passes - 1
+	}
+	return 0
+}
+
+// fisherExactGreater is the p-value of the one-sided Fisher exact test of the table
+//
+//	[[a, b],
+//	 [c, d]]
+//
+// for the alternative hypothesis that the odds ratio is greater than one, like scipy.stats.fisher_exact
+// with alternative="greater": the probability that the top left cell holds a or more with the same margins.
+func fisherExactGreater(a, b, c, d int) float64 {
+	row, column, total := a+b, a+c, a+b+c+d
+	maxTopLeft := row
+	if column < maxTopLeft {
+		maxTopLeft = column
+	}
+	p := 0.0
+	for x := a; x <= maxTopLeft; x++ {
+		p += math.Exp(logChoose(row, x) + logChoose(total-row, column-x) - logChoose(total, column))
+	}
+	return math.Min(p, 1)
+}
+
+func logChoose(n, k int) float64 {
+	if k < 0 || k > n {
+		return math.Inf(-1)
+	}
+	nFactorial, _ := math.Lgamma(float64(n + 1))
+	kFactorial, _ := math.Lgamma(float64(k + 1))
+	nMinusKFactorial, _ := math.Lgamma(float64(n - k + 1))
+	return nFactorial - kFactorial - nMinusKFactorial
+}
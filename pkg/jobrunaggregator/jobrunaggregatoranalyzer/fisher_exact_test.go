@@ -0,0 +1,21 @@
+package jobrunaggregatoranalyzer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRequiredPassesComparedToHistoryMatchesTables(t *testing.T) {
+	// the tables are generated by required-pass-rate.py for a history of 250 job runs, the ones for
+	// fewer than three attempts were raised by hand
+	const corpusSize = 250
+	for attempts := 3; attempts < len(requiredPassesByPassPercentageByNumberOfAttempts); attempts++ {
+		for percentage := 0; percentage < 100; percentage++ {
+			passes := int(math.RoundToEven(corpusSize * float64(percentage) / 100))
+			expected := requiredPassesByPassPercentageByNumberOfAttempts[attempts][percentage]
+			if actual := requiredPassesComparedToHistory(passes, corpusSize-passes, attempts); actual != expected {
+				t.Errorf("%d attempts at %d%%: expected %d required passes, got %d", attempts, percentage, expected, actual)
+			}
+		}
+	}
+}
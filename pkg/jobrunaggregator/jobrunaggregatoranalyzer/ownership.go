@@ -0,0 +1,112 @@
+package jobrunaggregatoranalyzer
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+)
+
+// unownedTests owns the tests no pattern of the ownership file matches
+const unownedTests = "unowned"
+
+// TestOwnership assigns the aggregated tests to the components or SIGs owning them, so that a
+// failed aggregation can be routed to the owners of the failed tests. It is read from the file
+// given with --ownership-file, like:
+//
+//	owners:
+//	- namePattern: '\[sig-network\]'
+//	  owner: sig-network
+//	- namePattern: 'disruption'
+//	  owner: sig-api-machinery
+type TestOwnership struct {
+	// Owners own the tests whose name matches their pattern. The first matching owner owns the test.
+	Owners []TestOwner `json:"owners"`
+}
+
+// TestOwner owns the tests whose name matches the pattern
+type TestOwner struct {
+	// NamePattern is a regular expression matched against the test name
+	NamePattern string `json:"namePattern"`
+	Owner       string `json:"owner"`
+
+	nameRegexp *regexp.Regexp
+}
+
+// LoadTestOwnership reads and validates the ownership file
+func LoadTestOwnership(path string) (*TestOwnership, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ownership file: %w", err)
+	}
+	ownership := &TestOwnership{}
+	if err := yaml.UnmarshalStrict(raw, ownership); err != nil {
+		return nil, fmt.Errorf("failed to parse ownership file %s: %w", path, err)
+	}
+	for i := range ownership.Owners {
+		owner := &ownership.Owners[i]
+		if len(owner.Owner) == 0 {
+			return nil, fmt.Errorf("invalid ownership file %s: owners[%d]: owner must be set", path, i)
+		}
+		nameRegexp, err := regexp.Compile(owner.NamePattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ownership file %s: owners[%d]: invalid namePattern: %w", path, i, err)
+		}
+		owner.nameRegexp = nameRegexp
+	}
+	return ownership, nil
+}
+
+// ownerOf returns the owner of the test
+func (o *TestOwnership) ownerOf(testName string) string {
+	for _, owner := range o.Owners {
+		if owner.nameRegexp != nil && owner.nameRegexp.MatchString(testName) {
+			return owner.Owner
+		}
+	}
+	return unownedTests
+}
+
+// ownerSummary sums up the results of the tests of an owner
+type ownerSummary struct {
+	Owner   string
+	Passed  int
+	Failed  int
+	Skipped int
+	// FailedTests are the names of the failed tests of the owner
+	FailedTests []string
+}
+
+// summarizeOwners sums up the results of the rows by owner, the owners with the most failed tests first
+func summarizeOwners(rows []reportRow) []ownerSummary {
+	summaries := map[string]*ownerSummary{}
+	for _, row := range rows {
+		summary, ok := summaries[row.Owner]
+		if !ok {
+			summary = &ownerSummary{Owner: row.Owner}
+			summaries[row.Owner] = summary
+		}
+		switch row.Status {
+		case reportResultFailed:
+			summary.Failed++
+			summary.FailedTests = append(summary.FailedTests, row.Name)
+		case reportResultSkipped:
+			summary.Skipped++
+		default:
+			summary.Passed++
+		}
+	}
+	var ret []ownerSummary
+	for _, summary := range summaries {
+		ret = append(ret, *summary)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Failed != ret[j].Failed {
+			return ret[i].Failed > ret[j].Failed
+		}
+		return ret[i].Owner < ret[j].Owner
+	})
+	return ret
+}
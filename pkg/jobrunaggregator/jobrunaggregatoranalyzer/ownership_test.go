@@ -0,0 +1,106 @@
+package jobrunaggregatoranalyzer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadTestOwnership(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expected      map[string]string
+		expectedError bool
+	}{
+		{
+			name: "first matching owner owns the test",
+			content: `owners:
+- namePattern: '\[sig-network\]'
+  owner: sig-network
+- namePattern: 'sig-'
+  owner: catch-all
+`,
+			expected: map[string]string{
+				"[sig-network] pods can talk": "sig-network",
+				"[sig-storage] volumes mount": "catch-all",
+				"disruption":                  unownedTests,
+			},
+		},
+		{
+			name:          "missing owner",
+			content:       "owners:\n- namePattern: 'sig-'",
+			expectedError: true,
+		},
+		{
+			name:          "invalid pattern",
+			content:       "owners:\n- namePattern: '['\n  owner: sig-network",
+			expectedError: true,
+		},
+		{
+			name:          "unknown field",
+			content:       "owner:\n- namePattern: 'sig-'",
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "ownership.yaml")
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("failed to write ownership file: %v", err)
+			}
+			ownership, err := LoadTestOwnership(path)
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			for testName, owner := range tc.expected {
+				assert.Equal(t, owner, ownership.ownerOf(testName), testName)
+			}
+		})
+	}
+}
+
+func TestAssignOwners(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ownership.yaml")
+	if err := os.WriteFile(path, []byte("owners:\n- namePattern: 'network'\n  owner: sig-network\n"), 0644); err != nil {
+		t.Fatalf("failed to write ownership file: %v", err)
+	}
+	ownership, err := LoadTestOwnership(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report := aggregationReport{
+		JobName: "job",
+		Tests: []reportRow{
+			{Name: "network a", Status: reportResultFailed},
+			{Name: "network b", Status: reportResultFailed},
+			{Name: "network c", Status: reportResultPassed},
+			{Name: "storage", Status: reportResultSkipped},
+		},
+		Disruptions: []reportRow{
+			{Name: "disruption", Status: reportResultFailed},
+		},
+	}
+	report.assignOwners(ownership)
+	assert.Equal(t, []ownerSummary{
+		{Owner: "sig-network", Failed: 2, Passed: 1, FailedTests: []string{"network a", "network b"}},
+		{Owner: unownedTests, Failed: 1, Skipped: 1, FailedTests: []string{"disruption"}},
+	}, report.Owners)
+	assert.Equal(t, "sig-network", report.Tests[0].Owner)
+	assert.Equal(t, unownedTests, report.Disruptions[0].Owner)
+
+	out := &bytes.Buffer{}
+	if err := writeAggregationReport(report, out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Contains(t, out.String(), "<h2>Owners</h2>")
+	assert.Contains(t, out.String(), "<td>network a<br/>network b</td>")
+	assert.Contains(t, verdictMessage(report, "").Text, "Failed tests by owner: sig-network (2), unowned (1)")
+	assert.Equal(t, "sig-network", newSippyAggregation(report).Owners[0].Owner)
+}
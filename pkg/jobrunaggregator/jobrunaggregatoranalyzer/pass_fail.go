@@ -34,6 +34,7 @@ type baseline interface {
 	CheckDisruptionMeanWithinOneStandardDeviation(ctx context.Context, jobRunIDToAvailabilityResultForBackend map[string]jobrunaggregatorlib.AvailabilityResult, backend, masterNodesUpdated string) (failedJobRunsIDs []string, successfulJobRunIDs []string, status testCaseStatus, message string, err error)
 	CheckPercentileDisruption(ctx context.Context, jobRunIDToAvailabilityResultForBackend map[string]jobrunaggregatorlib.AvailabilityResult,
 		backend string, percentile int, fixedGraceSeconds int, masterNodesUpdated string) (failureJobRunIDs []string, successJobRunIDs []string, status testCaseStatus, message string, err error)
+	CheckAlertDuration(ctx context.Context, jobRunIDToAlertSeconds map[string]int, alert jobrunaggregatorlib.AlertKey) (failureJobRunIDs []string, successJobRunIDs []string, status testCaseStatus, message string, err error)
 }
 
 func assignPassFail(ctx context.Context, jobName string, combined *junit.TestSuites, baselinePassFail baseline) error {
@@ -148,10 +149,10 @@ func getWorkingPercentage(testCaseDetails *TestCaseDetails) float32 {
 // weeklyAverageFromTenDays gets the weekly average pass rate from ten days ago so that the latest tests do not
 // influence the pass/fail criteria.
 type weeklyAverageFromTenDays struct {
-	jobName                 string
-	startDay                time.Time
-	minimumNumberOfAttempts int
-	bigQueryClient          jobrunaggregatorlib.CIDataClient
+	jobName        string
+	startDay       time.Time
+	policy         *AggregationPolicy
+	bigQueryClient jobrunaggregatorlib.CIDataClient
 
 	queryTestRunsOnce        sync.Once
 	queryTestRunsErr         error
@@ -161,6 +162,10 @@ type weeklyAverageFromTenDays struct {
 	queryDisruptionErr  error
 	disruptionByBackend map[string]backendDisruptionStats
 	fallBackJobName     string
+
+	queryAlertsOnce sync.Once
+	queryAlertsErr  error
+	alertStatistics map[jobrunaggregatorlib.AlertKey]jobrunaggregatorapi.AlertStatisticsRow
 }
 
 type TestKey struct {
@@ -168,13 +173,13 @@ type TestKey struct {
 	CombinedTestSuiteName string
 }
 
-func newWeeklyAverageFromTenDaysAgo(jobName string, startDay time.Time, minimumNumberOfAttempts int, bigQueryClient jobrunaggregatorlib.CIDataClient) baseline {
+func newWeeklyAverageFromTenDaysAgo(jobName string, startDay time.Time, policy *AggregationPolicy, bigQueryClient jobrunaggregatorlib.CIDataClient) baseline {
 	tenDayAgo := jobrunaggregatorlib.GetUTCDay(startDay).Add(-10 * 24 * time.Hour)
 
 	return &weeklyAverageFromTenDays{
 		jobName:                  jobName,
 		startDay:                 tenDayAgo,
-		minimumNumberOfAttempts:  minimumNumberOfAttempts,
+		policy:                   policy,
 		bigQueryClient:           bigQueryClient,
 		queryTestRunsOnce:        sync.Once{},
 		queryTestRunsErr:         nil,
@@ -548,6 +553,65 @@ func (a *weeklyAverageFromTenDays) innerCheckPercentileDisruptionWithGrace(
 	return requiredNumberOfPasses, failureJobRunIDs, successJobRunIDs, testCasePassed, summary
 }
 
+// alertGraceSeconds is added to the historical P99 of an alert, so that alerts which never fired before are
+// allowed to fire briefly
+const alertGraceSeconds = 30
+
+func (a *weeklyAverageFromTenDays) getAlertStatistics(ctx context.Context) (map[jobrunaggregatorlib.AlertKey]jobrunaggregatorapi.AlertStatisticsRow, error) {
+	a.queryAlertsOnce.Do(func() {
+		rows, err := a.bigQueryClient.GetAlertStatisticsByJob(ctx, a.jobName, a.startDay)
+		if err != nil {
+			a.queryAlertsErr = err
+			return
+		}
+		a.alertStatistics = map[jobrunaggregatorlib.AlertKey]jobrunaggregatorapi.AlertStatisticsRow{}
+		for _, row := range rows {
+			key := jobrunaggregatorlib.AlertKey{Name: row.AlertName, Namespace: row.AlertNamespace, Level: jobrunaggregatorlib.AlertLevel(row.AlertLevel)}
+			a.alertStatistics[key] = row
+		}
+	})
+	return a.alertStatistics, a.queryAlertsErr
+}
+
+// CheckAlertDuration fails an alert which fired longer than its historical P99 in more than half of the job runs.
+// An alert which has no history is held to the grace period alone, so that new always-firing alerts fail.
+func (a *weeklyAverageFromTenDays) CheckAlertDuration(ctx context.Context, jobRunIDToAlertSeconds map[string]int, alert jobrunaggregatorlib.AlertKey) ([]string, []string, testCaseStatus, string, error) {
+	alertStatistics, err := a.getAlertStatistics(ctx)
+	if err != nil {
+		message := fmt.Sprintf("error getting historical alert data, skipping: %v\n", err)
+		return sets.StringKeySet(jobRunIDToAlertSeconds).List(), []string{}, testCaseSkipped, message, nil
+	}
+	historical := alertStatistics[alert]
+	threshold := historical.P99 + alertGraceSeconds
+
+	failureJobRunIDs := []string{}
+	successJobRunIDs := []string{}
+	for _, jobRunID := range sets.StringKeySet(jobRunIDToAlertSeconds).List() {
+		if float64(jobRunIDToAlertSeconds[jobRunID]) > threshold {
+			failureJobRunIDs = append(failureJobRunIDs, jobRunID)
+		} else {
+			successJobRunIDs = append(successJobRunIDs, jobRunID)
+		}
+	}
+	runs := func(jobRunIDs []string) []string {
+		var ret []string
+		for _, jobRunID := range jobRunIDs {
+			ret = append(ret, fmt.Sprintf("%s=%ds", jobRunID, jobRunIDToAlertSeconds[jobRunID]))
+		}
+		return ret
+	}
+	details := fmt.Sprintf("(P95=%.2fs P99=%.2fs historicalJobRuns=%d grace=%d successes=%v failures=%v)",
+		historical.P95, historical.P99, historical.JobRuns, alertGraceSeconds, runs(successJobRunIDs), runs(failureJobRunIDs))
+
+	if len(jobRunIDToAlertSeconds) < 3 {
+		return failureJobRunIDs, successJobRunIDs, testCaseSkipped, fmt.Sprintf("We require at least three job runs with alert data %s", details), nil
+	}
+	if len(failureJobRunIDs) > len(jobRunIDToAlertSeconds)/2 {
+		return failureJobRunIDs, successJobRunIDs, testCaseFailed, fmt.Sprintf("Failed: fired longer than historically in %d of %d job runs.  %s", len(failureJobRunIDs), len(jobRunIDToAlertSeconds), details), nil
+	}
+	return failureJobRunIDs, successJobRunIDs, testCasePassed, fmt.Sprintf("Passed: fired longer than historically in %d of %d job runs.  %s", len(failureJobRunIDs), len(jobRunIDToAlertSeconds), details), nil
+}
+
 func (a *weeklyAverageFromTenDays) CheckFailed(ctx context.Context, jobName string, suiteNames []string, testCaseDetails *jobrunaggregatorlib.TestCaseDetails) (testCaseStatus, string, error) {
 	if reason := testShouldAlwaysPass(jobName, testCaseDetails.Name, testCaseDetails.TestSuiteName); len(reason) > 0 {
 		reason := fmt.Sprintf("always passing %q: %v\n", testCaseDetails.Name, reason)
@@ -569,14 +633,15 @@ func (a *weeklyAverageFromTenDays) CheckFailed(ctx context.Context, jobName stri
 		return testCasePassed, "probably intended to skip", nil
 	}
 
+	policy := a.policy.forTest(testCaseDetails.Name)
 	numberOfPasses := getNumberOfPasses(testCaseDetails)
 	numberOfFailures := getNumberOfFailures(testCaseDetails)
-	if numberOfAttempts < a.minimumNumberOfAttempts {
+	if numberOfAttempts < policy.minimumJobRuns {
 		summary := fmt.Sprintf("Passed %d times, failed %d times, skipped %d times: we require at least %d attempts to have a chance at success",
 			numberOfPasses,
 			numberOfFailures,
 			len(testCaseDetails.Skips),
-			a.minimumNumberOfAttempts,
+			policy.minimumJobRuns,
 		)
 		return testCaseFailed, summary, nil
 	}
@@ -589,6 +654,20 @@ func (a *weeklyAverageFromTenDays) CheckFailed(ctx context.Context, jobName stri
 		return testCaseFailed, summary, nil
 	}
 
+	if policy.passPercentage != nil {
+		requiredNumberOfPasses := policy.requiredPasses(numberOfAttempts)
+		summary := fmt.Sprintf("Passed %d times, failed %d times.  The pass rate required by the policy is %d%%.  The required number of passes is %d.",
+			numberOfPasses,
+			numberOfFailures,
+			*policy.passPercentage,
+			requiredNumberOfPasses,
+		)
+		if numberOfPasses < requiredNumberOfPasses {
+			return testCaseFailed, "Failed: " + summary, nil
+		}
+		return testCasePassed, "Passed: " + summary, nil
+	}
+
 	aggregatedTestRunsByName, err := a.getAggregatedTestRuns(ctx)
 	missingAllHistoricalData := false
 	if err != nil {
@@ -614,21 +693,39 @@ func (a *weeklyAverageFromTenDays) CheckFailed(ctx context.Context, jobName stri
 		workingPercentage = int(averageTestResult.WorkingPercentage)
 	}
 
-	requiredNumberOfPasses := requiredPassesByPassPercentageByNumberOfAttempts[numberOfAttempts][workingPercentage]
+	testCaseDetails.Baseline = &jobrunaggregatorlib.TestCaseBaseline{
+		PassPercentage: workingPercentage,
+		RequiredPasses: requiredPassesByPassPercentageByNumberOfAttempts[numberOfAttempts][workingPercentage],
+	}
+	historicalRuns := ""
+	// the tables assume a history of 250 job runs, compare to the actual history when it is known so that
+	// tests with little history are not held to a pass rate it cannot establish
+	historicalPasses := averageTestResult.PassCount + averageTestResult.FlakeCount
+	historicalFailures := averageTestResult.FailCount
+	if ok && historicalPasses+historicalFailures > 0 {
+		testCaseDetails.Baseline.Passes = historicalPasses
+		testCaseDetails.Baseline.Failures = historicalFailures
+		testCaseDetails.Baseline.RequiredPasses = requiredPassesComparedToHistory(historicalPasses, historicalFailures, numberOfAttempts)
+		historicalRuns = fmt.Sprintf(" over %d job runs", historicalPasses+historicalFailures)
+	}
+
+	requiredNumberOfPasses := testCaseDetails.Baseline.RequiredPasses
 	if numberOfPasses < requiredNumberOfPasses {
-		summary := fmt.Sprintf("Failed: Passed %d times, failed %d times.  The historical pass rate is %d%%.  The required number of passes is %d.",
+		summary := fmt.Sprintf("Failed: Passed %d times, failed %d times.  The historical pass rate is %d%%%s.  The required number of passes is %d.",
 			numberOfPasses,
 			numberOfFailures,
 			workingPercentage,
+			historicalRuns,
 			requiredNumberOfPasses,
 		)
 		return testCaseFailed, summary, nil
 	}
 
-	return testCasePassed, fmt.Sprintf("Passed: Passed %d times, failed %d times.  The historical pass rate is %d%%.  The required number of passes is %d.",
+	return testCasePassed, fmt.Sprintf("Passed: Passed %d times, failed %d times.  The historical pass rate is %d%%%s.  The required number of passes is %d.",
 		numberOfPasses,
 		numberOfFailures,
 		workingPercentage,
+		historicalRuns,
 		requiredNumberOfPasses,
 	), nil
 }
@@ -1,11 +1,13 @@
 package jobrunaggregatoranalyzer
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
 	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorlib"
 )
 
@@ -290,3 +292,154 @@ func TestCheckPercentileDisruption(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckFailedComparesToHistory(t *testing.T) {
+	history := map[TestKey]jobrunaggregatorapi.AggregatedTestRunRow{
+		{TestCaseName: "flaky"}:           {TestName: "flaky", PassCount: 500, FlakeCount: 100, FailCount: 400, WorkingPercentage: 60},
+		{TestCaseName: "reliable"}:        {TestName: "reliable", PassCount: 990, FailCount: 10, WorkingPercentage: 99},
+		{TestCaseName: "little history"}:  {TestName: "little history", PassCount: 19, FailCount: 1, WorkingPercentage: 95},
+		{TestCaseName: "percentage only"}: {TestName: "percentage only", WorkingPercentage: 95},
+	}
+	baseline := &weeklyAverageFromTenDays{policy: defaultAggregationPolicy()}
+	baseline.queryTestRunsOnce.Do(func() {
+		baseline.aggregatedTestRunsByName = history
+	})
+	details := func(name string, passes, failures int) *jobrunaggregatorlib.TestCaseDetails {
+		ret := &jobrunaggregatorlib.TestCaseDetails{Name: name}
+		for i := 0; i < passes; i++ {
+			ret.Passes = append(ret.Passes, jobrunaggregatorlib.TestCasePass{JobRunID: fmt.Sprintf("pass-%d", i)})
+		}
+		for i := 0; i < failures; i++ {
+			ret.Failures = append(ret.Failures, jobrunaggregatorlib.TestCaseFailure{JobRunID: fmt.Sprintf("failure-%d", i)})
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name             string
+		details          *jobrunaggregatorlib.TestCaseDetails
+		expectedStatus   testCaseStatus
+		expectedBaseline *jobrunaggregatorlib.TestCaseBaseline
+	}{
+		{
+			name:             "a flaky test passes as often as it did historically",
+			details:          details("flaky", 7, 3),
+			expectedStatus:   testCasePassed,
+			expectedBaseline: &jobrunaggregatorlib.TestCaseBaseline{PassPercentage: 60, Passes: 600, Failures: 400, RequiredPasses: 2},
+		},
+		{
+			name:             "a reliable test fails as often as a flaky one",
+			details:          details("reliable", 7, 3),
+			expectedStatus:   testCaseFailed,
+			expectedBaseline: &jobrunaggregatorlib.TestCaseBaseline{PassPercentage: 99, Passes: 990, Failures: 10, RequiredPasses: 8},
+		},
+		{
+			name:             "little history requires fewer passes than the tables",
+			details:          details("little history", 6, 4),
+			expectedStatus:   testCasePassed,
+			expectedBaseline: &jobrunaggregatorlib.TestCaseBaseline{PassPercentage: 95, Passes: 19, Failures: 1, RequiredPasses: 6},
+		},
+		{
+			name:             "the tables are used without historical counts",
+			details:          details("percentage only", 6, 4),
+			expectedStatus:   testCaseFailed,
+			expectedBaseline: &jobrunaggregatorlib.TestCaseBaseline{PassPercentage: 95, RequiredPasses: 7},
+		},
+		{
+			name:             "no history assumes a pass rate of 70%",
+			details:          details("unknown", 6, 4),
+			expectedStatus:   testCasePassed,
+			expectedBaseline: &jobrunaggregatorlib.TestCaseBaseline{PassPercentage: 70, RequiredPasses: 3},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			status, _, err := baseline.CheckFailed(context.Background(), "job", nil, tc.details)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, tc.expectedStatus, status)
+			assert.Equal(t, tc.expectedBaseline, tc.details.Baseline)
+		})
+	}
+}
+
+func TestCheckAlertDuration(t *testing.T) {
+	known := jobrunaggregatorlib.AlertKey{Name: "KubePodNotReady", Namespace: "openshift-etcd", Level: jobrunaggregatorlib.WarningAlertLevel}
+	unknown := jobrunaggregatorlib.AlertKey{Name: "NewAlert", Level: jobrunaggregatorlib.CriticalAlertLevel}
+	baseline := &weeklyAverageFromTenDays{}
+	baseline.queryAlertsOnce.Do(func() {
+		baseline.alertStatistics = map[jobrunaggregatorlib.AlertKey]jobrunaggregatorapi.AlertStatisticsRow{
+			known: {AlertName: known.Name, AlertNamespace: known.Namespace, AlertLevel: string(known.Level), JobRuns: 500, P95: 100, P99: 200},
+		}
+	})
+	seconds := func(durations ...int) map[string]int {
+		ret := map[string]int{}
+		for i, duration := range durations {
+			ret[fmt.Sprintf("run_%d", i)] = duration
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name           string
+		alert          jobrunaggregatorlib.AlertKey
+		seconds        map[string]int
+		expectedStatus testCaseStatus
+		expectedFailed int
+	}{
+		{
+			name:           "an alert firing as long as historically passes",
+			alert:          known,
+			seconds:        seconds(0, 50, 150, 220, 0),
+			expectedStatus: testCasePassed,
+			expectedFailed: 0,
+		},
+		{
+			name:           "an alert firing longer than historically in a minority of the job runs passes",
+			alert:          known,
+			seconds:        seconds(0, 500, 500, 0, 0),
+			expectedStatus: testCasePassed,
+			expectedFailed: 2,
+		},
+		{
+			name:           "an alert firing longer than historically in most job runs fails",
+			alert:          known,
+			seconds:        seconds(500, 500, 500, 0, 0),
+			expectedStatus: testCaseFailed,
+			expectedFailed: 3,
+		},
+		{
+			name:           "a new alert firing briefly passes",
+			alert:          unknown,
+			seconds:        seconds(10, 20, 0, 0),
+			expectedStatus: testCasePassed,
+			expectedFailed: 0,
+		},
+		{
+			name:           "a new always-firing alert fails",
+			alert:          unknown,
+			seconds:        seconds(3600, 3600, 3600, 3600),
+			expectedStatus: testCaseFailed,
+			expectedFailed: 4,
+		},
+		{
+			name:           "too few job runs are skipped",
+			alert:          unknown,
+			seconds:        seconds(3600, 3600),
+			expectedStatus: testCaseSkipped,
+			expectedFailed: 2,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			failed, successful, status, message, err := baseline.CheckAlertDuration(context.Background(), tc.seconds, tc.alert)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, tc.expectedStatus, status, message)
+			assert.Equal(t, tc.expectedFailed, len(failed), message)
+			assert.Equal(t, len(tc.seconds), len(failed)+len(successful), message)
+		})
+	}
+}
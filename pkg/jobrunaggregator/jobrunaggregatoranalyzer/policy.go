@@ -0,0 +1,121 @@
+package jobrunaggregatoranalyzer
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"sigs.k8s.io/yaml"
+)
+
+// defaultMinimumJobRuns is the number of job runs a test must be attempted in when the policy does not set one
+const defaultMinimumJobRuns = 6
+
+// AggregationPolicy decides how many job runs a test must run in and how many of them must pass for
+// the test to pass aggregation. It is read from the file given with --policy-file, like:
+//
+//	minimumJobRuns: 8
+//	tests:
+//	- namePattern: '\[Suite:openshift/conformance/parallel\]'
+//	  passPercentage: 95
+type AggregationPolicy struct {
+	// MinimumJobRuns is the number of job runs a test must be attempted in, defaults to 6
+	MinimumJobRuns int `json:"minimumJobRuns,omitempty"`
+	// PassPercentage, when set, is the percentage of the attempts a test must pass in. It replaces
+	// the required number of passes computed from the historical pass rate of the test.
+	PassPercentage *int `json:"passPercentage,omitempty"`
+	// Tests override the policy for the tests matching them. The first matching override applies.
+	Tests []TestPolicy `json:"tests,omitempty"`
+}
+
+// TestPolicy overrides the policy for the tests whose name matches the pattern. Unset fields are
+// inherited from the aggregation policy.
+type TestPolicy struct {
+	// NamePattern is a regular expression matched against the test name
+	NamePattern    string `json:"namePattern"`
+	MinimumJobRuns int    `json:"minimumJobRuns,omitempty"`
+	PassPercentage *int   `json:"passPercentage,omitempty"`
+
+	nameRegexp *regexp.Regexp
+}
+
+// testPolicy is the policy resolved for a single test
+type testPolicy struct {
+	minimumJobRuns int
+	passPercentage *int
+}
+
+// defaultAggregationPolicy is the policy used without a policy file
+func defaultAggregationPolicy() *AggregationPolicy {
+	return &AggregationPolicy{MinimumJobRuns: defaultMinimumJobRuns}
+}
+
+// LoadAggregationPolicy reads and validates the policy file
+func LoadAggregationPolicy(path string) (*AggregationPolicy, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	policy := &AggregationPolicy{}
+	if err := yaml.UnmarshalStrict(raw, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %s: %w", path, err)
+	}
+	if policy.MinimumJobRuns == 0 {
+		policy.MinimumJobRuns = defaultMinimumJobRuns
+	}
+	if err := policy.complete(); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+func (p *AggregationPolicy) complete() error {
+	maxJobRuns := len(requiredPassesByPassPercentageByNumberOfAttempts) - 1
+	validate := func(minimumJobRuns int, passPercentage *int) error {
+		if minimumJobRuns < 0 || minimumJobRuns > maxJobRuns {
+			return fmt.Errorf("minimumJobRuns must be between 0 and %d", maxJobRuns)
+		}
+		if passPercentage != nil && (*passPercentage < 0 || *passPercentage > 100) {
+			return fmt.Errorf("passPercentage must be between 0 and 100")
+		}
+		return nil
+	}
+	if err := validate(p.MinimumJobRuns, p.PassPercentage); err != nil {
+		return err
+	}
+	for i := range p.Tests {
+		test := &p.Tests[i]
+		if err := validate(test.MinimumJobRuns, test.PassPercentage); err != nil {
+			return fmt.Errorf("tests[%d]: %w", i, err)
+		}
+		nameRegexp, err := regexp.Compile(test.NamePattern)
+		if err != nil {
+			return fmt.Errorf("tests[%d]: invalid namePattern: %w", i, err)
+		}
+		test.nameRegexp = nameRegexp
+	}
+	return nil
+}
+
+// forTest resolves the policy of the test
+func (p *AggregationPolicy) forTest(testName string) testPolicy {
+	policy := testPolicy{minimumJobRuns: p.MinimumJobRuns, passPercentage: p.PassPercentage}
+	for _, test := range p.Tests {
+		if test.nameRegexp == nil || !test.nameRegexp.MatchString(testName) {
+			continue
+		}
+		if test.MinimumJobRuns > 0 {
+			policy.minimumJobRuns = test.MinimumJobRuns
+		}
+		if test.PassPercentage != nil {
+			policy.passPercentage = test.PassPercentage
+		}
+		break
+	}
+	return policy
+}
+
+// requiredPasses is the number of passes the pass percentage requires out of the attempts, rounded up
+func (p testPolicy) requiredPasses(attempts int) int {
+	return (*p.passPercentage*attempts + 99) / 100
+}
@@ -0,0 +1,140 @@
+package jobrunaggregatoranalyzer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorlib"
+)
+
+func TestLoadAggregationPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expected      map[string]testPolicy
+		expectedError bool
+	}{
+		{
+			name:    "defaults",
+			content: "{}",
+			expected: map[string]testPolicy{
+				"test": {minimumJobRuns: defaultMinimumJobRuns},
+			},
+		},
+		{
+			name: "per test overrides",
+			content: `minimumJobRuns: 8
+passPercentage: 80
+tests:
+- namePattern: '\[Feature:TechPreview\]'
+  passPercentage: 95
+- namePattern: 'TechPreview|slow'
+  minimumJobRuns: 10
+`,
+			expected: map[string]testPolicy{
+				"test":                                 {minimumJobRuns: 8, passPercentage: intPtr(80)},
+				"test [Feature:TechPreview]":           {minimumJobRuns: 8, passPercentage: intPtr(95)},
+				"TechPreview test which is not tagged": {minimumJobRuns: 10, passPercentage: intPtr(80)},
+			},
+		},
+		{
+			name:          "unknown field",
+			content:       "minimumRuns: 8",
+			expectedError: true,
+		},
+		{
+			name:          "invalid pattern",
+			content:       "tests:\n- namePattern: '['",
+			expectedError: true,
+		},
+		{
+			name:          "too many job runs",
+			content:       "minimumJobRuns: 20",
+			expectedError: true,
+		},
+		{
+			name:          "invalid pass percentage",
+			content:       "tests:\n- namePattern: 'a'\n  passPercentage: 101",
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.yaml")
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("failed to write policy: %v", err)
+			}
+			policy, err := LoadAggregationPolicy(path)
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for testName, expected := range tc.expected {
+				assert.Equal(t, expected, policy.forTest(testName), testName)
+			}
+		})
+	}
+}
+
+func TestCheckFailedWithPolicy(t *testing.T) {
+	policy := &AggregationPolicy{
+		MinimumJobRuns: 3,
+		Tests:          []TestPolicy{{NamePattern: "strict", MinimumJobRuns: 5, PassPercentage: intPtr(95)}},
+	}
+	if err := policy.complete(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	baseline := &weeklyAverageFromTenDays{policy: policy}
+	details := func(name string, passes, failures int) *jobrunaggregatorlib.TestCaseDetails {
+		ret := &jobrunaggregatorlib.TestCaseDetails{Name: name}
+		for i := 0; i < passes; i++ {
+			ret.Passes = append(ret.Passes, jobrunaggregatorlib.TestCasePass{JobRunID: string(rune('a' + i))})
+		}
+		for i := 0; i < failures; i++ {
+			ret.Failures = append(ret.Failures, jobrunaggregatorlib.TestCaseFailure{JobRunID: string(rune('A' + i))})
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name     string
+		details  *jobrunaggregatorlib.TestCaseDetails
+		expected testCaseStatus
+	}{
+		{
+			name:     "too few job runs for the test policy",
+			details:  details("strict test", 4, 0),
+			expected: testCaseFailed,
+		},
+		{
+			name:     "required pass rate is met",
+			details:  details("strict test", 5, 0),
+			expected: testCasePassed,
+		},
+		{
+			name:     "required pass rate is not met",
+			details:  details("strict test", 4, 1),
+			expected: testCaseFailed,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			status, _, err := baseline.CheckFailed(context.Background(), "job", nil, tc.details)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, tc.expected, status)
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
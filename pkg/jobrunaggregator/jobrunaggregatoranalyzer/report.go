@@ -0,0 +1,198 @@
+package jobrunaggregatoranalyzer
+
+import (
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorlib"
+	"github.com/openshift/ci-tools/pkg/junit"
+)
+
+const (
+	reportResultPassed  = "passed"
+	reportResultFailed  = "failed"
+	reportResultSkipped = "skipped"
+)
+
+// aggregationReport is the content of the HTML report written for an aggregation
+type aggregationReport struct {
+	JobName    string
+	PayloadTag string
+	// AggregationID is set instead of the payload tag when aggregating the job runs of a PR
+	AggregationID string
+	JobRuns       []JobRunInfo
+	// Tests are the aggregated tests, failed ones first
+	Tests []reportRow
+	// Disruptions are the aggregated disruption checks
+	Disruptions []reportRow
+	// Owners sum up the results of the tests and disruption checks by owner, when an ownership file is given
+	Owners []ownerSummary
+}
+
+// reportRow is the result of one aggregated test, overall and in each job run
+type reportRow struct {
+	Name    string
+	Suite   string
+	Status  string
+	Summary string
+	// Baseline is the historical pass rate the test was compared to, if any
+	Baseline *jobrunaggregatorlib.TestCaseBaseline
+	// Owner is the component or SIG owning the test, when an ownership file is given
+	Owner string
+	// Results are the results in the job runs of the report, in the same order. A job run in
+	// which the test did not run has an empty result.
+	Results []string
+}
+
+// newAggregationReport builds the report of the aggregated suites. The disruption suite is
+// reported separately from the tests.
+func newAggregationReport(jobName, payloadTag string, jobRuns []JobRunInfo, suites *junit.TestSuites, disruptionSuite *junit.TestSuite) aggregationReport {
+	report := aggregationReport{
+		JobName:    jobName,
+		PayloadTag: payloadTag,
+		JobRuns:    jobRuns,
+	}
+	for _, suite := range suites.Suites {
+		if suite == disruptionSuite {
+			continue
+		}
+		report.Tests = append(report.Tests, reportRowsForSuite(nil, suite, jobRuns)...)
+	}
+	sort.SliceStable(report.Tests, func(i, j int) bool {
+		if iFailed, jFailed := report.Tests[i].Status == reportResultFailed, report.Tests[j].Status == reportResultFailed; iFailed != jFailed {
+			return iFailed
+		}
+		return report.Tests[i].Name < report.Tests[j].Name
+	})
+	if disruptionSuite != nil {
+		report.Disruptions = reportRowsForSuite(nil, disruptionSuite, jobRuns)
+	}
+	return report
+}
+
+// assignOwners assigns the tests and disruption checks of the report to their owners and sums up
+// their results by owner
+func (r *aggregationReport) assignOwners(ownership *TestOwnership) {
+	for _, rows := range [][]reportRow{r.Tests, r.Disruptions} {
+		for i := range rows {
+			rows[i].Owner = ownership.ownerOf(rows[i].Name)
+		}
+	}
+	r.Owners = summarizeOwners(append(append([]reportRow{}, r.Tests...), r.Disruptions...))
+}
+
+func reportRowsForSuite(parents []string, suite *junit.TestSuite, jobRuns []JobRunInfo) []reportRow {
+	currSuite := parents
+	if len(suite.Name) > 0 {
+		currSuite = append(currSuite, suite.Name)
+	}
+	var rows []reportRow
+	for _, testCase := range suite.TestCases {
+		rows = append(rows, reportRowForTestCase(currSuite, testCase, jobRuns))
+	}
+	for _, child := range suite.Children {
+		rows = append(rows, reportRowsForSuite(currSuite, child, jobRuns)...)
+	}
+	return rows
+}
+
+func reportRowForTestCase(parents []string, testCase *junit.TestCase, jobRuns []JobRunInfo) reportRow {
+	row := reportRow{
+		Name:    testCase.Name,
+		Suite:   strings.Join(parents, " / "),
+		Status:  reportResultPassed,
+		Results: make([]string, len(jobRuns)),
+	}
+	switch {
+	case testCase.SkipMessage != nil:
+		row.Status = reportResultSkipped
+	case failedOnly(testCase):
+		row.Status = reportResultFailed
+	}
+
+	details := &jobrunaggregatorlib.TestCaseDetails{}
+	// test cases without details, like the ones for collected data, only have an overall status
+	_ = yaml.Unmarshal([]byte(testCase.SystemOut), details)
+	row.Summary = details.Summary
+	row.Baseline = details.Baseline
+	results := map[string]string{}
+	for _, pass := range details.Passes {
+		results[pass.JobRunID] = reportResultPassed
+	}
+	for _, skip := range details.Skips {
+		results[skip.JobRunID] = reportResultSkipped
+	}
+	for _, failure := range details.Failures {
+		results[failure.JobRunID] = reportResultFailed
+	}
+	for i, jobRun := range jobRuns {
+		row.Results[i] = results[jobRun.JobRunID]
+	}
+	return row
+}
+
+var aggregationReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"add": func(a, b int) int {
+		return a + b
+	},
+	"matrix": func(jobRuns []JobRunInfo, rows []reportRow) map[string]interface{} {
+		return map[string]interface{}{"JobRuns": jobRuns, "Rows": rows}
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Aggregation of {{ .JobName }} for {{ .PayloadTag }}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 2px 6px; text-align: left; }
+td.passed { background-color: #d4edda; }
+td.failed { background-color: #f8d7da; }
+td.skipped { background-color: #fff3cd; }
+</style>
+</head>
+<body>
+<h1>Aggregation of {{ .JobName }} for {{ .PayloadTag }}</h1>
+<h2>Job Runs</h2>
+<table>
+<tr><th>Job Run</th><th>Status</th></tr>
+{{- range .JobRuns }}
+<tr><td><a target="_blank" href="{{ .HumanURL }}">{{ .JobRunID }}</a></td><td>{{ .Status }}</td></tr>
+{{- end }}
+</table>
+{{- if .Owners }}
+<h2>Owners</h2>
+<table>
+<tr><th>Owner</th><th>Failed</th><th>Passed</th><th>Skipped</th><th>Failed Tests</th></tr>
+{{- range .Owners }}
+<tr><td>{{ .Owner }}</td><td{{ if .Failed }} class="failed"{{ end }}>{{ .Failed }}</td><td>{{ .Passed }}</td><td>{{ .Skipped }}</td><td>{{ range $i, $test := .FailedTests }}{{ if $i }}<br/>{{ end }}{{ $test }}{{ end }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- if .Disruptions }}
+<h2>Disruption</h2>
+{{ template "matrix" (matrix .JobRuns .Disruptions) }}
+{{- end }}
+<h2>Tests</h2>
+{{ template "matrix" (matrix .JobRuns .Tests) }}
+</body>
+</html>
+{{ define "matrix" -}}
+<table>
+<tr><th>Test</th><th>Result</th><th>Baseline</th>{{ range .JobRuns }}<th><a target="_blank" href="{{ .HumanURL }}">{{ .JobRunID }}</a></th>{{ end }}</tr>
+{{- range .Rows }}
+<tr><td>{{ if .Suite }}{{ .Suite }} / {{ end }}{{ .Name }}{{ if .Owner }} <small>({{ .Owner }})</small>{{ end }}{{ if .Summary }}<br/><small>{{ .Summary }}</small>{{ end }}</td><td class="{{ .Status }}">{{ .Status }}</td><td>{{ with .Baseline }}{{ .PassPercentage }}%{{ if or .Passes .Failures }} of {{ add .Passes .Failures }} runs{{ end }}, {{ .RequiredPasses }} passes required{{ end }}</td>{{ range .Results }}<td class="{{ . }}">{{ . }}</td>{{ end }}</tr>
+{{- end }}
+</table>
+{{- end }}
+`))
+
+// writeAggregationReport writes the report as a self-contained HTML page
+func writeAggregationReport(report aggregationReport, out io.Writer) error {
+	return aggregationReportTemplate.Execute(out, report)
+}
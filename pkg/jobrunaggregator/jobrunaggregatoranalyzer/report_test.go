@@ -0,0 +1,71 @@
+package jobrunaggregatoranalyzer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorlib"
+	"github.com/openshift/ci-tools/pkg/junit"
+)
+
+func testCaseWithDetails(t *testing.T, name string, details jobrunaggregatorlib.TestCaseDetails) *junit.TestCase {
+	out, err := yaml.Marshal(details)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return &junit.TestCase{Name: name, SystemOut: string(out)}
+}
+
+func TestAggregationReport(t *testing.T) {
+	jobRuns := []JobRunInfo{
+		{JobName: "job", JobRunID: "1", HumanURL: "https://prow.ci.openshift.org/view/gs/bucket/logs/job/1", Status: "success"},
+		{JobName: "job", JobRunID: "2", HumanURL: "https://prow.ci.openshift.org/view/gs/bucket/logs/job/2", Status: "failure"},
+	}
+	passing := testCaseWithDetails(t, "passing test", jobrunaggregatorlib.TestCaseDetails{
+		Summary:  "Passed 2 times",
+		Baseline: &jobrunaggregatorlib.TestCaseBaseline{PassPercentage: 60, Passes: 600, Failures: 400, RequiredPasses: 0},
+		Passes:   []jobrunaggregatorlib.TestCasePass{{JobRunID: "1"}, {JobRunID: "2"}},
+	})
+	failing := testCaseWithDetails(t, "failing test", jobrunaggregatorlib.TestCaseDetails{
+		Passes:   []jobrunaggregatorlib.TestCasePass{{JobRunID: "1"}},
+		Failures: []jobrunaggregatorlib.TestCaseFailure{{JobRunID: "2"}},
+	})
+	failing.FailureOutput = &junit.FailureOutput{Message: "Passed 1 times, failed 1 times"}
+	skipped := &junit.TestCase{Name: "skipped test", SkipMessage: &junit.SkipMessage{Message: "skipped"}}
+	disruption := testCaseWithDetails(t, "disruption <backend>", jobrunaggregatorlib.TestCaseDetails{
+		Passes: []jobrunaggregatorlib.TestCasePass{{JobRunID: "2"}},
+	})
+	disruptionSuite := &junit.TestSuite{Name: "BackendDisruption", TestCases: []*junit.TestCase{disruption}}
+	suites := &junit.TestSuites{Suites: []*junit.TestSuite{
+		{
+			Name:      "aggregated",
+			TestCases: []*junit.TestCase{passing, skipped},
+			Children:  []*junit.TestSuite{{Name: "e2e", TestCases: []*junit.TestCase{failing}}},
+		},
+		disruptionSuite,
+	}}
+
+	report := newAggregationReport("job", "4.10.0-0.ci-2021-10-01-000000", jobRuns, suites, disruptionSuite)
+	assert.Equal(t, []reportRow{
+		{Name: "failing test", Suite: "aggregated / e2e", Status: reportResultFailed, Results: []string{reportResultPassed, reportResultFailed}},
+		{Name: "passing test", Suite: "aggregated", Status: reportResultPassed, Summary: "Passed 2 times", Baseline: &jobrunaggregatorlib.TestCaseBaseline{PassPercentage: 60, Passes: 600, Failures: 400}, Results: []string{reportResultPassed, reportResultPassed}},
+		{Name: "skipped test", Suite: "aggregated", Status: reportResultSkipped, Results: []string{"", ""}},
+	}, report.Tests)
+	assert.Equal(t, []reportRow{
+		{Name: "disruption <backend>", Suite: "BackendDisruption", Status: reportResultPassed, Results: []string{"", reportResultPassed}},
+	}, report.Disruptions)
+
+	out := &bytes.Buffer{}
+	if err := writeAggregationReport(report, out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	html := out.String()
+	assert.Contains(t, html, `<a target="_blank" href="https://prow.ci.openshift.org/view/gs/bucket/logs/job/2">2</a>`)
+	assert.Contains(t, html, `<td class="failed">failed</td>`)
+	assert.Contains(t, html, "<td>60% of 1000 runs, 0 passes required</td>")
+	assert.Contains(t, html, "<h2>Disruption</h2>")
+	assert.Contains(t, html, "disruption &lt;backend&gt;")
+}
@@ -0,0 +1,131 @@
+package jobrunaggregatoranalyzer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// sippyAggregation is the result of an aggregation as Sippy ingests it, so that Sippy uses the
+// verdicts of the aggregator instead of computing its own
+type sippyAggregation struct {
+	JobName    string `json:"jobName"`
+	PayloadTag string `json:"payloadTag,omitempty"`
+	// AggregationID is set instead of the payload tag when aggregating the job runs of a PR
+	AggregationID string `json:"aggregationID,omitempty"`
+	// Verdict is failed when any test or disruption check failed, and passed otherwise
+	Verdict string          `json:"verdict"`
+	JobRuns []sippyJobRun   `json:"jobRuns"`
+	Tests   []sippyTestCase `json:"tests"`
+	// Owners sum up the results of the tests by owner, when an ownership file is given
+	Owners []sippyOwner `json:"owners,omitempty"`
+}
+
+type sippyOwner struct {
+	Owner       string   `json:"owner"`
+	Passes      int      `json:"passes"`
+	Failures    int      `json:"failures"`
+	Skips       int      `json:"skips"`
+	FailedTests []string `json:"failedTests,omitempty"`
+}
+
+type sippyJobRun struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
+	Status string `json:"status"`
+}
+
+type sippyTestCase struct {
+	Name     string `json:"name"`
+	Suite    string `json:"suite"`
+	Status   string `json:"status"`
+	Passes   int    `json:"passes"`
+	Failures int    `json:"failures"`
+	Skips    int    `json:"skips"`
+	Owner    string `json:"owner,omitempty"`
+	// RequiredPasses is the number of passes the test needed to pass aggregation, when it was
+	// compared to its history
+	RequiredPasses *int `json:"requiredPasses,omitempty"`
+}
+
+func newSippyAggregation(report aggregationReport) sippyAggregation {
+	aggregation := sippyAggregation{
+		JobName:       report.JobName,
+		PayloadTag:    report.PayloadTag,
+		AggregationID: report.AggregationID,
+		Verdict:       reportResultPassed,
+		JobRuns:       []sippyJobRun{},
+		Tests:         []sippyTestCase{},
+	}
+	for _, jobRun := range report.JobRuns {
+		aggregation.JobRuns = append(aggregation.JobRuns, sippyJobRun{ID: jobRun.JobRunID, URL: jobRun.HumanURL, Status: jobRun.Status})
+	}
+	for _, row := range append(append([]reportRow{}, report.Tests...), report.Disruptions...) {
+		testCase := sippyTestCase{
+			Name:     row.Name,
+			Suite:    row.Suite,
+			Status:   row.Status,
+			Passes:   countResults(row, reportResultPassed),
+			Failures: countResults(row, reportResultFailed),
+			Skips:    countResults(row, reportResultSkipped),
+			Owner:    row.Owner,
+		}
+		if row.Baseline != nil {
+			requiredPasses := row.Baseline.RequiredPasses
+			testCase.RequiredPasses = &requiredPasses
+		}
+		if row.Status == reportResultFailed {
+			aggregation.Verdict = reportResultFailed
+		}
+		aggregation.Tests = append(aggregation.Tests, testCase)
+	}
+	for _, owner := range report.Owners {
+		aggregation.Owners = append(aggregation.Owners, sippyOwner{
+			Owner:       owner.Owner,
+			Passes:      owner.Passed,
+			Failures:    owner.Failed,
+			Skips:       owner.Skipped,
+			FailedTests: owner.FailedTests,
+		})
+	}
+	return aggregation
+}
+
+// sippyExporter posts the results of the aggregations to a Sippy endpoint
+type sippyExporter struct {
+	url        string
+	httpClient *http.Client
+}
+
+func newSippyExporter(url string) *sippyExporter {
+	return &sippyExporter{
+		url:        url,
+		httpClient: http.DefaultClient,
+	}
+}
+
+func (e *sippyExporter) Export(ctx context.Context, aggregationJSON []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(aggregationJSON))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := e.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("sippy returned %s: %s", response.Status, body)
+	}
+	return nil
+}
+
+// marshalSippyAggregation serializes the report as Sippy ingests it
+func marshalSippyAggregation(report aggregationReport) ([]byte, error) {
+	return json.MarshalIndent(newSippyAggregation(report), "", "  ")
+}
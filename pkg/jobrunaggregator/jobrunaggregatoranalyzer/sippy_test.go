@@ -0,0 +1,89 @@
+package jobrunaggregatoranalyzer
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorlib"
+)
+
+func TestNewSippyAggregation(t *testing.T) {
+	report := aggregationReport{
+		JobName:    "job",
+		PayloadTag: "4.14.0-0.ci-2023-05-01-000000",
+		JobRuns:    []JobRunInfo{{JobRunID: "1", HumanURL: "https://prow/1", Status: "success"}, {JobRunID: "2", HumanURL: "https://prow/2", Status: "failure"}},
+		Tests: []reportRow{
+			{Name: "a", Suite: "suite", Status: reportResultFailed, Results: []string{"passed", "failed"}, Baseline: &jobrunaggregatorlib.TestCaseBaseline{RequiredPasses: 2}},
+			{Name: "b", Suite: "suite", Status: reportResultPassed, Results: []string{"passed", "skipped"}},
+		},
+		Disruptions: []reportRow{
+			{Name: "kube-api-new-connections disruption P70 should not be worse", Suite: "BackendDisruption", Status: reportResultPassed, Results: []string{"passed", ""}},
+		},
+	}
+	requiredPasses := 2
+	expected := sippyAggregation{
+		JobName:    "job",
+		PayloadTag: "4.14.0-0.ci-2023-05-01-000000",
+		Verdict:    reportResultFailed,
+		JobRuns:    []sippyJobRun{{ID: "1", URL: "https://prow/1", Status: "success"}, {ID: "2", URL: "https://prow/2", Status: "failure"}},
+		Tests: []sippyTestCase{
+			{Name: "a", Suite: "suite", Status: reportResultFailed, Passes: 1, Failures: 1, RequiredPasses: &requiredPasses},
+			{Name: "b", Suite: "suite", Status: reportResultPassed, Passes: 1, Skips: 1},
+			{Name: "kube-api-new-connections disruption P70 should not be worse", Suite: "BackendDisruption", Status: reportResultPassed, Passes: 1},
+		},
+	}
+	assert.Equal(t, expected, newSippyAggregation(report))
+
+	report.Tests = report.Tests[1:]
+	assert.Equal(t, reportResultPassed, newSippyAggregation(report).Verdict)
+}
+
+func TestSippyExporter(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		expectedError bool
+	}{
+		{
+			name:   "accepted",
+			status: http.StatusOK,
+		},
+		{
+			name:          "rejected",
+			status:        http.StatusBadRequest,
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var received sippyAggregation
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.NoError(t, json.Unmarshal(body, &received))
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			aggregationJSON, err := marshalSippyAggregation(aggregationReport{JobName: "job"})
+			if err != nil {
+				t.Fatalf("failed to marshal the aggregation: %v", err)
+			}
+			err = newSippyExporter(server.URL).Export(context.Background(), aggregationJSON)
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, "job", received.JobName)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package jobrunaggregatoranalyzer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// maxFailingTestsInNotification bounds the failing tests listed in a notification, the report has the rest
+const maxFailingTestsInNotification = 5
+
+// aggregationNotifier is told about the verdict of an aggregation
+type aggregationNotifier interface {
+	// NotifyVerdict is called once the job runs are aggregated
+	NotifyVerdict(ctx context.Context, report aggregationReport) error
+	// NotifyNotEnoughJobRuns is called when the aggregation is abandoned because too few job runs finished
+	NotifyNotEnoughJobRuns(ctx context.Context, jobName, target string, unfinishedJobRunIDs []string) error
+}
+
+// slackNotifier posts the verdicts to a Slack incoming webhook
+type slackNotifier struct {
+	webhookURL string
+	// reportURL is where the aggregation report is published, it is linked when set
+	reportURL  string
+	httpClient *http.Client
+}
+
+func newSlackNotifier(webhookURL, reportURL string) *slackNotifier {
+	return &slackNotifier{
+		webhookURL: webhookURL,
+		reportURL:  reportURL,
+		httpClient: http.DefaultClient,
+	}
+}
+
+func (n *slackNotifier) NotifyVerdict(ctx context.Context, report aggregationReport) error {
+	return slack.PostWebhookCustomHTTPContext(ctx, n.webhookURL, n.httpClient, verdictMessage(report, n.reportURL))
+}
+
+func (n *slackNotifier) NotifyNotEnoughJobRuns(ctx context.Context, jobName, target string, unfinishedJobRunIDs []string) error {
+	text := fmt.Sprintf(":warning: *%s* for `%s` was not aggregated: %d job runs did not finish (%s).",
+		jobName, target, len(unfinishedJobRunIDs), strings.Join(unfinishedJobRunIDs, ", "))
+	return slack.PostWebhookCustomHTTPContext(ctx, n.webhookURL, n.httpClient, &slack.WebhookMessage{Text: text})
+}
+
+// verdictMessage summarizes the report: the verdict, how many job runs passed and the tests which
+// failed in the most job runs
+func verdictMessage(report aggregationReport, reportURL string) *slack.WebhookMessage {
+	var failedTests []reportRow
+	for _, test := range append(append([]reportRow{}, report.Tests...), report.Disruptions...) {
+		if test.Status == reportResultFailed {
+			failedTests = append(failedTests, test)
+		}
+	}
+	sort.SliceStable(failedTests, func(i, j int) bool {
+		return countResults(failedTests[i], reportResultFailed) > countResults(failedTests[j], reportResultFailed)
+	})
+
+	passedJobRuns := 0
+	for _, jobRun := range report.JobRuns {
+		if jobRun.Status == "success" {
+			passedJobRuns++
+		}
+	}
+
+	target := report.PayloadTag
+	if len(report.AggregationID) > 0 {
+		target = report.AggregationID
+	}
+	verdict := ":white_check_mark: passed"
+	if len(failedTests) > 0 {
+		verdict = ":x: failed"
+	}
+	lines := []string{
+		fmt.Sprintf("*%s* for `%s` %s aggregation: %d/%d job runs passed, %d tests failed.",
+			report.JobName, target, verdict, passedJobRuns, len(report.JobRuns), len(failedTests)),
+	}
+	for i, test := range failedTests {
+		if i == maxFailingTestsInNotification {
+			lines = append(lines, fmt.Sprintf("• and %d more", len(failedTests)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("• %s (failed in %d/%d job runs)", test.Name, countResults(test, reportResultFailed), len(report.JobRuns)))
+	}
+	var failingOwners []string
+	for _, owner := range report.Owners {
+		if owner.Failed > 0 {
+			failingOwners = append(failingOwners, fmt.Sprintf("%s (%d)", owner.Owner, owner.Failed))
+		}
+	}
+	if len(failingOwners) > 0 {
+		lines = append(lines, fmt.Sprintf("Failed tests by owner: %s", strings.Join(failingOwners, ", ")))
+	}
+	if len(reportURL) > 0 {
+		lines = append(lines, fmt.Sprintf("<%s|Aggregation report>", reportURL))
+	}
+	return &slack.WebhookMessage{Text: strings.Join(lines, "\n")}
+}
+
+func countResults(row reportRow, result string) int {
+	count := 0
+	for _, curr := range row.Results {
+		if curr == result {
+			count++
+		}
+	}
+	return count
+}
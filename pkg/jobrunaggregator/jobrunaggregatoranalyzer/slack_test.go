@@ -0,0 +1,81 @@
+package jobrunaggregatoranalyzer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerdictMessage(t *testing.T) {
+	jobRuns := []JobRunInfo{{JobRunID: "1", Status: "success"}, {JobRunID: "2", Status: "failure"}, {JobRunID: "3", Status: "failure"}}
+	tests := []struct {
+		name      string
+		report    aggregationReport
+		reportURL string
+		expected  string
+	}{
+		{
+			name: "passed",
+			report: aggregationReport{
+				JobName:    "job",
+				PayloadTag: "4.14.0-0.ci-2023-05-01-000000",
+				JobRuns:    jobRuns,
+				Tests:      []reportRow{{Name: "test", Status: reportResultPassed, Results: []string{"passed", "passed", "failed"}}},
+			},
+			expected: "*job* for `4.14.0-0.ci-2023-05-01-000000` :white_check_mark: passed aggregation: 1/3 job runs passed, 0 tests failed.",
+		},
+		{
+			name: "failed tests are listed by the number of failed job runs",
+			report: aggregationReport{
+				JobName:       "job",
+				AggregationID: "aggregation-id",
+				JobRuns:       jobRuns,
+				Tests: []reportRow{
+					{Name: "a", Status: reportResultFailed, Results: []string{"passed", "failed", "passed"}},
+					{Name: "b", Status: reportResultFailed, Results: []string{"failed", "failed", "passed"}},
+				},
+				Disruptions: []reportRow{{Name: "disruption", Status: reportResultFailed, Results: []string{"failed", "failed", "failed"}}},
+			},
+			reportURL: "https://example.com/report.html",
+			expected: "*job* for `aggregation-id` :x: failed aggregation: 1/3 job runs passed, 3 tests failed.\n" +
+				"• disruption (failed in 3/3 job runs)\n" +
+				"• b (failed in 2/3 job runs)\n" +
+				"• a (failed in 1/3 job runs)\n" +
+				"<https://example.com/report.html|Aggregation report>",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, verdictMessage(tc.report, tc.reportURL).Text)
+		})
+	}
+}
+
+func TestSlackNotifier(t *testing.T) {
+	var received []slack.WebhookMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		msg := slack.WebhookMessage{}
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("failed to decode message: %v", err)
+		}
+		received = append(received, msg)
+	}))
+	defer server.Close()
+
+	notifier := newSlackNotifier(server.URL, "")
+	if err := notifier.NotifyNotEnoughJobRuns(context.Background(), "job", "tag", []string{"1", "2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := notifier.NotifyVerdict(context.Background(), aggregationReport{JobName: "job", PayloadTag: "tag"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, []slack.WebhookMessage{
+		{Text: ":warning: *job* for `tag` was not aggregated: 2 job runs did not finish (1, 2)."},
+		{Text: "*job* for `tag` :white_check_mark: passed aggregation: 0/0 job runs passed, 0 tests failed."},
+	}, received)
+}
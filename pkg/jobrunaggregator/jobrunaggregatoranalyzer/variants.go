@@ -0,0 +1,171 @@
+package jobrunaggregatoranalyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
+)
+
+// variantDimensions extract the variants of a job from its row in the Jobs table, which are parsed
+// from the name of the job when the table is primed
+var variantDimensions = map[string]func(job jobrunaggregatorapi.JobRow) string{
+	"architecture": func(job jobrunaggregatorapi.JobRow) string { return job.Architecture },
+	"network":      func(job jobrunaggregatorapi.JobRow) string { return job.Network },
+	"platform":     func(job jobrunaggregatorapi.JobRow) string { return job.Platform },
+	"topology":     func(job jobrunaggregatorapi.JobRow) string { return job.Topology },
+	"upgrade": func(job jobrunaggregatorapi.JobRow) string {
+		if job.RunsUpgrade {
+			return "upgrade"
+		}
+		return "install"
+	},
+}
+
+// defaultVariantDimensions are the dimensions the jobs are grouped by without --group-by
+var defaultVariantDimensions = []string{"platform", "network", "upgrade"}
+
+// variantOf names the variant of the job in the dimensions, like gcp-ovn-upgrade
+func variantOf(job jobrunaggregatorapi.JobRow, dimensions []string) string {
+	var values []string
+	for _, dimension := range dimensions {
+		value := variantDimensions[dimension](job)
+		if len(value) == 0 {
+			value = "unknown"
+		}
+		values = append(values, value)
+	}
+	return strings.Join(values, "-")
+}
+
+// jobLister lists the jobs of the Jobs table
+type jobLister interface {
+	ListAllJobs(ctx context.Context) ([]jobrunaggregatorapi.JobRow, error)
+}
+
+// VariantVerdict is the verdict of the aggregations of the jobs of a variant
+type VariantVerdict struct {
+	Variant string `json:"variant"`
+	// Verdict is failed when the aggregation of any job failed, error when the aggregation of any
+	// job did not complete, and passed otherwise
+	Verdict string       `json:"verdict"`
+	Jobs    []JobVerdict `json:"jobs"`
+}
+
+// JobVerdict is the verdict of the aggregation of a job
+type JobVerdict struct {
+	JobName string `json:"jobName"`
+	Verdict string `json:"verdict"`
+	Error   string `json:"error,omitempty"`
+}
+
+// jobVariantAggregatorOptions aggregates several jobs in one invocation and groups their verdicts
+// by the variants of the jobs
+type jobVariantAggregatorOptions struct {
+	jobNames   []string
+	dimensions []string
+	workingDir string
+
+	jobLister jobLister
+	// aggregate aggregates the job runs of the job
+	aggregate func(ctx context.Context, jobName string) error
+}
+
+func (o *jobVariantAggregatorOptions) Run(ctx context.Context) error {
+	jobs, err := o.jobLister.ListAllJobs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the jobs: %w", err)
+	}
+	jobsByName := map[string]jobrunaggregatorapi.JobRow{}
+	for _, job := range jobs {
+		jobsByName[job.JobName] = job
+	}
+	jobNamesByVariant := map[string][]string{}
+	for _, jobName := range o.jobNames {
+		job, ok := jobsByName[jobName]
+		if !ok {
+			return fmt.Errorf("job %s is not in the %s table, its variants are unknown", jobName, jobrunaggregatorapi.JobsTableName)
+		}
+		variant := variantOf(job, o.dimensions)
+		jobNamesByVariant[variant] = append(jobNamesByVariant[variant], jobName)
+	}
+
+	// the aggregations wait for the job runs to finish, so they run at the same time
+	jobVerdicts := make([]JobVerdict, len(o.jobNames))
+	var wg sync.WaitGroup
+	for i, jobName := range o.jobNames {
+		wg.Add(1)
+		go func(i int, jobName string) {
+			defer wg.Done()
+			jobVerdicts[i] = JobVerdict{JobName: jobName, Verdict: jobrunaggregatorapi.AggregationRunStatusPassed}
+			if err := o.aggregate(ctx, jobName); err != nil {
+				if errors.Is(err, errTestsFailedAggregation) {
+					jobVerdicts[i].Verdict = jobrunaggregatorapi.AggregationRunStatusFailed
+				} else {
+					jobVerdicts[i].Verdict = jobrunaggregatorapi.AggregationRunStatusError
+					jobVerdicts[i].Error = err.Error()
+				}
+			}
+		}(i, jobName)
+	}
+	wg.Wait()
+	jobVerdictsByName := map[string]JobVerdict{}
+	for _, jobVerdict := range jobVerdicts {
+		jobVerdictsByName[jobVerdict.JobName] = jobVerdict
+	}
+
+	var variantVerdicts []VariantVerdict
+	var errs []error
+	failed := false
+	for _, variant := range sets.List(sets.KeySet(jobNamesByVariant)) {
+		variantVerdict := VariantVerdict{Variant: variant, Verdict: jobrunaggregatorapi.AggregationRunStatusPassed}
+		jobNames := jobNamesByVariant[variant]
+		sort.Strings(jobNames)
+		for _, jobName := range jobNames {
+			jobVerdict := jobVerdictsByName[jobName]
+			variantVerdict.Jobs = append(variantVerdict.Jobs, jobVerdict)
+			switch jobVerdict.Verdict {
+			case jobrunaggregatorapi.AggregationRunStatusFailed:
+				variantVerdict.Verdict = jobrunaggregatorapi.AggregationRunStatusFailed
+			case jobrunaggregatorapi.AggregationRunStatusError:
+				if variantVerdict.Verdict != jobrunaggregatorapi.AggregationRunStatusFailed {
+					variantVerdict.Verdict = jobrunaggregatorapi.AggregationRunStatusError
+				}
+				errs = append(errs, fmt.Errorf("failed to aggregate %s: %s", jobName, jobVerdict.Error))
+			}
+		}
+		if variantVerdict.Verdict == jobrunaggregatorapi.AggregationRunStatusFailed {
+			failed = true
+		}
+		logrus.WithFields(logrus.Fields{"variant": variant, "verdict": variantVerdict.Verdict}).Info("Aggregated the jobs of the variant.")
+		variantVerdicts = append(variantVerdicts, variantVerdict)
+	}
+
+	if err := os.MkdirAll(o.workingDir, 0755); err != nil {
+		return fmt.Errorf("error creating destination directory %q: %w", o.workingDir, err)
+	}
+	raw, err := yaml.Marshal(variantVerdicts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the variant verdicts: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(o.workingDir, "variant-verdicts.yaml"), raw, 0644); err != nil {
+		return fmt.Errorf("failed to write the variant verdicts: %w", err)
+	}
+
+	if failed {
+		errs = append(errs, errTestsFailedAggregation)
+	}
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,121 @@
+package jobrunaggregatoranalyzer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
+)
+
+type fakeJobLister struct {
+	jobs []jobrunaggregatorapi.JobRow
+}
+
+func (l *fakeJobLister) ListAllJobs(context.Context) ([]jobrunaggregatorapi.JobRow, error) {
+	return l.jobs, nil
+}
+
+func TestVariantOf(t *testing.T) {
+	job := jobrunaggregatorapi.JobRow{Platform: "gcp", Network: "ovn", RunsUpgrade: true}
+	assert.Equal(t, "gcp-ovn-upgrade", variantOf(job, defaultVariantDimensions))
+	assert.Equal(t, "install", variantOf(jobrunaggregatorapi.JobRow{}, []string{"upgrade"}))
+	assert.Equal(t, "unknown-install", variantOf(jobrunaggregatorapi.JobRow{}, []string{"platform", "upgrade"}))
+}
+
+func TestJobVariantAggregator(t *testing.T) {
+	jobs := []jobrunaggregatorapi.JobRow{
+		{JobName: "gcp-ovn-upgrade-a", Platform: "gcp", Network: "ovn", RunsUpgrade: true},
+		{JobName: "gcp-ovn-upgrade-b", Platform: "gcp", Network: "ovn", RunsUpgrade: true},
+		{JobName: "aws-sdn", Platform: "aws", Network: "sdn"},
+	}
+	allJobNames := []string{"gcp-ovn-upgrade-a", "gcp-ovn-upgrade-b", "aws-sdn"}
+
+	tests := []struct {
+		name              string
+		jobNames          []string
+		aggregationErrors map[string]error
+		expected          []VariantVerdict
+		expectedError     bool
+	}{
+		{
+			name:     "jobs are grouped by variant",
+			jobNames: allJobNames,
+			aggregationErrors: map[string]error{
+				"gcp-ovn-upgrade-b": errTestsFailedAggregation,
+			},
+			expected: []VariantVerdict{
+				{Variant: "aws-sdn-install", Verdict: "passed", Jobs: []JobVerdict{{JobName: "aws-sdn", Verdict: "passed"}}},
+				{Variant: "gcp-ovn-upgrade", Verdict: "failed", Jobs: []JobVerdict{
+					{JobName: "gcp-ovn-upgrade-a", Verdict: "passed"},
+					{JobName: "gcp-ovn-upgrade-b", Verdict: "failed"},
+				}},
+			},
+			expectedError: true,
+		},
+		{
+			name:     "aggregations which did not complete error the variant",
+			jobNames: allJobNames,
+			aggregationErrors: map[string]error{
+				"aws-sdn": errors.New("not enough job runs"),
+			},
+			expected: []VariantVerdict{
+				{Variant: "aws-sdn-install", Verdict: "error", Jobs: []JobVerdict{{JobName: "aws-sdn", Verdict: "error", Error: "not enough job runs"}}},
+				{Variant: "gcp-ovn-upgrade", Verdict: "passed", Jobs: []JobVerdict{
+					{JobName: "gcp-ovn-upgrade-a", Verdict: "passed"},
+					{JobName: "gcp-ovn-upgrade-b", Verdict: "passed"},
+				}},
+			},
+			expectedError: true,
+		},
+		{
+			name:     "all variants pass",
+			jobNames: []string{"aws-sdn"},
+			expected: []VariantVerdict{
+				{Variant: "aws-sdn-install", Verdict: "passed", Jobs: []JobVerdict{{JobName: "aws-sdn", Verdict: "passed"}}},
+			},
+		},
+		{
+			name:          "jobs missing from the Jobs table have no variant",
+			jobNames:      []string{"aws-sdn", "unknown"},
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			workingDir := t.TempDir()
+			o := &jobVariantAggregatorOptions{
+				jobNames:   tc.jobNames,
+				dimensions: defaultVariantDimensions,
+				workingDir: workingDir,
+				jobLister:  &fakeJobLister{jobs: jobs},
+				aggregate: func(_ context.Context, jobName string) error {
+					return tc.aggregationErrors[jobName]
+				},
+			}
+			err := o.Run(context.Background())
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tc.expected == nil {
+				return
+			}
+			raw, err := os.ReadFile(filepath.Join(workingDir, "variant-verdicts.yaml"))
+			if err != nil {
+				t.Fatalf("failed to read the variant verdicts: %v", err)
+			}
+			var actual []VariantVerdict
+			if err := yaml.Unmarshal(raw, &actual); err != nil {
+				t.Fatalf("failed to parse the variant verdicts: %v", err)
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
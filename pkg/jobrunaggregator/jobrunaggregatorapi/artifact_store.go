@@ -0,0 +1,28 @@
+package jobrunaggregatorapi
+
+import (
+	"context"
+)
+
+// ArtifactStore gives access to the artifacts prow uploads for job runs to a bucket, regardless of
+// where the bucket is hosted.
+type ArtifactStore interface {
+	// List returns the names of the objects matching the query, sorted. When the query has a
+	// delimiter, the names of the "directories" end with it and their content is not listed.
+	List(ctx context.Context, query ArtifactQuery) ([]string, error)
+	// Read returns the content of the object at the path.
+	Read(ctx context.Context, path string) ([]byte, error)
+}
+
+// ArtifactQuery restricts the objects listed from an ArtifactStore.
+type ArtifactQuery struct {
+	// Prefix is the prefix of the names of the objects.
+	Prefix string
+	// StartOffset and EndOffset, when set, restrict the objects to the ones whose names sort
+	// between StartOffset, included, and EndOffset, excluded.
+	StartOffset string
+	EndOffset   string
+	// Delimiter, when set, collapses the objects whose names contain it after the prefix into
+	// "directories".
+	Delimiter string
+}
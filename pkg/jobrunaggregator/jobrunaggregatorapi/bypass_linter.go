@@ -14,7 +14,6 @@ func init() {
 		fmt.Print(testRunsSummaryLast200RunsSchema)
 		fmt.Print(testRunsUnifiedTestRunsSingleResultForAllJobRunsSchema)
 		fmt.Print(testRunsSummaryAllJobRunsSchema)
-		fmt.Print(alertSchema)
 		fmt.Print(unifiedAlertSchema)
 		fmt.Print(testRunsUnifiedJobRunsSchema)
 	}
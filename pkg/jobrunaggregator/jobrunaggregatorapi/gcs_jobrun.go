@@ -4,16 +4,13 @@ import (
 	"context"
 	"encoding/xml"
 	"fmt"
-	"io"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
 
-	"cloud.google.com/go/storage"
 	"github.com/sirupsen/logrus"
-	"google.golang.org/api/iterator"
 
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	prowjobv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
@@ -23,7 +20,7 @@ import (
 
 type gcsJobRun struct {
 	// retrieval mechanisms
-	bkt *storage.BucketHandle
+	store ArtifactStore
 
 	jobRunGCSBucketRoot string
 	jobName             string
@@ -37,9 +34,9 @@ type gcsJobRun struct {
 	jobRunGCSBucket string
 }
 
-func NewGCSJobRun(bkt *storage.BucketHandle, jobGCSBucketRoot string, jobName, jobRunID string, jobRunGCSBucket string) JobRunInfo {
+func NewGCSJobRun(store ArtifactStore, jobGCSBucketRoot string, jobName, jobRunID string, jobRunGCSBucket string) JobRunInfo {
 	return &gcsJobRun{
-		bkt:                 bkt,
+		store:               store,
 		jobRunGCSBucketRoot: path.Join(jobGCSBucketRoot, jobRunID),
 		jobName:             jobName,
 		jobRunID:            jobRunID,
@@ -148,49 +145,24 @@ func (j *gcsJobRun) writeCache(ctx context.Context, parentDir string) error {
 }
 
 func (j *gcsJobRun) GetJobRunFromGCS(ctx context.Context) error {
-	query := &storage.Query{
-		// This ends up being the equivalent of:
-		// https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com/gcs/test-platform-results/logs/periodic-ci-openshift-release-master-nightly-4.9-upgrade-from-stable-4.8-e2e-metal-ipi-upgrade/1671747590984568832
-		// the next directory step is based on some bit of metadata I don't recognize
-		Prefix: j.jobRunGCSBucketRoot,
-
-		// TODO this field is apparently missing from this level of go/storage
-		// Omit owner and ACL fields for performance
-		// Projection: storage.ProjectionNoACL,
-	}
-
-	// Only retrieve the name and creation time for performance
-	if err := query.SetAttrSelection([]string{"Name", "Created"}); err != nil {
+	// This ends up being the equivalent of:
+	// https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com/gcs/test-platform-results/logs/periodic-ci-openshift-release-master-nightly-4.9-upgrade-from-stable-4.8-e2e-metal-ipi-upgrade/1671747590984568832
+	// the next directory step is based on some bit of metadata I don't recognize
+	// this will list *all* files with the prefix.
+	names, err := j.store.List(ctx, ArtifactQuery{Prefix: j.jobRunGCSBucketRoot})
+	if err != nil {
 		return err
 	}
 
-	// Returns an iterator which iterates over the bucket query results.
-	// this will list *all* files with the query prefix.
-	it := j.bkt.Objects(ctx, query)
-
 	// Find the query results we're the most interested in.
-	for {
-		attrs, err := it.Next()
-		if err == iterator.Done {
-			// we're done adding values
-			break
-		}
-		if err != nil {
-			return err
-		}
-
-		// if we have a directory then skip
-		if len(attrs.Name) == 0 {
-			continue
-		}
-
+	for _, name := range names {
 		// add the name
-		j.AddGCSProwJobFileNames(attrs.Name)
+		j.AddGCSProwJobFileNames(name)
 
 		// see if it is a junit
-		if strings.HasSuffix(attrs.Name, ".xml") && strings.Contains(attrs.Name, "/junit") {
-			logrus.Debugf("found %s", attrs.Name)
-			j.AddGCSJunitPaths(attrs.Name)
+		if strings.HasSuffix(name, ".xml") && strings.Contains(name, "/junit") {
+			logrus.Debugf("found %s", name)
+			j.AddGCSJunitPaths(name)
 		}
 	}
 
@@ -330,27 +302,11 @@ func (j *gcsJobRun) GetContent(ctx context.Context, path string) ([]byte, error)
 }
 
 func (j *gcsJobRun) getCurrentContent(ctx context.Context, path string) ([]byte, error) {
-	// Get an Object handle for the path
-	obj := j.bkt.Object(path)
-
-	// use the object attributes to try to get the latest generation to try to retrieve the data without getting a cached
-	// version of data that does not match the latest content.  I don't know if this will work, but in the easy case
-	// it doesn't seem to fail.
-	objAttrs, err := obj.Attrs(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("error reading GCS attributes for jobrun/%v/%v at %q: %w", j.GetJobName(), j.GetJobRunID(), path, err)
-	}
-	obj = obj.Generation(objAttrs.Generation)
-
-	// Get an io.Reader for the object.
-	gcsReader, err := obj.NewReader(ctx)
+	content, err := j.store.Read(ctx, path)
 	if err != nil {
-		return nil, fmt.Errorf("error reading GCS content for jobrun/%v/%v at %q: %w", j.GetJobName(), j.GetJobRunID(), path, err)
+		return nil, fmt.Errorf("error reading content for jobrun/%v/%v at %q: %w", j.GetJobName(), j.GetJobRunID(), path, err)
 	}
-	defer gcsReader.Close()
-
-	return io.ReadAll(gcsReader)
-
+	return content, nil
 }
 
 func (j *gcsJobRun) getAllContent(ctx context.Context) (map[string][]byte, error) {
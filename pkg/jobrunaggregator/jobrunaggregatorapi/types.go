@@ -1,6 +1,10 @@
 package jobrunaggregatorapi
 
-import "time"
+import (
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
 
 type UnifiedTestRunRow struct {
 	TestName        string
@@ -13,6 +17,21 @@ type UnifiedTestRunRow struct {
 	//JobLabels       []string
 }
 
+// TestHistoryRow sums up the runs of a test in the job runs of a job started on a day. A test which passed and
+// failed in the same job run flaked.
+type TestHistoryRow struct {
+	// Day is null in the row summing up the whole window
+	Day        bigquery.NullDate
+	PassCount  int64
+	FailCount  int64
+	FlakeCount int64
+	// the durations are null when the test runs were uploaded before their duration was
+	MeanDurationSeconds bigquery.NullFloat64
+	P50DurationSeconds  bigquery.NullFloat64
+	P95DurationSeconds  bigquery.NullFloat64
+	MaxDurationSeconds  bigquery.NullFloat64
+}
+
 type BackendDisruptionStatisticsRow struct {
 	BackendName       string
 	Mean              float64
@@ -118,6 +137,17 @@ type BackendDisruptionStatisticsRow struct {
 	P99               float64
 }
 
+// AlertStatisticsRow is the distribution of how long an alert fired in the job runs of one variant.
+type AlertStatisticsRow struct {
+	AlertName      string
+	AlertNamespace string
+	AlertLevel     string
+	// JobRuns is the number of job runs the percentiles are computed from.
+	JobRuns int64
+	P95     float64
+	P99     float64
+}
+
 // KnownAlertRow is used for results from the Alerts_AllKnown view.
 type KnownAlertRow struct {
 	AlertName      string
@@ -0,0 +1,71 @@
+package jobrunaggregatorapi
+
+import (
+	"time"
+)
+
+const (
+	AggregationRunsTableName = "AggregationRuns"
+
+	// the aggregation passed or failed, the aggregations that errored are run again by backfill
+	AggregationRunStatusPassed = "passed"
+	AggregationRunStatusFailed = "failed"
+	AggregationRunStatusError  = "error"
+
+	AggregationRunSchema = `
+[
+  {
+    "name": "JobName",
+    "description": "name of the job whose job runs were aggregated",
+    "type": "STRING",
+    "mode": "REQUIRED"
+  },
+  {
+    "name": "PayloadTag",
+    "description": "payload tag whose job runs were aggregated, like 4.14.0-0.ci-2023-06-18-131345",
+    "type": "STRING",
+    "mode": "REQUIRED"
+  },
+  {
+    "name": "AggregationTime",
+    "description": "time the aggregation ended",
+    "type": "TIMESTAMP",
+    "mode": "REQUIRED"
+  },
+  {
+    "name": "Status",
+    "description": "passed, failed or error",
+    "type": "STRING",
+    "mode": "REQUIRED"
+  },
+  {
+    "name": "JobRunCount",
+    "description": "number of job runs aggregated",
+    "type": "INTEGER",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "FailedTestCount",
+    "description": "number of tests which failed aggregation",
+    "type": "INTEGER",
+    "mode": "NULLABLE"
+  }
+]
+`
+)
+
+// AggregationRunRow records the outcome of one aggregation of the job runs of a payload
+type AggregationRunRow struct {
+	JobName         string
+	PayloadTag      string
+	AggregationTime time.Time
+	Status          string
+	JobRunCount     int
+	FailedTestCount int
+}
+
+// PayloadJobRunsRow is a payload tag the job ran for and when its first job run started
+type PayloadJobRunsRow struct {
+	ReleaseTag string
+	StartTime  time.Time
+}
@@ -7,7 +7,7 @@ import (
 const (
 	AlertsTableName = "Alerts"
 
-	alertSchema = `
+	AlertSchema = `
 [
   {
     "name": "JobRunName",
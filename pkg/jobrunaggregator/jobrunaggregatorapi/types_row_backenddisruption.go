@@ -28,7 +28,32 @@ INNER JOIN openshift-ci-data-analysis.ci_data.Jobs on JobRuns.JobName = Jobs.Job
 `
 )
 
-const BackendDisruptionTableName = "BackendDisruption"
+const (
+	BackendDisruptionTableName = "BackendDisruption"
+
+	BackendDisruptionSchema = `
+[
+  {
+    "name": "BackendName",
+    "description": "name of the backend polled for disruption",
+    "type": "STRING",
+    "mode": "REQUIRED"
+  },
+  {
+    "name": "JobRunName",
+    "description": "name of the jobrun (the long number)",
+    "type": "STRING",
+    "mode": "REQUIRED"
+  },
+  {
+    "name": "DisruptionSeconds",
+    "description": "number of seconds the backend was unavailable",
+    "type": "INTEGER",
+    "mode": "REQUIRED"
+  }
+]
+`
+)
 
 type BackendDisruptionRow struct {
 	BackendName        string
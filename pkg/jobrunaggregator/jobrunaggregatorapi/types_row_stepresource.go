@@ -0,0 +1,125 @@
+package jobrunaggregatorapi
+
+import (
+	"cloud.google.com/go/bigquery"
+)
+
+const (
+	StepResourceTableName       = "StepResources"
+	StepResourceJobRunTableName = "StepResources_JobRuns"
+
+	// StepResourceSchema holds what the container of a step of a job run requested and how it ended, read from the
+	// pods ci-operator records in build-resources/pods.json.
+	StepResourceSchema = `
+[
+  {
+    "name": "JobRunName",
+    "description": "name of the jobrun (the long number)",
+    "type": "STRING",
+    "mode": "REQUIRED"
+  },
+  {
+    "name": "Test",
+    "description": "the multi-stage test the step belongs to, empty for the builds and the container tests",
+    "type": "STRING",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "Step",
+    "description": "the step, or the build, the pod ran",
+    "type": "STRING",
+    "mode": "REQUIRED"
+  },
+  {
+    "name": "Pod",
+    "description": "name of the pod of the step",
+    "type": "STRING",
+    "mode": "REQUIRED"
+  },
+  {
+    "name": "Container",
+    "description": "name of the container in the pod",
+    "type": "STRING",
+    "mode": "REQUIRED"
+  },
+  {
+    "name": "CPURequestMillicores",
+    "description": "the CPU the container requested, in millicores",
+    "type": "INTEGER",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "CPULimitMillicores",
+    "description": "the CPU limit of the container, in millicores",
+    "type": "INTEGER",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "MemoryRequestBytes",
+    "description": "the memory the container requested, in bytes",
+    "type": "INTEGER",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "MemoryLimitBytes",
+    "description": "the memory limit of the container, in bytes",
+    "type": "INTEGER",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "DurationSeconds",
+    "description": "how long the container ran, in seconds",
+    "type": "FLOAT",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "ExitCode",
+    "description": "the exit code of the container",
+    "type": "INTEGER",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "TerminationReason",
+    "description": "why the container terminated: Completed, Error, OOMKilled, etc",
+    "type": "STRING",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "Restarts",
+    "description": "the number of times the container restarted",
+    "type": "INTEGER",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "PodReason",
+    "description": "why the pod failed when it did not run to completion, like Evicted",
+    "type": "STRING",
+    "mode": "NULLABLE"
+  }
+]
+`
+)
+
+type StepResourceRow struct {
+	JobRunName           string
+	Test                 bigquery.NullString
+	Step                 string
+	Pod                  string
+	Container            string
+	CPURequestMillicores bigquery.NullInt64
+	CPULimitMillicores   bigquery.NullInt64
+	MemoryRequestBytes   bigquery.NullInt64
+	MemoryLimitBytes     bigquery.NullInt64
+	DurationSeconds      bigquery.NullFloat64
+	ExitCode             bigquery.NullInt64
+	TerminationReason    bigquery.NullString
+	Restarts             bigquery.NullInt64
+	PodReason            bigquery.NullString
+	JobName              bigquery.NullString
+	JobRunStartTime      bigquery.NullTimestamp
+	JobRunEndTime        bigquery.NullTimestamp
+	Cluster              bigquery.NullString
+	ReleaseTag           bigquery.NullString
+	MasterNodesUpdated   bigquery.NullString
+	JobRunStatus         bigquery.NullString
+}
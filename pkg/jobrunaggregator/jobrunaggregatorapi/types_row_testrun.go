@@ -58,4 +58,5 @@ type TestRunRow struct {
 	ReleaseTag         bigquery.NullString
 	MasterNodesUpdated bigquery.NullString
 	JobRunStatus       bigquery.NullString
+	DurationSeconds    bigquery.NullFloat64
 }
@@ -0,0 +1,101 @@
+package jobrunaggregatorlib
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type AlertList struct {
+	// Alerts is keyed by name to make the consumption easier
+	Alerts []Alert
+}
+
+// name and namespace are consistent (usually) for every CI run
+type AlertKey struct {
+	Name      string
+	Namespace string
+	Level     AlertLevel
+}
+
+type AlertByKey []Alert
+
+func (a AlertByKey) Len() int      { return len(a) }
+func (a AlertByKey) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a AlertByKey) Less(i, j int) bool {
+	if strings.Compare(a[i].Name, a[j].Name) < 0 {
+		return true
+	}
+	if strings.Compare(a[i].Namespace, a[j].Namespace) < 0 {
+		return true
+	}
+	if strings.Compare(string(a[i].Level), string(a[j].Level)) < 0 {
+		return true
+	}
+
+	return false
+}
+
+type AlertLevel string
+
+var (
+	UnknownAlertLevel  AlertLevel = "Unknown"
+	WarningAlertLevel  AlertLevel = "Warning"
+	CriticalAlertLevel AlertLevel = "Critical"
+)
+
+type Alert struct {
+	AlertKey `json:",inline"`
+	Duration metav1.Duration
+}
+
+// GetAlertsFromDirectData takes the content of the alert files of a job run, one per test phase, and sums up how
+// long every alert fired over all of them
+func GetAlertsFromDirectData(alertData map[string]string) []Alert {
+	alertMap := map[AlertKey]*Alert{}
+
+	for _, alertJSON := range alertData {
+		if len(alertJSON) == 0 {
+			continue
+		}
+		allAlerts := &AlertList{}
+		if err := json.Unmarshal([]byte(alertJSON), allAlerts); err != nil {
+			logrus.WithError(err).Error("error unmarshalling alertJson")
+			continue
+		}
+
+		for i := range allAlerts.Alerts {
+			curr := allAlerts.Alerts[i]
+			existing, ok := alertMap[curr.AlertKey]
+			if !ok {
+				existing = &Alert{
+					AlertKey: curr.AlertKey,
+				}
+			}
+			existing.Duration.Duration = existing.Duration.Duration + curr.Duration.Duration
+			alertMap[existing.AlertKey] = existing
+		}
+	}
+
+	// sort for stable output for testing and such.
+	alertList := []Alert{}
+	for _, alert := range alertMap {
+		alertList = append(alertList, *alert)
+	}
+	sort.Stable(AlertByKey(alertList))
+	return alertList
+}
+
+// GetAlertSecondsFromDirectData is GetAlertsFromDirectData with the durations rounded up to seconds
+func GetAlertSecondsFromDirectData(alertData map[string]string) map[AlertKey]int {
+	alertSeconds := map[AlertKey]int{}
+	for _, alert := range GetAlertsFromDirectData(alertData) {
+		alertSeconds[alert.AlertKey] = int(math.Ceil(alert.Duration.Seconds()))
+	}
+	return alertSeconds
+}
@@ -0,0 +1,26 @@
+package jobrunaggregatorlib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAlertSecondsFromDirectData(t *testing.T) {
+	alertData := map[string]string{
+		"alert-upgrade.json": `{"Alerts": [
+			{"Name": "TargetDown", "Namespace": "kube-system", "Level": "Warning", "Duration": "1m30s"},
+			{"Name": "Watchdog", "Level": "Info", "Duration": "500ms"}
+		]}`,
+		"alert-conformance.json": `{"Alerts": [
+			{"Name": "TargetDown", "Namespace": "kube-system", "Level": "Warning", "Duration": "30s"}
+		]}`,
+		"alert-empty.json":   "",
+		"alert-invalid.json": "not json",
+	}
+
+	assert.Equal(t, map[AlertKey]int{
+		{Name: "TargetDown", Namespace: "kube-system", Level: WarningAlertLevel}: 120,
+		{Name: "Watchdog", Level: "Info"}:                                        1,
+	}, GetAlertSecondsFromDirectData(alertData))
+}
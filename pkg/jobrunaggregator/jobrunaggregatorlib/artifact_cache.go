@@ -0,0 +1,67 @@
+package jobrunaggregatorlib
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// artifactCache keeps artifacts read from the bucket on disk, keyed by their path and generation.
+// Overwriting an object creates a new generation, so the cached content never goes stale and
+// nothing needs to be invalidated.
+type artifactCache struct {
+	dir string
+}
+
+// isCacheableArtifact returns true for the artifacts which are read again by every analysis of a
+// job run: prowjob.json, finished.json and the junit files
+func isCacheableArtifact(name string) bool {
+	base := path.Base(name)
+	return base == "prowjob.json" || base == "finished.json" || strings.HasSuffix(base, ".xml")
+}
+
+func (c *artifactCache) path(name string, generation int64) string {
+	return filepath.Join(c.dir, filepath.FromSlash(name)+"@"+strconv.FormatInt(generation, 10))
+}
+
+// get returns the cached content of the generation of the artifact, if any
+func (c *artifactCache) get(name string, generation int64) ([]byte, bool, error) {
+	content, err := os.ReadFile(c.path(name, generation))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read cached artifact %s: %w", name, err)
+	}
+	return content, true, nil
+}
+
+// put caches the content of the generation of the artifact. The content is written to a temporary
+// file first, so that concurrent reads never see a partial artifact.
+func (c *artifactCache) put(name string, generation int64, content []byte) error {
+	cachePath := c.path(name, generation)
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory for artifact %s: %w", name, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(cachePath), filepath.Base(cachePath)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to cache artifact %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to cache artifact %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to cache artifact %s: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), cachePath); err != nil {
+		return fmt.Errorf("failed to cache artifact %s: %w", name, err)
+	}
+	return nil
+}
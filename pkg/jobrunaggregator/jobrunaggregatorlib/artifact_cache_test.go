@@ -0,0 +1,43 @@
+package jobrunaggregatorlib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArtifactCache(t *testing.T) {
+	cache := &artifactCache{dir: t.TempDir()}
+	name := "logs/job/1/prowjob.json"
+
+	_, ok, err := cache.get(name, 1)
+	assert.NoError(t, err)
+	assert.False(t, ok, "nothing is cached yet")
+
+	assert.NoError(t, cache.put(name, 1, []byte("generation 1")))
+	content, ok, err := cache.get(name, 1)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "generation 1", string(content))
+
+	_, ok, err = cache.get(name, 2)
+	assert.NoError(t, err)
+	assert.False(t, ok, "a new generation of the artifact is not cached")
+
+	assert.NoError(t, cache.put(name, 2, []byte("generation 2")))
+	content, _, err = cache.get(name, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "generation 2", string(content))
+}
+
+func TestIsCacheableArtifact(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"logs/job/1/prowjob.json":  true,
+		"logs/job/1/finished.json": true,
+		"logs/job/1/artifacts/e2e/openshift-e2e-test/artifacts/junit/junit_e2e_20230101-000000.xml": true,
+		"logs/job/1/build-log.txt": false,
+		"logs/job/1/artifacts/e2e/openshift-e2e-test/artifacts/junit/backend-disruption_20230101-000000.json": false,
+	} {
+		assert.Equal(t, expected, isCacheableArtifact(name), name)
+	}
+}
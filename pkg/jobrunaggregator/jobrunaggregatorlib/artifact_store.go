@@ -0,0 +1,258 @@
+package jobrunaggregatorlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/api/iterator"
+
+	pkgio "k8s.io/test-infra/prow/io"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
+)
+
+const s3BucketScheme = "s3://"
+
+// gcsArtifactStore reads artifacts from a GCS bucket
+type gcsArtifactStore struct {
+	bkt *storage.BucketHandle
+	// cache keeps the artifacts read again by every analysis when set
+	cache *artifactCache
+}
+
+// NewGCSArtifactStore returns an artifact store reading from the GCS bucket
+func NewGCSArtifactStore(bkt *storage.BucketHandle) jobrunaggregatorapi.ArtifactStore {
+	return &gcsArtifactStore{bkt: bkt}
+}
+
+// NewCachingGCSArtifactStore returns an artifact store reading from the GCS bucket, which keeps
+// the prowjob.json, finished.json and junit files it reads in the cache directory so that
+// analyzing the same job runs again does not download them again
+func NewCachingGCSArtifactStore(bkt *storage.BucketHandle, cacheDir string) jobrunaggregatorapi.ArtifactStore {
+	return &gcsArtifactStore{bkt: bkt, cache: &artifactCache{dir: cacheDir}}
+}
+
+func (s *gcsArtifactStore) List(ctx context.Context, query jobrunaggregatorapi.ArtifactQuery) (names []string, err error) {
+	defer func(start time.Time) { observeGCSRequest("list", start, err) }(time.Now())
+	gcsQuery := &storage.Query{
+		Prefix:      query.Prefix,
+		StartOffset: query.StartOffset,
+		EndOffset:   query.EndOffset,
+		Delimiter:   query.Delimiter,
+
+		// TODO this field is apparently missing from this level of go/storage
+		// Omit owner and ACL fields for performance
+		// Projection: storage.ProjectionNoACL,
+	}
+	if len(query.Delimiter) == 0 {
+		// Only retrieve the name and creation time for performance. This cannot be used
+		// when listing "directories".
+		if err := gcsQuery.SetAttrSelection([]string{"Name", "Created"}); err != nil {
+			return nil, err
+		}
+	}
+
+	it := s.bkt.Objects(ctx, gcsQuery)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(attrs.Name) == 0 {
+			// a "directory"
+			names = append(names, attrs.Prefix)
+			continue
+		}
+		names = append(names, attrs.Name)
+	}
+	return names, nil
+}
+
+func (s *gcsArtifactStore) Read(ctx context.Context, path string) (content []byte, err error) {
+	defer func(start time.Time) { observeGCSRequest("read", start, err) }(time.Now())
+	// Get an Object handle for the path
+	obj := s.bkt.Object(path)
+
+	// use the object attributes to try to get the latest generation to try to retrieve the data without getting a cached
+	// version of data that does not match the latest content.  I don't know if this will work, but in the easy case
+	// it doesn't seem to fail.
+	objAttrs, err := obj.Attrs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading GCS attributes: %w", err)
+	}
+	obj = obj.Generation(objAttrs.Generation)
+
+	cache := s.cache
+	if cache != nil && !isCacheableArtifact(path) {
+		cache = nil
+	}
+	if cache != nil {
+		content, ok, err := cache.get(path, objAttrs.Generation)
+		if err != nil {
+			logrus.WithError(err).Warn("Failed to read the artifact from the cache.")
+		}
+		if ok {
+			return content, nil
+		}
+	}
+
+	// Get an io.Reader for the object.
+	gcsReader, err := obj.NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading GCS content: %w", err)
+	}
+	defer gcsReader.Close()
+
+	content, err = io.ReadAll(gcsReader)
+	if err != nil {
+		return nil, err
+	}
+	if cache != nil {
+		if err := cache.put(path, objAttrs.Generation, content); err != nil {
+			logrus.WithError(err).Warn("Failed to cache the artifact.")
+		}
+	}
+	return content, nil
+}
+
+// openerArtifactStore reads artifacts from a bucket that is accessed through a prow opener, like
+// S3 or S3-compatible storage like MinIO
+type openerArtifactStore struct {
+	opener pkgio.Opener
+	// bucketURL is the URL of the bucket, like s3://bucket
+	bucketURL string
+}
+
+// NewS3ArtifactStore returns an artifact store reading from the bucket at the s3:// URL. The
+// credentials file is described by k8s.io/test-infra/prow/io/providers.GetBucket; when it is
+// empty, the credentials are discovered from the environment.
+func NewS3ArtifactStore(ctx context.Context, bucketURL, credentialsFile string) (jobrunaggregatorapi.ArtifactStore, error) {
+	if !strings.HasPrefix(bucketURL, s3BucketScheme) {
+		return nil, fmt.Errorf("bucket URL %q does not start with %s", bucketURL, s3BucketScheme)
+	}
+	opener, err := pkgio.NewOpener(ctx, "", credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create opener for bucket %s: %w", bucketURL, err)
+	}
+	return &openerArtifactStore{opener: opener, bucketURL: strings.TrimSuffix(bucketURL, "/")}, nil
+}
+
+func (s *openerArtifactStore) List(ctx context.Context, query jobrunaggregatorapi.ArtifactQuery) ([]string, error) {
+	it, err := s.opener.Iterator(ctx, s.bucketURL+"/"+query.Prefix, query.Delimiter)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for {
+		attrs, err := it.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(attrs.Name, query.Prefix) {
+			continue
+		}
+		// the offsets are not supported by all providers, so they are applied once listed
+		if inOffsets(attrs.Name, query) {
+			names = append(names, attrs.Name)
+		}
+	}
+	return names, nil
+}
+
+func (s *openerArtifactStore) Read(ctx context.Context, path string) ([]byte, error) {
+	reader, err := s.opener.Reader(ctx, s.bucketURL+"/"+path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// localArtifactStore reads artifacts from a local directory laid out like the bucket, which
+// allows to analyze downloaded artifacts without access to the bucket
+type localArtifactStore struct {
+	dir string
+}
+
+// NewLocalArtifactStore returns an artifact store reading from the directory
+func NewLocalArtifactStore(dir string) jobrunaggregatorapi.ArtifactStore {
+	return &localArtifactStore{dir: dir}
+}
+
+func (s *localArtifactStore) List(_ context.Context, query jobrunaggregatorapi.ArtifactQuery) ([]string, error) {
+	// only the directory holding the prefix needs to be walked
+	root := path.Dir(query.Prefix + "_")
+	var names []string
+	err := filepath.WalkDir(filepath.Join(s.dir, filepath.FromSlash(root)), func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		rel, err := filepath.Rel(s.dir, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if entry.IsDir() {
+			name += "/"
+		}
+		if !strings.HasPrefix(name, query.Prefix) {
+			// only the directories leading to the prefix need to be descended into
+			if entry.IsDir() && name != "./" && !strings.HasPrefix(query.Prefix, name) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			// with a delimiter, the "directories" below the prefix are listed instead of their content
+			if len(query.Delimiter) > 0 && len(name) > len(query.Prefix) {
+				if i := strings.Index(name[len(query.Prefix):], query.Delimiter); i >= 0 {
+					if name := name[:len(query.Prefix)+i+len(query.Delimiter)]; inOffsets(name, query) {
+						names = append(names, name)
+					}
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+		if inOffsets(name, query) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func inOffsets(name string, query jobrunaggregatorapi.ArtifactQuery) bool {
+	if len(query.StartOffset) > 0 && name < query.StartOffset {
+		return false
+	}
+	return len(query.EndOffset) == 0 || name < query.EndOffset
+}
+
+func (s *localArtifactStore) Read(_ context.Context, name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(name)))
+}
@@ -0,0 +1,148 @@
+package jobrunaggregatorlib
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	prowv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+	pkgio "k8s.io/test-infra/prow/io"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
+)
+
+// fakeOpener serves the objects of a single bucket, listing them like gocloud does
+type fakeOpener struct {
+	pkgio.Opener
+	bucketURL string
+	objects   map[string]string
+}
+
+type fakeIterator struct {
+	attrs []pkgio.ObjectAttributes
+}
+
+func (i *fakeIterator) Next(context.Context) (pkgio.ObjectAttributes, error) {
+	if len(i.attrs) == 0 {
+		return pkgio.ObjectAttributes{}, io.EOF
+	}
+	next := i.attrs[0]
+	i.attrs = i.attrs[1:]
+	return next, nil
+}
+
+func (o *fakeOpener) Iterator(_ context.Context, prefix, delimiter string) (pkgio.ObjectIterator, error) {
+	relativePath := strings.TrimPrefix(prefix, o.bucketURL+"/")
+	seen := map[string]pkgio.ObjectAttributes{}
+	for name := range o.objects {
+		if !strings.HasPrefix(name, relativePath) {
+			continue
+		}
+		if i := strings.Index(strings.TrimPrefix(name, relativePath), delimiter); len(delimiter) > 0 && i >= 0 {
+			dir := name[:len(relativePath)+i+1]
+			seen[dir] = pkgio.ObjectAttributes{Name: dir, IsDir: true}
+			continue
+		}
+		seen[name] = pkgio.ObjectAttributes{Name: name}
+	}
+	it := &fakeIterator{}
+	for _, attrs := range seen {
+		it.attrs = append(it.attrs, attrs)
+	}
+	sort.Slice(it.attrs, func(i, j int) bool { return it.attrs[i].Name < it.attrs[j].Name })
+	return it, nil
+}
+
+func (o *fakeOpener) Reader(_ context.Context, path string) (io.ReadCloser, error) {
+	content, ok := o.objects[strings.TrimPrefix(path, o.bucketURL+"/")]
+	if !ok {
+		return nil, pkgio.ErrNotFoundTest
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+func prowJobContent(t *testing.T, jobName, jobRunID, aggregationID string) string {
+	raw, err := json.Marshal(prowv1.ProwJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{prowJobJobRunIDLabel: jobRunID, ProwJobAggregationIDLabel: aggregationID},
+			Annotations: map[string]string{ProwJobJobNameAnnotation: jobName, prowJobReleaseJobNameAnnotation: jobName},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return string(raw)
+}
+
+func TestArtifactStores(t *testing.T) {
+	objects := map[string]string{
+		"logs/job/1/prowjob.json":                    prowJobContent(t, "job", "1", "a"),
+		"logs/job/2/prowjob.json":                    prowJobContent(t, "job", "2", "b"),
+		"logs/job/2/artifacts/junit/junit_e2e.xml":   "<testsuite/>",
+		"logs/job/3/prowjob.json":                    prowJobContent(t, "job", "3", "a"),
+		"logs/job/4/prowjob.json":                    prowJobContent(t, "job", "4", "a"),
+		"logs/other-job/1/prowjob.json":              prowJobContent(t, "other-job", "1", "a"),
+		"logs/other-job/1/artifacts/junit/junit.xml": "<testsuite/>",
+	}
+
+	dir := t.TempDir()
+	for name, content := range objects {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write object: %v", err)
+		}
+	}
+
+	for name, store := range map[string]jobrunaggregatorapi.ArtifactStore{
+		"s3":    &openerArtifactStore{bucketURL: "s3://bucket", opener: &fakeOpener{bucketURL: "s3://bucket", objects: objects}},
+		"local": NewLocalArtifactStore(dir),
+	} {
+		t.Run(name, func(t *testing.T) {
+			names, err := store.List(context.Background(), jobrunaggregatorapi.ArtifactQuery{Prefix: "logs/job/", StartOffset: "logs/job/2", EndOffset: "logs/job/4", Delimiter: "/"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, []string{"logs/job/2/", "logs/job/3/"}, names)
+
+			content, err := store.Read(context.Background(), "logs/job/2/artifacts/junit/junit_e2e.xml")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, "<testsuite/>", string(content))
+
+			_, err = store.Read(context.Background(), "logs/job/5/prowjob.json")
+			assert.True(t, pkgio.IsNotExist(err))
+
+			client := &ciGCSClient{store: store, gcsBucketName: "bucket", concurrency: 2}
+			jobRuns, err := client.ReadRelatedJobRuns(context.Background(), "job", "logs/job", "1", "4", NewProwJobMatcherFuncForPR("job", "a", ProwJobAggregationIDLabel))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var ids []string
+			for _, jobRun := range jobRuns {
+				ids = append(ids, jobRun.GetJobRunID())
+			}
+			assert.Equal(t, []string{"1", "3"}, ids)
+
+			jobRun, err := client.ReadJobRunFromGCS(context.Background(), "logs/job", "job", "2", logrus.New())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := jobRun.GetJobRunFromGCS(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, []string{"logs/job/2/artifacts/junit/junit_e2e.xml"}, jobRun.GetGCSJunitPaths())
+		})
+	}
+}
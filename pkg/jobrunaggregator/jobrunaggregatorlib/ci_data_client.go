@@ -32,6 +32,10 @@ type AggregationJobClient interface {
 	// GetBackendDisruptionStatisticsByJob gets the mean and p95 disruption per backend from the week from 10 days ago.
 	GetBackendDisruptionStatisticsByJob(ctx context.Context, jobName, masterNodesUpdated string) ([]jobrunaggregatorapi.BackendDisruptionStatisticsRow, error)
 
+	// GetAlertStatisticsByJob gets the p95 and p99 of how long every alert fired in the job runs of the release and
+	// platform of the job, in the week from startDay.
+	GetAlertStatisticsByJob(ctx context.Context, jobName string, startDay time.Time) ([]jobrunaggregatorapi.AlertStatisticsRow, error)
+
 	ListAggregatedTestRunsForJob(ctx context.Context, frequency, jobName string, startDay time.Time) ([]jobrunaggregatorapi.AggregatedTestRunRow, error)
 }
 
@@ -41,6 +45,13 @@ type TestRunSummarizerClient interface {
 	ListUnifiedTestRunsForJobAfterDay(ctx context.Context, jobName string, startDay time.Time) (*UnifiedTestRunRowIterator, error)
 }
 
+// TestHistoryClient client view used to query the history of a test
+type TestHistoryClient interface {
+	// GetTestHistory sums up the runs of the test in the job runs of the job started between the times, one row
+	// per day, followed by the row of the whole window.
+	GetTestHistory(ctx context.Context, jobName, testName string, from, to time.Time) ([]jobrunaggregatorapi.TestHistoryRow, error)
+}
+
 type JobLister interface {
 	ListAllJobs(ctx context.Context) ([]jobrunaggregatorapi.JobRow, error)
 
@@ -55,10 +66,20 @@ type HistoricalDataClient interface {
 	ListAlertHistoricalData(ctx context.Context) ([]*jobrunaggregatorapi.AlertHistoricalDataRow, error)
 }
 
+// AggregationBackfillClient client view used to find the aggregations to run again
+type AggregationBackfillClient interface {
+	// ListPayloadsForJob lists the payload tags the job ran for between the times, with the start of their first job run.
+	ListPayloadsForJob(ctx context.Context, jobName string, from, to time.Time) ([]jobrunaggregatorapi.PayloadJobRunsRow, error)
+	// ListAggregationRunsForJob lists the aggregations of the job runs of the job recorded since the time.
+	ListAggregationRunsForJob(ctx context.Context, jobName string, since time.Time) ([]jobrunaggregatorapi.AggregationRunRow, error)
+}
+
 type CIDataClient interface {
 	JobLister
 	AggregationJobClient
+	AggregationBackfillClient
 	TestRunSummarizerClient
+	TestHistoryClient
 	HistoricalDataClient
 
 	// these deal with release tags
@@ -835,6 +856,64 @@ ORDER BY UnifiedTestRuns.JobRunStartTime ASC
 	return &UnifiedTestRunRowIterator{delegatedIterator: it}, nil
 }
 
+func (c *ciDataClient) GetTestHistory(ctx context.Context, jobName, testName string, from, to time.Time) ([]jobrunaggregatorapi.TestHistoryRow, error) {
+	// a test runs more than once in a job run when it is retried, so the runs are first reduced to one result per job run
+	queryString := c.dataCoordinates.SubstituteDataSetLocation(`
+WITH TestRunsPerJobRun AS (
+    SELECT
+        JobRunName,
+        DATE(MIN(JobRunStartTime)) AS Day,
+        COUNTIF(Status = "Passed") AS Passed,
+        COUNTIF(Status = "Failed") AS Failed,
+        SUM(DurationSeconds) AS DurationSeconds
+    FROM DATA_SET_LOCATION.` + jobrunaggregatorapi.TestRunTableName + `
+    WHERE
+        JobName = @JobName AND
+        Name = @TestName AND
+        JobRunStartTime >= @From AND
+        JobRunStartTime < @To
+    GROUP BY JobRunName
+)
+SELECT
+    Day,
+    COUNTIF(Passed > 0 AND Failed = 0) AS PassCount,
+    COUNTIF(Passed = 0 AND Failed > 0) AS FailCount,
+    COUNTIF(Passed > 0 AND Failed > 0) AS FlakeCount,
+    AVG(DurationSeconds) AS MeanDurationSeconds,
+    APPROX_QUANTILES(DurationSeconds, 100)[SAFE_OFFSET(50)] AS P50DurationSeconds,
+    APPROX_QUANTILES(DurationSeconds, 100)[SAFE_OFFSET(95)] AS P95DurationSeconds,
+    MAX(DurationSeconds) AS MaxDurationSeconds,
+FROM TestRunsPerJobRun
+GROUP BY ROLLUP(Day)
+ORDER BY Day IS NULL, Day ASC
+`)
+	query := c.client.Query(queryString)
+	query.QueryConfig.Parameters = []bigquery.QueryParameter{
+		{Name: "JobName", Value: jobName},
+		{Name: "TestName", Value: testName},
+		{Name: "From", Value: from},
+		{Name: "To", Value: to},
+	}
+	rows, err := query.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query test history with %q: %w", queryString, err)
+	}
+
+	var history []jobrunaggregatorapi.TestHistoryRow
+	for {
+		row := jobrunaggregatorapi.TestHistoryRow{}
+		err := rows.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, row)
+	}
+	return history, nil
+}
+
 func (c *ciDataClient) ListReleaseTags(ctx context.Context) (sets.Set[string], error) {
 	set := sets.Set[string]{}
 	queryString := c.dataCoordinates.SubstituteDataSetLocation(`SELECT distinct(ReleaseTag) FROM DATA_SET_LOCATION.ReleaseTags`)
@@ -873,6 +952,72 @@ func (it *UnifiedTestRunRowIterator) Next() (*jobrunaggregatorapi.UnifiedTestRun
 	return ret, nil
 }
 
+func (c *ciDataClient) ListPayloadsForJob(ctx context.Context, jobName string, from, to time.Time) ([]jobrunaggregatorapi.PayloadJobRunsRow, error) {
+	queryString := c.dataCoordinates.SubstituteDataSetLocation(
+		`SELECT ReleaseTag, MIN(StartTime) AS StartTime
+FROM DATA_SET_LOCATION.JobRuns
+WHERE JobRuns.JobName = @JobName AND JobRuns.StartTime >= @From AND JobRuns.StartTime < @To AND JobRuns.ReleaseTag IS NOT NULL AND JobRuns.ReleaseTag != ""
+GROUP BY ReleaseTag
+ORDER BY StartTime ASC
+`)
+	query := c.client.Query(queryString)
+	query.QueryConfig.Parameters = []bigquery.QueryParameter{
+		{Name: "JobName", Value: jobName},
+		{Name: "From", Value: from},
+		{Name: "To", Value: to},
+	}
+	rows, err := query.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job runs with %q: %w", queryString, err)
+	}
+
+	var payloads []jobrunaggregatorapi.PayloadJobRunsRow
+	for {
+		row := jobrunaggregatorapi.PayloadJobRunsRow{}
+		err := rows.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, row)
+	}
+	return payloads, nil
+}
+
+func (c *ciDataClient) ListAggregationRunsForJob(ctx context.Context, jobName string, since time.Time) ([]jobrunaggregatorapi.AggregationRunRow, error) {
+	queryString := c.dataCoordinates.SubstituteDataSetLocation(
+		`SELECT *
+FROM DATA_SET_LOCATION.` + jobrunaggregatorapi.AggregationRunsTableName + `
+WHERE JobName = @JobName AND AggregationTime >= @Since
+ORDER BY AggregationTime ASC
+`)
+	query := c.client.Query(queryString)
+	query.QueryConfig.Parameters = []bigquery.QueryParameter{
+		{Name: "JobName", Value: jobName},
+		{Name: "Since", Value: since},
+	}
+	rows, err := query.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query aggregation runs with %q: %w", queryString, err)
+	}
+
+	var aggregationRuns []jobrunaggregatorapi.AggregationRunRow
+	for {
+		row := jobrunaggregatorapi.AggregationRunRow{}
+		err := rows.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		aggregationRuns = append(aggregationRuns, row)
+	}
+	return aggregationRuns, nil
+}
+
 func (c *ciDataClient) GetJobRunForJobNameBeforeTime(ctx context.Context, jobName string, targetTime time.Time) (string, error) {
 	queryString := c.dataCoordinates.SubstituteDataSetLocation(
 		`SELECT Name
@@ -933,6 +1078,63 @@ LIMIT 1
 	return ret.Name, nil
 }
 
+func (c *ciDataClient) GetAlertStatisticsByJob(ctx context.Context, jobName string, startDay time.Time) ([]jobrunaggregatorapi.AlertStatisticsRow, error) {
+	// the alerts are compared to the job runs of every job of the same release and platform, the zeros uploaded for
+	// the known alerts which did not fire make the percentiles of the alerts which rarely fire meaningful.
+	queryString := c.dataCoordinates.SubstituteDataSetLocation(`
+WITH Variant AS (
+    SELECT Release, FromRelease, Platform, Architecture, Network, Topology
+    FROM DATA_SET_LOCATION.Jobs
+    WHERE JobName = @JobName
+)
+SELECT
+    Alerts.Name AS AlertName,
+    IFNULL(Alerts.Namespace, "") AS AlertNamespace,
+    Alerts.Level AS AlertLevel,
+    COUNT(DISTINCT Alerts.JobRunName) AS JobRuns,
+    APPROX_QUANTILES(Alerts.AlertSeconds, 100)[OFFSET(95)] AS P95,
+    APPROX_QUANTILES(Alerts.AlertSeconds, 100)[OFFSET(99)] AS P99,
+FROM DATA_SET_LOCATION.Alerts AS Alerts
+INNER JOIN DATA_SET_LOCATION.Alerts_JobRuns AS JobRuns ON Alerts.JobRunName = JobRuns.Name
+INNER JOIN DATA_SET_LOCATION.Jobs AS Jobs ON JobRuns.JobName = Jobs.JobName
+INNER JOIN Variant ON
+    Jobs.Release = Variant.Release AND
+    Jobs.FromRelease = Variant.FromRelease AND
+    Jobs.Platform = Variant.Platform AND
+    Jobs.Architecture = Variant.Architecture AND
+    Jobs.Network = Variant.Network AND
+    Jobs.Topology = Variant.Topology
+WHERE
+    JobRuns.StartTime >= @StartDay AND
+    JobRuns.StartTime < TIMESTAMP_ADD(@StartDay, INTERVAL 7 DAY)
+GROUP BY AlertName, AlertNamespace, AlertLevel
+`)
+
+	query := c.client.Query(queryString)
+	query.QueryConfig.Parameters = []bigquery.QueryParameter{
+		{Name: "JobName", Value: jobName},
+		{Name: "StartDay", Value: startDay},
+	}
+	rows, err := query.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert statistics with %q: %w", queryString, err)
+	}
+	ret := []jobrunaggregatorapi.AlertStatisticsRow{}
+	for {
+		row := jobrunaggregatorapi.AlertStatisticsRow{}
+		err = rows.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, row)
+	}
+
+	return ret, nil
+}
+
 func (c *ciDataClient) ListAggregatedTestRunsForJob(ctx context.Context, frequency, jobName string, startDay time.Time) ([]jobrunaggregatorapi.AggregatedTestRunRow, error) {
 	frequencyTable, err := c.tableForFrequency(frequency)
 	if err != nil {
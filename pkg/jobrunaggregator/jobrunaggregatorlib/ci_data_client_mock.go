@@ -39,6 +39,21 @@ func (m *MockCIDataClient) EXPECT() *MockCIDataClientMockRecorder {
 	return m.recorder
 }
 
+// GetAlertStatisticsByJob mocks base method.
+func (m *MockCIDataClient) GetAlertStatisticsByJob(arg0 context.Context, arg1 string, arg2 time.Time) ([]jobrunaggregatorapi.AlertStatisticsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlertStatisticsByJob", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]jobrunaggregatorapi.AlertStatisticsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAlertStatisticsByJob indicates an expected call of GetAlertStatisticsByJob.
+func (mr *MockCIDataClientMockRecorder) GetAlertStatisticsByJob(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlertStatisticsByJob", reflect.TypeOf((*MockCIDataClient)(nil).GetAlertStatisticsByJob), arg0, arg1, arg2)
+}
+
 // GetBackendDisruptionRowCountByJob mocks base method.
 func (m *MockCIDataClient) GetBackendDisruptionRowCountByJob(arg0 context.Context, arg1, arg2 string) (uint64, error) {
 	m.ctrl.T.Helper()
@@ -129,6 +144,21 @@ func (mr *MockCIDataClientMockRecorder) GetLastJobRunEndTimeFromTable(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastJobRunEndTimeFromTable", reflect.TypeOf((*MockCIDataClient)(nil).GetLastJobRunEndTimeFromTable), arg0, arg1)
 }
 
+// GetTestHistory mocks base method.
+func (m *MockCIDataClient) GetTestHistory(arg0 context.Context, arg1, arg2 string, arg3, arg4 time.Time) ([]jobrunaggregatorapi.TestHistoryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTestHistory", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]jobrunaggregatorapi.TestHistoryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTestHistory indicates an expected call of GetTestHistory.
+func (mr *MockCIDataClientMockRecorder) GetTestHistory(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTestHistory", reflect.TypeOf((*MockCIDataClient)(nil).GetTestHistory), arg0, arg1, arg2, arg3, arg4)
+}
+
 // ListAggregatedTestRunsForJob mocks base method.
 func (m *MockCIDataClient) ListAggregatedTestRunsForJob(arg0 context.Context, arg1, arg2 string, arg3 time.Time) ([]jobrunaggregatorapi.AggregatedTestRunRow, error) {
 	m.ctrl.T.Helper()
@@ -144,6 +174,21 @@ func (mr *MockCIDataClientMockRecorder) ListAggregatedTestRunsForJob(arg0, arg1,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAggregatedTestRunsForJob", reflect.TypeOf((*MockCIDataClient)(nil).ListAggregatedTestRunsForJob), arg0, arg1, arg2, arg3)
 }
 
+// ListAggregationRunsForJob mocks base method.
+func (m *MockCIDataClient) ListAggregationRunsForJob(arg0 context.Context, arg1 string, arg2 time.Time) ([]jobrunaggregatorapi.AggregationRunRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAggregationRunsForJob", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]jobrunaggregatorapi.AggregationRunRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAggregationRunsForJob indicates an expected call of ListAggregationRunsForJob.
+func (mr *MockCIDataClientMockRecorder) ListAggregationRunsForJob(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAggregationRunsForJob", reflect.TypeOf((*MockCIDataClient)(nil).ListAggregationRunsForJob), arg0, arg1, arg2)
+}
+
 // ListAlertHistoricalData mocks base method.
 func (m *MockCIDataClient) ListAlertHistoricalData(arg0 context.Context) ([]*jobrunaggregatorapi.AlertHistoricalDataRow, error) {
 	m.ctrl.T.Helper()
@@ -204,6 +249,21 @@ func (mr *MockCIDataClientMockRecorder) ListDisruptionHistoricalData(arg0 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDisruptionHistoricalData", reflect.TypeOf((*MockCIDataClient)(nil).ListDisruptionHistoricalData), arg0)
 }
 
+// ListPayloadsForJob mocks base method.
+func (m *MockCIDataClient) ListPayloadsForJob(arg0 context.Context, arg1 string, arg2, arg3 time.Time) ([]jobrunaggregatorapi.PayloadJobRunsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPayloadsForJob", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]jobrunaggregatorapi.PayloadJobRunsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPayloadsForJob indicates an expected call of ListPayloadsForJob.
+func (mr *MockCIDataClientMockRecorder) ListPayloadsForJob(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPayloadsForJob", reflect.TypeOf((*MockCIDataClient)(nil).ListPayloadsForJob), arg0, arg1, arg2, arg3)
+}
+
 // ListProwJobRunsSince mocks base method.
 func (m *MockCIDataClient) ListProwJobRunsSince(arg0 context.Context, arg1 *time.Time) ([]*jobrunaggregatorapi.TestPlatformProwJobRow, error) {
 	m.ctrl.T.Helper()
@@ -2,34 +2,108 @@ package jobrunaggregatorlib
 
 import (
 	"encoding/json"
+	"fmt"
 	"math"
+	"os"
 	"strings"
 
 	"github.com/sirupsen/logrus"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
 )
 
-var (
-	upgradeBackendNameToTestSubstring = map[string]string{
-		"kube-api-new-connections":                          "Kubernetes APIs remain available for new connections",
-		"kube-api-reused-connections":                       "Kubernetes APIs remain available with reused connections",
-		"openshift-api-new-connections":                     "OpenShift APIs remain available for new connections",
-		"openshift-api-reused-connections":                  "OpenShift APIs remain available with reused connections",
-		"oauth-api-new-connections":                         "OAuth APIs remain available for new connections",
-		"oauth-api-reused-connections":                      "OAuth APIs remain available with reused connections",
-		"service-load-balancer-with-pdb-reused-connections": "Application behind service load balancer with PDB is not disrupted",
-		"image-registry-reused-connections":                 "Image registry remain available",
-		"ingress-to-oauth-server-new-connections":           "OAuth remains available via cluster frontend ingress using new connections",
-		"ingress-to-oauth-server-used-connections":          "OAuth remains available via cluster frontend ingress using reused connections",
-		"ingress-to-console-new-connections":                "Console remains available via cluster frontend ingress using new connections",
-		"ingress-to-console-used-connections":               "Console remains available via cluster frontend ingress using reused connections",
+// DisruptionBackends decides which disruption backends are uploaded and analyzed, so that backends
+// can be added without changing the aggregator. It is read from the file given with
+// --disruption-backends-file, like:
+//
+//	required:
+//	- kube-api-new-connections
+//	- image-registry-reused-connections
+//	excludedFromAnalysis:
+//	- pod-to-pod
+//	ignored:
+//	- ci-cluster-network-liveness
+//
+// The file replaces the default backends, it is not merged with them.
+type DisruptionBackends struct {
+	// Required are the backends analyzed even when no job run reports them, so that a backend which stops
+	// being monitored fails the aggregation. Every other backend reported by the job runs is analyzed too.
+	Required []string `json:"required,omitempty"`
+	// ExcludedFromAnalysis are substrings of the names of the backends which are uploaded but not analyzed,
+	// while they gather enough data to compare against
+	ExcludedFromAnalysis []string `json:"excludedFromAnalysis,omitempty"`
+	// Ignored are substrings of the names of the backends which are neither uploaded nor analyzed
+	Ignored []string `json:"ignored,omitempty"`
+}
+
+// DefaultDisruptionBackends are the backends used without a backends file
+func DefaultDisruptionBackends() *DisruptionBackends {
+	return &DisruptionBackends{
+		Required: []string{
+			"image-registry-reused-connections",
+			"ingress-to-console-new-connections",
+			"ingress-to-console-used-connections",
+			"ingress-to-oauth-server-new-connections",
+			"ingress-to-oauth-server-used-connections",
+			"kube-api-new-connections",
+			"kube-api-reused-connections",
+			"oauth-api-new-connections",
+			"oauth-api-reused-connections",
+			"openshift-api-new-connections",
+			"openshift-api-reused-connections",
+			"service-load-balancer-with-pdb-reused-connections",
+		},
+		ExcludedFromAnalysis: []string{
+			"ci-cluster-network-liveness",
+			"kube-api-http1-external-lb",
+			"kube-api-http2-external-lb",
+			"openshift-api-http2-external-lb",
+			"host-to-service",
+			"host-to-host",
+			"host-to-pod",
+			"pod-to-host",
+			"pod-to-pod",
+			"pod-to-service",
+		},
 	}
-)
+}
+
+// LoadDisruptionBackends reads and validates the backends file
+func LoadDisruptionBackends(path string) (*DisruptionBackends, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read disruption backends file: %w", err)
+	}
+	backends := &DisruptionBackends{}
+	if err := yaml.UnmarshalStrict(raw, backends); err != nil {
+		return nil, fmt.Errorf("failed to parse disruption backends file %s: %w", path, err)
+	}
+	for _, name := range backends.Required {
+		if backends.IsIgnored(name) || backends.IsExcludedFromAnalysis(name) {
+			return nil, fmt.Errorf("invalid disruption backends file %s: required backend %s is excluded from the analysis", path, name)
+		}
+	}
+	return backends, nil
+}
+
+// IsExcludedFromAnalysis returns true when the backend is not analyzed
+func (b *DisruptionBackends) IsExcludedFromAnalysis(name string) bool {
+	return b.IsIgnored(name) || containsAny(name, b.ExcludedFromAnalysis)
+}
 
-func RequiredDisruptionTests() sets.String {
-	return sets.StringKeySet(upgradeBackendNameToTestSubstring)
+// IsIgnored returns true when the backend is neither uploaded nor analyzed
+func (b *DisruptionBackends) IsIgnored(name string) bool {
+	return containsAny(name, b.Ignored)
+}
+
+func containsAny(name string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(name, substring) {
+			return true
+		}
+	}
+	return false
 }
 
 type AvailabilityResult struct {
@@ -0,0 +1,84 @@
+package jobrunaggregatorlib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadDisruptionBackends(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expected      *DisruptionBackends
+		expectedError bool
+	}{
+		{
+			name: "backends",
+			content: `required:
+- kube-api-new-connections
+- image-registry-new-connections
+excludedFromAnalysis:
+- pod-to-pod
+ignored:
+- ci-cluster-network-liveness
+`,
+			expected: &DisruptionBackends{
+				Required:             []string{"kube-api-new-connections", "image-registry-new-connections"},
+				ExcludedFromAnalysis: []string{"pod-to-pod"},
+				Ignored:              []string{"ci-cluster-network-liveness"},
+			},
+		},
+		{
+			name:     "empty file requires no backend",
+			content:  "{}",
+			expected: &DisruptionBackends{},
+		},
+		{
+			name:          "unknown field",
+			content:       "excluded:\n- pod-to-pod",
+			expectedError: true,
+		},
+		{
+			name:          "required backend excluded from the analysis",
+			content:       "required:\n- pod-to-pod-new-connections\nexcludedFromAnalysis:\n- pod-to-pod",
+			expectedError: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "backends.yaml")
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("failed to write backends file: %v", err)
+			}
+			actual, err := LoadDisruptionBackends(path)
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestDisruptionBackendsExclusions(t *testing.T) {
+	backends := &DisruptionBackends{
+		ExcludedFromAnalysis: []string{"pod-to-pod"},
+		Ignored:              []string{"ci-cluster-network-liveness"},
+	}
+	for _, tc := range []struct {
+		backend          string
+		excludedAnalysis bool
+		ignored          bool
+	}{
+		{backend: "kube-api-new-connections"},
+		{backend: "pod-to-pod-new-connections", excludedAnalysis: true},
+		{backend: "ci-cluster-network-liveness-reused-connections", excludedAnalysis: true, ignored: true},
+	} {
+		assert.Equal(t, tc.excludedAnalysis, backends.IsExcludedFromAnalysis(tc.backend), tc.backend)
+		assert.Equal(t, tc.ignored, backends.IsIgnored(tc.backend), tc.backend)
+	}
+}
@@ -2,12 +2,20 @@ package jobrunaggregatorlib
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
-	"cloud.google.com/go/storage"
 	"github.com/sirupsen/logrus"
-	"google.golang.org/api/iterator"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	prowjobv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+	pkgio "k8s.io/test-infra/prow/io"
 
 	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
 )
@@ -18,19 +26,76 @@ type CIGCSClient interface {
 		matcherFunc ProwJobMatcherFunc) ([]jobrunaggregatorapi.JobRunInfo, error)
 }
 
+const (
+	// DefaultGCSConcurrency is the number of job runs read from GCS at the same time.
+	DefaultGCSConcurrency = 10
+)
+
+// gcsBackoff is used to retry reads from GCS, which fail transiently when reading many job runs.
+var gcsBackoff = wait.Backoff{
+	Steps:    4,
+	Duration: 1 * time.Second,
+	Factor:   2.0,
+	Jitter:   0.1,
+	Cap:      10 * time.Second,
+}
+
+func isRetriableGCSError(err error) bool {
+	return err != nil && !pkgio.IsNotExist(err) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// forEachConcurrently calls fn for every index in [0, count), with at most concurrency calls in
+// flight, and returns the errors of the calls.
+func forEachConcurrently(ctx context.Context, count, concurrency int, fn func(ctx context.Context, i int) error) []error {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	var lock sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	tokens := make(chan struct{}, concurrency)
+	for i := 0; i < count; i++ {
+		if ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+			case tokens <- struct{}{}:
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			wg.Wait()
+			return append(errs, err)
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-tokens
+				wg.Done()
+			}()
+			if err := fn(ctx, i); err != nil {
+				lock.Lock()
+				errs = append(errs, err)
+				lock.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
+
 type ciGCSClient struct {
-	gcsClient     *storage.Client
+	store         jobrunaggregatorapi.ArtifactStore
 	gcsBucketName string
+	// concurrency is the number of job runs read at the same time
+	concurrency int
 }
 
 func (o *ciGCSClient) ReadJobRunFromGCS(ctx context.Context, jobGCSRootLocation, jobName, jobRunID string, logger logrus.FieldLogger) (jobrunaggregatorapi.JobRunInfo, error) {
 	logger.Debugf("reading job run %s/%s", jobGCSRootLocation, jobRunID)
 
-	bkt := o.gcsClient.Bucket(o.gcsBucketName)
 	prowJobPath := fmt.Sprintf("%s/%s/prowjob.json", jobGCSRootLocation, jobRunID)
 	jobRunId := filepath.Base(filepath.Dir(prowJobPath))
 
-	jobRun := jobrunaggregatorapi.NewGCSJobRun(bkt, jobGCSRootLocation, jobName, jobRunId, o.gcsBucketName)
+	jobRun := jobrunaggregatorapi.NewGCSJobRun(o.store, jobGCSRootLocation, jobName, jobRunId, o.gcsBucketName)
 	jobRun.SetGCSProwJobPath(prowJobPath)
 	_, err := jobRun.GetProwJob(ctx)
 	if err != nil {
@@ -46,14 +111,10 @@ func (o *ciGCSClient) ReadRelatedJobRuns(ctx context.Context,
 	matcherFunc ProwJobMatcherFunc) ([]jobrunaggregatorapi.JobRunInfo, error) {
 
 	logrus.Debugf("searching GCS for related job runs in %s between %s and %s", gcsPrefix, startingJobRunID, endingJobRunID)
-	query := &storage.Query{
+	query := jobrunaggregatorapi.ArtifactQuery{
 		// This ends up being the equivalent of:
 		// https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com/gcs/test-platform-results/logs/periodic-ci-openshift-release-master-nightly-4.9-upgrade-from-stable-4.8-e2e-metal-ipi-upgrade/
 		Prefix: fmt.Sprintf("%s/", gcsPrefix),
-
-		// TODO this field is apparently missing from this level of go/storage
-		// Omit owner and ACL fields for performance
-		// Projection: storage.ProjectionNoACL,
 	}
 
 	if startingJobRunID == "" {
@@ -70,46 +131,59 @@ func (o *ciGCSClient) ReadRelatedJobRuns(ctx context.Context,
 	// restrict the query to just one level down
 	query.Delimiter = "/"
 
-	fmt.Printf("  starting from %v, ending at %q\n", query.StartOffset, query.EndOffset)
+	logrus.WithFields(logrus.Fields{"jobName": jobName, "startOffset": query.StartOffset, "endOffset": query.EndOffset}).Info("Listing job runs in GCS.")
 
-	// Returns an iterator which iterates over the bucket query results.
 	// This will list all the folders under the prefix
-	bkt := o.gcsClient.Bucket(o.gcsBucketName)
-	it := bkt.Objects(ctx, query)
+	names, err := o.store.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
 
 	// Find the query results we're the most interested in. In this case, we're interested in files called prowjob.json
-	// so that we only get each jobrun once and we queue them in a channel
-	relatedJobRuns := []jobrunaggregatorapi.JobRunInfo{}
-	for {
-		attrs, err := it.Next()
-		if err == iterator.Done {
-			// we're done adding values, so close the channel
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
-
+	// so that we only get each jobrun once. They are read concurrently once they are all listed.
+	var prowJobPaths []string
+	for _, name := range names {
 		// we are only interested in directories for this pass since we know the file we want
-		if len(attrs.Name) > 0 {
+		if !strings.HasSuffix(name, query.Delimiter) {
 			continue
 		}
 
 		// we only need prowjob.json at this time
-		prowJobPath := fmt.Sprintf("%s%s", attrs.Prefix, "prowjob.json")
-		logrus.Debugf("found %s", attrs.Name)
-		jobRunId := filepath.Base(filepath.Dir(prowJobPath))
-		jobRun := jobrunaggregatorapi.NewGCSJobRun(bkt, gcsPrefix, jobName, jobRunId, o.gcsBucketName)
-		jobRun.SetGCSProwJobPath(prowJobPath)
-
-		prowJob, err := jobRun.GetProwJob(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get prowjob for %q/%q: %w", jobName, jobRunId, err)
+		logrus.WithFields(logrus.Fields{"jobName": jobName, "prefix": name}).Trace("Found job run in GCS.")
+		prowJobPaths = append(prowJobPaths, fmt.Sprintf("%s%s", name, "prowjob.json"))
+	}
+
+	matchedJobRuns := make([]jobrunaggregatorapi.JobRunInfo, len(prowJobPaths))
+	errs := forEachConcurrently(ctx, len(prowJobPaths), o.concurrency, func(ctx context.Context, i int) error {
+		jobRunId := filepath.Base(filepath.Dir(prowJobPaths[i]))
+		jobRun := jobrunaggregatorapi.NewGCSJobRun(o.store, gcsPrefix, jobName, jobRunId, o.gcsBucketName)
+		jobRun.SetGCSProwJobPath(prowJobPaths[i])
+
+		var prowJob *prowjobv1.ProwJob
+		if err := retry.OnError(gcsBackoff, isRetriableGCSError, func() error {
+			var err error
+			prowJob, err = jobRun.GetProwJob(ctx)
+			return err
+		}); err != nil {
+			return fmt.Errorf("failed to get prowjob for %q/%q: %w", jobName, jobRunId, err)
 		}
 
 		if matcherFunc(prowJob) {
+			matchedJobRuns[i] = jobRun
+		}
+		return nil
+	})
+	if len(errs) > 0 {
+		return nil, utilerrors.NewAggregate(errs)
+	}
+
+	// keep the order in which the job runs were listed
+	relatedJobRuns := []jobrunaggregatorapi.JobRunInfo{}
+	for _, jobRun := range matchedJobRuns {
+		if jobRun != nil {
 			relatedJobRuns = append(relatedJobRuns, jobRun)
 		}
 	}
+	RecordJobRunsDiscovered(jobName, len(relatedJobRuns))
 	return relatedJobRuns, nil
 }
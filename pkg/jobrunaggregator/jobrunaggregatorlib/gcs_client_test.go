@@ -0,0 +1,54 @@
+package jobrunaggregatorlib
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEachConcurrently(t *testing.T) {
+	var lock sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	called := make([]bool, 20)
+	errs := forEachConcurrently(context.Background(), len(called), 3, func(ctx context.Context, i int) error {
+		lock.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		called[i] = true
+		lock.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		lock.Lock()
+		inFlight--
+		lock.Unlock()
+		if i%10 == 0 {
+			return fmt.Errorf("failed %d", i)
+		}
+		return nil
+	})
+
+	assert.ElementsMatch(t, []error{fmt.Errorf("failed 0"), fmt.Errorf("failed 10")}, errs)
+	assert.LessOrEqual(t, maxInFlight, 3)
+	for i := range called {
+		assert.True(t, called[i], "index %d was not called", i)
+	}
+}
+
+func TestForEachConcurrentlyCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+	errs := forEachConcurrently(ctx, 5, 1, func(ctx context.Context, i int) error {
+		calls++
+		return nil
+	})
+	assert.Equal(t, 0, calls)
+	assert.Contains(t, errs, context.Canceled)
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"cloud.google.com/go/bigquery"
 	"cloud.google.com/go/storage"
@@ -21,6 +22,15 @@ type GoogleAuthenticationFlags struct {
 	// location of a credential file described by https://cloud.google.com/docs/authentication/production
 	GoogleServiceAccountCredentialFile string
 	GoogleOAuthClientCredentialFile    string
+	// location of the credentials for buckets in S3 or S3-compatible storage, described by
+	// k8s.io/test-infra/prow/io/providers.GetBucket
+	S3CredentialFile string
+	// ArtifactsDir is a local directory laid out like the bucket that is read instead of the
+	// bucket, to analyze downloaded artifacts
+	ArtifactsDir string
+	// GCSCacheDir is a local directory caching the artifacts read from GCS which are read again by
+	// every analysis of a job run
+	GCSCacheDir string
 }
 
 func NewGoogleAuthenticationFlags() *GoogleAuthenticationFlags {
@@ -36,6 +46,9 @@ func NewGoogleAuthenticationFlags() *GoogleAuthenticationFlags {
 func (f *GoogleAuthenticationFlags) BindFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&f.GoogleServiceAccountCredentialFile, "google-service-account-credential-file", f.GoogleServiceAccountCredentialFile, "location of a credential file described by https://cloud.google.com/docs/authentication/production")
 	fs.StringVar(&f.GoogleOAuthClientCredentialFile, "google-oauth-credential-file", f.GoogleOAuthClientCredentialFile, "location of a credential file described by https://developers.google.com/people/quickstart/go, setup from https://cloud.google.com/bigquery/docs/authentication/end-user-installed#client-credentials")
+	fs.StringVar(&f.S3CredentialFile, "s3-credential-file", f.S3CredentialFile, "location of the credentials for artifacts in an s3:// bucket, like {\"region\": \"minio\", \"endpoint\": \"https://minio:9000\", \"s3_force_path_style\": true, \"access_key\": \"key\", \"secret_key\": \"secret\"}. When unset, the credentials are discovered from the environment.")
+	fs.StringVar(&f.GCSCacheDir, "gcs-cache-dir", f.GCSCacheDir, "local directory caching the prowjob.json, finished.json and junit files read from GCS by the generation of the objects, so that analyzing the same job runs again does not download them again.")
+	fs.StringVar(&f.ArtifactsDir, "artifacts-dir", f.ArtifactsDir, "local directory laid out like the bucket, like <dir>/logs/<job>/<job-run-id>/prowjob.json, to read job run artifacts from instead of the bucket.")
 }
 
 func (f *GoogleAuthenticationFlags) Validate() error {
@@ -96,15 +109,41 @@ func (f *GoogleAuthenticationFlags) NewGCSClient(ctx context.Context) (*storage.
 	)
 }
 
+// NewCIGCSClient returns a client reading job runs from the bucket. Buckets are in GCS unless
+// they are given as s3:// URLs. When --artifacts-dir is set, the job runs are read from it instead.
 func (f *GoogleAuthenticationFlags) NewCIGCSClient(ctx context.Context, gcsBucketName string) (CIGCSClient, error) {
+	if len(f.ArtifactsDir) > 0 {
+		return &ciGCSClient{
+			store:         NewLocalArtifactStore(f.ArtifactsDir),
+			gcsBucketName: gcsBucketName,
+			concurrency:   DefaultGCSConcurrency,
+		}, nil
+	}
+	if strings.HasPrefix(gcsBucketName, s3BucketScheme) {
+		store, err := NewS3ArtifactStore(ctx, gcsBucketName, f.S3CredentialFile)
+		if err != nil {
+			return nil, err
+		}
+		return &ciGCSClient{
+			store:         store,
+			gcsBucketName: strings.TrimSuffix(strings.TrimPrefix(gcsBucketName, s3BucketScheme), "/"),
+			concurrency:   DefaultGCSConcurrency,
+		}, nil
+	}
+
 	gcsClient, err := f.NewGCSClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	store := NewGCSArtifactStore(gcsClient.Bucket(gcsBucketName))
+	if len(f.GCSCacheDir) > 0 {
+		store = NewCachingGCSArtifactStore(gcsClient.Bucket(gcsBucketName), filepath.Join(f.GCSCacheDir, gcsBucketName))
+	}
 	return &ciGCSClient{
-		gcsClient:     gcsClient,
+		store:         store,
 		gcsBucketName: gcsBucketName,
+		concurrency:   DefaultGCSConcurrency,
 	}, nil
 }
 
@@ -7,6 +7,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	prowjobv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+	"k8s.io/utils/clock"
 
 	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
 )
@@ -34,13 +35,42 @@ type JobRunLocator interface {
 // It is kept this way to keep changes to the minimum.
 type ProwJobMatcherFunc func(prowJob *prowjobv1.ProwJob) bool
 
+// JobRunLocatorOption configures optional behavior of the job run locators.
+type JobRunLocatorOption func(*analysisJobAggregator)
+
+// WithJobSearchWindow overrides JobSearchWindowStartOffset and JobSearchWindowEndOffset: only
+// job runs started between startTime - startOffset and startTime + endOffset are located.
+func WithJobSearchWindow(startOffset, endOffset time.Duration) JobRunLocatorOption {
+	return func(a *analysisJobAggregator) {
+		a.windowStartOffset = startOffset
+		a.windowEndOffset = endOffset
+	}
+}
+
+// WithMaxJobRunAge bounds the search window so that job runs started more than maxAge ago are
+// not located, regardless of the start time. It avoids walking enormous GCS prefixes for jobs
+// like the ones of long-lived PRs. A zero maxAge does not bound anything.
+func WithMaxJobRunAge(maxAge time.Duration) JobRunLocatorOption {
+	return func(a *analysisJobAggregator) {
+		a.maxJobRunAge = maxAge
+	}
+}
+
 type analysisJobAggregator struct {
 	jobName string
 
 	prowJobMatcher ProwJobMatcherFunc
+	// prowJobLabels are the keys the matchers built by the locator constructors read
+	prowJobLabels ProwJobLabels
 	// startTime is the time when the analysis jobs were started.  We'll look plus or minus a day from here to bound the
 	// bigquery dataset.
 	startTime time.Time
+	// windowStartOffset and windowEndOffset bound the search window around startTime
+	windowStartOffset time.Duration
+	windowEndOffset   time.Duration
+	// maxJobRunAge, when set, moves the start of the search window to at most maxJobRunAge ago
+	maxJobRunAge time.Duration
+	clock        clock.PassiveClock
 
 	ciDataClient  AggregationJobClient
 	ciGCSClient   CIGCSClient
@@ -55,24 +85,48 @@ func NewPayloadAnalysisJobLocator(
 	ciDataClient AggregationJobClient,
 	ciGCSClient CIGCSClient,
 	gcsBucketName string,
-	gcsPrefix string) JobRunLocator {
-
-	return &analysisJobAggregator{
-		jobName:        jobName,
-		prowJobMatcher: prowJobMatcher,
-		startTime:      startTime,
-		ciDataClient:   ciDataClient,
-		ciGCSClient:    ciGCSClient,
-		gcsBucketName:  gcsBucketName,
-		gcsPrefix:      gcsPrefix,
+	gcsPrefix string,
+	opts ...JobRunLocatorOption) JobRunLocator {
+
+	locator := &analysisJobAggregator{
+		jobName:           jobName,
+		prowJobMatcher:    prowJobMatcher,
+		prowJobLabels:     DefaultProwJobLabels(),
+		startTime:         startTime,
+		windowStartOffset: JobSearchWindowStartOffset,
+		windowEndOffset:   JobSearchWindowEndOffset,
+		clock:             clock.RealClock{},
+		ciDataClient:      ciDataClient,
+		ciGCSClient:       ciGCSClient,
+		gcsBucketName:     gcsBucketName,
+		gcsPrefix:         gcsPrefix,
+	}
+	for _, opt := range opts {
+		opt(locator)
+	}
+	return locator
+}
+
+// searchWindow returns the bounds of the window in which job runs are located
+func (a *analysisJobAggregator) searchWindow() (time.Time, time.Time) {
+	start := a.startTime.Add(-1 * a.windowStartOffset)
+	end := a.startTime.Add(a.windowEndOffset)
+	if a.maxJobRunAge > 0 {
+		if oldest := a.clock.Now().Add(-1 * a.maxJobRunAge); start.Before(oldest) {
+			start = oldest
+		}
 	}
+	return start, end
 }
 
 // FindRelatedJobs returns a slice of JobRunInfo which has info contained in GCS buckets
 // used to determine pass/fail.
 func (a *analysisJobAggregator) FindRelatedJobs(ctx context.Context) ([]jobrunaggregatorapi.JobRunInfo, error) {
-	startOfJobRunWindow := a.startTime.Add(-1 * JobSearchWindowStartOffset)
-	endOfJobRunWindow := a.startTime.Add(JobSearchWindowEndOffset)
+	startOfJobRunWindow, endOfJobRunWindow := a.searchWindow()
+	if !endOfJobRunWindow.After(startOfJobRunWindow) {
+		logrus.WithFields(logrus.Fields{"jobName": a.jobName, "start": startOfJobRunWindow, "end": endOfJobRunWindow}).Info("Search window is empty, not locating job runs.")
+		return nil, nil
+	}
 	startingJobRunID, err := a.ciDataClient.GetJobRunForJobNameBeforeTime(ctx, a.jobName, startOfJobRunWindow)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,113 @@
+package jobrunaggregatorlib
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/labels"
+	prowjobv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+)
+
+// ProwJobLabels holds the keys of the labels and annotations read from the prow jobs to locate the job runs.
+// The defaults are the conventions of the OpenShift CI, other Prow deployments override the keys they name
+// differently with WithProwJobLabels.
+type ProwJobLabels struct {
+	// JobNameAnnotation holds the name of the job, it defaults to ProwJobJobNameAnnotation
+	JobNameAnnotation string
+	// JobRunIDLabel holds the ID of the job run, it defaults to prow.k8s.io/build-id
+	JobRunIDLabel string
+	// PayloadTagAnnotation holds the payload tag of the release controller jobs, it defaults to ProwJobPayloadTagAnnotation
+	PayloadTagAnnotation string
+	// AggregationIDLabel holds the aggregation ID of the PR payload jobs, it defaults to ProwJobAggregationIDLabel
+	AggregationIDLabel string
+	// ReleaseJobNameAnnotation holds the name of the periodic a PR payload job runs for, it defaults to releaseJobName
+	ReleaseJobNameAnnotation string
+}
+
+// DefaultProwJobLabels returns the label and annotation keys of the OpenShift CI
+func DefaultProwJobLabels() ProwJobLabels {
+	return ProwJobLabels{
+		JobNameAnnotation:        ProwJobJobNameAnnotation,
+		JobRunIDLabel:            prowJobJobRunIDLabel,
+		PayloadTagAnnotation:     ProwJobPayloadTagAnnotation,
+		AggregationIDLabel:       ProwJobAggregationIDLabel,
+		ReleaseJobNameAnnotation: prowJobReleaseJobNameAnnotation,
+	}
+}
+
+// withDefaults returns the keys with the unset ones defaulted
+func (l ProwJobLabels) withDefaults() ProwJobLabels {
+	defaults := DefaultProwJobLabels()
+	if len(l.JobNameAnnotation) == 0 {
+		l.JobNameAnnotation = defaults.JobNameAnnotation
+	}
+	if len(l.JobRunIDLabel) == 0 {
+		l.JobRunIDLabel = defaults.JobRunIDLabel
+	}
+	if len(l.PayloadTagAnnotation) == 0 {
+		l.PayloadTagAnnotation = defaults.PayloadTagAnnotation
+	}
+	if len(l.AggregationIDLabel) == 0 {
+		l.AggregationIDLabel = defaults.AggregationIDLabel
+	}
+	if len(l.ReleaseJobNameAnnotation) == 0 {
+		l.ReleaseJobNameAnnotation = defaults.ReleaseJobNameAnnotation
+	}
+	return l
+}
+
+// GetPayloadTag gets the payload tag from the prow job
+func (l ProwJobLabels) GetPayloadTag(prowJob *prowjobv1.ProwJob) string {
+	return prowJob.Annotations[l.withDefaults().PayloadTagAnnotation]
+}
+
+// GetJobRunID gets the ID of the job run from the prow job
+func (l ProwJobLabels) GetJobRunID(prowJob *prowjobv1.ProwJob) string {
+	return prowJob.Labels[l.withDefaults().JobRunIDLabel]
+}
+
+// NewProwJobMatcherFuncForReleaseController matches the runs of the job started by the release controller for the payload tag
+func (l ProwJobLabels) NewProwJobMatcherFuncForReleaseController(matchJobName, matchPayloadTag string) ProwJobMatcherFunc {
+	if len(matchPayloadTag) == 0 {
+		return func(*prowjobv1.ProwJob) bool { return false }
+	}
+	l = l.withDefaults()
+	return NewProwJobMatcherFunc(ProwJobMatcherConfig{
+		JobName:            matchJobName,
+		JobNameAnnotation:  l.JobNameAnnotation,
+		JobRunIDLabel:      l.JobRunIDLabel,
+		AnnotationSelector: labels.SelectorFromValidatedSet(labels.Set{l.PayloadTagAnnotation: matchPayloadTag}),
+		Logger:             logrus.WithField("payloadTag", matchPayloadTag),
+	})
+}
+
+// NewProwJobMatcherFuncForPR matches the runs of the job started for the payload of a PR with the aggregation ID
+func (l ProwJobLabels) NewProwJobMatcherFuncForPR(matchJobName, matchID string) ProwJobMatcherFunc {
+	if len(matchID) == 0 {
+		return func(*prowjobv1.ProwJob) bool { return false }
+	}
+	l = l.withDefaults()
+	return NewProwJobMatcherFunc(ProwJobMatcherConfig{
+		JobName:           matchJobName,
+		JobNameAnnotation: l.ReleaseJobNameAnnotation,
+		JobRunIDLabel:     l.JobRunIDLabel,
+		LabelSelector:     labels.SelectorFromValidatedSet(labels.Set{l.AggregationIDLabel: matchID}),
+		Logger:            logrus.WithFields(logrus.Fields{"matchLabel": l.AggregationIDLabel, "matchID": matchID}),
+	})
+}
+
+// WithProwJobLabels overrides the keys of the labels and annotations the matchers of the job run locators read.
+// Unset keys keep their default.
+func WithProwJobLabels(prowJobLabels ProwJobLabels) JobRunLocatorOption {
+	return func(a *analysisJobAggregator) {
+		a.prowJobLabels = prowJobLabels.withDefaults()
+	}
+}
+
+// prowJobLabelsFromOptions returns the keys the options configure, for the locators building their matcher
+func prowJobLabelsFromOptions(opts []JobRunLocatorOption) ProwJobLabels {
+	locator := &analysisJobAggregator{}
+	for _, opt := range opts {
+		opt(locator)
+	}
+	return locator.prowJobLabels.withDefaults()
+}
@@ -0,0 +1,97 @@
+package jobrunaggregatorlib
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	prowv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
+)
+
+func TestPayloadInvocationIDLabelIsAggregationIDLabel(t *testing.T) {
+	// the PR payload controller labels the prow jobs with the aggregation id only, the test case analyzer must match it
+	assert.Equal(t, ProwJobAggregationIDLabel, ProwJobPayloadInvocationIDLabel)
+}
+
+func TestProwJobLabelsWithDefaults(t *testing.T) {
+	assert.Equal(t, DefaultProwJobLabels(), ProwJobLabels{}.withDefaults())
+
+	expected := DefaultProwJobLabels()
+	expected.AggregationIDLabel = "example.com/aggregation"
+	assert.Equal(t, expected, ProwJobLabels{AggregationIDLabel: "example.com/aggregation"}.withDefaults())
+}
+
+func TestProwJobLabelsMatchers(t *testing.T) {
+	custom := ProwJobLabels{
+		JobNameAnnotation:        "example.com/job",
+		PayloadTagAnnotation:     "example.com/tag",
+		AggregationIDLabel:       "example.com/aggregation",
+		ReleaseJobNameAnnotation: "example.com/release-job",
+	}
+
+	releaseJob := prowJobWith(nil, map[string]string{"example.com/job": "job", "example.com/tag": "tag"})
+	assert.True(t, custom.NewProwJobMatcherFuncForReleaseController("job", "tag")(releaseJob))
+	assert.False(t, DefaultProwJobLabels().NewProwJobMatcherFuncForReleaseController("job", "tag")(releaseJob))
+	assert.Equal(t, "tag", custom.GetPayloadTag(releaseJob))
+
+	prJob := prowJobWith(map[string]string{"example.com/aggregation": "id"}, map[string]string{"example.com/release-job": "job"})
+	assert.True(t, custom.NewProwJobMatcherFuncForPR("job", "id")(prJob))
+	assert.False(t, custom.NewProwJobMatcherFuncForPR("job", "other")(prJob))
+	assert.False(t, DefaultProwJobLabels().NewProwJobMatcherFuncForPR("job", "id")(prJob))
+}
+
+func TestWithProwJobLabels(t *testing.T) {
+	startTime := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	custom := ProwJobLabels{JobNameAnnotation: "example.com/job", PayloadTagAnnotation: "example.com/tag"}
+	tests := []struct {
+		name    string
+		opts    []JobRunLocatorOption
+		prowJob *prowv1.ProwJob
+		matches bool
+	}{
+		{
+			name:    "default keys",
+			prowJob: prowJobWith(nil, map[string]string{ProwJobJobNameAnnotation: "job", ProwJobPayloadTagAnnotation: "tag"}),
+			matches: true,
+		},
+		{
+			name:    "configured keys",
+			opts:    []JobRunLocatorOption{WithProwJobLabels(custom)},
+			prowJob: prowJobWith(nil, map[string]string{"example.com/job": "job", "example.com/tag": "tag"}),
+			matches: true,
+		},
+		{
+			name:    "configured keys ignore the default ones",
+			opts:    []JobRunLocatorOption{WithProwJobLabels(custom)},
+			prowJob: prowJobWith(nil, map[string]string{ProwJobJobNameAnnotation: "job", ProwJobPayloadTagAnnotation: "tag"}),
+			matches: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDataClient := NewMockCIDataClient(mockCtrl)
+			mockGCSClient := NewMockCIGCSClient(mockCtrl)
+			mockDataClient.EXPECT().GetJobRunForJobNameBeforeTime(gomock.Any(), "job", gomock.Any()).Return("1000", nil).Times(1)
+			mockDataClient.EXPECT().GetJobRunForJobNameAfterTime(gomock.Any(), "job", gomock.Any()).Return("2000", nil).Times(1)
+			var matches bool
+			mockGCSClient.EXPECT().ReadRelatedJobRuns(gomock.Any(), "job", "logs/job", "1000", "2000", gomock.Any()).
+				DoAndReturn(func(_ context.Context, _, _, _, _ string, matcher ProwJobMatcherFunc) ([]jobrunaggregatorapi.JobRunInfo, error) {
+					matches = matcher(tc.prowJob)
+					return nil, nil
+				}).Times(1)
+
+			locator := NewPayloadAnalysisJobLocatorForReleaseController("job", "tag", startTime, mockDataClient, mockGCSClient, "bucket", tc.opts...)
+			_, err := locator.FindRelatedJobs(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, tc.matches, matches)
+		})
+	}
+}
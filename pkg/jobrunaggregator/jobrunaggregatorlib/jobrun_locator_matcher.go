@@ -0,0 +1,79 @@
+package jobrunaggregatorlib
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/labels"
+	prowjobv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+)
+
+// ProwJobMatcherConfig describes the prow jobs a locator is interested in. It allows building
+// matchers for different controllers (release controller, PR payloads, rehearsals, ...) without
+// a dedicated matcher function for each of them.
+type ProwJobMatcherConfig struct {
+	// JobName is the name of the job the prow jobs must be runs of.
+	JobName string
+	// JobNameAnnotation is the annotation holding the job name. It defaults to ProwJobJobNameAnnotation.
+	JobNameAnnotation string
+	// JobRunIDLabel is the label holding the job run ID, it is only logged. It defaults to prow.k8s.io/build-id.
+	JobRunIDLabel string
+	// LabelSelector is matched against the labels of the prow jobs. It supports multiple
+	// equality and set-based requirements. A nil selector matches everything.
+	LabelSelector labels.Selector
+	// AnnotationSelector is matched against the annotations of the prow jobs, with the same
+	// semantics as LabelSelector.
+	AnnotationSelector labels.Selector
+	// Logger is used to trace the prow jobs that are checked. It defaults to the standard logger.
+	Logger *logrus.Entry
+}
+
+// NewProwJobMatcherConfig builds a ProwJobMatcherConfig from selectors in their string form,
+// e.g. "release.openshift.io/aggregation-id=abc,ci.openshift.io/rehearse in (1,2)".
+func NewProwJobMatcherConfig(jobName, labelSelector, annotationSelector string) (ProwJobMatcherConfig, error) {
+	config := ProwJobMatcherConfig{JobName: jobName}
+	var err error
+	if config.LabelSelector, err = labels.Parse(labelSelector); err != nil {
+		return ProwJobMatcherConfig{}, fmt.Errorf("invalid label selector %q: %w", labelSelector, err)
+	}
+	if config.AnnotationSelector, err = labels.Parse(annotationSelector); err != nil {
+		return ProwJobMatcherConfig{}, fmt.Errorf("invalid annotation selector %q: %w", annotationSelector, err)
+	}
+	return config, nil
+}
+
+// NewProwJobMatcherFunc returns a matcher for the prow jobs described by the config.
+func NewProwJobMatcherFunc(config ProwJobMatcherConfig) ProwJobMatcherFunc {
+	jobNameAnnotation := config.JobNameAnnotation
+	if len(jobNameAnnotation) == 0 {
+		jobNameAnnotation = ProwJobJobNameAnnotation
+	}
+	jobRunIDLabel := config.JobRunIDLabel
+	if len(jobRunIDLabel) == 0 {
+		jobRunIDLabel = prowJobJobRunIDLabel
+	}
+	labelSelector, annotationSelector := config.LabelSelector, config.AnnotationSelector
+	if labelSelector == nil {
+		labelSelector = labels.Everything()
+	}
+	if annotationSelector == nil {
+		annotationSelector = labels.Everything()
+	}
+	logger := config.Logger
+	if logger == nil {
+		logger = logrus.NewEntry(logrus.StandardLogger())
+	}
+	return func(prowJob *prowjobv1.ProwJob) bool {
+		if jobName, ok := prowJob.Annotations[jobNameAnnotation]; !ok || jobName != config.JobName {
+			return false
+		}
+		matches := labelSelector.Matches(labels.Set(prowJob.Labels)) && annotationSelector.Matches(labels.Set(prowJob.Annotations))
+		logger.WithFields(logrus.Fields{
+			"jobName":  prowJob.Annotations[jobNameAnnotation],
+			"jobRunID": prowJob.Labels[jobRunIDLabel],
+			"matches":  matches,
+		}).Debug("Checked prow job for match.")
+		return matches
+	}
+}
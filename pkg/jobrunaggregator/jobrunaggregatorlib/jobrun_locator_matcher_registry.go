@@ -0,0 +1,168 @@
+package jobrunaggregatorlib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ProwJobMatcherPayloadTag matches the prow jobs of the release controller for a payload tag
+	ProwJobMatcherPayloadTag = "payload-tag"
+	// ProwJobMatcherPerPR matches the prow jobs of a PR payload by their aggregation id label
+	ProwJobMatcherPerPR = "per-pr"
+	// ProwJobMatcherLabelSelector matches the prow jobs by arbitrary label and annotation selectors
+	ProwJobMatcherLabelSelector = "label-selector"
+)
+
+// ProwJobMatcherParam describes a parameter a registered matcher is configured with
+type ProwJobMatcherParam struct {
+	Name        string
+	Description string
+	Required    bool
+	// Default is used when the parameter is not set
+	Default string
+}
+
+// ProwJobMatcherRegistration is a named matcher. New callers register the matcher of their prow jobs
+// instead of adding a job run locator constructor.
+type ProwJobMatcherRegistration struct {
+	Name        string
+	Description string
+	// Params is the schema of the parameters passed to New, the parameters are validated against it
+	Params []ProwJobMatcherParam
+	// New builds the matcher for the runs of the job from the validated parameters
+	New func(jobName string, params map[string]string) (ProwJobMatcherFunc, error)
+}
+
+var (
+	prowJobMatchersLock sync.RWMutex
+	prowJobMatchers     = map[string]ProwJobMatcherRegistration{}
+)
+
+// RegisterProwJobMatcher registers a matcher under its name. Registering a name twice is an error.
+func RegisterProwJobMatcher(registration ProwJobMatcherRegistration) error {
+	if len(registration.Name) == 0 || registration.New == nil {
+		return fmt.Errorf("a prow job matcher must have a name and a constructor")
+	}
+	prowJobMatchersLock.Lock()
+	defer prowJobMatchersLock.Unlock()
+	if _, ok := prowJobMatchers[registration.Name]; ok {
+		return fmt.Errorf("prow job matcher %q is already registered", registration.Name)
+	}
+	prowJobMatchers[registration.Name] = registration
+	return nil
+}
+
+// LookupProwJobMatcher returns the matcher registered under the name
+func LookupProwJobMatcher(name string) (ProwJobMatcherRegistration, bool) {
+	prowJobMatchersLock.RLock()
+	defer prowJobMatchersLock.RUnlock()
+	registration, ok := prowJobMatchers[name]
+	return registration, ok
+}
+
+// RegisteredProwJobMatchers lists the names of the registered matchers, sorted
+func RegisteredProwJobMatchers() []string {
+	prowJobMatchersLock.RLock()
+	defer prowJobMatchersLock.RUnlock()
+	var names []string
+	for name := range prowJobMatchers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewRegisteredProwJobMatcherFunc builds the matcher registered under the name for the runs of the job,
+// after defaulting and validating the parameters against the schema of the matcher.
+func NewRegisteredProwJobMatcherFunc(name, jobName string, params map[string]string) (ProwJobMatcherFunc, error) {
+	registration, ok := LookupProwJobMatcher(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown prow job matcher %q, valid values are: %s", name, strings.Join(RegisteredProwJobMatchers(), ", "))
+	}
+	resolved := map[string]string{}
+	known := map[string]bool{}
+	for _, param := range registration.Params {
+		known[param.Name] = true
+		value, ok := params[param.Name]
+		if !ok || len(value) == 0 {
+			value = param.Default
+		}
+		if len(value) == 0 {
+			if param.Required {
+				return nil, fmt.Errorf("prow job matcher %q requires the parameter %q: %s", name, param.Name, param.Description)
+			}
+			continue
+		}
+		resolved[param.Name] = value
+	}
+	for param := range params {
+		if !known[param] {
+			return nil, fmt.Errorf("prow job matcher %q has no parameter %q", name, param)
+		}
+	}
+	return registration.New(jobName, resolved)
+}
+
+func init() {
+	for _, registration := range []ProwJobMatcherRegistration{
+		{
+			Name:        ProwJobMatcherPayloadTag,
+			Description: "the runs of the job started by the release controller for a payload",
+			Params: []ProwJobMatcherParam{
+				{Name: "payload-tag", Description: "the payload tag, like 4.9.0-0.ci-2021-07-19-185802", Required: true},
+				{Name: "payload-tag-annotation", Description: "the annotation holding the payload tag", Default: ProwJobPayloadTagAnnotation},
+				{Name: "job-name-annotation", Description: "the annotation holding the name of the job", Default: ProwJobJobNameAnnotation},
+			},
+			New: func(jobName string, params map[string]string) (ProwJobMatcherFunc, error) {
+				prowJobLabels := ProwJobLabels{
+					PayloadTagAnnotation: params["payload-tag-annotation"],
+					JobNameAnnotation:    params["job-name-annotation"],
+				}
+				return prowJobLabels.NewProwJobMatcherFuncForReleaseController(jobName, params["payload-tag"]), nil
+			},
+		},
+		{
+			Name:        ProwJobMatcherPerPR,
+			Description: "the runs of the job started for the payload of a PR",
+			Params: []ProwJobMatcherParam{
+				{Name: "aggregation-id", Description: "the aggregation id the prow jobs are labeled with", Required: true},
+				{Name: "label", Description: "the label holding the aggregation id", Default: ProwJobAggregationIDLabel},
+				{Name: "job-name-annotation", Description: "the annotation holding the name of the periodic the job runs for", Default: prowJobReleaseJobNameAnnotation},
+			},
+			New: func(jobName string, params map[string]string) (ProwJobMatcherFunc, error) {
+				prowJobLabels := ProwJobLabels{
+					AggregationIDLabel:       params["label"],
+					ReleaseJobNameAnnotation: params["job-name-annotation"],
+				}
+				return prowJobLabels.NewProwJobMatcherFuncForPR(jobName, params["aggregation-id"]), nil
+			},
+		},
+		{
+			Name:        ProwJobMatcherLabelSelector,
+			Description: "the runs of the job selected by their labels and annotations",
+			Params: []ProwJobMatcherParam{
+				{Name: "label-selector", Description: "the selector the labels of the prow jobs must match, like ci.openshift.io/rehearse=1"},
+				{Name: "annotation-selector", Description: "the selector the annotations of the prow jobs must match"},
+				{Name: "job-name-annotation", Description: "the annotation holding the name of the job", Default: ProwJobJobNameAnnotation},
+			},
+			New: func(jobName string, params map[string]string) (ProwJobMatcherFunc, error) {
+				config, err := NewProwJobMatcherConfig(jobName, params["label-selector"], params["annotation-selector"])
+				if err != nil {
+					return nil, err
+				}
+				config.JobNameAnnotation = params["job-name-annotation"]
+				config.Logger = logrus.WithFields(logrus.Fields{"labelSelector": params["label-selector"], "annotationSelector": params["annotation-selector"]})
+				return NewProwJobMatcherFunc(config), nil
+			},
+		},
+	} {
+		if err := RegisterProwJobMatcher(registration); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package jobrunaggregatorlib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	prowv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+)
+
+func TestNewRegisteredProwJobMatcherFunc(t *testing.T) {
+	releaseJob := prowJobWith(nil, map[string]string{ProwJobJobNameAnnotation: "job", ProwJobPayloadTagAnnotation: "4.14.0-0.nightly"})
+	prJob := prowJobWith(map[string]string{ProwJobAggregationIDLabel: "id"}, map[string]string{prowJobReleaseJobNameAnnotation: "job"})
+	rehearsalJob := prowJobWith(map[string]string{"ci.openshift.io/rehearse": "1"}, map[string]string{ProwJobJobNameAnnotation: "job"})
+
+	tests := []struct {
+		name          string
+		matcher       string
+		params        map[string]string
+		expectedError string
+		matches       []*prowv1.ProwJob
+		doesNotMatch  []*prowv1.ProwJob
+	}{
+		{
+			name:         "payload tag",
+			matcher:      ProwJobMatcherPayloadTag,
+			params:       map[string]string{"payload-tag": "4.14.0-0.nightly"},
+			matches:      []*prowv1.ProwJob{releaseJob},
+			doesNotMatch: []*prowv1.ProwJob{prJob, rehearsalJob},
+		},
+		{
+			name:         "per PR with the default label",
+			matcher:      ProwJobMatcherPerPR,
+			params:       map[string]string{"aggregation-id": "id"},
+			matches:      []*prowv1.ProwJob{prJob},
+			doesNotMatch: []*prowv1.ProwJob{releaseJob, rehearsalJob},
+		},
+		{
+			name:         "per PR with another label",
+			matcher:      ProwJobMatcherPerPR,
+			params:       map[string]string{"aggregation-id": "id", "label": "example.com/aggregation-id"},
+			doesNotMatch: []*prowv1.ProwJob{prJob},
+		},
+		{
+			name:         "label selector",
+			matcher:      ProwJobMatcherLabelSelector,
+			params:       map[string]string{"label-selector": "ci.openshift.io/rehearse=1"},
+			matches:      []*prowv1.ProwJob{rehearsalJob},
+			doesNotMatch: []*prowv1.ProwJob{releaseJob, prJob},
+		},
+		{
+			name:          "unknown matcher",
+			matcher:       "unknown",
+			expectedError: `unknown prow job matcher "unknown", valid values are: label-selector, payload-tag, per-pr`,
+		},
+		{
+			name:          "missing required parameter",
+			matcher:       ProwJobMatcherPayloadTag,
+			expectedError: `prow job matcher "payload-tag" requires the parameter "payload-tag": the payload tag, like 4.9.0-0.ci-2021-07-19-185802`,
+		},
+		{
+			name:          "unknown parameter",
+			matcher:       ProwJobMatcherPayloadTag,
+			params:        map[string]string{"payload-tag": "4.14.0-0.nightly", "aggregation-id": "id"},
+			expectedError: `prow job matcher "payload-tag" has no parameter "aggregation-id"`,
+		},
+		{
+			name:          "invalid selector",
+			matcher:       ProwJobMatcherLabelSelector,
+			params:        map[string]string{"label-selector": "a in (b"},
+			expectedError: `invalid label selector "a in (b": unable to parse requirement: found '', expected: ',' or ')'`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			matcher, err := NewRegisteredProwJobMatcherFunc(tc.matcher, "job", tc.params)
+			if len(tc.expectedError) > 0 {
+				if err == nil {
+					t.Fatalf("expected error %q, got none", tc.expectedError)
+				}
+				assert.Equal(t, tc.expectedError, err.Error())
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, prowJob := range tc.matches {
+				assert.True(t, matcher(prowJob), "expected a match for %v", prowJob.ObjectMeta)
+			}
+			for _, prowJob := range tc.doesNotMatch {
+				assert.False(t, matcher(prowJob), "expected no match for %v", prowJob.ObjectMeta)
+			}
+		})
+	}
+}
+
+func TestRegisterProwJobMatcher(t *testing.T) {
+	matchAll := func(string, map[string]string) (ProwJobMatcherFunc, error) {
+		return func(*prowv1.ProwJob) bool { return true }, nil
+	}
+	if err := RegisterProwJobMatcher(ProwJobMatcherRegistration{Name: ProwJobMatcherPayloadTag, New: matchAll}); err == nil {
+		t.Error("expected registering a name twice to fail")
+	}
+	if err := RegisterProwJobMatcher(ProwJobMatcherRegistration{Name: "no-constructor"}); err == nil {
+		t.Error("expected registering a matcher without constructor to fail")
+	}
+}
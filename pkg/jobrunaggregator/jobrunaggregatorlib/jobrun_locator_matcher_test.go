@@ -0,0 +1,91 @@
+package jobrunaggregatorlib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	prowv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+)
+
+func prowJobWith(labels, annotations map[string]string) *prowv1.ProwJob {
+	return &prowv1.ProwJob{ObjectMeta: metav1.ObjectMeta{Labels: labels, Annotations: annotations}}
+}
+
+func TestNewProwJobMatcherFunc(t *testing.T) {
+	tests := []struct {
+		name               string
+		jobNameAnnotation  string
+		labelSelector      string
+		annotationSelector string
+		prowJob            *prowv1.ProwJob
+		result             bool
+	}{
+		{
+			name:    "job name matches without selectors",
+			prowJob: prowJobWith(nil, map[string]string{ProwJobJobNameAnnotation: "job"}),
+			result:  true,
+		},
+		{
+			name:    "job name differs",
+			prowJob: prowJobWith(nil, map[string]string{ProwJobJobNameAnnotation: "other"}),
+			result:  false,
+		},
+		{
+			name:              "job name is read from the configured annotation",
+			jobNameAnnotation: prowJobReleaseJobNameAnnotation,
+			prowJob:           prowJobWith(nil, map[string]string{ProwJobJobNameAnnotation: "generated", prowJobReleaseJobNameAnnotation: "job"}),
+			result:            true,
+		},
+		{
+			name:          "all label requirements match",
+			labelSelector: "a=1,b in (2,3),!c",
+			prowJob:       prowJobWith(map[string]string{"a": "1", "b": "3"}, map[string]string{ProwJobJobNameAnnotation: "job"}),
+			result:        true,
+		},
+		{
+			name:          "one label requirement does not match",
+			labelSelector: "a=1,b in (2,3),!c",
+			prowJob:       prowJobWith(map[string]string{"a": "1", "b": "3", "c": "4"}, map[string]string{ProwJobJobNameAnnotation: "job"}),
+			result:        false,
+		},
+		{
+			name:               "annotations match",
+			annotationSelector: ProwJobPayloadTagAnnotation + "=4.14.0-0.nightly",
+			prowJob:            prowJobWith(nil, map[string]string{ProwJobJobNameAnnotation: "job", ProwJobPayloadTagAnnotation: "4.14.0-0.nightly"}),
+			result:             true,
+		},
+		{
+			name:               "annotations do not match",
+			annotationSelector: ProwJobPayloadTagAnnotation + "=4.14.0-0.nightly",
+			prowJob:            prowJobWith(nil, map[string]string{ProwJobJobNameAnnotation: "job", ProwJobPayloadTagAnnotation: "4.13.0-0.nightly"}),
+			result:             false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := NewProwJobMatcherConfig("job", tc.labelSelector, tc.annotationSelector)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			config.JobNameAnnotation = tc.jobNameAnnotation
+			assert.Equal(t, tc.result, NewProwJobMatcherFunc(config)(tc.prowJob))
+		})
+	}
+}
+
+func TestNewProwJobMatcherFuncForPR(t *testing.T) {
+	annotations := map[string]string{prowJobReleaseJobNameAnnotation: "job"}
+	matched := prowJobWith(map[string]string{ProwJobAggregationIDLabel: "id"}, annotations)
+	assert.True(t, NewProwJobMatcherFuncForPR("job", "id", ProwJobAggregationIDLabel)(matched))
+	assert.False(t, NewProwJobMatcherFuncForPR("job", "other", ProwJobAggregationIDLabel)(matched))
+	assert.False(t, NewProwJobMatcherFuncForPR("job", "", ProwJobAggregationIDLabel)(prowJobWith(map[string]string{ProwJobAggregationIDLabel: ""}, annotations)))
+}
+
+func TestNewProwJobMatcherFuncForReleaseController(t *testing.T) {
+	matched := prowJobWith(nil, map[string]string{ProwJobJobNameAnnotation: "job", ProwJobPayloadTagAnnotation: "tag"})
+	assert.True(t, NewProwJobMatcherFuncForReleaseController("job", "tag")(matched))
+	assert.False(t, NewProwJobMatcherFuncForReleaseController("other", "tag")(matched))
+	assert.False(t, NewProwJobMatcherFuncForReleaseController("job", "")(prowJobWith(nil, map[string]string{ProwJobJobNameAnnotation: "job"})))
+}
@@ -2,17 +2,16 @@ package jobrunaggregatorlib
 
 import (
 	"time"
-
-	"github.com/sirupsen/logrus"
-
-	prowjobv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
 )
 
 const (
 	// ProwJobAggregationIDLabel is the name of the label for the aggregation id in prow job
 	ProwJobAggregationIDLabel = "release.openshift.io/aggregation-id"
-	// ProwJobPayloadInvocationIDLabel is the name of the label for the payload invocation id in prow job
-	ProwJobPayloadInvocationIDLabel = "release.openshift.io/aggregation-id"
+	// ProwJobPayloadInvocationIDLabel is the name of the label for the payload invocation id in prow job.
+	// The payload invocation id of the PR payload jobs is their aggregation id, there is no label of its own.
+	//
+	// Deprecated: use ProwJobAggregationIDLabel, or ProwJobLabels.AggregationIDLabel when the label is configured.
+	ProwJobPayloadInvocationIDLabel = ProwJobAggregationIDLabel
 	// prowJobReleaseJobNameAnnotation refers to the original periodic job name for PR based payload runs.
 	// This is a special case for the PR invoked payload jobs where ProwJobJobNameAnnotation annotation
 	// refers to a uniquely generated name per job run. Thus, prowJobReleaseJobNameAnnotation is used to
@@ -21,22 +20,7 @@ const (
 )
 
 func NewProwJobMatcherFuncForPR(matchJobName, matchID, matchLabel string) ProwJobMatcherFunc {
-	return func(prowJob *prowjobv1.ProwJob) bool {
-		id := prowJob.Labels[matchLabel]
-		jobName := prowJob.Annotations[ProwJobJobNameAnnotation]
-		jobRunId := prowJob.Labels[prowJobJobRunIDLabel]
-		if releaseJobName, ok := prowJob.Annotations[prowJobReleaseJobNameAnnotation]; ok {
-			if releaseJobName != matchJobName {
-				return false
-			}
-		} else {
-			return false
-		}
-		logrus.Infof("  checking %v/%v for matchID match: looking for %q found %q.", jobName, jobRunId, matchID, id)
-		idMatches := len(matchID) > 0 && id == matchID
-
-		return idMatches
-	}
+	return ProwJobLabels{AggregationIDLabel: matchLabel}.NewProwJobMatcherFuncForPR(matchJobName, matchID)
 }
 
 func NewPayloadAnalysisJobLocatorForPR(
@@ -45,15 +29,21 @@ func NewPayloadAnalysisJobLocatorForPR(
 	ciDataClient AggregationJobClient,
 	ciGCSClient CIGCSClient,
 	gcsBucketName string,
-	gcsPrefix string) JobRunLocator {
+	gcsPrefix string,
+	opts ...JobRunLocatorOption) JobRunLocator {
 
+	prowJobLabels := prowJobLabelsFromOptions(opts)
+	if len(matchLabel) > 0 {
+		prowJobLabels.AggregationIDLabel = matchLabel
+	}
 	return NewPayloadAnalysisJobLocator(
 		jobName,
-		NewProwJobMatcherFuncForPR(jobName, matchID, matchLabel),
+		prowJobLabels.NewProwJobMatcherFuncForPR(jobName, matchID),
 		startTime,
 		ciDataClient,
 		ciGCSClient,
 		gcsBucketName,
 		gcsPrefix,
+		opts...,
 	)
 }
@@ -3,8 +3,6 @@ package jobrunaggregatorlib
 import (
 	"time"
 
-	"github.com/sirupsen/logrus"
-
 	prowjobv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
 )
 
@@ -15,22 +13,11 @@ const (
 
 // GetPayloadTagFromProwJob gets the payload tag from prow jobs.
 func GetPayloadTagFromProwJob(prowJob *prowjobv1.ProwJob) string {
-	return prowJob.Annotations[ProwJobPayloadTagAnnotation]
+	return DefaultProwJobLabels().GetPayloadTag(prowJob)
 }
 
 func NewProwJobMatcherFuncForReleaseController(matchJobName, matchPayloadTag string) ProwJobMatcherFunc {
-	return func(prowJob *prowjobv1.ProwJob) bool {
-		payloadTag := GetPayloadTagFromProwJob(prowJob)
-		jobName := prowJob.Annotations[ProwJobJobNameAnnotation]
-		jobRunId := prowJob.Labels[prowJobJobRunIDLabel]
-		if jobName != matchJobName {
-			return false
-		}
-		logrus.Infof("checking %v/%v for payloadtag match: looking for %q found %q", jobName, jobRunId, matchPayloadTag, payloadTag)
-		payloadTagMatches := len(matchPayloadTag) > 0 && payloadTag == matchPayloadTag
-
-		return payloadTagMatches
-	}
+	return DefaultProwJobLabels().NewProwJobMatcherFuncForReleaseController(matchJobName, matchPayloadTag)
 }
 
 func NewPayloadAnalysisJobLocatorForReleaseController(
@@ -38,15 +25,17 @@ func NewPayloadAnalysisJobLocatorForReleaseController(
 	startTime time.Time,
 	ciDataClient AggregationJobClient,
 	ciGCSClient CIGCSClient,
-	gcsBucketName string) JobRunLocator {
+	gcsBucketName string,
+	opts ...JobRunLocatorOption) JobRunLocator {
 
 	return NewPayloadAnalysisJobLocator(
 		jobName,
-		NewProwJobMatcherFuncForReleaseController(jobName, payloadTag),
+		prowJobLabelsFromOptions(opts).NewProwJobMatcherFuncForReleaseController(jobName, payloadTag),
 		startTime,
 		ciDataClient,
 		ciGCSClient,
 		gcsBucketName,
 		"logs/"+jobName,
+		opts...,
 	)
 }
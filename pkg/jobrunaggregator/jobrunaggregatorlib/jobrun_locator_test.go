@@ -0,0 +1,82 @@
+package jobrunaggregatorlib
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	fakeclock "k8s.io/utils/clock/testing"
+)
+
+func TestFindRelatedJobsSearchWindow(t *testing.T) {
+	startTime := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		now           time.Time
+		opts          []JobRunLocatorOption
+		expectedStart time.Time
+		expectedEnd   time.Time
+		expectsSearch bool
+	}{
+		{
+			name:          "default window",
+			now:           startTime,
+			expectedStart: startTime.Add(-1 * JobSearchWindowStartOffset),
+			expectedEnd:   startTime.Add(JobSearchWindowEndOffset),
+			expectsSearch: true,
+		},
+		{
+			name:          "configured window",
+			now:           startTime,
+			opts:          []JobRunLocatorOption{WithJobSearchWindow(10*time.Minute, 20*time.Minute)},
+			expectedStart: startTime.Add(-10 * time.Minute),
+			expectedEnd:   startTime.Add(20 * time.Minute),
+			expectsSearch: true,
+		},
+		{
+			name:          "max age bounds the start of the window",
+			now:           startTime.Add(time.Hour),
+			opts:          []JobRunLocatorOption{WithMaxJobRunAge(90 * time.Minute)},
+			expectedStart: startTime.Add(-30 * time.Minute),
+			expectedEnd:   startTime.Add(JobSearchWindowEndOffset),
+			expectsSearch: true,
+		},
+		{
+			name:          "max age older than the window changes nothing",
+			now:           startTime,
+			opts:          []JobRunLocatorOption{WithMaxJobRunAge(24 * time.Hour)},
+			expectedStart: startTime.Add(-1 * JobSearchWindowStartOffset),
+			expectedEnd:   startTime.Add(JobSearchWindowEndOffset),
+			expectsSearch: true,
+		},
+		{
+			name:          "window entirely older than max age is not searched",
+			now:           startTime.Add(24 * time.Hour),
+			opts:          []JobRunLocatorOption{WithMaxJobRunAge(time.Hour)},
+			expectsSearch: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDataClient := NewMockCIDataClient(mockCtrl)
+			mockGCSClient := NewMockCIGCSClient(mockCtrl)
+			if tc.expectsSearch {
+				mockDataClient.EXPECT().GetJobRunForJobNameBeforeTime(gomock.Any(), "job", tc.expectedStart).Return("1000", nil).Times(1)
+				mockDataClient.EXPECT().GetJobRunForJobNameAfterTime(gomock.Any(), "job", tc.expectedEnd).Return("2000", nil).Times(1)
+				mockGCSClient.EXPECT().ReadRelatedJobRuns(gomock.Any(), "job", "logs/job", "1000", "2000", gomock.Any()).Return(nil, nil).Times(1)
+			}
+
+			locator := NewPayloadAnalysisJobLocatorForReleaseController("job", "tag", startTime, mockDataClient, mockGCSClient, "bucket", tc.opts...)
+			locator.(*analysisJobAggregator).clock = fakeclock.NewFakePassiveClock(tc.now)
+			jobRuns, err := locator.FindRelatedJobs(context.Background())
+			assert.NoError(t, err)
+			assert.Empty(t, jobRuns)
+		})
+	}
+}
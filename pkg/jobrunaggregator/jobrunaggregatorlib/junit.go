@@ -8,6 +8,8 @@ type TestCaseDetails struct {
 	TestSuiteName string
 	// Summary is filled in during the pass/fail calculation
 	Summary string
+	// Baseline is filled in during the pass/fail calculation when the test is compared to its history
+	Baseline *TestCaseBaseline `yaml:",omitempty"`
 
 	Passes   []TestCasePass
 	Failures []TestCaseFailure
@@ -15,6 +17,17 @@ type TestCaseDetails struct {
 	//NeverExecuted []TestCaseNeverExecuted
 }
 
+// TestCaseBaseline is the historical pass rate a test is compared to
+type TestCaseBaseline struct {
+	// PassPercentage is the percentage of the historical job runs the test passed or flaked in
+	PassPercentage int
+	// Passes and Failures are the historical counts, they are unset when the pass percentage is assumed
+	Passes   int `yaml:",omitempty"`
+	Failures int `yaml:",omitempty"`
+	// RequiredPasses is the number of passes the test needs to not be significantly worse than its history
+	RequiredPasses int
+}
+
 type TestCasePass struct {
 	JobRunID       string
 	HumanURL       string
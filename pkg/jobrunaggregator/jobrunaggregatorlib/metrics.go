@@ -0,0 +1,90 @@
+package jobrunaggregatorlib
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "job_run_aggregator"
+
+var (
+	jobRunsDiscovered = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "job_runs_discovered_total",
+			Help:      "number of job runs found to aggregate or upload, by job",
+		},
+		[]string{"job"},
+	)
+	gcsRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "gcs_request_duration_seconds",
+			Help:      "duration of the requests reading artifacts from GCS, by operation and result",
+			Buckets:   prometheus.ExponentialBuckets(0.05, 2, 10),
+		},
+		[]string{"operation", "result"},
+	)
+	bigQueryInsertFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "bigquery_insert_failures_total",
+			Help:      "number of failed inserts into BigQuery, by table",
+		},
+		[]string{"table"},
+	)
+	aggregationVerdicts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "aggregation_verdicts_total",
+			Help:      "number of aggregations, by job and verdict: passed, failed or error",
+		},
+		[]string{"job", "verdict"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(jobRunsDiscovered, gcsRequestDuration, bigQueryInsertFailures, aggregationVerdicts)
+}
+
+// RecordJobRunsDiscovered counts the job runs of the job found to aggregate or upload
+func RecordJobRunsDiscovered(jobName string, count int) {
+	jobRunsDiscovered.WithLabelValues(jobName).Add(float64(count))
+}
+
+// RecordAggregationVerdict counts the verdict of an aggregation of the job
+func RecordAggregationVerdict(jobName, verdict string) {
+	aggregationVerdicts.WithLabelValues(jobName, verdict).Inc()
+}
+
+// observeGCSRequest records the duration of a GCS request which started at start
+func observeGCSRequest(operation string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	gcsRequestDuration.WithLabelValues(operation, result).Observe(time.Since(start).Seconds())
+}
+
+type instrumentedInserter struct {
+	table    string
+	delegate BigQueryInserter
+}
+
+// NewInstrumentedInserter counts the failed inserts of the inserter into the table
+func NewInstrumentedInserter(table string, delegate BigQueryInserter) BigQueryInserter {
+	return &instrumentedInserter{
+		table:    table,
+		delegate: delegate,
+	}
+}
+
+func (i *instrumentedInserter) Put(ctx context.Context, src interface{}) error {
+	err := i.delegate.Put(ctx, src)
+	if err != nil {
+		bigQueryInsertFailures.WithLabelValues(i.table).Inc()
+	}
+	return err
+}
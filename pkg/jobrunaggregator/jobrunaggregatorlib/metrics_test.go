@@ -0,0 +1,38 @@
+package jobrunaggregatorlib
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeInserter struct {
+	err error
+}
+
+func (i fakeInserter) Put(context.Context, interface{}) error {
+	return i.err
+}
+
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	metric := &dto.Metric{}
+	if err := counter.Write(metric); err != nil {
+		t.Fatalf("failed to read the counter: %v", err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func TestInstrumentedInserter(t *testing.T) {
+	failures := bigQueryInsertFailures.WithLabelValues("TestTable")
+	before := counterValue(t, failures)
+
+	assert.NoError(t, NewInstrumentedInserter("TestTable", fakeInserter{}).Put(context.Background(), nil))
+	assert.Equal(t, before, counterValue(t, failures))
+
+	assert.Error(t, NewInstrumentedInserter("TestTable", fakeInserter{err: errors.New("quota exceeded")}).Put(context.Background(), nil))
+	assert.Equal(t, before+1, counterValue(t, failures))
+}
@@ -46,6 +46,26 @@ func (c *retryingCIDataClient) GetBackendDisruptionStatisticsByJob(ctx context.C
 	return ret, err
 }
 
+func (c *retryingCIDataClient) GetAlertStatisticsByJob(ctx context.Context, jobName string, startDay time.Time) ([]jobrunaggregatorapi.AlertStatisticsRow, error) {
+	var ret []jobrunaggregatorapi.AlertStatisticsRow
+	err := retry.OnError(slowBackoff, isReadQuotaError, func() error {
+		var innerErr error
+		ret, innerErr = c.delegate.GetAlertStatisticsByJob(ctx, jobName, startDay)
+		return innerErr
+	})
+	return ret, err
+}
+
+func (c *retryingCIDataClient) GetTestHistory(ctx context.Context, jobName, testName string, from, to time.Time) ([]jobrunaggregatorapi.TestHistoryRow, error) {
+	var ret []jobrunaggregatorapi.TestHistoryRow
+	err := retry.OnError(slowBackoff, isReadQuotaError, func() error {
+		var innerErr error
+		ret, innerErr = c.delegate.GetTestHistory(ctx, jobName, testName, from, to)
+		return innerErr
+	})
+	return ret, err
+}
+
 func (c *retryingCIDataClient) ListAllJobs(ctx context.Context) ([]jobrunaggregatorapi.JobRow, error) {
 	var ret []jobrunaggregatorapi.JobRow
 	err := retry.OnError(slowBackoff, isReadQuotaError, func() error {
@@ -116,6 +136,26 @@ func (c *retryingCIDataClient) ListReleaseTags(ctx context.Context) (sets.Set[st
 	return ret, err
 }
 
+func (c *retryingCIDataClient) ListPayloadsForJob(ctx context.Context, jobName string, from, to time.Time) ([]jobrunaggregatorapi.PayloadJobRunsRow, error) {
+	var ret []jobrunaggregatorapi.PayloadJobRunsRow
+	err := retry.OnError(slowBackoff, isReadQuotaError, func() error {
+		var innerErr error
+		ret, innerErr = c.delegate.ListPayloadsForJob(ctx, jobName, from, to)
+		return innerErr
+	})
+	return ret, err
+}
+
+func (c *retryingCIDataClient) ListAggregationRunsForJob(ctx context.Context, jobName string, since time.Time) ([]jobrunaggregatorapi.AggregationRunRow, error) {
+	var ret []jobrunaggregatorapi.AggregationRunRow
+	err := retry.OnError(slowBackoff, isReadQuotaError, func() error {
+		var innerErr error
+		ret, innerErr = c.delegate.ListAggregationRunsForJob(ctx, jobName, since)
+		return innerErr
+	})
+	return ret, err
+}
+
 func (c *retryingCIDataClient) GetJobRunForJobNameBeforeTime(ctx context.Context, jobName string, targetTime time.Time) (string, error) {
 	var ret string
 	err := retry.OnError(slowBackoff, isReadQuotaError, func() error {
@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/retry"
 	prowv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
 	prowjobclientset "k8s.io/test-infra/prow/client/clientset/versioned"
 	prowjobinformers "k8s.io/test-infra/prow/client/informers/externalversions"
@@ -107,25 +108,35 @@ func getAllFinishedJobRuns(ctx context.Context, relatedJobRuns []jobrunaggregato
 		return finishedJobRuns, unfinishedJobRuns, finishedJobRunNames, unfinishedJobRunNames
 	}
 
-	for i := range relatedJobRuns {
+	// the job runs are checked concurrently, because each check reads from GCS
+	finished := make([]bool, len(relatedJobRuns))
+	forEachConcurrently(ctx, len(relatedJobRuns), DefaultGCSConcurrency, func(ctx context.Context, i int) error {
 		jobRun := relatedJobRuns[i]
 		if !jobRun.IsFinished(ctx) {
 			logrus.Debugf("%v/%v is not finished", jobRun.GetJobName(), jobRun.GetJobRunID())
-			unfinishedJobRunNames = append(unfinishedJobRunNames, jobRun.GetJobRunID())
-			unfinishedJobRuns = append(unfinishedJobRuns, jobRun)
-			continue
+			return nil
 		}
 
-		prowJob, err := jobRun.GetProwJob(ctx)
-		if err != nil {
+		var prowJob *prowv1.ProwJob
+		if err := retry.OnError(gcsBackoff, isRetriableGCSError, func() error {
+			var err error
+			prowJob, err = jobRun.GetProwJob(ctx)
+			return err
+		}); err != nil {
 			logrus.WithError(err).Errorf("error reading prowjob %v", jobRun.GetJobRunID())
-			unfinishedJobRunNames = append(unfinishedJobRunNames, jobRun.GetJobRunID())
-			unfinishedJobRuns = append(unfinishedJobRuns, jobRun)
-			continue
+			return nil
 		}
 
 		if prowJob.Status.CompletionTime == nil {
 			logrus.Debugf("%v/%v has no completion time for resourceVersion=%v", jobRun.GetJobName(), jobRun.GetJobRunID(), prowJob.ResourceVersion)
+			return nil
+		}
+		finished[i] = true
+		return nil
+	})
+
+	for i, jobRun := range relatedJobRuns {
+		if !finished[i] {
 			unfinishedJobRunNames = append(unfinishedJobRunNames, jobRun.GetJobRunID())
 			unfinishedJobRuns = append(unfinishedJobRuns, jobRun)
 			continue
@@ -202,6 +213,8 @@ type ClusterJobRunWaiter struct {
 	ProwJobClient      *prowjobclientset.Clientset
 	TimeToStopWaiting  time.Time
 	ProwJobMatcherFunc ProwJobMatcherFunc
+	// ProwJobLabels are the keys read from the prow jobs, the unset keys default to the ones of the OpenShift CI
+	ProwJobLabels ProwJobLabels
 }
 
 func (w *ClusterJobRunWaiter) allProwJobsFinished(allItems []*prowv1.ProwJob) (bool, map[string]*prowv1.ProwJob) {
@@ -212,7 +225,7 @@ func (w *ClusterJobRunWaiter) allProwJobsFinished(allItems []*prowv1.ProwJob) (b
 		if !w.ProwJobMatcherFunc(prowJob) {
 			continue
 		}
-		jobRunID := prowJob.Labels[prowJobJobRunIDLabel]
+		jobRunID := w.ProwJobLabels.GetJobRunID(prowJob)
 		matchedJobMap[jobRunID] = prowJob
 		if prowJob.Status.CompletionTime != nil {
 			continue
@@ -330,8 +343,7 @@ func WaitAndGetAllFinishedJobRuns(ctx context.Context,
 
 	finishedJobRuns, unfinishedJobRuns, finishedJobRunNames, unfinishedJobRunNames = getAllFinishedJobRuns(ctx, relatedJobRuns)
 
-	summaryHTML := htmlForJobRuns(ctx, finishedJobRuns, unfinishedJobRuns, variantInfo)
-	if err := os.WriteFile(filepath.Join(outputDir, "job-run-summary.html"), []byte(summaryHTML), 0644); err != nil {
+	if err := WriteJobRunSummary(ctx, outputDir, finishedJobRuns, unfinishedJobRuns, variantInfo); err != nil {
 		return finishedJobRuns, unfinishedJobRuns, finishedJobRunNames, unfinishedJobRunNames, err
 	}
 
@@ -340,6 +352,12 @@ func WaitAndGetAllFinishedJobRuns(ctx context.Context,
 	return finishedJobRuns, unfinishedJobRuns, finishedJobRunNames, unfinishedJobRunNames, nil
 }
 
+// WriteJobRunSummary writes the summary of the finished and unfinished job runs to job-run-summary.html in outputDir
+func WriteJobRunSummary(ctx context.Context, outputDir string, finishedJobRuns, unfinishedJobRuns []jobrunaggregatorapi.JobRunInfo, variantInfo string) error {
+	summaryHTML := htmlForJobRuns(ctx, finishedJobRuns, unfinishedJobRuns, variantInfo)
+	return os.WriteFile(filepath.Join(outputDir, "job-run-summary.html"), []byte(summaryHTML), 0644)
+}
+
 // OutputTestCaseFailures prints detailed test failures
 func OutputTestCaseFailures(parents []string, suite *junit.TestSuite) {
 	currSuite := append(parents, suite.Name)
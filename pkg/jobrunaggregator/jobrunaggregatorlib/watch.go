@@ -0,0 +1,89 @@
+package jobrunaggregatorlib
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
+)
+
+// DefaultWatchPollInterval is how often the job runs are located again when watching them
+const DefaultWatchPollInterval = 2 * time.Minute
+
+// JobRunWatcher watches the job runs as they finish, instead of waiting for all of them before reading any:
+// 1. It locates the job runs on every poll, so that job runs started late are found as well.
+// 2. It checks only the job runs which were not seen finished yet.
+// 3. It hands every job run to OnJobRunFinished once, as soon as it finishes.
+// 4. It stops once RequiredJobRuns job runs finished, or at TimeToStopWaiting.
+type JobRunWatcher struct {
+	JobRunGetter JobRunGetter
+	// RequiredJobRuns is the number of finished job runs the watch ends with
+	RequiredJobRuns   int
+	PollInterval      time.Duration
+	TimeToStopWaiting time.Time
+	// OnJobRunFinished is called with every job run when it is seen finished. When it fails, the job run
+	// is handed to it again on the next poll.
+	OnJobRunFinished func(ctx context.Context, jobRun jobrunaggregatorapi.JobRunInfo) error
+
+	clock clock.PassiveClock
+}
+
+// Watch watches the job runs until RequiredJobRuns of them finished or until TimeToStopWaiting. It returns the
+// finished job runs in the order they finished and, when it timed out, the job runs which were still unfinished.
+func (w *JobRunWatcher) Watch(ctx context.Context) ([]jobrunaggregatorapi.JobRunInfo, []jobrunaggregatorapi.JobRunInfo, error) {
+	if w.clock == nil {
+		w.clock = clock.RealClock{}
+	}
+	pollInterval := w.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = DefaultWatchPollInterval
+	}
+
+	var finishedJobRuns []jobrunaggregatorapi.JobRunInfo
+	finishedJobRunIDs := map[string]bool{}
+	for {
+		relatedJobRuns, err := w.JobRunGetter.GetRelatedJobRuns(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		var jobRunsToCheck []jobrunaggregatorapi.JobRunInfo
+		for _, jobRun := range relatedJobRuns {
+			if !finishedJobRunIDs[jobRun.GetJobRunID()] {
+				jobRunsToCheck = append(jobRunsToCheck, jobRun)
+			}
+		}
+
+		newlyFinishedJobRuns, unfinishedJobRuns, _, _ := getAllFinishedJobRuns(ctx, jobRunsToCheck)
+		for _, jobRun := range newlyFinishedJobRuns {
+			if w.OnJobRunFinished != nil {
+				if err := w.OnJobRunFinished(ctx, jobRun); err != nil {
+					logrus.WithError(err).Warnf("failed to process finished job run %v/%v, will retry", jobRun.GetJobName(), jobRun.GetJobRunID())
+					unfinishedJobRuns = append(unfinishedJobRuns, jobRun)
+					continue
+				}
+			}
+			finishedJobRunIDs[jobRun.GetJobRunID()] = true
+			finishedJobRuns = append(finishedJobRuns, jobRun)
+		}
+		logrus.Infof("watching job runs: %d/%d required job runs finished, %d unfinished", len(finishedJobRuns), w.RequiredJobRuns, len(unfinishedJobRuns))
+
+		if len(finishedJobRuns) >= w.RequiredJobRuns {
+			logrus.Infof("the required %d job runs finished", w.RequiredJobRuns)
+			return finishedJobRuns, nil, nil
+		}
+		if w.clock.Now().After(w.TimeToStopWaiting) {
+			logrus.Infof("stopped watching at %v with %d unfinished job runs", w.TimeToStopWaiting, len(unfinishedJobRuns))
+			return finishedJobRuns, unfinishedJobRuns, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package jobrunaggregatorlib
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	prowv1 "k8s.io/test-infra/prow/apis/prowjobs/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
+)
+
+// fakeWatchedJobRun finishes at the poll given by finishedAt
+type fakeWatchedJobRun struct {
+	jobrunaggregatorapi.JobRunInfo
+	id         string
+	finishedAt int
+	polls      *int
+}
+
+func (j *fakeWatchedJobRun) GetJobName() string  { return "job" }
+func (j *fakeWatchedJobRun) GetJobRunID() string { return j.id }
+func (j *fakeWatchedJobRun) IsFinished(context.Context) bool {
+	return j.finishedAt >= 0 && *j.polls >= j.finishedAt
+}
+func (j *fakeWatchedJobRun) GetProwJob(context.Context) (*prowv1.ProwJob, error) {
+	completionTime := metav1.Now()
+	return &prowv1.ProwJob{Status: prowv1.ProwJobStatus{CompletionTime: &completionTime}}, nil
+}
+
+// fakeWatchedJobRunGetter locates the job runs started by the current poll
+type fakeWatchedJobRunGetter struct {
+	JobRunGetter
+	jobRuns   []*fakeWatchedJobRun
+	startedAt map[string]int
+	polls     int
+}
+
+func (g *fakeWatchedJobRunGetter) GetRelatedJobRuns(context.Context) ([]jobrunaggregatorapi.JobRunInfo, error) {
+	g.polls++
+	var jobRuns []jobrunaggregatorapi.JobRunInfo
+	for _, jobRun := range g.jobRuns {
+		if g.startedAt[jobRun.id] <= g.polls {
+			jobRuns = append(jobRuns, jobRun)
+		}
+	}
+	return jobRuns, nil
+}
+
+func jobRunIDs(jobRuns []jobrunaggregatorapi.JobRunInfo) []string {
+	var ids []string
+	for _, jobRun := range jobRuns {
+		ids = append(ids, jobRun.GetJobRunID())
+	}
+	return ids
+}
+
+func TestJobRunWatcher(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name              string
+		finishedAt        map[string]int
+		startedAt         map[string]int
+		requiredJobRuns   int
+		timeToStopWaiting time.Time
+		failHandling      map[string]int
+
+		expectedFinished   []string
+		expectedUnfinished []string
+		expectedHandled    []string
+	}{
+		{
+			name:              "stops once the required job runs finished",
+			finishedAt:        map[string]int{"a": 2, "b": 1, "c": -1},
+			requiredJobRuns:   2,
+			timeToStopWaiting: now.Add(time.Hour),
+			expectedFinished:  []string{"b", "a"},
+			expectedHandled:   []string{"b", "a"},
+		},
+		{
+			name:              "finds job runs started late",
+			finishedAt:        map[string]int{"a": 1, "b": 3},
+			startedAt:         map[string]int{"b": 2},
+			requiredJobRuns:   2,
+			timeToStopWaiting: now.Add(time.Hour),
+			expectedFinished:  []string{"a", "b"},
+			expectedHandled:   []string{"a", "b"},
+		},
+		{
+			name:               "returns the unfinished job runs at the timeout",
+			finishedAt:         map[string]int{"a": 1, "b": -1, "c": -1},
+			requiredJobRuns:    2,
+			timeToStopWaiting:  now.Add(-time.Minute),
+			expectedFinished:   []string{"a"},
+			expectedUnfinished: []string{"b", "c"},
+			expectedHandled:    []string{"a"},
+		},
+		{
+			name:              "hands a job run again when handling it failed",
+			finishedAt:        map[string]int{"a": 1, "b": 1},
+			failHandling:      map[string]int{"a": 1},
+			requiredJobRuns:   2,
+			timeToStopWaiting: now.Add(time.Hour),
+			expectedFinished:  []string{"b", "a"},
+			expectedHandled:   []string{"b", "a"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			getter := &fakeWatchedJobRunGetter{startedAt: tc.startedAt}
+			for _, id := range []string{"a", "b", "c"} {
+				if finishedAt, ok := tc.finishedAt[id]; ok {
+					getter.jobRuns = append(getter.jobRuns, &fakeWatchedJobRun{id: id, finishedAt: finishedAt, polls: &getter.polls})
+				}
+			}
+			failures := map[string]int{}
+			for id, count := range tc.failHandling {
+				failures[id] = count
+			}
+			var handled []string
+			watcher := &JobRunWatcher{
+				JobRunGetter:      getter,
+				RequiredJobRuns:   tc.requiredJobRuns,
+				PollInterval:      time.Millisecond,
+				TimeToStopWaiting: tc.timeToStopWaiting,
+				OnJobRunFinished: func(_ context.Context, jobRun jobrunaggregatorapi.JobRunInfo) error {
+					if failures[jobRun.GetJobRunID()] > 0 {
+						failures[jobRun.GetJobRunID()]--
+						return errors.New("failed")
+					}
+					handled = append(handled, jobRun.GetJobRunID())
+					return nil
+				},
+				clock: clocktesting.NewFakePassiveClock(now),
+			}
+
+			finished, unfinished, err := watcher.Watch(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedFinished, jobRunIDs(finished))
+			assert.Equal(t, tc.expectedUnfinished, jobRunIDs(unfinished))
+			assert.Equal(t, tc.expectedHandled, handled)
+		})
+	}
+}
@@ -2,19 +2,14 @@ package jobrunbigqueryloader
 
 import (
 	"context"
-	"encoding/json"
 	"math"
 	"os"
-	"sort"
-	"strings"
 
 	"cloud.google.com/go/bigquery"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
 	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
 	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorlib"
 )
@@ -113,8 +108,8 @@ func (f *BigQueryAlertUploadFlags) ToOptions(ctx context.Context) (*allJobsLoade
 		ciDataSet := bigQueryClient.Dataset(f.DataCoordinates.DataSetID)
 		jobRunTable := ciDataSet.Table(jobrunaggregatorapi.AlertJobRunTableName)
 		backendAlertTable := ciDataSet.Table(jobrunaggregatorapi.AlertsTableName)
-		jobRunTableInserter = jobRunTable.Inserter()
-		backendAlertTableInserter = backendAlertTable.Inserter()
+		jobRunTableInserter = jobrunaggregatorlib.NewInstrumentedInserter(jobrunaggregatorapi.AlertJobRunTableName, jobRunTable.Inserter())
+		backendAlertTableInserter = jobrunaggregatorlib.NewInstrumentedInserter(jobrunaggregatorapi.AlertsTableName, backendAlertTable.Inserter())
 	} else {
 		jobRunTableInserter = jobrunaggregatorlib.NewDryRunInserter(os.Stdout, jobrunaggregatorapi.AlertJobRunTableName)
 		backendAlertTableInserter = jobrunaggregatorlib.NewDryRunInserter(os.Stdout, jobrunaggregatorapi.AlertsTableName)
@@ -268,83 +263,9 @@ func populateZeros(jobRunRow *jobrunaggregatorapi.JobRunRow,
 	return observedAlertRows
 }
 
-type AlertList struct {
-	// Alerts is keyed by name to make the consumption easier
-	Alerts []Alert
-}
-
-// name and namespace are consistent (usually) for every CI run
-type AlertKey struct {
-	Name      string
-	Namespace string
-	Level     AlertLevel
-}
-
-type AlertByKey []Alert
-
-func (a AlertByKey) Len() int      { return len(a) }
-func (a AlertByKey) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
-func (a AlertByKey) Less(i, j int) bool {
-	if strings.Compare(a[i].Name, a[j].Name) < 0 {
-		return true
-	}
-	if strings.Compare(a[i].Namespace, a[j].Namespace) < 0 {
-		return true
-	}
-	if strings.Compare(string(a[i].Level), string(a[j].Level)) < 0 {
-		return true
-	}
-
-	return false
-}
-
-type AlertLevel string
-
-var (
-	UnknownAlertLevel  AlertLevel = "Unknown"
-	WarningAlertLevel  AlertLevel = "Warning"
-	CriticalAlertLevel AlertLevel = "Critical"
-)
-
-type Alert struct {
-	AlertKey `json:",inline"`
-	Duration metav1.Duration
-}
-
 func getAlertsFromPerJobRunData(alertData map[string]string, jobRunRow *jobrunaggregatorapi.JobRunRow) []jobrunaggregatorapi.AlertRow {
-	alertMap := map[AlertKey]*Alert{}
-
-	for _, alertJSON := range alertData {
-		if len(alertJSON) == 0 {
-			continue
-		}
-		allAlerts := &AlertList{}
-		if err := json.Unmarshal([]byte(alertJSON), allAlerts); err != nil {
-			continue
-		}
-
-		for i := range allAlerts.Alerts {
-			curr := allAlerts.Alerts[i]
-			existing, ok := alertMap[curr.AlertKey]
-			if !ok {
-				existing = &Alert{
-					AlertKey: curr.AlertKey,
-				}
-			}
-			existing.Duration.Duration = existing.Duration.Duration + curr.Duration.Duration
-			alertMap[existing.AlertKey] = existing
-		}
-	}
-
-	// sort for stable output for testing and such.
-	alertList := []Alert{}
-	for _, alert := range alertMap {
-		alertList = append(alertList, *alert)
-	}
-	sort.Stable(AlertByKey(alertList))
-
 	ret := []jobrunaggregatorapi.AlertRow{}
-	for _, alert := range alertList {
+	for _, alert := range jobrunaggregatorlib.GetAlertsFromDirectData(alertData) {
 		ret = append(ret, jobrunaggregatorapi.AlertRow{
 			Name:         alert.Name,
 			Namespace:    alert.Namespace,
@@ -110,8 +110,8 @@ func (f *BigQueryTestRunUploadFlags) ToOptions(ctx context.Context) (*allJobsLoa
 		ciDataSet := bigQueryClient.Dataset(f.DataCoordinates.DataSetID)
 		jobRunTable := ciDataSet.Table(jobrunaggregatorapi.LegacyJobRunTableName)
 		testRunTable := ciDataSet.Table(jobrunaggregatorlib.TestRunTableName)
-		jobRunTableInserter = jobRunTable.Inserter()
-		testRunTableInserter = testRunTable.Inserter()
+		jobRunTableInserter = jobrunaggregatorlib.NewInstrumentedInserter(jobrunaggregatorapi.LegacyJobRunTableName, jobRunTable.Inserter())
+		testRunTableInserter = jobrunaggregatorlib.NewInstrumentedInserter(jobrunaggregatorlib.TestRunTableName, testRunTable.Inserter())
 
 		// could start with dry run for the new uploaders if we wanted
 		// backendAlertTableInserter = jobrunaggregatorlib.NewDryRunInserter(os.Stdout, jobrunaggregatorapi.AlertsTableName)
@@ -149,7 +149,7 @@ func (f *BigQueryTestRunUploadFlags) ToOptions(ctx context.Context) (*allJobsLoa
 			return nil, err
 		}
 		jobRunUploaderRegistry.Register("alertUploader", alertUploader)
-		jobRunUploaderRegistry.Register("disruptionUploader", newDisruptionUploader(backendDisruptionTableInserter, ciDataClient))
+		jobRunUploaderRegistry.Register("disruptionUploader", newDisruptionUploader(backendDisruptionTableInserter, ciDataClient, jobrunaggregatorlib.DefaultDisruptionBackends()))
 	}
 
 	return &allJobsLoaderOptions{
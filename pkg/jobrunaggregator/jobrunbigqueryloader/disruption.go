@@ -19,9 +19,10 @@ type BigQueryDisruptionUploadFlags struct {
 	DataCoordinates *jobrunaggregatorlib.BigQueryDataCoordinates
 	Authentication  *jobrunaggregatorlib.GoogleAuthenticationFlags
 
-	DryRun    bool
-	LogLevel  string
-	GCSBucket string
+	DryRun                 bool
+	LogLevel               string
+	GCSBucket              string
+	DisruptionBackendsFile string
 }
 
 func NewBigQueryDisruptionUploadFlags() *BigQueryDisruptionUploadFlags {
@@ -38,6 +39,7 @@ func (f *BigQueryDisruptionUploadFlags) BindFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&f.DryRun, "dry-run", f.DryRun, "Run the command, but don't mutate data.")
 	fs.StringVar(&f.LogLevel, "log-level", "info", "Log level (trace,debug,info,warn,error) (default: info)")
 	fs.StringVar(&f.GCSBucket, "google-storage-bucket", "test-platform-results", "The optional GCS Bucket holding test artifacts")
+	fs.StringVar(&f.DisruptionBackendsFile, "disruption-backends-file", f.DisruptionBackendsFile, "The optional path to a file listing the disruption backends which are ignored, and not uploaded.")
 }
 
 func NewBigQueryDisruptionUploadFlagsCommand() *cobra.Command {
@@ -109,16 +111,24 @@ func (f *BigQueryDisruptionUploadFlags) ToOptions(ctx context.Context) (*allJobs
 		ciDataSet := bigQueryClient.Dataset(f.DataCoordinates.DataSetID)
 		jobRunTable := ciDataSet.Table(jobrunaggregatorapi.DisruptionJobRunTableName)
 		backendDisruptionTable := ciDataSet.Table(jobrunaggregatorapi.BackendDisruptionTableName)
-		jobRunTableInserter = jobRunTable.Inserter()
-		backendDisruptionTableInserter = backendDisruptionTable.Inserter()
+		jobRunTableInserter = jobrunaggregatorlib.NewInstrumentedInserter(jobrunaggregatorapi.DisruptionJobRunTableName, jobRunTable.Inserter())
+		backendDisruptionTableInserter = jobrunaggregatorlib.NewInstrumentedInserter(jobrunaggregatorapi.BackendDisruptionTableName, backendDisruptionTable.Inserter())
 	} else {
 		jobRunTableInserter = jobrunaggregatorlib.NewDryRunInserter(os.Stdout, jobrunaggregatorapi.DisruptionJobRunTableName)
 		backendDisruptionTableInserter = jobrunaggregatorlib.NewDryRunInserter(os.Stdout, jobrunaggregatorapi.BackendDisruptionTableName)
 	}
 
+	disruptionBackends := jobrunaggregatorlib.DefaultDisruptionBackends()
+	if len(f.DisruptionBackendsFile) > 0 {
+		disruptionBackends, err = jobrunaggregatorlib.LoadDisruptionBackends(f.DisruptionBackendsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	pendingUploadLister := newDisruptionPendingUploadLister(ciDataClient)
 	jobRunUploaderRegistry := JobRunUploaderRegistry{}
-	jobRunUploaderRegistry.Register("disruptionUploader", newDisruptionUploader(backendDisruptionTableInserter, ciDataClient))
+	jobRunUploaderRegistry.Register("disruptionUploader", newDisruptionUploader(backendDisruptionTableInserter, ciDataClient, disruptionBackends))
 	return &allJobsLoaderOptions{
 		ciDataClient: ciDataClient,
 		gcsClient:    gcsClient,
@@ -134,14 +144,17 @@ func (f *BigQueryDisruptionUploadFlags) ToOptions(ctx context.Context) (*allJobs
 type disruptionUploader struct {
 	backendDisruptionInserter jobrunaggregatorlib.BigQueryInserter
 	ciDataClient              jobrunaggregatorlib.CIDataClient
+	// disruptionBackends decides which backends are not uploaded
+	disruptionBackends *jobrunaggregatorlib.DisruptionBackends
 }
 
 func newDisruptionUploader(backendDisruptionInserter jobrunaggregatorlib.BigQueryInserter,
-	ciDataClient jobrunaggregatorlib.CIDataClient) uploader {
+	ciDataClient jobrunaggregatorlib.CIDataClient, disruptionBackends *jobrunaggregatorlib.DisruptionBackends) uploader {
 
 	return &disruptionUploader{
 		backendDisruptionInserter: backendDisruptionInserter,
 		ciDataClient:              ciDataClient,
+		disruptionBackends:        disruptionBackends,
 	}
 }
 
@@ -187,6 +200,10 @@ func (o *disruptionUploader) uploadBackendDisruption(ctx context.Context, jobRun
 	logger.Debug("inserting backend disruption rows")
 	rows := []*jobrunaggregatorapi.BackendDisruptionRow{}
 	for _, backendName := range sets.StringKeySet(serverAvailabilityResults).List() {
+		if o.disruptionBackends.IsIgnored(backendName) {
+			logger.WithField("backend", backendName).Debug("skipping ignored backend")
+			continue
+		}
 		unavailability := serverAvailabilityResults[backendName]
 		row := &jobrunaggregatorapi.BackendDisruptionRow{
 			BackendName:       backendName,
@@ -0,0 +1,279 @@
+package jobrunbigqueryloader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorlib"
+)
+
+const (
+	// stepPodsArtifact is where ci-operator records the pods of the job run namespace
+	stepPodsArtifact = "artifacts/build-resources/pods.json"
+
+	// the labels ci-operator sets on the pods of the steps
+	stepLabel           = "ci.openshift.io/metadata.step"
+	multiStageTestLabel = "ci.openshift.io/multi-stage-test"
+	buildNameLabel      = "openshift.io/build.name"
+)
+
+type BigQueryStepResourceUploadFlags struct {
+	DataCoordinates *jobrunaggregatorlib.BigQueryDataCoordinates
+	Authentication  *jobrunaggregatorlib.GoogleAuthenticationFlags
+
+	DryRun    bool
+	LogLevel  string
+	GCSBucket string
+}
+
+func NewBigQueryStepResourceUploadFlags() *BigQueryStepResourceUploadFlags {
+	return &BigQueryStepResourceUploadFlags{
+		DataCoordinates: jobrunaggregatorlib.NewBigQueryDataCoordinates(),
+		Authentication:  jobrunaggregatorlib.NewGoogleAuthenticationFlags(),
+	}
+}
+
+func (f *BigQueryStepResourceUploadFlags) BindFlags(fs *pflag.FlagSet) {
+	f.DataCoordinates.BindFlags(fs)
+	f.Authentication.BindFlags(fs)
+
+	fs.BoolVar(&f.DryRun, "dry-run", f.DryRun, "Run the command, but don't mutate data.")
+	fs.StringVar(&f.LogLevel, "log-level", "info", "Log level (trace,debug,info,warn,error) (default: info)")
+	fs.StringVar(&f.GCSBucket, "google-storage-bucket", "test-platform-results", "The optional GCS Bucket holding test artifacts")
+}
+
+func NewBigQueryStepResourceUploadFlagsCommand() *cobra.Command {
+	f := NewBigQueryStepResourceUploadFlags()
+
+	cmd := &cobra.Command{
+		Use:          "upload-step-resources",
+		Long:         `Upload the resources the steps of the job runs requested and how their containers ended to bigquery`,
+		SilenceUsage: true,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			if err := f.Validate(); err != nil {
+				logrus.WithError(err).Fatal("Flags are invalid")
+			}
+			o, err := f.ToOptions(ctx)
+			if err != nil {
+				logrus.WithError(err).Fatal("Failed to build runtime options")
+			}
+
+			if err := o.Run(ctx); err != nil {
+				logrus.WithError(err).Fatal("Command failed")
+			}
+
+			return nil
+		},
+
+		Args: jobrunaggregatorlib.NoArgs,
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Validate checks to see if the user-input is likely to produce functional runtime options
+func (f *BigQueryStepResourceUploadFlags) Validate() error {
+	if err := f.DataCoordinates.Validate(); err != nil {
+		return err
+	}
+	if err := f.Authentication.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ToOptions goes from the user input to the runtime values need to run the command.
+// Expect to see unit tests on the options, but not on the flags which are simply value mappings.
+func (f *BigQueryStepResourceUploadFlags) ToOptions(ctx context.Context) (*allJobsLoaderOptions, error) {
+	// Create a new GCS Client
+	gcsClient, err := f.Authentication.NewCIGCSClient(ctx, f.GCSBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	bigQueryClient, err := f.Authentication.NewBigQueryClient(ctx, f.DataCoordinates.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	ciDataClient := jobrunaggregatorlib.NewRetryingCIDataClient(
+		jobrunaggregatorlib.NewCIDataClient(*f.DataCoordinates, bigQueryClient),
+	)
+
+	var jobRunTableInserter jobrunaggregatorlib.BigQueryInserter
+	var stepResourceTableInserter jobrunaggregatorlib.BigQueryInserter
+	if !f.DryRun {
+		ciDataSet := bigQueryClient.Dataset(f.DataCoordinates.DataSetID)
+		jobRunTable := ciDataSet.Table(jobrunaggregatorapi.StepResourceJobRunTableName)
+		stepResourceTable := ciDataSet.Table(jobrunaggregatorapi.StepResourceTableName)
+		jobRunTableInserter = jobrunaggregatorlib.NewInstrumentedInserter(jobrunaggregatorapi.StepResourceJobRunTableName, jobRunTable.Inserter())
+		stepResourceTableInserter = jobrunaggregatorlib.NewInstrumentedInserter(jobrunaggregatorapi.StepResourceTableName, stepResourceTable.Inserter())
+	} else {
+		jobRunTableInserter = jobrunaggregatorlib.NewDryRunInserter(os.Stdout, jobrunaggregatorapi.StepResourceJobRunTableName)
+		stepResourceTableInserter = jobrunaggregatorlib.NewDryRunInserter(os.Stdout, jobrunaggregatorapi.StepResourceTableName)
+	}
+
+	jobRunUploaderRegistry := JobRunUploaderRegistry{}
+	jobRunUploaderRegistry.Register("stepResourceUploader", newStepResourceUploader(stepResourceTableInserter))
+	return &allJobsLoaderOptions{
+		ciDataClient: ciDataClient,
+		gcsClient:    gcsClient,
+
+		jobRunInserter: jobRunTableInserter,
+		shouldCollectedDataForJobFn: func(job jobrunaggregatorapi.JobRow) bool {
+			return true
+		},
+		jobRunUploaderRegistry: jobRunUploaderRegistry,
+		pendingUploadJobsLister: &testRunPendingUploadLister{
+			tableName:    jobrunaggregatorapi.StepResourceJobRunTableName,
+			ciDataClient: ciDataClient,
+		},
+		logLevel: f.LogLevel,
+	}, nil
+}
+
+// stepResourceUploader uploads what the containers of the steps of a job run requested and how they ended, so that
+// the steps killed for using more memory than they asked for can be found and their resources tuned
+type stepResourceUploader struct {
+	stepResourceInserter jobrunaggregatorlib.BigQueryInserter
+}
+
+func newStepResourceUploader(stepResourceInserter jobrunaggregatorlib.BigQueryInserter) uploader {
+	return &stepResourceUploader{
+		stepResourceInserter: stepResourceInserter,
+	}
+}
+
+func (o *stepResourceUploader) uploadContent(ctx context.Context, jobRun jobrunaggregatorapi.JobRunInfo,
+	jobRelease string, jobRunRow *jobrunaggregatorapi.JobRunRow, logger logrus.FieldLogger) error {
+	logger.Info("uploading step resources")
+	podsData, err := jobRun.GetOpenShiftTestsFilesWithPrefix(ctx, "build-resources/pods.json")
+	if err != nil {
+		return err
+	}
+	var pods string
+	for name, content := range podsData {
+		// only the pods of the namespace of ci-operator, not the ones the steps gathered from the clusters they tested
+		if strings.HasSuffix(name, "/"+stepPodsArtifact) {
+			pods = content
+		}
+	}
+	if len(pods) == 0 {
+		logger.Debug("no step pods found, skipping insert")
+		return nil
+	}
+
+	rows, err := getStepResourceRows([]byte(pods), jobRunRow)
+	if err != nil {
+		return fmt.Errorf("failed to read the step pods of jobrun/%v/%v: %w", jobRun.GetJobName(), jobRun.GetJobRunID(), err)
+	}
+	if err := o.stepResourceInserter.Put(ctx, rows); err != nil {
+		return err
+	}
+	logger.WithField("containers", len(rows)).Debug("insert complete")
+	return nil
+}
+
+// getStepResourceRows returns a row for every container of the pods of the steps and builds, the other pods of the
+// namespace are skipped
+func getStepResourceRows(podsJSON []byte, jobRunRow *jobrunaggregatorapi.JobRunRow) ([]jobrunaggregatorapi.StepResourceRow, error) {
+	pods := &corev1.PodList{}
+	if err := json.Unmarshal(podsJSON, pods); err != nil {
+		return nil, err
+	}
+
+	rows := []jobrunaggregatorapi.StepResourceRow{}
+	for _, pod := range pods.Items {
+		step := pod.Labels[stepLabel]
+		if len(step) == 0 {
+			step = pod.Labels[buildNameLabel]
+		}
+		if len(step) == 0 {
+			continue
+		}
+		statuses := map[string]corev1.ContainerStatus{}
+		for _, status := range append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+			statuses[status.Name] = status
+		}
+		for _, container := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+			row := newStepResourceRow(jobRunRow, container, statuses[container.Name])
+			row.Test = nullString(pod.Labels[multiStageTestLabel])
+			row.Step = step
+			row.Pod = pod.Name
+			row.PodReason = nullString(pod.Status.Reason)
+			rows = append(rows, row)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Pod != rows[j].Pod {
+			return rows[i].Pod < rows[j].Pod
+		}
+		return rows[i].Container < rows[j].Container
+	})
+	return rows, nil
+}
+
+func newStepResourceRow(jobRunRow *jobrunaggregatorapi.JobRunRow, container corev1.Container, status corev1.ContainerStatus) jobrunaggregatorapi.StepResourceRow {
+	row := jobrunaggregatorapi.StepResourceRow{
+		JobRunName: jobRunRow.Name,
+		Container:  container.Name,
+		JobName:    nullString(jobRunRow.JobName),
+		JobRunStartTime: bigquery.NullTimestamp{
+			Timestamp: jobRunRow.StartTime,
+			Valid:     true,
+		},
+		JobRunEndTime: bigquery.NullTimestamp{
+			Timestamp: jobRunRow.EndTime,
+			Valid:     true,
+		},
+		Cluster:            nullString(jobRunRow.Cluster),
+		ReleaseTag:         nullString(jobRunRow.ReleaseTag),
+		JobRunStatus:       nullString(jobRunRow.Status),
+		MasterNodesUpdated: jobRunRow.MasterNodesUpdated,
+	}
+	if cpu, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
+		row.CPURequestMillicores = bigquery.NullInt64{Int64: cpu.MilliValue(), Valid: true}
+	}
+	if cpu, ok := container.Resources.Limits[corev1.ResourceCPU]; ok {
+		row.CPULimitMillicores = bigquery.NullInt64{Int64: cpu.MilliValue(), Valid: true}
+	}
+	if memory, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
+		row.MemoryRequestBytes = bigquery.NullInt64{Int64: memory.Value(), Valid: true}
+	}
+	if memory, ok := container.Resources.Limits[corev1.ResourceMemory]; ok {
+		row.MemoryLimitBytes = bigquery.NullInt64{Int64: memory.Value(), Valid: true}
+	}
+
+	if len(status.Name) > 0 {
+		row.Restarts = bigquery.NullInt64{Int64: int64(status.RestartCount), Valid: true}
+	}
+	if terminated := status.State.Terminated; terminated != nil {
+		row.ExitCode = bigquery.NullInt64{Int64: int64(terminated.ExitCode), Valid: true}
+		row.TerminationReason = nullString(terminated.Reason)
+		if !terminated.StartedAt.IsZero() && !terminated.FinishedAt.IsZero() {
+			row.DurationSeconds = bigquery.NullFloat64{Float64: terminated.FinishedAt.Sub(terminated.StartedAt.Time).Seconds(), Valid: true}
+		}
+	}
+	return row
+}
+
+func nullString(value string) bigquery.NullString {
+	return bigquery.NullString{StringVal: value, Valid: len(value) > 0}
+}
@@ -0,0 +1,165 @@
+package jobrunbigqueryloader
+
+import (
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
+)
+
+func TestGetStepResourceRows(t *testing.T) {
+	start := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	jobRunRow := &jobrunaggregatorapi.JobRunRow{
+		Name:       "1000",
+		JobName:    "periodic-ci-openshift-release-master-ci-4.14-e2e-aws-ovn",
+		Status:     "failure",
+		StartTime:  start,
+		EndTime:    start.Add(2 * time.Hour),
+		ReleaseTag: "4.14.0-0.ci-2023-06-01-094412",
+		Cluster:    "build01",
+	}
+	podsJSON := `{
+  "kind": "PodList",
+  "apiVersion": "v1",
+  "items": [
+    {
+      "metadata": {
+        "name": "e2e-aws-ovn-gather-extra",
+        "labels": {
+          "ci.openshift.io/metadata.step": "gather-extra",
+          "ci.openshift.io/multi-stage-test": "e2e-aws-ovn"
+        }
+      },
+      "spec": {
+        "containers": [
+          {
+            "name": "test",
+            "resources": {
+              "requests": {"cpu": "300m", "memory": "300Mi"},
+              "limits": {"memory": "1Gi"}
+            }
+          },
+          {
+            "name": "sidecar",
+            "resources": {
+              "requests": {"cpu": "100m"}
+            }
+          }
+        ]
+      },
+      "status": {
+        "containerStatuses": [
+          {
+            "name": "test",
+            "restartCount": 0,
+            "state": {
+              "terminated": {
+                "exitCode": 137,
+                "reason": "OOMKilled",
+                "startedAt": "2023-06-01T11:00:00Z",
+                "finishedAt": "2023-06-01T11:01:30Z"
+              }
+            }
+          },
+          {
+            "name": "sidecar",
+            "restartCount": 1,
+            "state": {
+              "terminated": {
+                "exitCode": 0,
+                "reason": "Completed",
+                "startedAt": "2023-06-01T11:00:00Z",
+                "finishedAt": "2023-06-01T11:01:31Z"
+              }
+            }
+          }
+        ]
+      }
+    },
+    {
+      "metadata": {
+        "name": "src-build",
+        "labels": {
+          "openshift.io/build.name": "src"
+        }
+      },
+      "spec": {
+        "containers": [
+          {
+            "name": "docker-build",
+            "resources": {}
+          }
+        ]
+      },
+      "status": {
+        "reason": "Evicted"
+      }
+    },
+    {
+      "metadata": {
+        "name": "release-images-latest-cli"
+      },
+      "spec": {
+        "containers": [
+          {
+            "name": "release"
+          }
+        ]
+      }
+    }
+  ]
+}`
+
+	jobRunFields := func(row jobrunaggregatorapi.StepResourceRow) jobrunaggregatorapi.StepResourceRow {
+		row.JobRunName = "1000"
+		row.JobName = bigquery.NullString{StringVal: "periodic-ci-openshift-release-master-ci-4.14-e2e-aws-ovn", Valid: true}
+		row.JobRunStartTime = bigquery.NullTimestamp{Timestamp: start, Valid: true}
+		row.JobRunEndTime = bigquery.NullTimestamp{Timestamp: start.Add(2 * time.Hour), Valid: true}
+		row.Cluster = bigquery.NullString{StringVal: "build01", Valid: true}
+		row.ReleaseTag = bigquery.NullString{StringVal: "4.14.0-0.ci-2023-06-01-094412", Valid: true}
+		row.JobRunStatus = bigquery.NullString{StringVal: "failure", Valid: true}
+		return row
+	}
+	expected := []jobrunaggregatorapi.StepResourceRow{
+		jobRunFields(jobrunaggregatorapi.StepResourceRow{
+			Test:                 bigquery.NullString{StringVal: "e2e-aws-ovn", Valid: true},
+			Step:                 "gather-extra",
+			Pod:                  "e2e-aws-ovn-gather-extra",
+			Container:            "sidecar",
+			CPURequestMillicores: bigquery.NullInt64{Int64: 100, Valid: true},
+			DurationSeconds:      bigquery.NullFloat64{Float64: 91, Valid: true},
+			ExitCode:             bigquery.NullInt64{Int64: 0, Valid: true},
+			TerminationReason:    bigquery.NullString{StringVal: "Completed", Valid: true},
+			Restarts:             bigquery.NullInt64{Int64: 1, Valid: true},
+		}),
+		jobRunFields(jobrunaggregatorapi.StepResourceRow{
+			Test:                 bigquery.NullString{StringVal: "e2e-aws-ovn", Valid: true},
+			Step:                 "gather-extra",
+			Pod:                  "e2e-aws-ovn-gather-extra",
+			Container:            "test",
+			CPURequestMillicores: bigquery.NullInt64{Int64: 300, Valid: true},
+			MemoryRequestBytes:   bigquery.NullInt64{Int64: 300 * 1024 * 1024, Valid: true},
+			MemoryLimitBytes:     bigquery.NullInt64{Int64: 1024 * 1024 * 1024, Valid: true},
+			DurationSeconds:      bigquery.NullFloat64{Float64: 90, Valid: true},
+			ExitCode:             bigquery.NullInt64{Int64: 137, Valid: true},
+			TerminationReason:    bigquery.NullString{StringVal: "OOMKilled", Valid: true},
+			Restarts:             bigquery.NullInt64{Int64: 0, Valid: true},
+		}),
+		jobRunFields(jobrunaggregatorapi.StepResourceRow{
+			Step:      "src",
+			Pod:       "src-build",
+			Container: "docker-build",
+			PodReason: bigquery.NullString{StringVal: "Evicted", Valid: true},
+		}),
+	}
+
+	rows, err := getStepResourceRows([]byte(podsJSON), jobRunRow)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, rows)
+
+	_, err = getStepResourceRows([]byte("not json"), jobRunRow)
+	assert.Error(t, err)
+}
@@ -70,5 +70,9 @@ func newTestRunRow(jobRunRow *jobrunaggregatorapi.JobRunRow, status string, test
 			Valid:     true,
 		},
 		MasterNodesUpdated: jobRunRow.MasterNodesUpdated,
+		DurationSeconds: bigquery.NullFloat64{
+			Float64: testCase.Duration,
+			Valid:   true,
+		},
 	}
 }
@@ -142,6 +142,7 @@ func (o *allJobsLoaderOptions) Run(ctx context.Context) error {
 			continue
 		}
 		jobRunsToImportCh <- jr
+		jobrunaggregatorlib.RecordJobRunsDiscovered(jr.JobName, 1)
 	}
 	close(jobRunsToImportCh)
 	runsToImportCount := len(jobRunsToImportCh)
@@ -0,0 +1,20 @@
+package jobrunquery
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorlib"
+)
+
+// NewQueryCommand groups the commands which print the data the aggregator uploads to BigQuery, instead of
+// hand-writing the queries in the BigQuery console.
+func NewQueryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query",
+		Short: "Query the job run data uploaded to BigQuery",
+		Args:  jobrunaggregatorlib.NoArgs,
+	}
+
+	cmd.AddCommand(NewTestHistoryCommand())
+	return cmd
+}
@@ -0,0 +1,279 @@
+package jobrunquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorlib"
+)
+
+const (
+	testHistoryDateLayout = "2006-01-02"
+
+	outputTable = "table"
+	outputJSON  = "json"
+
+	// defaultTestHistoryDays is the number of days the history covers when --from is not set
+	defaultTestHistoryDays = 14
+)
+
+type TestHistoryFlags struct {
+	DataCoordinates *jobrunaggregatorlib.BigQueryDataCoordinates
+	Authentication  *jobrunaggregatorlib.GoogleAuthenticationFlags
+
+	JobName  string
+	TestName string
+	From     string
+	To       string
+	Output   string
+}
+
+func NewTestHistoryFlags() *TestHistoryFlags {
+	now := time.Now().UTC()
+	return &TestHistoryFlags{
+		DataCoordinates: jobrunaggregatorlib.NewBigQueryDataCoordinates(),
+		Authentication:  jobrunaggregatorlib.NewGoogleAuthenticationFlags(),
+		From:            now.AddDate(0, 0, 1-defaultTestHistoryDays).Format(testHistoryDateLayout),
+		To:              now.Format(testHistoryDateLayout),
+		Output:          outputTable,
+	}
+}
+
+func (f *TestHistoryFlags) BindFlags(fs *pflag.FlagSet) {
+	f.DataCoordinates.BindFlags(fs)
+	f.Authentication.BindFlags(fs)
+
+	fs.StringVar(&f.JobName, "job", f.JobName, "The name of the job to query the history of the test in, like periodic-ci-openshift-release-master-ci-4.9-e2e-gcp-upgrade")
+	fs.StringVar(&f.TestName, "test", f.TestName, "The full name of the test to query the history of")
+	fs.StringVar(&f.From, "from", f.From, fmt.Sprintf("The first day of the history, like %s. Defaults to %d days ago.", testHistoryDateLayout, defaultTestHistoryDays-1))
+	fs.StringVar(&f.To, "to", f.To, fmt.Sprintf("The last day of the history, like %s. Defaults to today.", testHistoryDateLayout))
+	fs.StringVar(&f.Output, "output", f.Output, fmt.Sprintf("The format the history is printed in: %s or %s", outputTable, outputJSON))
+}
+
+func NewTestHistoryCommand() *cobra.Command {
+	f := NewTestHistoryFlags()
+
+	cmd := &cobra.Command{
+		Use: "test-history",
+		Long: `Print how many times a test passed, failed and flaked in the job runs of a job, and how long it took,
+per day and over the whole window.`,
+		SilenceUsage: true,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			if err := f.Validate(); err != nil {
+				logrus.WithError(err).Fatal("Flags are invalid")
+			}
+			o, err := f.ToOptions(ctx)
+			if err != nil {
+				logrus.WithError(err).Fatal("Failed to build runtime options")
+			}
+
+			if err := o.Run(ctx); err != nil {
+				logrus.WithError(err).Fatal("Command failed")
+			}
+
+			return nil
+		},
+
+		Args: jobrunaggregatorlib.NoArgs,
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+// Validate checks to see if the user-input is likely to produce functional runtime options
+func (f *TestHistoryFlags) Validate() error {
+	if len(f.JobName) == 0 {
+		return fmt.Errorf("missing --job: like periodic-ci-openshift-release-master-ci-4.9-e2e-gcp-upgrade")
+	}
+	if len(f.TestName) == 0 {
+		return fmt.Errorf("missing --test")
+	}
+	from, err := time.Parse(testHistoryDateLayout, f.From)
+	if err != nil {
+		return fmt.Errorf("invalid --from: %w", err)
+	}
+	to, err := time.Parse(testHistoryDateLayout, f.To)
+	if err != nil {
+		return fmt.Errorf("invalid --to: %w", err)
+	}
+	if to.Before(from) {
+		return fmt.Errorf("--to must not be before --from")
+	}
+	if f.Output != outputTable && f.Output != outputJSON {
+		return fmt.Errorf("invalid --output %q: must be %s or %s", f.Output, outputTable, outputJSON)
+	}
+	if err := f.DataCoordinates.Validate(); err != nil {
+		return err
+	}
+	return f.Authentication.Validate()
+}
+
+// ToOptions goes from the user input to the runtime values need to run the command.
+func (f *TestHistoryFlags) ToOptions(ctx context.Context) (*testHistoryOptions, error) {
+	from, err := time.Parse(testHistoryDateLayout, f.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := time.Parse(testHistoryDateLayout, f.To)
+	if err != nil {
+		return nil, err
+	}
+
+	bigQueryClient, err := f.Authentication.NewBigQueryClient(ctx, f.DataCoordinates.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	ciDataClient := jobrunaggregatorlib.NewRetryingCIDataClient(
+		jobrunaggregatorlib.NewCIDataClient(*f.DataCoordinates, bigQueryClient),
+	)
+
+	return &testHistoryOptions{
+		jobName:      f.JobName,
+		testName:     f.TestName,
+		from:         from,
+		to:           to.Add(24 * time.Hour),
+		output:       f.Output,
+		ciDataClient: ciDataClient,
+		out:          os.Stdout,
+	}, nil
+}
+
+// testHistoryOptions prints the history of a test in the job runs of a job
+type testHistoryOptions struct {
+	jobName  string
+	testName string
+	// from and to bound the start of the job runs, to is exclusive
+	from   time.Time
+	to     time.Time
+	output string
+
+	ciDataClient jobrunaggregatorlib.TestHistoryClient
+	out          io.Writer
+}
+
+// testHistory is the printed history of a test
+type testHistory struct {
+	JobName  string    `json:"jobName"`
+	TestName string    `json:"testName"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	// Days holds the days with job runs, oldest first
+	Days  []testHistoryCounts `json:"days"`
+	Total testHistoryCounts   `json:"total"`
+}
+
+// testHistoryCounts sums up the runs of the test on a day or over the whole window
+type testHistoryCounts struct {
+	Day            string  `json:"day,omitempty"`
+	Passes         int64   `json:"passes"`
+	Failures       int64   `json:"failures"`
+	Flakes         int64   `json:"flakes"`
+	PassPercentage float64 `json:"passPercentage"`
+	// the durations are unset when none of the runs of the test recorded its duration
+	MeanDurationSeconds *float64 `json:"meanDurationSeconds,omitempty"`
+	P50DurationSeconds  *float64 `json:"p50DurationSeconds,omitempty"`
+	P95DurationSeconds  *float64 `json:"p95DurationSeconds,omitempty"`
+	MaxDurationSeconds  *float64 `json:"maxDurationSeconds,omitempty"`
+}
+
+func (o *testHistoryOptions) Run(ctx context.Context) error {
+	rows, err := o.ciDataClient.GetTestHistory(ctx, o.jobName, o.testName, o.from, o.to)
+	if err != nil {
+		return fmt.Errorf("failed to query the history of %q in %s: %w", o.testName, o.jobName, err)
+	}
+	history := newTestHistory(o.jobName, o.testName, o.from, o.to, rows)
+
+	switch o.output {
+	case outputJSON:
+		encoder := json.NewEncoder(o.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(history)
+	default:
+		return writeTestHistoryTable(o.out, history)
+	}
+}
+
+func newTestHistory(jobName, testName string, from, to time.Time, rows []jobrunaggregatorapi.TestHistoryRow) testHistory {
+	history := testHistory{
+		JobName:  jobName,
+		TestName: testName,
+		From:     from,
+		To:       to,
+		Days:     []testHistoryCounts{},
+	}
+	for _, row := range rows {
+		if !row.Day.Valid {
+			history.Total = newTestHistoryCounts(row)
+			continue
+		}
+		history.Days = append(history.Days, newTestHistoryCounts(row))
+	}
+	return history
+}
+
+func newTestHistoryCounts(row jobrunaggregatorapi.TestHistoryRow) testHistoryCounts {
+	counts := testHistoryCounts{
+		Passes:              row.PassCount,
+		Failures:            row.FailCount,
+		Flakes:              row.FlakeCount,
+		MeanDurationSeconds: nullableSeconds(row.MeanDurationSeconds),
+		P50DurationSeconds:  nullableSeconds(row.P50DurationSeconds),
+		P95DurationSeconds:  nullableSeconds(row.P95DurationSeconds),
+		MaxDurationSeconds:  nullableSeconds(row.MaxDurationSeconds),
+	}
+	if row.Day.Valid {
+		counts.Day = row.Day.Date.String()
+	}
+	if runs := counts.Passes + counts.Failures + counts.Flakes; runs > 0 {
+		counts.PassPercentage = float64(counts.Passes) * 100 / float64(runs)
+	}
+	return counts
+}
+
+func nullableSeconds(seconds bigquery.NullFloat64) *float64 {
+	if !seconds.Valid {
+		return nil
+	}
+	return &seconds.Float64
+}
+
+func writeTestHistoryTable(out io.Writer, history testHistory) error {
+	fmt.Fprintf(out, "%s in %s from %s to %s\n\n", history.TestName, history.JobName,
+		history.From.Format(testHistoryDateLayout), history.To.Add(-24*time.Hour).Format(testHistoryDateLayout))
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "DAY\tPASSED\tFAILED\tFLAKED\tPASS %\tMEAN\tP50\tP95\tMAX")
+	for _, day := range history.Days {
+		writeTestHistoryCounts(w, day.Day, day)
+	}
+	writeTestHistoryCounts(w, "total", history.Total)
+	return w.Flush()
+}
+
+func writeTestHistoryCounts(w io.Writer, name string, counts testHistoryCounts) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\n", name, counts.Passes, counts.Failures, counts.Flakes, counts.PassPercentage,
+		formatSeconds(counts.MeanDurationSeconds), formatSeconds(counts.P50DurationSeconds), formatSeconds(counts.P95DurationSeconds), formatSeconds(counts.MaxDurationSeconds))
+}
+
+func formatSeconds(seconds *float64) string {
+	if seconds == nil {
+		return "-"
+	}
+	return (time.Duration(*seconds * float64(time.Second))).Round(time.Second).String()
+}
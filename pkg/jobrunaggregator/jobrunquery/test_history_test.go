@@ -0,0 +1,111 @@
+package jobrunquery
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorlib"
+)
+
+func seconds(value float64) bigquery.NullFloat64 {
+	return bigquery.NullFloat64{Float64: value, Valid: true}
+}
+
+func TestTestHistory(t *testing.T) {
+	from := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(48 * time.Hour)
+	rows := []jobrunaggregatorapi.TestHistoryRow{
+		{
+			Day:                 bigquery.NullDate{Date: civil.DateOf(from), Valid: true},
+			PassCount:           3,
+			FailCount:           1,
+			MeanDurationSeconds: seconds(61),
+			P50DurationSeconds:  seconds(60),
+			P95DurationSeconds:  seconds(64.4),
+			MaxDurationSeconds:  seconds(65),
+		},
+		{
+			Day:        bigquery.NullDate{Date: civil.DateOf(from.Add(24 * time.Hour)), Valid: true},
+			PassCount:  1,
+			FlakeCount: 1,
+		},
+		{
+			PassCount:           4,
+			FailCount:           1,
+			FlakeCount:          1,
+			MeanDurationSeconds: seconds(61),
+			P50DurationSeconds:  seconds(60),
+			P95DurationSeconds:  seconds(64.4),
+			MaxDurationSeconds:  seconds(65),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{
+			name:   "table",
+			output: outputTable,
+			expected: `test in job from 2023-06-01 to 2023-06-02
+
+DAY         PASSED  FAILED  FLAKED  PASS %  MEAN  P50   P95   MAX
+2023-06-01  3       1       0       75.0    1m1s  1m0s  1m4s  1m5s
+2023-06-02  1       0       1       50.0    -     -     -     -
+total       4       1       1       66.7    1m1s  1m0s  1m4s  1m5s
+`,
+		},
+		{
+			name:   "json",
+			output: outputJSON,
+			expected: `{
+  "jobName": "job",
+  "testName": "test",
+  "from": "2023-06-01T00:00:00Z",
+  "to": "2023-06-03T00:00:00Z",
+  "days": [
+    {"day": "2023-06-01", "passes": 3, "failures": 1, "flakes": 0, "passPercentage": 75,
+     "meanDurationSeconds": 61, "p50DurationSeconds": 60, "p95DurationSeconds": 64.4, "maxDurationSeconds": 65},
+    {"day": "2023-06-02", "passes": 1, "failures": 0, "flakes": 1, "passPercentage": 50}
+  ],
+  "total": {"passes": 4, "failures": 1, "flakes": 1, "passPercentage": 66.66666666666667,
+    "meanDurationSeconds": 61, "p50DurationSeconds": 60, "p95DurationSeconds": 64.4, "maxDurationSeconds": 65}
+}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDataClient := jobrunaggregatorlib.NewMockCIDataClient(mockCtrl)
+			mockDataClient.EXPECT().GetTestHistory(gomock.Any(), "job", "test", from, to).Return(rows, nil).Times(1)
+
+			out := &bytes.Buffer{}
+			o := &testHistoryOptions{
+				jobName:      "job",
+				testName:     "test",
+				from:         from,
+				to:           to,
+				output:       tc.output,
+				ciDataClient: mockDataClient,
+				out:          out,
+			}
+			assert.NoError(t, o.Run(context.Background()))
+			if tc.output == outputJSON {
+				assert.JSONEq(t, tc.expected, out.String())
+				return
+			}
+			assert.Equal(t, tc.expected, out.String())
+		})
+	}
+}
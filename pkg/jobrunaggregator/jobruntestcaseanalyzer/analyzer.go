@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
 
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -424,7 +425,7 @@ func (o *JobRunTestCaseAnalyzerOptions) shouldAggregateJob(prowJob *prowjobv1.Pr
 		prowJobRunMatcherFunc = jobrunaggregatorlib.NewProwJobMatcherFuncForReleaseController(jobName, o.payloadTag)
 	}
 	if len(o.payloadInvocationID) > 0 {
-		prowJobRunMatcherFunc = jobrunaggregatorlib.NewProwJobMatcherFuncForPR(jobName, o.payloadInvocationID, jobrunaggregatorlib.ProwJobPayloadInvocationIDLabel)
+		prowJobRunMatcherFunc = jobrunaggregatorlib.NewProwJobMatcherFuncForPR(jobName, o.payloadInvocationID, jobrunaggregatorlib.ProwJobAggregationIDLabel)
 	}
 
 	if prowJobRunMatcherFunc != nil {
@@ -441,21 +442,22 @@ func (o *JobRunTestCaseAnalyzerOptions) findJobRunsWithRetry(ctx context.Context
 		return o.loadStaticJobRuns(ctx, jobName, jobRunLocator)
 	}
 
+	logger := logrus.WithField("jobName", jobName)
 	errorsInARow := 0
 	for {
 		jobRuns, err := jobRunLocator.FindRelatedJobs(ctx)
 		if err != nil {
 			if errorsInARow > 20 {
-				fmt.Printf("give up finding job runs for %s after retries: %v", jobName, err)
+				logger.WithError(err).Error("Giving up finding job runs after retries.")
 				return nil, err
 			}
 			errorsInARow++
-			fmt.Printf("error finding job runs for %s: %v", jobName, err)
+			logger.WithError(err).Warn("Failed to find job runs.")
 		} else {
 			return jobRuns, nil
 		}
 
-		fmt.Printf("   waiting and will attempt to find related jobs for %s in a minute\n", jobName)
+		logger.Info("Will attempt to find related job runs again in a minute.")
 		select {
 		case <-ctx.Done():
 			// Simply return. Caller will check ctx and return error
@@ -542,7 +544,7 @@ func (o *JobRunTestCaseAnalyzerOptions) GetRelatedJobRuns(ctx context.Context) (
 			jobRunLocator = jobrunaggregatorlib.NewPayloadAnalysisJobLocatorForPR(
 				job.JobName,
 				o.payloadInvocationID,
-				jobrunaggregatorlib.ProwJobPayloadInvocationIDLabel,
+				jobrunaggregatorlib.ProwJobAggregationIDLabel,
 				o.jobRunStartEstimate,
 				o.ciDataClient,
 				o.ciGCSClient,
@@ -551,7 +553,7 @@ func (o *JobRunTestCaseAnalyzerOptions) GetRelatedJobRuns(ctx context.Context) (
 			)
 		}
 
-		fmt.Printf("  launching findJobRunsWithRetry for %q\n", job.JobName)
+		logrus.WithField("jobName", job.JobName).Debug("Finding job runs.")
 
 		waitGroup.Add(1)
 
@@ -13,6 +13,9 @@ import (
 type BigQueryTablesCreateFlags struct {
 	DataCoordinates *jobrunaggregatorlib.BigQueryDataCoordinates
 	Authentication  *jobrunaggregatorlib.GoogleAuthenticationFlags
+
+	// DryRun is only used by migrate-schema
+	DryRun bool
 }
 
 func NewBigQueryTablesCreateFlags() *BigQueryTablesCreateFlags {
@@ -32,8 +35,8 @@ func NewBigQueryCreateTablesFlagsCommand() *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:          "create-tables",
-		Short:        "Create Jobs table in bigquery",
-		Long:         "Create Jobs table in bigquery",
+		Short:        "Create the dataset and the tables in bigquery",
+		Long:         "Create the dataset and the tables the aggregator uses in bigquery, with the latest version of their schema",
 		SilenceUsage: false,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -91,3 +94,43 @@ func (f *BigQueryTablesCreateFlags) ToOptions(ctx context.Context) (*allJobsTabl
 		ciDataSet:    ciDataSet,
 	}, nil
 }
+
+func NewBigQueryMigrateSchemaFlagsCommand() *cobra.Command {
+	f := NewBigQueryTablesCreateFlags()
+
+	cmd := &cobra.Command{
+		Use:          "migrate-schema",
+		Short:        "Migrate the tables in bigquery to the latest version of their schema",
+		Long:         "Add the columns of the newer versions of their schema to the tables the aggregator uses in bigquery",
+		SilenceUsage: false,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			if err := f.Validate(); err != nil {
+				logrus.WithError(err).Fatal("Flags are invalid")
+			}
+			bigQueryClient, err := f.Authentication.NewBigQueryClient(ctx, f.DataCoordinates.ProjectID)
+			if err != nil {
+				logrus.WithError(err).Fatal("Failed to build runtime options")
+			}
+			o := &schemaMigratorOptions{
+				ciDataSet: bigQueryClient.Dataset(f.DataCoordinates.DataSetID),
+				dryRun:    f.DryRun,
+			}
+
+			if err := o.Run(ctx); err != nil {
+				logrus.WithError(err).Fatal("Command failed")
+			}
+
+			return nil
+		},
+
+		Args: jobrunaggregatorlib.NoArgs,
+	}
+
+	f.BindFlags(cmd.Flags())
+	cmd.Flags().BoolVar(&f.DryRun, "dry-run", f.DryRun, "Only print the columns which would be added.")
+
+	return cmd
+}
@@ -0,0 +1,159 @@
+package tablescreator
+
+import (
+	"fmt"
+	"strconv"
+
+	"cloud.google.com/go/bigquery"
+
+	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
+)
+
+// schemaVersionLabel is the label of a table holding the version of its schema. Tables without it
+// have the first version.
+const schemaVersionLabel = "schema-version"
+
+const (
+	jobNameColumn = `
+[
+  {
+    "name": "JobName",
+    "description": "name of the job from CI",
+    "type": "STRING",
+    "mode": "NULLABLE"
+  }
+]
+`
+
+	// jobRunColumns describe the job run in the rows of its tests, disruption and alerts, so that
+	// they can be queried without joining the job runs
+	jobRunColumns = `
+[
+  {
+    "name": "JobRunStartTime",
+    "description": "time the jobrun started",
+    "type": "TIMESTAMP",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "JobRunEndTime",
+    "description": "time the jobrun ended",
+    "type": "TIMESTAMP",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "Cluster",
+    "description": "the build farm cluster that the CI job ran on: build01, build02, build03, vsphere, etc",
+    "type": "STRING",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "ReleaseTag",
+    "description": "the payload tested by the jobrun",
+    "type": "STRING",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "MasterNodesUpdated",
+    "description": "indicator if master nodes restarted during the jobrun",
+    "type": "STRING",
+    "mode": "NULLABLE"
+  },
+  {
+    "name": "JobRunStatus",
+    "description": "error, failure, success",
+    "type": "STRING",
+    "mode": "NULLABLE"
+  }
+]
+`
+
+	// testDurationColumn holds how long the test ran, so that the history of a test tells how long it takes
+	testDurationColumn = `
+[
+  {
+    "name": "DurationSeconds",
+    "description": "how long the test ran, in seconds",
+    "type": "FLOAT",
+    "mode": "NULLABLE"
+  }
+]
+`
+)
+
+// schemaVersion holds the JSON schemas of the columns a version of the schema of a table adds to the previous one
+type schemaVersion []string
+
+// tableSchemaVersions are the versions of the schemas of the tables managed by create-tables and
+// migrate-schema, oldest first. Only NULLABLE columns can be added to an existing table, so every
+// version but the first one may only add those. To change a schema, append a version to its table.
+var tableSchemaVersions = map[string][]schemaVersion{
+	jobrunaggregatorapi.JobsTableName:               {{jobrunaggregatorapi.JobSchema}},
+	jobrunaggregatorapi.LegacyJobRunTableName:       {{jobrunaggregatorapi.JobRunSchema}},
+	jobrunaggregatorapi.DisruptionJobRunTableName:   {{jobrunaggregatorapi.JobRunSchema}},
+	jobrunaggregatorapi.AlertJobRunTableName:        {{jobrunaggregatorapi.JobRunSchema}},
+	jobrunaggregatorapi.TestRunTableName:            {{jobrunaggregatorapi.TestRunsSchema}, {jobRunColumns}, {testDurationColumn}},
+	jobrunaggregatorapi.BackendDisruptionTableName:  {{jobrunaggregatorapi.BackendDisruptionSchema}, {jobNameColumn, jobRunColumns}},
+	jobrunaggregatorapi.AlertsTableName:             {{jobrunaggregatorapi.AlertSchema}, {jobNameColumn, jobRunColumns}},
+	jobrunaggregatorapi.AggregationRunsTableName:    {{jobrunaggregatorapi.AggregationRunSchema}},
+	jobrunaggregatorapi.StepResourceJobRunTableName: {{jobrunaggregatorapi.JobRunSchema}},
+	jobrunaggregatorapi.StepResourceTableName:       {{jobrunaggregatorapi.StepResourceSchema, jobNameColumn, jobRunColumns}},
+}
+
+// latestSchemaVersion is the version of the schema of the table created by create-tables
+func latestSchemaVersion(table string) int {
+	return len(tableSchemaVersions[table])
+}
+
+// schemaVersionOf is the version of the schema of an existing table
+func schemaVersionOf(metadata *bigquery.TableMetadata) (int, error) {
+	label, ok := metadata.Labels[schemaVersionLabel]
+	if !ok {
+		return 1, nil
+	}
+	version, err := strconv.Atoi(label)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s label %q: %w", schemaVersionLabel, label, err)
+	}
+	return version, nil
+}
+
+// columnsAddedAfter are the columns the versions after the given one add to the schema of the table
+func columnsAddedAfter(table string, version int) (bigquery.Schema, error) {
+	var columns bigquery.Schema
+	versions := tableSchemaVersions[table]
+	for i := version; i < len(versions); i++ {
+		for _, columnsJSON := range versions[i] {
+			schema, err := bigquery.SchemaFromJSON([]byte(columnsJSON))
+			if err != nil {
+				return nil, fmt.Errorf("invalid version %d of the schema of %s: %w", i+1, table, err)
+			}
+			columns = append(columns, schema...)
+		}
+	}
+	return columns, nil
+}
+
+// migratedSchema adds the columns of the versions after the given one to the schema of the table,
+// skipping the columns it already has
+func migratedSchema(table string, current bigquery.Schema, version int) (bigquery.Schema, error) {
+	added, err := columnsAddedAfter(table, version)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]bool{}
+	for _, column := range current {
+		existing[column.Name] = true
+	}
+	migrated := append(bigquery.Schema{}, current...)
+	for _, column := range added {
+		if existing[column.Name] {
+			continue
+		}
+		if column.Required {
+			return nil, fmt.Errorf("column %s cannot be added to %s: only NULLABLE columns can be added", column.Name, table)
+		}
+		migrated = append(migrated, column)
+	}
+	return migrated, nil
+}
@@ -0,0 +1,109 @@
+package tablescreator
+
+import (
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableSchemaVersions(t *testing.T) {
+	for table, versions := range tableSchemaVersions {
+		t.Run(table, func(t *testing.T) {
+			if len(versions) == 0 {
+				t.Fatalf("table has no schema")
+			}
+			columns := map[string]bool{}
+			for i := 0; i < len(versions); i++ {
+				added, err := columnsAddedAfter(table, i)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				later, err := columnsAddedAfter(table, i+1)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				// the columns of version i+1 come before the ones of the later versions
+				for _, column := range added[:len(added)-len(later)] {
+					if columns[column.Name] {
+						t.Errorf("version %d adds the column %s again", i+1, column.Name)
+					}
+					columns[column.Name] = true
+					if i > 0 && column.Required {
+						t.Errorf("version %d adds the REQUIRED column %s", i+1, column.Name)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaVersionOf(t *testing.T) {
+	testCases := []struct {
+		name     string
+		labels   map[string]string
+		expected int
+		wantErr  bool
+	}{
+		{
+			name:     "tables without the label have the first version",
+			expected: 1,
+		},
+		{
+			name:     "the label holds the version",
+			labels:   map[string]string{schemaVersionLabel: "2"},
+			expected: 2,
+		},
+		{
+			name:    "invalid label",
+			labels:  map[string]string{schemaVersionLabel: "two"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := schemaVersionOf(&bigquery.TableMetadata{Labels: tc.labels})
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestMigratedSchema(t *testing.T) {
+	tableSchemaVersions["test"] = []schemaVersion{
+		{`[{"name": "Name", "type": "STRING", "mode": "REQUIRED"}]`},
+		{`[{"name": "Cluster", "type": "STRING", "mode": "NULLABLE"}]`, `[{"name": "Status", "type": "STRING", "mode": "NULLABLE"}]`},
+		{`[{"name": "Required", "type": "STRING", "mode": "REQUIRED"}]`},
+	}
+	defer delete(tableSchemaVersions, "test")
+
+	current := bigquery.Schema{
+		{Name: "Name", Type: bigquery.StringFieldType, Required: true},
+		// added by hand before the schema was versioned
+		{Name: "Status", Type: bigquery.StringFieldType},
+	}
+	if _, err := migratedSchema("test", current, 1); err == nil {
+		t.Errorf("expected an error adding a REQUIRED column")
+	}
+
+	tableSchemaVersions["test"] = tableSchemaVersions["test"][:2]
+	actual, err := migratedSchema("test", current, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, column := range actual {
+		names = append(names, column.Name)
+	}
+	assert.Equal(t, []string{"Name", "Status", "Cluster"}, names)
+
+	actual, err = migratedSchema("test", current, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, current, actual)
+}
@@ -2,12 +2,17 @@ package tablescreator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"strconv"
 
 	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+
+	"k8s.io/apimachinery/pkg/util/sets"
 
-	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorapi"
 	"github.com/openshift/ci-tools/pkg/jobrunaggregator/jobrunaggregatorlib"
 )
 
@@ -16,30 +21,98 @@ type allJobsTableCreatorOptions struct {
 	ciDataSet    *bigquery.Dataset
 }
 
+// Run creates the dataset and the tables with the latest version of their schema, existing tables
+// are left alone and migrated by migrate-schema
 func (r *allJobsTableCreatorOptions) Run(ctx context.Context) error {
+	if _, err := r.ciDataSet.Metadata(ctx); err != nil {
+		if !isNotFound(err) {
+			return fmt.Errorf("failed to get the dataset %s: %w", r.ciDataSet.DatasetID, err)
+		}
+		if err := r.ciDataSet.Create(ctx, &bigquery.DatasetMetadata{}); err != nil {
+			return fmt.Errorf("failed to create the dataset %s: %w", r.ciDataSet.DatasetID, err)
+		}
+		fmt.Fprintf(os.Stdout, "created dataset: %s\n", r.ciDataSet.DatasetID)
+	}
 
-	tableNamesToSchemas := map[string]string{
-		jobrunaggregatorlib.JobsTableName:    jobrunaggregatorapi.JobSchema,
-		jobrunaggregatorlib.TestRunTableName: jobrunaggregatorapi.TestRunsSchema,
-		jobrunaggregatorlib.JobRunTableName:  jobrunaggregatorapi.JobRunSchema,
+	for _, tableName := range sets.List(sets.KeySet(tableSchemaVersions)) {
+		bqTable := r.ciDataSet.Table(tableName)
+		metadata, err := bqTable.Metadata(ctx)
+		if err == nil {
+			version, err := schemaVersionOf(metadata)
+			if err != nil {
+				return fmt.Errorf("table %s: %w", tableName, err)
+			}
+			fmt.Fprintf(os.Stdout, "table already exists: %s (schema version %d of %d)\n", tableName, version, latestSchemaVersion(tableName))
+			continue
+		}
+		if !isNotFound(err) {
+			return fmt.Errorf("failed to get the table %s: %w", tableName, err)
+		}
+
+		schema, err := columnsAddedAfter(tableName, 0)
+		if err != nil {
+			return err
+		}
+		if err := bqTable.Create(ctx, &bigquery.TableMetadata{
+			Schema: schema,
+			Labels: map[string]string{schemaVersionLabel: strconv.Itoa(latestSchemaVersion(tableName))},
+		}); err != nil {
+			return fmt.Errorf("failed to create the table %s: %w", tableName, err)
+		}
+		fmt.Fprintf(os.Stdout, "created table: %s\n", tableName)
 	}
+	return nil
+}
 
-	for tableName, tableSchema := range tableNamesToSchemas {
+type schemaMigratorOptions struct {
+	ciDataSet *bigquery.Dataset
+	dryRun    bool
+}
 
-		// Create the table
+// Run adds the columns of the newer versions of their schema to the existing tables
+func (r *schemaMigratorOptions) Run(ctx context.Context) error {
+	for _, tableName := range sets.List(sets.KeySet(tableSchemaVersions)) {
 		bqTable := r.ciDataSet.Table(tableName)
-		_, err := bqTable.Metadata(ctx)
+		metadata, err := bqTable.Metadata(ctx)
 		if err != nil {
-			schema, err := bigquery.SchemaFromJSON([]byte(tableSchema))
-			if err != nil {
-				return err
+			if isNotFound(err) {
+				fmt.Fprintf(os.Stdout, "table does not exist, run create-tables: %s\n", tableName)
+				continue
 			}
-			if err := bqTable.Create(ctx, &bigquery.TableMetadata{Schema: schema}); err != nil {
-				return err
-			}
-		} else {
-			fmt.Fprintf(os.Stdout, "table already exists: %s\n", tableName)
+			return fmt.Errorf("failed to get the table %s: %w", tableName, err)
+		}
+		version, err := schemaVersionOf(metadata)
+		if err != nil {
+			return fmt.Errorf("table %s: %w", tableName, err)
+		}
+		latest := latestSchemaVersion(tableName)
+		if version >= latest {
+			fmt.Fprintf(os.Stdout, "table is up to date: %s (schema version %d)\n", tableName, version)
+			continue
+		}
+
+		schema, err := migratedSchema(tableName, metadata.Schema, version)
+		if err != nil {
+			return err
+		}
+		for _, column := range schema[len(metadata.Schema):] {
+			fmt.Fprintf(os.Stdout, "table %s: adding column %s\n", tableName, column.Name)
+		}
+		if r.dryRun {
+			fmt.Fprintf(os.Stdout, "table %s: would migrate the schema from version %d to %d\n", tableName, version, latest)
+			continue
 		}
+		update := bigquery.TableMetadataToUpdate{Schema: schema}
+		update.SetLabel(schemaVersionLabel, strconv.Itoa(latest))
+		if _, err := bqTable.Update(ctx, update, metadata.ETag); err != nil {
+			return fmt.Errorf("failed to migrate the schema of the table %s: %w", tableName, err)
+		}
+		fmt.Fprintf(os.Stdout, "table %s: migrated the schema from version %d to %d\n", tableName, version, latest)
 	}
 	return nil
 }
+
+func isNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
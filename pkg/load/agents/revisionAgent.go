@@ -0,0 +1,211 @@
+package agents
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/config"
+	"github.com/openshift/ci-tools/pkg/load"
+	"github.com/openshift/ci-tools/pkg/registry"
+)
+
+// revisionCacheSize is the number of registry revisions kept in memory
+const revisionCacheSize = 10
+
+var revisionRegex = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// notFoundError is returned when the revision or the configuration at that
+// revision does not exist, as opposed to a failure to load or resolve it
+type notFoundError struct {
+	err error
+}
+
+func (e *notFoundError) Error() string {
+	return e.err.Error()
+}
+
+func (e *notFoundError) Unwrap() error {
+	return e.err
+}
+
+// NotFound marks the error as caused by a missing revision or configuration
+func (e *notFoundError) NotFound() bool {
+	return true
+}
+
+// RevisionAgent resolves ci-operator configurations as they were at a given
+// revision of the release repository.
+type RevisionAgent interface {
+	// ResolveConfigAtRevision loads the configuration matching the metadata from
+	// the given revision and resolves it with the registry of that same revision.
+	ResolveConfigAtRevision(metadata api.Metadata, revision string) (api.ReleaseBuildConfiguration, error)
+}
+
+type revisionAgent struct {
+	repoPath string
+	flags    load.RegistryFlag
+
+	lock      sync.Mutex
+	resolvers map[string]registry.Resolver
+	order     []string
+}
+
+// NewRevisionAgent returns a RevisionAgent that reads historical content from
+// the git repository at repoPath.
+func NewRevisionAgent(repoPath string, flatRegistry bool) RevisionAgent {
+	var flags load.RegistryFlag
+	if flatRegistry {
+		flags |= load.RegistryFlat
+	}
+	return &revisionAgent{
+		repoPath:  repoPath,
+		flags:     flags,
+		resolvers: map[string]registry.Resolver{},
+	}
+}
+
+func (a *revisionAgent) ResolveConfigAtRevision(metadata api.Metadata, revision string) (api.ReleaseBuildConfiguration, error) {
+	if !revisionRegex.MatchString(revision) {
+		return api.ReleaseBuildConfiguration{}, &notFoundError{err: fmt.Errorf("revision %q is not a git SHA", revision)}
+	}
+	sha, err := a.git("rev-parse", "--verify", "--quiet", revision+"^{commit}")
+	if err != nil {
+		return api.ReleaseBuildConfiguration{}, &notFoundError{err: fmt.Errorf("could not find revision %s: %w", revision, err)}
+	}
+	sha = strings.TrimSpace(sha)
+
+	tmpDir, err := os.MkdirTemp("", "revision")
+	if err != nil {
+		return api.ReleaseBuildConfiguration{}, fmt.Errorf("could not create temporary directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			logrus.WithError(err).Warn("failed to remove temporary directory")
+		}
+	}()
+
+	repoConfigPath := filepath.Join(config.CiopConfigInRepoPath, metadata.Org, metadata.Repo)
+	if _, err := a.git("cat-file", "-e", sha+":"+repoConfigPath); err != nil {
+		return api.ReleaseBuildConfiguration{}, &notFoundError{err: fmt.Errorf("could not find any config for repo %s/%s at %s", metadata.Org, metadata.Repo, sha)}
+	}
+	if err := a.extract(sha, repoConfigPath, tmpDir); err != nil {
+		return api.ReleaseBuildConfiguration{}, fmt.Errorf("could not read configuration for %s/%s at %s: %w", metadata.Org, metadata.Repo, sha, err)
+	}
+	configs, err := config.LoadByOrgRepo(filepath.Join(tmpDir, config.CiopConfigInRepoPath))
+	if err != nil {
+		return api.ReleaseBuildConfiguration{}, fmt.Errorf("could not load configuration at %s: %w", sha, err)
+	}
+	unresolved, err := NewFakeConfigAgent(configs).GetMatchingConfig(metadata)
+	if err != nil {
+		return api.ReleaseBuildConfiguration{}, &notFoundError{err: fmt.Errorf("at %s: %w", sha, err)}
+	}
+
+	resolver, err := a.resolverAt(sha, tmpDir)
+	if err != nil {
+		return api.ReleaseBuildConfiguration{}, err
+	}
+	return registry.ResolveConfig(resolver, unresolved)
+}
+
+// resolverAt returns a resolver for the registry at the given revision, loading
+// it into dir if it is not cached yet. The registry is loaded without holding the
+// lock so that a slow load does not block requests for cached revisions.
+func (a *revisionAgent) resolverAt(sha, dir string) (registry.Resolver, error) {
+	a.lock.Lock()
+	resolver, ok := a.resolvers[sha]
+	a.lock.Unlock()
+	if ok {
+		return resolver, nil
+	}
+
+	if err := a.extract(sha, config.RegistryPath, dir); err != nil {
+		return nil, fmt.Errorf("could not read registry at %s: %w", sha, err)
+	}
+	references, chains, workflows, _, _, observers, err := load.Registry(filepath.Join(dir, config.RegistryPath), a.flags)
+	if err != nil {
+		return nil, fmt.Errorf("could not load registry at %s: %w", sha, err)
+	}
+	resolver = registry.NewResolver(references, chains, workflows, observers)
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if cached, ok := a.resolvers[sha]; ok {
+		// another request loaded the same revision in the meantime
+		return cached, nil
+	}
+	if len(a.order) == revisionCacheSize {
+		delete(a.resolvers, a.order[0])
+		a.order = a.order[1:]
+	}
+	a.resolvers[sha] = resolver
+	a.order = append(a.order, sha)
+	return resolver, nil
+}
+
+// extract writes the content of path at the given revision into dir
+func (a *revisionAgent) extract(sha, path, dir string) error {
+	out, err := a.git("archive", "--format=tar", sha, "--", path)
+	if err != nil {
+		return err
+	}
+	return untar(bytes.NewBufferString(out), dir)
+}
+
+// untar writes the directories and regular files of the archive into dir,
+// refusing entries that would be written outside of it
+func untar(archive io.Reader, dir string) error {
+	reader := tar.NewReader(archive)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not read archive: %w", err)
+		}
+		target := filepath.Join(dir, filepath.Clean(header.Name))
+		if relative, err := filepath.Rel(dir, target); err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %s escapes the target directory", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			content, err := io.ReadAll(reader)
+			if err != nil {
+				return fmt.Errorf("could not read %s from archive: %w", header.Name, err)
+			}
+			if err := os.WriteFile(target, content, 0644); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (a *revisionAgent) git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = a.repoPath
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("'%s' failed with error=%w, output:\n%s", cmd.Args, err, stderr.String())
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,169 @@
+package agents
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift/ci-tools/pkg/api"
+)
+
+func TestResolveConfigAtRevision(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%v failed: %v: %s", cmd.Args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(path, content string) {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit := func(step string) string {
+		write("ci-operator/step-registry/step/step-ref.yaml", `ref:
+  as: step
+  from: src
+  commands: step-commands.sh
+  resources:
+    requests:
+      cpu: 100m
+`)
+		write("ci-operator/step-registry/step/step-commands.sh", step)
+		write("ci-operator/config/org/repo/org-repo-master.yaml", `build_root:
+  image_stream_tag:
+    name: release
+    namespace: openshift
+    tag: golang-1.20
+resources:
+  '*':
+    requests:
+      cpu: 100m
+tests:
+- as: e2e
+  steps:
+    test:
+    - ref: step
+zz_generated_metadata:
+  org: org
+  repo: repo
+  branch: master
+`)
+		git("add", "-A")
+		git("commit", "-m", step)
+		return git("rev-parse", "HEAD")
+	}
+	git("init", "--quiet")
+	first := commit("echo first")
+	second := commit("echo second")
+
+	agent := NewRevisionAgent(dir, true)
+	metadata := api.Metadata{Org: "org", Repo: "repo", Branch: "master"}
+	for revision, expected := range map[string]string{first: "echo first", second[:7]: "echo second"} {
+		config, err := agent.ResolveConfigAtRevision(metadata, revision)
+		if err != nil {
+			t.Fatalf("failed to resolve config at %s: %v", revision, err)
+		}
+		if len(config.Tests) != 1 || config.Tests[0].MultiStageTestConfigurationLiteral == nil {
+			t.Fatalf("expected a single resolved multi-stage test, got %#v", config.Tests)
+		}
+		if actual := config.Tests[0].MultiStageTestConfigurationLiteral.Test[0].Commands; actual != expected {
+			t.Errorf("expected commands %q at %s, got %q", expected, revision, actual)
+		}
+	}
+
+	isNotFound := func(err error) bool {
+		var nf *notFoundError
+		return errors.As(err, &nf)
+	}
+	for _, revision := range []string{"HEAD", "0000000000000000000000000000000000000000"} {
+		if _, err := agent.ResolveConfigAtRevision(metadata, revision); !isNotFound(err) {
+			t.Errorf("expected a not found error for revision %s, got %v", revision, err)
+		}
+	}
+	if _, err := agent.ResolveConfigAtRevision(api.Metadata{Org: "org", Repo: "other", Branch: "master"}, first); !isNotFound(err) {
+		t.Errorf("expected a not found error for a repository without configuration, got %v", err)
+	}
+	if _, err := agent.ResolveConfigAtRevision(api.Metadata{Org: "org", Repo: "repo", Branch: "release-1.0"}, first); !isNotFound(err) {
+		t.Errorf("expected a not found error for a branch without configuration, got %v", err)
+	}
+
+	write("ci-operator/step-registry/step/step-ref.yaml", "ref: [invalid")
+	git("add", "-A")
+	git("commit", "-m", "broken registry")
+	broken := git("rev-parse", "HEAD")
+	if _, err := agent.ResolveConfigAtRevision(metadata, broken); err == nil || isNotFound(err) {
+		t.Errorf("expected an error that is not a not found error for a broken registry, got %v", err)
+	}
+}
+
+func TestUntar(t *testing.T) {
+	archive := func(names ...string) *bytes.Buffer {
+		var buf bytes.Buffer
+		writer := tar.NewWriter(&buf)
+		for _, name := range names {
+			if err := writer.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(name))}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := writer.Write([]byte(name)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return &buf
+	}
+	for _, tc := range []struct {
+		name        string
+		entries     []string
+		expectedErr bool
+	}{
+		{
+			name:    "entries inside the directory are written",
+			entries: []string{"a/b/c.yaml", "a/../d.yaml", "./e.yaml"},
+		},
+		{
+			name:        "entry escaping the directory is refused",
+			entries:     []string{"a/b/c.yaml", "../escaped.yaml"},
+			expectedErr: true,
+		},
+		{
+			name:        "entry escaping the directory after cleaning is refused",
+			entries:     []string{"a/../../escaped.yaml"},
+			expectedErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "target")
+			err := untar(archive(tc.entries...), dir)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("expected error: %t, got %v", tc.expectedErr, err)
+			}
+			if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "escaped.yaml")); !os.IsNotExist(err) {
+				t.Errorf("expected no file to be written outside of the directory, got %v", err)
+			}
+			if tc.expectedErr {
+				return
+			}
+			for _, name := range tc.entries {
+				if content, err := os.ReadFile(filepath.Join(dir, filepath.Clean(name))); err != nil || string(content) != name {
+					t.Errorf("expected %s to contain its name, got %q: %v", name, content, err)
+				}
+			}
+		})
+	}
+}
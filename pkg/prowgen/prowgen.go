@@ -109,6 +109,10 @@ func GenerateJobs(configSpec *cioperatorapi.ReleaseBuildConfiguration, info *Pro
 			presubmitTargets = append(presubmitTargets, "[release:latest]")
 		}
 		jobBaseGen := newJobBaseBuilder().TestName("images")
+		if buildsMultiArchImages(configSpec) {
+			// the images are built on the nodes of their architectures
+			jobBaseGen = NewProwJobBaseBuilderForPromotion(configSpec, info, NewCiOperatorPodSpecGenerator())().TestName("images")
+		}
 		jobBaseGen.PodSpec.Add(Targets(presubmitTargets...))
 		presubmits[orgrepo] = append(presubmits[orgrepo], *generatePresubmitForTest(jobBaseGen, "images", info))
 
@@ -163,7 +167,7 @@ func NewProwJobBaseBuilderForPromotion(configSpec *cioperatorapi.ReleaseBuildCon
 	info *ProwgenInfo, podSpecGenerator CiOperatorPodSpecGenerator) func() *prowJobBaseBuilder {
 	return func() *prowJobBaseBuilder {
 		builder := NewProwJobBaseBuilder(configSpec, info, podSpecGenerator)
-		if info.Config.MultiArch {
+		if info.Config.MultiArch || buildsMultiArchImages(configSpec) {
 			podSpecGenerator.Add(func(spec *corev1.PodSpec) error {
 				if spec.NodeSelector == nil {
 					spec.NodeSelector = make(map[string]string)
@@ -178,6 +182,17 @@ func NewProwJobBaseBuilderForPromotion(configSpec *cioperatorapi.ReleaseBuildCon
 	}
 }
 
+// buildsMultiArchImages determines if an image is built for an architecture other than amd64,
+// which only the multi-arch cluster has nodes for
+func buildsMultiArchImages(configSpec *cioperatorapi.ReleaseBuildConfiguration) bool {
+	for _, image := range configSpec.Images {
+		if image.BuildsMultiArch() {
+			return true
+		}
+	}
+	return false
+}
+
 func testContainsLease(test *cioperatorapi.TestStepConfiguration) bool {
 	// this is predicated upon the config being fully resolved at this time.
 	if test.MultiStageTestConfigurationLiteral == nil {
@@ -381,6 +381,20 @@ func TestGenerateJobs(t *testing.T) {
 				Repo:   "repository",
 				Branch: "branch",
 			}},
+		}, {
+			id: "images built for other architectures run on the multi-arch cluster",
+			config: &ciop.ReleaseBuildConfiguration{
+				Images: []ciop.ProjectDirectoryImageBuildStepConfiguration{{
+					To:            "image",
+					Architectures: []ciop.ReleaseArchitecture{ciop.ReleaseArchitectureAMD64, ciop.ReleaseArchitectureARM64},
+				}},
+				PromotionConfiguration: &ciop.PromotionConfiguration{Targets: []ciop.PromotionTarget{{Namespace: "ci"}}},
+			},
+			repoInfo: &ProwgenInfo{Metadata: ciop.Metadata{
+				Org:    "organization",
+				Repo:   "repository",
+				Branch: "branch",
+			}},
 		}, {
 			id: "template test",
 			config: &ciop.ReleaseBuildConfiguration{
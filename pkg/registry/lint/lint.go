@@ -0,0 +1,350 @@
+// Package lint enforces structural rules on the step registry that are not
+// strictly required for the registry to load, but keep it maintainable.
+package lint
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/config"
+	"github.com/openshift/ci-tools/pkg/load"
+	"github.com/openshift/ci-tools/pkg/registry"
+)
+
+// Severity determines whether a violation fails the lint
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rule identifies a single lint check
+type Rule string
+
+const (
+	// RuleUnused flags components not referenced by any other component or configuration
+	RuleUnused Rule = "unused"
+	// RuleMissingDocumentation flags components without documentation
+	RuleMissingDocumentation Rule = "missing-documentation"
+	// RuleMissingOwners flags components without approvers in their OWNERS file
+	RuleMissingOwners Rule = "missing-owners"
+	// RuleUnusedEnvironment flags declared parameters that no command consumes
+	RuleUnusedEnvironment Rule = "unused-env"
+	// RuleChainDepth flags chains nested deeper than allowed
+	RuleChainDepth Rule = "chain-depth"
+)
+
+// DefaultSeverities are the severities used for rules without an override
+var DefaultSeverities = map[Rule]Severity{
+	RuleUnused:               SeverityWarning,
+	RuleMissingDocumentation: SeverityError,
+	RuleMissingOwners:        SeverityError,
+	RuleUnusedEnvironment:    SeverityWarning,
+	RuleChainDepth:           SeverityError,
+}
+
+const (
+	typeReference = "reference"
+	typeChain     = "chain"
+	typeWorkflow  = "workflow"
+)
+
+// Violation is a single rule violation by a registry component
+type Violation struct {
+	Rule     Rule     `json:"rule"`
+	Severity Severity `json:"severity,omitempty"`
+	Type     string   `json:"type"`
+	Name     string   `json:"name"`
+	// Parameter is the parameter the violation is about, for rules that check parameters
+	Parameter string `json:"parameter,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("[%s] %s %s: %s (%s)", v.Severity, v.Type, v.Name, v.Message, v.Rule)
+}
+
+// Registry holds the registry content the rules are checked against
+type Registry struct {
+	References    registry.ReferenceByName
+	Chains        registry.ChainByName
+	Workflows     registry.WorkflowByName
+	Documentation map[string]string
+	Metadata      api.RegistryMetadata
+}
+
+// Options configure the lint
+type Options struct {
+	// MaxChainDepth is the maximum allowed nesting of chains, unlimited when zero
+	MaxChainDepth int
+	// Severities override DefaultSeverities
+	Severities map[Rule]Severity
+	// Configs are used to determine which components are used directly by tests
+	Configs config.ByOrgRepo
+}
+
+// Lint checks every rule against the registry and returns the violations
+// sorted by component type and name
+func Lint(reg Registry, o Options) []Violation {
+	var violations []Violation
+	violations = append(violations, unused(reg, o.Configs)...)
+	violations = append(violations, missingDocumentation(reg)...)
+	violations = append(violations, missingOwners(reg)...)
+	violations = append(violations, unusedEnvironment(reg)...)
+	if o.MaxChainDepth > 0 {
+		violations = append(violations, chainDepth(reg, o.MaxChainDepth)...)
+	}
+	for i := range violations {
+		violations[i].Severity = DefaultSeverities[violations[i].Rule]
+		if severity, ok := o.Severities[violations[i].Rule]; ok {
+			violations[i].Severity = severity
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].Type != violations[j].Type {
+			return violations[i].Type < violations[j].Type
+		}
+		if violations[i].Name != violations[j].Name {
+			return violations[i].Name < violations[j].Name
+		}
+		return violations[i].Rule < violations[j].Rule
+	})
+	return violations
+}
+
+func unused(reg Registry, configs config.ByOrgRepo) []Violation {
+	used := registry.Components{
+		Workflows:  sets.New[string](),
+		Chains:     sets.New[string](),
+		References: sets.New[string](),
+	}
+	add := func(c registry.Components) {
+		used.Workflows = used.Workflows.Union(c.Workflows)
+		used.Chains = used.Chains.Union(c.Chains)
+		used.References = used.References.Union(c.References)
+	}
+	for _, repos := range configs {
+		for _, repoConfigs := range repos {
+			for _, c := range repoConfigs {
+				add(registry.ComponentsForConfig(c, reg.Chains, reg.Workflows))
+			}
+		}
+	}
+	// components used by other components count as used even if nothing uses
+	// the parent, the parent itself will be reported instead
+	for name, workflow := range reg.Workflows {
+		add(registry.ComponentsForConfig(api.ReleaseBuildConfiguration{Tests: []api.TestStepConfiguration{{
+			As:                          name,
+			MultiStageTestConfiguration: &api.MultiStageTestConfiguration{Pre: workflow.Pre, Test: workflow.Test, Post: workflow.Post},
+		}}}, reg.Chains, reg.Workflows))
+	}
+	for name, chain := range reg.Chains {
+		add(registry.ComponentsForConfig(api.ReleaseBuildConfiguration{Tests: []api.TestStepConfiguration{{
+			As:                          name,
+			MultiStageTestConfiguration: &api.MultiStageTestConfiguration{Test: chain.Steps},
+		}}}, reg.Chains, reg.Workflows))
+	}
+
+	var violations []Violation
+	for _, item := range []struct {
+		t     string
+		names []string
+		used  sets.Set[string]
+	}{
+		{t: typeReference, names: sets.List(sets.KeySet(reg.References)), used: used.References},
+		{t: typeChain, names: sets.List(sets.KeySet(reg.Chains)), used: used.Chains},
+		{t: typeWorkflow, names: sets.List(sets.KeySet(reg.Workflows)), used: used.Workflows},
+	} {
+		for _, name := range item.names {
+			if !item.used.Has(name) {
+				violations = append(violations, Violation{Rule: RuleUnused, Type: item.t, Name: name, Message: "not used by any component or configuration"})
+			}
+		}
+	}
+	return violations
+}
+
+func missingDocumentation(reg Registry) []Violation {
+	var violations []Violation
+	check := func(t, name string) {
+		if strings.TrimSpace(reg.Documentation[name]) == "" {
+			violations = append(violations, Violation{Rule: RuleMissingDocumentation, Type: t, Name: name, Message: "documentation is empty"})
+		}
+	}
+	for name := range reg.References {
+		check(typeReference, name)
+	}
+	for name := range reg.Chains {
+		check(typeChain, name)
+	}
+	for name := range reg.Workflows {
+		check(typeWorkflow, name)
+	}
+	return violations
+}
+
+func missingOwners(reg Registry) []Violation {
+	var violations []Violation
+	check := func(t, name, suffix string) {
+		info, ok := reg.Metadata[name+suffix]
+		if !ok {
+			violations = append(violations, Violation{Rule: RuleMissingOwners, Type: t, Name: name, Message: "no metadata found, is the OWNERS file missing?"})
+			return
+		}
+		if len(info.Owners.Approvers) == 0 {
+			violations = append(violations, Violation{Rule: RuleMissingOwners, Type: t, Name: name, Message: "OWNERS file lists no approvers"})
+		}
+	}
+	for name := range reg.References {
+		check(typeReference, name, load.RefSuffix)
+	}
+	for name := range reg.Chains {
+		check(typeChain, name, load.ChainSuffix)
+	}
+	for name := range reg.Workflows {
+		check(typeWorkflow, name, load.WorkflowSuffix)
+	}
+	return violations
+}
+
+// consumption matches the uses of a parameter in commands
+func consumption(parameter string) *regexp.Regexp {
+	return regexp.MustCompile(`\b` + regexp.QuoteMeta(parameter) + `\b`)
+}
+
+func unusedEnvironment(reg Registry) []Violation {
+	var violations []Violation
+	for name, ref := range reg.References {
+		for _, env := range ref.Environment {
+			if !consumption(env.Name).MatchString(ref.Commands) {
+				violations = append(violations, Violation{Rule: RuleUnusedEnvironment, Type: typeReference, Name: name, Parameter: env.Name, Message: fmt.Sprintf("parameter %s is not used in the commands", env.Name)})
+			}
+		}
+	}
+	for name, chain := range reg.Chains {
+		if len(chain.Environment) == 0 {
+			continue
+		}
+		descendants := registry.ComponentsForConfig(api.ReleaseBuildConfiguration{Tests: []api.TestStepConfiguration{{
+			As:                          name,
+			MultiStageTestConfiguration: &api.MultiStageTestConfiguration{Test: chain.Steps},
+		}}}, reg.Chains, reg.Workflows)
+		for _, env := range chain.Environment {
+			consumes := consumption(env.Name)
+			var found bool
+			for ref := range descendants.References {
+				if consumes.MatchString(reg.References[ref].Commands) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				for _, step := range chain.Steps {
+					if step.LiteralTestStep != nil && consumes.MatchString(step.LiteralTestStep.Commands) {
+						found = true
+						break
+					}
+				}
+			}
+			if !found {
+				violations = append(violations, Violation{Rule: RuleUnusedEnvironment, Type: typeChain, Name: name, Parameter: env.Name, Message: fmt.Sprintf("parameter %s is not used by any step", env.Name)})
+			}
+		}
+	}
+	return violations
+}
+
+func chainDepth(reg Registry, max int) []Violation {
+	depths := map[string]int{}
+	var depth func(name string) int
+	depth = func(name string) int {
+		if d, ok := depths[name]; ok {
+			return d
+		}
+		// guard against cycles, the registry loader rejects them anyway
+		depths[name] = 1
+		deepest := 0
+		for _, step := range reg.Chains[name].Steps {
+			if step.Chain != nil {
+				if d := depth(*step.Chain); d > deepest {
+					deepest = d
+				}
+			}
+		}
+		depths[name] = deepest + 1
+		return depths[name]
+	}
+	var violations []Violation
+	for name := range reg.Chains {
+		if d := depth(name); d > max {
+			violations = append(violations, Violation{Rule: RuleChainDepth, Type: typeChain, Name: name, Message: fmt.Sprintf("nesting depth %d exceeds the limit of %d", d, max)})
+		}
+	}
+	return violations
+}
+
+// Baseline lists grandfathered violations that do not fail the lint
+type Baseline []Violation
+
+// LoadBaseline reads a baseline file; a missing file yields an empty baseline
+func LoadBaseline(path string) (Baseline, error) {
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var baseline Baseline
+	if err := yaml.UnmarshalStrict(raw, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal baseline: %w", err)
+	}
+	return baseline, nil
+}
+
+// NewBaseline grandfathers all given violations
+func NewBaseline(violations []Violation) Baseline {
+	var baseline Baseline
+	for _, v := range violations {
+		baseline = append(baseline, Violation{Rule: v.Rule, Type: v.Type, Name: v.Name, Parameter: v.Parameter})
+	}
+	return baseline
+}
+
+// key identifies the violation of a rule by a component, or by one of its
+// parameters, so that grandfathering one parameter does not hide the others
+func (b Baseline) key(v Violation) string {
+	return fmt.Sprintf("%s/%s/%s/%s", v.Rule, v.Type, v.Name, v.Parameter)
+}
+
+// Filter splits violations into new ones and the ones grandfathered by the
+// baseline. It also returns baseline entries that no longer match anything.
+func (b Baseline) Filter(violations []Violation) (remaining, grandfathered []Violation, stale Baseline) {
+	entries := sets.New[string]()
+	for _, v := range b {
+		entries.Insert(b.key(v))
+	}
+	matched := sets.New[string]()
+	for _, v := range violations {
+		if entries.Has(b.key(v)) {
+			grandfathered = append(grandfathered, v)
+			matched.Insert(b.key(v))
+		} else {
+			remaining = append(remaining, v)
+		}
+	}
+	for _, v := range b {
+		if !matched.Has(b.key(v)) {
+			stale = append(stale, v)
+		}
+	}
+	return remaining, grandfathered, stale
+}
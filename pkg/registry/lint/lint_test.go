@@ -0,0 +1,99 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/test-infra/prow/repoowners"
+
+	"github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/config"
+	"github.com/openshift/ci-tools/pkg/registry"
+)
+
+func TestLint(t *testing.T) {
+	install, deprovision, orphan := "install", "deprovision", "orphan"
+	installChain, outer, outermost := "install-chain", "outer", "outermost"
+	owners := repoowners.Config{Approvers: []string{"someone"}}
+	reg := Registry{
+		References: registry.ReferenceByName{
+			install: {
+				As:          install,
+				Commands:    "openshift-install create cluster --region ${REGION}",
+				Environment: []api.StepParameter{{Name: "REGION"}, {Name: "UNUSED"}},
+			},
+			deprovision: {As: deprovision, Commands: "openshift-install destroy cluster"},
+			orphan:      {As: orphan, Commands: "true"},
+		},
+		Chains: registry.ChainByName{
+			installChain: {As: installChain, Steps: []api.TestStep{{Reference: &install}}, Environment: []api.StepParameter{{Name: "REGION"}, {Name: "NOPE"}}},
+			outer:        {As: outer, Steps: []api.TestStep{{Chain: &installChain}}},
+			outermost:    {As: outermost, Steps: []api.TestStep{{Chain: &outer}}},
+		},
+		Workflows: registry.WorkflowByName{
+			"ipi": {Pre: []api.TestStep{{Chain: &outermost}}, Post: []api.TestStep{{Reference: &deprovision}}},
+		},
+		Documentation: map[string]string{install: "Installs.", deprovision: "Deprovisions.", orphan: "Nothing.", installChain: "Installs.", outer: "Wraps.", outermost: "Wraps.", "ipi": " "},
+		Metadata: api.RegistryMetadata{
+			"install-ref.yaml":         {Owners: owners},
+			"deprovision-ref.yaml":     {Owners: owners},
+			"orphan-ref.yaml":          {},
+			"install-chain-chain.yaml": {Owners: owners},
+			"outer-chain.yaml":         {Owners: owners},
+			"outermost-chain.yaml":     {Owners: owners},
+			"ipi-workflow.yaml":        {Owners: owners},
+		},
+	}
+	workflow := "ipi"
+	configs := config.ByOrgRepo{"org": {"repo": {{Tests: []api.TestStepConfiguration{{
+		As:                          "e2e",
+		MultiStageTestConfiguration: &api.MultiStageTestConfiguration{Workflow: &workflow},
+	}}}}}}
+
+	expected := []Violation{
+		{Rule: RuleUnusedEnvironment, Severity: SeverityWarning, Type: typeChain, Name: installChain, Parameter: "NOPE", Message: "parameter NOPE is not used by any step"},
+		{Rule: RuleChainDepth, Severity: SeverityError, Type: typeChain, Name: outermost, Message: "nesting depth 3 exceeds the limit of 2"},
+		{Rule: RuleUnusedEnvironment, Severity: SeverityWarning, Type: typeReference, Name: install, Parameter: "UNUSED", Message: "parameter UNUSED is not used in the commands"},
+		{Rule: RuleMissingOwners, Severity: SeverityError, Type: typeReference, Name: orphan, Message: "OWNERS file lists no approvers"},
+		{Rule: RuleUnused, Severity: SeverityError, Type: typeReference, Name: orphan, Message: "not used by any component or configuration"},
+		{Rule: RuleMissingDocumentation, Severity: SeverityError, Type: typeWorkflow, Name: workflow, Message: "documentation is empty"},
+	}
+	actual := Lint(reg, Options{MaxChainDepth: 2, Configs: configs, Severities: map[Rule]Severity{RuleUnused: SeverityError}})
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("unexpected violations: %s", diff)
+	}
+
+	unusedWorkflow := Lint(reg, Options{})
+	if diff := cmp.Diff(Violation{Rule: RuleUnused, Severity: SeverityWarning, Type: typeWorkflow, Name: workflow, Message: "not used by any component or configuration"}, unusedWorkflow[len(unusedWorkflow)-1]); diff != "" {
+		t.Errorf("expected the workflow to be unused without configs: %s", diff)
+	}
+}
+
+func TestBaselineFilter(t *testing.T) {
+	violations := []Violation{
+		{Rule: RuleUnused, Severity: SeverityWarning, Type: typeReference, Name: "a"},
+		{Rule: RuleMissingOwners, Severity: SeverityError, Type: typeReference, Name: "a"},
+		{Rule: RuleMissingOwners, Severity: SeverityError, Type: typeChain, Name: "b"},
+		{Rule: RuleUnusedEnvironment, Severity: SeverityWarning, Type: typeReference, Name: "a", Parameter: "OLD", Message: "parameter OLD is not used in the commands"},
+		{Rule: RuleUnusedEnvironment, Severity: SeverityWarning, Type: typeReference, Name: "a", Parameter: "NEW", Message: "parameter NEW is not used in the commands"},
+	}
+	baseline := Baseline{
+		{Rule: RuleMissingOwners, Type: typeReference, Name: "a"},
+		{Rule: RuleChainDepth, Type: typeChain, Name: "gone"},
+		{Rule: RuleUnusedEnvironment, Type: typeReference, Name: "a", Parameter: "OLD"},
+	}
+	remaining, grandfathered, stale := baseline.Filter(violations)
+	if diff := cmp.Diff([]Violation{violations[0], violations[2], violations[4]}, remaining); diff != "" {
+		t.Errorf("unexpected remaining violations: %s", diff)
+	}
+	if diff := cmp.Diff([]Violation{violations[1], violations[3]}, grandfathered); diff != "" {
+		t.Errorf("unexpected grandfathered violations: %s", diff)
+	}
+	if diff := cmp.Diff(Baseline{baseline[1]}, stale); diff != "" {
+		t.Errorf("unexpected stale baseline entries: %s", diff)
+	}
+	if diff := cmp.Diff(Baseline{{Rule: RuleUnused, Type: typeReference, Name: "a"}, {Rule: RuleUnusedEnvironment, Type: typeReference, Name: "a", Parameter: "OLD"}}, NewBaseline([]Violation{violations[0], violations[3]})); diff != "" {
+		t.Errorf("unexpected new baseline: %s", diff)
+	}
+}
@@ -0,0 +1,137 @@
+// Package ownership determines which step registry components are touched by a
+// change and whether the owners of each of them approved it.
+package ownership
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/test-infra/prow/github"
+	"k8s.io/test-infra/prow/repoowners"
+)
+
+// OwnersGetter returns the OWNERS configuration in a registry directory,
+// relative to the registry root, or nil if there is no OWNERS file
+type OwnersGetter func(dir string) (*repoowners.SimpleConfig, error)
+
+// Component is a registry directory touched by a change, together with the
+// users allowed to approve changes to it
+type Component struct {
+	Path      string   `json:"path"`
+	Files     []string `json:"files"`
+	Approvers []string `json:"approvers"`
+}
+
+// TouchedComponents groups the changed files, relative to the registry root, by
+// the directory they are in and resolves the approvers for each directory.
+// Approvers are inherited from OWNERS files in parent directories unless a
+// file opts out with `no_parent_owners`, and aliases among them are expanded.
+func TouchedComponents(changed []string, getOwners OwnersGetter, aliases repoowners.RepoAliases) ([]Component, error) {
+	byDir := map[string][]string{}
+	for _, file := range changed {
+		dir := filepath.Dir(file)
+		byDir[dir] = append(byDir[dir], file)
+	}
+	var components []Component
+	for _, dir := range sets.List(sets.KeySet(byDir)) {
+		declared := sets.New[string]()
+		for current := dir; ; current = filepath.Dir(current) {
+			owners, err := getOwners(current)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get OWNERS for %s: %w", current, err)
+			}
+			if owners != nil {
+				declared.Insert(owners.Approvers...)
+				if owners.Options.NoParentOwners {
+					break
+				}
+			}
+			if current == "." || current == "/" {
+				break
+			}
+		}
+		approvers := sets.New[string]()
+		for _, approver := range sets.List(aliases.ExpandAliases(declared)) {
+			approvers.Insert(github.NormLogin(approver))
+		}
+		files := byDir[dir]
+		sort.Strings(files)
+		components = append(components, Component{Path: dir, Files: files, Approvers: sets.List(approvers)})
+	}
+	return components, nil
+}
+
+// Unapproved returns the components that none of their approvers approved
+func Unapproved(components []Component, approvals sets.Set[string]) []Component {
+	var unapproved []Component
+	for _, component := range components {
+		if !sets.New[string](component.Approvers...).HasAny(sets.List(approvals)...) {
+			unapproved = append(unapproved, component)
+		}
+	}
+	return unapproved
+}
+
+var (
+	approveRegex       = regexp.MustCompile(`(?mi)^/approve\s*$`)
+	approveCancelRegex = regexp.MustCompile(`(?mi)^/approve\s+cancel\s*$`)
+)
+
+// Approvals determines the users currently approving a pull request, following
+// the semantics of the Prow approve plugin: the author implicitly approves, an
+// approving review or an `/approve` comment adds an approval and
+// `/approve cancel` removes it. Comments and reviews are processed in the
+// order they were created.
+func Approvals(author string, comments []github.IssueComment, reviews []github.Review) sets.Set[string] {
+	type event struct {
+		user    string
+		approve bool
+		cancel  bool
+		at      int64
+	}
+	var events []event
+	for _, comment := range comments {
+		events = append(events, event{
+			user:    comment.User.Login,
+			approve: approveRegex.MatchString(comment.Body),
+			cancel:  approveCancelRegex.MatchString(comment.Body),
+			at:      comment.CreatedAt.UnixNano(),
+		})
+	}
+	for _, review := range reviews {
+		events = append(events, event{
+			user:    review.User.Login,
+			approve: review.State == github.ReviewStateApproved || approveRegex.MatchString(review.Body),
+			cancel:  approveCancelRegex.MatchString(review.Body),
+			at:      review.SubmittedAt.UnixNano(),
+		})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].at < events[j].at })
+
+	approvals := sets.New[string]()
+	if author != "" {
+		approvals.Insert(github.NormLogin(author))
+	}
+	for _, e := range events {
+		switch {
+		case e.cancel:
+			approvals.Delete(github.NormLogin(e.user))
+		case e.approve:
+			approvals.Insert(github.NormLogin(e.user))
+		}
+	}
+	return approvals
+}
+
+// Format renders the unapproved components in a human-readable list
+func Format(unapproved []Component) string {
+	var b strings.Builder
+	for _, component := range unapproved {
+		fmt.Fprintf(&b, "* %s (%s)\n  approvers: %s\n", component.Path, strings.Join(component.Files, ", "), strings.Join(component.Approvers, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package ownership
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/test-infra/prow/github"
+	"k8s.io/test-infra/prow/repoowners"
+)
+
+func TestTouchedComponents(t *testing.T) {
+	owners := map[string]*repoowners.SimpleConfig{
+		".":                 {Config: repoowners.Config{Approvers: []string{"root"}}},
+		"ipi":               {Config: repoowners.Config{Approvers: []string{"IPI-Owner"}}},
+		"ipi/install":       {Config: repoowners.Config{Approvers: []string{"installer"}}},
+		"ipi/deprovision":   {Config: repoowners.Config{Approvers: []string{"deprovisioner"}}},
+		"openshift/e2e/aws": {Config: repoowners.Config{Approvers: []string{"aws"}}},
+		"upi":               {Config: repoowners.Config{Approvers: []string{"UPI-Approvers", "someone"}}},
+	}
+	owners["openshift/e2e/aws"].Options.NoParentOwners = true
+	getter := func(dir string) (*repoowners.SimpleConfig, error) {
+		if dir == "broken" {
+			return nil, errors.New("oops")
+		}
+		return owners[dir], nil
+	}
+
+	components, err := TouchedComponents([]string{
+		"ipi/install/ipi-install-ref.yaml",
+		"ipi/install/ipi-install-commands.sh",
+		"openshift/e2e/aws/openshift-e2e-aws-workflow.yaml",
+		"new/new-ref.yaml",
+		"upi/upi-workflow.yaml",
+	}, getter, repoowners.RepoAliases{"upi-approvers": sets.New[string]("upi-lead", "someone")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Component{
+		{Path: "ipi/install", Files: []string{"ipi/install/ipi-install-commands.sh", "ipi/install/ipi-install-ref.yaml"}, Approvers: []string{"installer", "ipi-owner", "root"}},
+		{Path: "new", Files: []string{"new/new-ref.yaml"}, Approvers: []string{"root"}},
+		{Path: "openshift/e2e/aws", Files: []string{"openshift/e2e/aws/openshift-e2e-aws-workflow.yaml"}, Approvers: []string{"aws"}},
+		{Path: "upi", Files: []string{"upi/upi-workflow.yaml"}, Approvers: []string{"root", "someone", "upi-lead"}},
+	}
+	if diff := cmp.Diff(expected, components); diff != "" {
+		t.Errorf("unexpected components: %s", diff)
+	}
+
+	if _, err := TouchedComponents([]string{"broken/file"}, getter, nil); err == nil {
+		t.Error("expected an error when OWNERS cannot be read")
+	}
+
+	unapproved := Unapproved(components, sets.New[string]("installer", "upi-lead"))
+	if diff := cmp.Diff(components[1:3], unapproved); diff != "" {
+		t.Errorf("unexpected unapproved components: %s", diff)
+	}
+}
+
+func TestApprovals(t *testing.T) {
+	at := func(minutes int) time.Time { return time.Date(2023, 1, 1, 0, minutes, 0, 0, time.UTC) }
+	comments := []github.IssueComment{
+		{User: github.User{Login: "Alice"}, Body: "/approve", CreatedAt: at(1)},
+		{User: github.User{Login: "bob"}, Body: "/lgtm\n/approve", CreatedAt: at(2)},
+		{User: github.User{Login: "bob"}, Body: "/approve cancel", CreatedAt: at(5)},
+		{User: github.User{Login: "carol"}, Body: "please /approve this", CreatedAt: at(3)},
+	}
+	reviews := []github.Review{
+		{User: github.User{Login: "dave"}, State: github.ReviewStateApproved, SubmittedAt: at(4)},
+		{User: github.User{Login: "erin"}, State: github.ReviewStateCommented, SubmittedAt: at(4)},
+		{User: github.User{Login: "alice"}, Body: "/approve cancel", State: github.ReviewStateCommented, SubmittedAt: at(6)},
+	}
+	expected := sets.New[string]("author", "dave")
+	if diff := cmp.Diff(expected, Approvals("Author", comments, reviews)); diff != "" {
+		t.Errorf("unexpected approvals: %s", diff)
+	}
+}
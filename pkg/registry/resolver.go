@@ -118,6 +118,9 @@ func (r *registry) mergeWorkflow(config *api.MultiStageTestConfiguration) ([][]a
 	if config.AllowBestEffortPostSteps == nil {
 		config.AllowBestEffortPostSteps = workflow.AllowBestEffortPostSteps
 	}
+	if config.Cache == nil {
+		config.Cache = workflow.Cache
+	}
 	return overridden, errs
 }
 
@@ -133,6 +136,7 @@ func (r *registry) resolveTest(
 		AllowBestEffortPostSteps: config.AllowBestEffortPostSteps,
 		Leases:                   config.Leases,
 		DependencyOverrides:      config.DependencyOverrides,
+		Cache:                    config.Cache,
 	}
 	if config.Workflow != nil {
 		stack.push(stackRecordForTest("workflow/"+*config.Workflow, nil, nil, nil))
@@ -715,6 +715,7 @@ func TestResolve(t *testing.T) {
 		workflowMap: WorkflowByName{
 			awsWorkflow: {
 				ClusterProfile: api.ClusterProfileAWS,
+				Cache:          &api.StepCache{Size: "10Gi"},
 				Pre: []api.TestStep{{
 					LiteralTestStep: &api.LiteralTestStep{
 						As:       "ipi-install",
@@ -749,6 +750,7 @@ func TestResolve(t *testing.T) {
 		},
 		expectedRes: api.MultiStageTestConfigurationLiteral{
 			ClusterProfile: api.ClusterProfileAzure4,
+			Cache:          &api.StepCache{Size: "10Gi"},
 			Pre: []api.LiteralTestStep{{
 				As:       "ipi-install",
 				From:     "installer",
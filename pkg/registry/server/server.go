@@ -2,6 +2,7 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -21,6 +22,8 @@ const (
 	BranchQuery  = "branch"
 	VariantQuery = "variant"
 
+	RevisionQuery = "revision"
+
 	InjectFromOrgQuery     = "injectTestFromOrg"
 	InjectFromRepoQuery    = "injectTestFromRepo"
 	InjectFromBranchQuery  = "injectTestFromBranch"
@@ -219,6 +222,75 @@ func ResolveConfig(configs Getter, resolver Resolver, resolverMetrics *metrics.M
 	}
 }
 
+// RevisionResolver resolves configurations as they were at a given revision
+type RevisionResolver interface {
+	ResolveConfigAtRevision(metadata api.Metadata, revision string) (api.ReleaseBuildConfiguration, error)
+}
+
+// notFound is implemented by RevisionResolver errors caused by an unknown
+// revision or a configuration missing at that revision
+type notFound interface {
+	NotFound() bool
+}
+
+func isNotFound(err error) bool {
+	var nf notFound
+	return errors.As(err, &nf) && nf.NotFound()
+}
+
+// ResolveConfigAtRevision serves the configuration matching the query, loaded and
+// resolved from the state of the release repository at the requested revision
+func ResolveConfigAtRevision(resolver RevisionResolver, resolverMetrics *metrics.Metrics) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			w.WriteHeader(http.StatusNotImplemented)
+			_, _ = w.Write([]byte(http.StatusText(http.StatusNotImplemented)))
+			return
+		}
+		metadata, err := MetadataFromQuery(w, r)
+		if err != nil {
+			// MetadataFromQuery deals with setting status code and writing response
+			// so we need to just log the error here
+			metrics.RecordError("invalid query", resolverMetrics.ErrorRate)
+			logrus.WithError(err).Warning("failed to read query from request")
+			return
+		}
+		revision := r.URL.Query().Get(RevisionQuery)
+		if revision == "" {
+			metrics.RecordError("invalid query", resolverMetrics.ErrorRate)
+			MissingQuery(w, RevisionQuery)
+			return
+		}
+		logger := logrus.WithFields(api.LogFieldsFor(metadata)).WithField("revision", revision)
+
+		config, err := resolver.ResolveConfigAtRevision(metadata, revision)
+		if err != nil {
+			metrics.RecordError("failed to resolve config at revision", resolverMetrics.ErrorRate)
+			if isNotFound(err) {
+				w.WriteHeader(http.StatusNotFound)
+				logger.WithError(err).Warning("failed to resolve config at revision")
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+				logger.WithError(err).Error("failed to resolve config at revision")
+			}
+			fmt.Fprintf(w, "failed to resolve config at revision: %v", err)
+			return
+		}
+		jsonConfig, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			metrics.RecordError("failed to marshal config", resolverMetrics.ErrorRate)
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "failed to marshal config to JSON: %v", err)
+			logger.WithError(err).Errorf("failed to marshal config to JSON")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(jsonConfig); err != nil {
+			logrus.WithError(err).Error("Failed to write response")
+		}
+	}
+}
+
 func ResolveLiteralConfig(resolver Resolver, resolverMetrics *metrics.Metrics) http.HandlerFunc {
 	logger := logrus.NewEntry(logrus.New())
 	return func(w http.ResponseWriter, r *http.Request) {
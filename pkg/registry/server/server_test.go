@@ -0,0 +1,68 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/test-infra/prow/metrics"
+
+	"github.com/openshift/ci-tools/pkg/api"
+)
+
+type fakeRevisionResolver struct {
+	err error
+}
+
+func (r *fakeRevisionResolver) ResolveConfigAtRevision(api.Metadata, string) (api.ReleaseBuildConfiguration, error) {
+	return api.ReleaseBuildConfiguration{}, r.err
+}
+
+type fakeNotFoundError struct{}
+
+func (fakeNotFoundError) Error() string  { return "not found" }
+func (fakeNotFoundError) NotFound() bool { return true }
+
+func TestResolveConfigAtRevision(t *testing.T) {
+	resolverMetrics := metrics.NewMetrics("test")
+	for _, tc := range []struct {
+		name     string
+		query    string
+		err      error
+		expected int
+	}{
+		{
+			name:     "resolved",
+			query:    "org=org&repo=repo&branch=master&revision=abcdef0",
+			expected: http.StatusOK,
+		},
+		{
+			name:     "missing revision",
+			query:    "org=org&repo=repo&branch=master",
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "unknown revision or missing config",
+			query:    "org=org&repo=repo&branch=master&revision=abcdef0",
+			err:      fmt.Errorf("at abcdef0: %w", fakeNotFoundError{}),
+			expected: http.StatusNotFound,
+		},
+		{
+			name:     "failure to resolve",
+			query:    "org=org&repo=repo&branch=master&revision=abcdef0",
+			err:      errors.New("could not load registry"),
+			expected: http.StatusInternalServerError,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/configAtRevision?"+tc.query, nil)
+			ResolveConfigAtRevision(&fakeRevisionResolver{err: tc.err}, resolverMetrics)(recorder, request)
+			if recorder.Code != tc.expected {
+				t.Errorf("expected status %d, got %d: %s", tc.expected, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/config"
+	"github.com/openshift/ci-tools/pkg/registry"
+)
+
+// ConfigLister exposes all the ci-operator configurations known to the server
+type ConfigLister interface {
+	GetAll() config.ByOrgRepo
+}
+
+// RegistryLister exposes the content of the step registry known to the server
+type RegistryLister interface {
+	GetRegistryComponents() (registry.ReferenceByName, registry.ChainByName, registry.WorkflowByName, map[string]string, api.RegistryMetadata)
+}
+
+var registryComponentRequestsMetric = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "configresolver_registry_component_requests_total",
+		Help: "Number of registry components of the type required by resolved configurations. The usage per component is served by /registryUsage.",
+	},
+	[]string{"type"},
+)
+
+func init() {
+	prometheus.MustRegister(registryComponentRequestsMetric)
+}
+
+// UsageTracker records how often registry components are requested by
+// resolved configurations and serves the aggregated usage over HTTP.
+type UsageTracker struct {
+	configs  ConfigLister
+	registry RegistryLister
+
+	lock     sync.Mutex
+	requests *registry.UsageCounter
+}
+
+// NewUsageTracker returns a tracker that reports usage against the given
+// configurations and registry
+func NewUsageTracker(configs ConfigLister, registryLister RegistryLister) *UsageTracker {
+	return &UsageTracker{
+		configs:  configs,
+		registry: registryLister,
+		requests: registry.NewUsageCounter(nil, nil, nil),
+	}
+}
+
+// Resolver wraps the resolver so that every configuration successfully resolved
+// through it counts as a request for the registry components it references
+func (u *UsageTracker) Resolver(resolver Resolver) Resolver {
+	return &trackingResolver{tracker: u, delegate: resolver}
+}
+
+type trackingResolver struct {
+	tracker  *UsageTracker
+	delegate Resolver
+}
+
+func (r *trackingResolver) ResolveConfig(config api.ReleaseBuildConfiguration) (api.ReleaseBuildConfiguration, error) {
+	resolved, err := r.delegate.ResolveConfig(config)
+	if err == nil {
+		r.tracker.record(config)
+	}
+	return resolved, err
+}
+
+func (u *UsageTracker) record(config api.ReleaseBuildConfiguration) {
+	_, chains, workflows, _, _ := u.registry.GetRegistryComponents()
+	components := registry.ComponentsForConfig(config, chains, workflows)
+	for _, item := range []struct {
+		t     string
+		names sets.Set[string]
+	}{
+		{t: "workflow", names: components.Workflows},
+		{t: "chain", names: components.Chains},
+		{t: "reference", names: components.References},
+	} {
+		registryComponentRequestsMetric.WithLabelValues(item.t).Add(float64(item.names.Len()))
+	}
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.requests.Add(components)
+}
+
+// Usage returns the usage of every registry component, counting both the
+// configurations that reference it and the requests that needed it
+func (u *UsageTracker) Usage() []registry.ComponentUsage {
+	references, chains, workflows, _, _ := u.registry.GetRegistryComponents()
+	configs := registry.NewUsageCounter(references, chains, workflows)
+	for _, repos := range u.configs.GetAll() {
+		for _, repoConfigs := range repos {
+			for _, c := range repoConfigs {
+				configs.Add(registry.ComponentsForConfig(c, chains, workflows))
+			}
+		}
+	}
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	return registry.Usage(configs, u.requests)
+}
+
+// ServeUsage responds with the JSON-encoded usage of all registry components
+func (u *UsageTracker) ServeUsage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			w.WriteHeader(http.StatusNotImplemented)
+			_, _ = w.Write([]byte(http.StatusText(http.StatusNotImplemented)))
+			return
+		}
+		raw, err := json.MarshalIndent(u.Usage(), "", "  ")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "failed to marshal registry usage to JSON: %v", err)
+			logrus.WithError(err).Error("failed to marshal registry usage to JSON")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(raw); err != nil {
+			logrus.WithError(err).Error("Failed to write response")
+		}
+	}
+}
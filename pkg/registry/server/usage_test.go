@@ -0,0 +1,119 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/config"
+	"github.com/openshift/ci-tools/pkg/registry"
+)
+
+type fakeConfigLister struct {
+	configs config.ByOrgRepo
+}
+
+func (l *fakeConfigLister) GetAll() config.ByOrgRepo {
+	return l.configs
+}
+
+type fakeRegistryLister struct {
+	references registry.ReferenceByName
+	chains     registry.ChainByName
+	workflows  registry.WorkflowByName
+}
+
+func (l *fakeRegistryLister) GetRegistryComponents() (registry.ReferenceByName, registry.ChainByName, registry.WorkflowByName, map[string]string, api.RegistryMetadata) {
+	return l.references, l.chains, l.workflows, nil, nil
+}
+
+type fakeResolver struct {
+	err error
+}
+
+func (r *fakeResolver) ResolveConfig(config api.ReleaseBuildConfiguration) (api.ReleaseBuildConfiguration, error) {
+	return config, r.err
+}
+
+func configUsing(workflow string, references ...string) api.ReleaseBuildConfiguration {
+	test := api.MultiStageTestConfiguration{Workflow: &workflow}
+	for i := range references {
+		test.Test = append(test.Test, api.TestStep{Reference: &references[i]})
+	}
+	return api.ReleaseBuildConfiguration{
+		Tests: []api.TestStepConfiguration{{As: "e2e", MultiStageTestConfiguration: &test}},
+	}
+}
+
+func TestUsageTracker(t *testing.T) {
+	registryLister := &fakeRegistryLister{
+		references: registry.ReferenceByName{"ref-a": {}, "ref-b": {}, "unused-ref": {}},
+		chains: registry.ChainByName{"chain": {Steps: []api.TestStep{
+			{Reference: func() *string { s := "ref-b"; return &s }()},
+		}}},
+		workflows: registry.WorkflowByName{"workflow": {
+			Pre: []api.TestStep{{Chain: func() *string { s := "chain"; return &s }()}},
+		}},
+	}
+	configs := &fakeConfigLister{configs: config.ByOrgRepo{
+		"org": {"repo": {configUsing("workflow", "ref-a")}},
+	}}
+
+	for _, tc := range []struct {
+		name string
+		// requests are resolved in order through a single tracker
+		requests    []api.ReleaseBuildConfiguration
+		resolverErr error
+		expected    []registry.ComponentUsage
+	}{
+		{
+			name: "no requests only counts configurations",
+			expected: []registry.ComponentUsage{
+				{Type: "workflow", Name: "workflow", Configs: 1},
+				{Type: "chain", Name: "chain", Configs: 1},
+				{Type: "reference", Name: "ref-a", Configs: 1},
+				{Type: "reference", Name: "ref-b", Configs: 1},
+				{Type: "reference", Name: "unused-ref"},
+			},
+		},
+		{
+			name:     "requests accumulate across resolutions",
+			requests: []api.ReleaseBuildConfiguration{configUsing("workflow", "ref-a"), configUsing("workflow"), configUsing("unknown-workflow", "ref-a")},
+			expected: []registry.ComponentUsage{
+				{Type: "workflow", Name: "unknown-workflow", Requests: 1},
+				{Type: "workflow", Name: "workflow", Configs: 1, Requests: 2},
+				{Type: "chain", Name: "chain", Configs: 1, Requests: 2},
+				{Type: "reference", Name: "ref-a", Configs: 1, Requests: 2},
+				{Type: "reference", Name: "ref-b", Configs: 1, Requests: 2},
+				{Type: "reference", Name: "unused-ref"},
+			},
+		},
+		{
+			name:        "failed resolutions are not counted",
+			requests:    []api.ReleaseBuildConfiguration{configUsing("workflow", "ref-a")},
+			resolverErr: errors.New("failed"),
+			expected: []registry.ComponentUsage{
+				{Type: "workflow", Name: "workflow", Configs: 1},
+				{Type: "chain", Name: "chain", Configs: 1},
+				{Type: "reference", Name: "ref-a", Configs: 1},
+				{Type: "reference", Name: "ref-b", Configs: 1},
+				{Type: "reference", Name: "unused-ref"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// every case starts from a new tracker: the request counts only live as long as the
+			// tracker and start over when the server restarts
+			tracker := NewUsageTracker(configs, registryLister)
+			resolver := tracker.Resolver(&fakeResolver{err: tc.resolverErr})
+			for _, request := range tc.requests {
+				_, _ = resolver.ResolveConfig(request)
+			}
+			if diff := cmp.Diff(tc.expected, tracker.Usage()); diff != "" {
+				t.Errorf("unexpected usage (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package registry
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/ci-tools/pkg/api"
+)
+
+// Components holds the names of the registry elements referenced by a
+// configuration, either directly or transitively through workflows and chains.
+type Components struct {
+	Workflows  sets.Set[string]
+	Chains     sets.Set[string]
+	References sets.Set[string]
+}
+
+// ComponentUsage describes how often a single registry element is used
+type ComponentUsage struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// Configs is the number of ci-operator configurations referencing the element
+	Configs int `json:"configs"`
+	// Requests is the number of resolution requests that needed the element
+	Requests int `json:"requests"`
+}
+
+// ComponentsForConfig walks all multi-stage tests of the configuration and
+// returns every workflow, chain and reference they use. Unknown names are
+// recorded as-is, but not expanded any further.
+func ComponentsForConfig(config api.ReleaseBuildConfiguration, chains ChainByName, workflows WorkflowByName) Components {
+	c := Components{
+		Workflows:  sets.New[string](),
+		Chains:     sets.New[string](),
+		References: sets.New[string](),
+	}
+	for _, test := range config.Tests {
+		if test.MultiStageTestConfiguration == nil {
+			continue
+		}
+		c.addTest(*test.MultiStageTestConfiguration, chains, workflows)
+	}
+	return c
+}
+
+func (c *Components) addTest(test api.MultiStageTestConfiguration, chains ChainByName, workflows WorkflowByName) {
+	if test.Workflow != nil && !c.Workflows.Has(*test.Workflow) {
+		c.Workflows.Insert(*test.Workflow)
+		if workflow, ok := workflows[*test.Workflow]; ok {
+			c.addTest(workflow, chains, workflows)
+		}
+	}
+	for _, steps := range [][]api.TestStep{test.Pre, test.Test, test.Post} {
+		c.addSteps(steps, chains)
+	}
+}
+
+func (c *Components) addSteps(steps []api.TestStep, chains ChainByName) {
+	for _, step := range steps {
+		switch {
+		case step.Reference != nil:
+			c.References.Insert(*step.Reference)
+		case step.Chain != nil:
+			if c.Chains.Has(*step.Chain) {
+				continue
+			}
+			c.Chains.Insert(*step.Chain)
+			if chain, ok := chains[*step.Chain]; ok {
+				c.addSteps(chain.Steps, chains)
+			}
+		}
+	}
+}
+
+// UsageCounter accumulates per-element counts for workflows, chains and references
+type UsageCounter struct {
+	workflows  map[string]int
+	chains     map[string]int
+	references map[string]int
+}
+
+// NewUsageCounter returns a counter seeded with every element of the registry,
+// so that unused elements are reported with a count of zero.
+func NewUsageCounter(references ReferenceByName, chains ChainByName, workflows WorkflowByName) *UsageCounter {
+	u := &UsageCounter{
+		workflows:  map[string]int{},
+		chains:     map[string]int{},
+		references: map[string]int{},
+	}
+	for name := range references {
+		u.references[name] = 0
+	}
+	for name := range chains {
+		u.chains[name] = 0
+	}
+	for name := range workflows {
+		u.workflows[name] = 0
+	}
+	return u
+}
+
+// Add increments the count of every element in the components
+func (u *UsageCounter) Add(c Components) {
+	for name := range c.Workflows {
+		u.workflows[name]++
+	}
+	for name := range c.Chains {
+		u.chains[name]++
+	}
+	for name := range c.References {
+		u.references[name]++
+	}
+}
+
+// Usage merges the config reference counts with the request counts into a list
+// of workflows, chains and references, each sorted by name.
+func Usage(configs, requests *UsageCounter) []ComponentUsage {
+	var ret []ComponentUsage
+	for _, item := range []struct {
+		t                Type
+		configs, request map[string]int
+	}{
+		{t: Workflow, configs: configs.workflows, request: requests.workflows},
+		{t: Chain, configs: configs.chains, request: requests.chains},
+		{t: Reference, configs: configs.references, request: requests.references},
+	} {
+		names := sets.KeySet(item.configs).Union(sets.KeySet(item.request))
+		for _, name := range sets.List(names) {
+			ret = append(ret, ComponentUsage{
+				Type:     nodeTypes[item.t],
+				Name:     name,
+				Configs:  item.configs[name],
+				Requests: item.request[name],
+			})
+		}
+	}
+	return ret
+}
@@ -0,0 +1,94 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/ci-tools/pkg/api"
+)
+
+func TestComponentsForConfig(t *testing.T) {
+	unknown := "unknown"
+	testCases := []struct {
+		name     string
+		config   api.ReleaseBuildConfiguration
+		expected Components
+	}{{
+		name: "container tests reference nothing",
+		config: api.ReleaseBuildConfiguration{Tests: []api.TestStepConfiguration{{
+			As:                         "unit",
+			ContainerTestConfiguration: &api.ContainerTestConfiguration{From: "src"},
+		}}},
+		expected: Components{Workflows: sets.New[string](), Chains: sets.New[string](), References: sets.New[string]()},
+	}, {
+		name: "workflow is expanded transitively",
+		config: api.ReleaseBuildConfiguration{Tests: []api.TestStepConfiguration{{
+			As:                          "e2e",
+			MultiStageTestConfiguration: &api.MultiStageTestConfiguration{Workflow: &ipi},
+		}}},
+		expected: Components{
+			Workflows:  sets.New[string](ipi),
+			Chains:     sets.New[string](ipiInstall, ipiDeprovision),
+			References: sets.New[string](ipiInstallInstall, ipiInstallRBAC, ipiDeprovisionMustGather, ipiDeprovisionDeprovision),
+		},
+	}, {
+		name: "nested chains and direct references across tests",
+		config: api.ReleaseBuildConfiguration{Tests: []api.TestStepConfiguration{{
+			As: "e2e",
+			MultiStageTestConfiguration: &api.MultiStageTestConfiguration{
+				Pre:  []api.TestStep{{Chain: &nested}},
+				Test: []api.TestStep{{Reference: &ipiConf}},
+			},
+		}, {
+			As: "other",
+			MultiStageTestConfiguration: &api.MultiStageTestConfiguration{
+				Test: []api.TestStep{{Reference: &ipiConf}, {Chain: &unknown}},
+			},
+		}}},
+		expected: Components{
+			Workflows:  sets.New[string](),
+			Chains:     sets.New[string](nested, ipiInstall, ipiDeprovision, unknown),
+			References: sets.New[string](ipiConf, ipiInstallInstall, ipiInstallRBAC, ipiDeprovisionMustGather, ipiDeprovisionDeprovision),
+		},
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := ComponentsForConfig(tc.config, chainMap, workflowMap)
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("unexpected components: %s", diff)
+			}
+		})
+	}
+}
+
+func TestUsage(t *testing.T) {
+	configs := NewUsageCounter(referenceMap, chainMap, workflowMap)
+	configs.Add(ComponentsForConfig(api.ReleaseBuildConfiguration{Tests: []api.TestStepConfiguration{{
+		As:                          "e2e",
+		MultiStageTestConfiguration: &api.MultiStageTestConfiguration{Workflow: &ipi},
+	}}}, chainMap, workflowMap))
+	requests := NewUsageCounter(nil, nil, nil)
+	for i := 0; i < 3; i++ {
+		requests.Add(Components{References: sets.New[string](ipiConf)})
+	}
+
+	expected := []ComponentUsage{
+		{Type: "workflow", Name: ipi, Configs: 1},
+		{Type: "chain", Name: ipiConfAWS},
+		{Type: "chain", Name: ipiDeprovision, Configs: 1},
+		{Type: "chain", Name: ipiInstall, Configs: 1},
+		{Type: "chain", Name: nested},
+		{Type: "reference", Name: ipiConf, Requests: 3},
+		{Type: "reference", Name: ipiConfAWS},
+		{Type: "reference", Name: ipiDeprovisionDeprovision, Configs: 1},
+		{Type: "reference", Name: ipiDeprovisionMustGather, Configs: 1},
+		{Type: "reference", Name: ipiInstallInstall, Configs: 1},
+		{Type: "reference", Name: ipiInstallRBAC, Configs: 1},
+	}
+	if diff := cmp.Diff(expected, Usage(configs, requests)); diff != "" {
+		t.Errorf("unexpected usage: %s", diff)
+	}
+}
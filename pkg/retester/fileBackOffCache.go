@@ -106,7 +106,8 @@ func check(cache *map[string]*pullRequest, pr tide.PullRequest, baseSha string,
 		(*cache)[key] = &pullRequest{}
 	}
 	record := (*cache)[key]
-	record.LastConsideredTime = metav1.Now()
+	now := metav1.Now()
+	record.LastConsideredTime = now
 	if currentPRSha := string(pr.HeadRefOID); record.PRSha != currentPRSha {
 		record.PRSha = currentPRSha
 		record.RetestsForPrSha = 0
@@ -123,12 +124,24 @@ func check(cache *map[string]*pullRequest, pr tide.PullRequest, baseSha string,
 		return retestBackoffHold, fmt.Sprintf("Revision %s was retested %d times: holding", record.PRSha, policy.MaxRetestsForSha)
 	}
 
+	if policy.MaxRetestsForPR > 0 && record.RetestsForPR >= policy.MaxRetestsForPR {
+		return retestBackoffHold, fmt.Sprintf("PR was retested %d times: holding", record.RetestsForPR)
+	}
+
 	if record.RetestsForBaseSha == policy.MaxRetestsForShaAndBase {
 		return retestBackoffPause, fmt.Sprintf("Revision %s was retested %d times against base HEAD %s: pausing", record.PRSha, policy.MaxRetestsForShaAndBase, record.BaseSha)
 	}
 
+	if policy.CoolDownAfter > 0 && record.LastRetestTime != nil && record.RetestsForPrSha > 0 && record.RetestsForPrSha%policy.CoolDownAfter == 0 {
+		if until := record.LastRetestTime.Add(policy.CoolDown.Duration); now.Time.Before(until) {
+			return retestBackoffPause, fmt.Sprintf("Revision %s failed %d consecutive retests: cooling down until %s", record.PRSha, record.RetestsForPrSha, until.UTC().Format(time.RFC3339))
+		}
+	}
+
 	record.RetestsForBaseSha++
 	record.RetestsForPrSha++
+	record.RetestsForPR++
+	record.LastRetestTime = &now
 
 	return retestBackoffRetest, fmt.Sprintf("Remaining retests: %d against base HEAD %s and %d for PR HEAD %s in total", policy.MaxRetestsForShaAndBase-record.RetestsForBaseSha, record.BaseSha, policy.MaxRetestsForSha-record.RetestsForPrSha, record.PRSha)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/test-infra/prow/config"
 	"k8s.io/test-infra/prow/git/v2"
 	"k8s.io/test-infra/prow/github"
@@ -31,11 +33,13 @@ type githubClient interface {
 
 // pullRequest represents GitHub PR and number of retests.
 type pullRequest struct {
-	PRSha              string      `json:"pr_sha,omitempty"`
-	BaseSha            string      `json:"base_sha,omitempty"`
-	RetestsForPrSha    int         `json:"retests_for_pr_sha,omitempty"`
-	RetestsForBaseSha  int         `json:"retests_for_base_sha,omitempty"`
-	LastConsideredTime metav1.Time `json:"last_considered_time,omitempty"`
+	PRSha              string       `json:"pr_sha,omitempty"`
+	BaseSha            string       `json:"base_sha,omitempty"`
+	RetestsForPrSha    int          `json:"retests_for_pr_sha,omitempty"`
+	RetestsForBaseSha  int          `json:"retests_for_base_sha,omitempty"`
+	RetestsForPR       int          `json:"retests_for_pr,omitempty"`
+	LastRetestTime     *metav1.Time `json:"last_retest_time,omitempty"`
+	LastConsideredTime metav1.Time  `json:"last_considered_time,omitempty"`
 }
 
 var (
@@ -87,6 +91,36 @@ type RetesterPolicy struct {
 	MaxRetestsForShaAndBase int   `json:"max_retests_for_sha_and_base,omitempty"`
 	MaxRetestsForSha        int   `json:"max_retests_for_sha,omitempty"`
 	Enabled                 *bool `json:"enabled,omitempty"`
+	// MaxRetestsForPR limits the retests of a PR across all its revisions. Zero means no limit.
+	MaxRetestsForPR int `json:"max_retests_for_pr,omitempty"`
+	// CoolDownAfter is the number of consecutive retests of a revision after which
+	// the retester waits for CoolDown before retesting it again.
+	CoolDownAfter int             `json:"cool_down_after,omitempty"`
+	CoolDown      metav1.Duration `json:"cool_down,omitempty"`
+	// RequiredLabels must all be present on a PR for it to be retested.
+	RequiredLabels []string `json:"required_labels,omitempty"`
+	// MaxRetestsPerSync limits the retests issued for a repo in a single sync, so
+	// that a single repo cannot consume the whole budget. Zero means no limit.
+	MaxRetestsPerSync int `json:"max_retests_per_sync,omitempty"`
+}
+
+// inheritLimits sets the limits that are not set in the policy from the parent
+func (p *RetesterPolicy) inheritLimits(parent RetesterPolicy) {
+	if p.MaxRetestsForPR == 0 {
+		p.MaxRetestsForPR = parent.MaxRetestsForPR
+	}
+	if p.CoolDownAfter == 0 {
+		p.CoolDownAfter = parent.CoolDownAfter
+	}
+	if p.CoolDown.Duration == 0 {
+		p.CoolDown = parent.CoolDown
+	}
+	if p.RequiredLabels == nil {
+		p.RequiredLabels = parent.RequiredLabels
+	}
+	if p.MaxRetestsPerSync == 0 {
+		p.MaxRetestsPerSync = parent.MaxRetestsPerSync
+	}
 }
 
 // LoadConfig loads retester configuration via file.
@@ -116,11 +150,14 @@ type RetestController struct {
 	backoff       backoffCache
 
 	config *Config
+
+	// retestsInSync counts the retests issued for each org/repo in the current sync
+	retestsInSync map[string]int
 }
 
 func (c *Config) GetRetesterPolicy(org, repo string) (RetesterPolicy, error) {
 	policy := RetesterPolicy{}
-	if c.Retester.RetesterPolicy == policy && len(c.Retester.Oranizations) == 0 {
+	if reflect.DeepEqual(c.Retester.RetesterPolicy, policy) && len(c.Retester.Oranizations) == 0 {
 		return policy, nil
 	}
 	if orgStruct, ok := c.Retester.Oranizations[org]; ok && orgStruct.Enabled != nil {
@@ -136,6 +173,7 @@ func (c *Config) GetRetesterPolicy(org, repo string) (RetesterPolicy, error) {
 				if repoStruct.MaxRetestsForShaAndBase != 0 {
 					policy.MaxRetestsForShaAndBase = repoStruct.MaxRetestsForShaAndBase
 				}
+				policy.inheritLimits(repoStruct.RetesterPolicy)
 			} else {
 				return RetesterPolicy{}, nil
 			}
@@ -148,6 +186,7 @@ func (c *Config) GetRetesterPolicy(org, repo string) (RetesterPolicy, error) {
 			if orgStruct.MaxRetestsForShaAndBase != 0 && policy.MaxRetestsForShaAndBase == 0 {
 				policy.MaxRetestsForShaAndBase = orgStruct.MaxRetestsForShaAndBase
 			}
+			policy.inheritLimits(orgStruct.RetesterPolicy)
 		}
 		if !*policy.Enabled && (c.Retester.Enabled == nil || !*c.Retester.Enabled) {
 			return RetesterPolicy{}, nil
@@ -162,6 +201,7 @@ func (c *Config) GetRetesterPolicy(org, repo string) (RetesterPolicy, error) {
 	if policy.MaxRetestsForShaAndBase == 0 {
 		policy.MaxRetestsForShaAndBase = c.Retester.MaxRetestsForShaAndBase
 	}
+	policy.inheritLimits(c.Retester.RetesterPolicy)
 	return policy, nil
 }
 
@@ -178,6 +218,18 @@ func validatePolicies(policy RetesterPolicy) []error {
 			if policy.MaxRetestsForSha < policy.MaxRetestsForShaAndBase {
 				errs = append(errs, fmt.Errorf("max_retest_for_sha value can't be lower than max_retests_for_sha_and_base value: %d < %d", policy.MaxRetestsForSha, policy.MaxRetestsForShaAndBase))
 			}
+			if policy.MaxRetestsForPR < 0 {
+				errs = append(errs, fmt.Errorf("max_retests_for_pr has invalid value: %d", policy.MaxRetestsForPR))
+			}
+			if policy.MaxRetestsPerSync < 0 {
+				errs = append(errs, fmt.Errorf("max_retests_per_sync has invalid value: %d", policy.MaxRetestsPerSync))
+			}
+			if policy.CoolDownAfter < 0 {
+				errs = append(errs, fmt.Errorf("cool_down_after has invalid value: %d", policy.CoolDownAfter))
+			}
+			if policy.CoolDownAfter > 0 && policy.CoolDown.Duration <= 0 {
+				errs = append(errs, fmt.Errorf("cool_down must be positive when cool_down_after is set"))
+			}
 		} else {
 			return nil
 		}
@@ -234,6 +286,9 @@ func (c *RetestController) runWithCandidates(candidates map[string]tide.PullRequ
 	candidates = c.enabledPRs(candidates)
 	logrus.Infof("Remaining %d candidates for retest (from an enabled org or repo)", len(candidates))
 
+	candidates = c.labeledPRs(candidates)
+	logrus.Infof("Remaining %d candidates for retest (have the required labels)", len(candidates))
+
 	candidates, err := c.atLeastOneRequiredJob(candidates)
 	if err != nil {
 		return fmt.Errorf("failed to filter candidate PRs that have at least one required job: %w", err)
@@ -244,6 +299,7 @@ func (c *RetestController) runWithCandidates(candidates map[string]tide.PullRequ
 		logrus.Infof("Candidate PR: %s", prUrl(pr))
 	}
 
+	c.retestsInSync = map[string]int{}
 	var errs []error
 	for _, pr := range candidates {
 		errs = append(errs, c.retestOrBackoff(pr))
@@ -282,6 +338,12 @@ func (c *RetestController) retestOrBackoff(pr tide.PullRequest) error {
 		return fmt.Errorf("failed to validate retester policy: %v", validationErrors)
 	}
 
+	orgRepo := fmt.Sprintf("%s/%s", org, repo)
+	if policy.MaxRetestsPerSync > 0 && c.retestsInSync[orgRepo] >= policy.MaxRetestsPerSync {
+		c.logger.Infof("%s: %s (%s)", prUrl(pr), "no comment", fmt.Sprintf("Repository %s was retested %d times in this sync: skipping", orgRepo, c.retestsInSync[orgRepo]))
+		return nil
+	}
+
 	action, message := c.backoff.check(pr, baseSha, policy)
 	switch action {
 	case retestBackoffHold:
@@ -289,6 +351,9 @@ func (c *RetestController) retestOrBackoff(pr tide.PullRequest) error {
 	case retestBackoffPause:
 		c.logger.Infof("%s: %s (%s)", prUrl(pr), "no comment", message)
 	case retestBackoffRetest:
+		if c.retestsInSync != nil {
+			c.retestsInSync[orgRepo]++
+		}
 		c.createComment(pr, "/retest-required", message)
 	}
 	return nil
@@ -517,6 +582,26 @@ func (c *RetestController) enabledPRs(candidates map[string]tide.PullRequest) ma
 	return output
 }
 
+func (c *RetestController) labeledPRs(candidates map[string]tide.PullRequest) map[string]tide.PullRequest {
+	output := map[string]tide.PullRequest{}
+	for key, pr := range candidates {
+		policy, err := c.config.GetRetesterPolicy(string(pr.Repository.Owner.Login), string(pr.Repository.Name))
+		if err != nil {
+			c.logger.WithError(err).Warn("Failed to get retester policy")
+		}
+		labels := sets.New[string]()
+		for _, label := range pr.Labels.Nodes {
+			labels.Insert(string(label.Name))
+		}
+		if missing := sets.New[string](policy.RequiredLabels...).Difference(labels); missing.Len() > 0 {
+			c.logger.Infof("PR %s is missing required labels: %s", key, strings.Join(sets.List(missing), ", "))
+			continue
+		}
+		output[key] = pr
+	}
+	return output
+}
+
 // headContexts gets the status contexts for the commit with OID == pr.HeadRefOID
 //
 // First, we try to get this value from the commits we got with the PR query.
@@ -18,6 +18,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	configflagutil "k8s.io/test-infra/prow/flagutil/config"
 	"k8s.io/test-infra/prow/github"
 	"k8s.io/test-infra/prow/github/fakegithub"
@@ -183,21 +184,21 @@ func TestGetRetesterPolicy(t *testing.T) {
 			org:      "openshift",
 			repo:     "ci-tools",
 			config:   c,
-			expected: RetesterPolicy{3, 3, &True},
+			expected: RetesterPolicy{MaxRetestsForShaAndBase: 3, MaxRetestsForSha: 3, Enabled: &True},
 		},
 		{
 			name:     "enabled repo with one max retest value and enabled org",
 			org:      "openshift",
 			repo:     "repo-max",
 			config:   c,
-			expected: RetesterPolicy{2, 6, &True},
+			expected: RetesterPolicy{MaxRetestsForShaAndBase: 2, MaxRetestsForSha: 6, Enabled: &True},
 		},
 		{
 			name:     "enabled repo and disabled org",
 			org:      "no-openshift",
 			repo:     "ci-tools",
 			config:   c,
-			expected: RetesterPolicy{4, 4, &True},
+			expected: RetesterPolicy{MaxRetestsForShaAndBase: 4, MaxRetestsForSha: 4, Enabled: &True},
 		},
 		{
 			name:   "disabled repo and enabled org",
@@ -210,7 +211,7 @@ func TestGetRetesterPolicy(t *testing.T) {
 			org:      "openshift",
 			repo:     "ci-docs",
 			config:   c,
-			expected: RetesterPolicy{2, 2, &True},
+			expected: RetesterPolicy{MaxRetestsForShaAndBase: 2, MaxRetestsForSha: 2, Enabled: &True},
 		},
 		{
 			name:   "not configured repo and disabled org",
@@ -223,7 +224,7 @@ func TestGetRetesterPolicy(t *testing.T) {
 			org:      "no-openshift",
 			repo:     "true",
 			config:   c,
-			expected: RetesterPolicy{3, 9, &True},
+			expected: RetesterPolicy{MaxRetestsForShaAndBase: 3, MaxRetestsForSha: 9, Enabled: &True},
 		},
 		{
 			name:   "not configured repo and not configured org",
@@ -253,6 +254,60 @@ func TestGetRetesterPolicy(t *testing.T) {
 	}
 }
 
+func TestGetRetesterPolicyLimits(t *testing.T) {
+	c := &Config{
+		Retester: Retester{
+			RetesterPolicy: RetesterPolicy{MaxRetestsForShaAndBase: 3, MaxRetestsForSha: 9, MaxRetestsForPR: 30, MaxRetestsPerSync: 10},
+			Oranizations: map[string]Oranization{
+				"openshift": {
+					RetesterPolicy: RetesterPolicy{
+						Enabled: &True, CoolDownAfter: 3, CoolDown: metav1.Duration{Duration: time.Hour}, RequiredLabels: []string{"lgtm"},
+					},
+					Repos: map[string]Repo{
+						"flaky": {RetesterPolicy: RetesterPolicy{
+							Enabled: &True, MaxRetestsForPR: 5, MaxRetestsPerSync: 1, RequiredLabels: []string{"lgtm", "approved"},
+						}},
+					}},
+			},
+		}}
+	testCases := []struct {
+		name     string
+		org      string
+		repo     string
+		expected RetesterPolicy
+	}{
+		{
+			name: "limits are inherited from org and global level",
+			org:  "openshift",
+			repo: "ci-tools",
+			expected: RetesterPolicy{
+				MaxRetestsForShaAndBase: 3, MaxRetestsForSha: 9, Enabled: &True, MaxRetestsForPR: 30, MaxRetestsPerSync: 10,
+				CoolDownAfter: 3, CoolDown: metav1.Duration{Duration: time.Hour}, RequiredLabels: []string{"lgtm"},
+			},
+		},
+		{
+			name: "repo level limits override the general ones",
+			org:  "openshift",
+			repo: "flaky",
+			expected: RetesterPolicy{
+				MaxRetestsForShaAndBase: 3, MaxRetestsForSha: 9, Enabled: &True, MaxRetestsForPR: 5, MaxRetestsPerSync: 1,
+				CoolDownAfter: 3, CoolDown: metav1.Duration{Duration: time.Hour}, RequiredLabels: []string{"lgtm", "approved"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := c.GetRetesterPolicy(tc.org, tc.repo)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("%s differs from expected:\n%s", tc.name, diff)
+			}
+		})
+	}
+}
+
 func TestValidatePolicies(t *testing.T) {
 
 	testCases := []struct {
@@ -262,27 +317,40 @@ func TestValidatePolicies(t *testing.T) {
 	}{
 		{
 			name:   "basic case",
-			policy: RetesterPolicy{3, 9, &True},
+			policy: RetesterPolicy{MaxRetestsForShaAndBase: 3, MaxRetestsForSha: 9, Enabled: &True},
 		},
 		{
 			name: "empty policy is valid",
 		},
 		{
 			name:   "disable",
-			policy: RetesterPolicy{-1, -1, &False},
+			policy: RetesterPolicy{MaxRetestsForShaAndBase: -1, MaxRetestsForSha: -1, Enabled: &False},
 		},
 		{
 			name:   "negative",
-			policy: RetesterPolicy{-1, -1, &True},
+			policy: RetesterPolicy{MaxRetestsForShaAndBase: -1, MaxRetestsForSha: -1, Enabled: &True},
 			expected: []error{
 				errors.New("max_retest_for_sha has invalid value: -1"),
 				errors.New("max_retests_for_sha_and_base has invalid value: -1")},
 		},
 		{
 			name:     "lower",
-			policy:   RetesterPolicy{9, 3, &True},
+			policy:   RetesterPolicy{MaxRetestsForShaAndBase: 9, MaxRetestsForSha: 3, Enabled: &True},
 			expected: []error{errors.New("max_retest_for_sha value can't be lower than max_retests_for_sha_and_base value: 3 < 9")},
 		},
+		{
+			name:   "negative limits",
+			policy: RetesterPolicy{MaxRetestsForShaAndBase: 3, MaxRetestsForSha: 9, Enabled: &True, MaxRetestsForPR: -1, MaxRetestsPerSync: -1, CoolDownAfter: -1},
+			expected: []error{
+				errors.New("max_retests_for_pr has invalid value: -1"),
+				errors.New("max_retests_per_sync has invalid value: -1"),
+				errors.New("cool_down_after has invalid value: -1")},
+		},
+		{
+			name:     "cool down without duration",
+			policy:   RetesterPolicy{MaxRetestsForShaAndBase: 3, MaxRetestsForSha: 9, Enabled: &True, CoolDownAfter: 2},
+			expected: []error{errors.New("cool_down must be positive when cool_down_after is set")},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -463,6 +531,78 @@ func TestEnabledPRs(t *testing.T) {
 	}
 }
 
+func TestLabeledPRs(t *testing.T) {
+	logger := logrus.NewEntry(logrus.StandardLogger())
+	c := &RetestController{
+		config: &Config{Retester: Retester{
+			RetesterPolicy: RetesterPolicy{MaxRetestsForShaAndBase: 1, MaxRetestsForSha: 1, Enabled: &True}, Oranizations: map[string]Oranization{
+				"openshift": {RetesterPolicy: RetesterPolicy{Enabled: &True, RequiredLabels: []string{"lgtm", "approved"}}},
+			},
+		}},
+		logger: logger,
+	}
+	pr := func(org string, labels ...string) tide.PullRequest {
+		ret := tide.PullRequest{Number: 1}
+		ret.Repository.Name = "ci-tools"
+		ret.Repository.Owner.Login = githubv4.String(org)
+		for _, label := range labels {
+			ret.Labels.Nodes = append(ret.Labels.Nodes, struct{ Name githubv4.String }{Name: githubv4.String(label)})
+		}
+		return ret
+	}
+	candidates := map[string]tide.PullRequest{
+		"all labels":     pr("openshift", "approved", "lgtm", "other"),
+		"missing label":  pr("openshift", "lgtm"),
+		"no requirement": pr("org-a"),
+	}
+	expected := map[string]tide.PullRequest{
+		"all labels":     candidates["all labels"],
+		"no requirement": candidates["no requirement"],
+	}
+	if diff := cmp.Diff(expected, c.labeledPRs(candidates)); diff != "" {
+		t.Errorf("labeled PRs differ from expected:\n%s", diff)
+	}
+}
+
+func TestRetestOrBackoffBudget(t *testing.T) {
+	logger := logrus.NewEntry(logrus.StandardLogger())
+	ghc := &MyFakeClient{fakegithub.NewFakeClient()}
+	ghc.PullRequests = map[int]*github.PullRequest{1: {}, 2: {}, 3: {}}
+	c := &RetestController{
+		ghClient: ghc,
+		logger:   logger,
+		backoff:  &fileBackoffCache{cache: map[string]*pullRequest{}, logger: logger},
+		config: &Config{Retester: Retester{
+			RetesterPolicy: RetesterPolicy{MaxRetestsForShaAndBase: 3, MaxRetestsForSha: 9}, Oranizations: map[string]Oranization{
+				"org": {RetesterPolicy: RetesterPolicy{Enabled: &True}, Repos: map[string]Repo{
+					"flaky": {RetesterPolicy: RetesterPolicy{Enabled: &True, MaxRetestsPerSync: 1}},
+				}},
+			},
+		}},
+		retestsInSync: map[string]int{},
+	}
+	pr := func(number int, repo string) tide.PullRequest {
+		ret := tide.PullRequest{Number: githubv4.Int(number)}
+		ret.Repository.Name = githubv4.String(repo)
+		ret.Repository.Owner.Login = "org"
+		return ret
+	}
+	for _, p := range []tide.PullRequest{pr(1, "flaky"), pr(2, "flaky"), pr(3, "repo")} {
+		if err := c.retestOrBackoff(p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	commented := sets.New[int]()
+	for number, comments := range ghc.IssueComments {
+		if len(comments) > 0 {
+			commented.Insert(number)
+		}
+	}
+	if diff := cmp.Diff([]int{1, 3}, sets.List(commented)); diff != "" {
+		t.Errorf("retested PRs differ from expected:\n%s", diff)
+	}
+}
+
 var (
 	now     = metav1.NewTime(time.Date(2022, 8, 18, 0, 0, 0, 0, time.UTC))
 	justNow = metav1.NewTime(now.Add(-time.Minute))
@@ -805,6 +945,8 @@ func TestCheck(t *testing.T) {
 		policy         RetesterPolicy
 		expected       retestBackoffAction
 		expectedString string
+		// expectedStringPrefix is used for messages containing the current time
+		expectedStringPrefix string
 	}{
 		{
 			name:  "hold PR",
@@ -816,7 +958,7 @@ func TestCheck(t *testing.T) {
 					Owner         struct{ Login githubv4.String }
 				}{Name: "repo", NameWithOwner: "org/repo", Owner: struct{ Login githubv4.String }{Login: "org"}},
 				HeadRefOID: "holdPR"},
-			policy:         RetesterPolicy{3, 9, &True},
+			policy:         RetesterPolicy{MaxRetestsForShaAndBase: 3, MaxRetestsForSha: 9, Enabled: &True},
 			expected:       0,
 			expectedString: "Revision holdPR was retested 9 times: holding",
 		},
@@ -830,7 +972,7 @@ func TestCheck(t *testing.T) {
 					Owner         struct{ Login githubv4.String }
 				}{Name: "repo", NameWithOwner: "org/repo", Owner: struct{ Login githubv4.String }{Login: "org"}},
 				HeadRefOID: "pausePR"},
-			policy:         RetesterPolicy{3, 9, &True},
+			policy:         RetesterPolicy{MaxRetestsForShaAndBase: 3, MaxRetestsForSha: 9, Enabled: &True},
 			expected:       1,
 			expectedString: "Revision pausePR was retested 3 times against base HEAD : pausing",
 		},
@@ -838,10 +980,34 @@ func TestCheck(t *testing.T) {
 			name:           "retest PR",
 			cache:          fileBackoffCache{cache: map[string]*pullRequest{}, logger: logger},
 			pr:             tide.PullRequest{HeadRefOID: "retestPR"},
-			policy:         RetesterPolicy{3, 9, &True},
+			policy:         RetesterPolicy{MaxRetestsForShaAndBase: 3, MaxRetestsForSha: 9, Enabled: &True},
 			expected:       2,
 			expectedString: "Remaining retests: 2 against base HEAD  and 8 for PR HEAD retestPR in total",
 		},
+		{
+			name:           "hold PR retested too many times in total",
+			cache:          fileBackoffCache{cache: map[string]*pullRequest{"#0": {PRSha: "newSha", RetestsForPR: 5}}, logger: logger},
+			pr:             tide.PullRequest{HeadRefOID: "flakyPR"},
+			policy:         RetesterPolicy{MaxRetestsForShaAndBase: 3, MaxRetestsForSha: 9, Enabled: &True, MaxRetestsForPR: 5},
+			expected:       0,
+			expectedString: "PR was retested 5 times: holding",
+		},
+		{
+			name:                 "cool down after consecutive failures",
+			cache:                fileBackoffCache{cache: map[string]*pullRequest{"#0": {PRSha: "coolPR", RetestsForPrSha: 2, RetestsForPR: 2, LastRetestTime: &metav1.Time{Time: time.Now().Add(-time.Minute)}}}, logger: logger},
+			pr:                   tide.PullRequest{HeadRefOID: "coolPR"},
+			policy:               RetesterPolicy{MaxRetestsForShaAndBase: 3, MaxRetestsForSha: 9, Enabled: &True, CoolDownAfter: 2, CoolDown: metav1.Duration{Duration: time.Hour}},
+			expected:             1,
+			expectedStringPrefix: "Revision coolPR failed 2 consecutive retests: cooling down until ",
+		},
+		{
+			name:           "retest after cool down",
+			cache:          fileBackoffCache{cache: map[string]*pullRequest{"#0": {PRSha: "coolPR", RetestsForPrSha: 2, RetestsForPR: 2, LastRetestTime: &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}}}, logger: logger},
+			pr:             tide.PullRequest{HeadRefOID: "coolPR"},
+			policy:         RetesterPolicy{MaxRetestsForShaAndBase: 3, MaxRetestsForSha: 9, Enabled: &True, CoolDownAfter: 2, CoolDown: metav1.Duration{Duration: time.Hour}},
+			expected:       2,
+			expectedString: "Remaining retests: 2 against base HEAD  and 6 for PR HEAD coolPR in total",
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -849,6 +1015,12 @@ func TestCheck(t *testing.T) {
 			if diff := cmp.Diff(tc.expected, actual); diff != "" {
 				t.Errorf("%s differs from expected:\n%s", tc.name, diff)
 			}
+			if tc.expectedStringPrefix != "" {
+				if !strings.HasPrefix(actualString, tc.expectedStringPrefix) {
+					t.Errorf("%s: expected message starting with %q, got %q", tc.name, tc.expectedStringPrefix, actualString)
+				}
+				return
+			}
 			if diff := cmp.Diff(tc.expectedString, actualString); diff != "" {
 				t.Errorf("%s differs from expected:\n%s", tc.name, diff)
 			}
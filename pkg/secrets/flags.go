@@ -51,6 +51,15 @@ func (o *CLIOptions) NewReadOnlyClient(censor *DynamicCensor) (ReadOnlyClient, e
 }
 
 func (o *CLIOptions) NewClient(censor *DynamicCensor) (Client, error) {
+	c, err := o.NewUnprefixedVaultClient()
+	if err != nil {
+		return nil, err
+	}
+	return NewVaultClient(c, o.VaultPrefix, censor), nil
+}
+
+// NewUnprefixedVaultClient constructs a client for the vault API that is not limited to the prefix
+func (o *CLIOptions) NewUnprefixedVaultClient() (*vaultclient.VaultClient, error) {
 	var c *vaultclient.VaultClient
 	var err error
 	if o.VaultRole != "" {
@@ -61,5 +70,5 @@ func (o *CLIOptions) NewClient(censor *DynamicCensor) (Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("Failed to construct vault client: %w", err)
 	}
-	return NewVaultClient(c, o.VaultPrefix, censor), nil
+	return c, nil
 }
@@ -1,6 +1,7 @@
 package secrets
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"sort"
@@ -22,38 +23,61 @@ type VaultClient interface {
 	UpsertKV(path string, data map[string]string) error
 }
 
+// keyNotFoundError is returned when an item exists, but does not have the requested key
+type keyNotFoundError struct {
+	path string
+	key  string
+}
+
+func (e *keyNotFoundError) Error() string {
+	return fmt.Sprintf("item at path %q has no key %q", e.path, e.key)
+}
+
+// IsKeyNotFound returns whether the error is caused by an item not having the requested key
+func IsKeyNotFound(err error) bool {
+	e := &keyNotFoundError{}
+	return errors.As(err, &e)
+}
+
+// dryRunClient writes the secrets to a file. It is safe for concurrent use.
 type dryRunClient struct {
+	lock sync.Mutex
 	file *os.File
 }
 
-func (d dryRunClient) SetFieldOnItem(itemName, fieldName string, fieldValue []byte) error {
+func (d *dryRunClient) SetFieldOnItem(itemName, fieldName string, fieldValue []byte) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 	_, err := fmt.Fprintf(d.file, "ItemName: %s\n\tField: \n\t\t %s: %s\n", itemName, fieldName, string(fieldValue))
 	return err
 }
 
-func (d dryRunClient) UpdateNotesOnItem(itemName, notes string) error {
+func (d *dryRunClient) UpdateNotesOnItem(itemName, notes string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 	_, err := fmt.Fprintf(d.file, "ItemName: %s\n\tNotes: %s\n", itemName, notes)
 	return err
 }
 
-func (d dryRunClient) GetFieldOnItem(_, _ string) ([]byte, error) {
-	return nil, nil
+// GetFieldOnItem never finds a field, as there are no items when running dry
+func (d *dryRunClient) GetFieldOnItem(itemName, fieldName string) ([]byte, error) {
+	return nil, &keyNotFoundError{path: itemName, key: fieldName}
 }
 
-func (d dryRunClient) GetInUseInformationForAllItems(_ string) (map[string]SecretUsageComparer, error) {
+func (d *dryRunClient) GetInUseInformationForAllItems(_ string) (map[string]SecretUsageComparer, error) {
 	return nil, nil
 }
 
-func (d dryRunClient) GetUserSecrets() (map[types.NamespacedName]map[string]string, error) {
+func (d *dryRunClient) GetUserSecrets() (map[types.NamespacedName]map[string]string, error) {
 	return nil, nil
 }
 
-func (d dryRunClient) HasItem(itemname string) (bool, error) {
+func (d *dryRunClient) HasItem(itemname string) (bool, error) {
 	return false, nil
 }
 
 func NewDryRunClient(outputFile *os.File) Client {
-	return dryRunClient{
+	return &dryRunClient{
 		file: outputFile,
 	}
 }
@@ -84,7 +108,7 @@ func (c *vaultClient) getKeyAtPath(path, key string) ([]byte, error) {
 	}
 	val, ok := response.Data[key]
 	if !ok {
-		return nil, fmt.Errorf("item at path %q has no key %q", path, key)
+		return nil, &keyNotFoundError{path: path, key: key}
 	}
 
 	return []byte(val), nil
@@ -0,0 +1,73 @@
+package secretstore
+
+import (
+	"fmt"
+	"sync"
+)
+
+// FakeClient is an in-memory Client for tests
+type FakeClient struct {
+	lock sync.Mutex
+	// Items maps the name of an item to the values of its fields, attachments and password
+	Items map[string]map[string]string
+	// Notes maps the name of an item to its notes
+	Notes map[string]string
+}
+
+var _ Client = &FakeClient{}
+
+// NewFakeClient returns an empty FakeClient
+func NewFakeClient() *FakeClient {
+	return &FakeClient{Items: map[string]map[string]string{}, Notes: map[string]string{}}
+}
+
+func (c *FakeClient) GetField(itemName, fieldName string) ([]byte, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	value, ok := c.Items[itemName][fieldName]
+	if !ok {
+		return nil, fmt.Errorf("field %s of item %s: %w", fieldName, itemName, ErrNotFound)
+	}
+	return []byte(value), nil
+}
+
+func (c *FakeClient) GetNotes(itemName string) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	notes, ok := c.Notes[itemName]
+	if !ok {
+		return "", fmt.Errorf("notes of item %s: %w", itemName, ErrNotFound)
+	}
+	return notes, nil
+}
+
+func (c *FakeClient) set(itemName, key string, value []byte) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.Items[itemName] == nil {
+		c.Items[itemName] = map[string]string{}
+	}
+	c.Items[itemName][key] = string(value)
+}
+
+func (c *FakeClient) SetField(itemName, fieldName string, value []byte) error {
+	c.set(itemName, fieldName, value)
+	return nil
+}
+
+func (c *FakeClient) SetAttachment(itemName, attachmentName string, content []byte) error {
+	c.set(itemName, attachmentName, content)
+	return nil
+}
+
+func (c *FakeClient) SetPassword(itemName string, password []byte) error {
+	c.set(itemName, PasswordField, password)
+	return nil
+}
+
+func (c *FakeClient) UpdateNotes(itemName, notes string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.Notes[itemName] = notes
+	return nil
+}
@@ -0,0 +1,203 @@
+package secretstore
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/openshift/ci-tools/pkg/secrets"
+)
+
+const (
+	// GSMEndpoint is the endpoint of the API of Google Secret Manager
+	GSMEndpoint = "https://secretmanager.googleapis.com"
+	// GSMScope is the OAuth scope needed to use Google Secret Manager
+	GSMScope = "https://www.googleapis.com/auth/cloud-platform"
+
+	gsmManagedByLabel = "managed-by"
+	gsmManagedBy      = "ci-secret-generator"
+)
+
+// gsmSecretIDRegex matches the valid IDs of secrets in Google Secret Manager
+var gsmSecretIDRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,255}$`)
+
+// NewGSMClient returns a client for Google Secret Manager. Every item is a secret named like
+// the item in the project, and the payload of its latest version is a JSON object mapping the
+// fields, attachments, password and notes of the item to their values. Every change of the
+// item adds a new version to the secret.
+func NewGSMClient(client *http.Client, endpoint, project string, censor *secrets.DynamicCensor) Client {
+	return &gsmClient{
+		client:  client,
+		baseURL: fmt.Sprintf("%s/v1/projects/%s/secrets", endpoint, url.PathEscape(project)),
+		censor:  censor,
+	}
+}
+
+type gsmClient struct {
+	client  *http.Client
+	baseURL string
+	censor  *secrets.DynamicCensor
+}
+
+type gsmPayload struct {
+	Data string `json:"data"`
+}
+
+// gsmError is an error response of Google Secret Manager
+type gsmError struct {
+	statusCode int
+	body       string
+}
+
+func (e *gsmError) Error() string {
+	return fmt.Sprintf("unexpected status code %d: %s", e.statusCode, e.body)
+}
+
+func (c *gsmClient) do(method, endpoint string, body interface{}, into interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequest(method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to %s %s: %w", method, endpoint, err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &gsmError{statusCode: resp.StatusCode, body: string(raw)}
+	}
+	if into == nil {
+		return nil
+	}
+	if err := json.Unmarshal(raw, into); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+func isGSMStatus(err error, statusCode int) bool {
+	e := &gsmError{}
+	return errors.As(err, &e) && e.statusCode == statusCode
+}
+
+func secretID(itemName string) (string, error) {
+	if !gsmSecretIDRegex.MatchString(itemName) {
+		return "", fmt.Errorf("item name %q is not a valid secret name in Google Secret Manager, which must match %s", itemName, gsmSecretIDRegex.String())
+	}
+	return itemName, nil
+}
+
+// getItem returns the fields of the latest version of the secret of the item
+func (c *gsmClient) getItem(itemName string) (map[string]string, error) {
+	id, err := secretID(itemName)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Payload gsmPayload `json:"payload"`
+	}
+	if err := c.do(http.MethodGet, fmt.Sprintf("%s/%s/versions/latest:access", c.baseURL, id), nil, &response); err != nil {
+		if isGSMStatus(err, http.StatusNotFound) {
+			return nil, fmt.Errorf("item %s: %w", itemName, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to access secret %s: %w", id, err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(response.Payload.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode secret %s: %w", id, err)
+	}
+	fields := map[string]string{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal secret %s: %w", id, err)
+	}
+	for _, value := range fields {
+		c.censor.AddSecrets(value)
+	}
+	return fields, nil
+}
+
+// setOnItem sets a field of the item, creating its secret if needed
+func (c *gsmClient) setOnItem(itemName, fieldName, value string) error {
+	id, err := secretID(itemName)
+	if err != nil {
+		return err
+	}
+	fields, err := c.getItem(itemName)
+	if err != nil {
+		if !IsNotFound(err) {
+			return err
+		}
+		create := map[string]interface{}{
+			"replication": map[string]interface{}{"automatic": map[string]interface{}{}},
+			"labels":      map[string]string{gsmManagedByLabel: gsmManagedBy},
+		}
+		// the secret may exist without any version
+		if err := c.do(http.MethodPost, fmt.Sprintf("%s?secretId=%s", c.baseURL, url.QueryEscape(id)), create, nil); err != nil && !isGSMStatus(err, http.StatusConflict) {
+			return fmt.Errorf("failed to create secret %s: %w", id, err)
+		}
+		fields = map[string]string{}
+	}
+	fields[fieldName] = value
+	c.censor.AddSecrets(value)
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal item %s: %w", itemName, err)
+	}
+	addVersion := map[string]interface{}{"payload": gsmPayload{Data: base64.StdEncoding.EncodeToString(raw)}}
+	if err := c.do(http.MethodPost, fmt.Sprintf("%s/%s:addVersion", c.baseURL, id), addVersion, nil); err != nil {
+		return fmt.Errorf("failed to add a version to secret %s: %w", id, err)
+	}
+	return nil
+}
+
+func (c *gsmClient) GetField(itemName, fieldName string) ([]byte, error) {
+	fields, err := c.getItem(itemName)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := fields[fieldName]
+	if !ok {
+		return nil, fmt.Errorf("field %s of item %s: %w", fieldName, itemName, ErrNotFound)
+	}
+	return []byte(value), nil
+}
+
+func (c *gsmClient) GetNotes(itemName string) (string, error) {
+	notes, err := c.GetField(itemName, NotesField)
+	return string(notes), err
+}
+
+func (c *gsmClient) SetField(itemName, fieldName string, value []byte) error {
+	return c.setOnItem(itemName, fieldName, string(value))
+}
+
+func (c *gsmClient) SetAttachment(itemName, attachmentName string, content []byte) error {
+	return c.setOnItem(itemName, attachmentName, string(content))
+}
+
+func (c *gsmClient) SetPassword(itemName string, password []byte) error {
+	return c.setOnItem(itemName, PasswordField, string(password))
+}
+
+func (c *gsmClient) UpdateNotes(itemName, notes string) error {
+	return c.setOnItem(itemName, NotesField, notes)
+}
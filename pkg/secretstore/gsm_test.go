@@ -0,0 +1,139 @@
+package secretstore
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/openshift/ci-tools/pkg/secrets"
+)
+
+// fakeGSM implements the parts of the API of Google Secret Manager used by the client
+type fakeGSM struct {
+	lock sync.Mutex
+	// secrets maps the ID of a secret to the payloads of its versions
+	secrets map[string][]string
+	labels  map[string]map[string]string
+}
+
+func (f *fakeGSM) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	path := strings.TrimPrefix(r.URL.Path, "/v1/projects/project/secrets")
+	switch {
+	case r.Method == http.MethodPost && path == "":
+		id := r.URL.Query().Get("secretId")
+		if _, ok := f.secrets[id]; ok {
+			http.Error(w, "exists", http.StatusConflict)
+			return
+		}
+		var body struct {
+			Labels map[string]string `json:"labels"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.secrets[id] = nil
+		f.labels[id] = body.Labels
+	case r.Method == http.MethodPost && strings.HasSuffix(path, ":addVersion"):
+		id := strings.TrimSuffix(strings.TrimPrefix(path, "/"), ":addVersion")
+		if _, ok := f.secrets[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		var body struct {
+			Payload gsmPayload `json:"payload"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.secrets[id] = append(f.secrets[id], body.Payload.Data)
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/versions/latest:access"):
+		id := strings.TrimSuffix(strings.TrimPrefix(path, "/"), "/versions/latest:access")
+		if len(f.secrets[id]) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"payload": gsmPayload{Data: f.secrets[id][len(f.secrets[id])-1]}})
+		return
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+		return
+	}
+	_, _ = w.Write([]byte("{}"))
+}
+
+func (f *fakeGSM) latest(t *testing.T, id string) map[string]string {
+	t.Helper()
+	versions := f.secrets[id]
+	raw, err := base64.StdEncoding.DecodeString(versions[len(versions)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := map[string]string{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		t.Fatal(err)
+	}
+	return fields
+}
+
+func TestGSMClient(t *testing.T) {
+	fake := &fakeGSM{secrets: map[string][]string{}, labels: map[string]map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	censor := secrets.NewDynamicCensor()
+	client := NewGSMClient(server.Client(), server.URL, "project", &censor)
+
+	if _, err := client.GetField("item", "field"); !IsNotFound(err) {
+		t.Errorf("expected a missing item not to be found, got %v", err)
+	}
+	for _, err := range []error{
+		client.SetField("item", "field", []byte("value")),
+		client.SetAttachment("item", "attachment", []byte("content")),
+		client.SetPassword("item", []byte("secret")),
+		client.UpdateNotes("item", "notes"),
+	} {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	expected := map[string]string{"field": "value", "attachment": "content", "password": "secret", "notes": "notes"}
+	if diff := cmp.Diff(expected, fake.latest(t, "item")); diff != "" {
+		t.Errorf("payload differs from expected: %s", diff)
+	}
+	if diff := cmp.Diff(map[string]string{"managed-by": "ci-secret-generator"}, fake.labels["item"]); diff != "" {
+		t.Errorf("labels differ from expected: %s", diff)
+	}
+	if n := len(fake.secrets["item"]); n != 4 {
+		t.Errorf("expected a version for every change, got %d", n)
+	}
+
+	value, err := client.GetField("item", "field")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(value) != "value" {
+		t.Errorf("expected value, got %q", value)
+	}
+	notes, err := client.GetNotes("item")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if notes != "notes" {
+		t.Errorf("expected notes, got %q", notes)
+	}
+	if _, err := client.GetField("item", "missing"); !IsNotFound(err) {
+		t.Errorf("expected a missing field not to be found, got %v", err)
+	}
+	if err := client.SetField("invalid/name", "field", []byte("value")); err == nil {
+		t.Error("expected an error for an invalid secret name")
+	}
+}
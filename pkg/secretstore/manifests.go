@@ -0,0 +1,136 @@
+package secretstore
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ManifestItemAnnotation is the annotation of the Secret manifests with the name of their item
+	ManifestItemAnnotation = "ci.openshift.io/secret-generator-item"
+	// ManifestNotesAnnotation is the annotation of the Secret manifests with the notes of their item
+	ManifestNotesAnnotation = "ci.openshift.io/secret-generator-notes"
+)
+
+// invalidSecretNameChars matches the characters that are not valid in the name of a Secret
+var invalidSecretNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// ManifestClient keeps the items in memory, to write them as Kubernetes Secrets
+// afterwards. It is safe for concurrent use.
+type ManifestClient struct {
+	lock  sync.Mutex
+	items map[string]map[string][]byte
+	notes map[string]string
+}
+
+var _ Client = &ManifestClient{}
+
+// NewManifestClient returns an empty ManifestClient
+func NewManifestClient() *ManifestClient {
+	return &ManifestClient{items: map[string]map[string][]byte{}, notes: map[string]string{}}
+}
+
+func (c *ManifestClient) GetField(itemName, fieldName string) ([]byte, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	value, ok := c.items[itemName][fieldName]
+	if !ok {
+		return nil, fmt.Errorf("field %s of item %s: %w", fieldName, itemName, ErrNotFound)
+	}
+	return value, nil
+}
+
+func (c *ManifestClient) GetNotes(itemName string) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	notes, ok := c.notes[itemName]
+	if !ok {
+		return "", fmt.Errorf("notes of item %s: %w", itemName, ErrNotFound)
+	}
+	return notes, nil
+}
+
+func (c *ManifestClient) set(itemName, key string, value []byte) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.items[itemName] == nil {
+		c.items[itemName] = map[string][]byte{}
+	}
+	c.items[itemName][key] = value
+}
+
+func (c *ManifestClient) SetField(itemName, fieldName string, value []byte) error {
+	c.set(itemName, fieldName, value)
+	return nil
+}
+
+func (c *ManifestClient) SetAttachment(itemName, attachmentName string, content []byte) error {
+	c.set(itemName, attachmentName, content)
+	return nil
+}
+
+func (c *ManifestClient) SetPassword(itemName string, password []byte) error {
+	c.set(itemName, PasswordField, password)
+	return nil
+}
+
+func (c *ManifestClient) UpdateNotes(itemName, notes string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.notes[itemName] = notes
+	return nil
+}
+
+// Secrets returns a Secret for every item, sorted by name. Items are named like the
+// Secrets after lowercasing them and replacing invalid characters with dashes.
+func (c *ManifestClient) Secrets() []corev1.Secret {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	names := map[string]struct{}{}
+	for name := range c.items {
+		names[name] = struct{}{}
+	}
+	for name := range c.notes {
+		names[name] = struct{}{}
+	}
+	var secrets []corev1.Secret
+	for name := range names {
+		secret := corev1.Secret{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        strings.Trim(invalidSecretNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-."),
+				Annotations: map[string]string{ManifestItemAnnotation: name},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: c.items[name],
+		}
+		if notes, ok := c.notes[name]; ok {
+			secret.Annotations[ManifestNotesAnnotation] = notes
+		}
+		secrets = append(secrets, secret)
+	}
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Annotations[ManifestItemAnnotation] < secrets[j].Annotations[ManifestItemAnnotation] })
+	return secrets
+}
+
+// WriteManifests writes the Secrets of the items as a multi-document YAML
+func (c *ManifestClient) WriteManifests(out io.Writer) error {
+	for _, secret := range c.Secrets() {
+		raw, err := yaml.Marshal(secret)
+		if err != nil {
+			return fmt.Errorf("failed to marshal secret %s: %w", secret.Name, err)
+		}
+		if _, err := fmt.Fprintf(out, "---\n%s", raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
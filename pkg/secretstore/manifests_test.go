@@ -0,0 +1,61 @@
+package secretstore
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestManifestClient(t *testing.T) {
+	client := NewManifestClient()
+	for _, err := range []error{
+		client.SetField("My_Item", "field", []byte("value")),
+		client.SetAttachment("My_Item", "attachment", []byte("content")),
+		client.UpdateNotes("My_Item", "notes"),
+		client.SetPassword("another", []byte("secret")),
+	} {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if value, err := client.GetField("My_Item", "field"); err != nil || string(value) != "value" {
+		t.Errorf("expected the value of the field, got %q and %v", value, err)
+	}
+	if _, err := client.GetField("My_Item", "missing"); !IsNotFound(err) {
+		t.Errorf("expected a missing field not to be found, got %v", err)
+	}
+
+	out := &bytes.Buffer{}
+	if err := client.WriteManifests(out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `---
+apiVersion: v1
+data:
+  attachment: Y29udGVudA==
+  field: dmFsdWU=
+kind: Secret
+metadata:
+  annotations:
+    ci.openshift.io/secret-generator-item: My_Item
+    ci.openshift.io/secret-generator-notes: notes
+  creationTimestamp: null
+  name: my-item
+type: Opaque
+---
+apiVersion: v1
+data:
+  password: c2VjcmV0
+kind: Secret
+metadata:
+  annotations:
+    ci.openshift.io/secret-generator-item: another
+  creationTimestamp: null
+  name: another
+type: Opaque
+`
+	if diff := cmp.Diff(expected, out.String()); diff != "" {
+		t.Errorf("manifests differ from expected: %s", diff)
+	}
+}
@@ -0,0 +1,68 @@
+package secretstore
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/time/rate"
+)
+
+// NewRateLimitedClient returns a client waiting for a token of the limiter before every
+// request to the upstream client, so that large runs do not trip the throttling of the store
+func NewRateLimitedClient(upstream Client, limiter *rate.Limiter) Client {
+	return &rateLimitedClient{upstream: upstream, limiter: limiter}
+}
+
+type rateLimitedClient struct {
+	upstream Client
+	limiter  *rate.Limiter
+}
+
+func (c *rateLimitedClient) wait() error {
+	if err := c.limiter.Wait(context.Background()); err != nil {
+		return fmt.Errorf("failed to wait for the rate limiter: %w", err)
+	}
+	return nil
+}
+
+func (c *rateLimitedClient) GetField(itemName, fieldName string) ([]byte, error) {
+	if err := c.wait(); err != nil {
+		return nil, err
+	}
+	return c.upstream.GetField(itemName, fieldName)
+}
+
+func (c *rateLimitedClient) GetNotes(itemName string) (string, error) {
+	if err := c.wait(); err != nil {
+		return "", err
+	}
+	return c.upstream.GetNotes(itemName)
+}
+
+func (c *rateLimitedClient) SetField(itemName, fieldName string, value []byte) error {
+	if err := c.wait(); err != nil {
+		return err
+	}
+	return c.upstream.SetField(itemName, fieldName, value)
+}
+
+func (c *rateLimitedClient) SetAttachment(itemName, attachmentName string, content []byte) error {
+	if err := c.wait(); err != nil {
+		return err
+	}
+	return c.upstream.SetAttachment(itemName, attachmentName, content)
+}
+
+func (c *rateLimitedClient) SetPassword(itemName string, password []byte) error {
+	if err := c.wait(); err != nil {
+		return err
+	}
+	return c.upstream.SetPassword(itemName, password)
+}
+
+func (c *rateLimitedClient) UpdateNotes(itemName, notes string) error {
+	if err := c.wait(); err != nil {
+		return err
+	}
+	return c.upstream.UpdateNotes(itemName, notes)
+}
@@ -0,0 +1,29 @@
+package secretstore
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimitedClient(t *testing.T) {
+	upstream := NewFakeClient()
+	client := NewRateLimitedClient(upstream, rate.NewLimiter(rate.Every(50*time.Millisecond), 1))
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := client.SetField("item", "field", []byte("value")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := client.GetField("item", "field"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// the first request uses the initial token, every further one waits for a new token
+	if elapsed := time.Since(start); elapsed < 5*50*time.Millisecond {
+		t.Errorf("expected the 6 requests to take at least 250ms, took %s", elapsed)
+	}
+	if value := upstream.Items["item"]["field"]; value != "value" {
+		t.Errorf("expected the field to be uploaded, got %q", value)
+	}
+}
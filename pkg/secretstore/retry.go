@@ -0,0 +1,83 @@
+package secretstore
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// IsRetryable returns whether an error of a secret store is transient, like a conflict, a rate
+// limit, a server error or a network error, so that the request may succeed when retried
+func IsRetryable(err error) bool {
+	if respErr := (&api.ResponseError{}); errors.As(err, &respErr) {
+		return isRetryableStatus(respErr.StatusCode)
+	}
+	if gsmErr := (&gsmError{}); errors.As(err, &gsmErr) {
+		return isRetryableStatus(gsmErr.statusCode)
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusConflict || statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// NewRetryingClient returns a client retrying the uploads to the upstream client up to maxRetries
+// times when they fail with a retryable error, waiting the backoff before the first retry and
+// doubling it for every further one. Reads are not retried.
+func NewRetryingClient(upstream Client, maxRetries int, backoff time.Duration) Client {
+	return &retryingClient{Client: upstream, maxRetries: maxRetries, backoff: backoff}
+}
+
+type retryingClient struct {
+	Client
+	maxRetries int
+	backoff    time.Duration
+}
+
+func (c *retryingClient) retry(itemName, what string, upload func() error) error {
+	var lastErr error
+	attempt := 0
+	err := wait.ExponentialBackoff(wait.Backoff{Steps: c.maxRetries + 1, Factor: 2, Duration: c.backoff}, func() (bool, error) {
+		attempt++
+		lastErr = upload()
+		if lastErr == nil {
+			return true, nil
+		}
+		if !IsRetryable(lastErr) {
+			return false, lastErr
+		}
+		if attempt <= c.maxRetries {
+			logrus.WithError(lastErr).WithFields(logrus.Fields{"item": itemName, "attempt": attempt}).Warnf("Failed to upload %s, retrying.", what)
+		}
+		return false, nil
+	})
+	if wait.Interrupted(err) {
+		return fmt.Errorf("failed after %d attempts: %w", attempt, lastErr)
+	}
+	return err
+}
+
+func (c *retryingClient) SetField(itemName, fieldName string, value []byte) error {
+	return c.retry(itemName, "field "+fieldName, func() error { return c.Client.SetField(itemName, fieldName, value) })
+}
+
+func (c *retryingClient) SetAttachment(itemName, attachmentName string, content []byte) error {
+	return c.retry(itemName, "attachment "+attachmentName, func() error { return c.Client.SetAttachment(itemName, attachmentName, content) })
+}
+
+func (c *retryingClient) SetPassword(itemName string, password []byte) error {
+	return c.retry(itemName, "password", func() error { return c.Client.SetPassword(itemName, password) })
+}
+
+func (c *retryingClient) UpdateNotes(itemName, notes string) error {
+	return c.retry(itemName, "notes", func() error { return c.Client.UpdateNotes(itemName, notes) })
+}
@@ -0,0 +1,107 @@
+package secretstore
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/vault/api"
+
+	"github.com/openshift/ci-tools/pkg/testhelper"
+)
+
+// failingClient fails the first uploads with the errors
+type failingClient struct {
+	*FakeClient
+	errs     []error
+	attempts int
+}
+
+func (c *failingClient) SetField(itemName, fieldName string, value []byte) error {
+	c.attempts++
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		return err
+	}
+	return c.FakeClient.SetField(itemName, fieldName, value)
+}
+
+func TestIsRetryable(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "vault conflict", err: &api.ResponseError{StatusCode: http.StatusConflict}, expected: true},
+		{name: "vault server error", err: &api.ResponseError{StatusCode: http.StatusBadGateway}, expected: true},
+		{name: "vault permission denied", err: &api.ResponseError{StatusCode: http.StatusForbidden}},
+		{name: "gsm rate limit", err: &gsmError{statusCode: http.StatusTooManyRequests}, expected: true},
+		{name: "gsm bad request", err: &gsmError{statusCode: http.StatusBadRequest}},
+		{name: "network error", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, expected: true},
+		{name: "other error", err: errors.New("invalid item")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := IsRetryable(tc.err); actual != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRetryingClient(t *testing.T) {
+	retryable := &api.ResponseError{StatusCode: http.StatusServiceUnavailable}
+	fatal := &api.ResponseError{StatusCode: http.StatusForbidden}
+	testCases := []struct {
+		name             string
+		errs             []error
+		expectedAttempts int
+		expectedErr      error
+		expectedItems    map[string]map[string]string
+	}{
+		{
+			name:             "success",
+			expectedAttempts: 1,
+			expectedItems:    map[string]map[string]string{"item": {"field": "value"}},
+		},
+		{
+			name:             "retryable errors are retried",
+			errs:             []error{retryable, retryable},
+			expectedAttempts: 3,
+			expectedItems:    map[string]map[string]string{"item": {"field": "value"}},
+		},
+		{
+			name:             "fatal errors are not retried",
+			errs:             []error{fatal},
+			expectedAttempts: 1,
+			expectedErr:      fatal,
+			expectedItems:    map[string]map[string]string{},
+		},
+		{
+			name:             "retries are limited",
+			errs:             []error{retryable, retryable, retryable, retryable},
+			expectedAttempts: 3,
+			expectedErr:      errors.New("failed after 3 attempts: " + retryable.Error()),
+			expectedItems:    map[string]map[string]string{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			upstream := &failingClient{FakeClient: NewFakeClient(), errs: tc.errs}
+			client := NewRetryingClient(upstream, 2, 0)
+			err := client.SetField("item", "field", []byte("value"))
+			if diff := cmp.Diff(tc.expectedErr, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("error differs from expected: %s", diff)
+			}
+			if upstream.attempts != tc.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d", tc.expectedAttempts, upstream.attempts)
+			}
+			if diff := cmp.Diff(tc.expectedItems, upstream.Items); diff != "" {
+				t.Errorf("items differ from expected: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Package secretstore abstracts the stores secrets are uploaded to, so that the tools
+// generating secrets do not depend on a specific one.
+package secretstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/openshift/ci-tools/pkg/secrets"
+	"github.com/openshift/ci-tools/pkg/vaultclient"
+)
+
+const (
+	// PasswordField is the field the password of an item is stored in by key-value stores
+	PasswordField = "password"
+	// NotesField is the field the notes of an item are stored in by key-value stores
+	NotesField = "notes"
+)
+
+// ErrNotFound is returned when reading an item or a field that does not exist
+var ErrNotFound = errors.New("not found")
+
+// IsNotFound returns whether the error is caused by reading an item or a field that does not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// Reader reads the parts of items from a secret store
+type Reader interface {
+	// GetField returns the value of a field, attachment or password of an item
+	GetField(itemName, fieldName string) ([]byte, error)
+	// GetNotes returns the notes of an item
+	GetNotes(itemName string) (string, error)
+}
+
+// Client reads and uploads the parts of items in a secret store
+type Client interface {
+	Reader
+	SetField(itemName, fieldName string, value []byte) error
+	SetAttachment(itemName, attachmentName string, content []byte) error
+	SetPassword(itemName string, password []byte) error
+	UpdateNotes(itemName, notes string) error
+}
+
+// NewKVClient returns a client for key-value stores like Vault, in which every item
+// is a set of keys: attachments are stored like fields and the password is stored in
+// the PasswordField.
+func NewKVClient(upstream secrets.Client) Client {
+	return &kvClient{upstream: upstream}
+}
+
+type kvClient struct {
+	upstream secrets.Client
+}
+
+func (c *kvClient) GetField(itemName, fieldName string) ([]byte, error) {
+	value, err := c.upstream.GetFieldOnItem(itemName, fieldName)
+	if vaultclient.IsNotFound(err) || secrets.IsKeyNotFound(err) {
+		return nil, fmt.Errorf("field %s of item %s: %w", fieldName, itemName, ErrNotFound)
+	}
+	return value, err
+}
+
+func (c *kvClient) GetNotes(itemName string) (string, error) {
+	notes, err := c.GetField(itemName, NotesField)
+	return string(notes), err
+}
+
+func (c *kvClient) SetField(itemName, fieldName string, value []byte) error {
+	return c.upstream.SetFieldOnItem(itemName, fieldName, value)
+}
+
+func (c *kvClient) SetAttachment(itemName, attachmentName string, content []byte) error {
+	return c.upstream.SetFieldOnItem(itemName, attachmentName, content)
+}
+
+func (c *kvClient) SetPassword(itemName string, password []byte) error {
+	return c.upstream.SetFieldOnItem(itemName, PasswordField, password)
+}
+
+func (c *kvClient) UpdateNotes(itemName, notes string) error {
+	return c.upstream.UpdateNotesOnItem(itemName, notes)
+}
@@ -0,0 +1,40 @@
+package secretstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/openshift/ci-tools/pkg/secrets"
+)
+
+func TestKVClient(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "secrets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewKVClient(secrets.NewDryRunClient(f))
+	for _, err := range []error{
+		client.SetField("item", "field", []byte("value")),
+		client.SetAttachment("item", "attachment", []byte("content")),
+		client.SetPassword("item", []byte("secret")),
+		client.UpdateNotes("item", "notes"),
+	} {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	actual, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "ItemName: item\n\tField: \n\t\t field: value\n" +
+		"ItemName: item\n\tField: \n\t\t attachment: content\n" +
+		"ItemName: item\n\tField: \n\t\t password: secret\n" +
+		"ItemName: item\n\tNotes: notes\n"
+	if diff := cmp.Diff(expected, string(actual)); diff != "" {
+		t.Errorf("uploaded secrets differ from expected: %s", diff)
+	}
+}
@@ -40,6 +40,9 @@ type clusterClaimStep struct {
 	jobSpec      *api.JobSpec
 	wrapped      api.Step
 	censor       *secrets.DynamicCensor
+
+	// pool is the cluster pool the cluster was claimed from
+	pool *hivev1.ClusterPool
 }
 
 func (s clusterClaimStep) Inputs() (api.InputDefinition, error) {
@@ -60,7 +63,32 @@ func (s *clusterClaimStep) Description() string                 { return s.wrapp
 func (s *clusterClaimStep) Requires() []api.StepLink            { return s.wrapped.Requires() }
 func (s *clusterClaimStep) Creates() []api.StepLink             { return s.wrapped.Creates() }
 func (s *clusterClaimStep) Objects() []ctrlruntimeclient.Object { return s.wrapped.Objects() }
-func (s *clusterClaimStep) Provides() api.ParameterMap          { return s.wrapped.Provides() }
+
+func (s *clusterClaimStep) Provides() api.ParameterMap {
+	parameters := s.wrapped.Provides()
+	if parameters == nil {
+		parameters = api.ParameterMap{}
+	}
+	fromPool := func(value func(pool *hivev1.ClusterPool) string) func() (string, error) {
+		return func() (string, error) {
+			if s.pool == nil {
+				return "", nil
+			}
+			return value(s.pool), nil
+		}
+	}
+	parameters[api.ClusterClaimPoolEnv] = fromPool(func(pool *hivev1.ClusterPool) string { return pool.Name })
+	parameters[api.ClusterClaimPoolNamespaceEnv] = fromPool(func(pool *hivev1.ClusterPool) string { return pool.Namespace })
+	parameters[api.ClusterClaimPoolLabelsEnv] = fromPool(func(pool *hivev1.ClusterPool) string {
+		labels := make([]string, 0, len(pool.Labels))
+		for k, v := range pool.Labels {
+			labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+		}
+		sort.Strings(labels)
+		return strings.Join(labels, ",")
+	})
+	return parameters
+}
 
 func (s *clusterClaimStep) Run(ctx context.Context) error {
 	return results.ForReason("utilizing_cluster_claim").ForError(s.run(ctx))
@@ -128,6 +156,7 @@ func (s *clusterClaimStep) acquireCluster(ctx context.Context, waitForClaim func
 		return nil, err
 	}
 	logrus.Infof("Claiming cluster from pool %s/%s owned by %s", clusterPool.Namespace, clusterPool.Name, clusterPool.Labels["owner"])
+	s.pool = clusterPool
 
 	claimName := s.jobSpec.ProwJobID
 	claimNamespace := clusterPool.Namespace
@@ -334,3 +334,33 @@ func (client *clusterClaimStatusSettingClient) Create(ctx context.Context, obj c
 	}
 	return client.WithWatch.Create(ctx, obj, opts...)
 }
+
+func TestClusterClaimStepProvides(t *testing.T) {
+	s := &clusterClaimStep{wrapped: &fakeStep{name: "e2e"}}
+	values := func() map[string]string {
+		ret := map[string]string{}
+		for name, fn := range s.Provides() {
+			value, err := fn()
+			if err != nil {
+				t.Fatalf("failed to get %s: %v", name, err)
+			}
+			ret[name] = value
+		}
+		return ret
+	}
+	if diff := cmp.Diff(map[string]string{
+		"CLUSTER_CLAIM_POOL":           "",
+		"CLUSTER_CLAIM_POOL_NAMESPACE": "",
+		"CLUSTER_CLAIM_POOL_LABELS":    "",
+	}, values()); diff != "" {
+		t.Errorf("unexpected parameters before the cluster is claimed: %s", diff)
+	}
+	s.pool = aClusterPool()
+	if diff := cmp.Diff(map[string]string{
+		"CLUSTER_CLAIM_POOL":           "ci-ocp-4.7.0-amd64-aws-us-east-1",
+		"CLUSTER_CLAIM_POOL_NAMESPACE": "ci-cluster-pool",
+		"CLUSTER_CLAIM_POOL_LABELS":    "architecture=amd64,cloud=aws,owner=dpp,product=ocp,region=us-east-1,version=4.7.0",
+	}, values()); diff != "" {
+		t.Errorf("unexpected parameters after the cluster is claimed: %s", diff)
+	}
+}
@@ -0,0 +1,172 @@
+package steps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	coreapi "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	imagev1 "github.com/openshift/api/image/v1"
+
+	"github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/results"
+	"github.com/openshift/ci-tools/pkg/secrets"
+	"github.com/openshift/ci-tools/pkg/steps/loggingclient"
+	"github.com/openshift/ci-tools/pkg/steps/utils"
+)
+
+// externalImagesArtifactDir is where the resolutions of the external images are recorded
+const externalImagesArtifactDir = "external-images"
+
+// ExternalImageResolution records the digest an external image was resolved to,
+// so that the run can be reproduced with the same image.
+type ExternalImageResolution struct {
+	// Name is the name of the external image, its tag in the pipeline image stream
+	Name string `json:"name"`
+	// PullSpec is the pull spec of the image in the configuration
+	PullSpec string `json:"pull_spec"`
+	// Digest is the digest the pull spec was resolved to
+	Digest string `json:"digest"`
+	// ResolvedPullSpec is the pull spec of the image by digest
+	ResolvedPullSpec string `json:"resolved_pull_spec"`
+}
+
+// externalImageStep resolves an image outside of image streams
+// to a digest and tags it into the pipeline ImageStream
+type externalImageStep struct {
+	name    string
+	config  api.ExternalImage
+	client  loggingclient.LoggingClient
+	jobSpec *api.JobSpec
+	censor  *secrets.DynamicCensor
+}
+
+func (s *externalImageStep) Inputs() (api.InputDefinition, error) {
+	return api.InputDefinition{s.config.PullSpec}, nil
+}
+
+func (*externalImageStep) Validate() error { return nil }
+
+func (s *externalImageStep) Run(ctx context.Context) error {
+	return results.ForReason("importing_external_image").ForError(s.run(ctx))
+}
+
+func (s *externalImageStep) run(ctx context.Context) error {
+	logrus.Infof("Importing external image %s into %s:%s.", s.config.PullSpec, api.PipelineImageStream, s.name)
+	streamImport := &imagev1.ImageStreamImport{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: s.jobSpec.Namespace(),
+			Name:      api.PipelineImageStream,
+		},
+		Spec: imagev1.ImageStreamImportSpec{
+			Import: true,
+			Images: []imagev1.ImageImportSpec{{
+				To: &coreapi.LocalObjectReference{
+					Name: s.name,
+				},
+				From: coreapi.ObjectReference{
+					Kind: "DockerImage",
+					Name: s.config.PullSpec,
+				},
+				ImportPolicy: imagev1.TagImportPolicy{
+					ImportMode: imagev1.ImportModePreserveOriginal,
+				},
+				ReferencePolicy: imagev1.TagReferencePolicy{
+					Type: imagev1.LocalTagReferencePolicy,
+				},
+			}},
+		},
+	}
+	var message string
+	if err := wait.ExponentialBackoff(wait.Backoff{Steps: 4, Duration: 1 * time.Second, Factor: 2}, func() (bool, error) {
+		attempt := streamImport.DeepCopy()
+		if err := s.client.Create(ctx, attempt); err != nil {
+			if kerrors.IsConflict(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		if len(attempt.Status.Images) == 0 {
+			return false, nil
+		}
+		image := attempt.Status.Images[0]
+		if image.Image == nil {
+			message = image.Status.Message
+			return false, nil
+		}
+		streamImport = attempt
+		return true, nil
+	}); err != nil {
+		if wait.Interrupted(err) && message != "" {
+			err = fmt.Errorf("%w: %s", err, message)
+		}
+		return fmt.Errorf("unable to import external image %s from %s: %w", s.name, s.config.PullSpec, err)
+	}
+
+	image := streamImport.Status.Images[0].Image
+	resolution := ExternalImageResolution{
+		Name:             s.name,
+		PullSpec:         s.config.PullSpec,
+		Digest:           image.Name,
+		ResolvedPullSpec: image.DockerImageReference,
+	}
+	logrus.Infof("Resolved external image %s (%s) to %s.", s.name, s.config.PullSpec, resolution.Digest)
+	data, err := json.MarshalIndent(resolution, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the resolution of external image %s: %w", s.name, err)
+	}
+	if err := api.SaveArtifact(s.censor, filepath.Join(externalImagesArtifactDir, s.name+".json"), data); err != nil {
+		logrus.WithError(err).Warnf("Failed to record the resolution of external image %s.", s.name)
+	}
+	return nil
+}
+
+func (s *externalImageStep) Requires() []api.StepLink {
+	return nil
+}
+
+func (s *externalImageStep) Creates() []api.StepLink {
+	return []api.StepLink{api.InternalImageLink(api.PipelineImageStreamTagReference(s.name))}
+}
+
+func (s *externalImageStep) Provides() api.ParameterMap {
+	tag := api.PipelineImageStreamTagReference(s.name)
+	return api.ParameterMap{
+		utils.PipelineImageEnvFor(tag): utils.ImageDigestFor(s.client, s.jobSpec.Namespace, api.PipelineImageStream, string(tag)),
+	}
+}
+
+func (s *externalImageStep) Name() string { return fmt.Sprintf("[external:%s]", s.name) }
+
+func (s *externalImageStep) Description() string {
+	return fmt.Sprintf("Resolve the external image %s to a digest and tag it into the pipeline", s.config.PullSpec)
+}
+
+func (s *externalImageStep) Objects() []ctrlruntimeclient.Object {
+	return s.client.Objects()
+}
+
+func ExternalImageStep(
+	name string,
+	config api.ExternalImage,
+	client loggingclient.LoggingClient,
+	jobSpec *api.JobSpec,
+	censor *secrets.DynamicCensor,
+) api.Step {
+	return &externalImageStep{
+		name:    name,
+		config:  config,
+		client:  client,
+		jobSpec: jobSpec,
+		censor:  censor,
+	}
+}
@@ -0,0 +1,98 @@
+package steps
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	imagev1 "github.com/openshift/api/image/v1"
+
+	"github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/secrets"
+	"github.com/openshift/ci-tools/pkg/steps/loggingclient"
+	"github.com/openshift/ci-tools/pkg/testhelper"
+)
+
+// importingClient resolves the ImageStreamImports it creates, like the
+// image API does synchronously
+type importingClient struct {
+	ctrlruntimeclient.WithWatch
+	err error
+}
+
+func (c *importingClient) Create(ctx context.Context, obj ctrlruntimeclient.Object, opts ...ctrlruntimeclient.CreateOption) error {
+	streamImport, ok := obj.(*imagev1.ImageStreamImport)
+	if !ok {
+		return c.WithWatch.Create(ctx, obj, opts...)
+	}
+	if c.err != nil {
+		return c.err
+	}
+	for range streamImport.Spec.Images {
+		streamImport.Status.Images = append(streamImport.Status.Images, imagev1.ImageImportStatus{
+			Image: &imagev1.Image{
+				ObjectMeta:           metav1.ObjectMeta{Name: "sha256:4f3e5b"},
+				DockerImageReference: "quay.io/org/tool@sha256:4f3e5b",
+			},
+		})
+	}
+	return nil
+}
+
+func TestExternalImageStep(t *testing.T) {
+	config := api.ExternalImage{PullSpec: "quay.io/org/tool:latest"}
+	jobSpec := &api.JobSpec{}
+	jobSpec.SetNamespace("namespace")
+	censor := secrets.NewDynamicCensor()
+
+	examineStep(t, ExternalImageStep("tool", config, loggingclient.New(fakectrlruntimeclient.NewClientBuilder().Build()), jobSpec, &censor), stepExpectation{
+		name:    "[external:tool]",
+		creates: []api.StepLink{api.InternalImageLink("tool")},
+		inputs:  inputsExpectation{values: api.InputDefinition{"quay.io/org/tool:latest"}},
+	})
+
+	for _, tc := range []struct {
+		name        string
+		err         error
+		expected    string
+		expectedErr error
+	}{{
+		name: "image is resolved and recorded",
+		expected: `{
+  "name": "tool",
+  "pull_spec": "quay.io/org/tool:latest",
+  "digest": "sha256:4f3e5b",
+  "resolved_pull_spec": "quay.io/org/tool@sha256:4f3e5b"
+}`,
+	}, {
+		name:        "import fails",
+		err:         errors.New("forbidden"),
+		expectedErr: errors.New("unable to import external image tool from quay.io/org/tool:latest: forbidden"),
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			artifacts := t.TempDir()
+			t.Setenv("ARTIFACTS", artifacts)
+			client := loggingclient.New(&importingClient{WithWatch: fakectrlruntimeclient.NewClientBuilder().Build(), err: tc.err})
+			step := ExternalImageStep("tool", config, client, jobSpec, &censor)
+			err := step.Run(context.Background())
+			if diff := cmp.Diff(tc.expectedErr, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Fatalf("unexpected error: %s", diff)
+			}
+			var recorded string
+			if data, err := os.ReadFile(filepath.Join(artifacts, "external-images", "tool.json")); err == nil {
+				recorded = string(data)
+			}
+			if diff := cmp.Diff(tc.expected, recorded); diff != "" {
+				t.Errorf("unexpected resolution: %s", diff)
+			}
+		})
+	}
+}
@@ -31,11 +31,19 @@ func (s *multiStageTestStep) generateObservers(
 	secretVolumeMounts []coreapi.VolumeMount,
 	genPodOpts *generatePodOptions,
 ) ([]coreapi.Pod, error) {
+	if genPodOpts == nil {
+		genPodOpts = defaultGeneratePodOptions()
+	}
 	var adapted []api.LiteralTestStep
 	for _, observer := range observers {
+		name := observer.Name
+		if genPodOpts.ObservedStep != "" {
+			// the observer runs once for every step it is attached to
+			name = fmt.Sprintf("%s-%s", observer.Name, genPodOpts.ObservedStep)
+		}
 		// observers are just like steps, so we can adapt one to the other
 		adapted = append(adapted, api.LiteralTestStep{
-			As:          observer.Name,
+			As:          name,
 			From:        observer.From,
 			FromImage:   observer.FromImage,
 			Commands:    observer.Commands,
@@ -46,11 +54,50 @@ func (s *multiStageTestStep) generateObservers(
 		})
 	}
 	pods, _, err := s.generatePods(adapted, nil, secretVolumes, secretVolumeMounts, genPodOpts)
-	return pods, err
+	if err != nil {
+		return nil, err
+	}
+	// observers are never skipped, so there is a pod for each of them
+	for i := range pods {
+		addObserverLifecycle(&pods[i], observers[i])
+	}
+	return pods, nil
+}
+
+// addObserverLifecycle sets up the readiness of an observer and the file it is
+// told how what it observed ended in before it is signalled to terminate
+func addObserverLifecycle(pod *coreapi.Pod, observer api.Observer) {
+	pod.Labels[base_steps.LabelMetadataStep] = observer.Name
+	pod.Spec.Volumes = append(pod.Spec.Volumes, coreapi.Volume{
+		Name:         observerVolumeName,
+		VolumeSource: coreapi.VolumeSource{EmptyDir: &coreapi.EmptyDirVolumeSource{}},
+	})
+	container := &pod.Spec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, coreapi.VolumeMount{
+		Name:      observerVolumeName,
+		MountPath: ObserverTerminationMountPath,
+	})
+	container.Env = append(container.Env, coreapi.EnvVar{
+		Name:  api.ObserverTerminationFileEnv,
+		Value: observerTerminationFile,
+	})
+	if observer.Readiness != nil {
+		container.ReadinessProbe = &coreapi.Probe{
+			ProbeHandler: coreapi.ProbeHandler{
+				Exec: &coreapi.ExecAction{Command: []string{"/bin/bash", "-c", CommandPrefix + observer.Readiness.Commands}},
+			},
+		}
+	}
 }
 
 type generatePodOptions struct {
 	IsObserver bool
+	// ObservedStep is the step the observers the pods are generated for are
+	// attached to, empty for the observers of the whole test
+	ObservedStep string
+	// Attempt is the attempt of the steps the pods are generated for, the
+	// pods of the retries of a step are named and save their artifacts by it
+	Attempt int
 }
 
 func defaultGeneratePodOptions() *generatePodOptions {
@@ -113,6 +160,10 @@ func (s *multiStageTestStep) generatePods(
 			return &i
 		}
 		artifactDir := fmt.Sprintf("%s/%s", s.name, step.As)
+		if genPodOpts.Attempt > 1 {
+			name = fmt.Sprintf("%s-attempt-%d", name, genPodOpts.Attempt)
+			artifactDir = fmt.Sprintf("%s/attempt-%d", artifactDir, genPodOpts.Attempt)
+		}
 		timeout := entrypoint.DefaultTimeout
 		if step.Timeout != nil {
 			timeout = step.Timeout.Duration
@@ -231,6 +282,9 @@ func (s *multiStageTestStep) generatePods(
 			addCliInjector(imagestream, pod)
 		}
 		addSharedDirSecret(s.name, pod)
+		if s.cache != nil && !genPodOpts.IsObserver {
+			addCache(cacheName(s.name), pod)
+		}
 		addCredentials(step.Credentials, pod)
 		if step.RunAsScript != nil && *step.RunAsScript {
 			addCommandScript(commandConfigMapForTest(s.name), pod)
@@ -534,6 +588,23 @@ func addSharedDirSecret(secret string, pod *coreapi.Pod) {
 	})
 }
 
+func addCache(claim string, pod *coreapi.Pod) {
+	pod.Spec.Volumes = append(pod.Spec.Volumes, coreapi.Volume{
+		Name: claim,
+		VolumeSource: coreapi.VolumeSource{
+			PersistentVolumeClaim: &coreapi.PersistentVolumeClaimVolumeSource{ClaimName: claim},
+		},
+	})
+	pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, coreapi.VolumeMount{
+		Name:      claim,
+		MountPath: CacheMountPath,
+	})
+	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, coreapi.EnvVar{
+		Name:  CacheMountEnv,
+		Value: CacheMountPath,
+	})
+}
+
 func addCredentials(credentials []api.CredentialReference, pod *coreapi.Pod) {
 	for _, credential := range credentials {
 		name := fmt.Sprintf("%s-%s", credential.Namespace, credential.Name)
@@ -3,6 +3,7 @@ package multi_stage
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	prowdapi "k8s.io/test-infra/prow/pod-utils/downwardapi"
 
 	"github.com/openshift/ci-tools/pkg/api"
+	base_steps "github.com/openshift/ci-tools/pkg/steps"
 	"github.com/openshift/ci-tools/pkg/testhelper"
 )
 
@@ -121,9 +123,10 @@ func TestGenerateObservers(t *testing.T) {
 		Timeout:     &prowapi.Duration{Duration: 2 * time.Minute},
 		GracePeriod: &prowapi.Duration{Duration: 4 * time.Second},
 	}, {
-		Name:     "observer1",
-		From:     "src",
-		Commands: "command1",
+		Name:      "observer1",
+		From:      "src",
+		Commands:  "command1",
+		Readiness: &api.ObserverReadiness{Commands: "test -f /tmp/ready"},
 	}}
 	jobSpec := api.JobSpec{
 		Metadata: api.Metadata{
@@ -313,6 +316,144 @@ func TestGeneratePodBestEffort(t *testing.T) {
 	}
 }
 
+func TestGeneratePodsAttempt(t *testing.T) {
+	config := api.ReleaseBuildConfiguration{
+		Tests: []api.TestStepConfiguration{{
+			As: "test",
+			MultiStageTestConfigurationLiteral: &api.MultiStageTestConfigurationLiteral{
+				Test: []api.LiteralTestStep{{
+					As:       "step0",
+					From:     "src",
+					Commands: "command0",
+					Retries:  &api.StepRetries{Count: 1},
+				}},
+			},
+		}},
+	}
+	jobSpec := api.JobSpec{
+		JobSpec: prowdapi.JobSpec{
+			Job:       "job",
+			BuildID:   "build id",
+			ProwJobID: "prow job id",
+			Type:      "periodic",
+			DecorationConfig: &prowapi.DecorationConfig{
+				Timeout:     &prowapi.Duration{Duration: time.Minute},
+				GracePeriod: &prowapi.Duration{Duration: time.Second},
+				UtilityImages: &prowapi.UtilityImages{
+					Sidecar:    "sidecar",
+					Entrypoint: "entrypoint",
+				},
+			},
+		},
+	}
+	jobSpec.SetNamespace("namespace")
+	step := newMultiStageTestStep(config.Tests[0], &config, nil, nil, &jobSpec, nil, "node-name", "")
+	for attempt, expected := range map[int]struct{ name, subDir string }{
+		1: {name: "test-step0", subDir: `"sub_dir":"artifacts/test/step0"`},
+		2: {name: "test-step0-attempt-2", subDir: `"sub_dir":"artifacts/test/step0/attempt-2"`},
+	} {
+		pods, _, err := step.generatePods(config.Tests[0].MultiStageTestConfigurationLiteral.Test, nil, nil, nil, &generatePodOptions{Attempt: attempt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pods) != 1 {
+			t.Fatalf("expected a pod, got %d", len(pods))
+		}
+		if pods[0].Name != expected.name {
+			t.Errorf("attempt %d: expected pod %s, got %s", attempt, expected.name, pods[0].Name)
+		}
+		if label := pods[0].Labels[base_steps.LabelMetadataStep]; label != "step0" {
+			t.Errorf("attempt %d: expected the step label to be step0, got %s", attempt, label)
+		}
+		var sidecarOptions string
+		for _, container := range pods[0].Spec.Containers {
+			for _, env := range container.Env {
+				if env.Name == "SIDECAR_OPTIONS" {
+					sidecarOptions = env.Value
+				}
+			}
+		}
+		if !strings.Contains(sidecarOptions, expected.subDir) {
+			t.Errorf("attempt %d: expected the artifacts in %s, sidecar options: %s", attempt, expected.subDir, sidecarOptions)
+		}
+	}
+}
+
+func TestGeneratePodsCache(t *testing.T) {
+	config := api.ReleaseBuildConfiguration{
+		Tests: []api.TestStepConfiguration{{
+			As: "test",
+			MultiStageTestConfigurationLiteral: &api.MultiStageTestConfigurationLiteral{
+				Test: []api.LiteralTestStep{{
+					As:       "step0",
+					From:     "src",
+					Commands: "command0",
+				}},
+				Cache: &api.StepCache{Size: "10Gi"},
+			},
+		}},
+	}
+	jobSpec := api.JobSpec{
+		JobSpec: prowdapi.JobSpec{
+			Job:       "job",
+			BuildID:   "build id",
+			ProwJobID: "prow job id",
+			Type:      "periodic",
+			DecorationConfig: &prowapi.DecorationConfig{
+				Timeout:     &prowapi.Duration{Duration: time.Minute},
+				GracePeriod: &prowapi.Duration{Duration: time.Second},
+				UtilityImages: &prowapi.UtilityImages{
+					Sidecar:    "sidecar",
+					Entrypoint: "entrypoint",
+				},
+			},
+		},
+	}
+	jobSpec.SetNamespace("namespace")
+	step := newMultiStageTestStep(config.Tests[0], &config, nil, nil, &jobSpec, nil, "node-name", "")
+	for _, tc := range []struct {
+		name     string
+		opts     *generatePodOptions
+		expected bool
+	}{{
+		name:     "steps mount the cache",
+		opts:     &generatePodOptions{},
+		expected: true,
+	}, {
+		name: "observers do not mount the cache",
+		opts: &generatePodOptions{IsObserver: true},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			pods, _, err := step.generatePods(config.Tests[0].MultiStageTestConfigurationLiteral.Test, nil, nil, nil, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pods) != 1 {
+				t.Fatalf("expected a pod, got %d", len(pods))
+			}
+			var volume, mount, env bool
+			for _, v := range pods[0].Spec.Volumes {
+				if v.PersistentVolumeClaim != nil && v.PersistentVolumeClaim.ClaimName == "test-cache" {
+					volume = true
+				}
+			}
+			for _, m := range pods[0].Spec.Containers[0].VolumeMounts {
+				if m.Name == "test-cache" && m.MountPath == CacheMountPath {
+					mount = true
+				}
+			}
+			for _, e := range pods[0].Spec.Containers[0].Env {
+				if e.Name == CacheMountEnv && e.Value == CacheMountPath {
+					env = true
+				}
+			}
+			if volume != tc.expected || mount != tc.expected || env != tc.expected {
+				t.Errorf("expected the cache to be mounted: %t, got volume: %t, mount: %t, env: %t", tc.expected, volume, mount, env)
+			}
+		})
+	}
+}
+
 func TestAddCredentials(t *testing.T) {
 	var testCases = []struct {
 		name        string
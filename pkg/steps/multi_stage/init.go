@@ -12,6 +12,7 @@ import (
 	coreapi "k8s.io/api/core/v1"
 	rbacapi "k8s.io/api/rbac/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -40,6 +41,47 @@ func (s *multiStageTestStep) createSharedDirSecret(ctx context.Context) error {
 	return s.client.Create(ctx, secret)
 }
 
+// createCache creates the claim of the volume shared between the steps. The
+// claim left behind by an earlier run of the test in the namespace is reused.
+func (s *multiStageTestStep) createCache(ctx context.Context) error {
+	if s.cache == nil {
+		return nil
+	}
+	size, err := resource.ParseQuantity(s.cache.Size)
+	if err != nil {
+		return fmt.Errorf("invalid cache size %q: %w", s.cache.Size, err)
+	}
+	name := cacheName(s.name)
+	logrus.Debugf("Creating multi-stage test cache %q", name)
+	claim := &coreapi.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: s.jobSpec.Namespace(),
+			Name:      name,
+			Labels:    map[string]string{MultiStageTestLabel: s.name},
+		},
+		Spec: coreapi.PersistentVolumeClaimSpec{
+			AccessModes: []coreapi.PersistentVolumeAccessMode{coreapi.ReadWriteOnce},
+			Resources: coreapi.ResourceRequirements{
+				Requests: coreapi.ResourceList{coreapi.ResourceStorage: size},
+			},
+		},
+	}
+	if s.cache.StorageClass != "" {
+		claim.Spec.StorageClassName = &s.cache.StorageClass
+	}
+	if owner := s.jobSpec.Owner(); owner != nil {
+		claim.OwnerReferences = append(claim.OwnerReferences, *owner)
+	}
+	if err := s.client.Create(ctx, claim); err != nil && !kerrors.IsAlreadyExists(err) {
+		return fmt.Errorf("cannot create cache %q: %w", name, err)
+	}
+	return nil
+}
+
+func cacheName(testName string) string {
+	return testName + "-cache"
+}
+
 func (s *multiStageTestStep) createCredentials(ctx context.Context) error {
 	logrus.Debugf("Creating multi-stage test credentials for %q", s.name)
 	toCreate := map[string]*coreapi.Secret{}
@@ -1,9 +1,19 @@
 package multi_stage
 
 import (
+	"context"
 	"testing"
 
+	coreapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/steps/loggingclient"
 	"github.com/openshift/ci-tools/pkg/testhelper"
+	testhelper_kube "github.com/openshift/ci-tools/pkg/testhelper/kubernetes"
 )
 
 func TestParseNamespaceUID(t *testing.T) {
@@ -37,3 +47,62 @@ func TestParseNamespaceUID(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateCache(t *testing.T) {
+	storageClass := "fast"
+	for _, tc := range []struct {
+		name     string
+		cache    *api.StepCache
+		existing []ctrlruntimeclient.Object
+		expected *coreapi.PersistentVolumeClaimSpec
+	}{{
+		name: "no cache",
+	}, {
+		name:  "cache is created",
+		cache: &api.StepCache{Size: "10Gi", StorageClass: storageClass},
+		expected: &coreapi.PersistentVolumeClaimSpec{
+			AccessModes: []coreapi.PersistentVolumeAccessMode{coreapi.ReadWriteOnce},
+			Resources: coreapi.ResourceRequirements{
+				Requests: coreapi.ResourceList{coreapi.ResourceStorage: resource.MustParse("10Gi")},
+			},
+			StorageClassName: &storageClass,
+		},
+	}, {
+		name:  "existing cache is reused",
+		cache: &api.StepCache{Size: "10Gi"},
+		existing: []ctrlruntimeclient.Object{&coreapi.PersistentVolumeClaim{
+			ObjectMeta: meta.ObjectMeta{Namespace: "namespace", Name: "test-cache"},
+			Spec: coreapi.PersistentVolumeClaimSpec{
+				Resources: coreapi.ResourceRequirements{
+					Requests: coreapi.ResourceList{coreapi.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			},
+		}},
+		expected: &coreapi.PersistentVolumeClaimSpec{
+			Resources: coreapi.ResourceRequirements{
+				Requests: coreapi.ResourceList{coreapi.ResourceStorage: resource.MustParse("1Gi")},
+			},
+		},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &testhelper_kube.FakePodClient{FakePodExecutor: &testhelper_kube.FakePodExecutor{
+				LoggingClient: loggingclient.New(fakectrlruntimeclient.NewClientBuilder().WithObjects(tc.existing...).Build()),
+			}}
+			jobSpec := api.JobSpec{}
+			jobSpec.SetNamespace("namespace")
+			s := &multiStageTestStep{name: "test", cache: tc.cache, jobSpec: &jobSpec, client: client}
+			if err := s.createCache(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			claims := coreapi.PersistentVolumeClaimList{}
+			if err := client.List(context.Background(), &claims); err != nil {
+				t.Fatal(err)
+			}
+			var spec *coreapi.PersistentVolumeClaimSpec
+			if len(claims.Items) != 0 {
+				spec = &claims.Items[0].Spec
+			}
+			testhelper.Diff(t, "claim", spec, tc.expected)
+		})
+	}
+}
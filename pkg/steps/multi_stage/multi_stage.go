@@ -6,6 +6,7 @@ import (
 	"path"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -73,6 +74,15 @@ const (
 	// CommandScriptMountPath is where we mount the command script
 	CommandScriptMountPath = "/var/run/configmaps/ci.openshift.io/multi-stage"
 	homeVolumeName         = "home"
+	// CacheMountPath is where we mount the cache volume
+	CacheMountPath = "/var/run/ci.openshift.io/cache"
+	// CacheMountEnv is the env we use to expose the cache volume
+	CacheMountEnv = "CACHE_DIR"
+	// ObserverTerminationMountPath is where we mount the directory holding the
+	// termination file of an observer
+	ObserverTerminationMountPath = "/var/run/ci.openshift.io/observer"
+	observerVolumeName           = "observer"
+	observerTerminationFile      = ObserverTerminationMountPath + "/termination.json"
 	// vpnConfPath is the path of the configuration file in the cluster profile.
 	vpnConfPath = "vpn.yaml"
 )
@@ -101,6 +111,7 @@ type multiStageTestStep struct {
 	flags           stepFlag
 	leases          []api.StepLease
 	clusterClaim    *api.ClusterClaim
+	cache           *api.StepCache
 	vpnConf         *vpnConf
 }
 
@@ -152,6 +163,7 @@ func newMultiStageTestStep(
 		flags:            flags,
 		leases:           leases,
 		clusterClaim:     testConfig.ClusterClaim,
+		cache:            ms.Cache,
 		subLock:          &sync.Mutex{},
 	}
 }
@@ -191,6 +203,9 @@ func (s *multiStageTestStep) run(ctx context.Context) error {
 	if err := s.createCredentials(ctx); err != nil {
 		return fmt.Errorf("failed to create credentials: %w", err)
 	}
+	if err := s.createCache(ctx); err != nil {
+		return fmt.Errorf("failed to create cache: %w", err)
+	}
 	if err := s.createCommandConfigMaps(ctx); err != nil {
 		return fmt.Errorf("failed to create command configmap: %w", err)
 	}
@@ -209,20 +224,32 @@ func (s *multiStageTestStep) run(ctx context.Context) error {
 	var errs []error
 	generateObserverOpt := defaultGeneratePodOptions()
 	generateObserverOpt.IsObserver = true
-	observers, err := s.generateObservers(s.observers, secretVolumes, secretVolumeMounts, generateObserverOpt)
+	testObservers := s.testObservers()
+	observers, err := s.generateObservers(testObservers, secretVolumes, secretVolumeMounts, generateObserverOpt)
 	if err != nil {
 		// if we can't even generate the Pods there's no reason to run the job
 		return err
 	}
 	observerContext, cancel := context.WithCancel(ctx)
 	observerDone := make(chan struct{})
-	go s.runObservers(observerContext, ctx, observers, observerDone)
+	// observers only read how the test ended once they are signalled to terminate, which is before the test ends
+	// when it is cancelled
+	termination := &atomic.Pointer[api.ObserverTermination]{}
+	phase := "pre"
+	termination.Store(&api.ObserverTermination{Phase: phase, Outcome: api.ObserverOutcomeCancelled})
+	go s.runObservers(observerContext, ctx, observers, termination, observerDone)
+	s.waitForObserversReadiness(ctx, testObservers, observers)
 	s.flags |= shortCircuit
 	if err := s.runSteps(ctx, "pre", s.pre, env, secretVolumes, secretVolumeMounts); err != nil {
 		errs = append(errs, fmt.Errorf("%q pre steps failed: %w", s.name, err))
-	} else if err := s.runSteps(ctx, "test", s.test, env, secretVolumes, secretVolumeMounts); err != nil {
-		errs = append(errs, fmt.Errorf("%q test steps failed: %w", s.name, err))
+	} else {
+		phase = "test"
+		termination.Store(&api.ObserverTermination{Phase: phase, Outcome: api.ObserverOutcomeCancelled})
+		if err := s.runSteps(ctx, "test", s.test, env, secretVolumes, secretVolumeMounts); err != nil {
+			errs = append(errs, fmt.Errorf("%q test steps failed: %w", s.name, err))
+		}
 	}
+	termination.Store(&api.ObserverTermination{Phase: phase, Outcome: observerOutcome(ctx, utilerrors.NewAggregate(errs))})
 	cancel() // signal to observers that we're tearing down
 	s.flags &= ^shortCircuit
 	if err := s.runSteps(context.Background(), "post", s.post, env, secretVolumes, secretVolumeMounts); err != nil {
@@ -364,6 +391,16 @@ func (s *multiStageTestStep) environment() ([]coreapi.EnvVar, error) {
 		ret = append(ret, coreapi.EnvVar{Name: l.Env, Value: val})
 	}
 
+	if s.clusterClaim != nil {
+		for _, e := range api.ClusterClaimEnv {
+			val, err := s.params.Get(e)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, coreapi.EnvVar{Name: e, Value: val})
+		}
+	}
+
 	if s.profile != "" {
 		for _, e := range envForProfile {
 			val, err := s.params.Get(e)
@@ -209,11 +209,12 @@ func (f fakeStepParams) Get(key string) (string, error) {
 func TestEnvironment(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
-		name      string
-		params    api.Parameters
-		leases    []api.StepLease
-		expected  []coreapi.EnvVar
-		expectErr bool
+		name         string
+		params       api.Parameters
+		leases       []api.StepLease
+		clusterClaim *api.ClusterClaim
+		expected     []coreapi.EnvVar
+		expectErr    bool
 	}{
 		{
 			name:     "leases are exposed in environment",
@@ -221,6 +222,20 @@ func TestEnvironment(t *testing.T) {
 			leases:   []api.StepLease{{Env: "LEASE_ONE"}, {Env: "LEASE_TWO"}},
 			expected: []coreapi.EnvVar{{Name: "LEASE_ONE", Value: "ONE"}, {Name: "LEASE_TWO", Value: "TWO"}},
 		},
+		{
+			name: "parameters of the cluster claim are exposed in environment",
+			params: fakeStepParams{
+				"CLUSTER_CLAIM_POOL":           "hosted-arm64",
+				"CLUSTER_CLAIM_POOL_NAMESPACE": "hypershift-cluster-pool",
+				"CLUSTER_CLAIM_POOL_LABELS":    "architecture=arm64,hypershift=hosted",
+			},
+			clusterClaim: &api.ClusterClaim{Selector: map[string]string{"hypershift": "hosted"}},
+			expected: []coreapi.EnvVar{
+				{Name: "CLUSTER_CLAIM_POOL", Value: "hosted-arm64"},
+				{Name: "CLUSTER_CLAIM_POOL_LABELS", Value: "architecture=arm64,hypershift=hosted"},
+				{Name: "CLUSTER_CLAIM_POOL_NAMESPACE", Value: "hypershift-cluster-pool"},
+			},
+		},
 		{
 			name: "arbitrary variables are not exposed in environment",
 			params: fakeStepParams{
@@ -233,8 +248,9 @@ func TestEnvironment(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			s := &multiStageTestStep{
-				params: tc.params,
-				leases: tc.leases,
+				params:       tc.params,
+				leases:       tc.leases,
+				clusterClaim: tc.clusterClaim,
 			}
 			got, err := s.environment()
 			if (err != nil) != tc.expectErr {
@@ -1,11 +1,15 @@
 package multi_stage
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -14,6 +18,9 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/test-infra/prow/entrypoint"
 	utilpointer "k8s.io/utils/pointer"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -23,6 +30,16 @@ import (
 	"github.com/openshift/ci-tools/pkg/util"
 )
 
+const (
+	// defaultObserverReadinessTimeout is how long we wait for an observer to be ready when it does not say
+	defaultObserverReadinessTimeout = 10 * time.Minute
+	// observerTerminationTimeout is how long writing the termination file of an observer may take
+	observerTerminationTimeout = 30 * time.Second
+)
+
+// observerReadinessPollInterval is how often we check whether the observers are ready
+var observerReadinessPollInterval = 5 * time.Second
+
 func (s *multiStageTestStep) runSteps(
 	ctx context.Context,
 	phase string,
@@ -38,13 +55,19 @@ func (s *multiStageTestStep) runSteps(
 		s.flags |= hasPrevErrs
 		return err
 	}
+	retries := s.stepRetries(steps, env, secretVolumes, secretVolumeMounts)
+	observers, err := s.generateStepObservers(phase, steps, secretVolumes, secretVolumeMounts)
+	if err != nil {
+		s.flags |= hasPrevErrs
+		return err
+	}
 	var errs []error
 	defer func() {
 		if len(errs) != 0 {
 			s.flags |= hasPrevErrs
 		}
 	}()
-	if err := s.runPods(ctx, pods, bestEffortSteps); err != nil {
+	if err := s.runPods(ctx, pods, bestEffortSteps, retries, observers); err != nil {
 		errs = append(errs, err)
 	}
 	select {
@@ -79,10 +102,101 @@ func (s *multiStageTestStep) runSteps(
 	return err
 }
 
-func (s *multiStageTestStep) runPods(ctx context.Context, pods []coreapi.Pod, bestEffortSteps sets.Set[string]) error {
+// stepRetry generates the pods of the later attempts of a step which is retried when it fails
+type stepRetry struct {
+	retries  *api.StepRetries
+	generate func(attempt int) (*coreapi.Pod, error)
+}
+
+// stepRetries returns the retries of the steps which have any, by the name of their pods
+func (s *multiStageTestStep) stepRetries(
+	steps []api.LiteralTestStep,
+	env []coreapi.EnvVar,
+	secretVolumes []coreapi.Volume,
+	secretVolumeMounts []coreapi.VolumeMount,
+) map[string]*stepRetry {
+	retries := map[string]*stepRetry{}
+	for _, step := range steps {
+		if step.Retries == nil {
+			continue
+		}
+		step := step
+		retries[fmt.Sprintf("%s-%s", s.name, step.As)] = &stepRetry{
+			retries: step.Retries,
+			generate: func(attempt int) (*coreapi.Pod, error) {
+				pods, _, err := s.generatePods([]api.LiteralTestStep{step}, env, secretVolumes, secretVolumeMounts, &generatePodOptions{Attempt: attempt})
+				if err != nil {
+					return nil, err
+				}
+				if len(pods) != 1 {
+					return nil, fmt.Errorf("expected a pod for step %s, got %d", step.As, len(pods))
+				}
+				return &pods[0], nil
+			},
+		}
+	}
+	return retries
+}
+
+// stepObservation holds the observers attached to a step
+type stepObservation struct {
+	phase, step string
+	observers   []api.Observer
+	pods        []coreapi.Pod
+}
+
+// testObservers returns the observers which observe the whole test
+func (s *multiStageTestStep) testObservers() []api.Observer {
+	var ret []api.Observer
+	for _, observer := range s.observers {
+		if observer.Scope != api.ObserverScopeStep {
+			ret = append(ret, observer)
+		}
+	}
+	return ret
+}
+
+// generateStepObservers generates the pods of the observers attached to the steps, by the name of the pods of the steps
+func (s *multiStageTestStep) generateStepObservers(
+	phase string,
+	steps []api.LiteralTestStep,
+	secretVolumes []coreapi.Volume,
+	secretVolumeMounts []coreapi.VolumeMount,
+) (map[string]*stepObservation, error) {
+	byName := map[string]api.Observer{}
+	for _, observer := range s.observers {
+		if observer.Scope == api.ObserverScopeStep {
+			byName[observer.Name] = observer
+		}
+	}
+	ret := map[string]*stepObservation{}
+	var errs []error
+	for _, step := range steps {
+		var observers []api.Observer
+		for _, name := range step.Observers {
+			if observer, ok := byName[name]; ok {
+				observers = append(observers, observer)
+			}
+		}
+		if len(observers) == 0 {
+			continue
+		}
+		pods, err := s.generateObservers(observers, secretVolumes, secretVolumeMounts, &generatePodOptions{IsObserver: true, ObservedStep: step.As})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ret[fmt.Sprintf("%s-%s", s.name, step.As)] = &stepObservation{phase: phase, step: step.As, observers: observers, pods: pods}
+	}
+	return ret, utilerrors.NewAggregate(errs)
+}
+
+func (s *multiStageTestStep) runPods(ctx context.Context, pods []coreapi.Pod, bestEffortSteps sets.Set[string], retries map[string]*stepRetry, observers map[string]*stepObservation) error {
 	var errs []error
 	for _, pod := range pods {
-		err := s.runPod(ctx, &pod, base_steps.NewTestCaseNotifier(util.NopNotifier), util.WaitForPodFlag(0))
+		err := s.observeStep(ctx, observers[pod.Name], func() error {
+			return s.runPodWithRetries(ctx, &pod, retries[pod.Name])
+		})
 		if err == nil {
 			continue
 		}
@@ -98,13 +212,157 @@ func (s *multiStageTestStep) runPods(ctx context.Context, pods []coreapi.Pod, be
 	return utilerrors.NewAggregate(errs)
 }
 
-func (s *multiStageTestStep) runObservers(ctx, textCtx context.Context, pods []coreapi.Pod, done chan<- struct{}) {
+// runPodWithRetries runs the pod of a step and, as long as the step failed with an outcome it is retried on, the pods of
+// its later attempts. The error of the last attempt is returned.
+func (s *multiStageTestStep) runPodWithRetries(ctx context.Context, pod *coreapi.Pod, retry *stepRetry) error {
+	err := s.runPod(ctx, pod, base_steps.NewTestCaseNotifier(util.NopNotifier), util.WaitForPodFlag(0))
+	if retry == nil {
+		return err
+	}
+	attempts := retry.retries.Count + 1
+	for attempt := 2; err != nil && attempt <= attempts && ctx.Err() == nil; attempt++ {
+		outcome := attemptOutcome(pod)
+		if !retry.retries.RetriesOn(outcome) {
+			break
+		}
+		logrus.Infof("Step %s failed with a %s, retrying it: attempt %d/%d.", pod.Name, outcome, attempt, attempts)
+		next, genErr := retry.generate(attempt)
+		if genErr != nil {
+			return utilerrors.NewAggregate([]error{err, fmt.Errorf("failed to generate the pod of attempt %d: %w", attempt, genErr)})
+		}
+		pod = next
+		err = s.runPod(ctx, pod, base_steps.NewTestCaseNotifier(util.NopNotifier), util.WaitForPodFlag(0))
+	}
+	return err
+}
+
+// attemptOutcome determines whether the failed attempt of a step timed out or failed. The entrypoint of the test
+// container exits with its internal error code when the command does not finish before its timeout.
+func attemptOutcome(pod *coreapi.Pod) api.StepRetryOutcome {
+	if pod.Status.Phase == coreapi.PodFailed && pod.Status.Reason == "DeadlineExceeded" {
+		return api.StepRetryOnTimeout
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == containerName && status.State.Terminated != nil && status.State.Terminated.ExitCode == entrypoint.InternalErrorCode {
+			return api.StepRetryOnTimeout
+		}
+	}
+	return api.StepRetryOnFailure
+}
+
+// observeStep runs a step while the observers attached to it run
+func (s *multiStageTestStep) observeStep(ctx context.Context, observation *stepObservation, run func() error) error {
+	if observation == nil {
+		return run()
+	}
+	observerContext, cancel := context.WithCancel(ctx)
+	observerDone := make(chan struct{})
+	termination := &atomic.Pointer[api.ObserverTermination]{}
+	termination.Store(&api.ObserverTermination{Phase: observation.phase, Step: observation.step, Outcome: api.ObserverOutcomeCancelled})
+	go s.runObservers(observerContext, ctx, observation.pods, termination, observerDone)
+	s.waitForObserversReadiness(ctx, observation.observers, observation.pods)
+	err := run()
+	termination.Store(&api.ObserverTermination{Phase: observation.phase, Step: observation.step, Outcome: observerOutcome(ctx, err)})
+	cancel()
+	<-observerDone
+	return err
+}
+
+// observerOutcome is how what the observers observed ended
+func observerOutcome(ctx context.Context, err error) api.ObserverOutcome {
+	switch {
+	case ctx.Err() != nil:
+		return api.ObserverOutcomeCancelled
+	case err != nil:
+		return api.ObserverOutcomeFailed
+	default:
+		return api.ObserverOutcomeSucceeded
+	}
+}
+
+// waitForObserversReadiness waits for the observers which have a readiness check to be ready. Observers never fail a
+// test, so one which is not ready in time is only warned about.
+func (s *multiStageTestStep) waitForObserversReadiness(ctx context.Context, observers []api.Observer, pods []coreapi.Pod) {
+	wg := sync.WaitGroup{}
+	for i, observer := range observers {
+		if observer.Readiness == nil {
+			continue
+		}
+		timeout := defaultObserverReadinessTimeout
+		if observer.Readiness.Timeout != nil {
+			timeout = observer.Readiness.Timeout.Duration
+		}
+		wg.Add(1)
+		go func(pod coreapi.Pod) {
+			defer wg.Done()
+			logrus.Infof("Waiting for observer pod %q to be ready...", pod.Name)
+			if err := wait.PollUntilContextTimeout(ctx, observerReadinessPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+				return s.observerReady(ctx, &pod)
+			}); err != nil {
+				logrus.WithError(err).Warnf("Observer pod %q did not become ready, continuing without it.", pod.Name)
+			}
+		}(pods[i])
+	}
+	wg.Wait()
+}
+
+// observerReady determines whether the test container of an observer is ready. An observer which already ended will
+// never be, so there is nothing to wait for.
+func (s *multiStageTestStep) observerReady(ctx context.Context, pod *coreapi.Pod) (bool, error) {
+	current := &coreapi.Pod{}
+	if err := s.client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(pod), current); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if current.Status.Phase == coreapi.PodSucceeded || current.Status.Phase == coreapi.PodFailed {
+		return true, nil
+	}
+	for _, status := range current.Status.ContainerStatuses {
+		if status.Name == containerName {
+			return status.Ready, nil
+		}
+	}
+	return false, nil
+}
+
+// writeObserverTermination tells an observer how what it observed ended before it is signalled to terminate
+func (s *multiStageTestStep) writeObserverTermination(pod *coreapi.Pod, termination api.ObserverTermination) error {
+	raw, err := json.Marshal(termination)
+	if err != nil {
+		return err
+	}
+	e, err := s.client.Exec(pod.Namespace, pod.Name, &coreapi.PodExecOptions{
+		Container: containerName,
+		Stdin:     true,
+		Stderr:    true,
+		Command:   []string{"/bin/sh", "-c", fmt.Sprintf("cat > %s", observerTerminationFile)},
+	})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), observerTerminationTimeout)
+	defer cancel()
+	if err := e.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  bytes.NewReader(raw),
+		Stderr: os.Stderr,
+	}); err != nil {
+		return fmt.Errorf("could not run remote command: %w", err)
+	}
+	return nil
+}
+
+func (s *multiStageTestStep) runObservers(ctx, textCtx context.Context, pods []coreapi.Pod, termination *atomic.Pointer[api.ObserverTermination], done chan<- struct{}) {
 	wg := sync.WaitGroup{}
 	wg.Add(len(pods))
 	errs := make(chan error, len(pods))
 	for _, pod := range pods {
 		go func(p coreapi.Pod) {
 			<-ctx.Done()
+			if err := s.writeObserverTermination(&p, *termination.Load()); err != nil {
+				logrus.WithError(err).Debugf("failed to tell observer %q how the test ended", p.Name)
+			}
 			logrus.Infof("Signalling observer pod %q to terminate...", p.Name)
 			if err := s.client.Delete(context.Background(), &p); err != nil {
 				logrus.WithError(err).Warn("failed to trigger observer to stop")
@@ -141,7 +399,8 @@ func (s *multiStageTestStep) runPod(ctx context.Context, pod *coreapi.Pod, notif
 	}
 	newPod, err := util.WaitForPodCompletion(ctx, client, pod.Namespace, pod.Name, notifier, flags)
 	if newPod != nil {
-		pod = newPod
+		// the callers look at how the pod ended
+		*pod = *newPod
 	}
 	finished := time.Now()
 	duration := finished.Sub(start)
@@ -164,7 +423,7 @@ func (s *multiStageTestStep) runPod(ctx context.Context, pod *coreapi.Pod, notif
 	s.subLock.Unlock()
 	if err != nil {
 		linksText := strings.Builder{}
-		linksText.WriteString(fmt.Sprintf("Link to step on registry info site: https://steps.ci.openshift.org/reference/%s", strings.TrimPrefix(pod.Name, s.name+"-")))
+		linksText.WriteString(fmt.Sprintf("Link to step on registry info site: https://steps.ci.openshift.org/reference/%s", pod.Labels[base_steps.LabelMetadataStep]))
 		linksText.WriteString(fmt.Sprintf("\nLink to job on registry info site: https://steps.ci.openshift.org/job?org=%s&repo=%s&branch=%s&test=%s", s.config.Metadata.Org, s.config.Metadata.Repo, s.config.Metadata.Branch, s.name))
 		if s.config.Metadata.Variant != "" {
 			linksText.WriteString(fmt.Sprintf("&variant=%s", s.config.Metadata.Variant))
@@ -2,6 +2,7 @@ package multi_stage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
@@ -28,10 +29,16 @@ import (
 
 func TestRun(t *testing.T) {
 	yes := true
+	interval := observerReadinessPollInterval
+	observerReadinessPollInterval = 10 * time.Millisecond
+	defer func() { observerReadinessPollInterval = interval }()
 	for _, tc := range []struct {
 		name      string
 		observers []api.Observer
+		test      []api.LiteralTestStep
 		failures  sets.Set[string]
+		// the failures are retried until the steps succeed
+		retriedAway bool
 		// Remove these names from the expected ones. So far the sole use case is for the observers
 		// as they run asynchrounously so the ordering is not stable
 		removeNames sets.Set[string]
@@ -121,6 +128,51 @@ func TestRun(t *testing.T) {
 				"test-post0",
 			},
 		},
+		{
+			name:        "failure in a retried test step, retry succeeds",
+			test:        []api.LiteralTestStep{{As: "test0", Retries: &api.StepRetries{Count: 2}}, {As: "test1"}},
+			failures:    sets.New[string]("test-test0"),
+			retriedAway: true,
+			expected: []string{
+				"test-pre0", "test-pre1",
+				"test-test0", "test-test0-attempt-2", "test-test1",
+				"test-post0",
+			},
+		},
+		{
+			name:     "failure in a retried test step, all the attempts fail",
+			test:     []api.LiteralTestStep{{As: "test0", Retries: &api.StepRetries{Count: 2}}, {As: "test1"}},
+			failures: sets.New[string]("test-test0", "test-test0-attempt-2", "test-test0-attempt-3"),
+			expected: []string{
+				"test-pre0", "test-pre1",
+				"test-test0", "test-test0-attempt-2", "test-test0-attempt-3",
+				"test-post0", "test-post1",
+			},
+		},
+		{
+			name:     "failure in a test step retried only on timeouts, no retry",
+			test:     []api.LiteralTestStep{{As: "test0", Retries: &api.StepRetries{Count: 2, On: []api.StepRetryOutcome{api.StepRetryOnTimeout}}}, {As: "test1"}},
+			failures: sets.New[string]("test-test0"),
+			expected: []string{
+				"test-pre0", "test-pre1",
+				"test-test0",
+				"test-post0", "test-post1",
+			},
+		},
+		{
+			name: "observer attached to a step runs while the step runs",
+			observers: []api.Observer{{
+				Name:      "obsrv0",
+				Scope:     api.ObserverScopeStep,
+				Readiness: &api.ObserverReadiness{Commands: "true"},
+			}},
+			test: []api.LiteralTestStep{{As: "test0", Observers: []string{"obsrv0"}}, {As: "test1"}},
+			expected: []string{
+				"test-pre0", "test-pre1",
+				"test-obsrv0-test0", "test-test0", "test-test1",
+				"test-post0",
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			sa := &v1.ServiceAccount{
@@ -166,18 +218,23 @@ func TestRun(t *testing.T) {
 				PendingTimeout:  30 * time.Minute,
 				FakePodExecutor: crclient,
 			}
+			test := tc.test
+			if test == nil {
+				test = []api.LiteralTestStep{{As: "test0"}, {As: "test1"}}
+			}
 			step := MultiStageTestStep(api.TestStepConfiguration{
 				As: name,
 				MultiStageTestConfigurationLiteral: &api.MultiStageTestConfigurationLiteral{
 					Pre:                []api.LiteralTestStep{{As: "pre0"}, {As: "pre1"}},
-					Test:               []api.LiteralTestStep{{As: "test0"}, {As: "test1"}},
+					Test:               test,
 					Post:               []api.LiteralTestStep{{As: "post0"}, {As: "post1", OptionalOnSuccess: &yes}},
 					Observers:          tc.observers,
 					AllowSkipOnSuccess: &yes,
 				},
 			}, &api.ReleaseBuildConfiguration{}, nil, client, &jobSpec, nil, "node-name", "")
-			if err := step.Run(context.Background()); (err != nil) != (tc.failures != nil) {
-				t.Errorf("expected error: %t, got error: %v", (tc.failures != nil), err)
+			expectedErr := tc.failures != nil && !tc.retriedAway
+			if err := step.Run(context.Background()); (err != nil) != expectedErr {
+				t.Errorf("expected error: %t, got error: %v", expectedErr, err)
 			}
 			secrets := &v1.SecretList{}
 			if err := crclient.List(context.TODO(), secrets, ctrlruntimeclient.InNamespace(jobSpec.Namespace())); err != nil {
@@ -210,6 +267,45 @@ func TestRun(t *testing.T) {
 	}
 }
 
+func TestAttemptOutcome(t *testing.T) {
+	terminated := func(exitCode int32) v1.PodStatus {
+		return v1.PodStatus{
+			Phase: v1.PodFailed,
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "test", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: exitCode}}},
+				{Name: "sidecar", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0}}},
+			},
+		}
+	}
+	for _, tc := range []struct {
+		name     string
+		status   v1.PodStatus
+		expected api.StepRetryOutcome
+	}{
+		{
+			name:     "command fails",
+			status:   terminated(1),
+			expected: api.StepRetryOnFailure,
+		},
+		{
+			name:     "entrypoint times the command out",
+			status:   terminated(127),
+			expected: api.StepRetryOnTimeout,
+		},
+		{
+			name:     "pod exceeds its deadline",
+			status:   v1.PodStatus{Phase: v1.PodFailed, Reason: "DeadlineExceeded"},
+			expected: api.StepRetryOnTimeout,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := attemptOutcome(&v1.Pod{Status: tc.status}); actual != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
 func TestJUnit(t *testing.T) {
 	for _, tc := range []struct {
 		name     string
@@ -326,3 +422,38 @@ func fakePodNameIndexer(object ctrlruntimeclient.Object) []string {
 	}
 	return []string{p.Name}
 }
+
+func TestObserverOutcome(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, tc := range []struct {
+		name     string
+		ctx      context.Context
+		err      error
+		expected api.ObserverOutcome
+	}{
+		{
+			name:     "succeeded",
+			ctx:      context.Background(),
+			expected: api.ObserverOutcomeSucceeded,
+		},
+		{
+			name:     "failed",
+			ctx:      context.Background(),
+			err:      errors.New("failed"),
+			expected: api.ObserverOutcomeFailed,
+		},
+		{
+			name:     "cancelled",
+			ctx:      cancelled,
+			err:      errors.New("cancelled"),
+			expected: api.ObserverOutcomeCancelled,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := observerOutcome(tc.ctx, tc.err); actual != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, actual)
+			}
+		})
+	}
+}
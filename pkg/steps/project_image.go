@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"path"
+	"strings"
 
 	coreapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	buildapi "github.com/openshift/api/build/v1"
@@ -64,7 +66,31 @@ func (s *projectDirectoryImageBuildStep) run(ctx context.Context) error {
 		s.config.BuildArgs,
 		s.config.Ref,
 	)
-	return handleBuilds(ctx, s.client, s.podClient, *build)
+	architectures, err := buildArchitectures(s.config.Architectures, s.client.NodeArchitectures())
+	if err != nil {
+		return err
+	}
+	return handleBuildsForArchitectures(ctx, s.client, s.podClient, *build, architectures)
+}
+
+// buildArchitectures determines the architectures the image is built for: the
+// ones it declares or, when it declares none, the ones of the nodes.
+func buildArchitectures(architectures []api.ReleaseArchitecture, nodeArchitectures []string) ([]string, error) {
+	if len(architectures) == 0 {
+		return nodeArchitectures, nil
+	}
+	available := sets.New[string](nodeArchitectures...)
+	var ret, missing []string
+	for _, arch := range architectures {
+		if !available.Has(string(arch)) {
+			missing = append(missing, string(arch))
+		}
+		ret = append(ret, string(arch))
+	}
+	if len(missing) != 0 {
+		return nil, fmt.Errorf("the build cluster has no nodes for architectures %s, available: %s", strings.Join(missing, ", "), strings.Join(nodeArchitectures, ", "))
+	}
+	return ret, nil
 }
 
 type workingDir func(tag string) (string, error)
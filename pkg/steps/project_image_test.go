@@ -11,6 +11,7 @@ import (
 	buildapi "github.com/openshift/api/build/v1"
 
 	"github.com/openshift/ci-tools/pkg/api"
+	"github.com/openshift/ci-tools/pkg/testhelper"
 )
 
 func TestImagesFor(t *testing.T) {
@@ -266,3 +267,37 @@ func TestImagesFor(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildArchitectures(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		architectures     []api.ReleaseArchitecture
+		nodeArchitectures []string
+		expected          []string
+		expectedErr       error
+	}{{
+		name:              "no architectures, the ones of the nodes",
+		nodeArchitectures: []string{"amd64", "arm64"},
+		expected:          []string{"amd64", "arm64"},
+	}, {
+		name:              "declared architectures",
+		architectures:     []api.ReleaseArchitecture{api.ReleaseArchitectureARM64},
+		nodeArchitectures: []string{"amd64", "arm64"},
+		expected:          []string{"arm64"},
+	}, {
+		name:              "architectures without nodes",
+		architectures:     []api.ReleaseArchitecture{api.ReleaseArchitectureAMD64, api.ReleaseArchitectureARM64, api.ReleaseArchitectureS390x},
+		nodeArchitectures: []string{"amd64"},
+		expectedErr:       errors.New("the build cluster has no nodes for architectures arm64, s390x, available: amd64"),
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			architectures, err := buildArchitectures(tc.architectures, tc.nodeArchitectures)
+			if diff := cmp.Diff(tc.expectedErr, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("unexpected error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expected, architectures); diff != "" {
+				t.Errorf("unexpected architectures: %s", diff)
+			}
+		})
+	}
+}
@@ -441,9 +441,15 @@ func isBuildPhaseTerminated(phase buildapi.BuildPhase) bool {
 }
 
 func handleBuilds(ctx context.Context, buildClient BuildClient, podClient kubernetes.PodClient, build buildapi.Build) error {
+	return handleBuildsForArchitectures(ctx, buildClient, podClient, build, buildClient.NodeArchitectures())
+}
+
+// handleBuildsForArchitectures runs the build on the nodes of each of the architectures
+// and pushes the images as a manifest list.
+func handleBuildsForArchitectures(ctx context.Context, buildClient BuildClient, podClient kubernetes.PodClient, build buildapi.Build, architectures []string) error {
 	var wg sync.WaitGroup
 
-	builds := constructMultiArchBuilds(build, buildClient.NodeArchitectures())
+	builds := constructMultiArchBuilds(build, architectures)
 	errChan := make(chan error, len(builds))
 
 	wg.Add(len(builds))
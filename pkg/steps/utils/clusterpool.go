@@ -17,16 +17,7 @@ import (
 
 func ClusterPoolFromClaim(ctx context.Context, claim *api.ClusterClaim, hiveClient ctrlruntimeclient.Reader) (*hivev1.ClusterPool, error) {
 	clusterPools := &hivev1.ClusterPoolList{}
-	listOption := ctrlruntimeclient.MatchingLabels{
-		"product":      string(claim.Product),
-		"version":      claim.Version,
-		"architecture": string(claim.Architecture),
-		"cloud":        string(claim.Cloud),
-		"owner":        claim.Owner,
-	}
-	for k, v := range claim.Labels {
-		listOption[k] = v
-	}
+	listOption := ctrlruntimeclient.MatchingLabels(claim.PoolSelector())
 	if err := hiveClient.List(ctx, clusterPools, listOption); err != nil {
 		return nil, fmt.Errorf("failed to list cluster pools with list option %v: %w", listOption, err)
 	}
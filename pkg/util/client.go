@@ -40,3 +40,18 @@ func LoadKubeConfig(path string) (*rest.Config, error) {
 	}
 	return clusterConfig, nil
 }
+
+// LoadCurrentContext loads the current context of the kubeconfig of the user, from $KUBECONFIG
+// or ~/.kube/config, and returns its namespace along with the client configuration
+func LoadCurrentContext() (*rest.Config, string, error) {
+	config := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
+	clusterConfig, err := config.ClientConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("could not load client configuration: %w", err)
+	}
+	namespace, _, err := config.Namespace()
+	if err != nil {
+		return nil, "", fmt.Errorf("could not load the namespace of the current context: %w", err)
+	}
+	return clusterConfig, namespace, nil
+}
@@ -10,6 +10,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 
+	"github.com/openshift/library-go/pkg/image/reference"
+
 	"github.com/openshift/ci-tools/pkg/api"
 )
 
@@ -170,6 +172,7 @@ func (v *Validator) validateConfiguration(ctx *configContext, config *api.Releas
 	}
 	validationErrors = append(validationErrors, ValidateBaseImages(ctx.AddField("base_images"), config.InputConfiguration.BaseImages)...)
 	validationErrors = append(validationErrors, validateBaseRPMImages(ctx.AddField("base_rpm_images"), config.InputConfiguration.BaseRPMImages)...)
+	validationErrors = append(validationErrors, validateExternalImages(ctx.AddField("external_images"), config.InputConfiguration.ExternalImages)...)
 	// Validate tag_specification
 	if config.InputConfiguration.ReleaseTagConfiguration != nil {
 		validationErrors = append(validationErrors, validateReleaseTagConfiguration("tag_specification", *config.InputConfiguration.ReleaseTagConfiguration)...)
@@ -279,6 +282,16 @@ func ValidateImages(ctx *configContext, images []api.ProjectDirectoryImageBuildS
 		if image.DockerfileLiteral != nil && (image.ContextDir != "" || image.DockerfilePath != "") {
 			validationErrors = append(validationErrors, ctxN.errorf("dockerfile_literal is mutually exclusive with context_dir and dockerfile_path"))
 		}
+		seen := sets.New[api.ReleaseArchitecture]()
+		for i, arch := range image.Architectures {
+			if !api.ImageArchitectures.Has(arch) {
+				validationErrors = append(validationErrors, ctxN.AddField("architectures").addIndex(i).errorf("invalid architecture %q, must be one of %v", arch, sets.List(api.ImageArchitectures)))
+			}
+			if seen.Has(arch) {
+				validationErrors = append(validationErrors, ctxN.AddField("architectures").addIndex(i).errorf("duplicate architecture %q", arch))
+			}
+			seen.Insert(arch)
+		}
 	}
 	return validationErrors
 }
@@ -384,6 +397,22 @@ func validateBaseRPMImages(ctx *configContext, images map[string]api.ImageStream
 	return ret
 }
 
+func validateExternalImages(ctx *configContext, images map[string]api.ExternalImage) []error {
+	var ret []error
+	for name, image := range images {
+		ctxN := ctx.addKey(name)
+		if err := ctxN.addPipelineImage(api.PipelineImageStreamTagReference(name), ""); err != nil {
+			ret = append(ret, err)
+		}
+		if image.PullSpec == "" {
+			ret = append(ret, ctxN.AddField("pull_spec").errorf("value required but not provided"))
+		} else if _, err := reference.Parse(image.PullSpec); err != nil {
+			ret = append(ret, ctxN.AddField("pull_spec").errorf("invalid pull spec %q: %v", image.PullSpec, err))
+		}
+	}
+	return ret
+}
+
 func validateImageStreamTagReference(fieldRoot string, input api.ImageStreamTagReference) []error {
 	var validationErrors []error
 
@@ -609,6 +638,14 @@ func Observer(observer api.Observer) []error {
 		errs = append(errs, fmt.Errorf("%s.commands cannot be empty", fieldRoot))
 	}
 	errs = append(errs, validateResourceRequirements(fieldRoot+".resources", observer.Resources)...)
+	if observer.Readiness != nil && observer.Readiness.Commands == "" {
+		errs = append(errs, fmt.Errorf("%s.readiness.commands cannot be empty", fieldRoot))
+	}
+	switch observer.Scope {
+	case "", api.ObserverScopeTest, api.ObserverScopeStep:
+	default:
+		errs = append(errs, fmt.Errorf("%s.scope must be one of %q or %q, not %q", fieldRoot, api.ObserverScopeTest, api.ObserverScopeStep, observer.Scope))
+	}
 	// we're validating unresolved configuration outside of a full test config, so
 	// we cannot know the releases that may or may not be contained in a config using
 	// this observer in the future. This technically disallows users from using `from:`
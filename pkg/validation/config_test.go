@@ -406,6 +406,30 @@ func TestValidateReleaseTagConfiguration(t *testing.T) {
 	}
 }
 
+func TestValidateExternalImages(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		images   map[string]api.ExternalImage
+		expected []error
+	}{{
+		name:   "valid",
+		images: map[string]api.ExternalImage{"tool": {PullSpec: "quay.io/org/tool:latest"}},
+	}, {
+		name:     "missing pull spec",
+		images:   map[string]api.ExternalImage{"tool": {}},
+		expected: []error{errors.New("external_images[tool].pull_spec: value required but not provided")},
+	}, {
+		name:     "invalid pull spec",
+		images:   map[string]api.ExternalImage{"tool": {PullSpec: "quay.io/Org/tool"}},
+		expected: []error{errors.New(`external_images[tool].pull_spec: invalid pull spec "quay.io/Org/tool": repository name must be lowercase`)},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validateExternalImages(NewConfigContext().AddField("external_images"), tc.images)
+			testhelper.Diff(t, "errors", errs, tc.expected, testhelper.EquateErrorMessage)
+		})
+	}
+}
+
 func TestValidateImages(t *testing.T) {
 	var testCases = []struct {
 		name   string
@@ -454,6 +478,24 @@ func TestValidateImages(t *testing.T) {
 				errors.New("images[0]: dockerfile_literal is mutually exclusive with context_dir and dockerfile_path"),
 			},
 		},
+		{
+			name: "valid architectures",
+			input: []api.ProjectDirectoryImageBuildStepConfiguration{{
+				To:            "amsterdam",
+				Architectures: []api.ReleaseArchitecture{api.ReleaseArchitectureAMD64, api.ReleaseArchitectureARM64},
+			}},
+		},
+		{
+			name: "invalid and duplicate architectures",
+			input: []api.ProjectDirectoryImageBuildStepConfiguration{{
+				To:            "amsterdam",
+				Architectures: []api.ReleaseArchitecture{api.ReleaseArchitectureMULTI, api.ReleaseArchitectureARM64, api.ReleaseArchitectureARM64},
+			}},
+			output: []error{
+				errors.New("images[0].architectures[0]: invalid architecture \"multi\", must be one of [amd64 arm64 ppc64le s390x]"),
+				errors.New("images[0].architectures[2]: duplicate architecture \"arm64\""),
+			},
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -751,6 +793,19 @@ func TestReleaseBuildConfiguration_validateTestStepDependencies(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "dependencies on external images",
+			config: api.ReleaseBuildConfiguration{
+				InputConfiguration: api.InputConfiguration{
+					ExternalImages: map[string]api.ExternalImage{"tool": {PullSpec: "quay.io/org/tool:latest"}},
+				},
+				Tests: []api.TestStepConfiguration{
+					{MultiStageTestConfigurationLiteral: &api.MultiStageTestConfigurationLiteral{
+						Test: []api.LiteralTestStep{{Dependencies: []api.StepDependency{{Name: "tool"}, {Name: "pipeline:tool"}}}},
+					}},
+				},
+			},
+		},
 		{
 			name: "overridden dependencies",
 			config: api.ReleaseBuildConfiguration{
@@ -1260,3 +1315,60 @@ func TestValidateReleaseBuildConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestObserver(t *testing.T) {
+	resources := api.ResourceRequirements{Requests: api.ResourceList{"cpu": "100m"}}
+	for _, tc := range []struct {
+		name     string
+		observer api.Observer
+		expected []string
+	}{
+		{
+			name:     "valid observer",
+			observer: api.Observer{Name: "observer", From: "src", Commands: "observe", Resources: resources},
+		},
+		{
+			name: "valid observer of steps with readiness",
+			observer: api.Observer{
+				Name:      "observer",
+				From:      "src",
+				Commands:  "observe",
+				Resources: resources,
+				Readiness: &api.ObserverReadiness{Commands: "test -f /tmp/ready"},
+				Scope:     api.ObserverScopeStep,
+			},
+		},
+		{
+			name: "readiness without commands",
+			observer: api.Observer{
+				Name:      "observer",
+				From:      "src",
+				Commands:  "observe",
+				Resources: resources,
+				Readiness: &api.ObserverReadiness{},
+			},
+			expected: []string{`observer "observer": .readiness.commands cannot be empty`},
+		},
+		{
+			name: "invalid scope",
+			observer: api.Observer{
+				Name:      "observer",
+				From:      "src",
+				Commands:  "observe",
+				Resources: resources,
+				Scope:     "chain",
+			},
+			expected: []string{`observer "observer": .scope must be one of "test" or "step", not "chain"`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual []string
+			for _, err := range Observer(tc.observer) {
+				actual = append(actual, err.Error())
+			}
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("unexpected errors: %s", diff)
+			}
+		})
+	}
+}
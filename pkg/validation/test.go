@@ -485,19 +485,25 @@ func (v *Validator) validateTestConfigurationType(
 	clusterCount := 0
 	if claim := test.ClusterClaim; claim != nil {
 		clusterCount++
-		for key := range claim.Labels {
-			if key == "product" || key == "version" || key == "architecture" || key == "cloud" || key == "owner" {
-				validationErrors = append(validationErrors, fmt.Errorf("%s.cluster_claim.labels contains an invalid key in claim's label: %s", fieldRoot, key))
+		if len(claim.Selector) != 0 {
+			if claim.Product != "" || claim.Version != "" || claim.Architecture != "" || claim.Cloud != "" || claim.Owner != "" || len(claim.Labels) != 0 {
+				validationErrors = append(validationErrors, fmt.Errorf("%s.cluster_claim.selector cannot be set together with product, version, architecture, cloud, owner or labels", fieldRoot))
+			}
+		} else {
+			for key := range claim.Labels {
+				if key == "product" || key == "version" || key == "architecture" || key == "cloud" || key == "owner" {
+					validationErrors = append(validationErrors, fmt.Errorf("%s.cluster_claim.labels contains an invalid key in claim's label: %s", fieldRoot, key))
+				}
+			}
+			if claim.Version == "" {
+				validationErrors = append(validationErrors, fmt.Errorf("%s.cluster_claim.version cannot be empty when cluster_claim is not nil", fieldRoot))
+			}
+			if claim.Cloud == "" {
+				validationErrors = append(validationErrors, fmt.Errorf("%s.cluster_claim.cloud cannot be empty when cluster_claim is not nil", fieldRoot))
+			}
+			if claim.Owner == "" {
+				validationErrors = append(validationErrors, fmt.Errorf("%s.cluster_claim.owner cannot be empty when cluster_claim is not nil", fieldRoot))
 			}
-		}
-		if claim.Version == "" {
-			validationErrors = append(validationErrors, fmt.Errorf("%s.cluster_claim.version cannot be empty when cluster_claim is not nil", fieldRoot))
-		}
-		if claim.Cloud == "" {
-			validationErrors = append(validationErrors, fmt.Errorf("%s.cluster_claim.cloud cannot be empty when cluster_claim is not nil", fieldRoot))
-		}
-		if claim.Owner == "" {
-			validationErrors = append(validationErrors, fmt.Errorf("%s.cluster_claim.owner cannot be empty when cluster_claim is not nil", fieldRoot))
 		}
 		if test.MultiStageTestConfigurationLiteral == nil && test.MultiStageTestConfiguration == nil {
 			validationErrors = append(validationErrors, fmt.Errorf("%s.cluster_claim cannot be set on a test which is not a multi-stage test", fieldRoot))
@@ -565,6 +571,7 @@ func (v *Validator) validateTestConfigurationType(
 		}
 		context := newContext(fieldPath(fieldRoot), testConfig.Environment, releases, inputImagesSeen)
 		validationErrors = append(validationErrors, validateLeases(context.addField("leases"), testConfig.Leases)...)
+		validationErrors = append(validationErrors, validateStepCache(context.addField("cache"), testConfig.Cache)...)
 		validationErrors = append(validationErrors, v.validateTestSteps(context.addField("pre"), testStagePre, testConfig.Pre, claimRelease)...)
 		validationErrors = append(validationErrors, v.validateTestSteps(context.addField("test"), testStageTest, testConfig.Test, claimRelease)...)
 		validationErrors = append(validationErrors, v.validateTestSteps(context.addField("post"), testStagePost, testConfig.Post, claimRelease)...)
@@ -577,6 +584,7 @@ func (v *Validator) validateTestConfigurationType(
 			validationErrors = append(validationErrors, v.validateClusterProfile(fieldRoot, testConfig.ClusterProfile, metadata)...)
 		}
 		validationErrors = append(validationErrors, validateLeases(context.addField("leases"), testConfig.Leases)...)
+		validationErrors = append(validationErrors, validateStepCache(context.addField("cache"), testConfig.Cache)...)
 		for i, s := range testConfig.Pre {
 			validationErrors = append(validationErrors, v.validateLiteralTestStep(context.addField("pre").addIndex(i), testStagePre, s, claimRelease)...)
 		}
@@ -680,6 +688,7 @@ func (v *Validator) validateLiteralTestStep(context *context, stage testStage, s
 	}
 	ret = append(ret, validateDependencies(string(context.field), step.Dependencies)...)
 	ret = append(ret, validateLeases(context.addField("leases"), step.Leases)...)
+	ret = append(ret, validateStepRetries(context.addField("retries"), step.Retries)...)
 	switch stage {
 	case testStagePre, testStageTest:
 		if step.OptionalOnSuccess != nil {
@@ -689,6 +698,27 @@ func (v *Validator) validateLiteralTestStep(context *context, stage testStage, s
 	return ret
 }
 
+// maxStepRetries bounds the retries of a step, so that a step which is broken
+// rather than flaky does not hold the test for hours.
+const maxStepRetries = 3
+
+func validateStepRetries(context *context, retries *api.StepRetries) (ret []error) {
+	if retries == nil {
+		return nil
+	}
+	if retries.Count < 1 || retries.Count > maxStepRetries {
+		ret = append(ret, context.addField("count").errorf("must be between 1 and %d", maxStepRetries))
+	}
+	for i, on := range retries.On {
+		switch on {
+		case api.StepRetryOnFailure, api.StepRetryOnTimeout:
+		default:
+			ret = append(ret, context.addField("on").addIndex(i).errorf("must be %q or %q, not %q", api.StepRetryOnFailure, api.StepRetryOnTimeout, on))
+		}
+	}
+	return ret
+}
+
 func validateFromAndFromImage(
 	context *context,
 	from string,
@@ -853,6 +883,21 @@ func validateDNSConfig(fieldRoot string, dnsConfig []api.StepDNSConfig) (ret []e
 	return errs
 }
 
+func validateStepCache(context *context, cache *api.StepCache) (ret []error) {
+	if cache == nil {
+		return nil
+	}
+	if cache.Size == "" {
+		return []error{context.errorf("'size' cannot be empty")}
+	}
+	if size, err := resource.ParseQuantity(cache.Size); err != nil {
+		ret = append(ret, context.errorf("'size' must be a Kubernetes quantity: %w", err))
+	} else if size.Sign() <= 0 {
+		ret = append(ret, context.errorf("'size' must be positive"))
+	}
+	return
+}
+
 func validateLeases(context *context, leases []api.StepLease) (ret []error) {
 	for i, l := range leases {
 		if l.ResourceType == "" {
@@ -1062,6 +1062,32 @@ func TestValidateTestSteps(t *testing.T) {
 				Resources: resources},
 		}},
 		clusterClaim: api.ClaimRelease{ReleaseName: "myclaim-as", OverrideName: "myclaim"},
+	}, {
+		name: "retries",
+		steps: []api.TestStep{{
+			LiteralTestStep: &api.LiteralTestStep{
+				As:        "as",
+				From:      "from",
+				Commands:  "commands",
+				Resources: resources,
+				Retries:   &api.StepRetries{Count: 2, On: []api.StepRetryOutcome{api.StepRetryOnTimeout}},
+			},
+		}},
+	}, {
+		name: "invalid retries",
+		steps: []api.TestStep{{
+			LiteralTestStep: &api.LiteralTestStep{
+				As:        "as",
+				From:      "from",
+				Commands:  "commands",
+				Resources: resources,
+				Retries:   &api.StepRetries{Count: 4, On: []api.StepRetryOutcome{api.StepRetryOnFailure, "error"}},
+			},
+		}},
+		errs: []error{
+			errors.New("test[0].retries.count: must be between 1 and 3"),
+			errors.New(`test[0].retries.on[1]: must be "failure" or "timeout", not "error"`),
+		},
 	}} {
 		t.Run(tc.name, func(t *testing.T) {
 			context := newContext("test", nil, tc.releases, make(testInputImages))
@@ -1413,6 +1439,46 @@ func TestValidateLeases(t *testing.T) {
 	}
 }
 
+func TestValidateStepCache(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		cache *api.StepCache
+		err   []error
+	}{{
+		name:  "valid cache",
+		cache: &api.StepCache{Size: "10Gi", StorageClass: "gp3-csi"},
+	}, {
+		name:  "invalid empty size",
+		cache: &api.StepCache{},
+		err: []error{
+			errors.New("tests[0].steps.cache: 'size' cannot be empty"),
+		},
+	}, {
+		name:  "invalid size",
+		cache: &api.StepCache{Size: "lots"},
+		err: []error{
+			errors.New("tests[0].steps.cache: 'size' must be a Kubernetes quantity: quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'"),
+		},
+	}, {
+		name:  "invalid zero size",
+		cache: &api.StepCache{Size: "0"},
+		err: []error{
+			errors.New("tests[0].steps.cache: 'size' must be positive"),
+		},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			test := api.TestStepConfiguration{
+				MultiStageTestConfigurationLiteral: &api.MultiStageTestConfigurationLiteral{Cache: tc.cache},
+			}
+			v := NewValidator(nil)
+			err := v.validateTestConfigurationType("tests[0]", test, nil, nil, nil, make(testInputImages), true)
+			if diff := cmp.Diff(tc.err, err, testhelper.EquateErrorMessage); diff != "" {
+				t.Errorf("unexpected error: %s", diff)
+			}
+		})
+	}
+}
+
 func TestValidateTestConfigurationType(t *testing.T) {
 	for _, tc := range []struct {
 		name     string
@@ -1599,6 +1665,52 @@ func TestValidateTestConfigurationType(t *testing.T) {
 				errors.New("test.cluster_claim.labels contains an invalid key in claim's label: cloud"),
 			},
 		},
+		{
+			name: "claim with a selector",
+			test: api.TestStepConfiguration{
+				ClusterClaim: &api.ClusterClaim{
+					Selector: map[string]string{"hypershift": "hosted", "architecture": "arm64"},
+					Timeout:  &prowv1.Duration{Duration: time.Hour},
+				},
+				MultiStageTestConfiguration: &api.MultiStageTestConfiguration{
+					Test: []api.TestStep{
+						{
+							LiteralTestStep: &api.LiteralTestStep{
+								As:        "e2e-aws-test",
+								Commands:  "oc get node",
+								From:      "cli",
+								Resources: api.ResourceRequirements{Requests: api.ResourceList{"cpu": "1"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "claim with a selector and a version -> error",
+			test: api.TestStepConfiguration{
+				ClusterClaim: &api.ClusterClaim{
+					Version:  "4.6.0",
+					Selector: map[string]string{"hypershift": "hosted"},
+					Timeout:  &prowv1.Duration{Duration: time.Hour},
+				},
+				MultiStageTestConfiguration: &api.MultiStageTestConfiguration{
+					Test: []api.TestStep{
+						{
+							LiteralTestStep: &api.LiteralTestStep{
+								As:        "e2e-aws-test",
+								Commands:  "oc get node",
+								From:      "cli",
+								Resources: api.ResourceRequirements{Requests: api.ResourceList{"cpu": "1"}},
+							},
+						},
+					},
+				},
+			},
+			expected: []error{
+				errors.New("test.cluster_claim.selector cannot be set together with product, version, architecture, cloud, owner or labels"),
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			v := NewValidator(nil)
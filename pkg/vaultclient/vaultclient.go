@@ -350,6 +350,13 @@ func (v *VaultClient) UpdateGroupMembers(groupName string, newMemberIDs []string
 	return err
 }
 
+// UpdateGroupMembersAndMetadata replaces both the members and the metadata of a group
+func (v *VaultClient) UpdateGroupMembersAndMetadata(groupName string, newMemberIDs []string, metadata map[string]string) error {
+	data := map[string]interface{}{"member_entity_ids": newMemberIDs, "metadata": metadata}
+	_, err := v.Logical().Write(fmt.Sprintf("identity/group/name/%s", groupName), data)
+	return err
+}
+
 func (v *VaultClient) DeleteGroupByName(name string) error {
 	_, err := v.Logical().Delete(fmt.Sprintf("identity/group/name/%s", name))
 	return err
@@ -160,12 +160,25 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"canonical_go_repository_list:\n" +
 	"    - ref: ' '\n" +
 	"      repository: ' '\n" +
+	"# ExternalImages are images outside of image streams, given by their\n" +
+	"# pull specs. They are resolved to digests when the job starts, so that\n" +
+	"# all steps use the same image, and are tagged into the pipeline under\n" +
+	"# their key, which steps can use as a dependency.\n" +
+	"external_images:\n" +
+	"    \"\":\n" +
+	"        # PullSpec is the pull spec of the image, which may be a tag.\n" +
+	"        pull_spec: ' '\n" +
 	"# Images describes the images that are built\n" +
 	"# baseImage the project as part of the release\n" +
 	"# process. The name of each image is its \"to\" value\n" +
 	"# and can be used to build only a specific image.\n" +
 	"images:\n" +
-	"    - # BuildArgs contains build arguments that will be resolved in the Dockerfile.\n" +
+	"    - # Architectures are the architectures the image is built for. The image is built\n" +
+	"      # on the nodes of each architecture and pushed as a manifest list. When unset, the\n" +
+	"      # image is built for all the architectures of the nodes of the build cluster.\n" +
+	"      architectures:\n" +
+	"        - \"\"\n" +
+	"      # BuildArgs contains build arguments that will be resolved in the Dockerfile.\n" +
 	"      # See https://docs.docker.com/engine/reference/builder/#/arg for more details.\n" +
 	"      build_args:\n" +
 	"        - # Name of the build arg.\n" +
@@ -422,6 +435,11 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"        # Ref is an optional string linking to the extra_ref in \"org.repo\" format that this belongs to\n" +
 	"        ref: ' '\n" +
 	"      project_directory_image_build_step:\n" +
+	"        # Architectures are the architectures the image is built for. The image is built\n" +
+	"        # on the nodes of each architecture and pushed as a manifest list. When unset, the\n" +
+	"        # image is built for all the architectures of the nodes of the build cluster.\n" +
+	"        architectures:\n" +
+	"            - \"\"\n" +
 	"        # BuildArgs contains build arguments that will be resolved in the Dockerfile.\n" +
 	"        # See https://docs.docker.com/engine/reference/builder/#/arg for more details.\n" +
 	"        build_args:\n" +
@@ -563,6 +581,12 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"            # Product is the name of the product being released.\n" +
 	"            # Defaults to ocp.\n" +
 	"            product: ' '\n" +
+	"            # Selector is the labels to select the cluster pools by alone, for the\n" +
+	"            # pools which are not described by a product, version, architecture,\n" +
+	"            # cloud and owner, e.g. the ones providing hosted clusters. It cannot be\n" +
+	"            # set together with any of those or with labels.\n" +
+	"            selector:\n" +
+	"                \"\": \"\"\n" +
 	"            # Timeout is how long ci-operator will wait for the cluster to be ready.\n" +
 	"            # Defaults to 1h.\n" +
 	"            timeout: 0s\n" +
@@ -602,6 +626,14 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"            # all previous `pre` and `test` steps were successful. The given step must explicitly\n" +
 	"            # ask for being skipped by setting the OptionalOnSuccess flag to true.\n" +
 	"            allow_skip_on_success: false\n" +
+	"            # Cache is a volume shared between the steps which, unlike the shared directory, is not limited in size.\n" +
+	"            # It is mounted at $CACHE_DIR, the observers do not mount it.\n" +
+	"            cache:\n" +
+	"                # Size is the size of the volume, e.g. `10Gi`.\n" +
+	"                size: ' '\n" +
+	"                # StorageClass is the storage class of the volume. Defaults to the\n" +
+	"                # default storage class of the cluster.\n" +
+	"                storage_class: ' '\n" +
 	"            # ClusterProfile defines the profile/cloud provider for end-to-end test steps.\n" +
 	"            cluster_profile: ' '\n" +
 	"            # Dependencies holds override values for dependency parameters.\n" +
@@ -654,6 +686,15 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                  grace_period: 0s\n" +
 	"                  # Name is the name of this observer\n" +
 	"                  name: ' '\n" +
+	"                  # Readiness, when set, holds what the observer observes until the observer\n" +
+	"                  # is ready to do so.\n" +
+	"                  readiness:\n" +
+	"                    # Commands is run in the observer container until it succeeds, the\n" +
+	"                    # observer is ready from then on.\n" +
+	"                    commands: ' '\n" +
+	"                    # Timeout is how long we wait for the observer to become ready before\n" +
+	"                    # going on without it. Defaults to 10 minutes.\n" +
+	"                    timeout: 0s\n" +
 	"                  # Resources defines the resource requirements for the step.\n" +
 	"                  resources:\n" +
 	"                    # Limits are resource limits applied to an individual step in the job.\n" +
@@ -664,6 +705,9 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                    # These are directly used in creating the Pods that execute the Job.\n" +
 	"                    requests:\n" +
 	"                        \"\": \"\"\n" +
+	"                  # Scope is what the observer observes: the whole test by default or, with\n" +
+	"                  # `step`, only the steps which list it in their `observers`.\n" +
+	"                  scope: ' '\n" +
 	"                  # Timeout is how long the we will wait before aborting a job with SIGINT.\n" +
 	"                  timeout: 0s\n" +
 	"            # Post is the array of test steps run after the tests finish and teardown/deprovision resources.\n" +
@@ -734,7 +778,8 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                  # so no local copy of it will be created for the step and if the step\n" +
 	"                  # creates one, it will not be propagated.\n" +
 	"                  no_kubeconfig: false\n" +
-	"                  # Observers are the observers that should be running\n" +
+	"                  # Observers are the observers that should be running. Those with the\n" +
+	"                  # `step` scope only run while this step runs.\n" +
 	"                  observers:\n" +
 	"                    - \"\"\n" +
 	"                  # OptionalOnSuccess defines if this step should be skipped as long\n" +
@@ -752,6 +797,15 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                    # These are directly used in creating the Pods that execute the Job.\n" +
 	"                    requests:\n" +
 	"                        \"\": \"\"\n" +
+	"                  # Retries defines if and how many times this step is run again when\n" +
+	"                  # it fails, without running the whole test again.\n" +
+	"                  retries:\n" +
+	"                    # Count is how many times the step is run again after its first attempt.\n" +
+	"                    count: 0\n" +
+	"                    # On lists the outcomes of an attempt the step is retried on. Defaults\n" +
+	"                    # to both failure and timeout.\n" +
+	"                    \"on\":\n" +
+	"                        - \"\"\n" +
 	"                  # RunAsScript defines if this step should be executed as a script mounted\n" +
 	"                  # in the test container instead of being executed directly via bash\n" +
 	"                  run_as_script: false\n" +
@@ -824,7 +878,8 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                  # so no local copy of it will be created for the step and if the step\n" +
 	"                  # creates one, it will not be propagated.\n" +
 	"                  no_kubeconfig: false\n" +
-	"                  # Observers are the observers that should be running\n" +
+	"                  # Observers are the observers that should be running. Those with the\n" +
+	"                  # `step` scope only run while this step runs.\n" +
 	"                  observers:\n" +
 	"                    - \"\"\n" +
 	"                  # OptionalOnSuccess defines if this step should be skipped as long\n" +
@@ -842,6 +897,15 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                    # These are directly used in creating the Pods that execute the Job.\n" +
 	"                    requests:\n" +
 	"                        \"\": \"\"\n" +
+	"                  # Retries defines if and how many times this step is run again when\n" +
+	"                  # it fails, without running the whole test again.\n" +
+	"                  retries:\n" +
+	"                    # Count is how many times the step is run again after its first attempt.\n" +
+	"                    count: 0\n" +
+	"                    # On lists the outcomes of an attempt the step is retried on. Defaults\n" +
+	"                    # to both failure and timeout.\n" +
+	"                    \"on\":\n" +
+	"                        - \"\"\n" +
 	"                  # RunAsScript defines if this step should be executed as a script mounted\n" +
 	"                  # in the test container instead of being executed directly via bash\n" +
 	"                  run_as_script: false\n" +
@@ -914,7 +978,8 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                  # so no local copy of it will be created for the step and if the step\n" +
 	"                  # creates one, it will not be propagated.\n" +
 	"                  no_kubeconfig: false\n" +
-	"                  # Observers are the observers that should be running\n" +
+	"                  # Observers are the observers that should be running. Those with the\n" +
+	"                  # `step` scope only run while this step runs.\n" +
 	"                  observers:\n" +
 	"                    - \"\"\n" +
 	"                  # OptionalOnSuccess defines if this step should be skipped as long\n" +
@@ -932,6 +997,15 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                    # These are directly used in creating the Pods that execute the Job.\n" +
 	"                    requests:\n" +
 	"                        \"\": \"\"\n" +
+	"                  # Retries defines if and how many times this step is run again when\n" +
+	"                  # it fails, without running the whole test again.\n" +
+	"                  retries:\n" +
+	"                    # Count is how many times the step is run again after its first attempt.\n" +
+	"                    count: 0\n" +
+	"                    # On lists the outcomes of an attempt the step is retried on. Defaults\n" +
+	"                    # to both failure and timeout.\n" +
+	"                    \"on\":\n" +
+	"                        - \"\"\n" +
 	"                  # RunAsScript defines if this step should be executed as a script mounted\n" +
 	"                  # in the test container instead of being executed directly via bash\n" +
 	"                  run_as_script: false\n" +
@@ -1013,6 +1087,14 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"            # all previous `pre` and `test` steps were successful. The given step must explicitly\n" +
 	"            # ask for being skipped by setting the OptionalOnSuccess flag to true.\n" +
 	"            allow_skip_on_success: false\n" +
+	"            # Cache is a volume shared between the steps which, unlike the shared directory, is not limited in size.\n" +
+	"            # It is mounted at $CACHE_DIR, the observers do not mount it.\n" +
+	"            cache:\n" +
+	"                # Size is the size of the volume, e.g. `10Gi`.\n" +
+	"                size: ' '\n" +
+	"                # StorageClass is the storage class of the volume. Defaults to the\n" +
+	"                # default storage class of the cluster.\n" +
+	"                storage_class: ' '\n" +
 	"            # ClusterProfile defines the profile/cloud provider for end-to-end test steps.\n" +
 	"            cluster_profile: ' '\n" +
 	"            # Dependencies holds override values for dependency parameters.\n" +
@@ -1110,6 +1192,12 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                    requests:\n" +
 	"                        # LiteralTestStep is a full test step definition.\n" +
 	"                        \"\": \"\"\n" +
+	"                  retries:\n" +
+	"                    # LiteralTestStep is a full test step definition.\n" +
+	"                    count: 0\n" +
+	"                    \"on\":\n" +
+	"                        # LiteralTestStep is a full test step definition.\n" +
+	"                        - \"\"\n" +
 	"                  run_as_script: false\n" +
 	"                  timeout: 0s\n" +
 	"            # Pre is the array of test steps run to set up the environment for the test.\n" +
@@ -1173,6 +1261,12 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                    requests:\n" +
 	"                        # LiteralTestStep is a full test step definition.\n" +
 	"                        \"\": \"\"\n" +
+	"                  retries:\n" +
+	"                    # LiteralTestStep is a full test step definition.\n" +
+	"                    count: 0\n" +
+	"                    \"on\":\n" +
+	"                        # LiteralTestStep is a full test step definition.\n" +
+	"                        - \"\"\n" +
 	"                  run_as_script: false\n" +
 	"                  timeout: 0s\n" +
 	"            # Test is the array of test steps that define the actual test.\n" +
@@ -1236,6 +1330,12 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                    requests:\n" +
 	"                        # LiteralTestStep is a full test step definition.\n" +
 	"                        \"\": \"\"\n" +
+	"                  retries:\n" +
+	"                    # LiteralTestStep is a full test step definition.\n" +
+	"                    count: 0\n" +
+	"                    \"on\":\n" +
+	"                        # LiteralTestStep is a full test step definition.\n" +
+	"                        - \"\"\n" +
 	"                  run_as_script: false\n" +
 	"                  timeout: 0s\n" +
 	"            # Workflow is the name of the workflow to be used for this configuration. For fields defined in both\n" +
@@ -1376,6 +1476,12 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"        # Product is the name of the product being released.\n" +
 	"        # Defaults to ocp.\n" +
 	"        product: ' '\n" +
+	"        # Selector is the labels to select the cluster pools by alone, for the\n" +
+	"        # pools which are not described by a product, version, architecture,\n" +
+	"        # cloud and owner, e.g. the ones providing hosted clusters. It cannot be\n" +
+	"        # set together with any of those or with labels.\n" +
+	"        selector:\n" +
+	"            \"\": \"\"\n" +
 	"        # Timeout is how long ci-operator will wait for the cluster to be ready.\n" +
 	"        # Defaults to 1h.\n" +
 	"        timeout: 0s\n" +
@@ -1415,6 +1521,14 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"        # all previous `pre` and `test` steps were successful. The given step must explicitly\n" +
 	"        # ask for being skipped by setting the OptionalOnSuccess flag to true.\n" +
 	"        allow_skip_on_success: false\n" +
+	"        # Cache is a volume shared between the steps which, unlike the shared directory, is not limited in size.\n" +
+	"        # It is mounted at $CACHE_DIR, the observers do not mount it.\n" +
+	"        cache:\n" +
+	"            # Size is the size of the volume, e.g. `10Gi`.\n" +
+	"            size: ' '\n" +
+	"            # StorageClass is the storage class of the volume. Defaults to the\n" +
+	"            # default storage class of the cluster.\n" +
+	"            storage_class: ' '\n" +
 	"        # ClusterProfile defines the profile/cloud provider for end-to-end test steps.\n" +
 	"        cluster_profile: ' '\n" +
 	"        # Dependencies holds override values for dependency parameters.\n" +
@@ -1467,6 +1581,15 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"              grace_period: 0s\n" +
 	"              # Name is the name of this observer\n" +
 	"              name: ' '\n" +
+	"              # Readiness, when set, holds what the observer observes until the observer\n" +
+	"              # is ready to do so.\n" +
+	"              readiness:\n" +
+	"                # Commands is run in the observer container until it succeeds, the\n" +
+	"                # observer is ready from then on.\n" +
+	"                commands: ' '\n" +
+	"                # Timeout is how long we wait for the observer to become ready before\n" +
+	"                # going on without it. Defaults to 10 minutes.\n" +
+	"                timeout: 0s\n" +
 	"              # Resources defines the resource requirements for the step.\n" +
 	"              resources:\n" +
 	"                # Limits are resource limits applied to an individual step in the job.\n" +
@@ -1477,6 +1600,9 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                # These are directly used in creating the Pods that execute the Job.\n" +
 	"                requests:\n" +
 	"                    \"\": \"\"\n" +
+	"              # Scope is what the observer observes: the whole test by default or, with\n" +
+	"              # `step`, only the steps which list it in their `observers`.\n" +
+	"              scope: ' '\n" +
 	"              # Timeout is how long the we will wait before aborting a job with SIGINT.\n" +
 	"              timeout: 0s\n" +
 	"        # Post is the array of test steps run after the tests finish and teardown/deprovision resources.\n" +
@@ -1547,7 +1673,8 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"              # so no local copy of it will be created for the step and if the step\n" +
 	"              # creates one, it will not be propagated.\n" +
 	"              no_kubeconfig: false\n" +
-	"              # Observers are the observers that should be running\n" +
+	"              # Observers are the observers that should be running. Those with the\n" +
+	"              # `step` scope only run while this step runs.\n" +
 	"              observers:\n" +
 	"                - \"\"\n" +
 	"              # OptionalOnSuccess defines if this step should be skipped as long\n" +
@@ -1565,6 +1692,15 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                # These are directly used in creating the Pods that execute the Job.\n" +
 	"                requests:\n" +
 	"                    \"\": \"\"\n" +
+	"              # Retries defines if and how many times this step is run again when\n" +
+	"              # it fails, without running the whole test again.\n" +
+	"              retries:\n" +
+	"                # Count is how many times the step is run again after its first attempt.\n" +
+	"                count: 0\n" +
+	"                # On lists the outcomes of an attempt the step is retried on. Defaults\n" +
+	"                # to both failure and timeout.\n" +
+	"                \"on\":\n" +
+	"                    - \"\"\n" +
 	"              # RunAsScript defines if this step should be executed as a script mounted\n" +
 	"              # in the test container instead of being executed directly via bash\n" +
 	"              run_as_script: false\n" +
@@ -1637,7 +1773,8 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"              # so no local copy of it will be created for the step and if the step\n" +
 	"              # creates one, it will not be propagated.\n" +
 	"              no_kubeconfig: false\n" +
-	"              # Observers are the observers that should be running\n" +
+	"              # Observers are the observers that should be running. Those with the\n" +
+	"              # `step` scope only run while this step runs.\n" +
 	"              observers:\n" +
 	"                - \"\"\n" +
 	"              # OptionalOnSuccess defines if this step should be skipped as long\n" +
@@ -1655,6 +1792,15 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                # These are directly used in creating the Pods that execute the Job.\n" +
 	"                requests:\n" +
 	"                    \"\": \"\"\n" +
+	"              # Retries defines if and how many times this step is run again when\n" +
+	"              # it fails, without running the whole test again.\n" +
+	"              retries:\n" +
+	"                # Count is how many times the step is run again after its first attempt.\n" +
+	"                count: 0\n" +
+	"                # On lists the outcomes of an attempt the step is retried on. Defaults\n" +
+	"                # to both failure and timeout.\n" +
+	"                \"on\":\n" +
+	"                    - \"\"\n" +
 	"              # RunAsScript defines if this step should be executed as a script mounted\n" +
 	"              # in the test container instead of being executed directly via bash\n" +
 	"              run_as_script: false\n" +
@@ -1727,7 +1873,8 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"              # so no local copy of it will be created for the step and if the step\n" +
 	"              # creates one, it will not be propagated.\n" +
 	"              no_kubeconfig: false\n" +
-	"              # Observers are the observers that should be running\n" +
+	"              # Observers are the observers that should be running. Those with the\n" +
+	"              # `step` scope only run while this step runs.\n" +
 	"              observers:\n" +
 	"                - \"\"\n" +
 	"              # OptionalOnSuccess defines if this step should be skipped as long\n" +
@@ -1745,6 +1892,15 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                # These are directly used in creating the Pods that execute the Job.\n" +
 	"                requests:\n" +
 	"                    \"\": \"\"\n" +
+	"              # Retries defines if and how many times this step is run again when\n" +
+	"              # it fails, without running the whole test again.\n" +
+	"              retries:\n" +
+	"                # Count is how many times the step is run again after its first attempt.\n" +
+	"                count: 0\n" +
+	"                # On lists the outcomes of an attempt the step is retried on. Defaults\n" +
+	"                # to both failure and timeout.\n" +
+	"                \"on\":\n" +
+	"                    - \"\"\n" +
 	"              # RunAsScript defines if this step should be executed as a script mounted\n" +
 	"              # in the test container instead of being executed directly via bash\n" +
 	"              run_as_script: false\n" +
@@ -1826,6 +1982,14 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"        # all previous `pre` and `test` steps were successful. The given step must explicitly\n" +
 	"        # ask for being skipped by setting the OptionalOnSuccess flag to true.\n" +
 	"        allow_skip_on_success: false\n" +
+	"        # Cache is a volume shared between the steps which, unlike the shared directory, is not limited in size.\n" +
+	"        # It is mounted at $CACHE_DIR, the observers do not mount it.\n" +
+	"        cache:\n" +
+	"            # Size is the size of the volume, e.g. `10Gi`.\n" +
+	"            size: ' '\n" +
+	"            # StorageClass is the storage class of the volume. Defaults to the\n" +
+	"            # default storage class of the cluster.\n" +
+	"            storage_class: ' '\n" +
 	"        # ClusterProfile defines the profile/cloud provider for end-to-end test steps.\n" +
 	"        cluster_profile: ' '\n" +
 	"        # Dependencies holds override values for dependency parameters.\n" +
@@ -1923,6 +2087,12 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                requests:\n" +
 	"                    # LiteralTestStep is a full test step definition.\n" +
 	"                    \"\": \"\"\n" +
+	"              retries:\n" +
+	"                # LiteralTestStep is a full test step definition.\n" +
+	"                count: 0\n" +
+	"                \"on\":\n" +
+	"                    # LiteralTestStep is a full test step definition.\n" +
+	"                    - \"\"\n" +
 	"              run_as_script: false\n" +
 	"              timeout: 0s\n" +
 	"        # Pre is the array of test steps run to set up the environment for the test.\n" +
@@ -1986,6 +2156,12 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                requests:\n" +
 	"                    # LiteralTestStep is a full test step definition.\n" +
 	"                    \"\": \"\"\n" +
+	"              retries:\n" +
+	"                # LiteralTestStep is a full test step definition.\n" +
+	"                count: 0\n" +
+	"                \"on\":\n" +
+	"                    # LiteralTestStep is a full test step definition.\n" +
+	"                    - \"\"\n" +
 	"              run_as_script: false\n" +
 	"              timeout: 0s\n" +
 	"        # Test is the array of test steps that define the actual test.\n" +
@@ -2049,6 +2225,12 @@ const ciOperatorReferenceYaml = "# The list of base images describe\n" +
 	"                requests:\n" +
 	"                    # LiteralTestStep is a full test step definition.\n" +
 	"                    \"\": \"\"\n" +
+	"              retries:\n" +
+	"                # LiteralTestStep is a full test step definition.\n" +
+	"                count: 0\n" +
+	"                \"on\":\n" +
+	"                    # LiteralTestStep is a full test step definition.\n" +
+	"                    - \"\"\n" +
 	"              run_as_script: false\n" +
 	"              timeout: 0s\n" +
 	"        # Workflow is the name of the workflow to be used for this configuration. For fields defined in both\n" +